@@ -8,6 +8,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/segmentstream"
 )
 
 // API provides all public-facing functionality of the Flow Access API.
@@ -50,6 +51,28 @@ type API interface {
 	GetExecutionResultByID(ctx context.Context, id flow.Identifier) (*flow.ExecutionResult, error)
 }
 
+// RegisterProofAPI provides proofs of the execution state at sealed blocks, which clients can verify
+// against the state commitment of the block's seal. It is implemented by nodes which maintain a
+// proof-capable index of the execution state.
+type RegisterProofAPI interface {
+	GetRegisterWithProof(ctx context.Context, registerIDs []flow.RegisterID, blockID flow.Identifier) (*RegisterProof, error)
+	GetAccountWithProof(ctx context.Context, address flow.Address, blockID flow.Identifier) (*AccountProof, error)
+}
+
+// SealingSegmentAPI streams the sealing segments of finalized blocks in chunks, allowing other nodes
+// to bootstrap from the protocol snapshot at a block without obtaining a snapshot file out of band.
+type SealingSegmentAPI interface {
+	GetSealingSegmentManifest(ctx context.Context, blockID flow.Identifier) (*segmentstream.Manifest, error)
+	GetSealingSegmentChunk(ctx context.Context, headID flow.Identifier, index uint) (*segmentstream.Chunk, error)
+}
+
+// ProtocolHistoryAPI provides the identity table and epoch of the protocol state at any finalized height
+// since the root block. It is implemented by nodes which archive the epochs of the protocol state.
+type ProtocolHistoryAPI interface {
+	GetIdentitiesAtHeight(ctx context.Context, height uint64) (flow.IdentityList, error)
+	GetEpochAtHeight(ctx context.Context, height uint64) (*Epoch, error)
+}
+
 // TODO: Combine this with flow.TransactionResult?
 type TransactionResult struct {
 	Status        flow.TransactionStatus
@@ -112,3 +135,12 @@ type NodeVersionInfo struct {
 	SporkId         flow.Identifier
 	ProtocolVersion uint64
 }
+
+// Epoch describes the epoch of the protocol state at a finalized height, as specified by its service events.
+type Epoch struct {
+	Phase       flow.EpochPhase // phase of the epoch at the height
+	FirstHeight *uint64         // nil if the first block of the epoch was finalized before the root block
+	FinalHeight *uint64         // nil if the epoch has not ended yet
+	Setup       *flow.EpochSetup
+	Commit      *flow.EpochCommit
+}
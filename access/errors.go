@@ -10,6 +10,9 @@ import (
 // ErrUnknownReferenceBlock indicates that a transaction references an unknown block.
 var ErrUnknownReferenceBlock = errors.New("unknown reference block")
 
+// ErrAccountNotFound indicates that an account does not exist at the queried execution state.
+var ErrAccountNotFound = errors.New("account not found")
+
 // IncompleteTransactionError indicates that a transaction is missing one or more required fields.
 type IncompleteTransactionError struct {
 	MissingFields []string
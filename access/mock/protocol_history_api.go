@@ -0,0 +1,85 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+
+	access "github.com/onflow/flow-go/access"
+
+	flow "github.com/onflow/flow-go/model/flow"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProtocolHistoryAPI is an autogenerated mock type for the ProtocolHistoryAPI type
+type ProtocolHistoryAPI struct {
+	mock.Mock
+}
+
+// GetEpochAtHeight provides a mock function with given fields: ctx, height
+func (_m *ProtocolHistoryAPI) GetEpochAtHeight(ctx context.Context, height uint64) (*access.Epoch, error) {
+	ret := _m.Called(ctx, height)
+
+	var r0 *access.Epoch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*access.Epoch, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *access.Epoch); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*access.Epoch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIdentitiesAtHeight provides a mock function with given fields: ctx, height
+func (_m *ProtocolHistoryAPI) GetIdentitiesAtHeight(ctx context.Context, height uint64) (flow.IdentityList, error) {
+	ret := _m.Called(ctx, height)
+
+	var r0 flow.IdentityList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (flow.IdentityList, error)); ok {
+		return rf(ctx, height)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) flow.IdentityList); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(flow.IdentityList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewProtocolHistoryAPI interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewProtocolHistoryAPI creates a new instance of ProtocolHistoryAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewProtocolHistoryAPI(t mockConstructorTestingTNewProtocolHistoryAPI) *ProtocolHistoryAPI {
+	mock := &ProtocolHistoryAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+
+	access "github.com/onflow/flow-go/access"
+
+	flow "github.com/onflow/flow-go/model/flow"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RegisterProofAPI is an autogenerated mock type for the RegisterProofAPI type
+type RegisterProofAPI struct {
+	mock.Mock
+}
+
+// GetAccountWithProof provides a mock function with given fields: ctx, address, blockID
+func (_m *RegisterProofAPI) GetAccountWithProof(ctx context.Context, address flow.Address, blockID flow.Identifier) (*access.AccountProof, error) {
+	ret := _m.Called(ctx, address, blockID)
+
+	var r0 *access.AccountProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, flow.Identifier) (*access.AccountProof, error)); ok {
+		return rf(ctx, address, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, flow.Identifier) *access.AccountProof); ok {
+		r0 = rf(ctx, address, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*access.AccountProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, flow.Identifier) error); ok {
+		r1 = rf(ctx, address, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRegisterWithProof provides a mock function with given fields: ctx, registerIDs, blockID
+func (_m *RegisterProofAPI) GetRegisterWithProof(ctx context.Context, registerIDs []flow.RegisterID, blockID flow.Identifier) (*access.RegisterProof, error) {
+	ret := _m.Called(ctx, registerIDs, blockID)
+
+	var r0 *access.RegisterProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []flow.RegisterID, flow.Identifier) (*access.RegisterProof, error)); ok {
+		return rf(ctx, registerIDs, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []flow.RegisterID, flow.Identifier) *access.RegisterProof); ok {
+		r0 = rf(ctx, registerIDs, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*access.RegisterProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []flow.RegisterID, flow.Identifier) error); ok {
+		r1 = rf(ctx, registerIDs, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRegisterProofAPI interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRegisterProofAPI creates a new instance of RegisterProofAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRegisterProofAPI(t mockConstructorTestingTNewRegisterProofAPI) *RegisterProofAPI {
+	mock := &RegisterProofAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
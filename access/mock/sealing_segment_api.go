@@ -0,0 +1,85 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+
+	flow "github.com/onflow/flow-go/model/flow"
+
+	mock "github.com/stretchr/testify/mock"
+
+	segmentstream "github.com/onflow/flow-go/module/segmentstream"
+)
+
+// SealingSegmentAPI is an autogenerated mock type for the SealingSegmentAPI type
+type SealingSegmentAPI struct {
+	mock.Mock
+}
+
+// GetSealingSegmentChunk provides a mock function with given fields: ctx, headID, index
+func (_m *SealingSegmentAPI) GetSealingSegmentChunk(ctx context.Context, headID flow.Identifier, index uint) (*segmentstream.Chunk, error) {
+	ret := _m.Called(ctx, headID, index)
+
+	var r0 *segmentstream.Chunk
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, uint) (*segmentstream.Chunk, error)); ok {
+		return rf(ctx, headID, index)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, uint) *segmentstream.Chunk); ok {
+		r0 = rf(ctx, headID, index)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*segmentstream.Chunk)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier, uint) error); ok {
+		r1 = rf(ctx, headID, index)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSealingSegmentManifest provides a mock function with given fields: ctx, blockID
+func (_m *SealingSegmentAPI) GetSealingSegmentManifest(ctx context.Context, blockID flow.Identifier) (*segmentstream.Manifest, error) {
+	ret := _m.Called(ctx, blockID)
+
+	var r0 *segmentstream.Manifest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) (*segmentstream.Manifest, error)); ok {
+		return rf(ctx, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) *segmentstream.Manifest); ok {
+		r0 = rf(ctx, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*segmentstream.Manifest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier) error); ok {
+		r1 = rf(ctx, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSealingSegmentAPI interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSealingSegmentAPI creates a new instance of SealingSegmentAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSealingSegmentAPI(t mockConstructorTestingTNewSealingSegmentAPI) *SealingSegmentAPI {
+	mock := &SealingSegmentAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
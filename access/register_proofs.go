@@ -0,0 +1,241 @@
+package access
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/common/proof"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// RegisterProofPathFinderVersion is the version of the path finder the execution state derives the
+// path of a register in the trie from its key with.
+const RegisterProofPathFinderVersion = uint8(1)
+
+// RegisterProof is a batch proof of the values of registers at the sealed execution state of a block.
+type RegisterProof struct {
+	BlockID         flow.Identifier
+	StateCommitment flow.StateCommitment
+	RegisterIDs     []flow.RegisterID
+	Proof           *ledger.TrieBatchProof
+}
+
+// AccountProof is a batch proof of the registers defining an account at the sealed execution state of
+// a block: the account status, the public keys, the contract names and the code of all contracts.
+type AccountProof struct {
+	BlockID         flow.Identifier
+	StateCommitment flow.StateCommitment
+	Address         flow.Address
+	Proof           *ledger.TrieBatchProof
+}
+
+// InvalidProofError indicates that a register proof does not prove the values of the requested
+// registers against the expected state commitment.
+type InvalidProofError struct {
+	err error
+}
+
+func NewInvalidProofErrorf(msg string, args ...interface{}) error {
+	return InvalidProofError{
+		err: fmt.Errorf(msg, args...),
+	}
+}
+
+func (e InvalidProofError) Unwrap() error {
+	return e.err
+}
+
+func (e InvalidProofError) Error() string {
+	return e.err.Error()
+}
+
+// IsInvalidProofError returns whether the given error is an InvalidProofError error
+func IsInvalidProofError(err error) bool {
+	var errInvalidProofError InvalidProofError
+	return errors.As(err, &errInvalidProofError)
+}
+
+// Verify verifies the proof against the given state commitment, which the caller must trust, for
+// instance because it is the sealed state commitment of a block verified by a light client.
+// Values are returned in the order of the proof's register IDs, where nil indicates an unset register.
+// Expected errors during normal operation:
+//   - InvalidProofError if the proof is not valid for the given state commitment
+func (p *RegisterProof) Verify(commit flow.StateCommitment) ([]flow.RegisterValue, error) {
+	if p.StateCommitment != commit {
+		return nil, NewInvalidProofErrorf("proof is for state %x, expected state %x", p.StateCommitment, commit)
+	}
+	return VerifyRegisterProof(commit, p.RegisterIDs, p.Proof)
+}
+
+// Verify verifies the proof against the given state commitment, which the caller must trust, and
+// returns the account defined by the proven registers. The account balance is held by a resource in
+// the account's storage, which is not covered by the proof, and is therefore not set.
+// Expected errors during normal operation:
+//   - InvalidProofError if the proof is not valid for the given state commitment
+func (p *AccountProof) Verify(commit flow.StateCommitment) (*flow.Account, error) {
+	if p.StateCommitment != commit {
+		return nil, NewInvalidProofErrorf("proof is for state %x, expected state %x", p.StateCommitment, commit)
+	}
+	return VerifyAccountProof(commit, p.Address, p.Proof)
+}
+
+// VerifyRegisterProof verifies the values of the given registers against the given state commitment
+// using the batch proof. Values are returned in the order of the register IDs, where nil indicates
+// an unset register. The proofs within the batch may be in any order.
+// Expected errors during normal operation:
+//   - InvalidProofError if the proof does not prove the values of all registers against the state commitment
+func VerifyRegisterProof(commit flow.StateCommitment, registerIDs []flow.RegisterID, batchProof *ledger.TrieBatchProof) ([]flow.RegisterValue, error) {
+	if batchProof == nil {
+		return nil, NewInvalidProofErrorf("missing proof")
+	}
+
+	proofs := make(map[ledger.Path]*ledger.TrieProof, len(batchProof.Proofs))
+	for _, p := range batchProof.Proofs {
+		proofs[p.Path] = p
+	}
+
+	values := make([]flow.RegisterValue, 0, len(registerIDs))
+	for _, registerID := range registerIDs {
+		value, err := verifyRegister(ledger.State(commit), registerID, proofs)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// verifyRegister verifies the value of a single register using the proof for the register's path.
+// Expected errors during normal operation:
+//   - InvalidProofError if the proof is missing or invalid
+func verifyRegister(state ledger.State, registerID flow.RegisterID, proofs map[ledger.Path]*ledger.TrieProof) (flow.RegisterValue, error) {
+	key := convert.RegisterIDToLedgerKey(registerID)
+	path, err := pathfinder.KeyToPath(key, RegisterProofPathFinderVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive path of register %s: %w", registerID, err)
+	}
+
+	p, ok := proofs[path]
+	if !ok {
+		return nil, NewInvalidProofErrorf("missing proof for register %s", registerID)
+	}
+	if !proof.VerifyTrieProof(p, state) {
+		return nil, NewInvalidProofErrorf("invalid proof for register %s against state %x", registerID, state)
+	}
+
+	if p.Payload.IsEmpty() {
+		return nil, nil
+	}
+	// the proof authenticates the value at the path, which is derived from the key,
+	// so a payload with a different key is malformed
+	payloadKey, err := p.Payload.Key()
+	if err != nil {
+		return nil, NewInvalidProofErrorf("could not decode payload key for register %s: %w", registerID, err)
+	}
+	if !payloadKey.Equals(&key) {
+		return nil, NewInvalidProofErrorf("payload key %s does not match register %s", payloadKey.String(), registerID)
+	}
+	return flow.RegisterValue(p.Payload.Value()), nil
+}
+
+// AccountHeaderRegisterIDs returns the IDs of the registers which determine the remaining registers
+// defining an account: the account status, holding the number of public keys, and the contract names.
+func AccountHeaderRegisterIDs(address flow.Address) []flow.RegisterID {
+	return []flow.RegisterID{
+		flow.AccountStatusRegisterID(address),
+		flow.ContractNamesRegisterID(address),
+	}
+}
+
+// AccountRegisterIDs returns the IDs of all registers defining an account, given the values of its
+// header registers in the order of AccountHeaderRegisterIDs.
+// Expected errors during normal operation:
+//   - ErrAccountNotFound if the account status register is not set
+//   - decoding errors if the values of the header registers are malformed
+func AccountRegisterIDs(address flow.Address, headerValues []flow.RegisterValue) ([]flow.RegisterID, error) {
+	if len(headerValues) != 2 {
+		return nil, fmt.Errorf("expected 2 account header registers, got %d", len(headerValues))
+	}
+	if len(headerValues[0]) == 0 {
+		return nil, ErrAccountNotFound
+	}
+	status, err := environment.AccountStatusFromBytes(headerValues[0])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode account status: %w", err)
+	}
+	contractNames, err := decodeContractNames(headerValues[1])
+	if err != nil {
+		return nil, err
+	}
+
+	registerIDs := AccountHeaderRegisterIDs(address)
+	for i := uint64(0); i < status.PublicKeyCount(); i++ {
+		registerIDs = append(registerIDs, flow.PublicKeyRegisterID(address, i))
+	}
+	for _, name := range contractNames {
+		registerIDs = append(registerIDs, flow.ContractRegisterID(address, name))
+	}
+	return registerIDs, nil
+}
+
+// VerifyAccountProof verifies the registers defining the account at the given address against the
+// given state commitment using the batch proof, and returns the account. The account balance is
+// held by a resource in the account's storage, which is not covered by the proof, and is therefore
+// not set.
+// Expected errors during normal operation:
+//   - InvalidProofError if the proof does not prove all registers of the account against the state
+//     commitment, or the proven registers do not define a valid account
+func VerifyAccountProof(commit flow.StateCommitment, address flow.Address, batchProof *ledger.TrieBatchProof) (*flow.Account, error) {
+	headerValues, err := VerifyRegisterProof(commit, AccountHeaderRegisterIDs(address), batchProof)
+	if err != nil {
+		return nil, err
+	}
+	registerIDs, err := AccountRegisterIDs(address, headerValues)
+	if err != nil {
+		return nil, NewInvalidProofErrorf("proven registers do not define account %s: %w", address, err)
+	}
+	values, err := VerifyRegisterProof(commit, registerIDs, batchProof)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &flow.Account{
+		Address:   address,
+		Contracts: make(map[string][]byte),
+	}
+	for i, registerID := range registerIDs[2:] {
+		value := values[i+2]
+		if strings.HasPrefix(registerID.Key, flow.CodeKeyPrefix) {
+			account.Contracts[strings.TrimPrefix(registerID.Key, flow.CodeKeyPrefix)] = value
+		} else {
+			index := uint64(len(account.Keys))
+			key, err := flow.DecodeAccountPublicKey(value, index)
+			if err != nil {
+				return nil, NewInvalidProofErrorf("could not decode public key %d of account %s: %w", index, address, err)
+			}
+			account.Keys = append(account.Keys, key)
+		}
+	}
+	return account, nil
+}
+
+// decodeContractNames decodes the value of the contract names register of an account.
+func decodeContractNames(value flow.RegisterValue) ([]string, error) {
+	names := make([]string, 0)
+	if len(value) == 0 {
+		return names, nil
+	}
+	err := cbor.NewDecoder(bytes.NewReader(value)).Decode(&names)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode contract names %x: %w", value, err)
+	}
+	return names, nil
+}
@@ -61,6 +61,14 @@ func WithMaxMsgSize(size int) CommandRunnerOption {
 	}
 }
 
+// WithDebugHandler registers the given http handler with the http server of the admin, under the /debug/ path with the
+// given name, e.g., /debug/peer-score-history for name peer-score-history.
+func WithDebugHandler(name string, handler http.Handler) CommandRunnerOption {
+	return func(r *CommandRunner) {
+		r.debugHandlers[name] = handler
+	}
+}
+
 type CommandRunnerBootstrapper struct {
 	handlers   map[string]CommandHandler
 	validators map[string]CommandValidator
@@ -102,6 +110,7 @@ func (r *CommandRunnerBootstrapper) Bootstrap(logger zerolog.Logger, bindAddress
 		httpAddress:      bindAddress,
 		logger:           logger.With().Str("admin", "command_runner").Logger(),
 		startupCompleted: make(chan struct{}),
+		debugHandlers:    make(map[string]http.Handler),
 	}
 
 	for _, opt := range opts {
@@ -135,6 +144,8 @@ type CommandRunner struct {
 	maxMsgSize  int
 	tlsConfig   *tls.Config
 	logger      zerolog.Logger
+	// debugHandlers are the http handlers served under the /debug/ path, keyed by their name.
+	debugHandlers map[string]http.Handler
 
 	// wait for worker routines to be ready
 	workersStarted sync.WaitGroup
@@ -241,6 +252,10 @@ func (r *CommandRunner) runAdminServer(ctx irrecoverable.SignalerContext) error
 	}
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
+	for name, handler := range r.debugHandlers {
+		mux.Handle(fmt.Sprintf("/debug/%s", name), handler)
+	}
+
 	httpServer := &http.Server{
 		Addr:      r.httpAddress,
 		Handler:   mux,
@@ -0,0 +1,124 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/alsp"
+)
+
+var _ commands.AdminCommand = (*AlspSpamRecordsCommand)(nil)
+
+type alspSpamRecordsAction string
+
+const (
+	alspListSpamRecords alspSpamRecordsAction = "list"
+	alspGetSpamRecord   alspSpamRecordsAction = "get"
+	alspSetPenalty      alspSpamRecordsAction = "set-penalty"
+	alspClearSpamRecord alspSpamRecordsAction = "clear"
+)
+
+type alspSpamRecordsRequestData struct {
+	action  alspSpamRecordsAction
+	flowID  flow.Identifier
+	penalty float64
+}
+
+// AlspSpamRecordsCommand lists, inspects and manually adjusts or clears the penalties that the application layer
+// spam prevention (ALSP) protocol applied to nodes.
+//
+// Input:
+//
+//	{"action": "list"}
+//	{"action": "get", "flow_id": "<64-char hex node ID>"}
+//	{"action": "set-penalty", "flow_id": "<64-char hex node ID>", "penalty": -1000}
+//	{"action": "clear", "flow_id": "<64-char hex node ID>"}
+type AlspSpamRecordsCommand struct {
+	records alsp.SpamRecordAdmin
+}
+
+// NewAlspSpamRecordsCommand creates a command to administer the given ALSP spam records. The spam records may be nil,
+// if the ALSP module of the node does not expose them, in which case the command returns an error for every request.
+func NewAlspSpamRecordsCommand(records alsp.SpamRecordAdmin) commands.AdminCommand {
+	return &AlspSpamRecordsCommand{
+		records: records,
+	}
+}
+
+func (c *AlspSpamRecordsCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if c.records == nil {
+		return nil, fmt.Errorf("alsp spam records are not available on this node")
+	}
+	data := req.ValidatorData.(*alspSpamRecordsRequestData)
+
+	switch data.action {
+	case alspListSpamRecords:
+		return commands.ConvertToInterfaceList(c.records.SpamRecords())
+	case alspGetSpamRecord:
+		record, ok := c.records.SpamRecord(data.flowID)
+		if !ok {
+			return nil, fmt.Errorf("no spam record found for flow ID: %s", data.flowID)
+		}
+		return commands.ConvertToMap(record)
+	case alspSetPenalty:
+		record, err := c.records.SetPenalty(data.flowID, data.penalty)
+		if err != nil {
+			return nil, fmt.Errorf("could not set penalty: %w", err)
+		}
+		return commands.ConvertToMap(record)
+	default: // alspClearSpamRecord
+		if !c.records.ClearSpamRecord(data.flowID) {
+			return nil, fmt.Errorf("no spam record found for flow ID: %s", data.flowID)
+		}
+		return "ok", nil
+	}
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (c *AlspSpamRecordsCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	data := &alspSpamRecordsRequestData{}
+	action, ok := input["action"].(string)
+	if !ok {
+		return admin.NewInvalidAdminReqParameterError("action", "must be one of list, get, set-penalty, clear", input["action"])
+	}
+	data.action = alspSpamRecordsAction(action)
+
+	switch data.action {
+	case alspListSpamRecords:
+		req.ValidatorData = data
+		return nil
+	case alspGetSpamRecord, alspSetPenalty, alspClearSpamRecord:
+	default:
+		return admin.NewInvalidAdminReqParameterError("action", "must be one of list, get, set-penalty, clear", action)
+	}
+
+	flowID, ok := input["flow_id"].(string)
+	if !ok {
+		return admin.NewInvalidAdminReqParameterError("flow_id", "must be 64-char hex string", input["flow_id"])
+	}
+	id, err := flow.HexStringToIdentifier(flowID)
+	if err != nil {
+		return admin.NewInvalidAdminReqParameterError("flow_id", "must be 64-char hex string", flowID)
+	}
+	data.flowID = id
+
+	if data.action == alspSetPenalty {
+		penalty, ok := input["penalty"].(float64)
+		if !ok || penalty > 0 {
+			return admin.NewInvalidAdminReqParameterError("penalty", "must be a non-positive number", input["penalty"])
+		}
+		data.penalty = penalty
+	}
+
+	req.ValidatorData = data
+	return nil
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+var _ commands.AdminCommand = (*PeerScoreHistoryCommand)(nil)
+
+type peerScoreHistoryRequestData struct {
+	peerID peer.ID // peer to get the history of, empty for all peers
+}
+
+// PeerScoreHistoryCommand returns the score history of peers recorded by the gossipsub score tracer, in which each
+// score snapshot is broken down into its topic components, application specific penalties and ALSP penalty.
+// The history is returned in the response only; it is never written to the file system of the node.
+//
+// Input:
+//
+//	{}
+//	{"peer_id": "<peer ID>"}
+type PeerScoreHistoryCommand struct {
+	historian p2p.PeerScoreHistorian
+}
+
+// NewPeerScoreHistoryCommand creates a command to inspect the given peer score history. The historian may be nil, if
+// the node does not keep a peer score history, in which case the command returns an error for every request.
+func NewPeerScoreHistoryCommand(historian p2p.PeerScoreHistorian) commands.AdminCommand {
+	return &PeerScoreHistoryCommand{
+		historian: historian,
+	}
+}
+
+func (c *PeerScoreHistoryCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if c.historian == nil {
+		return nil, fmt.Errorf("peer score history is not available on this node")
+	}
+	data := req.ValidatorData.(*peerScoreHistoryRequestData)
+
+	histories, err := peerScoreHistories(c.historian, data.peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return commands.ConvertToMap(histories)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (c *PeerScoreHistoryCommand) Validator(req *admin.CommandRequest) error {
+	data := &peerScoreHistoryRequestData{}
+	req.ValidatorData = data
+
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	if peerID, ok := input["peer_id"]; ok {
+		str, ok := peerID.(string)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("peer_id", "must be valid peer id string", peerID)
+		}
+		pid, err := peer.Decode(str)
+		if err != nil {
+			return admin.NewInvalidAdminReqParameterError("peer_id", "must be valid peer id string", peerID)
+		}
+		data.peerID = pid
+	}
+
+	return nil
+}
+
+// NewPeerScoreHistoryHandler returns an http handler serving the score histories of peers as JSON, optionally
+// restricted to a single peer by the peer_id query parameter, e.g., /debug/peer-score-history?peer_id=<peer ID>.
+// The historian may be nil, if the node does not keep a peer score history.
+func NewPeerScoreHistoryHandler(historian p2p.PeerScoreHistorian) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if historian == nil {
+			http.Error(w, "peer score history is not available on this node", http.StatusNotFound)
+			return
+		}
+
+		var pid peer.ID
+		if str := r.URL.Query().Get("peer_id"); str != "" {
+			var err error
+			pid, err = peer.Decode(str)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid peer id: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+
+		histories, err := peerScoreHistories(historian, pid)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(histories)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("could not encode peer score history: %v", err), http.StatusInternalServerError)
+		}
+	})
+}
+
+// peerScoreHistories returns the score histories of all peers, keyed by peer id, or only of the given peer if it is
+// not empty.
+// Returns an error if the given peer has no score history.
+func peerScoreHistories(historian p2p.PeerScoreHistorian, pid peer.ID) (map[string][]p2p.PeerScoreHistoryEntry, error) {
+	histories := make(map[string][]p2p.PeerScoreHistoryEntry)
+
+	if pid != "" {
+		history, ok := historian.PeerScoreHistory(pid)
+		if !ok {
+			return nil, fmt.Errorf("no score history found for peer ID: %s", pid)
+		}
+		histories[pid.String()] = history
+		return histories, nil
+	}
+
+	for peerID, history := range historian.PeerScoreHistories() {
+		histories[peerID.String()] = history
+	}
+	return histories, nil
+}
@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+var _ commands.AdminCommand = (*ReadDBPrefixStatsCommand)(nil)
+var _ commands.AdminCommand = (*CompactDBCommand)(nil)
+
+// ReadDBPrefixStatsCommand returns the number of keys and the size in bytes of the keys and values stored
+// under each key prefix of the protocol database.
+//
+// Input:
+//
+//	{}
+type ReadDBPrefixStatsCommand struct {
+	db *badger.DB
+}
+
+func NewReadDBPrefixStatsCommand(db *badger.DB) *ReadDBPrefixStatsCommand {
+	return &ReadDBPrefixStatsCommand{
+		db: db,
+	}
+}
+
+func (c *ReadDBPrefixStatsCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	var stats []operation.PrefixStats
+	err := c.db.View(operation.ComputePrefixStats(&stats))
+	if err != nil {
+		return nil, fmt.Errorf("could not compute prefix stats: %w", err)
+	}
+
+	var total uint64
+	prefixes := make([]interface{}, 0, len(stats))
+	for _, s := range stats {
+		prefixes = append(prefixes, map[string]interface{}{
+			"code":        s.Code,
+			"name":        s.Name,
+			"keys":        s.Keys,
+			"key_bytes":   s.KeyBytes,
+			"value_bytes": s.ValueBytes,
+			"total_bytes": s.TotalBytes(),
+		})
+		total += s.TotalBytes()
+	}
+
+	return map[string]interface{}{
+		"prefixes":    prefixes,
+		"total_bytes": total,
+	}, nil
+}
+
+func (c *ReadDBPrefixStatsCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
+
+// CompactDBCommand controls online compactions of the protocol database, which run in the background.
+// A compaction flattens the LSM tree into a single level and then repeatedly runs the value log
+// garbage collection, pausing between value log rewrites to throttle the IO load.
+//
+// Badger can neither pause nor interrupt the flattening of the LSM tree, and stops its background
+// compactions while it runs. Its IO load is therefore only throttled by the number of flatten workers,
+// which defaults to one, and a cancellation takes effect once the flattening completed. Set "flatten"
+// to false to only run the value log garbage collection.
+//
+// The "start" action, which is the default, starts a compaction and returns immediately. Parameters
+// which are not provided default to badgerstorage.DefaultCompactionConfig. The "status" action returns
+// the progress of the latest compaction and the "cancel" action stops the running compaction.
+//
+// Input:
+//
+//	{}
+//	{"action": "start", "flatten": true, "flatten_workers": 1, "discard_ratio": 0.5, "gc_pause": "5s", "max_gc_rounds": 100}
+//	{"action": "status"}
+//	{"action": "cancel"}
+type CompactDBCommand struct {
+	compactor *badgerstorage.Compactor
+}
+
+// compactDBRequest is the validated input of a CompactDBCommand.
+type compactDBRequest struct {
+	action string
+	config badgerstorage.CompactionConfig
+}
+
+const (
+	compactDBActionStart  = "start"
+	compactDBActionStatus = "status"
+	compactDBActionCancel = "cancel"
+)
+
+func NewCompactDBCommand(compactor *badgerstorage.Compactor) *CompactDBCommand {
+	return &CompactDBCommand{
+		compactor: compactor,
+	}
+}
+
+func (c *CompactDBCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(compactDBRequest)
+
+	switch data.action {
+	case compactDBActionStart:
+		err := c.compactor.Submit(data.config)
+		if err != nil {
+			return nil, fmt.Errorf("could not start compaction: %w", err)
+		}
+	case compactDBActionCancel:
+		err := c.compactor.Cancel()
+		if err != nil {
+			return nil, fmt.Errorf("could not cancel compaction: %w", err)
+		}
+	}
+
+	return compactionProgressResponse(c.compactor.Progress()), nil
+}
+
+// compactionProgressResponse converts the progress of a compaction into the response of the admin command.
+func compactionProgressResponse(progress badgerstorage.CompactionProgress) map[string]interface{} {
+	response := map[string]interface{}{
+		"phase": string(progress.Phase),
+	}
+	if progress.Started.IsZero() {
+		return response
+	}
+
+	response["started"] = progress.Started.UTC().Format(time.RFC3339)
+	response["flatten_duration"] = progress.FlattenDuration.String()
+	response["gc_rounds"] = progress.GCRounds
+	response["max_gc_rounds"] = progress.Config.MaxGCRounds
+	response["rewritten_files"] = progress.RewrittenFiles
+	if progress.Result != nil {
+		response["duration"] = progress.Result.Duration.String()
+	}
+	if progress.Err != nil {
+		response["error"] = progress.Err.Error()
+	}
+	return response
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (c *CompactDBCommand) Validator(req *admin.CommandRequest) error {
+	data := compactDBRequest{
+		action: compactDBActionStart,
+		config: badgerstorage.DefaultCompactionConfig(),
+	}
+
+	if req.Data != nil {
+		input, ok := req.Data.(map[string]interface{})
+		if !ok {
+			return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+		}
+
+		if action, ok := input["action"]; ok {
+			str, ok := action.(string)
+			if !ok || (str != compactDBActionStart && str != compactDBActionStatus && str != compactDBActionCancel) {
+				return admin.NewInvalidAdminReqParameterError("action", "must be one of start, status or cancel", action)
+			}
+			data.action = str
+		}
+
+		if flatten, ok := input["flatten"]; ok {
+			b, ok := flatten.(bool)
+			if !ok {
+				return admin.NewInvalidAdminReqParameterError("flatten", "must be a boolean", flatten)
+			}
+			data.config.Flatten = b
+		}
+
+		if workers, ok := input["flatten_workers"]; ok {
+			n, ok := workers.(float64)
+			if !ok || n < 1 || n != float64(int(n)) {
+				return admin.NewInvalidAdminReqParameterError("flatten_workers", "must be a positive integer", workers)
+			}
+			data.config.FlattenWorkers = int(n)
+		}
+
+		if ratio, ok := input["discard_ratio"]; ok {
+			r, ok := ratio.(float64)
+			if !ok || r <= 0 || r >= 1 {
+				return admin.NewInvalidAdminReqParameterError("discard_ratio", "must be a number in (0, 1)", ratio)
+			}
+			data.config.DiscardRatio = r
+		}
+
+		if pause, ok := input["gc_pause"]; ok {
+			str, ok := pause.(string)
+			if !ok {
+				return admin.NewInvalidAdminReqParameterError("gc_pause", "must be a duration string", pause)
+			}
+			d, err := time.ParseDuration(str)
+			if err != nil || d < 0 {
+				return admin.NewInvalidAdminReqParameterError("gc_pause", "must be a non-negative duration string", pause)
+			}
+			data.config.GCPause = d
+		}
+
+		if rounds, ok := input["max_gc_rounds"]; ok {
+			n, ok := rounds.(float64)
+			if !ok || n < 1 || n != float64(uint(n)) {
+				return admin.NewInvalidAdminReqParameterError("max_gc_rounds", "must be a positive integer", rounds)
+			}
+			data.config.MaxGCRounds = uint(n)
+		}
+	}
+
+	req.ValidatorData = data
+	return nil
+}
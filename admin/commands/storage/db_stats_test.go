@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReadDBPrefixStats(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		for i := 0; i < 2; i++ {
+			err := db.Update(operation.IndexStateCommitment(unittest.IdentifierFixture(), unittest.StateCommitmentFixture()))
+			require.NoError(t, err)
+		}
+
+		command := NewReadDBPrefixStatsCommand(db)
+		req := &admin.CommandRequest{}
+		require.NoError(t, command.Validator(req))
+
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		stats := result.(map[string]interface{})
+		prefixes := stats["prefixes"].([]interface{})
+		require.Len(t, prefixes, 1)
+		commits := prefixes[0].(map[string]interface{})
+		require.Equal(t, "commit", commits["name"])
+		require.Equal(t, uint64(2), commits["keys"])
+		require.Equal(t, commits["total_bytes"], stats["total_bytes"])
+	})
+}
+
+func TestCompactDB(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		compactor := badgerstorage.NewCompactor(unittest.Logger(), db, metrics.NewNoopCollector())
+		command := NewCompactDBCommand(compactor)
+
+		ctx, cancel := irrecoverable.NewMockSignalerContextWithCancel(t, context.Background())
+		compactor.Start(ctx)
+		unittest.RequireComponentsReadyBefore(t, time.Second, compactor)
+		defer func() {
+			cancel()
+			unittest.RequireCloseBefore(t, compactor.Done(), time.Second, "compactor did not stop")
+		}()
+
+		t.Run("invalid parameters", func(t *testing.T) {
+			invalid := []map[string]interface{}{
+				{"action": "flatten"},
+				{"action": float64(1)},
+				{"flatten": "yes"},
+				{"flatten_workers": float64(0)},
+				{"flatten_workers": 1.5},
+				{"discard_ratio": float64(1)},
+				{"discard_ratio": "0.5"},
+				{"gc_pause": "soon"},
+				{"gc_pause": float64(5)},
+				{"max_gc_rounds": float64(0)},
+			}
+			for _, data := range invalid {
+				err := command.Validator(&admin.CommandRequest{Data: data})
+				require.True(t, admin.IsInvalidAdminParameterError(err), data)
+			}
+
+			err := command.Validator(&admin.CommandRequest{Data: "compact"})
+			require.True(t, admin.IsInvalidAdminParameterError(err))
+		})
+
+		t.Run("defaults", func(t *testing.T) {
+			req := &admin.CommandRequest{}
+			require.NoError(t, command.Validator(req))
+			require.Equal(t, compactDBRequest{
+				action: compactDBActionStart,
+				config: badgerstorage.DefaultCompactionConfig(),
+			}, req.ValidatorData)
+		})
+
+		t.Run("status before compaction", func(t *testing.T) {
+			req := &admin.CommandRequest{Data: map[string]interface{}{"action": "status"}}
+			require.NoError(t, command.Validator(req))
+			result, err := command.Handler(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, map[string]interface{}{"phase": "idle"}, result)
+		})
+
+		t.Run("cancel without compaction", func(t *testing.T) {
+			req := &admin.CommandRequest{Data: map[string]interface{}{"action": "cancel"}}
+			require.NoError(t, command.Validator(req))
+			_, err := command.Handler(context.Background(), req)
+			require.ErrorIs(t, err, badgerstorage.ErrCompactionNotRunning)
+		})
+
+		t.Run("compact", func(t *testing.T) {
+			req := &admin.CommandRequest{Data: map[string]interface{}{
+				"flatten_workers": float64(2),
+				"discard_ratio":   0.7,
+				"gc_pause":        "1ms",
+				"max_gc_rounds":   float64(3),
+			}}
+			require.NoError(t, command.Validator(req))
+			config := badgerstorage.DefaultCompactionConfig()
+			config.FlattenWorkers = 2
+			config.DiscardRatio = 0.7
+			config.GCPause = time.Millisecond
+			config.MaxGCRounds = 3
+			require.Equal(t, compactDBRequest{action: compactDBActionStart, config: config}, req.ValidatorData)
+
+			result, err := command.Handler(context.Background(), req)
+			require.NoError(t, err)
+			require.Contains(t, result, "phase")
+
+			status := &admin.CommandRequest{Data: map[string]interface{}{"action": "status"}}
+			require.NoError(t, command.Validator(status))
+			require.Eventually(t, func() bool {
+				result, err := command.Handler(context.Background(), status)
+				require.NoError(t, err)
+				return result.(map[string]interface{})["phase"] == "completed"
+			}, time.Second, 10*time.Millisecond)
+		})
+	})
+}
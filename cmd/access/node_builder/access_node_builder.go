@@ -35,8 +35,10 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
 	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
 	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/engine/access/epocharchive"
 	"github.com/onflow/flow-go/engine/access/ingestion"
 	pingeng "github.com/onflow/flow-go/engine/access/ping"
+	"github.com/onflow/flow-go/engine/access/registerproofs"
 	"github.com/onflow/flow-go/engine/access/rest/routes"
 	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
@@ -44,7 +46,9 @@ import (
 	"github.com/onflow/flow-go/engine/access/state_stream"
 	followereng "github.com/onflow/flow-go/engine/common/follower"
 	"github.com/onflow/flow-go/engine/common/requester"
+	commonrpc "github.com/onflow/flow-go/engine/common/rpc"
 	synceng "github.com/onflow/flow-go/engine/common/synchronization"
+	"github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
@@ -59,6 +63,7 @@ import (
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/metrics/unstaked"
+	"github.com/onflow/flow-go/module/segmentstream"
 	"github.com/onflow/flow-go/module/state_synchronization"
 	edrequester "github.com/onflow/flow-go/module/state_synchronization/requester"
 	"github.com/onflow/flow-go/network"
@@ -130,6 +135,12 @@ type AccessNodeConfig struct {
 	executionDataDir             string
 	executionDataStartHeight     uint64
 	executionDataConfig          edrequester.ExecutionDataConfig
+	registerProofsEnabled        bool
+	registerProofsCheckpoint     string
+	registerProofsCapacity       int
+	epochArchiveEnabled          bool
+	forwardedTxCacheSize         uint
+	forwardedTxTTL               time.Duration
 	PublicNetworkConfig          PublicNetworkConfig
 }
 
@@ -152,6 +163,7 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 			SecureGRPCListenAddr:   "0.0.0.0:9001",
 			HTTPListenAddr:         "0.0.0.0:8000",
 			RESTListenAddr:         "",
+			SealingSegmentAddr:     "",
 			CollectionAddr:         "",
 			HistoricalAccessAddrs:  "",
 			BackendConfig: backend.Config{
@@ -207,6 +219,12 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 			RetryDelay:         edrequester.DefaultRetryDelay,
 			MaxRetryDelay:      edrequester.DefaultMaxRetryDelay,
 		},
+		registerProofsEnabled:    false,
+		registerProofsCheckpoint: "",
+		registerProofsCapacity:   registerproofs.DefaultCapacity,
+		epochArchiveEnabled:      false,
+		forwardedTxCacheSize:     commonrpc.DefaultForwardedTransactionsCacheSize,
+		forwardedTxTTL:           commonrpc.DefaultForwardedTransactionsTTL,
 	}
 }
 
@@ -240,6 +258,8 @@ type FlowAccessNodeBuilder struct {
 	ExecutionDataRequester     state_synchronization.ExecutionDataRequester
 	ExecutionDataStore         execution_data.ExecutionDataStore
 	ExecutionDataCache         *execdatacache.ExecutionDataCache
+	RegisterProofIndex         *registerproofs.Index
+	EpochArchive               *epocharchive.Archive
 
 	// The sync engine participants provider is the libp2p peer store for the access node
 	// which is not available until after the network has started.
@@ -277,9 +297,12 @@ func (builder *FlowAccessNodeBuilder) buildFollowerState() *FlowAccessNodeBuilde
 			node.Storage.Payloads,
 			blocktimer.DefaultBlockTimer,
 		)
+		if err != nil {
+			return err
+		}
 		builder.FollowerState = followerState
 
-		return err
+		return cmd.ExtendRootFollowingBlocks(node, followerState)
 	})
 
 	return builder
@@ -498,9 +521,9 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 					bitswap.WithPeerBlockRequestFilter(
 						blob.AuthorizedRequester(nil, builder.IdentityProvider, builder.Logger),
 					),
-					bitswap.WithTracer(
-						blob.NewTracer(node.Logger.With().Str("blob_service", channels.ExecutionDataService.String()).Logger()),
-					),
+				),
+				blob.WithTracer(
+					blob.NewTracer(node.Logger.With().Str("blob_service", channels.ExecutionDataService.String()).Logger()),
 				),
 			}
 
@@ -586,6 +609,65 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 			return builder.ExecutionDataRequester, nil
 		})
 
+	if builder.registerProofsEnabled {
+		builder.
+			// the index is created as a module, so that it is available to the RPC engine
+			Module("register proof index", func(node *cmd.NodeConfig) error {
+				// the index applies the execution data of all blocks since the root execution state,
+				// which is only available from the root checkpoint of the spork
+				if node.SealedRootBlock.ID() != node.FinalizedRootBlock.ID() {
+					return fmt.Errorf("register proofs require a spork root snapshot, but sealed root block (%d) differs from finalized root block (%d)",
+						node.SealedRootBlock.Header.Height, node.FinalizedRootBlock.Header.Height)
+				}
+
+				checkpointPath := builder.registerProofsCheckpoint
+				if checkpointPath == "" {
+					checkpointPath = filepath.Join(node.BootstrapDir, bootstrap.PathRootCheckpoint)
+				}
+				rootTrie, err := registerproofs.LoadRootTrie(node.Logger, checkpointPath, node.RootSeal.FinalState)
+				if err != nil {
+					return fmt.Errorf("could not load root trie: %w", err)
+				}
+
+				// Execution Data cache that uses the local blobstore as the backend, so that it
+				// returns a not found error instead of downloading missing execution data.
+				var heroCacheCollector module.HeroCacheMetrics = metrics.NewNoopCollector()
+				executionDataCache := execdatacache.NewExecutionDataCache(
+					builder.ExecutionDataStore,
+					node.Storage.Headers,
+					node.Storage.Seals,
+					node.Storage.Results,
+					herocache.NewBlockExecutionData(state_stream.DefaultCacheSize, node.Logger, heroCacheCollector),
+				)
+
+				builder.RegisterProofIndex, err = registerproofs.New(
+					node.Logger,
+					metrics.NewNoopCollector(),
+					builder.registerProofsCapacity,
+					node.SealedRootBlock.Header.Height,
+					rootTrie,
+					node.Storage.Headers,
+					node.Storage.Seals,
+					executionDataCache,
+				)
+				if err != nil {
+					return fmt.Errorf("could not create register proof index: %w", err)
+				}
+				return nil
+			}).
+			Component("register proof index", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+				highestAvailableHeight, err := builder.ExecutionDataRequester.HighestConsecutiveHeight()
+				if err != nil {
+					return nil, fmt.Errorf("could not get highest consecutive height: %w", err)
+				}
+				builder.RegisterProofIndex.OnHighestAvailableHeight(highestAvailableHeight)
+
+				execDataDistributor.AddOnExecutionDataReceivedConsumer(builder.RegisterProofIndex.OnExecutionData)
+
+				return builder.RegisterProofIndex, nil
+			})
+	}
+
 	if builder.stateStreamConf.ListenAddr != "" {
 		builder.Component("exec state stream engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			for key, value := range builder.stateStreamFilterConf {
@@ -675,6 +757,7 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.StringVar(&builder.stateStreamConf.ListenAddr, "state-stream-addr", defaultConfig.stateStreamConf.ListenAddr, "the address the state stream server listens on (if empty the server will not be started)")
 		flags.StringVarP(&builder.rpcConf.HTTPListenAddr, "http-addr", "h", defaultConfig.rpcConf.HTTPListenAddr, "the address the http proxy server listens on")
 		flags.StringVar(&builder.rpcConf.RESTListenAddr, "rest-addr", defaultConfig.rpcConf.RESTListenAddr, "the address the REST server listens on (if empty the REST server will not be started)")
+		flags.StringVar(&builder.rpcConf.SealingSegmentAddr, "sealing-segment-addr", defaultConfig.rpcConf.SealingSegmentAddr, "the address the HTTPS server streaming sealing segments to nodes bootstrapping from this node listens on, authenticated with the networking key (if empty the server will not be started)")
 		flags.StringVarP(&builder.rpcConf.CollectionAddr, "static-collection-ingress-addr", "", defaultConfig.rpcConf.CollectionAddr, "the address (of the collection node) to send transactions to")
 		flags.StringVarP(&builder.ExecutionNodeAddress, "script-addr", "s", defaultConfig.ExecutionNodeAddress, "the address (of the execution node) forward the script to")
 		flags.StringSliceVar(&builder.rpcConf.BackendConfig.ArchiveAddressList, "archive-address-list", defaultConfig.rpcConf.BackendConfig.ArchiveAddressList, "the list of address of the archive node to forward the script queries to")
@@ -719,6 +802,12 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.StringToIntVar(&builder.stateStreamFilterConf, "state-stream-event-filter-limits", defaultConfig.stateStreamFilterConf, "event filter limits for ExecutionData SubscribeEvents API e.g. EventTypes=100,Addresses=100,Contracts=100 etc.")
 		flags.DurationVar(&builder.stateStreamConf.ClientSendTimeout, "state-stream-send-timeout", defaultConfig.stateStreamConf.ClientSendTimeout, "maximum wait before timing out while sending a response to a streaming client e.g. 30s")
 		flags.UintVar(&builder.stateStreamConf.ClientSendBufferSize, "state-stream-send-buffer-size", defaultConfig.stateStreamConf.ClientSendBufferSize, "maximum number of responses to buffer within a stream")
+		flags.BoolVar(&builder.registerProofsEnabled, "register-proofs-enabled", defaultConfig.registerProofsEnabled, "whether to index the execution state from execution data to serve register and account proofs. requires execution data sync, and the root checkpoint of the spork")
+		flags.StringVar(&builder.registerProofsCheckpoint, "register-proofs-checkpoint", defaultConfig.registerProofsCheckpoint, "path to the checkpoint file holding the root execution state (defaults to the root checkpoint in the bootstrap directory)")
+		flags.IntVar(&builder.registerProofsCapacity, "register-proofs-capacity", defaultConfig.registerProofsCapacity, "number of execution states of the most recent sealed blocks retained in memory to serve register proofs for")
+		flags.UintVar(&builder.forwardedTxCacheSize, "forwarded-tx-cache-size", defaultConfig.forwardedTxCacheSize, "number of recently forwarded transactions remembered to suppress forwarding duplicate submissions (0 to disable)")
+		flags.DurationVar(&builder.forwardedTxTTL, "forwarded-tx-ttl", defaultConfig.forwardedTxTTL, "time for which a forwarded transaction is remembered to suppress forwarding duplicate submissions")
+		flags.BoolVar(&builder.epochArchiveEnabled, "epoch-archive-enabled", defaultConfig.epochArchiveEnabled, "whether to archive the identity table and service events of every epoch since the root block, to serve the protocol state at historical heights")
 		flags.Float64Var(&builder.stateStreamConf.ResponseLimit, "state-stream-response-limit", defaultConfig.stateStreamConf.ResponseLimit, "max number of responses per second to send over streaming endpoints. this helps manage resources consumed by each client querying data not in the cache e.g. 3 or 0.5. 0 means no limit")
 	}).ValidateFlags(func() error {
 		if builder.supportsObserver && (builder.PublicNetworkConfig.BindAddress == cmd.NotSet || builder.PublicNetworkConfig.BindAddress == "") {
@@ -741,6 +830,17 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 				return errors.New("execution-data-max-search-ahead must be greater than 0")
 			}
 		}
+		if builder.registerProofsEnabled {
+			if !builder.executionDataSyncEnabled {
+				return errors.New("execution-data-sync-enabled must be true if register-proofs-enabled is true")
+			}
+			if builder.executionDataStartHeight > 0 {
+				return errors.New("execution-data-start-height must not be set if register-proofs-enabled is true")
+			}
+			if builder.registerProofsCapacity <= 0 {
+				return errors.New("register-proofs-capacity must be greater than 0")
+			}
+		}
 		if builder.stateStreamConf.ListenAddr != "" {
 			if builder.stateStreamConf.ExecutionDataCacheSize == 0 {
 				return errors.New("execution-data-cache-size must be greater than 0")
@@ -811,6 +911,8 @@ func (builder *FlowAccessNodeBuilder) initNetwork(nodeID module.Local,
 			AlspMetrics:             builder.Metrics.Network,
 			NetworkType:             network.PublicNetwork,
 			HeroCacheMetricsFactory: builder.HeroCacheMetricsFactory(),
+			SpamRecordStore:         builder.AlspSpamRecordStore(network.PublicNetwork),
+			PersistenceInterval:     builder.FlowConfig.NetworkConfig.AlspConfig.SpamRecordPersistenceInterval,
 		},
 	})
 	if err != nil {
@@ -901,6 +1003,7 @@ func (builder *FlowAccessNodeBuilder) Initialize() error {
 	}
 
 	builder.EnqueueTracer()
+	builder.PreInit(cmd.PeerBootstrapPreInit)
 	builder.PreInit(cmd.DynamicStartPreInit)
 	builder.ValidateRootSnapshot(badgerState.ValidRootSnapshotContainsEntityExpiryRange)
 
@@ -923,6 +1026,31 @@ func (builder *FlowAccessNodeBuilder) enqueueRelayNetwork() {
 }
 
 func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
+	if builder.epochArchiveEnabled {
+		builder.
+			// the archive is created as a module, so that it is available to the RPC engine
+			Module("epoch archive", func(node *cmd.NodeConfig) error {
+				var err error
+				builder.EpochArchive, err = epocharchive.New(
+					node.Logger,
+					node.DB,
+					node.State,
+					node.Storage.Headers,
+					node.Storage.Statuses,
+					node.Storage.Setups,
+					node.Storage.EpochCommits,
+				)
+				if err != nil {
+					return fmt.Errorf("could not create epoch archive: %w", err)
+				}
+				return nil
+			}).
+			Component("epoch archive", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+				builder.FollowerDistributor.AddOnBlockFinalizedConsumer(builder.EpochArchive.OnFinalizedBlock)
+				return builder.EpochArchive, nil
+			})
+	}
+
 	builder.
 		BuildConsensusFollower().
 		Module("collection node client", func(node *cmd.NodeConfig) error {
@@ -1024,6 +1152,7 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 			}
 			tlsConfig := grpcutils.DefaultServerTLSConfig(x509Certificate)
 			builder.rpcConf.TransportCredentials = credentials.NewTLS(tlsConfig)
+			builder.rpcConf.SealingSegmentTLS = tlsConfig
 			return nil
 		}).
 		Module("creating grpc servers", func(node *cmd.NodeConfig) error {
@@ -1114,6 +1243,32 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				backendConfig.ScriptExecValidation,
 				backendConfig.CircuitBreakerConfig.Enabled)
 
+			if builder.forwardedTxCacheSize > 0 {
+				forwarded, err := commonrpc.NewForwardedTransactions(int(builder.forwardedTxCacheSize), builder.forwardedTxTTL)
+				if err != nil {
+					return nil, err
+				}
+				backend.SetForwardedTransactions(forwarded)
+			}
+			if builder.RegisterProofIndex != nil {
+				backend.SetRegisterProver(builder.RegisterProofIndex)
+			}
+			if builder.EpochArchive != nil {
+				backend.SetEpochArchive(builder.EpochArchive)
+			}
+			if config.SealingSegmentAddr != "" {
+				provider, err := segmentstream.NewProvider(
+					segmentstream.DefaultProviderConfig(),
+					node.State,
+					node.Storage.Headers,
+					node.Storage.Blocks,
+				)
+				if err != nil {
+					return nil, fmt.Errorf("could not create sealing segment provider: %w", err)
+				}
+				backend.SetSealingSegmentProvider(provider)
+			}
+
 			engineBuilder, err := rpc.NewBuilder(
 				node.Logger,
 				node.State,
@@ -1154,6 +1309,7 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				channels.RequestCollections,
 				filter.HasRole(flow.RoleCollection),
 				func() flow.Entity { return &flow.Collection{} },
+				requester.WithRequestService(node.CollectionRequestServiceEnabled),
 			)
 			if err != nil {
 				return nil, fmt.Errorf("could not create requester engine: %w", err)
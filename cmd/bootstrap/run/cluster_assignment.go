@@ -0,0 +1,77 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/assignment"
+	"github.com/onflow/flow-go/model/flow/factory"
+)
+
+// ConstructClusterAssignment constructs a random cluster assignment of the given partner and
+// internal collection nodes into numClusters clusters.
+// The number of nodes in each cluster is deterministic and only depends on the number of clusters
+// and the number of nodes. The repartition of internal and partner nodes is also deterministic
+// and only depends on the number of clusters and nodes.
+// The identity of internal and partner nodes in each cluster is non-deterministic and is randomized
+// using the system entropy.
+// The function guarantees a specific constraint when partitioning the nodes into clusters:
+// Each cluster must contain strictly more than 2/3 of internal nodes, which are able to sign the
+// cluster's root QC. If the constraint can't be satisfied, an error is returned.
+// Note that if an error is returned with a certain number of internal/partner nodes, there is no chance
+// of succeeding the assignment by re-running the function without increasing the internal nodes ratio.
+func ConstructClusterAssignment(partners, internals flow.IdentityList, numClusters uint) (flow.AssignmentList, flow.ClusterList, error) {
+	if numClusters == 0 {
+		return nil, nil, fmt.Errorf("must have at least one cluster")
+	}
+	nCollectors := uint(len(partners) + len(internals))
+
+	// ensure we have at least as many collection nodes as clusters
+	if nCollectors < numClusters {
+		return nil, nil, fmt.Errorf("configured with %d collection nodes, but %d clusters - must have at least one collection node per cluster",
+			nCollectors, numClusters)
+	}
+
+	// shuffle both collector lists based on a non-deterministic algorithm
+	partners, err := partners.Shuffle()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not shuffle partners: %w", err)
+	}
+	internals, err = internals.Shuffle()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not shuffle internals: %w", err)
+	}
+
+	identifierLists := make([]flow.IdentifierList, numClusters)
+	// array to track the 2/3 internal-nodes constraint (internal_nodes > 2 * partner_nodes)
+	constraint := make([]int, numClusters)
+
+	// first, round-robin internal nodes into each cluster
+	for i, node := range internals {
+		identifierLists[uint(i)%numClusters] = append(identifierLists[uint(i)%numClusters], node.NodeID)
+		constraint[uint(i)%numClusters] += 1
+	}
+
+	// next, round-robin partner nodes into each cluster
+	for i, node := range partners {
+		identifierLists[uint(i)%numClusters] = append(identifierLists[uint(i)%numClusters], node.NodeID)
+		constraint[uint(i)%numClusters] -= 2
+	}
+
+	// check the 2/3 constraint: for every cluster `i`, constraint[i] must be strictly positive
+	for i := range constraint {
+		if constraint[i] <= 0 {
+			return nil, nil, fmt.Errorf("there isn't enough internal nodes to have at least 2/3 internal nodes in cluster %d", i)
+		}
+	}
+
+	assignments := assignment.FromIdentifierLists(identifierLists)
+
+	collectors := append(partners, internals...)
+	clusters, err := factory.NewClusterList(assignments, collectors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create cluster list: %w", err)
+	}
+
+	return assignments, clusters, nil
+}
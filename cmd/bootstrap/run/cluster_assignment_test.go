@@ -0,0 +1,62 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// collectorsFixture returns n collection node identities without keys.
+func collectorsFixture(n int) flow.IdentityList {
+	collectors := make(flow.IdentityList, 0, n)
+	for i := 0; i < n; i++ {
+		collectors = append(collectors, &flow.Identity{
+			NodeID: unittest.IdentifierFixture(),
+			Role:   flow.RoleCollection,
+			Weight: 1000,
+		})
+	}
+	return collectors
+}
+
+func TestConstructClusterAssignment(t *testing.T) {
+	t.Run("more than 2/3 internal nodes in each cluster", func(t *testing.T) {
+		// limit set-up, can't have one less internal node
+		partners := collectorsFixture(7)
+		internals := collectorsFixture(22)
+
+		assignments, clusters, err := ConstructClusterAssignment(partners, internals, 5)
+		require.NoError(t, err)
+		require.Len(t, assignments, 5)
+		require.Len(t, clusters, 5)
+
+		for _, cluster := range clusters {
+			numInternals := 0
+			for _, member := range cluster {
+				if _, ok := internals.ByNodeID(member.NodeID); ok {
+					numInternals++
+				}
+			}
+			assert.Greater(t, numInternals, 2*(len(cluster)-numInternals))
+		}
+	})
+
+	t.Run("not enough internal nodes", func(t *testing.T) {
+		_, _, err := ConstructClusterAssignment(collectorsFixture(7), collectorsFixture(21), 5)
+		require.Error(t, err)
+	})
+
+	t.Run("fewer collection nodes than clusters", func(t *testing.T) {
+		_, _, err := ConstructClusterAssignment(nil, collectorsFixture(2), 3)
+		require.Error(t, err)
+	})
+
+	t.Run("no clusters", func(t *testing.T) {
+		_, _, err := ConstructClusterAssignment(nil, collectorsFixture(2), 0)
+		require.Error(t, err)
+	})
+}
@@ -44,6 +44,8 @@ import (
 	"github.com/onflow/flow-go/module/mempool"
 	epochpool "github.com/onflow/flow-go/module/mempool/epochs"
 	"github.com/onflow/flow-go/module/mempool/herocache"
+	"github.com/onflow/flow-go/module/mempool/persistent"
+	"github.com/onflow/flow-go/module/mempool/priority"
 	"github.com/onflow/flow-go/module/mempool/queue"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network/channels"
@@ -68,6 +70,12 @@ func main() {
 		builderPayerRateLimitDryRun       bool
 		builderPayerRateLimit             float64
 		builderUnlimitedPayers            []string
+		txPriorityPolicy                  string
+		txPolicy                          priority.Policy // parsed from txPriorityPolicy, nil if not set
+		txMaxPayerPoolSize                uint
+		txAgePromotionInterval            time.Duration
+		txPayerRankPenalty                float64
+		txPoolPersistence                 bool
 		hotstuffMinTimeout                time.Duration
 		hotstuffTimeoutAdjustmentFactor   float64
 		hotstuffHappyPathMaxRoundFailures uint64
@@ -106,6 +114,17 @@ func main() {
 	nodeBuilder.ExtraFlags(func(flags *pflag.FlagSet) {
 		flags.UintVar(&txLimit, "tx-limit", 50_000,
 			"maximum number of transactions in the memory pool")
+		flags.BoolVar(&txPoolPersistence, "tx-pool-persistence", false,
+			"whether pending transactions are persisted, so that non-expired transactions are restored after a restart")
+		flags.StringVar(&txPriorityPolicy, "tx-priority-policy", "",
+			fmt.Sprintf("ordering policy of the transaction memory pool, one of [%s, %s, %s, %s] (empty for insertion order without payer fairness)",
+				priority.PolicyFIFO, priority.PolicyFee, priority.PolicyInclusionEffort, priority.PolicyFeePerEffort))
+		flags.UintVar(&txMaxPayerPoolSize, "tx-max-payer-pool-size", priority.DefaultMaxPayerTransactions,
+			"maximum number of transactions per payer in the memory pool, only used with --tx-priority-policy (0 for no limit)")
+		flags.DurationVar(&txAgePromotionInterval, "tx-age-promotion-interval", priority.DefaultAgePromotionInterval,
+			"time after which a pending transaction is promoted by one priority level, only used with --tx-priority-policy (0 to disable)")
+		flags.Float64Var(&txPayerRankPenalty, "tx-payer-rank-penalty", priority.DefaultPayerRankPenalty,
+			"priority penalty per preceding transaction of the same payer, only used with --tx-priority-policy (0 to disable)")
 		flags.StringVarP(&rpcConf.ListenAddr, "ingress-addr", "i", "localhost:9000",
 			"the address the ingress server listens on")
 		flags.UintVar(&rpcConf.MaxMsgSize, "rpc-max-message-size", grpcutils.DefaultMaxMsgSize,
@@ -124,6 +143,10 @@ func main() {
 			"expiry buffer for inbound transactions")
 		flags.UintVar(&ingestConf.PropagationRedundancy, "ingest-tx-propagation-redundancy", 10,
 			"how many additional cluster members we propagate transactions to")
+		flags.UintVar(&ingestConf.ForwardedTransactionsCacheSize, "ingest-propagated-tx-cache-size", ingest.DefaultConfig().ForwardedTransactionsCacheSize,
+			"number of recently propagated transactions remembered to suppress propagating duplicate submissions (0 to disable)")
+		flags.DurationVar(&ingestConf.ForwardedTransactionsTTL, "ingest-propagated-tx-ttl", ingest.DefaultConfig().ForwardedTransactionsTTL,
+			"time for which a propagated transaction is remembered to suppress propagating duplicate submissions")
 		flags.UintVar(&builderExpiryBuffer, "builder-expiry-buffer", builder.DefaultExpiryBuffer,
 			"expiry buffer for transactions in proposed collections")
 		flags.BoolVar(&builderPayerRateLimitDryRun, "builder-rate-limit-dry-run", false,
@@ -169,6 +192,13 @@ func main() {
 			}
 			startupTime = t
 		}
+		if txPriorityPolicy != "" {
+			policy, err := priority.ParsePolicy(txPriorityPolicy)
+			if err != nil {
+				return fmt.Errorf("invalid tx-priority-policy value: %w", err)
+			}
+			txPolicy = policy
+		}
 		if deprecatedFlagBlockRateDelay > 0 {
 			nodeBuilder.Logger.Warn().Msg("A deprecated flag was specified (--block-rate-delay). This flag is deprecated as of v0.30 (Jun 2023), has no effect, and will eventually be removed.")
 		}
@@ -180,6 +210,7 @@ func main() {
 	}
 
 	nodeBuilder.
+		PreInit(cmd.PeerBootstrapPreInit).
 		PreInit(cmd.DynamicStartPreInit).
 		AdminCommand("read-range-cluster-blocks", func(conf *cmd.NodeConfig) commands.AdminCommand {
 			clusterPayloads := badger.NewClusterPayloads(&metrics.NoopCollector{}, conf.DB)
@@ -210,23 +241,86 @@ func main() {
 				node.Storage.Payloads,
 				blocktimer.DefaultBlockTimer,
 			)
-			return err
+			if err != nil {
+				return err
+			}
+			return cmd.ExtendRootFollowingBlocks(node, followerState)
 		}).
 		Module("transactions mempool", func(node *cmd.NodeConfig) error {
-			create := func(epoch uint64) mempool.Transactions {
-				var heroCacheMetricsCollector module.HeroCacheMetrics = metrics.NewNoopCollector()
-				if node.BaseConfig.HeroCacheMetricsEnable {
-					heroCacheMetricsCollector = metrics.CollectionNodeTransactionsCacheMetrics(node.MetricsRegisterer, epoch)
+			// createInMemory creates the in-memory transaction pool of an epoch, which notifies
+			// the given callback of ejected transactions, if not nil
+			var createInMemory func(epoch uint64, onEjected func(*flow.TransactionBody)) mempool.Transactions
+			if txPolicy != nil {
+				unlimitedPayers := make([]flow.Address, 0, len(builderUnlimitedPayers))
+				for _, payerStr := range builderUnlimitedPayers {
+					unlimitedPayers = append(unlimitedPayers, flow.HexToAddress(payerStr))
+				}
+				createInMemory = func(epoch uint64, onEjected func(*flow.TransactionBody)) mempool.Transactions {
+					return priority.NewTransactions(
+						txLimit,
+						priority.WithPolicy(txPolicy),
+						priority.WithMaxPayerTransactions(txMaxPayerPoolSize),
+						priority.WithUnlimitedPayers(unlimitedPayers...),
+						priority.WithAgePromotionInterval(txAgePromotionInterval),
+						priority.WithPayerRankPenalty(txPayerRankPenalty),
+						priority.WithEjectionCallback(onEjected),
+					)
+				}
+			} else {
+				createInMemory = func(epoch uint64, onEjected func(*flow.TransactionBody)) mempool.Transactions {
+					var heroCacheMetricsCollector module.HeroCacheMetrics = metrics.NewNoopCollector()
+					if node.BaseConfig.HeroCacheMetricsEnable {
+						heroCacheMetricsCollector = metrics.CollectionNodeTransactionsCacheMetrics(node.MetricsRegisterer, epoch)
+					}
+					return herocache.NewTransactions(
+						uint32(txLimit),
+						node.Logger,
+						heroCacheMetricsCollector,
+						herocache.WithEjectionCallback(onEjected))
 				}
-				return herocache.NewTransactions(
-					uint32(txLimit),
-					node.Logger,
-					heroCacheMetricsCollector)
 			}
 
-			pools = epochpool.NewTransactionPools(create)
-			err := node.Metrics.Mempool.Register(metrics.ResourceTransaction, pools.CombinedSize)
-			return err
+			if !txPoolPersistence {
+				pools = epochpool.NewTransactionPools(func(epoch uint64) mempool.Transactions {
+					return createInMemory(epoch, nil)
+				})
+				return node.Metrics.Mempool.Register(metrics.ResourceTransaction, pools.CombinedSize)
+			}
+
+			createPersistent := func(epoch uint64) *persistent.Transactions {
+				return persistent.NewTransactions(node.Logger, node.DB, epoch, func(onEjected func(*flow.TransactionBody)) mempool.Transactions {
+					return createInMemory(epoch, onEjected)
+				})
+			}
+
+			// restore the pools of all epochs with persisted transactions before cluster consensus
+			// starts. Persisted transactions are revalidated with the same rules as inbound transactions.
+			// The pools of epochs whose cluster consensus does not resume are pruned by the epoch manager.
+			validator := ingest.NewTransactionValidator(node.State, node.RootChainID.Chain(), ingestConf)
+			persistedEpochs, err := persistent.PersistedEpochs(node.DB)
+			if err != nil {
+				return err
+			}
+			restored := make(map[uint64]*persistent.Transactions, len(persistedEpochs))
+			for _, epoch := range persistedEpochs {
+				pool := createPersistent(epoch)
+				_, _, err := pool.Restore(validator)
+				if err != nil {
+					return fmt.Errorf("could not restore persisted transaction pool of epoch %d: %w", epoch, err)
+				}
+				restored[epoch] = pool
+			}
+
+			pools = epochpool.NewTransactionPools(func(epoch uint64) mempool.Transactions {
+				if pool, ok := restored[epoch]; ok {
+					return pool
+				}
+				return createPersistent(epoch)
+			})
+			for epoch := range restored {
+				_ = pools.ForEpoch(epoch)
+			}
+			return node.Metrics.Mempool.Register(metrics.ResourceTransaction, pools.CombinedSize)
 		}).
 		Module("metrics", func(node *cmd.NodeConfig) error {
 			colMetrics = metrics.NewCollectionCollector(node.Tracer)
@@ -412,7 +506,7 @@ func main() {
 			}
 			collectionRequestQueue := queue.NewHeroStore(maxCollectionRequestCacheSize, node.Logger, collectionRequestMetrics)
 
-			return provider.New(
+			eng, err := provider.New(
 				node.Logger,
 				node.Metrics.Engine,
 				node.Network,
@@ -427,6 +521,16 @@ func main() {
 				),
 				retrieve,
 			)
+			if err != nil {
+				return nil, err
+			}
+			if node.CollectionRequestServiceEnabled {
+				err = eng.RegisterRequestService(node.Network)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return eng, nil
 		}).
 		Component("pusher engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			push, err = pusher.New(
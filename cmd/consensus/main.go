@@ -212,6 +212,7 @@ func main() {
 	}
 
 	nodeBuilder.
+		PreInit(cmd.PeerBootstrapPreInit).
 		PreInit(cmd.DynamicStartPreInit).
 		ValidateRootSnapshot(badgerState.ValidRootSnapshotContainsEntityExpiryRange).
 		Module("consensus node metrics", func(node *cmd.NodeConfig) error {
@@ -293,7 +294,10 @@ func main() {
 				receiptValidator,
 				sealValidator,
 			)
-			return err
+			if err != nil {
+				return err
+			}
+			return cmd.ExtendRootFollowingBlocks(node, mutableState)
 		}).
 		Module("random beacon key", func(node *cmd.NodeConfig) error {
 			// If this node was a participant in a spork, their beacon key for the
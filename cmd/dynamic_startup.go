@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
@@ -71,6 +72,10 @@ func GetSnapshotAtEpochAndPhase(ctx context.Context, log zerolog.Logger, startup
 		snapshot, err = getSnapshot(ctx)
 		if err != nil {
 			err = fmt.Errorf("failed to get protocol snapshot: %w", err)
+			// conflicting snapshots of the configured access nodes are not resolved by retrying
+			if errors.Is(err, ErrSnapshotQuorumDisagreement) {
+				return err
+			}
 			log.Error().Err(err).Msg("could not get protocol snapshot")
 			return retry.RetryableError(err)
 		}
@@ -118,12 +123,7 @@ func GetSnapshotAtEpochAndPhase(ctx context.Context, log zerolog.Logger, startup
 // - assert dynamic-startup-access-address is not empty
 // - assert dynamic-startup-startup-epoch-phase is > 0 (EpochPhaseUndefined)
 func ValidateDynamicStartupFlags(accessPublicKey, accessAddress string, startPhase flow.EpochPhase) error {
-	b, err := hex.DecodeString(strings.TrimPrefix(accessPublicKey, "0x"))
-	if err != nil {
-		return fmt.Errorf("invalid flag --dynamic-startup-access-publickey: %w", err)
-	}
-
-	_, err = crypto.DecodePublicKey(crypto.ECDSAP256, b)
+	err := validateAccessPublicKey(accessPublicKey)
 	if err != nil {
 		return fmt.Errorf("invalid flag --dynamic-startup-access-publickey: %w", err)
 	}
@@ -139,6 +139,49 @@ func ValidateDynamicStartupFlags(accessPublicKey, accessAddress string, startPha
 	return nil
 }
 
+// ValidateDynamicStartupQuorumFlags will validate flags necessary for node startup using a quorum of access nodes
+// - assert there is a dynamic-startup-access-publickeys entry for each dynamic-startup-access-addresses entry
+// - assert dynamic-startup-access-publickeys are valid ECDSA_P256 public key hex
+// - assert dynamic-startup-access-addresses are not empty
+// - assert dynamic-startup-quorum does not exceed the number of access nodes
+// - assert dynamic-startup-startup-epoch-phase is > 0 (EpochPhaseUndefined)
+func ValidateDynamicStartupQuorumFlags(accessPublicKeys, accessAddresses []string, quorum uint, startPhase flow.EpochPhase) error {
+	if len(accessPublicKeys) != len(accessAddresses) {
+		return fmt.Errorf("invalid flag --dynamic-startup-access-publickeys: expected %d public keys, got %d", len(accessAddresses), len(accessPublicKeys))
+	}
+
+	for i, address := range accessAddresses {
+		err := validateAccessPublicKey(accessPublicKeys[i])
+		if err != nil {
+			return fmt.Errorf("invalid flag --dynamic-startup-access-publickeys: %w", err)
+		}
+		if address == "" {
+			return fmt.Errorf("invalid flag --dynamic-startup-access-addresses can not contain empty addresses")
+		}
+	}
+
+	if quorum > uint(len(accessAddresses)) {
+		return fmt.Errorf("invalid flag --dynamic-startup-quorum can not exceed the number of access nodes (%d)", len(accessAddresses))
+	}
+
+	if startPhase <= flow.EpochPhaseUndefined {
+		return fmt.Errorf("invalid flag --dynamic-startup-startup-epoch-phase unknown epoch phase")
+	}
+
+	return nil
+}
+
+// validateAccessPublicKey validates that the given access node public key is a valid ECDSA_P256 public key hex.
+func validateAccessPublicKey(accessPublicKey string) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(accessPublicKey, "0x"))
+	if err != nil {
+		return err
+	}
+
+	_, err = crypto.DecodePublicKey(crypto.ECDSAP256, b)
+	return err
+}
+
 // DynamicStartPreInit is the pre-init func that will check if a node has already bootstrapped
 // from a root protocol snapshot. If not attempt to get a protocol snapshot where the following
 // conditions are met.
@@ -161,6 +204,12 @@ func DynamicStartPreInit(nodeConfig *NodeConfig) error {
 		return nil
 	}
 
+	// skip dynamic startup if the root snapshot was already obtained, e.g. from a bootstrap peer
+	if nodeConfig.RootSnapshot != nil {
+		log.Info().Msg("root snapshot already obtained, skipping dynamic startup")
+		return nil
+	}
+
 	// skip dynamic startup if a root snapshot file is specified - this takes priority
 	rootSnapshotPath := filepath.Join(nodeConfig.BootstrapDir, bootstrap.PathRootProtocolStateSnapshot)
 	if utilsio.FileExists(rootSnapshotPath) {
@@ -170,19 +219,21 @@ func DynamicStartPreInit(nodeConfig *NodeConfig) error {
 		return nil
 	}
 
-	// get flow client with secure client connection to download protocol snapshot from access node
-	config, err := common.NewFlowClientConfig(nodeConfig.DynamicStartupANAddress, nodeConfig.DynamicStartupANPubkey, flow.ZeroID, false)
-	if err != nil {
-		return fmt.Errorf("failed to create flow client config for node dynamic startup pre-init: %w", err)
-	}
+	startupPhase := flow.GetEpochPhase(nodeConfig.DynamicStartupEpochPhase)
 
-	flowClient, err := common.FlowClient(config)
+	// validate the access node and phase dynamic startup flags
+	if len(nodeConfig.DynamicStartupANAddresses) > 0 {
+		err = ValidateDynamicStartupQuorumFlags(nodeConfig.DynamicStartupANPubkeys, nodeConfig.DynamicStartupANAddresses, nodeConfig.DynamicStartupQuorum, startupPhase)
+	} else {
+		err = ValidateDynamicStartupFlags(nodeConfig.DynamicStartupANPubkey, nodeConfig.DynamicStartupANAddress, startupPhase)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create flow client for node dynamic startup pre-init: %w", err)
+		return err
 	}
 
-	getSnapshotFunc := func(ctx context.Context) (protocol.Snapshot, error) {
-		return GetSnapshot(ctx, flowClient)
+	getSnapshotFunc, err := dynamicStartupGetSnapshotFunc(nodeConfig, log)
+	if err != nil {
+		return err
 	}
 
 	// validate dynamic startup epoch flag
@@ -191,14 +242,6 @@ func DynamicStartPreInit(nodeConfig *NodeConfig) error {
 		return fmt.Errorf("failed to validate flag --dynamic-start-epoch: %w", err)
 	}
 
-	startupPhase := flow.GetEpochPhase(nodeConfig.DynamicStartupEpochPhase)
-
-	// validate the rest of the dynamic startup flags
-	err = ValidateDynamicStartupFlags(nodeConfig.DynamicStartupANPubkey, nodeConfig.DynamicStartupANAddress, startupPhase)
-	if err != nil {
-		return err
-	}
-
 	snapshot, err := GetSnapshotAtEpochAndPhase(
 		ctx,
 		log,
@@ -216,6 +259,68 @@ func DynamicStartPreInit(nodeConfig *NodeConfig) error {
 	return nil
 }
 
+// dynamicStartupGetSnapshotFunc returns the function retrieving the latest finalized protocol snapshot
+// during dynamic startup. If multiple access nodes are configured, the snapshot is retrieved from all
+// of them and must be agreed on by a quorum of them. Otherwise, the snapshot of the single configured
+// access node is used.
+func dynamicStartupGetSnapshotFunc(nodeConfig *NodeConfig, log zerolog.Logger) (GetProtocolSnapshot, error) {
+	if len(nodeConfig.DynamicStartupANAddresses) == 0 {
+		flowClient, err := dynamicStartupFlowClient(nodeConfig.DynamicStartupANAddress, nodeConfig.DynamicStartupANPubkey)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (protocol.Snapshot, error) {
+			return GetSnapshot(ctx, flowClient)
+		}, nil
+	}
+
+	// the flags are validated, so there is a public key for each address
+	sources := make([]SnapshotSource, 0, len(nodeConfig.DynamicStartupANAddresses))
+	for i, address := range nodeConfig.DynamicStartupANAddresses {
+		flowClient, err := dynamicStartupFlowClient(address, nodeConfig.DynamicStartupANPubkeys[i])
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, SnapshotSource{
+			Address:   address,
+			PublicKey: nodeConfig.DynamicStartupANPubkeys[i],
+			GetSnapshot: func(ctx context.Context) (*inmem.Snapshot, error) {
+				return GetSnapshot(ctx, flowClient)
+			},
+		})
+	}
+
+	quorum := DynamicStartupQuorum(nodeConfig.DynamicStartupQuorum, len(sources))
+	cacheDir := filepath.Join(nodeConfig.BootstrapDir, bootstrap.DirnameDynamicStartupSnapshots)
+	return func(ctx context.Context) (protocol.Snapshot, error) {
+		return GetSnapshotWithQuorum(ctx, log, sources, quorum, cacheDir)
+	}, nil
+}
+
+// dynamicStartupFlowClient returns a flow client with secure client connection to download protocol
+// snapshots from the given access node.
+func dynamicStartupFlowClient(address string, publicKey string) (*client.Client, error) {
+	config, err := common.NewFlowClientConfig(address, publicKey, flow.ZeroID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flow client config for node dynamic startup pre-init: %w", err)
+	}
+
+	flowClient, err := common.FlowClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flow client for node dynamic startup pre-init: %w", err)
+	}
+	return flowClient, nil
+}
+
+// DynamicStartupQuorum returns the number of access nodes which must agree on the snapshot during
+// dynamic startup. A configured quorum of 0 requires a majority of the access nodes.
+func DynamicStartupQuorum(quorum uint, accessNodes int) uint {
+	if quorum == 0 {
+		return uint(accessNodes/2 + 1)
+	}
+	return quorum
+}
+
 // validateDynamicStartEpochFlags parse the start epoch flag and return the uin64 value,
 // if epoch = current return the current epoch counter
 func validateDynamicStartEpochFlags(ctx context.Context, getSnapshot GetProtocolSnapshot, flagEpoch string) (uint64, error) {
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	utilsio "github.com/onflow/flow-go/utils/io"
+)
+
+// ErrSnapshotQuorumDisagreement is returned when the access nodes queried during dynamic startup
+// return conflicting snapshots, so that no quorum of them agrees on the snapshot to start from.
+var ErrSnapshotQuorumDisagreement = errors.New("access nodes disagree on protocol snapshot")
+
+// FilenameSnapshotQuorumReport is the name of the report written when the queried access nodes disagree.
+const FilenameSnapshotQuorumReport = "quorum-report-%d.json" // %d will be replaced by the unix time
+
+// SnapshotSource is an access node to retrieve protocol snapshots from during dynamic startup.
+type SnapshotSource struct {
+	Address     string
+	PublicKey   string
+	GetSnapshot func(ctx context.Context) (*inmem.Snapshot, error)
+}
+
+// SnapshotProvenance describes where and when a cached snapshot was retrieved.
+type SnapshotProvenance struct {
+	Address   string
+	PublicKey string
+	FetchedAt time.Time
+}
+
+// CachedSnapshot is the encoding format of a snapshot cached during dynamic startup.
+type CachedSnapshot struct {
+	Provenance SnapshotProvenance
+	Snapshot   inmem.EncodableSnapshot
+}
+
+// SnapshotSummary summarizes the snapshot returned by an access node for the quorum report.
+type SnapshotSummary struct {
+	Address          string
+	Error            string `json:",omitempty"`
+	CacheFile        string `json:",omitempty"`
+	HeadID           flow.Identifier
+	HeadHeight       uint64
+	SporkID          flow.Identifier
+	EpochCounter     uint64
+	EpochPhase       string
+	EpochFingerprint string
+}
+
+// SnapshotDisagreement lists the reasons why the snapshots of two access nodes are conflicting.
+type SnapshotDisagreement struct {
+	Lower   string // address of the access node with the lower head
+	Higher  string // address of the access node with the higher head
+	Reasons []string
+}
+
+// SnapshotQuorumReport is written when the access nodes queried during dynamic startup disagree.
+type SnapshotQuorumReport struct {
+	Quorum        uint
+	Snapshots     []SnapshotSummary
+	Disagreements []SnapshotDisagreement
+}
+
+// fetchedSnapshot is the response of a single access node.
+type fetchedSnapshot struct {
+	source   SnapshotSource
+	snapshot inmem.EncodableSnapshot
+	summary  SnapshotSummary
+	err      error
+}
+
+// GetSnapshotWithQuorum retrieves the latest finalized snapshot from each of the given access nodes
+// and returns the snapshot with the highest head which at least quorum access nodes agree on.
+//
+// An access node agrees with a snapshot if its own snapshot has the same spork parameters and epoch
+// data, and includes the head of the snapshot in its sealing segment. Access nodes may be at
+// different heights, so the returned snapshot is not necessarily the latest one of all access nodes.
+// The latest snapshot retrieved from each access node is cached in cacheDir together with its
+// provenance, replacing the snapshot cached by a previous call. If the responding access nodes
+// disagree, such that no snapshot reaches the quorum, a report listing the differences is written
+// to cacheDir.
+// Expected errors during normal operation:
+//   - ErrSnapshotQuorumDisagreement if the snapshots of the access nodes are conflicting
+//   - generic error if fewer than quorum access nodes responded, or their heads are too far apart
+//     to be compared, which may be retried
+func GetSnapshotWithQuorum(
+	ctx context.Context,
+	log zerolog.Logger,
+	sources []SnapshotSource,
+	quorum uint,
+	cacheDir string,
+) (*inmem.Snapshot, error) {
+	if quorum == 0 || quorum > uint(len(sources)) {
+		return nil, fmt.Errorf("invalid quorum %d for %d access nodes", quorum, len(sources))
+	}
+
+	fetched := fetchSnapshots(ctx, sources)
+
+	var responses []*fetchedSnapshot
+	for _, f := range fetched {
+		if f.err != nil {
+			log.Warn().Err(f.err).Str("access_address", f.source.Address).Msg("could not get protocol snapshot")
+			continue
+		}
+		cacheFile, err := cacheSnapshot(cacheDir, f)
+		if err != nil {
+			return nil, err
+		}
+		f.summary.CacheFile = cacheFile
+		responses = append(responses, f)
+	}
+	if uint(len(responses)) < quorum {
+		return nil, fmt.Errorf("only %d of %d access nodes returned a protocol snapshot, quorum is %d", len(responses), len(sources), quorum)
+	}
+
+	// select the highest snapshot which at least quorum access nodes at the same or higher height agree on
+	var selected *fetchedSnapshot
+	var disagreements []SnapshotDisagreement
+	for _, lower := range responses {
+		agreeing := uint(0)
+		for _, higher := range responses {
+			if higher.summary.HeadHeight < lower.summary.HeadHeight {
+				continue
+			}
+			reasons, comparable := snapshotDisagreements(lower, higher)
+			if !comparable {
+				// the higher access node is too far ahead to verify the head of the lower one
+				continue
+			}
+			if len(reasons) == 0 {
+				agreeing++
+				continue
+			}
+			disagreements = append(disagreements, SnapshotDisagreement{
+				Lower:   lower.source.Address,
+				Higher:  higher.source.Address,
+				Reasons: reasons,
+			})
+		}
+		if agreeing >= quorum && (selected == nil || lower.summary.HeadHeight > selected.summary.HeadHeight) {
+			selected = lower
+		}
+	}
+
+	if selected == nil && len(disagreements) == 0 {
+		return nil, fmt.Errorf("no snapshot is agreed on by %d access nodes, the heads of the access nodes are too far apart to be compared", quorum)
+	}
+	if selected == nil {
+		report := SnapshotQuorumReport{
+			Quorum:        quorum,
+			Disagreements: disagreements,
+		}
+		for _, f := range fetched {
+			report.Snapshots = append(report.Snapshots, f.summary)
+		}
+		reportPath := filepath.Join(cacheDir, fmt.Sprintf(FilenameSnapshotQuorumReport, time.Now().Unix()))
+		err := utilsio.WriteJSON(reportPath, report)
+		if err != nil {
+			return nil, fmt.Errorf("could not write quorum report: %w", err)
+		}
+		return nil, fmt.Errorf("no snapshot is agreed on by %d access nodes, see report %s: %w", quorum, reportPath, ErrSnapshotQuorumDisagreement)
+	}
+
+	for _, disagreement := range disagreements {
+		log.Warn().
+			Str("lower_access_address", disagreement.Lower).
+			Str("higher_access_address", disagreement.Higher).
+			Strs("reasons", disagreement.Reasons).
+			Msg("access nodes disagree on protocol snapshot")
+	}
+	log.Info().
+		Str("access_address", selected.source.Address).
+		Hex("head_id", selected.summary.HeadID[:]).
+		Uint64("head_height", selected.summary.HeadHeight).
+		Str("cache_file", selected.summary.CacheFile).
+		Msg("quorum of access nodes agrees on protocol snapshot")
+
+	return inmem.SnapshotFromEncodable(selected.snapshot), nil
+}
+
+// fetchSnapshots retrieves the latest snapshot from all sources concurrently.
+func fetchSnapshots(ctx context.Context, sources []SnapshotSource) []*fetchedSnapshot {
+	fetched := make([]*fetchedSnapshot, len(sources))
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source SnapshotSource) {
+			defer wg.Done()
+
+			f := &fetchedSnapshot{
+				source:  source,
+				summary: SnapshotSummary{Address: source.Address},
+			}
+			fetched[i] = f
+
+			snapshot, err := source.GetSnapshot(ctx)
+			if err == nil {
+				f.snapshot = snapshot.Encodable()
+				err = summarizeSnapshot(&f.summary, f.snapshot)
+			}
+			if err != nil {
+				f.err = err
+				f.summary.Error = err.Error()
+			}
+		}(i, source)
+	}
+	wg.Wait()
+
+	return fetched
+}
+
+// summarizeSnapshot fills the summary of the given snapshot.
+// No errors are expected during normal operation.
+func summarizeSnapshot(summary *SnapshotSummary, snapshot inmem.EncodableSnapshot) error {
+	if snapshot.Head == nil || snapshot.SealingSegment == nil {
+		return fmt.Errorf("snapshot is incomplete")
+	}
+	fingerprint, err := epochFingerprint(snapshot.Epochs.Current)
+	if err != nil {
+		return err
+	}
+
+	summary.HeadID = snapshot.Head.ID()
+	summary.HeadHeight = snapshot.Head.Height
+	summary.SporkID = snapshot.Params.SporkID
+	summary.EpochCounter = snapshot.Epochs.Current.Counter
+	summary.EpochPhase = snapshot.Phase.String()
+	summary.EpochFingerprint = fingerprint
+	return nil
+}
+
+// epochFingerprint returns a hash of the given epoch, which is equal for all snapshots sharing
+// the same epoch data.
+// No errors are expected during normal operation.
+func epochFingerprint(epoch inmem.EncodableEpoch) (string, error) {
+	data, err := json.Marshal(epoch)
+	if err != nil {
+		return "", fmt.Errorf("could not encode epoch %d: %w", epoch.Counter, err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// snapshotDisagreements returns the reasons why the snapshot of the higher access node does not
+// agree with the snapshot of the lower access node, which has a head at the same or a lower height.
+// The snapshots are not comparable if the head of the lower snapshot is below the sealing segment
+// of the higher snapshot. In this case, the access nodes neither agree nor conflict.
+func snapshotDisagreements(lower, higher *fetchedSnapshot) (reasons []string, comparable bool) {
+	if lower.snapshot.Params != higher.snapshot.Params {
+		reasons = append(reasons, fmt.Sprintf("spork parameters differ: %+v != %+v", lower.snapshot.Params, higher.snapshot.Params))
+	}
+
+	// the higher access node may already be in the next epoch, in which case the current epoch of
+	// the lower access node is the previous epoch of the higher access node
+	higherEpoch := higher.snapshot.Epochs.Current
+	if higherEpoch.Counter == lower.summary.EpochCounter+1 && higher.snapshot.Epochs.Previous != nil {
+		higherEpoch = *higher.snapshot.Epochs.Previous
+	}
+	if higherEpoch.Counter != lower.summary.EpochCounter {
+		reasons = append(reasons, fmt.Sprintf("current epoch differs: %d != %d", lower.summary.EpochCounter, higher.summary.EpochCounter))
+	} else if fingerprint, err := epochFingerprint(higherEpoch); err != nil {
+		reasons = append(reasons, err.Error())
+	} else if fingerprint != lower.summary.EpochFingerprint {
+		reasons = append(reasons, fmt.Sprintf("data of epoch %d differs: fingerprint %s != %s",
+			lower.summary.EpochCounter, lower.summary.EpochFingerprint, fingerprint))
+	}
+
+	// the head of the lower snapshot must be finalized in the higher snapshot
+	head := lower.snapshot.Head
+	for _, block := range higher.snapshot.SealingSegment.AllBlocks() {
+		if block.Header.Height != head.Height {
+			continue
+		}
+		if block.ID() != head.ID() {
+			reasons = append(reasons, fmt.Sprintf("finalized block at height %d differs: %x != %x", head.Height, head.ID(), block.ID()))
+		}
+		return reasons, true
+	}
+
+	// only conflicting spork or epoch data can be detected without an overlap of the snapshots
+	return reasons, len(reasons) > 0
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// cacheSnapshot writes the fetched snapshot with its provenance to the cache directory, and
+// returns the path of the cache file. Only the latest snapshot of each access node is kept, as
+// dynamic startup polls the access nodes until the target epoch phase is reached.
+// No errors are expected during normal operation.
+func cacheSnapshot(cacheDir string, f *fetchedSnapshot) (string, error) {
+	name := fmt.Sprintf("snapshot-%s.json", unsafeFilenameChars.ReplaceAllString(f.source.Address, "_"))
+	path := filepath.Join(cacheDir, name)
+
+	err := utilsio.WriteJSON(path, CachedSnapshot{
+		Provenance: SnapshotProvenance{
+			Address:   f.source.Address,
+			PublicKey: f.source.PublicKey,
+			FetchedAt: time.Now().UTC(),
+		},
+		Snapshot: f.snapshot,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not cache snapshot of access node %s: %w", f.source.Address, err)
+	}
+	return path, nil
+}
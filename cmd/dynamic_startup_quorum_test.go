@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// quorumSnapshotFixture returns a snapshot with the given block as head, whose sealing segment holds
+// the head and the five blocks before it.
+func quorumSnapshotFixture(chain []*flow.Block, head int, epochCounter uint64) *inmem.Snapshot {
+	return inmem.SnapshotFromEncodable(inmem.EncodableSnapshot{
+		Head: chain[head].Header,
+		SealingSegment: &flow.SealingSegment{
+			Blocks: chain[head-5 : head+1],
+		},
+		Epochs: inmem.EncodableEpochs{
+			Current: inmem.EncodableEpoch{Counter: epochCounter, FirstView: 100, FinalView: 1000},
+		},
+		Params: inmem.EncodableParams{ChainID: flow.Localnet},
+	})
+}
+
+func snapshotSourceFixture(address string, snapshot *inmem.Snapshot, err error) SnapshotSource {
+	return SnapshotSource{
+		Address:   address,
+		PublicKey: unittest.NetworkingPrivKeyFixture().PublicKey().String(),
+		GetSnapshot: func(context.Context) (*inmem.Snapshot, error) {
+			return snapshot, err
+		},
+	}
+}
+
+func TestGetSnapshotWithQuorum(t *testing.T) {
+	chain := unittest.ChainFixtureFrom(20, unittest.BlockHeaderFixture())
+	fork := unittest.ChainFixtureFrom(20, chain[9].Header)
+
+	t.Run("access nodes at different heights agree", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		sources := []SnapshotSource{
+			snapshotSourceFixture("access-001:9000", quorumSnapshotFixture(chain, 10, 1), nil),
+			snapshotSourceFixture("access-002:9000", quorumSnapshotFixture(chain, 12, 1), nil),
+			snapshotSourceFixture("access-003:9000", quorumSnapshotFixture(chain, 11, 1), nil),
+		}
+
+		// the highest head agreed on by all access nodes is the lowest head
+		snapshot, err := GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 3, cacheDir)
+		require.NoError(t, err)
+		head, err := snapshot.Head()
+		require.NoError(t, err)
+		assert.Equal(t, chain[10].ID(), head.ID())
+
+		// the highest head agreed on by two access nodes
+		snapshot, err = GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 2, cacheDir)
+		require.NoError(t, err)
+		head, err = snapshot.Head()
+		require.NoError(t, err)
+		assert.Equal(t, chain[11].ID(), head.ID())
+
+		// a later poll replaces the cached snapshot of an access node which progressed
+		sources[0] = snapshotSourceFixture("access-001:9000", quorumSnapshotFixture(chain, 13, 1), nil)
+		_, err = GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 2, cacheDir)
+		require.NoError(t, err)
+
+		// only the latest snapshot of each access node is cached, with its provenance
+		cached, err := filepath.Glob(filepath.Join(cacheDir, "snapshot-*.json"))
+		require.NoError(t, err)
+		assert.Len(t, cached, len(sources))
+
+		data, err := os.ReadFile(filepath.Join(cacheDir, "snapshot-access-002_9000.json"))
+		require.NoError(t, err)
+		var snapshotFile CachedSnapshot
+		require.NoError(t, json.Unmarshal(data, &snapshotFile))
+		assert.Equal(t, "access-002:9000", snapshotFile.Provenance.Address)
+		assert.Equal(t, sources[1].PublicKey, snapshotFile.Provenance.PublicKey)
+		assert.Equal(t, chain[12].ID(), snapshotFile.Snapshot.Head.ID())
+	})
+
+	t.Run("access node on a fork", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		sources := []SnapshotSource{
+			snapshotSourceFixture("access-001:9000", quorumSnapshotFixture(chain, 12, 1), nil),
+			snapshotSourceFixture("access-002:9000", quorumSnapshotFixture(chain, 12, 1), nil),
+			snapshotSourceFixture("access-003:9000", quorumSnapshotFixture(append(chain[:10:10], fork...), 12, 1), nil),
+		}
+
+		// a majority agrees on the head
+		snapshot, err := GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 2, cacheDir)
+		require.NoError(t, err)
+		head, err := snapshot.Head()
+		require.NoError(t, err)
+		assert.Equal(t, chain[12].ID(), head.ID())
+
+		// refuse to start if not all access nodes agree
+		_, err = GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 3, cacheDir)
+		require.ErrorIs(t, err, ErrSnapshotQuorumDisagreement)
+
+		reports, err := filepath.Glob(filepath.Join(cacheDir, "quorum-report-*.json"))
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		data, err := os.ReadFile(reports[0])
+		require.NoError(t, err)
+		var report SnapshotQuorumReport
+		require.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, uint(3), report.Quorum)
+		assert.Len(t, report.Snapshots, 3)
+		require.NotEmpty(t, report.Disagreements)
+		assert.Contains(t, report.Disagreements[0].Reasons[0], "finalized block at height")
+	})
+
+	t.Run("access nodes disagree on epoch", func(t *testing.T) {
+		sources := []SnapshotSource{
+			snapshotSourceFixture("access-001:9000", quorumSnapshotFixture(chain, 12, 1), nil),
+			snapshotSourceFixture("access-002:9000", quorumSnapshotFixture(chain, 12, 2), nil),
+		}
+
+		_, err := GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 2, t.TempDir())
+		require.ErrorIs(t, err, ErrSnapshotQuorumDisagreement)
+	})
+
+	t.Run("access node already in the next epoch", func(t *testing.T) {
+		next := quorumSnapshotFixture(chain, 12, 2).Encodable()
+		previous := quorumSnapshotFixture(chain, 10, 1).Encodable().Epochs.Current
+		next.Epochs.Previous = &previous
+		sources := []SnapshotSource{
+			snapshotSourceFixture("access-001:9000", quorumSnapshotFixture(chain, 10, 1), nil),
+			snapshotSourceFixture("access-002:9000", inmem.SnapshotFromEncodable(next), nil),
+		}
+
+		snapshot, err := GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 2, t.TempDir())
+		require.NoError(t, err)
+		head, err := snapshot.Head()
+		require.NoError(t, err)
+		assert.Equal(t, chain[10].ID(), head.ID())
+	})
+
+	t.Run("access nodes too far apart to be compared", func(t *testing.T) {
+		sources := []SnapshotSource{
+			snapshotSourceFixture("access-001:9000", quorumSnapshotFixture(chain, 6, 1), nil),
+			snapshotSourceFixture("access-002:9000", quorumSnapshotFixture(chain, 18, 1), nil),
+		}
+
+		// the access nodes do not conflict, so startup may be retried
+		_, err := GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 2, t.TempDir())
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrSnapshotQuorumDisagreement)
+	})
+
+	t.Run("too few access nodes respond", func(t *testing.T) {
+		sources := []SnapshotSource{
+			snapshotSourceFixture("access-001:9000", quorumSnapshotFixture(chain, 12, 1), nil),
+			snapshotSourceFixture("access-002:9000", nil, fmt.Errorf("unavailable")),
+		}
+
+		_, err := GetSnapshotWithQuorum(context.Background(), zerolog.Nop(), sources, 2, t.TempDir())
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrSnapshotQuorumDisagreement)
+	})
+}
+
+// TestGetSnapshotAtEpochAndPhase_QuorumDisagreement ensures that disagreeing access nodes are not retried
+func TestGetSnapshotAtEpochAndPhase_QuorumDisagreement(t *testing.T) {
+	calls := 0
+	getSnapshot := func(context.Context) (protocol.Snapshot, error) {
+		calls++
+		return nil, fmt.Errorf("no quorum: %w", ErrSnapshotQuorumDisagreement)
+	}
+
+	_, err := GetSnapshotAtEpochAndPhase(context.Background(), unittest.Logger(), 1, flow.EpochPhaseSetup, time.Millisecond, getSnapshot)
+	require.ErrorIs(t, err, ErrSnapshotQuorumDisagreement)
+	assert.Equal(t, 1, calls)
+}
+
+// TestValidateDynamicStartupQuorumFlags tests validation of the dynamic-startup-* CLI flags of the quorum mode
+func TestValidateDynamicStartupQuorumFlags(t *testing.T) {
+	pub, address, phase, _ := dynamicJoinFlagsFixture()
+
+	t.Run("should return nil if all flags are valid", func(t *testing.T) {
+		err := ValidateDynamicStartupQuorumFlags([]string{pub, pub}, []string{address, "access_2:9001"}, 2, phase)
+		require.NoError(t, err)
+	})
+
+	t.Run("should return error if public keys do not match addresses", func(t *testing.T) {
+		err := ValidateDynamicStartupQuorumFlags([]string{pub}, []string{address, "access_2:9001"}, 0, phase)
+		require.ErrorContains(t, err, "invalid flag --dynamic-startup-access-publickeys")
+	})
+
+	t.Run("should return error if quorum exceeds access nodes", func(t *testing.T) {
+		err := ValidateDynamicStartupQuorumFlags([]string{pub, pub}, []string{address, "access_2:9001"}, 3, phase)
+		require.ErrorContains(t, err, "invalid flag --dynamic-startup-quorum")
+	})
+
+	t.Run("should default to a majority quorum", func(t *testing.T) {
+		assert.Equal(t, uint(2), DynamicStartupQuorum(0, 3))
+		assert.Equal(t, uint(3), DynamicStartupQuorum(0, 4))
+		assert.Equal(t, uint(1), DynamicStartupQuorum(1, 4))
+	})
+}
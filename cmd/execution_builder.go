@@ -326,9 +326,9 @@ func (exeNode *ExecutionNode) LoadBlobService(
 			bitswap.WithPeerBlockRequestFilter(
 				blob.AuthorizedRequester(allowedANs, exeNode.builder.IdentityProvider, exeNode.builder.Logger),
 			),
-			bitswap.WithTracer(
-				blob.NewTracer(node.Logger.With().Str("blob_service", channels.ExecutionDataService.String()).Logger()),
-			),
+		),
+		blob.WithTracer(
+			blob.NewTracer(node.Logger.With().Str("blob_service", channels.ExecutionDataService.String()).Logger()),
 		),
 	}
 
@@ -858,6 +858,7 @@ func (exeNode *ExecutionNode) LoadIngestionEngine(
 		// consistency of collection can be checked by checking hash, and hash comes from trusted source (blocks from consensus follower)
 		// hence we not need to check origin
 		requester.WithValidateStaking(false),
+		requester.WithRequestService(node.CollectionRequestServiceEnabled),
 	)
 
 	if err != nil {
@@ -145,9 +145,15 @@ type BaseConfig struct {
 	NodeRole                    string
 	DynamicStartupANAddress     string
 	DynamicStartupANPubkey      string
+	DynamicStartupANAddresses   []string
+	DynamicStartupANPubkeys     []string
+	DynamicStartupQuorum        uint
 	DynamicStartupEpochPhase    string
 	DynamicStartupEpoch         string
 	DynamicStartupSleepInterval time.Duration
+	BootstrapPeerAddress        string
+	BootstrapPeerPubkey         string
+	BootstrapPeerBlockID        string
 	datadir                     string
 	secretsdir                  string
 	secretsDBEnabled            bool
@@ -162,6 +168,7 @@ type BaseConfig struct {
 	MetricsEnabled              bool
 	guaranteesCacheSize         uint
 	receiptsCacheSize           uint
+	prefixSizeReportInterval    time.Duration
 	db                          *badger.DB
 	HeroCacheMetricsEnable      bool
 	SyncCoreConfig              chainsync.Config
@@ -170,6 +177,9 @@ type BaseConfig struct {
 	// ComplianceConfig configures either the compliance engine (consensus nodes)
 	// or the follower engine (all other node roles)
 	ComplianceConfig compliance.Config
+	// CollectionRequestServiceEnabled enables serving and sending collection requests through the request service of
+	// the network, rather than by unicast messages.
+	CollectionRequestServiceEnabled bool
 
 	// FlowConfig Flow configuration.
 	FlowConfig config.FlowConfig
@@ -215,6 +225,8 @@ type NodeConfig struct {
 
 	// root state information
 	RootSnapshot protocol.Snapshot
+	// finalized blocks following the root snapshot, downloaded when bootstrapping from a peer
+	RootFollowingBlocks []*flow.Block
 	// excerpt of root snapshot and latest finalized snapshot, when we boot up
 	StateExcerptAtBoot
 
@@ -272,6 +284,8 @@ func DefaultBaseConfig() *BaseConfig {
 		receiptsCacheSize:   bstorage.DefaultCacheSize,
 		guaranteesCacheSize: bstorage.DefaultCacheSize,
 
+		prefixSizeReportInterval: bstorage.DefaultPrefixSizeReportInterval,
+
 		profilerConfig: profiler.ProfilerConfig{
 			Enabled:         false,
 			UploaderEnabled: false,
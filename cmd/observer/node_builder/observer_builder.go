@@ -264,9 +264,12 @@ func (builder *ObserverServiceBuilder) buildFollowerState() *ObserverServiceBuil
 			node.Storage.Payloads,
 			blocktimer.DefaultBlockTimer,
 		)
+		if err != nil {
+			return err
+		}
 		builder.FollowerState = followerState
 
-		return err
+		return cmd.ExtendRootFollowingBlocks(node, followerState)
 	})
 
 	return builder
@@ -502,6 +505,8 @@ func (builder *ObserverServiceBuilder) initNetwork(nodeID module.Local,
 			AlspMetrics:             builder.Metrics.Network,
 			HeroCacheMetricsFactory: builder.HeroCacheMetricsFactory(),
 			NetworkType:             network.PublicNetwork,
+			SpamRecordStore:         builder.AlspSpamRecordStore(network.PublicNetwork),
+			PersistenceInterval:     builder.FlowConfig.NetworkConfig.AlspConfig.SpamRecordPersistenceInterval,
 		},
 	})
 	if err != nil {
@@ -663,6 +668,7 @@ func (builder *ObserverServiceBuilder) Initialize() error {
 	}
 
 	builder.PreInit(builder.initObserverLocal())
+	builder.PreInit(cmd.PeerBootstrapPreInit)
 
 	return nil
 }
@@ -1039,6 +1045,11 @@ func (builder *ObserverServiceBuilder) initMiddleware(nodeID flow.Identifier,
 		middleware.WithMessageValidators(validators...), // use default identifier provider
 	)
 	builder.Middleware = mw
+
+	// nodes disallow-listed by the operator before a restart are disallow-listed again.
+	if disallowListWrapper, ok := builder.IdentityProvider.(*cache.NodeDisallowListingWrapper); ok {
+		disallowListWrapper.NotifyDisallowList()
+	}
 	return builder.Middleware
 }
 
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/signature"
+	"github.com/onflow/flow-go/consensus/hotstuff/validator"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/segmentstream"
+	"github.com/onflow/flow-go/state/protocol"
+	badgerstate "github.com/onflow/flow-go/state/protocol/badger"
+	"github.com/onflow/flow-go/utils/grpcutils"
+	utilsio "github.com/onflow/flow-go/utils/io"
+)
+
+const bootstrapPeerRequestTimeout = time.Minute
+
+// PeerBootstrapPreInit is the pre-init func that bootstraps a node from a trusted peer, if the
+// node is not bootstrapped yet and --bootstrap-peer-address is set. It streams the root snapshot
+// at a finalized block of the peer, and the finalized blocks following it, in verified chunks.
+// The chunks are persisted in the bootstrap directory, so that an interrupted download is resumed
+// when the node is restarted. Node builders registering this pre-init func must add the following
+// blocks to their follower state with ExtendRootFollowingBlocks once it is constructed.
+//
+// As the root snapshot is taken from the peer, the peer must be trusted in the same way as a root
+// snapshot file obtained out of band. As for dynamic startup, the peer is authenticated by pinning
+// its networking public key, set with --bootstrap-peer-publickey, in the TLS connection.
+func PeerBootstrapPreInit(nodeConfig *NodeConfig) error {
+	if nodeConfig.BootstrapPeerAddress == "" {
+		return nil
+	}
+
+	tlsConfig, err := bootstrapPeerTLSConfig(nodeConfig.BootstrapPeerAddress, nodeConfig.BootstrapPeerPubkey)
+	if err != nil {
+		return err
+	}
+
+	log := nodeConfig.Logger.With().
+		Str("component", "peer-bootstrap").
+		Str("bootstrap_peer_address", nodeConfig.BootstrapPeerAddress).
+		Logger()
+
+	// skip bootstrapping from a peer if the protocol state is bootstrapped
+	isBootstrapped, err := badgerstate.IsBootstrapped(nodeConfig.DB)
+	if err != nil {
+		return fmt.Errorf("could not check if state is boostrapped: %w", err)
+	}
+	if isBootstrapped {
+		log.Info().Msg("protocol state already bootstrapped, skipping bootstrapping from peer")
+		return nil
+	}
+
+	// skip bootstrapping from a peer if a root snapshot file is specified - this takes priority
+	rootSnapshotPath := filepath.Join(nodeConfig.BootstrapDir, bootstrap.PathRootProtocolStateSnapshot)
+	if utilsio.FileExists(rootSnapshotPath) {
+		log.Info().
+			Str("root_snapshot_path", rootSnapshotPath).
+			Msg("protocol state is not bootstrapped, will bootstrap using configured root snapshot file, skipping bootstrapping from peer")
+		return nil
+	}
+
+	blockID := flow.ZeroID
+	if nodeConfig.BootstrapPeerBlockID != "" {
+		blockID, err = flow.HexStringToIdentifier(nodeConfig.BootstrapPeerBlockID)
+		if err != nil {
+			return fmt.Errorf("invalid flag --bootstrap-peer-block-id: %w", err)
+		}
+	}
+
+	downloader := segmentstream.NewDownloader(
+		log,
+		segmentstream.DefaultDownloaderConfig(),
+		segmentstream.NewHTTPSource(nodeConfig.BootstrapPeerAddress, bootstrapPeerRequestTimeout, tlsConfig),
+		filepath.Join(nodeConfig.BootstrapDir, bootstrap.DirnameSealingSegmentDownload),
+	)
+	snapshot, following, err := downloader.Download(context.Background(), blockID)
+	if err != nil {
+		return fmt.Errorf("failed to download root snapshot from bootstrap peer: %w", err)
+	}
+
+	// set the root snapshot in the config - we will use this later to bootstrap
+	nodeConfig.RootSnapshot = snapshot
+	nodeConfig.RootFollowingBlocks = following
+	return nil
+}
+
+// bootstrapPeerTLSConfig returns the TLS config authenticating the bootstrap peer at the given address
+// with the given networking public key.
+// No errors are expected during normal operation.
+func bootstrapPeerTLSConfig(address string, publicKeyHex string) (*tls.Config, error) {
+	if !strings.HasPrefix(address, "https://") {
+		return nil, fmt.Errorf("invalid flag --bootstrap-peer-address: must be an https address")
+	}
+	if publicKeyHex == "" {
+		return nil, fmt.Errorf("missing required flag --bootstrap-peer-publickey when using --bootstrap-peer-address")
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(publicKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid flag --bootstrap-peer-publickey: %w", err)
+	}
+	publicKey, err := crypto.DecodePublicKey(crypto.ECDSAP256, b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flag --bootstrap-peer-publickey: %w", err)
+	}
+
+	tlsConfig, err := grpcutils.DefaultClientTLSConfig(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not create TLS config for bootstrap peer: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+// ExtendRootFollowingBlocks adds the finalized blocks following the root snapshot, which were
+// downloaded when bootstrapping from a peer, to the given follower state. This saves the node from
+// synchronizing them again once it has started. The quorum certificates of the blocks are validated
+// with the consensus committee of the bootstrapped state before the blocks are added.
+// No errors are expected during normal operation.
+func ExtendRootFollowingBlocks(nodeConfig *NodeConfig, state protocol.FollowerState) error {
+	if len(nodeConfig.RootFollowingBlocks) == 0 {
+		return nil
+	}
+
+	committee, err := committees.NewConsensusCommittee(state, nodeConfig.Me.NodeID())
+	if err != nil {
+		return fmt.Errorf("could not create consensus committee: %w", err)
+	}
+	verifier := verification.NewCombinedVerifier(committee, signature.NewConsensusSigDataPacker(committee))
+	qcValidator := validator.New(committee, verifier)
+
+	err = segmentstream.ExtendFollowing(context.Background(), state, qcValidator, nodeConfig.RootFollowingBlocks)
+	if err != nil {
+		return fmt.Errorf("could not add blocks following the root snapshot: %w", err)
+	}
+	nodeConfig.Logger.Info().
+		Int("blocks", len(nodeConfig.RootFollowingBlocks)-1).
+		Msg("added blocks following the root snapshot to protocol state")
+
+	// the blocks are not needed anymore
+	nodeConfig.RootFollowingBlocks = nil
+	return nil
+}
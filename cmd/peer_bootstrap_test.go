@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestBootstrapPeerTLSConfig(t *testing.T) {
+	publicKey := unittest.NetworkingPrivKeyFixture().PublicKey().String()
+
+	t.Run("valid", func(t *testing.T) {
+		tlsConfig, err := bootstrapPeerTLSConfig("https://access_1:8071", publicKey)
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig.VerifyPeerCertificate)
+	})
+
+	t.Run("plain http address", func(t *testing.T) {
+		_, err := bootstrapPeerTLSConfig("http://access_1:8070", publicKey)
+		require.Error(t, err)
+	})
+
+	t.Run("missing public key", func(t *testing.T) {
+		_, err := bootstrapPeerTLSConfig("https://access_1:8071", "")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		_, err := bootstrapPeerTLSConfig("https://access_1:8071", "0xabcd")
+		require.Error(t, err)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -44,8 +45,12 @@ import (
 	"github.com/onflow/flow-go/module/updatable_configs"
 	"github.com/onflow/flow-go/module/util"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	alspmgr "github.com/onflow/flow-go/network/alsp/manager"
 	netcache "github.com/onflow/flow-go/network/cache"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/compressor"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/cache"
 	"github.com/onflow/flow-go/network/p2p/conduit"
@@ -59,6 +64,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
 	"github.com/onflow/flow-go/network/p2p/unicast/ratelimit"
 	"github.com/onflow/flow-go/network/p2p/utils/ratelimiter"
+	"github.com/onflow/flow-go/network/queue"
 	"github.com/onflow/flow-go/network/topology"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
@@ -84,6 +90,7 @@ type Metrics struct {
 	Cache          module.CacheMetrics
 	Mempool        module.MempoolMetrics
 	CleanCollector module.CleanerMetrics
+	DatabaseSize   module.DatabaseSizeMetrics
 	Bitswap        module.BitswapMetrics
 }
 
@@ -123,6 +130,7 @@ type FlowNodeBuilder struct {
 	extraFlagCheck           func() error
 	adminCommandBootstrapper *admin.CommandRunnerBootstrapper
 	adminCommands            map[string]func(config *NodeConfig) commands.AdminCommand
+	dbCompactor              *bstorage.Compactor
 	componentBuilder         component.ComponentManagerBuilder
 }
 
@@ -174,6 +182,8 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 
 	fnb.flags.UintVar(&fnb.BaseConfig.guaranteesCacheSize, "guarantees-cache-size", bstorage.DefaultCacheSize, "collection guarantees cache size")
 	fnb.flags.UintVar(&fnb.BaseConfig.receiptsCacheSize, "receipts-cache-size", bstorage.DefaultCacheSize, "receipts cache size")
+	fnb.flags.DurationVar(&fnb.BaseConfig.prefixSizeReportInterval, "db-prefix-size-report-interval", defaultConfig.prefixSizeReportInterval,
+		"interval in which the disk usage of each key prefix of the protocol database is reported as metrics, 0 to disable")
 
 	// dynamic node startup flags
 	fnb.flags.StringVar(&fnb.BaseConfig.DynamicStartupANPubkey, "dynamic-startup-access-publickey", "", "the public key of the trusted secure access node to connect to when using dynamic-startup, this access node must be staked")
@@ -181,9 +191,18 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.StringVar(&fnb.BaseConfig.DynamicStartupEpochPhase, "dynamic-startup-epoch-phase", "EpochPhaseSetup", "the target epoch phase for dynamic startup <EpochPhaseStaking|EpochPhaseSetup|EpochPhaseCommitted")
 	fnb.flags.StringVar(&fnb.BaseConfig.DynamicStartupEpoch, "dynamic-startup-epoch", "current", "the target epoch for dynamic-startup, use \"current\" to start node in the current epoch")
 	fnb.flags.DurationVar(&fnb.BaseConfig.DynamicStartupSleepInterval, "dynamic-startup-sleep-interval", time.Minute, "the interval in which the node will check if it can start")
+	fnb.flags.StringSliceVar(&fnb.BaseConfig.DynamicStartupANAddresses, "dynamic-startup-access-addresses", []string{}, "the access addresses of multiple trusted secure access nodes to retrieve the snapshot from when using dynamic-startup, of which a quorum must agree on the snapshot; takes precedence over --dynamic-startup-access-address")
+	fnb.flags.StringSliceVar(&fnb.BaseConfig.DynamicStartupANPubkeys, "dynamic-startup-access-publickeys", []string{}, "the public keys of the access nodes in --dynamic-startup-access-addresses, in the same order")
+	fnb.flags.UintVar(&fnb.BaseConfig.DynamicStartupQuorum, "dynamic-startup-quorum", 0, "the number of access nodes in --dynamic-startup-access-addresses which must agree on the snapshot, 0 for a majority")
+
+	// bootstrapping from a peer
+	fnb.flags.StringVar(&fnb.BaseConfig.BootstrapPeerAddress, "bootstrap-peer-address", "", "the sealing segment server address (e.g. https://access-001:8071) of a trusted access node to stream the root protocol snapshot from, if the protocol state is not bootstrapped")
+	fnb.flags.StringVar(&fnb.BaseConfig.BootstrapPeerPubkey, "bootstrap-peer-publickey", "", "the public networking key of the access node set with --bootstrap-peer-address, in hex, which the node is authenticated with")
+	fnb.flags.StringVar(&fnb.BaseConfig.BootstrapPeerBlockID, "bootstrap-peer-block-id", "", "the ID of the finalized block to bootstrap from when using --bootstrap-peer-address, defaults to the latest finalized block of the peer")
 
 	fnb.flags.BoolVar(&fnb.BaseConfig.InsecureSecretsDB, "insecure-secrets-db", false, "allow the node to start up without an secrets DB encryption key")
 	fnb.flags.BoolVar(&fnb.BaseConfig.HeroCacheMetricsEnable, "herocache-metrics-collector", false, "enables herocache metrics collection")
+	fnb.flags.BoolVar(&fnb.BaseConfig.CollectionRequestServiceEnabled, "collection-request-service-enabled", false, "serve (collection nodes) and send (access and execution nodes) collection requests through the request service of the network, requests to collection nodes not serving the request service fall back to unicast messages")
 
 	// sync core flags
 	fnb.flags.DurationVar(&fnb.BaseConfig.SyncCoreConfig.RetryInterval, "sync-retry-interval", defaultConfig.SyncCoreConfig.RetryInterval, "the initial interval before we retry a sync request, uses exponential backoff")
@@ -411,6 +430,101 @@ func (fnb *FlowNodeBuilder) HeroCacheMetricsFactory() metrics.HeroCacheMetricsFa
 	return metrics.NewNoopHeroCacheMetricsFactory()
 }
 
+// networkTopology returns the topology of the staked network. Nodes are fully connected, unless a topology fanout
+// is configured, in which case the partial topology is used, re-seeded at every epoch transition.
+func (fnb *FlowNodeBuilder) networkTopology() (network.Topology, error) {
+	fanout := fnb.FlowConfig.NetworkConfig.TopologyFanout
+	if fanout == 0 {
+		return topology.NewFullyConnectedTopology(), nil
+	}
+	top, err := topology.NewPartialTopology(fnb.Logger, fnb.Me.NodeID(), fnb.State, topology.WithFanout(fanout))
+	if err != nil {
+		return nil, fmt.Errorf("could not create partial topology: %w", err)
+	}
+	fnb.ProtocolEvents.AddConsumer(top)
+	return top, nil
+}
+
+// channelCompression parses the compression codec of each channel from the network configuration. Cluster channels
+// are configured by their cluster channel prefix.
+func (fnb *FlowNodeBuilder) channelCompression() (map[channels.Channel]compressor.Codec, error) {
+	policies := make(map[channels.Channel]compressor.Codec)
+	for _, pair := range fnb.FlowConfig.NetworkConfig.ChannelCompression {
+		name, codecName, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid channel compression %s, expected channel=codec", pair)
+		}
+		channel := channels.Channel(strings.TrimSpace(name))
+		if !channels.ChannelExists(channel) && !channels.IsClusterChannel(channel) {
+			return nil, fmt.Errorf("unknown channel: %s", name)
+		}
+		codec, err := compressor.ParseCodec(strings.TrimSpace(codecName))
+		if err != nil {
+			return nil, fmt.Errorf("invalid compression of channel %s: %w", name, err)
+		}
+		policies[channel] = codec
+	}
+	return policies, nil
+}
+
+// inboundQueueConfig returns the configuration of the fair inbound message queue, parsing the weight of each channel
+// from the network configuration. Cluster channels are configured by their cluster channel prefix.
+func (fnb *FlowNodeBuilder) inboundQueueConfig() (*queue.FairMessageQueueConfig, error) {
+	cfg := queue.DefaultFairMessageQueueConfig()
+	cfg.ChannelCapacity = fnb.FlowConfig.NetworkConfig.InboundQueueChannelCapacity
+	for _, pair := range fnb.FlowConfig.NetworkConfig.InboundQueueChannelWeights {
+		name, weightStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid channel weight %s, expected channel=weight", pair)
+		}
+		channel := channels.Channel(strings.TrimSpace(name))
+		if !channels.ChannelExists(channel) && !channels.IsClusterChannel(channel) {
+			return nil, fmt.Errorf("unknown channel: %s", name)
+		}
+		weight, err := strconv.ParseUint(strings.TrimSpace(weightStr), 10, 32)
+		if err != nil || weight == 0 {
+			return nil, fmt.Errorf("invalid weight of channel %s: %s, expected a positive integer", name, weightStr)
+		}
+		cfg.ChannelWeights[channel] = uint(weight)
+	}
+	return cfg, nil
+}
+
+// networkCapturer returns the capturer of the messages sent and received on the capture channels of the network
+// configuration. Returns nil if capturing is disabled.
+func (fnb *FlowNodeBuilder) networkCapturer() (*capture.Capturer, error) {
+	path := fnb.FlowConfig.NetworkConfig.CaptureFile
+	if path == "" {
+		return nil, nil
+	}
+
+	var captured channels.ChannelList
+	for _, name := range fnb.FlowConfig.NetworkConfig.CaptureChannels {
+		channel := channels.Channel(strings.TrimSpace(name))
+		if !channels.ChannelExists(channel) && !channels.IsClusterChannel(channel) {
+			return nil, fmt.Errorf("unknown channel: %s", name)
+		}
+		captured = append(captured, channel)
+	}
+
+	capturer, err := capture.NewCapturer(fnb.Logger, path, fnb.Me.NodeID(), captured)
+	if err != nil {
+		return nil, err
+	}
+	fnb.ShutdownFunc(capturer.Close)
+	fnb.Logger.Warn().Str("path", path).Strs("channels", captured.String()).Msg("network message capture enabled")
+	return capturer, nil
+}
+
+// AlspSpamRecordStore returns the store the ALSP spam records of the network of the given type are persisted to.
+// Returns nil if persistence of the spam records is disabled.
+func (fnb *FlowNodeBuilder) AlspSpamRecordStore(networkType network.NetworkingType) alsp.SpamRecordStore {
+	if !fnb.FlowConfig.NetworkConfig.AlspConfig.PersistSpamRecords {
+		return nil
+	}
+	return alspmgr.NewBadgerSpamRecordStore(fnb.DB, networkType)
+}
+
 func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 	node *NodeConfig,
 	cf network.ConduitFactory,
@@ -430,6 +544,23 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 		middleware.WithPreferredUnicastProtocols(protocols.ToProtocolNames(fnb.FlowConfig.NetworkConfig.PreferredUnicastProtocols)),
 	)
 
+	channelCompression, err := fnb.channelCompression()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse channel compression: %w", err)
+	}
+	mwOpts = append(mwOpts,
+		middleware.WithChannelCompression(channelCompression, fnb.Metrics.Network),
+		middleware.WithPubSubCompression(fnb.FlowConfig.NetworkConfig.PubSubCompression))
+
+	capturer, err := fnb.networkCapturer()
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize network capture: %w", err)
+	}
+	if capturer != nil {
+		mwOpts = append(mwOpts, middleware.WithInboundCapture(capturer))
+		cf = capture.NewConduitFactory(cf, capturer)
+	}
+
 	// peerManagerFilters are used by the peerManager via the middleware to filter peers from the topology.
 	if len(peerManagerFilters) > 0 {
 		mwOpts = append(mwOpts, middleware.WithPeerManagerFilters(peerManagerFilters))
@@ -449,24 +580,40 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 
 	fnb.Middleware = mw
 
+	// nodes disallow-listed by the operator before a restart are disallow-listed again, the same way the ALSP
+	// module restores the nodes it disallow-listed from its persisted spam records.
+	if disallowListWrapper, ok := fnb.IdentityProvider.(*cache.NodeDisallowListingWrapper); ok {
+		disallowListWrapper.NotifyDisallowList()
+	}
+
 	subscriptionManager := subscription.NewChannelSubscriptionManager(fnb.Middleware)
 
 	receiveCache := netcache.NewHeroReceiveCache(fnb.FlowConfig.NetworkConfig.NetworkReceivedMessageCacheSize,
 		fnb.Logger,
 		metrics.NetworkReceiveCacheMetricsFactory(fnb.HeroCacheMetricsFactory(), network.PrivateNetwork))
 
-	err := node.Metrics.Mempool.Register(metrics.ResourceNetworkingReceiveCache, receiveCache.Size)
+	err = node.Metrics.Mempool.Register(metrics.ResourceNetworkingReceiveCache, receiveCache.Size)
 	if err != nil {
 		return nil, fmt.Errorf("could not register networking receive cache metric: %w", err)
 	}
 
+	top, err := fnb.networkTopology()
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize topology: %w", err)
+	}
+
+	inboundQueueCfg, err := fnb.inboundQueueConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse inbound queue configuration: %w", err)
+	}
+
 	// creates network instance
 	net, err := p2p.NewNetwork(&p2p.NetworkConfig{
 		Logger:              fnb.Logger,
 		Codec:               fnb.CodecFactory(),
 		Me:                  fnb.Me,
 		MiddlewareFactory:   func() (network.Middleware, error) { return fnb.Middleware, nil },
-		Topology:            topology.NewFullyConnectedTopology(),
+		Topology:            top,
 		SubscriptionManager: subscriptionManager,
 		Metrics:             fnb.Metrics.Network,
 		IdentityProvider:    fnb.IdentityProvider,
@@ -481,7 +628,10 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 			AlspMetrics:             fnb.Metrics.Network,
 			HeroCacheMetricsFactory: fnb.HeroCacheMetricsFactory(),
 			NetworkType:             network.PrivateNetwork,
+			SpamRecordStore:         fnb.AlspSpamRecordStore(network.PrivateNetwork),
+			PersistenceInterval:     fnb.FlowConfig.NetworkConfig.AlspConfig.SpamRecordPersistenceInterval,
 		},
+		InboundQueueCfg: inboundQueueCfg,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize network: %w", err)
@@ -489,6 +639,16 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 
 	fnb.Network = net
 
+	// records the ALSP penalties of peers in their score history, if the node keeps one.
+	if historian := fnb.peerScoreHistorian(); historian != nil {
+		if records, ok := net.MisbehaviorReportManager().(alsp.SpamRecordAdmin); ok {
+			err = historian.RegisterAlspSpamRecords(records)
+			if err != nil {
+				return nil, fmt.Errorf("could not register alsp spam records with peer score history: %w", err)
+			}
+		}
+	}
+
 	// register middleware's ReadyDoneAware interface so other components can depend on it for startup
 	if fnb.middlewareDependable != nil {
 		fnb.middlewareDependable.Init(fnb.Middleware)
@@ -500,6 +660,18 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(
 	return net, nil
 }
 
+// peerScoreHistorian returns the peer score history of the libp2p node, or nil if the node does not keep one.
+func (fnb *FlowNodeBuilder) peerScoreHistorian() p2p.PeerScoreHistorian {
+	if fnb.LibP2PNode == nil {
+		return nil
+	}
+	historian, ok := fnb.LibP2PNode.PeerScoreExposer().(p2p.PeerScoreHistorian)
+	if !ok {
+		return nil
+	}
+	return historian
+}
+
 func (fnb *FlowNodeBuilder) EnqueueMetricsServerInit() {
 	fnb.Component("metrics server", func(node *NodeConfig) (module.ReadyDoneAware, error) {
 		server := metrics.NewServer(fnb.Logger, fnb.BaseConfig.metricsPort)
@@ -529,6 +701,7 @@ func (fnb *FlowNodeBuilder) EnqueueAdminServerInit() error {
 
 		opts := []admin.CommandRunnerOption{
 			admin.WithMaxMsgSize(int(fnb.AdminMaxMsgSize)),
+			admin.WithDebugHandler("peer-score-history", common.NewPeerScoreHistoryHandler(fnb.peerScoreHistorian())),
 		}
 
 		if node.AdminCert != NotSet {
@@ -702,6 +875,7 @@ func (fnb *FlowNodeBuilder) initMetrics() error {
 		Cache:          metrics.NewNoopCollector(),
 		Mempool:        metrics.NewNoopCollector(),
 		CleanCollector: metrics.NewNoopCollector(),
+		DatabaseSize:   metrics.NewNoopCollector(),
 		Bitswap:        metrics.NewNoopCollector(),
 	}
 	if fnb.BaseConfig.MetricsEnabled {
@@ -717,6 +891,7 @@ func (fnb *FlowNodeBuilder) initMetrics() error {
 			// Cache:          metrics.NewCacheCollector(fnb.RootChainID),
 			Cache:          metrics.NewNoopCollector(),
 			CleanCollector: metrics.NewCleanerCollector(),
+			DatabaseSize:   metrics.NewDatabaseSizeCollector(),
 			Mempool:        mempools,
 			Bitswap:        metrics.NewBitswapCollector(),
 		}
@@ -896,6 +1071,16 @@ func (fnb *FlowNodeBuilder) initDB() error {
 		return bstorage.NewCleaner(node.Logger, node.DB, node.Metrics.CleanCollector, flow.DefaultValueLogGCWaitDuration), nil
 	})
 
+	fnb.Component("badger prefix size reporter", func(node *NodeConfig) (module.ReadyDoneAware, error) {
+		return bstorage.NewPrefixSizeReporter(node.Logger, node.DB, node.Metrics.DatabaseSize, node.BaseConfig.prefixSizeReportInterval), nil
+	})
+
+	// the compactor runs the compactions started by the compact-db admin command in the background
+	fnb.Component("badger compactor", func(node *NodeConfig) (module.ReadyDoneAware, error) {
+		fnb.dbCompactor = bstorage.NewCompactor(node.Logger, node.DB, node.Metrics.DatabaseSize)
+		return fnb.dbCompactor, nil
+	})
+
 	return nil
 }
 
@@ -1750,8 +1935,20 @@ func (fnb *FlowNodeBuilder) RegisterDefaultAdminCommands() {
 		return storageCommands.NewReadResultsCommand(config.State, config.Storage.Results)
 	}).AdminCommand("read-seals", func(config *NodeConfig) commands.AdminCommand {
 		return storageCommands.NewReadSealsCommand(config.State, config.Storage.Seals, config.Storage.Index)
+	}).AdminCommand("read-db-prefix-stats", func(config *NodeConfig) commands.AdminCommand {
+		return storageCommands.NewReadDBPrefixStatsCommand(config.DB)
+	}).AdminCommand("compact-db", func(config *NodeConfig) commands.AdminCommand {
+		return storageCommands.NewCompactDBCommand(fnb.dbCompactor)
 	}).AdminCommand("get-latest-identity", func(config *NodeConfig) commands.AdminCommand {
 		return common.NewGetIdentityCommand(config.IdentityProvider)
+	}).AdminCommand("alsp-spam-records", func(config *NodeConfig) commands.AdminCommand {
+		var records alsp.SpamRecordAdmin
+		if net, ok := config.Network.(*p2p.Network); ok {
+			records, _ = net.MisbehaviorReportManager().(alsp.SpamRecordAdmin)
+		}
+		return common.NewAlspSpamRecordsCommand(records)
+	}).AdminCommand("peer-score-history", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewPeerScoreHistoryCommand(fnb.peerScoreHistorian())
 	})
 }
 
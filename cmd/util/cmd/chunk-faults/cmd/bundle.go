@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// BundleVersion is the version of the chunk fault bundle format.
+const BundleVersion = 1
+
+// Bundle is a self-contained export of chunk faults found by a verification node. Each report
+// holds all inputs of the chunk verification, so that the bundle can be replayed offline
+// without access to the node's database.
+type Bundle struct {
+	Version    int
+	ExportedAt time.Time
+	Reports    []*chunks.FaultReport
+}
+
+// ReportFilter selects the chunk fault reports to export.
+type ReportFilter func(report *chunks.FaultReport) bool
+
+// ForResult selects the chunk fault reports of the given execution result. If resultID is
+// flow.ZeroID, all reports are selected.
+func ForResult(resultID flow.Identifier) ReportFilter {
+	return func(report *chunks.FaultReport) bool {
+		return resultID == flow.ZeroID || report.ResultID() == resultID
+	}
+}
+
+// ExportBundle collects the chunk fault reports persisted in the database which are selected
+// by the filter into a bundle.
+// No errors are expected during normal operation.
+func ExportBundle(db *badger.DB, filter ReportFilter) (*Bundle, error) {
+	bundle := &Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+	err := db.View(operation.TraverseChunkFaultReports(func(report *chunks.FaultReport) error {
+		if !filter(report) {
+			return nil
+		}
+		bundle.Reports = append(bundle.Reports, report)
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("could not traverse chunk fault reports: %w", err)
+	}
+	return bundle, nil
+}
+
+// WriteBundle writes the bundle as JSON to the given file.
+func WriteBundle(path string, bundle *Bundle) error {
+	encoded, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode bundle: %w", err)
+	}
+	err = os.WriteFile(path, encoded, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write bundle to %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadBundle reads a bundle from the given JSON file.
+func ReadBundle(path string) (*Bundle, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read bundle from %s: %w", path, err)
+	}
+	var bundle Bundle
+	err = json.Unmarshal(encoded, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode bundle: %w", err)
+	}
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d (expected %d)", bundle.Version, BundleVersion)
+	}
+	return &bundle, nil
+}
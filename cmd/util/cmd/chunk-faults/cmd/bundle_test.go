@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func faultReportFixture(chunkIndex uint64) *chunks.FaultReport {
+	vc := unittest.VerifiableChunkDataFixture(chunkIndex)
+	report := &chunks.FaultReport{
+		ChainID:           flow.Testnet,
+		Chunk:             vc.Chunk,
+		Header:            vc.Header,
+		Result:            vc.Result,
+		ChunkDataPack:     vc.ChunkDataPack,
+		EndState:          vc.EndState,
+		TransactionOffset: vc.TransactionOffset,
+		RandomSource:      unittest.RandomBytes(32),
+	}
+	report.SetOutcome(chunks.NewCFMissingRegisterTouch([]string{"register"}, chunkIndex, vc.Result.ID(), unittest.IdentifierFixture()))
+	return report
+}
+
+// TestBundle verifies that persisted chunk fault reports are exported into a bundle, and that
+// the bundle is read back from file without loss.
+func TestBundle(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		first := faultReportFixture(0)
+		second := faultReportFixture(1)
+		require.NoError(t, db.Update(operation.UpsertChunkFaultReport(first)))
+		require.NoError(t, db.Update(operation.UpsertChunkFaultReport(second)))
+
+		t.Run("filter by result", func(t *testing.T) {
+			bundle, err := ExportBundle(db, ForResult(first.ResultID()))
+			require.NoError(t, err)
+			require.Len(t, bundle.Reports, 1)
+			assert.Equal(t, first.ResultID(), bundle.Reports[0].ResultID())
+		})
+
+		bundle, err := ExportBundle(db, ForResult(flow.ZeroID))
+		require.NoError(t, err)
+		require.Len(t, bundle.Reports, 2)
+
+		unittest.RunWithTempDir(t, func(dir string) {
+			path := filepath.Join(dir, "bundle.json")
+			require.NoError(t, WriteBundle(path, bundle))
+
+			read, err := ReadBundle(path)
+			require.NoError(t, err)
+			require.Len(t, read.Reports, 2)
+			for i, report := range read.Reports {
+				expected := bundle.Reports[i]
+				assert.Equal(t, expected.ResultID(), report.ResultID())
+				assert.Equal(t, expected.Header.ID(), report.Header.ID())
+				assert.Equal(t, expected.ChunkDataPack.ID(), report.ChunkDataPack.ID())
+				assert.Equal(t, expected.MissingRegisters, report.MissingRegisters)
+				assert.Equal(t, expected.RandomSource, report.RandomSource)
+				assert.Equal(t, chunks.FaultTypeMissingRegisterTouch, report.FaultType)
+			}
+		})
+	})
+}
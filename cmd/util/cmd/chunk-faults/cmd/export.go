@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagDatadir  string
+	flagOutput   string
+	flagResultID string
+)
+
+// example:
+// ./util chunk-faults export --datadir /var/flow/data/protocol --output ./chunk-faults.json
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the chunk faults persisted by a verification node into a self-contained bundle",
+	Run:   runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&flagDatadir, "datadir", "/var/flow/data/protocol",
+		"directory to the badger database of the verification node")
+	exportCmd.Flags().StringVar(&flagOutput, "output", "",
+		"file to write the bundle to")
+	_ = exportCmd.MarkFlagRequired("output")
+	exportCmd.Flags().StringVar(&flagResultID, "result-id", "",
+		"only export the chunk faults of the given execution result (optional)")
+}
+
+func runExport(*cobra.Command, []string) {
+	resultID := flow.ZeroID
+	if flagResultID != "" {
+		var err error
+		resultID, err = flow.HexStringToIdentifier(flagResultID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed result ID")
+		}
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	bundle, err := ExportBundle(db, ForResult(resultID))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not export chunk faults")
+	}
+
+	err = WriteBundle(flagOutput, bundle)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write bundle")
+	}
+
+	log.Info().
+		Int("reports", len(bundle.Reports)).
+		Str("output", flagOutput).
+		Msg("chunk faults exported")
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	chunkverifier "github.com/onflow/flow-go/module/chunks"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/invalid"
+)
+
+var (
+	flagBundle     string
+	flagChunkIndex int64
+)
+
+// example:
+// ./util chunk-faults replay --bundle ./chunk-faults.json --result-id 1f2e... --chunk-index 3
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "replay the chunk verifications of a chunk fault bundle to reproduce the faults",
+	Run:   runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().StringVar(&flagBundle, "bundle", "",
+		"chunk fault bundle exported by the export command")
+	_ = replayCmd.MarkFlagRequired("bundle")
+	replayCmd.Flags().StringVar(&flagResultID, "result-id", "",
+		"only replay the chunk faults of the given execution result (optional)")
+	replayCmd.Flags().Int64Var(&flagChunkIndex, "chunk-index", -1,
+		"only replay the chunk fault of the given chunk index (optional)")
+}
+
+func runReplay(*cobra.Command, []string) {
+	resultID := flow.ZeroID
+	if flagResultID != "" {
+		var err error
+		resultID, err = flow.HexStringToIdentifier(flagResultID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed result ID")
+		}
+	}
+
+	bundle, err := ReadBundle(flagBundle)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read bundle")
+	}
+
+	selected := ForResult(resultID)
+	reproduced, replayed := 0, 0
+	for _, report := range bundle.Reports {
+		if !selected(report) || (flagChunkIndex >= 0 && report.ChunkIndex() != uint64(flagChunkIndex)) {
+			continue
+		}
+		replayed++
+
+		lg := log.With().
+			Str("result_id", report.ResultID().String()).
+			Uint64("chunk_index", report.ChunkIndex()).
+			Str("reported_fault_type", report.FaultType).
+			Logger()
+
+		outcome, err := Replay(report, lg)
+		if err != nil {
+			lg.Error().Err(err).Msg("could not replay chunk verification")
+			continue
+		}
+		if outcome.Reproduced() {
+			reproduced++
+		}
+		lg.Info().
+			Str("replayed_fault_type", outcome.FaultType).
+			Str("replayed_fault", outcome.Fault).
+			Bool("reproduced", outcome.Reproduced()).
+			Msg("chunk verification replayed")
+	}
+
+	log.Info().
+		Int("replayed", replayed).
+		Int("reproduced", reproduced).
+		Msg("chunk fault bundle replayed")
+}
+
+// ReplayOutcome is the outcome of replaying the chunk verification of a fault report.
+type ReplayOutcome struct {
+	Report           *chunks.FaultReport
+	FaultType        string               // type of the fault found by the replay, empty if the chunk verified successfully
+	Fault            string               // description of the fault found by the replay
+	ComputedEndState flow.StateCommitment // final state commitment computed by the replay, for final state mismatches
+}
+
+// Reproduced returns true if the replay found the same fault as the report, including the
+// same computed final state commitment for final state mismatches.
+func (o *ReplayOutcome) Reproduced() bool {
+	if o.FaultType != o.Report.FaultType {
+		return false
+	}
+	if o.FaultType == chunks.FaultTypeNonMatchingFinalState {
+		return o.ComputedEndState == o.Report.ComputedEndState
+	}
+	return true
+}
+
+// Replay re-runs the chunk verification of the fault report.
+//
+// Replays run without access to the protocol state, so transactions which look up other blocks
+// (e.g. `getBlock`) do not observe the same values as on the verification node.
+// No errors are expected during normal operation, errors indicate that the chunk could not
+// be verified at all.
+func Replay(report *chunks.FaultReport, log zerolog.Logger) (*ReplayOutcome, error) {
+	chain := report.ChainID.Chain()
+
+	vm := fvm.NewVirtualMachine()
+	vmCtx := fvm.NewContext(replayOptions(chain, log)...)
+	verifier := chunkverifier.NewChunkVerifier(vm, vmCtx, log)
+
+	vc := &verification.VerifiableChunkData{
+		IsSystemChunk:     report.IsSystemChunk,
+		Chunk:             report.Chunk,
+		Header:            report.Header,
+		Snapshot:          newRandomSourceSnapshot(report.RandomSource),
+		Result:            report.Result,
+		ChunkDataPack:     report.ChunkDataPack,
+		EndState:          report.EndState,
+		TransactionOffset: report.TransactionOffset,
+	}
+
+	_, fault, err := verifier.Verify(vc)
+	if err != nil {
+		return nil, fmt.Errorf("could not verify chunk: %w", err)
+	}
+
+	outcome := &ReplayOutcome{Report: report}
+	if fault != nil {
+		replayed := &chunks.FaultReport{}
+		replayed.SetOutcome(fault)
+		outcome.FaultType = replayed.FaultType
+		outcome.Fault = replayed.Fault
+		outcome.ComputedEndState = replayed.ComputedEndState
+	}
+	return outcome, nil
+}
+
+// replayOptions returns the FVM options of verification nodes for the given chain, except for
+// the block finder which requires the protocol state.
+func replayOptions(chain flow.Chain, log zerolog.Logger) []fvm.Option {
+	opts := []fvm.Option{
+		fvm.WithLogger(log),
+		fvm.WithChain(chain),
+		fvm.WithAccountStorageLimit(true),
+	}
+	chainID := chain.ChainID()
+	if chainID == flow.Testnet || chainID == flow.Sandboxnet || chainID == flow.Mainnet {
+		opts = append(opts, fvm.WithTransactionFeesEnabled(true))
+	}
+	if chainID == flow.Testnet || chainID == flow.Sandboxnet || chainID == flow.Localnet || chainID == flow.Benchnet {
+		opts = append(opts, fvm.WithContractDeploymentRestricted(false))
+	}
+	return opts
+}
+
+// randomSourceSnapshot stands in for the protocol state snapshot of the chunk's block during
+// replays. Chunk verification only uses the snapshot as source of randomness, which is taken
+// from the fault report. All other methods return an error, as the protocol state is not
+// available during replays.
+type randomSourceSnapshot struct {
+	*invalid.Snapshot
+	source []byte
+}
+
+var _ protocol.Snapshot = (*randomSourceSnapshot)(nil)
+
+func newRandomSourceSnapshot(source []byte) *randomSourceSnapshot {
+	return &randomSourceSnapshot{
+		Snapshot: invalid.NewSnapshotf("protocol state is not available when replaying chunk faults"),
+		source:   source,
+	}
+}
+
+func (s *randomSourceSnapshot) RandomSource() ([]byte, error) {
+	return s.source, nil
+}
@@ -0,0 +1,108 @@
+package db_stats
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/module/metrics"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+var (
+	flagDatadir        string
+	flagCompact        bool
+	flagFlattenWorkers int
+	flagDiscardRatio   float64
+	flagGCPause        string
+	flagMaxGCRounds    uint
+)
+
+var Cmd = &cobra.Command{
+	Use:   "db-stats",
+	Short: "Reports the number of keys and the size of each key prefix of the protocol database, optionally compacting it first",
+	Run:   run,
+}
+
+func init() {
+	defaultConfig := badgerstorage.DefaultCompactionConfig()
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().BoolVar(&flagCompact, "compact", false,
+		"compact the database before reporting the prefix sizes, the node must be stopped")
+	Cmd.Flags().IntVar(&flagFlattenWorkers, "flatten-workers", defaultConfig.FlattenWorkers,
+		"number of concurrent workers flattening the LSM tree during compaction")
+	Cmd.Flags().Float64Var(&flagDiscardRatio, "discard-ratio", defaultConfig.DiscardRatio,
+		"minimum ratio of discardable data for a value log file to be rewritten during compaction")
+	Cmd.Flags().StringVar(&flagGCPause, "gc-pause", defaultConfig.GCPause.String(),
+		"pause between two value log rewrites during compaction, to throttle the IO load")
+	Cmd.Flags().UintVar(&flagMaxGCRounds, "max-gc-rounds", defaultConfig.MaxGCRounds,
+		"maximum number of value log files rewritten during compaction")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().
+		Str("datadir", flagDatadir).
+		Bool("compact", flagCompact).
+		Msg("flags")
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	if flagCompact {
+		pause, err := time.ParseDuration(flagGCPause)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid gc pause")
+		}
+		config := badgerstorage.CompactionConfig{
+			Flatten:        true,
+			FlattenWorkers: flagFlattenWorkers,
+			DiscardRatio:   flagDiscardRatio,
+			GCPause:        pause,
+			MaxGCRounds:    flagMaxGCRounds,
+		}
+
+		compactor := badgerstorage.NewCompactor(log.Logger, db, metrics.NewNoopCollector())
+		result, err := compactor.Compact(context.Background(), config)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not compact database")
+		}
+		log.Info().
+			Dur("duration", result.Duration).
+			Uint("rewritten_files", result.RewrittenFiles).
+			Msg("database compacted")
+	}
+
+	var stats []operation.PrefixStats
+	err := db.View(operation.ComputePrefixStats(&stats))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not compute prefix stats")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "code\tname\tkeys\tkey bytes\tvalue bytes\ttotal bytes\t")
+	var keys, total uint64
+	for _, s := range stats {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%d\t\n", s.Code, s.Name, s.Keys, s.KeyBytes, s.ValueBytes, s.TotalBytes())
+		keys += s.Keys
+		total += s.TotalBytes()
+	}
+	fmt.Fprintf(w, "\ttotal\t%d\t\t\t%d\t\n", keys, total)
+	_ = w.Flush()
+
+	lsm, vlog := db.Size()
+	log.Info().
+		Int64("lsm_bytes", lsm).
+		Int64("vlog_bytes", vlog).
+		Msg("database size on disk")
+}
@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/bootstrap/run"
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/io"
+)
+
+var (
+	flagRecoverInternalNodePrivInfoDir string
+	flagRecoverCollectionClusters      uint
+	flagRecoverFirstView               uint64
+	flagRecoverNumViewsInEpoch         uint64
+	flagRecoverNumViewsInStaking       uint64
+	flagRecoverNumViewsInDKGPhase      uint64
+)
+
+// recoverCmd represents a command to generate the EpochRecover service event, which ends
+// epoch fallback mode without a spork.
+//
+// When the network is in epoch fallback mode, the current epoch continues until the next spork.
+// The recovery epoch generated by this command follows the current epoch of a protocol state
+// snapshot taken during epoch fallback mode. It has the same participants as the current epoch
+// (excluding ejected nodes), a new cluster assignment whose root QCs are signed by the internal
+// collection nodes, and reuses the DKG of the current epoch. The service event must be emitted
+// by the FlowEpoch smart contract, which is authorized by the service account.
+var recoverCmd = &cobra.Command{
+	Use:   "recover-epoch",
+	Short: "Generates the EpochRecover service event to leave epoch fallback mode",
+	Long: "Generates a recovery epoch following the current epoch of a protocol state snapshot taken during epoch " +
+		"fallback mode and writes the EpochRecover service event as JSON to STDOUT. The root QCs of the collector " +
+		"clusters are signed using the private node infos of the internal collection nodes.",
+	Run: recoverRun,
+}
+
+func init() {
+	rootCmd.AddCommand(recoverCmd)
+	addRecoverCmdFlags()
+}
+
+func addRecoverCmdFlags() {
+	recoverCmd.Flags().StringVar(&flagBucketNetworkName, "bucket-network-name", "", "when retrieving the root snapshot from a GCP bucket, the network name portion of the URL (eg. \"mainnet-13\")")
+	recoverCmd.Flags().StringVar(&flagRecoverInternalNodePrivInfoDir, "internal-priv-dir", "", "path to directory containing the private node infos of the internal nodes (default --boot-dir)")
+	recoverCmd.Flags().UintVar(&flagRecoverCollectionClusters, "collection-clusters", 0, "number of collection clusters of the recovery epoch")
+	_ = recoverCmd.MarkFlagRequired("collection-clusters")
+
+	// view ranges of the recovery epoch
+	recoverCmd.Flags().Uint64Var(&flagRecoverFirstView, "first-view", 0, "first view of the recovery epoch (default twice the epoch commit safety threshold after the snapshot head)")
+	recoverCmd.Flags().Uint64Var(&flagRecoverNumViewsInEpoch, "epoch-length", 0, "length of the recovery epoch measured in views (default derived from snapshot)")
+	recoverCmd.Flags().Uint64Var(&flagRecoverNumViewsInStaking, "epoch-staking-phase-length", 0, "length of the epoch staking phase measured in views (default derived from snapshot)")
+	recoverCmd.Flags().Uint64Var(&flagRecoverNumViewsInDKGPhase, "epoch-dkg-phase-length", 0, "length of each DKG phase measured in views (default derived from snapshot)")
+}
+
+// recoverRun generates the EpochRecover service event from a protocol state snapshot and writes it to STDOUT
+func recoverRun(cmd *cobra.Command, args []string) {
+
+	stdout := cmd.OutOrStdout()
+
+	// determine the source we will use for retrieving the root state snapshot,
+	// prioritizing downloading from a GCP bucket
+	var (
+		snapshot *inmem.Snapshot
+		err      error
+	)
+
+	if flagBucketNetworkName != "" {
+		url := fmt.Sprintf(rootSnapshotBucketURL, flagBucketNetworkName)
+		snapshot, err = getSnapshotFromBucket(url)
+		if err != nil {
+			log.Error().Err(err).Str("url", url).Msg("failed to retrieve root snapshot from bucket")
+			return
+		}
+	} else if flagBootDir != "" {
+		path := filepath.Join(flagBootDir, bootstrap.PathRootProtocolStateSnapshot)
+		snapshot, err = getSnapshotFromLocalBootstrapDir(path)
+		if err != nil {
+			log.Error().Err(err).Str("path", path).Msg("failed to retrieve root snapshot from local bootstrap directory")
+			return
+		}
+	} else {
+		log.Fatal().Msg("must provide a source for root snapshot (specify either --boot-dir or --bucket-network-name)")
+	}
+
+	privInfoDir := flagRecoverInternalNodePrivInfoDir
+	if privInfoDir == "" {
+		privInfoDir = flagBootDir
+	}
+	internalNodes, err := readInternalNodePrivInfos(privInfoDir)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", privInfoDir).Msg("could not read private node infos of internal nodes")
+	}
+	log.Info().Msgf("read %d internal private node-info files", len(internalNodes))
+
+	params, err := deriveEpochContractParams(snapshot.Epochs().Current())
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not derive epoch smart contract parameters from snapshot")
+	}
+	if flagRecoverNumViewsInEpoch != 0 {
+		params.NumViewsInEpoch = flagRecoverNumViewsInEpoch
+	}
+	if flagRecoverNumViewsInStaking != 0 {
+		params.NumViewsInStakingAuction = flagRecoverNumViewsInStaking
+	}
+	if flagRecoverNumViewsInDKGPhase != 0 {
+		params.NumViewsInDKGPhase = flagRecoverNumViewsInDKGPhase
+	}
+
+	recovery, err := constructRecoveryEpoch(snapshot, internalNodes, flagRecoverCollectionClusters, params, flagRecoverFirstView)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not construct recovery epoch")
+	}
+
+	encoded, err := json.MarshalIndent(recovery.ServiceEvent(), "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode EpochRecover service event")
+	}
+	_, err = stdout.Write(encoded)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write EpochRecover service event")
+	}
+
+	log.Info().
+		Uint64("recovery_epoch_counter", recovery.EpochSetup.Counter).
+		Uint64("first_view", recovery.EpochSetup.FirstView).
+		Uint64("final_view", recovery.EpochSetup.FinalView).
+		Msg("generated EpochRecover service event")
+}
+
+// readInternalNodePrivInfos reads the private node infos of the internal nodes from the given
+// directory, which has the layout of the private root information of the bootstrap directory.
+// No errors are expected during normal operation.
+func readInternalNodePrivInfos(dir string) ([]bootstrap.NodeInfoPriv, error) {
+	files, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf(bootstrap.PathNodeInfoPriv, "*")))
+	if err != nil {
+		return nil, fmt.Errorf("could not list private node infos: %w", err)
+	}
+
+	internals := make([]bootstrap.NodeInfoPriv, 0, len(files))
+	for _, file := range files {
+		data, err := io.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("could not read private node info %s: %w", file, err)
+		}
+		var info bootstrap.NodeInfoPriv
+		err = json.Unmarshal(data, &info)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode private node info %s: %w", file, err)
+		}
+		internals = append(internals, info)
+	}
+	return internals, nil
+}
+
+// constructRecoveryEpoch constructs the recovery epoch following the current epoch of the given
+// snapshot. The recovery epoch:
+//   - has the same participants as the current epoch, excluding ejected nodes
+//   - assigns the collection nodes to numClusters clusters, such that each cluster contains
+//     strictly more than 2/3 internal nodes, which sign the root QCs of the clusters
+//   - reuses the DKG of the current epoch, hence the consensus committee must not change
+//   - begins at firstView, or twice the epoch commit safety threshold after the snapshot
+//     head, if firstView is 0, and has view ranges according to params
+//
+// No errors are expected during normal operation.
+func constructRecoveryEpoch(
+	snapshot protocol.Snapshot,
+	internalNodes []bootstrap.NodeInfoPriv,
+	numClusters uint,
+	params epochContractParams,
+	firstView uint64,
+) (*flow.EpochRecover, error) {
+
+	head, err := snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot head: %w", err)
+	}
+	safetyThreshold, err := snapshot.Params().EpochCommitSafetyThreshold()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch commit safety threshold: %w", err)
+	}
+	current := snapshot.Epochs().Current()
+	counter, err := current.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch counter: %w", err)
+	}
+	currentFinalView, err := current.FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch final view: %w", err)
+	}
+
+	// view ranges of the recovery epoch
+	if firstView == 0 {
+		firstView = head.View + 2*safetyThreshold
+		if firstView <= currentFinalView {
+			firstView = currentFinalView + 1
+		}
+	}
+	if firstView <= currentFinalView {
+		return nil, fmt.Errorf("recovery epoch must begin after the current epoch's final view (%d <= %d)", firstView, currentFinalView)
+	}
+	if firstView <= head.View+safetyThreshold {
+		return nil, fmt.Errorf("recovery epoch must begin more than the epoch commit safety threshold (%d) after the snapshot head (view %d), got first view %d",
+			safetyThreshold, head.View, firstView)
+	}
+	if params.NumViewsInStakingAuction+3*params.NumViewsInDKGPhase >= params.NumViewsInEpoch {
+		return nil, fmt.Errorf("staking auction and dkg phases (%d + 3*%d views) do not fit within the recovery epoch (%d views)",
+			params.NumViewsInStakingAuction, params.NumViewsInDKGPhase, params.NumViewsInEpoch)
+	}
+
+	// participants of the recovery epoch
+	participants, err := snapshot.Identities(filter.And(filter.HasWeight(true), filter.Not(filter.Ejected)))
+	if err != nil {
+		return nil, fmt.Errorf("could not get participants: %w", err)
+	}
+	participants = participants.Sort(order.Canonical)
+
+	// the recovery epoch reuses the DKG of the current epoch
+	dkg, err := current.DKG()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch dkg: %w", err)
+	}
+	dkgParticipantKeys, err := protocol.GetDKGParticipantKeys(dkg, participants.Filter(filter.IsValidDKGParticipant))
+	if err != nil {
+		return nil, fmt.Errorf("consensus committee of the recovery epoch is inconsistent with the current epoch's dkg: %w", err)
+	}
+
+	// cluster assignment and root QCs signed by the internal collection nodes
+	internalCollectors := make(map[flow.Identifier]bootstrap.NodeInfo)
+	for _, internal := range internalNodes {
+		identity, ok := participants.ByNodeID(internal.NodeID)
+		if !ok || identity.Role != flow.RoleCollection {
+			continue
+		}
+		internalCollectors[identity.NodeID] = bootstrap.PrivateNodeInfoFromIdentity(identity, internal.NetworkPrivKey.PrivateKey, internal.StakingPrivKey.PrivateKey)
+	}
+	collectors := participants.Filter(filter.HasRole(flow.RoleCollection))
+	partnerCollectors := collectors.Filter(func(identity *flow.Identity) bool {
+		_, ok := internalCollectors[identity.NodeID]
+		return !ok
+	})
+	internalCollectorIdentities := collectors.Filter(func(identity *flow.Identity) bool {
+		_, ok := internalCollectors[identity.NodeID]
+		return ok
+	})
+	assignments, clusters, err := run.ConstructClusterAssignment(partnerCollectors, internalCollectorIdentities, numClusters)
+	if err != nil {
+		return nil, fmt.Errorf("could not construct cluster assignment: %w", err)
+	}
+
+	recoveryCounter := counter + 1
+	clusterBlocks := run.GenerateRootClusterBlocks(recoveryCounter, clusters)
+	clusterQCs := make([]*flow.QuorumCertificateWithSignerIDs, 0, len(clusters))
+	for i, cluster := range clusters {
+		signers := make([]bootstrap.NodeInfo, 0, len(cluster))
+		for _, member := range cluster {
+			if internal, ok := internalCollectors[member.NodeID]; ok {
+				signers = append(signers, internal)
+			}
+		}
+		qc, err := run.GenerateClusterRootQC(signers, cluster, clusterBlocks[i])
+		if err != nil {
+			return nil, fmt.Errorf("could not generate root qc for cluster %d: %w", i, err)
+		}
+		signerIDs, err := signature.DecodeSignerIndicesToIdentifiers(cluster.NodeIDs(), qc.SignerIndices)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode signer IDs of root qc for cluster %d: %w", i, err)
+		}
+		clusterQCs = append(clusterQCs, &flow.QuorumCertificateWithSignerIDs{
+			View:      qc.View,
+			BlockID:   qc.BlockID,
+			SignerIDs: signerIDs,
+			SigData:   qc.SigData,
+		})
+	}
+
+	randomSource := make([]byte, flow.EpochSetupRandomSourceLength)
+	_, err = rand.Read(randomSource)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate random source: %w", err)
+	}
+
+	recovery := &flow.EpochRecover{
+		EpochSetup: flow.EpochSetup{
+			Counter:            recoveryCounter,
+			FirstView:          firstView,
+			DKGPhase1FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase - 1,
+			DKGPhase2FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase*2 - 1,
+			DKGPhase3FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase*3 - 1,
+			FinalView:          firstView + params.NumViewsInEpoch - 1,
+			Participants:       participants,
+			Assignments:        assignments,
+			RandomSource:       randomSource,
+		},
+		EpochCommit: flow.EpochCommit{
+			Counter:            recoveryCounter,
+			ClusterQCs:         flow.ClusterQCVoteDatasFromQCs(clusterQCs),
+			DKGGroupKey:        dkg.GroupKey(),
+			DKGParticipantKeys: dkgParticipantKeys,
+		},
+	}
+	return recovery, nil
+}
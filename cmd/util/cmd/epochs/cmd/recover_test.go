@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// recoverFixture returns a root snapshot, whose collection nodes are the given internal
+// nodes and one partner node, together with the private node infos of the internal nodes.
+func recoverFixture(t *testing.T, numInternalCollectors int) (*inmem.Snapshot, []bootstrap.NodeInfoPriv) {
+	internalNodes := unittest.PrivateNodeInfosFixture(numInternalCollectors, unittest.WithRole(flow.RoleCollection))
+	participants := unittest.CompleteIdentitySet()
+	privInfos := make([]bootstrap.NodeInfoPriv, 0, len(internalNodes))
+	for _, node := range internalNodes {
+		participants = append(participants, node.Identity())
+		privInfo, err := node.Private()
+		require.NoError(t, err)
+		privInfos = append(privInfos, privInfo)
+	}
+	return unittest.RootSnapshotFixture(participants), privInfos
+}
+
+// writeInternalNodePrivInfos writes the private node infos with the layout of the bootstrap directory.
+func writeInternalNodePrivInfos(dir string, privInfos []bootstrap.NodeInfoPriv) error {
+	for _, info := range privInfos {
+		err := writeJSON(filepath.Join(dir, fmt.Sprintf(bootstrap.PathNodeInfoPriv, info.NodeID)), info)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestConstructRecoveryEpoch(t *testing.T) {
+	params := epochContractParams{
+		NumViewsInEpoch:          5000,
+		NumViewsInStakingAuction: 1000,
+		NumViewsInDKGPhase:       500,
+	}
+
+	t.Run("recovery epoch follows current epoch", func(t *testing.T) {
+		snapshot, privInfos := recoverFixture(t, 4)
+		current := snapshot.Epochs().Current()
+		currentSetup := snapshot.Encodable().Epochs.Current
+		dkg, err := current.DKG()
+		require.NoError(t, err)
+
+		recovery, err := constructRecoveryEpoch(snapshot, privInfos, 1, params, 0)
+		require.NoError(t, err)
+
+		setup := recovery.EpochSetup
+		assert.Equal(t, currentSetup.Counter+1, setup.Counter)
+		assert.Greater(t, setup.FirstView, currentSetup.FinalView)
+		assert.Equal(t, setup.FirstView+params.NumViewsInEpoch-1, setup.FinalView)
+		assert.Len(t, setup.RandomSource, flow.EpochSetupRandomSourceLength)
+		assert.ElementsMatch(t, currentSetup.InitialIdentities.NodeIDs(), setup.Participants.NodeIDs())
+		require.Len(t, setup.Assignments, 1)
+
+		// the recovery epoch reuses the current dkg
+		commit := recovery.EpochCommit
+		assert.Equal(t, setup.Counter, commit.Counter)
+		assert.True(t, dkg.GroupKey().Equals(commit.DKGGroupKey))
+		assert.Len(t, commit.DKGParticipantKeys, len(setup.Participants.Filter(filter.IsValidDKGParticipant)))
+
+		// the cluster root qc is signed by the internal nodes
+		require.Len(t, commit.ClusterQCs, 1)
+		assert.Len(t, commit.ClusterQCs[0].VoterIDs, len(privInfos))
+	})
+
+	t.Run("first view must leave time to prepare for recovery epoch", func(t *testing.T) {
+		snapshot, privInfos := recoverFixture(t, 4)
+		currentFinalView := snapshot.Encodable().Epochs.Current.FinalView
+
+		_, err := constructRecoveryEpoch(snapshot, privInfos, 1, params, currentFinalView)
+		require.Error(t, err)
+	})
+
+	t.Run("too few internal nodes", func(t *testing.T) {
+		snapshot, privInfos := recoverFixture(t, 2)
+
+		// two clusters, each with one internal node
+		_, err := constructRecoveryEpoch(snapshot, privInfos, 2, params, 0)
+		require.Error(t, err)
+	})
+}
+
+// TestRecover_LocalSnapshot tests the command with a local snapshot file and private node infos.
+func TestRecover_LocalSnapshot(t *testing.T) {
+	unittest.RunWithTempDir(t, func(bootDir string) {
+		snapshot, privInfos := recoverFixture(t, 4)
+		err := writeRootSnapshot(bootDir, snapshot)
+		require.NoError(t, err)
+		err = writeInternalNodePrivInfos(bootDir, privInfos)
+		require.NoError(t, err)
+
+		// set initial flag values
+		flagBootDir = bootDir
+		flagBucketNetworkName = ""
+		flagRecoverInternalNodePrivInfoDir = ""
+		flagRecoverCollectionClusters = 1
+
+		// run command with overwritten stdout
+		stdout := bytes.NewBuffer(nil)
+		recoverCmd.SetOut(stdout)
+		recoverRun(recoverCmd, nil)
+
+		var serviceEvent flow.ServiceEvent
+		err = json.NewDecoder(stdout).Decode(&serviceEvent)
+		require.NoError(t, err)
+		require.Equal(t, flow.ServiceEventRecoverEpoch, serviceEvent.Type)
+		recovery, ok := serviceEvent.Event.(*flow.EpochRecover)
+		require.True(t, ok)
+		assert.Equal(t, snapshot.Encodable().Epochs.Current.Counter+1, recovery.EpochSetup.Counter)
+	})
+}
@@ -0,0 +1,422 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+var (
+	flagSimulateNumEpochs            uint
+	flagSimulateNumViewsInEpoch      uint64
+	flagSimulateNumViewsInStaking    uint64
+	flagSimulateNumViewsInDKGPhase   uint64
+	flagSimulateSafetyThreshold      uint64
+	flagSimulateServiceEventViews    uint64
+	flagSimulateDKGResultViews       uint64
+	flagSimulateClusterQCVotingViews uint64
+)
+
+// simulateCmd represents a command to simulate the epoch transitions following a root
+// protocol state snapshot, given the parameters of the FlowEpoch smart contract.
+//
+// For each epoch, the staking, setup and committed phases are simulated, including the
+// expected views needed for the DKG and the cluster QC voting to complete. The command
+// flags configurations where the phase transitions would not fit within the view range
+// of the epoch, or where the EpochCommit service event would not be incorporated before
+// the epoch commitment deadline, which would trigger epoch fallback mode.
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulates the epoch phase transitions following a root snapshot",
+	Long: "Simulates the staking, setup and committed phases of the epochs following a root protocol state snapshot " +
+		"and writes a JSON report to STDOUT. Exits with an error if a phase transition would not fit within the " +
+		"view range of its epoch or would trigger epoch fallback mode.",
+	Run: simulateRun,
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+	addSimulateCmdFlags()
+}
+
+func addSimulateCmdFlags() {
+	simulateCmd.Flags().StringVar(&flagBucketNetworkName, "bucket-network-name", "", "when retrieving the root snapshot from a GCP bucket, the network name portion of the URL (eg. \"mainnet-13\")")
+	simulateCmd.Flags().UintVar(&flagSimulateNumEpochs, "epochs", 3, "number of epochs to simulate, starting with the current epoch of the snapshot")
+
+	// epoch smart contract parameters, derived from the current epoch of the snapshot if not set
+	simulateCmd.Flags().Uint64Var(&flagSimulateNumViewsInEpoch, "epoch-length", 0, "length of each epoch measured in views (default derived from snapshot)")
+	simulateCmd.Flags().Uint64Var(&flagSimulateNumViewsInStaking, "epoch-staking-phase-length", 0, "length of the epoch staking phase measured in views (default derived from snapshot)")
+	simulateCmd.Flags().Uint64Var(&flagSimulateNumViewsInDKGPhase, "epoch-dkg-phase-length", 0, "length of each DKG phase measured in views (default derived from snapshot)")
+	simulateCmd.Flags().Uint64Var(&flagSimulateSafetyThreshold, "epoch-commit-safety-threshold", 0, "epoch commitment deadline measured in views before the end of the epoch (default from snapshot)")
+
+	// expected latencies of the epoch protocol
+	simulateCmd.Flags().Uint64Var(&flagSimulateServiceEventViews, "service-event-views", 50, "expected views between emitting a service event and incorporating its seal")
+	simulateCmd.Flags().Uint64Var(&flagSimulateDKGResultViews, "dkg-result-views", 50, "expected views after the final DKG phase until the DKG result is submitted")
+	simulateCmd.Flags().Uint64Var(&flagSimulateClusterQCVotingViews, "qc-voting-views", 100, "expected views after the start of the setup phase until all cluster QCs are voted")
+}
+
+// epochContractParams are the parameters of the FlowEpoch smart contract which determine the
+// view ranges of the epochs it sets up.
+type epochContractParams struct {
+	NumViewsInEpoch          uint64
+	NumViewsInStakingAuction uint64
+	NumViewsInDKGPhase       uint64
+}
+
+// epochLatencies are the expected number of views needed by the steps of the epoch protocol.
+type epochLatencies struct {
+	ServiceEventViews    uint64 // views between emitting a service event and incorporating its seal
+	DKGResultViews       uint64 // views after the final DKG phase until the DKG result is submitted
+	ClusterQCVotingViews uint64 // views after the start of the setup phase until all cluster QCs are voted
+}
+
+// simulatedEpoch is the simulated timeline of an epoch, including the transition to the next epoch.
+//
+//	  Staking  Setup                        Committed
+//	           DKG1  DKG2  DKG3
+//	|---------|-----|-----|-----|-----------|--------------|------|
+//	          ^                 ^           ^              ^      ^-FinalView
+//	          |                 |           |              `-CommitDeadline
+//	          |                 |           `-CommitView
+//	          |                 `-DKGPhase3FinalView
+//	          `-StakingAuctionEndView
+type simulatedEpoch struct {
+	Counter               uint64
+	FirstView             uint64
+	FinalView             uint64
+	StakingAuctionEndView uint64
+	DKGPhase1FinalView    uint64
+	DKGPhase2FinalView    uint64
+	DKGPhase3FinalView    uint64
+	// the following views are the expected views of the transition to the next epoch
+	SetupView           uint64   // view of the block incorporating the seal of the next EpochSetup event
+	ClusterQCVotingView uint64   // view by which all cluster QCs for the next epoch are voted
+	DKGResultView       uint64   // view by which the DKG result for the next epoch is submitted
+	CommitView          uint64   // view of the block incorporating the seal of the next EpochCommit event
+	CommitDeadline      uint64   // first view triggering epoch fallback if the next epoch is not committed
+	AlreadySetup        bool     // true if the next epoch was already set up as of the snapshot
+	AlreadyCommitted    bool     // true if the next epoch was already committed as of the snapshot
+	Issues              []string `json:",omitempty"`
+}
+
+// simulationReport is the output of the simulate command.
+type simulationReport struct {
+	ChainID                    flow.ChainID
+	Phase                      string
+	EpochCommitSafetyThreshold uint64
+	DefaultSafetyThreshold     uint64
+	Params                     epochContractParams
+	Latencies                  epochLatencies
+	Epochs                     []simulatedEpoch
+	Issues                     []string `json:",omitempty"`
+}
+
+// HasIssues returns true if any issue was found during the simulation.
+func (r *simulationReport) HasIssues() bool {
+	if len(r.Issues) > 0 {
+		return true
+	}
+	for _, epoch := range r.Epochs {
+		if len(epoch.Issues) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// simulateRun simulates the epochs following a root protocol state snapshot and writes the report to STDOUT
+func simulateRun(cmd *cobra.Command, args []string) {
+
+	stdout := cmd.OutOrStdout()
+
+	// determine the source we will use for retrieving the root state snapshot,
+	// prioritizing downloading from a GCP bucket
+	var (
+		snapshot *inmem.Snapshot
+		err      error
+	)
+
+	if flagBucketNetworkName != "" {
+		url := fmt.Sprintf(rootSnapshotBucketURL, flagBucketNetworkName)
+		snapshot, err = getSnapshotFromBucket(url)
+		if err != nil {
+			log.Error().Err(err).Str("url", url).Msg("failed to retrieve root snapshot from bucket")
+			return
+		}
+	} else if flagBootDir != "" {
+		path := filepath.Join(flagBootDir, bootstrap.PathRootProtocolStateSnapshot)
+		snapshot, err = getSnapshotFromLocalBootstrapDir(path)
+		if err != nil {
+			log.Error().Err(err).Str("path", path).Msg("failed to retrieve root snapshot from local bootstrap directory")
+			return
+		}
+	} else {
+		log.Fatal().Msg("must provide a source for root snapshot (specify either --boot-dir or --bucket-network-name)")
+	}
+
+	params, err := deriveEpochContractParams(snapshot.Epochs().Current())
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not derive epoch smart contract parameters from snapshot")
+	}
+	if flagSimulateNumViewsInEpoch != 0 {
+		params.NumViewsInEpoch = flagSimulateNumViewsInEpoch
+	}
+	if flagSimulateNumViewsInStaking != 0 {
+		params.NumViewsInStakingAuction = flagSimulateNumViewsInStaking
+	}
+	if flagSimulateNumViewsInDKGPhase != 0 {
+		params.NumViewsInDKGPhase = flagSimulateNumViewsInDKGPhase
+	}
+	latencies := epochLatencies{
+		ServiceEventViews:    flagSimulateServiceEventViews,
+		DKGResultViews:       flagSimulateDKGResultViews,
+		ClusterQCVotingViews: flagSimulateClusterQCVotingViews,
+	}
+
+	report, err := simulateEpochs(snapshot, params, latencies, flagSimulateSafetyThreshold, flagSimulateNumEpochs)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not simulate epochs")
+	}
+
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode simulation report")
+	}
+	_, err = stdout.Write(encoded)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write simulation report")
+	}
+
+	if report.HasIssues() {
+		log.Fatal().Msg("epoch configuration would not complete all epoch transitions safely, see issues in report")
+	}
+	log.Info().Uint("epochs", flagSimulateNumEpochs).Msg("all simulated epoch transitions complete before the epoch commitment deadline")
+}
+
+// deriveEpochContractParams derives the epoch smart contract parameters from the view ranges
+// of the given epoch, assuming the epoch was set up by the smart contract.
+// No errors are expected during normal operation.
+func deriveEpochContractParams(epoch protocol.Epoch) (epochContractParams, error) {
+	firstView, err := epoch.FirstView()
+	if err != nil {
+		return epochContractParams{}, fmt.Errorf("could not get first view: %w", err)
+	}
+	finalView, err := epoch.FinalView()
+	if err != nil {
+		return epochContractParams{}, fmt.Errorf("could not get final view: %w", err)
+	}
+	stakingEndView, err := getStakingAuctionEndView(epoch)
+	if err != nil {
+		return epochContractParams{}, fmt.Errorf("could not determine staking auction end view: %w", err)
+	}
+	dkgPhase1FinalView, err := epoch.DKGPhase1FinalView()
+	if err != nil {
+		return epochContractParams{}, fmt.Errorf("could not get dkg phase 1 final view: %w", err)
+	}
+	if stakingEndView+1 < firstView {
+		return epochContractParams{}, fmt.Errorf("invalid dkg timing - staking auction ends (%d) before the epoch begins (%d)", stakingEndView, firstView)
+	}
+
+	return epochContractParams{
+		NumViewsInEpoch:          finalView - firstView + 1,
+		NumViewsInStakingAuction: stakingEndView - firstView + 1,
+		NumViewsInDKGPhase:       dkgPhase1FinalView - stakingEndView,
+	}, nil
+}
+
+// simulateEpochs simulates the phase transitions of numEpochs epochs, starting with the current
+// epoch of the snapshot. The view ranges of the current epoch, and of the next epoch if it was
+// already set up, are taken from the snapshot. The view ranges of the following epochs are
+// determined by the epoch smart contract parameters.
+// If safetyThreshold is 0, the epoch commit safety threshold of the snapshot is used.
+// Issues found during the simulation are recorded in the report.
+// No errors are expected during normal operation.
+func simulateEpochs(snapshot protocol.Snapshot, params epochContractParams, latencies epochLatencies, safetyThreshold uint64, numEpochs uint) (*simulationReport, error) {
+	chainID, err := snapshot.Params().ChainID()
+	if err != nil {
+		return nil, fmt.Errorf("could not get chain id: %w", err)
+	}
+	if safetyThreshold == 0 {
+		safetyThreshold, err = snapshot.Params().EpochCommitSafetyThreshold()
+		if err != nil {
+			return nil, fmt.Errorf("could not get epoch commit safety threshold: %w", err)
+		}
+	}
+	defaultSafetyThreshold, err := protocol.DefaultEpochCommitSafetyThreshold(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get default epoch commit safety threshold: %w", err)
+	}
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch phase: %w", err)
+	}
+
+	report := &simulationReport{
+		ChainID:                    chainID,
+		Phase:                      phase.String(),
+		EpochCommitSafetyThreshold: safetyThreshold,
+		DefaultSafetyThreshold:     defaultSafetyThreshold,
+		Params:                     params,
+		Latencies:                  latencies,
+	}
+
+	// sanity check: the safety threshold is >= the default for the chain
+	if safetyThreshold < defaultSafetyThreshold {
+		report.Issues = append(report.Issues, fmt.Sprintf("potentially unsafe epoch config: epoch commit safety threshold smaller than expected (%d < %d)",
+			safetyThreshold, defaultSafetyThreshold))
+	}
+	if params.NumViewsInEpoch == 0 || params.NumViewsInDKGPhase == 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("invalid epoch config: epoch length (%d) and dkg phase length (%d) must be positive",
+			params.NumViewsInEpoch, params.NumViewsInDKGPhase))
+		return report, nil
+	}
+
+	epoch, err := simulatedEpochFromSnapshot(snapshot.Epochs().Current())
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch: %w", err)
+	}
+	epoch.AlreadySetup = phase == flow.EpochPhaseSetup || phase == flow.EpochPhaseCommitted
+	epoch.AlreadyCommitted = phase == flow.EpochPhaseCommitted
+
+	for i := uint(0); i < numEpochs; i++ {
+		if i == 1 && epoch.AlreadySetup {
+			// the next epoch was already set up as of the snapshot, so its view ranges are known
+			epoch, err = simulatedEpochFromSnapshot(snapshot.Epochs().Next())
+			if err != nil {
+				return nil, fmt.Errorf("could not get next epoch: %w", err)
+			}
+		} else if i > 0 {
+			epoch = nextSimulatedEpoch(epoch, params)
+		}
+		simulateEpochTransition(&epoch, latencies, safetyThreshold, defaultSafetyThreshold)
+		report.Epochs = append(report.Epochs, epoch)
+	}
+
+	return report, nil
+}
+
+// simulatedEpochFromSnapshot returns the simulated epoch with the view ranges of the given epoch.
+// No errors are expected during normal operation.
+func simulatedEpochFromSnapshot(epoch protocol.Epoch) (simulatedEpoch, error) {
+	counter, err := epoch.Counter()
+	if err != nil {
+		return simulatedEpoch{}, fmt.Errorf("could not get counter: %w", err)
+	}
+	firstView, err := epoch.FirstView()
+	if err != nil {
+		return simulatedEpoch{}, fmt.Errorf("could not get first view: %w", err)
+	}
+	finalView, err := epoch.FinalView()
+	if err != nil {
+		return simulatedEpoch{}, fmt.Errorf("could not get final view: %w", err)
+	}
+	dkgPhase1FinalView, err := epoch.DKGPhase1FinalView()
+	if err != nil {
+		return simulatedEpoch{}, fmt.Errorf("could not get dkg phase 1 final view: %w", err)
+	}
+	dkgPhase2FinalView, err := epoch.DKGPhase2FinalView()
+	if err != nil {
+		return simulatedEpoch{}, fmt.Errorf("could not get dkg phase 2 final view: %w", err)
+	}
+	dkgPhase3FinalView, err := epoch.DKGPhase3FinalView()
+	if err != nil {
+		return simulatedEpoch{}, fmt.Errorf("could not get dkg phase 3 final view: %w", err)
+	}
+	return simulatedEpoch{
+		Counter:            counter,
+		FirstView:          firstView,
+		FinalView:          finalView,
+		DKGPhase1FinalView: dkgPhase1FinalView,
+		DKGPhase2FinalView: dkgPhase2FinalView,
+		DKGPhase3FinalView: dkgPhase3FinalView,
+	}, nil
+}
+
+// nextSimulatedEpoch returns the epoch following the given epoch, as set up by the epoch smart contract.
+func nextSimulatedEpoch(epoch simulatedEpoch, params epochContractParams) simulatedEpoch {
+	firstView := epoch.FinalView + 1
+	return simulatedEpoch{
+		Counter:            epoch.Counter + 1,
+		FirstView:          firstView,
+		FinalView:          firstView + params.NumViewsInEpoch - 1,
+		DKGPhase1FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase - 1,
+		DKGPhase2FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase*2 - 1,
+		DKGPhase3FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase*3 - 1,
+	}
+}
+
+// simulateEpochTransition simulates the setup and committed phases of the given epoch, which set
+// up the next epoch, and records the expected views and issues in the epoch.
+//
+// The EpochSetup event is emitted with the first block after the staking auction and takes effect
+// once its seal is incorporated. The cluster QC voting starts with the setup phase, while the DKG
+// follows the DKG phase views. The EpochCommit event is emitted once both completed, and the next
+// epoch is committed once its seal is incorporated.
+func simulateEpochTransition(epoch *simulatedEpoch, latencies epochLatencies, safetyThreshold, defaultSafetyThreshold uint64) {
+	if epoch.DKGPhase1FinalView >= epoch.DKGPhase2FinalView || epoch.DKGPhase2FinalView >= epoch.DKGPhase3FinalView {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("invalid dkg timing: dkg phase final views (%d, %d, %d) are not increasing",
+			epoch.DKGPhase1FinalView, epoch.DKGPhase2FinalView, epoch.DKGPhase3FinalView))
+		return
+	}
+	// the staking auction ends when the dkg begins
+	dkgPhaseLength := epoch.DKGPhase2FinalView - epoch.DKGPhase1FinalView
+	if epoch.DKGPhase1FinalView < dkgPhaseLength || epoch.DKGPhase1FinalView-dkgPhaseLength+1 < epoch.FirstView {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("invalid dkg timing: dkg phase 1 (final view %d) begins before the epoch (first view %d)",
+			epoch.DKGPhase1FinalView, epoch.FirstView))
+		return
+	}
+	epoch.StakingAuctionEndView = epoch.DKGPhase1FinalView - dkgPhaseLength
+	if safetyThreshold >= epoch.FinalView-epoch.FirstView {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("epoch fallback: epoch commit safety threshold (%d) exceeds the epoch length (%d)",
+			safetyThreshold, epoch.FinalView-epoch.FirstView+1))
+		return
+	}
+	epoch.CommitDeadline = epoch.FinalView - safetyThreshold
+
+	// sanity check: the DKG phases fit within the epoch
+	if epoch.DKGPhase3FinalView >= epoch.FinalView {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("dkg does not fit within the epoch: dkg phase 3 final view (%d) is not before the epoch final view (%d)",
+			epoch.DKGPhase3FinalView, epoch.FinalView))
+	}
+	// sanity check: the epoch commitment deadline is not before the DKG end, with enough views in between
+	if epoch.CommitDeadline <= epoch.DKGPhase3FinalView {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("invalid epoch config: the epoch commitment deadline (%d) is before the DKG final view (%d)",
+			epoch.CommitDeadline, epoch.DKGPhase3FinalView))
+	} else if epoch.CommitDeadline-epoch.DKGPhase3FinalView < defaultSafetyThreshold {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("potentially unsafe epoch config: time between DKG end and epoch commitment deadline is smaller than expected (%d-%d < %d)",
+			epoch.CommitDeadline, epoch.DKGPhase3FinalView, defaultSafetyThreshold))
+	}
+
+	if epoch.AlreadyCommitted {
+		// the next epoch was already committed as of the snapshot, the transition cannot fail anymore
+		return
+	}
+
+	epoch.SetupView = epoch.StakingAuctionEndView + 1 + latencies.ServiceEventViews
+	if !epoch.AlreadySetup && epoch.SetupView > epoch.DKGPhase1FinalView {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("setup phase starts too late: EpochSetup is expected to be incorporated at view %d, after dkg phase 1 ends (%d)",
+			epoch.SetupView, epoch.DKGPhase1FinalView))
+	}
+	epoch.ClusterQCVotingView = epoch.SetupView + latencies.ClusterQCVotingViews
+	epoch.DKGResultView = epoch.DKGPhase3FinalView + latencies.DKGResultViews
+
+	commitEmittedView := epoch.DKGResultView
+	if epoch.ClusterQCVotingView > commitEmittedView {
+		commitEmittedView = epoch.ClusterQCVotingView
+	}
+	epoch.CommitView = commitEmittedView + 1 + latencies.ServiceEventViews
+
+	// epoch fallback is triggered when finalizing the first block with a view >= the commitment
+	// deadline, if the next epoch is not committed by then
+	if epoch.CommitView >= epoch.CommitDeadline {
+		epoch.Issues = append(epoch.Issues, fmt.Sprintf("epoch fallback: EpochCommit is expected to be incorporated at view %d, not before the epoch commitment deadline (%d)",
+			epoch.CommitView, epoch.CommitDeadline))
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// simulateSnapshotFixture returns a localnet snapshot in the given phase, whose current epoch
+// begins at view 100 and was set up with the given epoch smart contract parameters. In the setup
+// and committed phases, the next epoch was set up with the same parameters.
+func simulateSnapshotFixture(phase flow.EpochPhase, params epochContractParams) *inmem.Snapshot {
+	return simulateSnapshotFixtureWithNext(phase, params, params)
+}
+
+// simulateSnapshotFixtureWithNext returns a localnet snapshot in the given phase, whose current
+// epoch begins at view 100 and was set up with the given epoch smart contract parameters. In the
+// setup and committed phases, the next epoch was set up with the given next parameters.
+func simulateSnapshotFixtureWithNext(phase flow.EpochPhase, params, nextParams epochContractParams) *inmem.Snapshot {
+	current := encodableEpochFixture(1, 100, params)
+	var next *inmem.EncodableEpoch
+	if phase == flow.EpochPhaseSetup || phase == flow.EpochPhaseCommitted {
+		epoch := encodableEpochFixture(2, current.FinalView+1, nextParams)
+		next = &epoch
+	}
+	return inmem.SnapshotFromEncodable(inmem.EncodableSnapshot{
+		Head:  unittest.BlockHeaderFixture(unittest.HeaderWithView(current.FirstView)),
+		Phase: phase,
+		Epochs: inmem.EncodableEpochs{
+			Current: current,
+			Next:    next,
+		},
+		Params: inmem.EncodableParams{
+			ChainID:                    flow.Localnet,
+			EpochCommitSafetyThreshold: 100,
+		},
+	})
+}
+
+// encodableEpochFixture returns an epoch with the given counter beginning at the given view,
+// with the view ranges set up by the given epoch smart contract parameters.
+func encodableEpochFixture(counter uint64, firstView uint64, params epochContractParams) inmem.EncodableEpoch {
+	return inmem.EncodableEpoch{
+		Counter:            counter,
+		FirstView:          firstView,
+		DKGPhase1FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase - 1,
+		DKGPhase2FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase*2 - 1,
+		DKGPhase3FinalView: firstView + params.NumViewsInStakingAuction + params.NumViewsInDKGPhase*3 - 1,
+		FinalView:          firstView + params.NumViewsInEpoch - 1,
+	}
+}
+
+func TestSimulateEpochs(t *testing.T) {
+	params := epochContractParams{
+		NumViewsInEpoch:          5000,
+		NumViewsInStakingAuction: 1000,
+		NumViewsInDKGPhase:       500,
+	}
+	latencies := epochLatencies{
+		ServiceEventViews:    50,
+		DKGResultViews:       50,
+		ClusterQCVotingViews: 100,
+	}
+
+	t.Run("derive contract parameters from snapshot", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseStaking, params)
+		derived, err := deriveEpochContractParams(snapshot.Epochs().Current())
+		require.NoError(t, err)
+		assert.Equal(t, params, derived)
+	})
+
+	t.Run("all epoch transitions complete in time", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseStaking, params)
+		report, err := simulateEpochs(snapshot, params, latencies, 0, 3)
+		require.NoError(t, err)
+		assert.False(t, report.HasIssues())
+		assert.Equal(t, uint64(100), report.EpochCommitSafetyThreshold)
+		require.Len(t, report.Epochs, 3)
+
+		current := report.Epochs[0]
+		assert.Equal(t, uint64(1099), current.StakingAuctionEndView)
+		assert.Equal(t, uint64(1150), current.SetupView)
+		assert.Equal(t, uint64(2649), current.DKGResultView)
+		assert.Equal(t, uint64(2700), current.CommitView)
+		assert.Equal(t, uint64(4999), current.CommitDeadline)
+
+		// the following epochs are consecutive and set up by the contract parameters
+		for i := 1; i < len(report.Epochs); i++ {
+			previous, epoch := report.Epochs[i-1], report.Epochs[i]
+			assert.Equal(t, previous.Counter+1, epoch.Counter)
+			assert.Equal(t, previous.FinalView+1, epoch.FirstView)
+			assert.Equal(t, previous.CommitView+params.NumViewsInEpoch, epoch.CommitView)
+		}
+	})
+
+	t.Run("epoch commit after commitment deadline triggers epoch fallback", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseStaking, params)
+		short := params
+		short.NumViewsInEpoch = 2700
+		report, err := simulateEpochs(snapshot, short, latencies, 0, 2)
+		require.NoError(t, err)
+		require.True(t, report.HasIssues())
+
+		// the current epoch is not affected by the contract parameters
+		assert.Empty(t, report.Epochs[0].Issues)
+		require.Len(t, report.Epochs[1].Issues, 1)
+		assert.Contains(t, report.Epochs[1].Issues[0], "epoch fallback")
+	})
+
+	t.Run("dkg does not fit within epoch", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseStaking, params)
+		short := params
+		short.NumViewsInEpoch = 2500
+		report, err := simulateEpochs(snapshot, short, latencies, 0, 2)
+		require.NoError(t, err)
+		require.NotEmpty(t, report.Epochs[1].Issues)
+		assert.Contains(t, report.Epochs[1].Issues[0], "dkg does not fit within the epoch")
+	})
+
+	t.Run("setup phase starts after first dkg phase", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseStaking, params)
+		slow := latencies
+		slow.ServiceEventViews = 600
+		report, err := simulateEpochs(snapshot, params, slow, 0, 1)
+		require.NoError(t, err)
+		require.NotEmpty(t, report.Epochs[0].Issues)
+		assert.Contains(t, report.Epochs[0].Issues[0], "setup phase starts too late")
+	})
+
+	t.Run("next epoch already set up", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseSetup, params)
+		slow := latencies
+		slow.ServiceEventViews = 600
+		report, err := simulateEpochs(snapshot, params, slow, 0, 1)
+		require.NoError(t, err)
+		assert.True(t, report.Epochs[0].AlreadySetup)
+		for _, issue := range report.Epochs[0].Issues {
+			assert.NotContains(t, issue, "setup phase starts too late")
+		}
+	})
+
+	t.Run("next epoch view ranges are taken from the snapshot", func(t *testing.T) {
+		// the contract parameters changed after the next epoch was set up
+		next := params
+		next.NumViewsInEpoch = 8000
+		for _, phase := range []flow.EpochPhase{flow.EpochPhaseSetup, flow.EpochPhaseCommitted} {
+			snapshot := simulateSnapshotFixtureWithNext(phase, params, next)
+			report, err := simulateEpochs(snapshot, params, latencies, 0, 3)
+			require.NoError(t, err)
+			assert.False(t, report.HasIssues())
+			require.Len(t, report.Epochs, 3)
+
+			nextEpoch := report.Epochs[1]
+			assert.Equal(t, uint64(2), nextEpoch.Counter)
+			assert.Equal(t, uint64(5100), nextEpoch.FirstView)
+			assert.Equal(t, uint64(13099), nextEpoch.FinalView)
+			assert.False(t, nextEpoch.AlreadySetup)
+			assert.NotZero(t, nextEpoch.CommitView)
+
+			// the epochs following the next epoch are set up by the contract parameters
+			assert.Equal(t, uint64(13100), report.Epochs[2].FirstView)
+			assert.Equal(t, uint64(18099), report.Epochs[2].FinalView)
+		}
+	})
+
+	t.Run("safety threshold below chain default", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseStaking, params)
+		report, err := simulateEpochs(snapshot, params, latencies, 50, 1)
+		require.NoError(t, err)
+		require.Len(t, report.Issues, 1)
+		assert.Contains(t, report.Issues[0], "epoch commit safety threshold smaller than expected")
+	})
+
+	t.Run("next epoch already committed", func(t *testing.T) {
+		snapshot := simulateSnapshotFixture(flow.EpochPhaseCommitted, params)
+		slow := latencies
+		slow.DKGResultViews = 5000
+		report, err := simulateEpochs(snapshot, params, slow, 0, 1)
+		require.NoError(t, err)
+		assert.False(t, report.HasIssues())
+		assert.True(t, report.Epochs[0].AlreadyCommitted)
+		assert.Zero(t, report.Epochs[0].CommitView)
+	})
+}
+
+// TestSimulate_LocalSnapshot tests the command with a local snapshot file.
+func TestSimulate_LocalSnapshot(t *testing.T) {
+	unittest.RunWithTempDir(t, func(bootDir string) {
+		params := epochContractParams{
+			NumViewsInEpoch:          5000,
+			NumViewsInStakingAuction: 1000,
+			NumViewsInDKGPhase:       500,
+		}
+		err := writeRootSnapshot(bootDir, simulateSnapshotFixture(flow.EpochPhaseStaking, params))
+		require.NoError(t, err)
+
+		// set initial flag values
+		flagBootDir = bootDir
+		flagBucketNetworkName = ""
+		flagSimulateNumEpochs = 2
+
+		// run command with overwritten stdout
+		stdout := bytes.NewBuffer(nil)
+		simulateCmd.SetOut(stdout)
+		simulateRun(simulateCmd, nil)
+
+		var report simulationReport
+		err = json.NewDecoder(stdout).Decode(&report)
+		require.NoError(t, err)
+		assert.Equal(t, params, report.Params)
+		assert.Len(t, report.Epochs, 2)
+		assert.False(t, report.HasIssues())
+	})
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+)
+
+var (
+	flagDatadir   string
+	flagPebbleDir string
+	flagBatchSize uint
+	flagNoVerify  bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "migrate-badger-to-pebble",
+	Short: "Copies a badger protocol database into a new Pebble database and verifies the copy",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the badger protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagPebbleDir, "pebble-dir", "",
+		"directory to create the Pebble database in, the database must not exist or be empty")
+	_ = Cmd.MarkFlagRequired("pebble-dir")
+
+	Cmd.Flags().UintVar(&flagBatchSize, "batch-size", 32,
+		"maximum size of the write batches in MiB")
+
+	Cmd.Flags().BoolVar(&flagNoVerify, "no-verify", false,
+		"skip verifying the Pebble database against the badger database after the copy")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().
+		Str("datadir", flagDatadir).
+		Str("pebble_dir", flagPebbleDir).
+		Uint("batch_size_mib", flagBatchSize).
+		Msg("flags")
+
+	if flagBatchSize == 0 {
+		log.Fatal().Msg("batch size must be above 0")
+	}
+
+	bdb := common.InitStorage(flagDatadir)
+	defer bdb.Close()
+
+	pdb, err := pebble.Open(flagPebbleDir, &pebble.Options{})
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not open pebble database")
+	}
+	defer pdb.Close()
+
+	copied, err := copyBadgerToPebble(log.Logger, bdb, pdb, int(flagBatchSize)<<20)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not copy badger database to pebble")
+	}
+	log.Info().Uint64("keys", copied).Msg("badger database copied to pebble")
+
+	if flagNoVerify {
+		log.Warn().Msg("skipping verification of the pebble database")
+		return
+	}
+
+	verified, err := verifyPebbleAgainstBadger(bdb, pdb)
+	if err != nil {
+		log.Fatal().Err(err).Msg("pebble database does not match badger database")
+	}
+	log.Info().Uint64("keys", verified).Msg("pebble database verified against badger database")
+}
+
+// copyBadgerToPebble copies all key-value pairs of the badger database into the Pebble database, in
+// write batches of at most batchSize bytes. The Pebble database must be empty.
+// Returns the number of copied key-value pairs.
+func copyBadgerToPebble(log zerolog.Logger, bdb *badger.DB, pdb *pebble.DB, batchSize int) (uint64, error) {
+	empty, err := isEmpty(pdb)
+	if err != nil {
+		return 0, fmt.Errorf("could not check pebble database: %w", err)
+	}
+	if !empty {
+		return 0, fmt.Errorf("pebble database is not empty")
+	}
+
+	var copied uint64
+	err = bdb.View(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		batch := pdb.NewBatch()
+		defer func() {
+			_ = batch.Close()
+		}()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("could not read value of key %x: %w", item.Key(), err)
+			}
+			err = batch.Set(item.KeyCopy(nil), val, nil)
+			if err != nil {
+				return fmt.Errorf("could not add key %x to batch: %w", item.Key(), err)
+			}
+			copied++
+
+			if batch.Len() < batchSize {
+				continue
+			}
+			err = batch.Commit(pebble.Sync)
+			if err != nil {
+				return fmt.Errorf("could not commit batch: %w", err)
+			}
+			_ = batch.Close()
+			batch = pdb.NewBatch()
+			log.Info().Uint64("keys", copied).Msg("copying badger database")
+		}
+
+		err := batch.Commit(pebble.Sync)
+		if err != nil {
+			return fmt.Errorf("could not commit batch: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return copied, err
+	}
+
+	return copied, nil
+}
+
+// verifyPebbleAgainstBadger iterates over both databases in key order, and checks they contain the
+// same key-value pairs. Returns the number of verified key-value pairs.
+func verifyPebbleAgainstBadger(bdb *badger.DB, pdb *pebble.DB) (uint64, error) {
+	snap := pdb.NewSnapshot()
+	defer snap.Close()
+
+	pit, err := snap.NewIter(nil)
+	if err != nil {
+		return 0, fmt.Errorf("could not create pebble iterator: %w", err)
+	}
+	defer pit.Close()
+
+	var verified uint64
+	err = bdb.View(func(tx *badger.Txn) error {
+		bit := tx.NewIterator(badger.DefaultIteratorOptions)
+		defer bit.Close()
+
+		pit.First()
+		for bit.Rewind(); bit.Valid(); bit.Next() {
+			item := bit.Item()
+			if !pit.Valid() {
+				return fmt.Errorf("key %x is missing in pebble database", item.Key())
+			}
+			if !bytes.Equal(item.Key(), pit.Key()) {
+				return fmt.Errorf("key mismatch: badger key %x, pebble key %x", item.Key(), pit.Key())
+			}
+			err := item.Value(func(val []byte) error {
+				if !bytes.Equal(val, pit.Value()) {
+					return fmt.Errorf("value mismatch for key %x", item.Key())
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			verified++
+			pit.Next()
+		}
+
+		if pit.Valid() {
+			return fmt.Errorf("key %x of pebble database does not exist in badger database", pit.Key())
+		}
+		return nil
+	})
+	if err != nil {
+		return verified, err
+	}
+
+	return verified, nil
+}
+
+// isEmpty returns true if the Pebble database does not contain any key.
+func isEmpty(pdb *pebble.DB) (bool, error) {
+	it, err := pdb.NewIter(nil)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	return !it.First(), nil
+}
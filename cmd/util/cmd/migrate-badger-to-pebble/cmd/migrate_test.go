@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	pebblestorage "github.com/onflow/flow-go/storage/pebble"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestMigrateBadgerToPebble(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(bdb *badger.DB) {
+		unittest.RunWithPebbleDB(t, func(pdb *pebble.DB) {
+			collector := metrics.NewNoopCollector()
+
+			// populate the badger database through the badger storage
+			bheaders := badgerstorage.NewHeaders(collector, bdb)
+			bcommits := badgerstorage.NewCommits(collector, bdb)
+			headers := make([]*flow.Header, 0, 10)
+			for i := 0; i < 10; i++ {
+				header := unittest.BlockHeaderFixture()
+				require.NoError(t, bheaders.Store(header))
+				require.NoError(t, bdb.Update(operation.IndexBlockHeight(header.Height, header.ID())))
+				require.NoError(t, bcommits.Store(header.ID(), unittest.StateCommitmentFixture()))
+				headers = append(headers, header)
+			}
+
+			// use a tiny batch size to exercise committing multiple batches
+			copied, err := copyBadgerToPebble(zerolog.Nop(), bdb, pdb, 64)
+			require.NoError(t, err)
+			require.Equal(t, uint64(30), copied)
+
+			verified, err := verifyPebbleAgainstBadger(bdb, pdb)
+			require.NoError(t, err)
+			require.Equal(t, copied, verified)
+
+			// the pebble storage reads the migrated data
+			pheaders := pebblestorage.NewHeaders(collector, pdb)
+			pcommits := pebblestorage.NewCommits(collector, pdb)
+			for _, header := range headers {
+				actual, err := pheaders.ByHeight(header.Height)
+				require.NoError(t, err)
+				require.Equal(t, header, actual)
+
+				expected, err := bcommits.ByBlockID(header.ID())
+				require.NoError(t, err)
+				commit, err := pcommits.ByBlockID(header.ID())
+				require.NoError(t, err)
+				require.Equal(t, expected, commit)
+			}
+
+			// copying into a non-empty database fails
+			_, err = copyBadgerToPebble(zerolog.Nop(), bdb, pdb, 64)
+			require.Error(t, err)
+
+			// verification detects divergent databases
+			batch := pdb.NewBatch()
+			require.NoError(t, batch.Set([]byte{0xff}, []byte{0x01}, nil))
+			require.NoError(t, batch.Commit(pebble.Sync))
+			_, err = verifyPebbleAgainstBadger(bdb, pdb)
+			require.Error(t, err)
+		})
+	})
+}
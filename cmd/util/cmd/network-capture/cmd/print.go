@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/channels"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+)
+
+var (
+	flagFile      string
+	flagChannels  []string
+	flagOrigins   []string
+	flagTypes     []string
+	flagDirection string
+	flagSince     string
+	flagUntil     string
+	flagDecode    bool
+)
+
+// example:
+// ./util network-capture print --file ./capture.bin --channels consensus-committee --types BlockProposal --decode
+var printCmd = &cobra.Command{
+	Use:   "print",
+	Short: "pretty-print the messages of a capture file matching the given filters",
+	Run:   runPrint,
+}
+
+func init() {
+	rootCmd.AddCommand(printCmd)
+
+	printCmd.Flags().StringVar(&flagFile, "file", "", "capture file to print")
+	_ = printCmd.MarkFlagRequired("file")
+	printCmd.Flags().StringSliceVar(&flagChannels, "channels", nil,
+		"only print messages on the given channels, cluster channels are selected by their prefix (optional)")
+	printCmd.Flags().StringSliceVar(&flagOrigins, "origins", nil, "only print messages sent by the given node IDs (optional)")
+	printCmd.Flags().StringSliceVar(&flagTypes, "types", nil, "only print messages of the given types, e.g., BlockProposal (optional)")
+	printCmd.Flags().StringVar(&flagDirection, "direction", "", "only print inbound or outbound messages (optional)")
+	printCmd.Flags().StringVar(&flagSince, "since", "", "only print messages captured at or after the given RFC3339 time (optional)")
+	printCmd.Flags().StringVar(&flagUntil, "until", "", "only print messages captured at or before the given RFC3339 time (optional)")
+	printCmd.Flags().BoolVar(&flagDecode, "decode", false, "print the decoded content of each message")
+}
+
+func runPrint(*cobra.Command, []string) {
+	filter, err := parseFilter()
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid filter")
+	}
+
+	file, err := os.Open(flagFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not open capture file")
+	}
+	defer file.Close()
+
+	count, err := PrintRecords(os.Stdout, capture.NewReader(file), filter, flagDecode)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not print capture file")
+	}
+	log.Info().Int("messages", count).Msg("capture file printed")
+}
+
+// parseFilter returns the filter of captured messages given by the command line flags.
+func parseFilter() (capture.Filter, error) {
+	filter := capture.Filter{
+		Types:     flagTypes,
+		Direction: capture.Direction(flagDirection),
+	}
+	switch filter.Direction {
+	case "", capture.DirectionInbound, capture.DirectionOutbound:
+	default:
+		return capture.Filter{}, fmt.Errorf("invalid direction %s, expected %s or %s", flagDirection, capture.DirectionInbound, capture.DirectionOutbound)
+	}
+
+	for _, name := range flagChannels {
+		filter.Channels = append(filter.Channels, channels.Channel(name))
+	}
+	for _, hex := range flagOrigins {
+		originID, err := flow.HexStringToIdentifier(hex)
+		if err != nil {
+			return capture.Filter{}, fmt.Errorf("malformed origin ID %s: %w", hex, err)
+		}
+		filter.Origins = append(filter.Origins, originID)
+	}
+
+	var err error
+	if flagSince != "" {
+		filter.Since, err = time.Parse(time.RFC3339, flagSince)
+		if err != nil {
+			return capture.Filter{}, fmt.Errorf("malformed since time: %w", err)
+		}
+	}
+	if flagUntil != "" {
+		filter.Until, err = time.Parse(time.RFC3339, flagUntil)
+		if err != nil {
+			return capture.Filter{}, fmt.Errorf("malformed until time: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// PrintRecords prints the records read by the given reader matching the filter to the given writer, one line per
+// record, followed by the decoded message as indented JSON if decode is set. Returns the number of printed records.
+// No errors are expected during normal operations, unless the capture file is invalid or the writer fails.
+func PrintRecords(w io.Writer, r *capture.Reader, filter capture.Filter, decode bool) (int, error) {
+	codec := cborcodec.NewCodec()
+	count := 0
+	for {
+		record, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		if !filter.Match(record) {
+			continue
+		}
+
+		_, err = fmt.Fprintf(w, "%s %-8s %-28s origin=%s type=%s size=%d targets=%d\n",
+			record.Timestamp.UTC().Format(time.RFC3339Nano),
+			record.Direction,
+			record.Channel,
+			record.OriginID,
+			record.MessageType,
+			len(record.Payload),
+			len(record.TargetIDs))
+		if err != nil {
+			return count, fmt.Errorf("could not print record: %w", err)
+		}
+		count++
+
+		if !decode {
+			continue
+		}
+		msg, err := record.Decode(codec)
+		if err != nil {
+			return count, err
+		}
+		content, err := json.MarshalIndent(msg, "  ", "  ")
+		if err != nil {
+			return count, fmt.Errorf("could not encode %s as json: %w", record.MessageType, err)
+		}
+		_, err = fmt.Fprintf(w, "  %s\n", content)
+		if err != nil {
+			return count, fmt.Errorf("could not print record: %w", err)
+		}
+	}
+}
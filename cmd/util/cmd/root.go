@@ -11,12 +11,16 @@ import (
 
 	checkpoint_collect_stats "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-collect-stats"
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
+	chunk_faults "github.com/onflow/flow-go/cmd/util/cmd/chunk-faults/cmd"
+	db_stats "github.com/onflow/flow-go/cmd/util/cmd/db-stats"
 	epochs "github.com/onflow/flow-go/cmd/util/cmd/epochs/cmd"
 	export "github.com/onflow/flow-go/cmd/util/cmd/exec-data-json-export"
 	edbs "github.com/onflow/flow-go/cmd/util/cmd/execution-data-blobstore/cmd"
 	extract "github.com/onflow/flow-go/cmd/util/cmd/execution-state-extract"
 	ledger_json_exporter "github.com/onflow/flow-go/cmd/util/cmd/export-json-execution-state"
 	export_json_transactions "github.com/onflow/flow-go/cmd/util/cmd/export-json-transactions"
+	migrate_badger_to_pebble "github.com/onflow/flow-go/cmd/util/cmd/migrate-badger-to-pebble/cmd"
+	network_capture "github.com/onflow/flow-go/cmd/util/cmd/network-capture/cmd"
 	read_badger "github.com/onflow/flow-go/cmd/util/cmd/read-badger/cmd"
 	read_execution_state "github.com/onflow/flow-go/cmd/util/cmd/read-execution-state"
 	read_hotstuff "github.com/onflow/flow-go/cmd/util/cmd/read-hotstuff/cmd"
@@ -25,6 +29,7 @@ import (
 	rollback_executed_height "github.com/onflow/flow-go/cmd/util/cmd/rollback-executed-height/cmd"
 	"github.com/onflow/flow-go/cmd/util/cmd/snapshot"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
+	verify_snapshot "github.com/onflow/flow-go/cmd/util/cmd/verify-snapshot"
 )
 
 var (
@@ -76,6 +81,11 @@ func addCommands() {
 	rootCmd.AddCommand(snapshot.Cmd)
 	rootCmd.AddCommand(export_json_transactions.Cmd)
 	rootCmd.AddCommand(read_hotstuff.RootCmd)
+	rootCmd.AddCommand(chunk_faults.RootCmd)
+	rootCmd.AddCommand(network_capture.RootCmd)
+	rootCmd.AddCommand(migrate_badger_to_pebble.Cmd)
+	rootCmd.AddCommand(db_stats.Cmd)
+	rootCmd.AddCommand(verify_snapshot.Cmd)
 }
 
 func initConfig() {
@@ -0,0 +1,55 @@
+package verify_snapshot
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+)
+
+var (
+	flagSnapshot       string
+	flagVerifyResultID bool
+)
+
+// This command verifies a protocol state snapshot offline, before it is used to bootstrap a node.
+// The snapshot is the JSON encoded inmem.Snapshot, as returned by the access API
+// GetLatestProtocolStateSnapshot or written by the `snapshot` command.
+// The command prints a report of all checks and exits with a non-zero code if any check failed.
+
+var Cmd = &cobra.Command{
+	Use:   "verify-snapshot",
+	Short: "Verifies a protocol state snapshot before it is used to bootstrap a node",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagSnapshot, "snapshot", "",
+		"path to the JSON encoded protocol state snapshot")
+	_ = Cmd.MarkFlagRequired("snapshot")
+
+	Cmd.Flags().BoolVar(&flagVerifyResultID, "verify-result-id", true,
+		"verify that the seal of the sealed result references the ID of the sealed result")
+}
+
+func run(*cobra.Command, []string) {
+	bz, err := os.ReadFile(flagSnapshot)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read snapshot file")
+	}
+
+	snapshot, err := convert.BytesToInmemSnapshot(bz)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not decode snapshot")
+	}
+
+	results := Verify(snapshot, flagVerifyResultID)
+	failed := WriteReport(os.Stdout, snapshot, results)
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "snapshot verification failed: %d of %d checks failed\n", failed, len(results))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,539 @@
+package verify_snapshot
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/signature"
+	"github.com/onflow/flow-go/consensus/hotstuff/validator"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/state/protocol"
+	badgerState "github.com/onflow/flow-go/state/protocol/badger"
+)
+
+// CheckResult is the outcome of a single check of a snapshot. Err is nil if the check passed.
+type CheckResult struct {
+	Name string
+	Err  error
+}
+
+// check is a single named verification of a snapshot.
+type check struct {
+	name   string
+	verify func(snapshot protocol.Snapshot) error
+}
+
+// Verify runs all checks against the given snapshot and returns their outcome in order.
+// A failing check does not prevent the subsequent checks from running, so that the report
+// lists all problems of the snapshot at once.
+func Verify(snapshot protocol.Snapshot, verifyResultID bool) []CheckResult {
+	checks := []check{
+		{name: "sealing segment structure", verify: verifySealingSegment},
+		{name: "header chain", verify: verifyHeaderChain},
+		{name: "seal chain", verify: verifySealChain},
+		{name: "sealed result and commit", verify: verifySealedResult},
+		{name: "root snapshot consistency", verify: func(snapshot protocol.Snapshot) error {
+			return badgerState.IsValidRootSnapshot(snapshot, verifyResultID)
+		}},
+		{name: "epoch service events", verify: verifyEpochs},
+		{name: "quorum certificates", verify: badgerState.IsValidRootSnapshotQCs},
+		{name: "segment quorum certificates", verify: verifySegmentQCs},
+	}
+
+	results := make([]CheckResult, 0, len(checks))
+	for _, c := range checks {
+		results = append(results, CheckResult{
+			Name: c.name,
+			Err:  c.verify(snapshot),
+		})
+	}
+	return results
+}
+
+// WriteReport writes a human-readable report of the snapshot and the outcome of all checks.
+// Returns the number of failed checks.
+func WriteReport(out io.Writer, snapshot protocol.Snapshot, results []CheckResult) int {
+	fmt.Fprintln(out, "protocol state snapshot")
+	if head, err := snapshot.Head(); err == nil {
+		fmt.Fprintf(out, "  head:          height %d, view %d, id %x\n", head.Height, head.View, head.ID())
+	}
+	if segment, err := snapshot.SealingSegment(); err == nil && len(segment.Blocks) > 0 {
+		sealed := segment.Sealed().Header
+		fmt.Fprintf(out, "  sealed:        height %d, view %d, id %x\n", sealed.Height, sealed.View, sealed.ID())
+		fmt.Fprintf(out, "  segment:       %d blocks, %d extra blocks\n", len(segment.Blocks), len(segment.ExtraBlocks))
+	}
+	if counter, err := snapshot.Epochs().Current().Counter(); err == nil {
+		fmt.Fprintf(out, "  epoch:         %d\n", counter)
+	}
+	if phase, err := snapshot.Phase(); err == nil {
+		fmt.Fprintf(out, "  epoch phase:   %s\n", phase)
+	}
+	fmt.Fprintln(out)
+
+	failed := 0
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, result := range results {
+		if result.Err == nil {
+			fmt.Fprintf(w, "[PASS]\t%s\t\n", result.Name)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "[FAIL]\t%s\t%v\n", result.Name, result.Err)
+	}
+	_ = w.Flush()
+
+	fmt.Fprintf(out, "\n%d checks, %d passed, %d failed\n", len(results), len(results)-failed, failed)
+	return failed
+}
+
+// verifySealingSegment checks the structure of the sealing segment, by re-building it.
+func verifySealingSegment(snapshot protocol.Snapshot) error {
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return fmt.Errorf("could not get sealing segment: %w", err)
+	}
+	return segment.Validate()
+}
+
+// verifyHeaderChain checks that the blocks of the sealing segment, including the extra blocks,
+// form a chain of finalized blocks leading up to the head of the snapshot, where each block
+// references its parent and commits to its payload.
+func verifyHeaderChain(snapshot protocol.Snapshot) error {
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return fmt.Errorf("could not get sealing segment: %w", err)
+	}
+	if len(segment.Blocks) == 0 {
+		return fmt.Errorf("sealing segment has no blocks")
+	}
+
+	blocks := make([]*flow.Block, 0, len(segment.ExtraBlocks)+len(segment.Blocks))
+	blocks = append(blocks, segment.ExtraBlocks...)
+	blocks = append(blocks, segment.Blocks...)
+
+	for i, block := range blocks {
+		header := block.Header
+		if header.PayloadHash != block.Payload.Hash() {
+			return fmt.Errorf("block %x (height=%d) does not commit to its payload", header.ID(), header.Height)
+		}
+		if i == 0 {
+			continue
+		}
+
+		parent := blocks[i-1].Header
+		if header.ParentID != parent.ID() {
+			return fmt.Errorf("block %x (height=%d) does not reference the previous block %x as parent", header.ID(), header.Height, parent.ID())
+		}
+		if header.Height != parent.Height+1 {
+			return fmt.Errorf("block %x has height %d, expected %d", header.ID(), header.Height, parent.Height+1)
+		}
+		if header.ParentView != parent.View || header.View <= parent.View {
+			return fmt.Errorf("block %x (height=%d) has inconsistent views (view=%d, parent view=%d, actual parent view=%d)",
+				header.ID(), header.Height, header.View, header.ParentView, parent.View)
+		}
+		if header.ChainID != parent.ChainID {
+			return fmt.Errorf("block %x (height=%d) is on chain %s, expected %s", header.ID(), header.Height, header.ChainID, parent.ChainID)
+		}
+	}
+
+	head, err := snapshot.Head()
+	if err != nil {
+		return fmt.Errorf("could not get head: %w", err)
+	}
+	if head.ID() != segment.Highest().ID() {
+		return fmt.Errorf("head %x is not the highest block %x of the sealing segment", head.ID(), segment.Highest().ID())
+	}
+
+	return nil
+}
+
+// verifySegmentQCs checks the quorum certificate in the header of each block of the sealing segment,
+// which certifies the parent of the block, against the consensus committee of the epoch containing
+// the certified view. The spork root block contains no quorum certificate and is skipped. The extra
+// blocks are not checked, as they may belong to epochs which are not part of the snapshot.
+func verifySegmentQCs(snapshot protocol.Snapshot) error {
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return fmt.Errorf("could not get sealing segment: %w", err)
+	}
+	sporkRootHeight, err := snapshot.Params().SporkRootBlockHeight()
+	if err != nil {
+		return fmt.Errorf("could not get spork root block height: %w", err)
+	}
+
+	// validators of the epochs containing the certified views, by epoch counter
+	validators := make(map[uint64]hotstuff.Validator)
+	for _, block := range segment.Blocks {
+		header := block.Header
+		if header.Height == sporkRootHeight {
+			continue
+		}
+
+		epoch, err := epochAtView(snapshot, header.ParentView)
+		if err != nil {
+			return fmt.Errorf("could not get epoch of parent of block %x (height=%d): %w", header.ID(), header.Height, err)
+		}
+		counter, err := epoch.Counter()
+		if err != nil {
+			return fmt.Errorf("could not get epoch counter: %w", err)
+		}
+		qcValidator, ok := validators[counter]
+		if !ok {
+			qcValidator, err = epochQCValidator(epoch)
+			if err != nil {
+				return fmt.Errorf("could not create qc validator for epoch %d: %w", counter, err)
+			}
+			validators[counter] = qcValidator
+		}
+
+		err = qcValidator.ValidateQC(header.QuorumCertificate())
+		if err != nil {
+			return fmt.Errorf("invalid quorum certificate in block %x (height=%d): %w", header.ID(), header.Height, err)
+		}
+	}
+	return nil
+}
+
+// epochAtView returns the epoch of the snapshot containing the given view. Only the previous, current
+// and committed next epoch are considered, as the snapshot contains their committees.
+func epochAtView(snapshot protocol.Snapshot, view uint64) (protocol.Epoch, error) {
+	epochs := []protocol.Epoch{snapshot.Epochs().Current()}
+
+	previousExists, err := protocol.PreviousEpochExists(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("could not check for previous epoch: %w", err)
+	}
+	if previousExists {
+		epochs = append(epochs, snapshot.Epochs().Previous())
+	}
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch phase: %w", err)
+	}
+	if phase == flow.EpochPhaseCommitted {
+		epochs = append(epochs, snapshot.Epochs().Next())
+	}
+
+	for _, epoch := range epochs {
+		firstView, err := epoch.FirstView()
+		if err != nil {
+			return nil, fmt.Errorf("could not get first view: %w", err)
+		}
+		finalView, err := epoch.FinalView()
+		if err != nil {
+			return nil, fmt.Errorf("could not get final view: %w", err)
+		}
+		if firstView <= view && view <= finalView {
+			return epoch, nil
+		}
+	}
+	return nil, fmt.Errorf("no epoch of the snapshot contains view %d", view)
+}
+
+// epochQCValidator returns a validator of quorum certificates signed by the consensus committee of
+// the given epoch, which must be committed.
+func epochQCValidator(epoch protocol.Epoch) (hotstuff.Validator, error) {
+	identities, err := epoch.InitialIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("could not get identities: %w", err)
+	}
+	dkg, err := epoch.DKG()
+	if err != nil {
+		return nil, fmt.Errorf("could not get dkg: %w", err)
+	}
+	committee, err := committees.NewStaticCommitteeWithDKG(identities.Filter(filter.IsVotingConsensusCommitteeMember), flow.ZeroID, dkg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create committee: %w", err)
+	}
+	verifier := verification.NewCombinedVerifier(committee, signature.NewConsensusSigDataPacker(committee))
+	return validator.New(committee, verifier), nil
+}
+
+// verifySealChain checks that the latest seal as of each block of the sealing segment is consistent
+// with the seals included in the block payloads, that the sealed heights never decrease along the
+// chain, and that each seal commits to the final state of the result it seals, if the result is known.
+func verifySealChain(snapshot protocol.Snapshot) error {
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return fmt.Errorf("could not get sealing segment: %w", err)
+	}
+
+	heights := make(map[flow.Identifier]uint64)
+	results := segment.ExecutionResults.Lookup()
+	for _, block := range append(append([]*flow.Block{}, segment.ExtraBlocks...), segment.Blocks...) {
+		heights[block.ID()] = block.Header.Height
+		for _, result := range block.Payload.Results {
+			results[result.ID()] = result
+		}
+	}
+
+	latest := segment.FirstSeal
+	for _, block := range segment.Blocks {
+		blockID := block.ID()
+		sealID, ok := segment.LatestSeals[blockID]
+		if !ok {
+			return fmt.Errorf("no latest seal for block %x (height=%d)", blockID, block.Header.Height)
+		}
+
+		// the latest seal is either included in the block, or it is the latest seal as of the parent
+		var seal *flow.Seal
+		for _, included := range block.Payload.Seals {
+			if included.ID() == sealID {
+				seal = included
+			}
+		}
+		if seal == nil {
+			if len(block.Payload.Seals) > 0 {
+				return fmt.Errorf("latest seal %x of block %x (height=%d) is not included in the block, which contains seals", sealID, blockID, block.Header.Height)
+			}
+			if latest == nil || latest.ID() != sealID {
+				return fmt.Errorf("latest seal %x of block %x (height=%d) is neither included in the block nor the latest seal of its parent", sealID, blockID, block.Header.Height)
+			}
+			seal = latest
+		}
+
+		// sealed heights never decrease, this is only checked if both sealed blocks are in the segment
+		if latest != nil {
+			previousHeight, ok1 := heights[latest.BlockID]
+			height, ok2 := heights[seal.BlockID]
+			if ok1 && ok2 && height < previousHeight {
+				return fmt.Errorf("latest sealed height decreases from %d to %d at block %x", previousHeight, height, blockID)
+			}
+		}
+
+		for _, included := range block.Payload.Seals {
+			err := verifySealCommitment(included, results)
+			if err != nil {
+				return fmt.Errorf("invalid seal in block %x (height=%d): %w", blockID, block.Header.Height, err)
+			}
+		}
+		latest = seal
+	}
+
+	if latest == nil {
+		return fmt.Errorf("sealing segment has no seal")
+	}
+	if latest.BlockID != segment.Sealed().ID() {
+		return fmt.Errorf("latest seal of head seals block %x, expected lowest block %x of the sealing segment", latest.BlockID, segment.Sealed().ID())
+	}
+	return verifySealCommitment(latest, results)
+}
+
+// verifySealCommitment checks that the seal is for the block of the sealed result and commits to its
+// final state. Seals of results which are not known are not checked.
+func verifySealCommitment(seal *flow.Seal, results map[flow.Identifier]*flow.ExecutionResult) error {
+	result, ok := results[seal.ResultID]
+	if !ok {
+		return nil
+	}
+	if result.BlockID != seal.BlockID {
+		return fmt.Errorf("seal %x is for block %x, but its result is for block %x", seal.ID(), seal.BlockID, result.BlockID)
+	}
+	commit, err := result.FinalStateCommitment()
+	if err != nil {
+		return fmt.Errorf("could not get final state of result %x: %w", result.ID(), err)
+	}
+	if commit != seal.FinalState {
+		return fmt.Errorf("seal %x commits to state %x, but its result has final state %x", seal.ID(), seal.FinalState, commit)
+	}
+	return nil
+}
+
+// verifySealedResult checks that the sealed result is sealed by the seal of the snapshot, and that the
+// state commitment of the snapshot is the final state of the sealed result.
+func verifySealedResult(snapshot protocol.Snapshot) error {
+	result, seal, err := snapshot.SealedResult()
+	if err != nil {
+		return fmt.Errorf("could not get sealed result: %w", err)
+	}
+	if result.BlockID != seal.BlockID {
+		return fmt.Errorf("sealed result is for block %x, but seal is for block %x", result.BlockID, seal.BlockID)
+	}
+
+	commit, err := result.FinalStateCommitment()
+	if err != nil {
+		return fmt.Errorf("could not get final state of sealed result: %w", err)
+	}
+	if commit != seal.FinalState {
+		return fmt.Errorf("sealed result has final state %x, but seal commits to %x", commit, seal.FinalState)
+	}
+
+	snapshotCommit, err := snapshot.Commit()
+	if err != nil {
+		return fmt.Errorf("could not get state commitment: %w", err)
+	}
+	if snapshotCommit != commit {
+		return fmt.Errorf("snapshot state commitment %x does not match final state %x of sealed result", snapshotCommit, commit)
+	}
+	return nil
+}
+
+// verifyEpochs checks that the epoch setup and commit events of the previous, current and next
+// epoch, as far as they are available, are consistent with each other.
+func verifyEpochs(snapshot protocol.Snapshot) error {
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return fmt.Errorf("could not get epoch phase: %w", err)
+	}
+
+	current := snapshot.Epochs().Current()
+	err = verifyEpoch(current, true)
+	if err != nil {
+		return fmt.Errorf("invalid current epoch: %w", err)
+	}
+
+	previousExists, err := protocol.PreviousEpochExists(snapshot)
+	if err != nil {
+		return fmt.Errorf("could not check for previous epoch: %w", err)
+	}
+	if previousExists {
+		previous := snapshot.Epochs().Previous()
+		err = verifyEpoch(previous, true)
+		if err != nil {
+			return fmt.Errorf("invalid previous epoch: %w", err)
+		}
+		err = verifyEpochTransition(previous, current)
+		if err != nil {
+			return fmt.Errorf("invalid transition from previous to current epoch: %w", err)
+		}
+	}
+
+	if phase == flow.EpochPhaseStaking {
+		return nil
+	}
+	next := snapshot.Epochs().Next()
+	err = verifyEpoch(next, phase == flow.EpochPhaseCommitted)
+	if err != nil {
+		return fmt.Errorf("invalid next epoch: %w", err)
+	}
+	err = verifyEpochTransition(current, next)
+	if err != nil {
+		return fmt.Errorf("invalid transition from current to next epoch: %w", err)
+	}
+	return nil
+}
+
+// verifyEpoch checks the internal consistency of the epoch setup and, if committed, of the epoch
+// commit event of the given epoch.
+func verifyEpoch(epoch protocol.Epoch, committed bool) error {
+	counter, err := epoch.Counter()
+	if err != nil {
+		return fmt.Errorf("could not get counter: %w", err)
+	}
+
+	views := make([]uint64, 0, 5)
+	for _, view := range []func() (uint64, error){
+		epoch.FirstView, epoch.DKGPhase1FinalView, epoch.DKGPhase2FinalView, epoch.DKGPhase3FinalView, epoch.FinalView,
+	} {
+		v, err := view()
+		if err != nil {
+			return fmt.Errorf("could not get views of epoch %d: %w", counter, err)
+		}
+		views = append(views, v)
+	}
+	for i := 1; i < len(views); i++ {
+		if views[i] <= views[i-1] {
+			return fmt.Errorf("epoch %d views are not increasing (first view, dkg phase final views, final view): %v", counter, views)
+		}
+	}
+
+	identities, err := epoch.InitialIdentities()
+	if err != nil {
+		return fmt.Errorf("could not get identities of epoch %d: %w", counter, err)
+	}
+	if !identities.Sorted(order.Canonical) {
+		return fmt.Errorf("identities of epoch %d are not canonically ordered", counter)
+	}
+
+	clustering, err := epoch.Clustering()
+	if err != nil {
+		return fmt.Errorf("could not get clustering of epoch %d: %w", counter, err)
+	}
+	for i, cluster := range clustering {
+		for _, member := range cluster {
+			identity, ok := identities.ByNodeID(member.NodeID)
+			if !ok || identity.Role != flow.RoleCollection {
+				return fmt.Errorf("cluster %d of epoch %d contains node %x, which is not a collection node of the epoch", i, counter, member.NodeID)
+			}
+		}
+	}
+
+	if !committed {
+		return nil
+	}
+
+	for i := range clustering {
+		cluster, err := epoch.Cluster(uint(i))
+		if err != nil {
+			return fmt.Errorf("could not get cluster %d of epoch %d: %w", i, counter, err)
+		}
+		if cluster.RootQC() == nil {
+			return fmt.Errorf("cluster %d of epoch %d has no root qc", i, counter)
+		}
+	}
+
+	dkg, err := epoch.DKG()
+	if err != nil {
+		return fmt.Errorf("could not get dkg of epoch %d: %w", counter, err)
+	}
+	if dkg.GroupKey() == nil {
+		return fmt.Errorf("dkg of epoch %d has no group key", counter)
+	}
+	participants := identities.Filter(filter.IsValidDKGParticipant)
+	if dkg.Size() != uint(len(participants)) {
+		return fmt.Errorf("dkg of epoch %d has %d participants, but the epoch has %d consensus nodes", counter, dkg.Size(), len(participants))
+	}
+	indices := make(map[uint]flow.Identifier, len(participants))
+	for _, participant := range participants {
+		index, err := dkg.Index(participant.NodeID)
+		if err != nil {
+			return fmt.Errorf("consensus node %x is not a dkg participant of epoch %d: %w", participant.NodeID, counter, err)
+		}
+		if index >= dkg.Size() {
+			return fmt.Errorf("consensus node %x has dkg index %d out of range in epoch %d", participant.NodeID, index, counter)
+		}
+		if other, ok := indices[index]; ok {
+			return fmt.Errorf("consensus nodes %x and %x have the same dkg index %d in epoch %d", other, participant.NodeID, index, counter)
+		}
+		indices[index] = participant.NodeID
+		if _, err := dkg.KeyShare(participant.NodeID); err != nil {
+			return fmt.Errorf("consensus node %x has no dkg key share in epoch %d: %w", participant.NodeID, counter, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyEpochTransition checks that the second epoch directly follows the first epoch.
+func verifyEpochTransition(first, second protocol.Epoch) error {
+	firstCounter, err := first.Counter()
+	if err != nil {
+		return fmt.Errorf("could not get counter: %w", err)
+	}
+	secondCounter, err := second.Counter()
+	if err != nil {
+		return fmt.Errorf("could not get counter: %w", err)
+	}
+	if secondCounter != firstCounter+1 {
+		return fmt.Errorf("epoch counter %d does not follow %d", secondCounter, firstCounter)
+	}
+
+	finalView, err := first.FinalView()
+	if err != nil {
+		return fmt.Errorf("could not get final view: %w", err)
+	}
+	firstView, err := second.FirstView()
+	if err != nil {
+		return fmt.Errorf("could not get first view: %w", err)
+	}
+	if firstView != finalView+1 {
+		return fmt.Errorf("epoch %d starts at view %d, but epoch %d ends at view %d", secondCounter, firstView, firstCounter, finalView)
+	}
+	return nil
+}
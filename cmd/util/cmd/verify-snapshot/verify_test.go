@@ -0,0 +1,237 @@
+package verify_snapshot
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bootstrapDKG "github.com/onflow/flow-go/cmd/bootstrap/dkg"
+	bootstrapRun "github.com/onflow/flow-go/cmd/bootstrap/run"
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/factory"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/module/signature"
+	clusterstate "github.com/onflow/flow-go/state/cluster"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestVerify(t *testing.T) {
+	participants := unittest.CompleteIdentitySet()
+
+	t.Run("valid snapshot", func(t *testing.T) {
+		snapshot := signedSnapshotFixture(t)
+		for _, result := range Verify(snapshot, true) {
+			assert.NoError(t, result.Err, result.Name)
+		}
+	})
+
+	t.Run("tampered quorum certificate of head", func(t *testing.T) {
+		enc := signedSnapshotFixture(t).Encodable()
+		enc.QuorumCertificate.SigData = tamper(enc.QuorumCertificate.SigData)
+
+		results := Verify(inmem.SnapshotFromEncodable(enc), true)
+		requireFailed(t, results, "quorum certificates")
+	})
+
+	t.Run("tampered quorum certificate in segment block", func(t *testing.T) {
+		snapshot := signedSnapshotFixture(t, func(header *flow.Header) {
+			header.ParentVoterSigData = tamper(header.ParentVoterSigData)
+		})
+
+		for _, result := range Verify(snapshot, true) {
+			if result.Name == "segment quorum certificates" {
+				assert.Error(t, result.Err)
+				continue
+			}
+			assert.NoError(t, result.Err, result.Name)
+		}
+	})
+
+	t.Run("seal does not commit to the final state of the sealed result", func(t *testing.T) {
+		enc := unittest.RootSnapshotFixture(participants).Encodable()
+		enc.LatestSeal.FinalState = unittest.StateCommitmentFixture()
+
+		results := Verify(inmem.SnapshotFromEncodable(enc), true)
+		requireFailed(t, results, "sealed result and commit")
+	})
+
+	t.Run("head is not the highest block of the sealing segment", func(t *testing.T) {
+		enc := unittest.RootSnapshotFixture(participants).Encodable()
+		enc.Head = unittest.BlockHeaderWithParentFixture(enc.Head)
+
+		results := Verify(inmem.SnapshotFromEncodable(enc), true)
+		requireFailed(t, results, "header chain")
+	})
+
+	t.Run("dkg does not match consensus participants", func(t *testing.T) {
+		enc := unittest.RootSnapshotFixture(participants).Encodable()
+		for nodeID := range enc.Epochs.Current.DKG.Participants {
+			delete(enc.Epochs.Current.DKG.Participants, nodeID)
+			break
+		}
+
+		results := Verify(inmem.SnapshotFromEncodable(enc), true)
+		requireFailed(t, results, "epoch service events")
+	})
+
+	t.Run("epoch views are not increasing", func(t *testing.T) {
+		enc := unittest.RootSnapshotFixture(participants).Encodable()
+		enc.Epochs.Current.DKGPhase2FinalView = enc.Epochs.Current.DKGPhase1FinalView
+
+		results := Verify(inmem.SnapshotFromEncodable(enc), true)
+		requireFailed(t, results, "epoch service events")
+	})
+}
+
+func TestWriteReport(t *testing.T) {
+	snapshot := unittest.RootSnapshotFixture(unittest.CompleteIdentitySet())
+	results := []CheckResult{
+		{Name: "passing check"},
+		{Name: "failing check", Err: assert.AnError},
+	}
+
+	var out bytes.Buffer
+	failed := WriteReport(&out, snapshot, results)
+	assert.Equal(t, 1, failed)
+
+	report := out.String()
+	assert.Contains(t, report, "[PASS]  passing check")
+	assert.Contains(t, report, "[FAIL]  failing check")
+	assert.Contains(t, report, assert.AnError.Error())
+	assert.Contains(t, report, "2 checks, 1 passed, 1 failed")
+}
+
+// requireFailed requires the check with the given name to have failed.
+func requireFailed(t *testing.T, results []CheckResult, name string) {
+	for _, result := range results {
+		if result.Name == name {
+			require.Error(t, result.Err)
+			return
+		}
+	}
+	require.Fail(t, "check not found", name)
+}
+
+// signedSnapshotFixture returns a snapshot in which all quorum certificates are signed by the consensus
+// committee or collection cluster of the epoch. The sealing segment consists of the spork root block
+// and a child of the root block, whose header contains the quorum certificate of the root block.
+// The given options are applied to the header of the child before its quorum certificate is signed.
+func signedSnapshotFixture(t *testing.T, opts ...func(*flow.Header)) *inmem.Snapshot {
+	consensus := unittest.PrivateNodeInfosFixture(3, unittest.WithRole(flow.RoleConsensus))
+	sort.Slice(consensus, func(i, j int) bool {
+		return order.IdentifierCanonical(consensus[i].NodeID, consensus[j].NodeID)
+	})
+	collectors := unittest.PrivateNodeInfosFixture(2, unittest.WithRole(flow.RoleCollection))
+
+	identities := make(flow.IdentityList, 0, len(consensus)+len(collectors))
+	for _, node := range append(consensus, collectors...) {
+		identities = append(identities, node.Identity())
+	}
+	participants := unittest.CompleteIdentitySet(identities...).Sort(order.Canonical)
+
+	// run the random beacon key generation for the consensus nodes, in canonical order
+	dkg, err := bootstrapDKG.RandomBeaconKG(len(consensus), unittest.RandomBytes(48))
+	require.NoError(t, err)
+	participantData := &bootstrapRun.ParticipantData{
+		Lookup:   make(map[flow.Identifier]flow.DKGParticipant),
+		GroupKey: dkg.PubGroupKey,
+	}
+	for i, node := range consensus {
+		participantData.Participants = append(participantData.Participants, bootstrapRun.Participant{
+			NodeInfo:            node,
+			RandomBeaconPrivKey: dkg.PrivKeyShares[i],
+		})
+		participantData.Lookup[node.NodeID] = flow.DKGParticipant{
+			Index:    uint(i),
+			KeyShare: dkg.PubKeyShares[i],
+		}
+	}
+
+	root := unittest.GenesisFixture()
+	counter := uint64(1)
+	setup := unittest.EpochSetupFixture(
+		unittest.WithParticipants(participants),
+		unittest.SetupWithCounter(counter),
+		unittest.WithFirstView(root.Header.View),
+		unittest.WithFinalView(root.Header.View+1000),
+	)
+
+	// sign the root QC of each collection cluster by its members
+	clusters, err := factory.NewClusterList(setup.Assignments, participants.Filter(filter.HasRole(flow.RoleCollection)))
+	require.NoError(t, err)
+	clusterQCs := make([]*flow.QuorumCertificateWithSignerIDs, 0, len(clusters))
+	for _, cluster := range clusters {
+		signers := make([]bootstrap.NodeInfo, 0, len(cluster))
+		for _, node := range collectors {
+			if _, ok := cluster.ByNodeID(node.NodeID); ok {
+				signers = append(signers, node)
+			}
+		}
+		qc, err := bootstrapRun.GenerateClusterRootQC(signers, cluster, clusterstate.CanonicalRootBlock(counter, cluster))
+		require.NoError(t, err)
+		signerIDs, err := signature.DecodeSignerIndicesToIdentifiers(cluster.NodeIDs(), qc.SignerIndices)
+		require.NoError(t, err)
+		clusterQCs = append(clusterQCs, &flow.QuorumCertificateWithSignerIDs{
+			View:      qc.View,
+			BlockID:   qc.BlockID,
+			SignerIDs: signerIDs,
+			SigData:   qc.SigData,
+		})
+	}
+
+	commit := unittest.EpochCommitFixture(
+		unittest.CommitWithCounter(counter),
+		func(commit *flow.EpochCommit) {
+			commit.ClusterQCs = flow.ClusterQCVoteDatasFromQCs(clusterQCs)
+			commit.DKGGroupKey = dkg.PubGroupKey
+			commit.DKGParticipantKeys = dkg.PubKeyShares
+		},
+	)
+	result := unittest.BootstrapExecutionResultFixture(root, unittest.GenesisStateCommitment)
+	result.ServiceEvents = []flow.ServiceEvent{setup.ServiceEvent(), commit.ServiceEvent()}
+	seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+
+	rootQC := signedQCFixture(t, root, participantData)
+	rootSnapshot, err := inmem.SnapshotFromBootstrapState(root, result, seal, rootQC)
+	require.NoError(t, err)
+
+	// extend the sealing segment with a child of the root block, certified by the committee
+	child := unittest.BlockWithParentFixture(root.Header)
+	child.SetPayload(flow.EmptyPayload())
+	child.Header.ParentVoterIndices = rootQC.SignerIndices
+	child.Header.ParentVoterSigData = rootQC.SigData
+	for _, apply := range opts {
+		apply(child.Header)
+	}
+
+	enc := rootSnapshot.Encodable()
+	enc.Head = child.Header
+	enc.SealingSegment.Blocks = append(enc.SealingSegment.Blocks, child)
+	enc.SealingSegment.LatestSeals[child.ID()] = seal.ID()
+	enc.QuorumCertificate = signedQCFixture(t, child, participantData)
+	return inmem.SnapshotFromEncodable(enc)
+}
+
+// signedQCFixture returns a quorum certificate for the given block, signed by all consensus participants.
+func signedQCFixture(t *testing.T, block *flow.Block, participantData *bootstrapRun.ParticipantData) *flow.QuorumCertificate {
+	votes, err := bootstrapRun.GenerateRootBlockVotes(block, participantData)
+	require.NoError(t, err)
+	qc, invalidVotes, err := bootstrapRun.GenerateRootQC(block, votes, participantData, participantData.Identities())
+	require.NoError(t, err)
+	require.Empty(t, invalidVotes)
+	return qc
+}
+
+// tamper returns a copy of the given signature data with its last byte modified.
+func tamper(sigData []byte) []byte {
+	tampered := make([]byte, len(sigData))
+	copy(tampered, sigData)
+	tampered[len(tampered)-1] ^= 1
+	return tampered
+}
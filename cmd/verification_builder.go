@@ -51,6 +51,7 @@ type VerificationConfig struct {
 
 	blockWorkers uint64 // number of blocks processed in parallel.
 	chunkWorkers uint64 // number of chunks processed in parallel.
+	cpuBudget    uint   // maximum number of chunks verified in parallel, 0 for one per CPU.
 
 	stopAtHeight uint64 // height to stop the node on
 }
@@ -79,6 +80,7 @@ func (v *VerificationNodeBuilder) LoadFlags() {
 			flags.Uint64Var(&v.verConf.requestTargets, "request-targets", requester.DefaultRequestTargets, "maximum number of execution nodes a chunk data pack request is dispatched to")
 			flags.Uint64Var(&v.verConf.blockWorkers, "block-workers", blockconsumer.DefaultBlockWorkers, "maximum number of blocks being processed in parallel")
 			flags.Uint64Var(&v.verConf.chunkWorkers, "chunk-workers", chunkconsumer.DefaultChunkWorkers, "maximum number of execution nodes a chunk data pack request is dispatched to")
+			flags.UintVar(&v.verConf.cpuBudget, "chunk-verification-cpu-budget", 0, "maximum number of chunks verified in parallel, chunks fetched beyond this budget are verified in order of sealing urgency (0 for one per CPU)")
 			flags.Uint64Var(&v.verConf.stopAtHeight, "stop-at-height", 0, "height to stop the node at (0 to disable)")
 		})
 }
@@ -109,6 +111,7 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 	)
 
 	v.FlowNodeBuilder.
+		PreInit(PeerBootstrapPreInit).
 		PreInit(DynamicStartPreInit).
 		Module("mutable follower state", func(node *NodeConfig) error {
 			var err error
@@ -127,7 +130,10 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 				node.Storage.Payloads,
 				blocktimer.DefaultBlockTimer,
 			)
-			return err
+			if err != nil {
+				return err
+			}
+			return ExtendRootFollowingBlocks(node, followerState)
 		}).
 		Module("verification metrics", func(node *NodeConfig) error {
 			collector = metrics.NewVerificationCollector(node.Tracer, node.MetricsRegisterer)
@@ -205,7 +211,10 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 				node.State,
 				node.Me,
 				chunkVerifier,
-				approvalStorage)
+				approvalStorage,
+				badger.NewChunkFaultReports(node.DB),
+				verifier.WithCPUBudget(v.verConf.cpuBudget),
+				verifier.WithApprovalCounter(verifier.NewStoredApprovalCounter(approvalStorage)))
 			return verifierEng, err
 		}).
 		Component("chunk consumer, requester, and fetcher engines", func(node *NodeConfig) (module.ReadyDoneAware, error) {
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"sync"
 
-	"go.uber.org/atomic"
-
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
@@ -31,6 +29,7 @@ type staticEpochInfo struct {
 	weightThresholdForQC uint64 // computed based on initial committee weights
 	weightThresholdForTO uint64 // computed based on initial committee weights
 	dkg                  hotstuff.DKG
+	isFallback           bool // true for artificial epochs injected by epoch fallback mode
 }
 
 // newStaticEpochInfo returns the static epoch information from the epoch.
@@ -80,22 +79,26 @@ func newStaticEpochInfo(epoch protocol.Epoch) (*staticEpochInfo, error) {
 // newEmergencyFallbackEpoch creates an artificial fallback epoch generated from
 // the last committed epoch at the time epoch emergency fallback is triggered.
 // The fallback epoch:
-// * begins after the last committed epoch
-// * lasts until the next spork (estimated 6 months)
-// * has the same static committee as the last committed epoch
-func newEmergencyFallbackEpoch(lastCommittedEpoch *staticEpochInfo) (*staticEpochInfo, error) {
+//   - begins after the last committed epoch
+//   - lasts for the given number of views: until the next spork (estimated 6 months),
+//     or until the first view of a recovery epoch
+//   - has the same static committee as the last committed epoch
+//
+// The leader selection for a view does not depend on the number of views, hence
+// fallback epochs of different lengths agree on the leaders for their common views.
+func newEmergencyFallbackEpoch(lastCommittedEpoch *staticEpochInfo, numViews uint64) (*staticEpochInfo, error) {
 
 	rng, err := prg.New(lastCommittedEpoch.randomSource, prg.ConsensusLeaderSelection, nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not create rng from seed: %w", err)
 	}
-	leaders, err := leader.ComputeLeaderSelection(lastCommittedEpoch.finalView+1, rng, leader.EstimatedSixMonthOfViews, lastCommittedEpoch.initialCommittee)
+	leaders, err := leader.ComputeLeaderSelection(lastCommittedEpoch.finalView+1, rng, int(numViews), lastCommittedEpoch.initialCommittee)
 	if err != nil {
 		return nil, fmt.Errorf("could not compute leader selection for fallback epoch: %w", err)
 	}
 	epochInfo := &staticEpochInfo{
 		firstView:            lastCommittedEpoch.finalView + 1,
-		finalView:            lastCommittedEpoch.finalView + leader.EstimatedSixMonthOfViews,
+		finalView:            lastCommittedEpoch.finalView + numViews,
 		randomSource:         lastCommittedEpoch.randomSource,
 		leaders:              leaders,
 		initialCommittee:     lastCommittedEpoch.initialCommittee,
@@ -103,6 +106,7 @@ func newEmergencyFallbackEpoch(lastCommittedEpoch *staticEpochInfo) (*staticEpoc
 		weightThresholdForQC: lastCommittedEpoch.weightThresholdForQC,
 		weightThresholdForTO: lastCommittedEpoch.weightThresholdForTO,
 		dkg:                  lastCommittedEpoch.dkg,
+		isFallback:           true,
 	}
 	return epochInfo, nil
 }
@@ -112,11 +116,11 @@ func newEmergencyFallbackEpoch(lastCommittedEpoch *staticEpochInfo) (*staticEpoc
 type Consensus struct {
 	state                  protocol.State              // the protocol state
 	me                     flow.Identifier             // the node ID of this node
-	mu                     sync.RWMutex                // protects access to epochs
+	mu                     sync.RWMutex                // protects access to epochs and extensions
 	epochs                 map[uint64]*staticEpochInfo // cache of initial committee & leader selection per epoch
+	extensions             map[uint64]*staticEpochInfo // cache of fallback epochs extending the prior epoch until a recovery epoch begins, keyed by recovery epoch counter
 	committedEpochsCh      chan *flow.Header           // protocol events for newly committed epochs (the first block of the epoch is passed over the channel)
 	epochEmergencyFallback chan struct{}               // protocol event for epoch emergency fallback
-	isEpochFallbackHandled *atomic.Bool                // ensure we only inject fallback epoch once
 	events.Noop                                        // implements protocol.Consumer
 	component.Component
 }
@@ -131,9 +135,9 @@ func NewConsensusCommittee(state protocol.State, me flow.Identifier) (*Consensus
 		state:                  state,
 		me:                     me,
 		epochs:                 make(map[uint64]*staticEpochInfo),
+		extensions:             make(map[uint64]*staticEpochInfo),
 		committedEpochsCh:      make(chan *flow.Header, 1),
 		epochEmergencyFallback: make(chan struct{}, 1),
-		isEpochFallbackHandled: atomic.NewBool(false),
 	}
 
 	com.Component = component.NewComponentManagerBuilder().
@@ -143,9 +147,8 @@ func NewConsensusCommittee(state protocol.State, me flow.Identifier) (*Consensus
 	final := state.Final()
 
 	// pre-compute leader selection for all presently relevant committed epochs
-	epochs := make([]protocol.Epoch, 0, 3)
-	// we always prepare the current epoch
-	epochs = append(epochs, final.Epochs().Current())
+	// epochs are prepared in ascending order, so that a recovery epoch is prepared after the epoch it follows
+	epochs := make([]protocol.Epoch, 0, 2)
 
 	// we prepare the previous epoch, if one exists
 	exists, err := protocol.PreviousEpochExists(final)
@@ -155,15 +158,8 @@ func NewConsensusCommittee(state protocol.State, me flow.Identifier) (*Consensus
 	if exists {
 		epochs = append(epochs, final.Epochs().Previous())
 	}
-
-	// we prepare the next epoch, if it is committed
-	phase, err := final.Phase()
-	if err != nil {
-		return nil, fmt.Errorf("could not check epoch phase: %w", err)
-	}
-	if phase == flow.EpochPhaseCommitted {
-		epochs = append(epochs, final.Epochs().Next())
-	}
+	// we always prepare the current epoch
+	epochs = append(epochs, final.Epochs().Current())
 
 	for _, epoch := range epochs {
 		_, err = com.prepareEpoch(epoch)
@@ -184,6 +180,19 @@ func NewConsensusCommittee(state protocol.State, me flow.Identifier) (*Consensus
 		}
 	}
 
+	// we prepare the next epoch, if it is committed
+	// if epoch fallback was triggered, the next epoch can only be committed by a recovery epoch, which replaces the fallback epoch
+	phase, err := final.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not check epoch phase: %w", err)
+	}
+	if phase == flow.EpochPhaseCommitted {
+		_, err = com.prepareEpoch(final.Epochs().Next())
+		if err != nil {
+			return nil, fmt.Errorf("could not prepare next epoch: %w", err)
+		}
+	}
+
 	return com, nil
 }
 
@@ -373,15 +382,8 @@ func (c *Consensus) EpochEmergencyFallbackTriggered() {
 // No errors are expected during normal operation.
 func (c *Consensus) onEpochEmergencyFallbackTriggered() error {
 
-	// we respond to epoch fallback being triggered at most once, therefore
-	// the core logic is protected by an atomic bool.
-	// although it is only valid for epoch fallback to be triggered once per spork,
-	// we must account for repeated delivery of protocol events.
-	if !c.isEpochFallbackHandled.CompareAndSwap(false, true) {
-		return nil
-	}
-
-	currentEpochCounter, err := c.state.Final().Epochs().Current().Counter()
+	final := c.state.Final()
+	currentEpochCounter, err := final.Epochs().Current().Counter()
 	if err != nil {
 		return fmt.Errorf("could not get current epoch counter: %w", err)
 	}
@@ -393,14 +395,27 @@ func (c *Consensus) onEpochEmergencyFallbackTriggered() error {
 		c.mu.RUnlock()
 		return fmt.Errorf("epoch fallback: could not find current epoch (counter=%d) info", currentEpochCounter)
 	}
-	// sanity check: next epoch must never be committed, therefore must not be cached
-	_, ok = c.epochs[currentEpochCounter+1]
+	nextEpoch, ok := c.epochs[currentEpochCounter+1]
 	c.mu.RUnlock()
 	if ok {
+		// we respond to epoch fallback being triggered at most once per epoch,
+		// but we must account for repeated delivery of protocol events.
+		if nextEpoch.isFallback {
+			return nil
+		}
+		// sanity check: next epoch must never be committed, therefore must not be cached,
+		// unless it was committed by a recovery epoch ending epoch fallback mode
+		phase, err := final.Phase()
+		if err != nil {
+			return fmt.Errorf("could not check epoch phase: %w", err)
+		}
+		if phase == flow.EpochPhaseCommitted {
+			return nil
+		}
 		return fmt.Errorf("epoch fallback: next epoch (counter=%d) is cached contrary to expectation", currentEpochCounter+1)
 	}
 
-	fallbackEpoch, err := newEmergencyFallbackEpoch(currentEpoch)
+	fallbackEpoch, err := newEmergencyFallbackEpoch(currentEpoch, leader.EstimatedSixMonthOfViews)
 	if err != nil {
 		return fmt.Errorf("could not construct fallback epoch: %w", err)
 	}
@@ -427,13 +442,17 @@ func (c *Consensus) staticEpochInfoByView(view uint64) (*staticEpochInfo, error)
 	// of the time. Since epochs are long-lived and we only cache the most recent 3,
 	// this linear map iteration is inexpensive.
 	c.mu.RLock()
+	defer c.mu.RUnlock()
 	for _, epoch := range c.epochs {
 		if epoch.firstView <= view && view <= epoch.finalView {
-			c.mu.RUnlock()
 			return epoch, nil
 		}
 	}
-	c.mu.RUnlock()
+	for _, extension := range c.extensions {
+		if extension.firstView <= view && view <= extension.finalView {
+			return extension, nil
+		}
+	}
 
 	return nil, model.ErrViewForUnknownEpoch
 }
@@ -441,6 +460,10 @@ func (c *Consensus) staticEpochInfoByView(view uint64) (*staticEpochInfo, error)
 // prepareEpoch pre-computes and stores the static epoch information for the
 // given epoch, including leader selection. Calling prepareEpoch multiple times
 // for the same epoch returns cached static epoch information.
+// A recovery epoch, committed while epoch fallback mode is in effect, replaces
+// the fallback epoch with the same counter. If the recovery epoch does not begin
+// directly after the prior epoch, the views in between remain covered by the
+// fallback epoch extending the prior epoch.
 // Input must be a committed epoch.
 // No errors are expected during normal operation.
 func (c *Consensus) prepareEpoch(epoch protocol.Epoch) (*staticEpochInfo, error) {
@@ -454,7 +477,7 @@ func (c *Consensus) prepareEpoch(epoch protocol.Epoch) (*staticEpochInfo, error)
 	c.mu.RLock()
 	epochInfo, exists := c.epochs[counter]
 	c.mu.RUnlock()
-	if exists {
+	if exists && !epochInfo.isFallback {
 		return epochInfo, nil
 	}
 
@@ -463,22 +486,33 @@ func (c *Consensus) prepareEpoch(epoch protocol.Epoch) (*staticEpochInfo, error)
 		return nil, fmt.Errorf("could not create static epoch info for epch %d: %w", counter, err)
 	}
 
-	// sanity check: ensure new epoch has contiguous views with the prior epoch
+	// sanity check: ensure new epoch has contiguous views with the prior epoch,
+	// or the prior epoch was extended by epoch fallback mode until the new epoch begins
+	var extension *staticEpochInfo
 	c.mu.RLock()
 	prevEpochInfo, exists := c.epochs[counter-1]
 	c.mu.RUnlock()
 	if exists {
-		if epochInfo.firstView != prevEpochInfo.finalView+1 {
+		if epochInfo.firstView <= prevEpochInfo.finalView {
 			return nil, fmt.Errorf("non-contiguous view ranges between consecutive epochs (epoch_%d=[%d,%d], epoch_%d=[%d,%d])",
 				counter-1, prevEpochInfo.firstView, prevEpochInfo.finalView,
 				counter, epochInfo.firstView, epochInfo.finalView)
 		}
+		if epochInfo.firstView > prevEpochInfo.finalView+1 {
+			extension, err = newEmergencyFallbackEpoch(prevEpochInfo, epochInfo.firstView-prevEpochInfo.finalView-1)
+			if err != nil {
+				return nil, fmt.Errorf("could not construct fallback epoch extending epoch %d until recovery epoch: %w", counter-1, err)
+			}
+		}
 	}
 
 	// cache the epoch info
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.epochs[counter] = epochInfo
+	if extension != nil {
+		c.extensions[counter] = extension
+	}
 	// now prune any old epochs, if we have exceeded our maximum of 3
 	// if we have fewer than 3 epochs, this is a no-op
 	c.pruneEpochInfo()
@@ -502,4 +536,9 @@ func (c *Consensus) pruneEpochInfo() {
 			delete(c.epochs, counter)
 		}
 	}
+	for counter := range c.extensions {
+		if counter+3 <= max {
+			delete(c.extensions, counter)
+		}
+	}
 }
@@ -0,0 +1,439 @@
+package epocharchive
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/mapfunc"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/counters"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// Archive retains the epochs of the protocol state for every epoch since the root block, so that
+// the identity table and epoch at any finalized height since the root block can be queried without
+// relying on the sealing segments and epoch statuses of individual blocks.
+//
+// For each epoch, the archive stores the EpochSetup and EpochCommit events together with the heights
+// at which the epoch and its phases started. The archive processes finalized blocks in order of
+// height, and persists its progress, so that it resumes where it stopped when the node restarts.
+// The epoch containing the root block is archived from the root block onward.
+type Archive struct {
+	component.Component
+
+	log      zerolog.Logger
+	db       *badger.DB
+	state    protocol.State
+	headers  storage.Headers
+	statuses storage.EpochStatuses
+	setups   storage.EpochSetups
+	commits  storage.EpochCommits
+
+	notifier       engine.Notifier
+	archivedHeight counters.StrictMonotonousCounter // highest finalized height archived
+	epoch          *flow.ArchivedEpoch              // epoch at the highest archived height, only accessed by the worker
+}
+
+var _ backend.EpochArchive = (*Archive)(nil)
+
+// New creates an archive of the epochs of the given protocol state. If the archive has not been
+// bootstrapped yet, it is bootstrapped from the epochs at the finalized root block of the state.
+// The archive processes finalized blocks once it is notified of them with OnFinalizedBlock.
+// No errors are expected during normal operation.
+func New(
+	log zerolog.Logger,
+	db *badger.DB,
+	state protocol.State,
+	headers storage.Headers,
+	statuses storage.EpochStatuses,
+	setups storage.EpochSetups,
+	commits storage.EpochCommits,
+) (*Archive, error) {
+	a := &Archive{
+		log:      log.With().Str("component", "epoch_archive").Logger(),
+		db:       db,
+		state:    state,
+		headers:  headers,
+		statuses: statuses,
+		setups:   setups,
+		commits:  commits,
+		notifier: engine.NewNotifier(),
+	}
+
+	var archivedHeight uint64
+	err := db.View(operation.RetrieveEpochArchiveHeight(&archivedHeight))
+	if errors.Is(err, storage.ErrNotFound) {
+		archivedHeight, err = a.bootstrap()
+		if err != nil {
+			return nil, fmt.Errorf("could not bootstrap epoch archive: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("could not get archived height: %w", err)
+	}
+
+	a.archivedHeight = counters.NewMonotonousCounter(archivedHeight)
+	a.epoch, err = a.EpochAtHeight(archivedHeight)
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch at archived height %d: %w", archivedHeight, err)
+	}
+
+	a.Component = component.NewComponentManagerBuilder().
+		AddWorker(a.processLoop).
+		Build()
+
+	return a, nil
+}
+
+// bootstrap archives the previous, current and next epochs at the finalized root block of the
+// protocol state, and returns the root height. Phases of the current epoch which started before
+// the root block are archived as starting at the root block.
+// No errors are expected during normal operation.
+func (a *Archive) bootstrap() (uint64, error) {
+	root, err := a.state.Params().FinalizedRoot()
+	if err != nil {
+		return 0, fmt.Errorf("could not get finalized root block: %w", err)
+	}
+	status, err := a.statuses.ByBlockID(root.ID())
+	if err != nil {
+		return 0, fmt.Errorf("could not get epoch status of root block: %w", err)
+	}
+	phase, err := status.Phase()
+	if err != nil {
+		return 0, fmt.Errorf("could not get epoch phase of root block: %w", err)
+	}
+	epochs := a.state.AtBlockID(root.ID()).Epochs()
+
+	var archived []*flow.ArchivedEpoch
+	if status.HasPrevious() {
+		previous, err := a.archivedEpoch(status.PreviousEpoch)
+		if err != nil {
+			return 0, fmt.Errorf("could not get previous epoch: %w", err)
+		}
+		previous.FirstHeight, err = firstHeight(epochs.Previous())
+		if err != nil {
+			return 0, fmt.Errorf("could not get first height of previous epoch: %w", err)
+		}
+		archived = append(archived, previous)
+	}
+
+	current, err := a.archivedEpoch(status.CurrentEpoch)
+	if err != nil {
+		return 0, fmt.Errorf("could not get current epoch: %w", err)
+	}
+	current.FirstHeight, err = firstHeight(epochs.Current())
+	if err != nil {
+		return 0, fmt.Errorf("could not get first height of current epoch: %w", err)
+	}
+	if phase != flow.EpochPhaseStaking {
+		current.SetupPhaseHeight = &root.Height
+	}
+	if phase == flow.EpochPhaseCommitted {
+		current.CommittedPhaseHeight = &root.Height
+	}
+	archived = append(archived, current)
+
+	if status.NextEpoch.SetupID != flow.ZeroID {
+		next, err := a.archivedEpoch(status.NextEpoch)
+		if err != nil {
+			return 0, fmt.Errorf("could not get next epoch: %w", err)
+		}
+		archived = append(archived, next)
+	}
+
+	err = operation.RetryOnConflict(a.db.Update, func(tx *badger.Txn) error {
+		for _, epoch := range archived {
+			err := operation.UpsertArchivedEpoch(epoch)(tx)
+			if err != nil {
+				return fmt.Errorf("could not archive epoch %d: %w", epoch.Counter(), err)
+			}
+		}
+		err := operation.IndexArchivedEpochByHeight(root.Height, current.Counter())(tx)
+		if err != nil {
+			return fmt.Errorf("could not index current epoch: %w", err)
+		}
+		return operation.InsertEpochArchiveHeight(root.Height)(tx)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	a.log.Info().
+		Uint64("root_height", root.Height).
+		Uint64("epoch_counter", current.Counter()).
+		Msg("bootstrapped epoch archive")
+
+	return root.Height, nil
+}
+
+// OnFinalizedBlock notifies the archive that a new block has been finalized.
+func (a *Archive) OnFinalizedBlock(*model.Block) {
+	a.notifier.Notify()
+}
+
+// ArchivedHeight returns the highest finalized height archived.
+func (a *Archive) ArchivedHeight() uint64 {
+	return a.archivedHeight.Value()
+}
+
+// EpochAtHeight returns the archived epoch containing the finalized block at the given height.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if the height is below the root height, or has not been archived yet
+func (a *Archive) EpochAtHeight(height uint64) (*flow.ArchivedEpoch, error) {
+	if height > a.archivedHeight.Value() {
+		return nil, fmt.Errorf("height %d has not been archived yet: %w", height, storage.ErrNotFound)
+	}
+
+	var epoch flow.ArchivedEpoch
+	err := a.db.View(func(tx *badger.Txn) error {
+		var counter uint64
+		err := operation.LookupArchivedEpochByHeight(height, &counter)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up epoch at height %d: %w", height, err)
+		}
+		return operation.RetrieveArchivedEpoch(counter, &epoch)(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &epoch, nil
+}
+
+// EpochByCounter returns the archived epoch with the given counter. Epochs are archived once their
+// EpochSetup event is finalized.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if the epoch has not been archived
+func (a *Archive) EpochByCounter(counter uint64) (*flow.ArchivedEpoch, error) {
+	var epoch flow.ArchivedEpoch
+	err := a.db.View(operation.RetrieveArchivedEpoch(counter, &epoch))
+	if err != nil {
+		return nil, err
+	}
+	return &epoch, nil
+}
+
+// IdentitiesAtHeight returns the identity table at the finalized block at the given height, as
+// returned by the protocol state snapshot of the block: the participants of the epoch, together with
+// the participants leaving in the staking phase, or joining in the setup and committed phases, with
+// weight zero.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if the height is below the root height, or has not been archived yet
+func (a *Archive) IdentitiesAtHeight(height uint64) (flow.IdentityList, error) {
+	epoch, err := a.EpochAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := epoch.Setup.Participants.Sort(order.Canonical)
+
+	// get identities that are in either last/next epoch but NOT in the current epoch
+	var other *flow.ArchivedEpoch
+	switch epoch.PhaseAtHeight(height) {
+	case flow.EpochPhaseStaking:
+		other, err = a.EpochByCounter(epoch.Counter() - 1)
+		if errors.Is(err, storage.ErrNotFound) {
+			// there is no previous epoch at the spork root block
+			other = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not get previous epoch: %w", err)
+		}
+	case flow.EpochPhaseSetup, flow.EpochPhaseCommitted:
+		other, err = a.EpochByCounter(epoch.Counter() + 1)
+		if err != nil {
+			return nil, fmt.Errorf("could not get next epoch: %w", err)
+		}
+	}
+
+	var otherEpochIdentities flow.IdentityList
+	if other != nil {
+		for _, identity := range other.Setup.Participants {
+			if !identities.Exists(identity) {
+				otherEpochIdentities = append(otherEpochIdentities, identity)
+			}
+		}
+	}
+
+	// add the identities from next/last epoch, with weight set to 0
+	identities = append(identities, otherEpochIdentities.Map(mapfunc.WithWeight(0))...)
+
+	return identities.Sort(order.Canonical), nil
+}
+
+// processLoop archives newly finalized blocks.
+func (a *Archive) processLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	// archive the blocks finalized while the node was stopped
+	a.notifier.Notify()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.notifier.Channel():
+			err := a.archiveFinalized()
+			if err != nil {
+				ctx.Throw(err)
+				return
+			}
+		}
+	}
+}
+
+// archiveFinalized archives all blocks up to the latest finalized block.
+// No errors are expected during normal operation.
+func (a *Archive) archiveFinalized() error {
+	final, err := a.state.Final().Head()
+	if err != nil {
+		return fmt.Errorf("could not get latest finalized block: %w", err)
+	}
+	for height := a.archivedHeight.Value() + 1; height <= final.Height; height++ {
+		err := a.archiveHeight(height)
+		if err != nil {
+			return fmt.Errorf("could not archive height %d: %w", height, err)
+		}
+	}
+	return nil
+}
+
+// archiveHeight archives the finalized block at the given height, which must be the height following
+// the highest archived height. The archived epochs are updated if the block starts a new epoch or
+// epoch phase.
+// No errors are expected during normal operation.
+func (a *Archive) archiveHeight(height uint64) error {
+	header, err := a.headers.ByHeight(height)
+	if err != nil {
+		return fmt.Errorf("could not get header: %w", err)
+	}
+	status, err := a.statuses.ByBlockID(header.ID())
+	if err != nil {
+		return fmt.Errorf("could not get epoch status: %w", err)
+	}
+	phase, err := status.Phase()
+	if err != nil {
+		return fmt.Errorf("could not get epoch phase: %w", err)
+	}
+	currentSetup, err := a.setups.ByID(status.CurrentEpoch.SetupID)
+	if err != nil {
+		return fmt.Errorf("could not get current epoch setup: %w", err)
+	}
+
+	epoch := a.epoch
+	var next *flow.ArchivedEpoch // the next epoch, if it is updated by the block
+	newEpoch := false
+
+	// the block is the first block of a new epoch, which has been archived with its EpochSetup event
+	if currentSetup.Counter != epoch.Counter() {
+		if currentSetup.Counter != epoch.Counter()+1 {
+			return fmt.Errorf("epoch counter of finalized block (%d) does not follow archived epoch counter (%d)", currentSetup.Counter, epoch.Counter())
+		}
+		epoch, err = a.EpochByCounter(currentSetup.Counter)
+		if err != nil {
+			return fmt.Errorf("could not get archived epoch %d: %w", currentSetup.Counter, err)
+		}
+		epoch.FirstHeight = &height
+		newEpoch = true
+	}
+
+	// the block is the first block of the setup phase, in which the next epoch is archived
+	if phase != flow.EpochPhaseStaking && epoch.SetupPhaseHeight == nil {
+		nextSetup, err := a.setups.ByID(status.NextEpoch.SetupID)
+		if err != nil {
+			return fmt.Errorf("could not get next epoch setup: %w", err)
+		}
+		epoch.SetupPhaseHeight = &height
+		next = &flow.ArchivedEpoch{Setup: nextSetup}
+	}
+
+	// the block is the first block of the committed phase. The next epoch might have been specified
+	// by an EpochRecover event after the setup phase, so it is archived again with both events.
+	if phase == flow.EpochPhaseCommitted && epoch.CommittedPhaseHeight == nil {
+		nextSetup, err := a.setups.ByID(status.NextEpoch.SetupID)
+		if err != nil {
+			return fmt.Errorf("could not get next epoch setup: %w", err)
+		}
+		nextCommit, err := a.commits.ByID(status.NextEpoch.CommitID)
+		if err != nil {
+			return fmt.Errorf("could not get next epoch commit: %w", err)
+		}
+		epoch.CommittedPhaseHeight = &height
+		next = &flow.ArchivedEpoch{Setup: nextSetup, Commit: nextCommit}
+	}
+
+	err = operation.RetryOnConflict(a.db.Update, func(tx *badger.Txn) error {
+		if newEpoch {
+			err := operation.IndexArchivedEpochByHeight(height, epoch.Counter())(tx)
+			if err != nil {
+				return fmt.Errorf("could not index epoch %d: %w", epoch.Counter(), err)
+			}
+		}
+		if newEpoch || next != nil {
+			err := operation.UpsertArchivedEpoch(epoch)(tx)
+			if err != nil {
+				return fmt.Errorf("could not archive epoch %d: %w", epoch.Counter(), err)
+			}
+		}
+		if next != nil {
+			err := operation.UpsertArchivedEpoch(next)(tx)
+			if err != nil {
+				return fmt.Errorf("could not archive next epoch %d: %w", next.Counter(), err)
+			}
+		}
+		return operation.UpdateEpochArchiveHeight(height)(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	if newEpoch {
+		a.log.Info().
+			Uint64("height", height).
+			Uint64("epoch_counter", epoch.Counter()).
+			Msg("archived epoch transition")
+	}
+
+	a.epoch = epoch
+	a.archivedHeight.Set(height)
+	return nil
+}
+
+// archivedEpoch returns a new archived epoch with the given service events, without heights.
+// No errors are expected during normal operation.
+func (a *Archive) archivedEpoch(eventIDs flow.EventIDs) (*flow.ArchivedEpoch, error) {
+	setup, err := a.setups.ByID(eventIDs.SetupID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch setup: %w", err)
+	}
+	epoch := &flow.ArchivedEpoch{Setup: setup}
+	if eventIDs.CommitID != flow.ZeroID {
+		epoch.Commit, err = a.commits.ByID(eventIDs.CommitID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get epoch commit: %w", err)
+		}
+	}
+	return epoch, nil
+}
+
+// firstHeight returns the height of the first block of the given epoch, or nil if the first block
+// has not been finalized.
+// No errors are expected during normal operation.
+func firstHeight(epoch protocol.Epoch) (*uint64, error) {
+	height, err := epoch.FirstHeight()
+	if errors.Is(err, protocol.ErrEpochTransitionNotFinalized) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &height, nil
+}
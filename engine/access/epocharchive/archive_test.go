@@ -0,0 +1,248 @@
+package epocharchive
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// chainFixture is a finalized chain of blocks with their epoch statuses, backing the storage and
+// protocol state mocks used by the archive.
+type chainFixture struct {
+	t        *testing.T
+	state    *protocolmock.State
+	headers  *storagemock.Headers
+	statuses *storagemock.EpochStatuses
+	setups   *storagemock.EpochSetups
+	commits  *storagemock.EpochCommits
+	blocks   []*flow.Header
+}
+
+func newChainFixture(t *testing.T) *chainFixture {
+	return &chainFixture{
+		t:        t,
+		state:    protocolmock.NewState(t),
+		headers:  storagemock.NewHeaders(t),
+		statuses: storagemock.NewEpochStatuses(t),
+		setups:   storagemock.NewEpochSetups(t),
+		commits:  storagemock.NewEpochCommits(t),
+	}
+}
+
+// addEvents makes the given epoch service events available in storage.
+func (c *chainFixture) addEvents(setup *flow.EpochSetup, commit *flow.EpochCommit) {
+	c.setups.On("ByID", setup.ID()).Return(setup, nil).Maybe()
+	c.commits.On("ByID", commit.ID()).Return(commit, nil).Maybe()
+}
+
+// extend finalizes a block at the given height with the given epoch status.
+func (c *chainFixture) extend(height uint64, status *flow.EpochStatus) *flow.Header {
+	header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+	c.headers.On("ByHeight", height).Return(header, nil).Maybe()
+	c.statuses.On("ByBlockID", header.ID()).Return(status, nil).Maybe()
+	c.blocks = append(c.blocks, header)
+	return header
+}
+
+// bootstrap sets up the protocol state with the first block of the chain as finalized root block,
+// and the last block of the chain as latest finalized block. The first heights of the epochs at the
+// root block are nil if they are unknown.
+func (c *chainFixture) bootstrap(previousEpochFirstHeight, currentEpochFirstHeight *uint64) {
+	root := c.blocks[0]
+	params := protocolmock.NewParams(c.t)
+	params.On("FinalizedRoot").Return(root, nil).Maybe()
+	c.state.On("Params").Return(params).Maybe()
+
+	epochs := protocolmock.NewEpochQuery(c.t)
+	epochs.On("Current").Return(c.epochWithFirstHeight(currentEpochFirstHeight)).Maybe()
+	if previousEpochFirstHeight != nil {
+		epochs.On("Previous").Return(c.epochWithFirstHeight(previousEpochFirstHeight)).Maybe()
+	}
+	rootSnapshot := protocolmock.NewSnapshot(c.t)
+	rootSnapshot.On("Epochs").Return(epochs).Maybe()
+	c.state.On("AtBlockID", root.ID()).Return(rootSnapshot).Maybe()
+
+	final := protocolmock.NewSnapshot(c.t)
+	final.On("Head").Return(func() *flow.Header { return c.blocks[len(c.blocks)-1] }, nil).Maybe()
+	c.state.On("Final").Return(final).Maybe()
+}
+
+// epochWithFirstHeight returns an epoch with the given first height, or an epoch whose first block
+// has not been finalized if the height is nil.
+func (c *chainFixture) epochWithFirstHeight(height *uint64) *protocolmock.Epoch {
+	epoch := protocolmock.NewEpoch(c.t)
+	if height != nil {
+		epoch.On("FirstHeight").Return(*height, nil).Maybe()
+	} else {
+		epoch.On("FirstHeight").Return(uint64(0), protocol.ErrEpochTransitionNotFinalized).Maybe()
+	}
+	return epoch
+}
+
+func (c *chainFixture) newArchive(db *badger.DB) *Archive {
+	archive, err := New(unittest.Logger(), db, c.state, c.headers, c.statuses, c.setups, c.commits)
+	require.NoError(c.t, err)
+	return archive
+}
+
+// TestArchive_EpochTransition tests archiving a chain from the spork root block through the phases
+// of the first epoch into the second epoch.
+func TestArchive_EpochTransition(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		identities := unittest.IdentityListFixture(4, unittest.WithAllRoles())
+		setup1 := unittest.EpochSetupFixture(unittest.SetupWithCounter(1), unittest.WithParticipants(identities[:3]))
+		commit1 := unittest.EpochCommitFixture(unittest.CommitWithCounter(1))
+		setup2 := unittest.EpochSetupFixture(unittest.SetupWithCounter(2), unittest.WithParticipants(identities[1:]))
+		commit2 := unittest.EpochCommitFixture(unittest.CommitWithCounter(2))
+
+		chain := newChainFixture(t)
+		chain.addEvents(setup1, commit1)
+		chain.addEvents(setup2, commit2)
+
+		staking := &flow.EpochStatus{CurrentEpoch: flow.EventIDs{SetupID: setup1.ID(), CommitID: commit1.ID()}}
+		setupPhase := staking.Copy()
+		setupPhase.NextEpoch.SetupID = setup2.ID()
+		committed := setupPhase.Copy()
+		committed.NextEpoch.CommitID = commit2.ID()
+		nextEpoch := &flow.EpochStatus{PreviousEpoch: committed.CurrentEpoch, CurrentEpoch: committed.NextEpoch}
+
+		chain.extend(0, staking)
+		chain.extend(1, staking)
+		chain.extend(2, setupPhase)
+		chain.extend(3, committed)
+		chain.extend(4, committed)
+		chain.extend(5, nextEpoch)
+		rootHeight := uint64(0)
+		chain.bootstrap(nil, &rootHeight)
+
+		archive := chain.newArchive(db)
+		assert.Equal(t, uint64(0), archive.ArchivedHeight())
+		require.NoError(t, archive.archiveFinalized())
+		assert.Equal(t, uint64(5), archive.ArchivedHeight())
+
+		t.Run("epochs by height", func(t *testing.T) {
+			for height, expected := range map[uint64]flow.EpochPhase{
+				0: flow.EpochPhaseStaking,
+				1: flow.EpochPhaseStaking,
+				2: flow.EpochPhaseSetup,
+				3: flow.EpochPhaseCommitted,
+				4: flow.EpochPhaseCommitted,
+			} {
+				epoch, err := archive.EpochAtHeight(height)
+				require.NoError(t, err)
+				assert.Equal(t, setup1, epoch.Setup)
+				assert.Equal(t, commit1, epoch.Commit)
+				assert.Equal(t, uint64(0), *epoch.FirstHeight)
+				assert.Equal(t, expected, epoch.PhaseAtHeight(height), "unexpected phase at height %d", height)
+			}
+
+			epoch, err := archive.EpochAtHeight(5)
+			require.NoError(t, err)
+			assert.Equal(t, setup2, epoch.Setup)
+			assert.Equal(t, commit2, epoch.Commit)
+			assert.Equal(t, uint64(5), *epoch.FirstHeight)
+			assert.Equal(t, flow.EpochPhaseStaking, epoch.PhaseAtHeight(5))
+
+			// heights which have not been finalized are not archived
+			_, err = archive.EpochAtHeight(6)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+		})
+
+		t.Run("identities by height", func(t *testing.T) {
+			// in the staking phase of the first epoch, there are no other identities
+			actual, err := archive.IdentitiesAtHeight(1)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, setup1.Participants, actual)
+
+			// in the setup phase, the identities joining in the next epoch are included with zero weight
+			actual, err = archive.IdentitiesAtHeight(2)
+			require.NoError(t, err)
+			require.Len(t, actual, 4)
+			joining, ok := actual.ByNodeID(identities[3].NodeID)
+			require.True(t, ok)
+			assert.Zero(t, joining.Weight)
+			assert.ElementsMatch(t, setup1.Participants.NodeIDs(), actual.Filter(filter.HasWeight(true)).NodeIDs())
+
+			// in the staking phase of the second epoch, the identities leaving are included with zero weight
+			actual, err = archive.IdentitiesAtHeight(5)
+			require.NoError(t, err)
+			require.Len(t, actual, 4)
+			leaving, ok := actual.ByNodeID(identities[0].NodeID)
+			require.True(t, ok)
+			assert.Zero(t, leaving.Weight)
+			assert.ElementsMatch(t, setup2.Participants.NodeIDs(), actual.Filter(filter.HasWeight(true)).NodeIDs())
+		})
+
+		t.Run("resume after restart", func(t *testing.T) {
+			chain.extend(6, nextEpoch)
+
+			restarted := chain.newArchive(db)
+			assert.Equal(t, uint64(5), restarted.ArchivedHeight())
+			require.NoError(t, restarted.archiveFinalized())
+			assert.Equal(t, uint64(6), restarted.ArchivedHeight())
+
+			epoch, err := restarted.EpochAtHeight(6)
+			require.NoError(t, err)
+			assert.Equal(t, setup2, epoch.Setup)
+		})
+	})
+}
+
+// TestArchive_BootstrapMidEpoch tests bootstrapping the archive from a root block in the committed
+// phase of an epoch which started before the root block.
+func TestArchive_BootstrapMidEpoch(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		identities := unittest.IdentityListFixture(4, unittest.WithAllRoles())
+		setup1 := unittest.EpochSetupFixture(unittest.SetupWithCounter(1), unittest.WithParticipants(identities))
+		commit1 := unittest.EpochCommitFixture(unittest.CommitWithCounter(1))
+		setup2 := unittest.EpochSetupFixture(unittest.SetupWithCounter(2), unittest.WithParticipants(identities))
+		commit2 := unittest.EpochCommitFixture(unittest.CommitWithCounter(2))
+		setup3 := unittest.EpochSetupFixture(unittest.SetupWithCounter(3), unittest.WithParticipants(identities))
+		commit3 := unittest.EpochCommitFixture(unittest.CommitWithCounter(3))
+
+		chain := newChainFixture(t)
+		chain.addEvents(setup1, commit1)
+		chain.addEvents(setup2, commit2)
+		chain.addEvents(setup3, commit3)
+		chain.extend(100, &flow.EpochStatus{
+			PreviousEpoch: flow.EventIDs{SetupID: setup1.ID(), CommitID: commit1.ID()},
+			CurrentEpoch:  flow.EventIDs{SetupID: setup2.ID(), CommitID: commit2.ID()},
+			NextEpoch:     flow.EventIDs{SetupID: setup3.ID(), CommitID: commit3.ID()},
+		})
+		previousEpochFirstHeight := uint64(20)
+		chain.bootstrap(&previousEpochFirstHeight, nil)
+
+		archive := chain.newArchive(db)
+		assert.Equal(t, uint64(100), archive.ArchivedHeight())
+
+		// the current epoch is archived from the root block
+		epoch, err := archive.EpochAtHeight(100)
+		require.NoError(t, err)
+		assert.Equal(t, setup2, epoch.Setup)
+		assert.Nil(t, epoch.FirstHeight)
+		assert.Equal(t, flow.EpochPhaseCommitted, epoch.PhaseAtHeight(100))
+
+		_, err = archive.EpochAtHeight(99)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// the adjacent epochs are archived with their events
+		epoch, err = archive.EpochByCounter(1)
+		require.NoError(t, err)
+		assert.Equal(t, setup1, epoch.Setup)
+		assert.Equal(t, uint64(20), *epoch.FirstHeight)
+		epoch, err = archive.EpochByCounter(3)
+		require.NoError(t, err)
+		assert.Equal(t, setup3, epoch.Setup)
+		assert.Equal(t, commit3, epoch.Commit)
+	})
+}
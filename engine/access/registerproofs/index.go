@@ -0,0 +1,332 @@
+package registerproofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/counters"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// DefaultCapacity is the default number of execution states the index retains in memory.
+const DefaultCapacity = 100
+
+// ExecutionDataByHeight provides the execution data of sealed blocks by height.
+type ExecutionDataByHeight interface {
+	// ByHeight returns the execution data of the sealed block at the given height.
+	ByHeight(ctx context.Context, height uint64) (*execution_data.BlockExecutionDataEntity, error)
+}
+
+// Index is a local, proof-capable index of the execution state. Starting from the root execution
+// state, it applies the trie updates of the execution data of each sealed block in order of height,
+// and checks the resulting state against the state commitment of the block's seal. The execution
+// states of the most recent blocks are retained in memory, so that batch proofs of register values
+// can be created for them.
+//
+// The index is held in memory only, so the execution data of all blocks since the root block must
+// be available in the local execution data store when the node starts.
+type Index struct {
+	component.Component
+
+	log           zerolog.Logger
+	headers       storage.Headers
+	seals         storage.Seals
+	executionData ExecutionDataByHeight
+	forest        *mtrie.Forest
+
+	notifier      engine.Notifier
+	highestHeight counters.StrictMonotonousCounter // highest height with execution data available
+
+	mu             sync.RWMutex
+	indexedHeight  uint64                                     // highest height indexed
+	indexedCommit  flow.StateCommitment                       // state commitment at the highest height indexed
+	commits        map[flow.Identifier]flow.StateCommitment   // state commitments of retained blocks
+	blocksByCommit map[flow.StateCommitment][]flow.Identifier // retained blocks by state commitment
+}
+
+var _ backend.RegisterProver = (*Index)(nil)
+
+// New creates an index starting from the given root execution state, which is the sealed state of
+// the block at the root height. The index processes execution data once it is notified that it is
+// available, using OnHighestAvailableHeight or OnExecutionData.
+// No errors are expected during normal operation.
+func New(
+	log zerolog.Logger,
+	metrics module.LedgerMetrics,
+	capacity int,
+	rootHeight uint64,
+	rootTrie *trie.MTrie,
+	headers storage.Headers,
+	seals storage.Seals,
+	executionData ExecutionDataByHeight,
+) (*Index, error) {
+	rootHeader, err := headers.ByHeight(rootHeight)
+	if err != nil {
+		return nil, fmt.Errorf("could not get root header: %w", err)
+	}
+
+	idx := &Index{
+		log:            log.With().Str("component", "register_proof_index").Logger(),
+		headers:        headers,
+		seals:          seals,
+		executionData:  executionData,
+		notifier:       engine.NewNotifier(),
+		highestHeight:  counters.NewMonotonousCounter(rootHeight),
+		indexedHeight:  rootHeight,
+		indexedCommit:  flow.StateCommitment(rootTrie.RootHash()),
+		commits:        make(map[flow.Identifier]flow.StateCommitment),
+		blocksByCommit: make(map[flow.StateCommitment][]flow.Identifier),
+	}
+
+	// the forest retains the tries of the most recent execution states, and the retained blocks
+	// are pruned with the tries of their execution states
+	idx.forest, err = mtrie.NewForest(capacity, metrics, idx.onTrieEvicted)
+	if err != nil {
+		return nil, fmt.Errorf("could not create forest: %w", err)
+	}
+	err = idx.forest.AddTrie(rootTrie)
+	if err != nil {
+		return nil, fmt.Errorf("could not add root trie: %w", err)
+	}
+	idx.addCommit(rootHeader.ID(), idx.indexedCommit)
+
+	idx.Component = component.NewComponentManagerBuilder().
+		AddWorker(idx.processLoop).
+		Build()
+
+	return idx, nil
+}
+
+// LoadRootTrie loads the trie with the given root hash from the checkpoint file at the given path.
+// No errors are expected during normal operation.
+func LoadRootTrie(log zerolog.Logger, checkpointPath string, commit flow.StateCommitment) (*trie.MTrie, error) {
+	tries, err := wal.LoadCheckpoint(checkpointPath, &log)
+	if err != nil {
+		return nil, fmt.Errorf("could not load checkpoint %s: %w", checkpointPath, err)
+	}
+	for _, t := range tries {
+		if flow.StateCommitment(t.RootHash()) == commit {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("checkpoint %s does not contain the trie of state commitment %x", checkpointPath, commit)
+}
+
+// OnExecutionData notifies the index that the execution data of the given block is available.
+func (i *Index) OnExecutionData(executionData *execution_data.BlockExecutionDataEntity) {
+	header, err := i.headers.ByBlockID(executionData.BlockID)
+	if err != nil {
+		// if the execution data is available, the block must be locally finalized
+		i.log.Fatal().Err(err).Msg("could not get header for execution data")
+		return
+	}
+
+	i.OnHighestAvailableHeight(header.Height)
+}
+
+// OnHighestAvailableHeight notifies the index that the execution data of all blocks up to the given
+// height is available.
+func (i *Index) OnHighestAvailableHeight(height uint64) {
+	if i.highestHeight.Set(height) {
+		i.notifier.Notify()
+	}
+}
+
+// IndexedHeight returns the highest height the index has processed the execution data of.
+func (i *Index) IndexedHeight() uint64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.indexedHeight
+}
+
+// RegistersWithProof returns the sealed state commitment of the given block, and a batch proof of
+// the values of the given registers against it.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if the execution state of the block is not retained by the index
+func (i *Index) RegistersWithProof(blockID flow.Identifier, registerIDs []flow.RegisterID) (flow.StateCommitment, *ledger.TrieBatchProof, error) {
+	i.mu.RLock()
+	commit, ok := i.commits[blockID]
+	i.mu.RUnlock()
+	if !ok {
+		return flow.DummyStateCommitment, nil, fmt.Errorf("execution state of block %v is not indexed: %w", blockID, storage.ErrNotFound)
+	}
+
+	paths := make([]ledger.Path, 0, len(registerIDs))
+	for _, registerID := range registerIDs {
+		path, err := pathfinder.KeyToPath(convert.RegisterIDToLedgerKey(registerID), access.RegisterProofPathFinderVersion)
+		if err != nil {
+			return flow.DummyStateCommitment, nil, fmt.Errorf("could not derive path of register %s: %w", registerID, err)
+		}
+		paths = append(paths, path)
+	}
+
+	proof, err := i.forest.Proofs(&ledger.TrieRead{RootHash: ledger.RootHash(commit), Paths: paths})
+	if err != nil {
+		// the trie might have been evicted since the commitment was looked up
+		if !i.forest.HasTrie(ledger.RootHash(commit)) {
+			return flow.DummyStateCommitment, nil, fmt.Errorf("execution state of block %v is no longer retained: %w", blockID, storage.ErrNotFound)
+		}
+		return flow.DummyStateCommitment, nil, fmt.Errorf("could not create proofs: %w", err)
+	}
+	return commit, proof, nil
+}
+
+// processLoop indexes the execution data of newly available blocks.
+func (i *Index) processLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	// index the execution data available at startup
+	i.notifier.Notify()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.notifier.Channel():
+			err := i.indexAvailable(ctx)
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return
+				}
+				ctx.Throw(err)
+				return
+			}
+		}
+	}
+}
+
+// indexAvailable indexes the execution data of all blocks up to the highest available height.
+// No errors are expected during normal operation.
+func (i *Index) indexAvailable(ctx context.Context) error {
+	for height := i.IndexedHeight() + 1; height <= i.highestHeight.Value(); height++ {
+		err := i.indexHeight(ctx, height)
+		if err != nil {
+			return fmt.Errorf("could not index height %d: %w", height, err)
+		}
+	}
+	return nil
+}
+
+// indexHeight applies the trie updates of the execution data of the sealed block at the given height.
+// No errors are expected during normal operation.
+func (i *Index) indexHeight(ctx context.Context, height uint64) error {
+	executionData, err := i.executionData.ByHeight(ctx, height)
+	if err != nil {
+		return fmt.Errorf("could not get execution data: %w", err)
+	}
+	seal, err := i.seals.FinalizedSealForBlock(executionData.BlockID)
+	if err != nil {
+		return fmt.Errorf("could not get seal: %w", err)
+	}
+
+	i.mu.RLock()
+	commit := i.indexedCommit
+	i.mu.RUnlock()
+
+	current, err := i.forest.GetTrie(ledger.RootHash(commit))
+	if err != nil {
+		return fmt.Errorf("could not get trie of parent state %x: %w", commit, err)
+	}
+	for index, chunk := range executionData.ChunkExecutionDatas {
+		if chunk.TrieUpdate == nil {
+			continue
+		}
+		if chunk.TrieUpdate.RootHash != current.RootHash() {
+			return fmt.Errorf("trie update of chunk %d starts at state %x instead of %x", index, chunk.TrieUpdate.RootHash, current.RootHash())
+		}
+		current, err = applyTrieUpdate(current, chunk.TrieUpdate)
+		if err != nil {
+			return fmt.Errorf("could not apply trie update of chunk %d: %w", index, err)
+		}
+	}
+
+	commit = flow.StateCommitment(current.RootHash())
+	if commit != seal.FinalState {
+		return fmt.Errorf("execution state %x does not match sealed state %x", commit, seal.FinalState)
+	}
+
+	// the forest might evict tries, which removes the retained blocks, so it must not be called with the lock held
+	err = i.forest.AddTrie(current)
+	if err != nil {
+		return fmt.Errorf("could not add trie: %w", err)
+	}
+
+	i.mu.Lock()
+	i.indexedHeight = height
+	i.indexedCommit = commit
+	i.addCommit(executionData.BlockID, commit)
+	i.mu.Unlock()
+
+	i.log.Debug().
+		Uint64("height", height).
+		Hex("block_id", logging.ID(executionData.BlockID)).
+		Hex("state_commitment", commit[:]).
+		Msg("indexed execution state")
+
+	return nil
+}
+
+// applyTrieUpdate returns the trie resulting from applying the given update to the given trie.
+// Multiple updates of the same register retain the value of the last update.
+func applyTrieUpdate(parent *trie.MTrie, update *ledger.TrieUpdate) (*trie.MTrie, error) {
+	if len(update.Paths) == 0 {
+		return parent, nil
+	}
+
+	paths := make([]ledger.Path, 0, len(update.Paths))
+	payloads := make([]ledger.Payload, 0, len(update.Paths))
+	indices := make(map[ledger.Path]int, len(update.Paths))
+	for j, path := range update.Paths {
+		if index, ok := indices[path]; ok {
+			payloads[index] = *update.Payloads[j]
+			continue
+		}
+		indices[path] = len(paths)
+		paths = append(paths, path)
+		payloads = append(payloads, *update.Payloads[j])
+	}
+
+	updated, _, err := trie.NewTrieWithUpdatedRegisters(parent, paths, payloads, true)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// addCommit retains the state commitment of the given block.
+// Caller must hold the lock.
+func (i *Index) addCommit(blockID flow.Identifier, commit flow.StateCommitment) {
+	i.commits[blockID] = commit
+	i.blocksByCommit[commit] = append(i.blocksByCommit[commit], blockID)
+}
+
+// onTrieEvicted removes the blocks whose execution state is the evicted trie.
+func (i *Index) onTrieEvicted(evicted *trie.MTrie) {
+	commit := flow.StateCommitment(evicted.RootHash())
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, blockID := range i.blocksByCommit[commit] {
+		delete(i.commits, blockID)
+	}
+	delete(i.blocksByCommit, commit)
+}
@@ -0,0 +1,162 @@
+package registerproofs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// executionDataStub serves execution data by height.
+type executionDataStub map[uint64]*execution_data.BlockExecutionDataEntity
+
+func (s executionDataStub) ByHeight(_ context.Context, height uint64) (*execution_data.BlockExecutionDataEntity, error) {
+	executionData, ok := s[height]
+	if !ok {
+		return nil, fmt.Errorf("no execution data at height %d: %w", height, storage.ErrNotFound)
+	}
+	return executionData, nil
+}
+
+// indexFixture holds a chain of blocks, each of which updates a single register.
+type indexFixture struct {
+	headers       *storagemock.Headers
+	seals         *storagemock.Seals
+	executionData executionDataStub
+	rootTrie      *trie.MTrie
+	blocks        []*flow.Header
+	commits       []flow.StateCommitment
+	registerID    flow.RegisterID
+}
+
+func newIndexFixture(t *testing.T, blockCount int) *indexFixture {
+	f := &indexFixture{
+		headers:       storagemock.NewHeaders(t),
+		seals:         storagemock.NewSeals(t),
+		executionData: make(executionDataStub),
+		rootTrie:      trie.NewEmptyMTrie(),
+		registerID:    flow.NewRegisterID(string(unittest.RandomAddressFixture().Bytes()), "counter"),
+	}
+	key := convert.RegisterIDToLedgerKey(f.registerID)
+	path, err := pathfinder.KeyToPath(key, access.RegisterProofPathFinderVersion)
+	require.NoError(t, err)
+
+	root := unittest.BlockHeaderFixture()
+	f.headers.On("ByHeight", root.Height).Return(root, nil).Maybe()
+	f.blocks = append(f.blocks, root)
+	f.commits = append(f.commits, flow.StateCommitment(f.rootTrie.RootHash()))
+
+	current := f.rootTrie
+	for i := 1; i <= blockCount; i++ {
+		block := unittest.BlockHeaderWithParentFixture(f.blocks[i-1])
+		update := &ledger.TrieUpdate{
+			RootHash: current.RootHash(),
+			Paths:    []ledger.Path{path},
+			Payloads: []*ledger.Payload{ledger.NewPayload(key, []byte{byte(i)})},
+		}
+		current, err = applyTrieUpdate(current, update)
+		require.NoError(t, err)
+
+		f.executionData[block.Height] = execution_data.NewBlockExecutionDataEntity(unittest.IdentifierFixture(), &execution_data.BlockExecutionData{
+			BlockID: block.ID(),
+			ChunkExecutionDatas: []*execution_data.ChunkExecutionData{
+				{TrieUpdate: update},
+				{TrieUpdate: nil},
+			},
+		})
+		seal := unittest.Seal.Fixture(unittest.Seal.WithBlockID(block.ID()))
+		seal.FinalState = flow.StateCommitment(current.RootHash())
+		f.seals.On("FinalizedSealForBlock", block.ID()).Return(seal, nil).Maybe()
+
+		f.blocks = append(f.blocks, block)
+		f.commits = append(f.commits, seal.FinalState)
+	}
+	return f
+}
+
+func (f *indexFixture) newIndex(t *testing.T, capacity int) *Index {
+	idx, err := New(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		capacity,
+		f.blocks[0].Height,
+		f.rootTrie,
+		f.headers,
+		f.seals,
+		f.executionData,
+	)
+	require.NoError(t, err)
+	idx.OnHighestAvailableHeight(f.blocks[len(f.blocks)-1].Height)
+	return idx
+}
+
+func TestIndex(t *testing.T) {
+	f := newIndexFixture(t, 3)
+	idx := f.newIndex(t, 10)
+
+	err := idx.indexAvailable(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, f.blocks[len(f.blocks)-1].Height, idx.IndexedHeight())
+
+	for i, block := range f.blocks {
+		commit, batchProof, err := idx.RegistersWithProof(block.ID(), []flow.RegisterID{f.registerID})
+		require.NoError(t, err)
+		assert.Equal(t, f.commits[i], commit)
+
+		values, err := access.VerifyRegisterProof(commit, []flow.RegisterID{f.registerID}, batchProof)
+		require.NoError(t, err)
+		if i == 0 {
+			assert.Nil(t, values[0])
+		} else {
+			assert.Equal(t, flow.RegisterValue{byte(i)}, values[0])
+		}
+	}
+
+	_, _, err = idx.RegistersWithProof(unittest.IdentifierFixture(), []flow.RegisterID{f.registerID})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+// TestIndex_Capacity tests that only the execution states of the most recent blocks are retained.
+func TestIndex_Capacity(t *testing.T) {
+	f := newIndexFixture(t, 3)
+	idx := f.newIndex(t, 2)
+
+	err := idx.indexAvailable(context.Background())
+	require.NoError(t, err)
+
+	for _, block := range f.blocks[:2] {
+		_, _, err := idx.RegistersWithProof(block.ID(), []flow.RegisterID{f.registerID})
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	}
+	for _, block := range f.blocks[2:] {
+		_, _, err := idx.RegistersWithProof(block.ID(), []flow.RegisterID{f.registerID})
+		assert.NoError(t, err)
+	}
+}
+
+// TestIndex_SealMismatch tests that an execution state which does not match the sealed state is rejected.
+func TestIndex_SealMismatch(t *testing.T) {
+	f := newIndexFixture(t, 1)
+	f.seals = storagemock.NewSeals(t)
+	f.seals.On("FinalizedSealForBlock", f.blocks[1].ID()).Return(unittest.Seal.Fixture(), nil)
+	idx := f.newIndex(t, 10)
+
+	err := idx.indexAvailable(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, f.blocks[0].Height, idx.IndexedHeight())
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/hex"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// The historical protocol state models are not part of the generated OpenAPI models, as the Access
+// API specification does not define protocol state endpoints yet.
+
+type Identity struct {
+	NodeId  string `json:"node_id"`
+	Role    string `json:"role"`
+	Address string `json:"address"`
+	Weight  string `json:"weight"`
+	Ejected bool   `json:"ejected"`
+	// Hex encoded public keys of the node.
+	StakingKey    string `json:"staking_key"`
+	NetworkingKey string `json:"networking_key"`
+}
+
+type Epoch struct {
+	Counter            string `json:"counter"`
+	Phase              string `json:"phase"`
+	FirstHeight        string `json:"first_height,omitempty"`
+	FinalHeight        string `json:"final_height,omitempty"`
+	FirstView          string `json:"first_view"`
+	DkgPhase1FinalView string `json:"dkg_phase_1_final_view"`
+	DkgPhase2FinalView string `json:"dkg_phase_2_final_view"`
+	DkgPhase3FinalView string `json:"dkg_phase_3_final_view"`
+	FinalView          string `json:"final_view"`
+	// Base64 encoded random source of the epoch.
+	RandomSource string `json:"random_source"`
+	// Node IDs of the members of each collector cluster.
+	Clusters [][]string `json:"clusters"`
+	// Hex encoded group key of the random beacon, empty if the epoch has not been committed.
+	DkgGroupKey string `json:"dkg_group_key,omitempty"`
+}
+
+func (i *Identity) Build(identity *flow.Identity) {
+	i.NodeId = identity.NodeID.String()
+	i.Role = identity.Role.String()
+	i.Address = identity.Address
+	i.Weight = util.FromUint64(identity.Weight)
+	i.Ejected = identity.Ejected
+	if identity.StakingPubKey != nil {
+		i.StakingKey = hex.EncodeToString(identity.StakingPubKey.Encode())
+	}
+	if identity.NetworkPubKey != nil {
+		i.NetworkingKey = hex.EncodeToString(identity.NetworkPubKey.Encode())
+	}
+}
+
+func BuildIdentities(identities flow.IdentityList) []Identity {
+	response := make([]Identity, len(identities))
+	for i, identity := range identities {
+		response[i].Build(identity)
+	}
+	return response
+}
+
+func (e *Epoch) Build(epoch *access.Epoch) {
+	setup := epoch.Setup
+
+	e.Counter = util.FromUint64(setup.Counter)
+	e.Phase = epoch.Phase.String()
+	if epoch.FirstHeight != nil {
+		e.FirstHeight = util.FromUint64(*epoch.FirstHeight)
+	}
+	if epoch.FinalHeight != nil {
+		e.FinalHeight = util.FromUint64(*epoch.FinalHeight)
+	}
+	e.FirstView = util.FromUint64(setup.FirstView)
+	e.DkgPhase1FinalView = util.FromUint64(setup.DKGPhase1FinalView)
+	e.DkgPhase2FinalView = util.FromUint64(setup.DKGPhase2FinalView)
+	e.DkgPhase3FinalView = util.FromUint64(setup.DKGPhase3FinalView)
+	e.FinalView = util.FromUint64(setup.FinalView)
+	e.RandomSource = util.ToBase64(setup.RandomSource)
+
+	e.Clusters = make([][]string, len(setup.Assignments))
+	for i, assignment := range setup.Assignments {
+		e.Clusters[i] = make([]string, len(assignment))
+		for j, nodeID := range assignment {
+			e.Clusters[i][j] = nodeID.String()
+		}
+	}
+
+	if epoch.Commit != nil && epoch.Commit.DKGGroupKey != nil {
+		e.DkgGroupKey = hex.EncodeToString(epoch.Commit.DKGGroupKey.Encode())
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/hex"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// The register proof models are not part of the generated OpenAPI models, as the Access API
+// specification does not define proof endpoints yet.
+
+type RegisterId struct {
+	// Hex encoded owner of the register, empty for global registers.
+	Owner string `json:"owner"`
+	// Hex encoded key of the register.
+	Key string `json:"key"`
+}
+
+type RegisterProof struct {
+	BlockId         string       `json:"block_id"`
+	StateCommitment string       `json:"state_commitment"`
+	Registers       []RegisterId `json:"registers"`
+	// Base64 encoded batch proof of the register values against the state commitment.
+	Proof string `json:"proof"`
+}
+
+type AccountProof struct {
+	BlockId         string `json:"block_id"`
+	StateCommitment string `json:"state_commitment"`
+	Address         string `json:"address"`
+	// Base64 encoded batch proof of the account registers against the state commitment.
+	Proof string `json:"proof"`
+}
+
+func (r *RegisterId) Build(registerID flow.RegisterID) {
+	r.Owner = hex.EncodeToString([]byte(registerID.Owner))
+	r.Key = hex.EncodeToString([]byte(registerID.Key))
+}
+
+func (r *RegisterProof) Build(proof *access.RegisterProof) {
+	registers := make([]RegisterId, len(proof.RegisterIDs))
+	for i, registerID := range proof.RegisterIDs {
+		registers[i].Build(registerID)
+	}
+
+	r.BlockId = proof.BlockID.String()
+	r.StateCommitment = hex.EncodeToString(proof.StateCommitment[:])
+	r.Registers = registers
+	r.Proof = util.ToBase64(ledger.EncodeTrieBatchProof(proof.Proof))
+}
+
+func (a *AccountProof) Build(proof *access.AccountProof) {
+	a.BlockId = proof.BlockID.String()
+	a.StateCommitment = hex.EncodeToString(proof.StateCommitment[:])
+	a.Address = proof.Address.String()
+	a.Proof = util.ToBase64(ledger.EncodeTrieBatchProof(proof.Proof))
+}
@@ -0,0 +1,26 @@
+package request
+
+type GetProtocolStateAtHeight struct {
+	Height uint64
+}
+
+func (g *GetProtocolStateAtHeight) Build(r *Request) error {
+	return g.Parse(r.GetQueryParam(heightQuery))
+}
+
+func (g *GetProtocolStateAtHeight) Parse(rawHeight string) error {
+	var height Height
+	err := height.Parse(rawHeight)
+	if err != nil {
+		return err
+	}
+
+	g.Height = height.Flow()
+
+	// default to last block
+	if g.Height == EmptyHeight {
+		g.Height = FinalHeight
+	}
+
+	return nil
+}
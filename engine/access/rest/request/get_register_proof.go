@@ -0,0 +1,93 @@
+package request
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const registersQuery = "registers"
+
+// MaxRegistersLength is the maximum number of registers which can be requested at a time.
+const MaxRegistersLength = 100
+
+type GetRegisterProof struct {
+	RegisterIDs []flow.RegisterID
+	Height      uint64
+}
+
+func (g *GetRegisterProof) Build(r *Request) error {
+	return g.Parse(
+		r.GetQueryParams(registersQuery),
+		r.GetQueryParam(blockHeightQuery),
+	)
+}
+
+func (g *GetRegisterProof) Parse(rawRegisters []string, rawHeight string) error {
+	if len(rawRegisters) == 0 {
+		return fmt.Errorf("at least one register must be provided")
+	}
+	if len(rawRegisters) > MaxRegistersLength {
+		return fmt.Errorf("at most %d registers can be requested at a time", MaxRegistersLength)
+	}
+
+	registerIDs := make([]flow.RegisterID, 0, len(rawRegisters))
+	for _, raw := range rawRegisters {
+		registerID, err := ParseRegisterID(raw)
+		if err != nil {
+			return err
+		}
+		registerIDs = append(registerIDs, registerID)
+	}
+
+	var height Height
+	err := height.Parse(rawHeight)
+	if err != nil {
+		return err
+	}
+
+	g.RegisterIDs = registerIDs
+	g.Height = height.Flow()
+
+	// default to last block
+	if g.Height == EmptyHeight {
+		g.Height = SealedHeight
+	}
+
+	return nil
+}
+
+// ParseRegisterID parses a register ID of the form <owner>.<key>, where the owner and the key are
+// hex encoded. The owner is empty for global registers.
+func ParseRegisterID(raw string) (flow.RegisterID, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 2 {
+		return flow.RegisterID{}, fmt.Errorf("invalid register %q: expected <owner>.<key>", raw)
+	}
+	owner, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return flow.RegisterID{}, fmt.Errorf("invalid register owner %q: %w", parts[0], err)
+	}
+	key, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return flow.RegisterID{}, fmt.Errorf("invalid register key %q: %w", parts[1], err)
+	}
+	if len(owner) != 0 && len(owner) != flow.AddressLength {
+		return flow.RegisterID{}, fmt.Errorf("invalid register owner %q: must be empty or an address", parts[0])
+	}
+	if len(key) == 0 {
+		return flow.RegisterID{}, fmt.Errorf("invalid register %q: key must not be empty", raw)
+	}
+	// global registers have an empty owner, so the owner is not converted to an address
+	return flow.RegisterID{Owner: string(owner), Key: string(key)}, nil
+}
+
+type GetAccountProof struct {
+	GetAccount
+}
+
+func (g *GetAccountProof) Build(r *Request) error {
+	return g.GetAccount.Build(r)
+}
@@ -0,0 +1,59 @@
+package request
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+type GetSealingSegmentManifest struct {
+	// BlockID is the ID of the head of the sealing segment, or flow.ZeroID for the latest finalized block.
+	BlockID flow.Identifier
+}
+
+func (g *GetSealingSegmentManifest) Build(r *Request) error {
+	return g.Parse(r.GetQueryParam(blockIDQuery))
+}
+
+func (g *GetSealingSegmentManifest) Parse(rawID string) error {
+	var id ID
+	err := id.Parse(rawID)
+	if err != nil {
+		return err
+	}
+	g.BlockID = id.Flow()
+	return nil
+}
+
+type GetSealingSegmentChunk struct {
+	HeadID flow.Identifier
+	Index  uint
+}
+
+func (g *GetSealingSegmentChunk) Build(r *Request) error {
+	return g.Parse(
+		r.GetVar(idQuery),
+		r.GetVar(indexVar),
+	)
+}
+
+func (g *GetSealingSegmentChunk) Parse(rawID string, rawIndex string) error {
+	var id ID
+	err := id.Parse(rawID)
+	if err != nil {
+		return err
+	}
+	if id.Flow() == flow.ZeroID {
+		return fmt.Errorf("invalid ID format")
+	}
+
+	index, err := util.ToUint64(rawIndex)
+	if err != nil {
+		return fmt.Errorf("invalid chunk index: %w", err)
+	}
+
+	g.HeadID = id.Flow()
+	g.Index = uint(index)
+	return nil
+}
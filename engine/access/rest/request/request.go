@@ -60,6 +60,36 @@ func (rd *Request) GetAccountKeyRequest() (GetAccountKey, error) {
 	return req, err
 }
 
+func (rd *Request) GetAccountProofRequest() (GetAccountProof, error) {
+	var req GetAccountProof
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) GetRegisterProofRequest() (GetRegisterProof, error) {
+	var req GetRegisterProof
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) GetProtocolStateAtHeightRequest() (GetProtocolStateAtHeight, error) {
+	var req GetProtocolStateAtHeight
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) GetSealingSegmentManifestRequest() (GetSealingSegmentManifest, error) {
+	var req GetSealingSegmentManifest
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) GetSealingSegmentChunkRequest() (GetSealingSegmentChunk, error) {
+	var req GetSealingSegmentChunk
+	err := req.Build(rd)
+	return req, err
+}
+
 func (rd *Request) GetExecutionResultByBlockIDsRequest() (GetExecutionResultByBlockIDs, error) {
 	var req GetExecutionResultByBlockIDs
 	err := req.Build(rd)
@@ -129,6 +129,15 @@ func (h *Handler) errorHandler(w http.ResponseWriter, err error, errorLogger zer
 			h.errorResponse(w, http.StatusServiceUnavailable, msg, errorLogger)
 			return
 		}
+		if se.Code() == codes.ResourceExhausted {
+			msg := fmt.Sprintf("Failed to process request: %s", se.Message())
+			h.errorResponse(w, http.StatusTooManyRequests, msg, errorLogger)
+			return
+		}
+		if se.Code() == codes.Unimplemented {
+			h.errorResponse(w, http.StatusNotImplemented, se.Message(), errorLogger)
+			return
+		}
 	}
 
 	// stop going further - catch all error
@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+)
+
+// GetIdentitiesAtHeight handler retrieves the identity table at a finalized block height and returns the response
+func GetIdentitiesAtHeight(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetProtocolStateAtHeightRequest()
+	if err != nil {
+		return nil, models.NewBadRequestError(err)
+	}
+
+	history, err := protocolHistoryAPI(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := protocolStateHeight(r, backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := history.GetIdentitiesAtHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BuildIdentities(identities), nil
+}
+
+// GetEpochAtHeight handler retrieves the epoch at a finalized block height and returns the response
+func GetEpochAtHeight(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetProtocolStateAtHeightRequest()
+	if err != nil {
+		return nil, models.NewBadRequestError(err)
+	}
+
+	history, err := protocolHistoryAPI(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := protocolStateHeight(r, backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	epoch, err := history.GetEpochAtHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.Epoch
+	response.Build(epoch)
+	return response, nil
+}
+
+// protocolHistoryAPI returns the historical protocol state API of the backend, or an error if the
+// backend does not provide the historical protocol state.
+func protocolHistoryAPI(backend access.API) (access.ProtocolHistoryAPI, error) {
+	history, ok := backend.(access.ProtocolHistoryAPI)
+	if !ok {
+		err := fmt.Errorf("historical protocol state is not supported by this node")
+		return nil, models.NewRestError(http.StatusNotImplemented, err.Error(), err)
+	}
+	return history, nil
+}
+
+// protocolStateHeight returns the requested height, resolving the special height values 'final'
+// and 'sealed'.
+func protocolStateHeight(r *request.Request, backend access.API, height uint64) (uint64, error) {
+	if height != request.FinalHeight && height != request.SealedHeight {
+		return height, nil
+	}
+
+	header, _, err := backend.GetLatestBlockHeader(r.Context(), height == request.SealedHeight)
+	if err != nil {
+		return 0, err
+	}
+	return header.Height, nil
+}
@@ -0,0 +1,182 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	mocktestify "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// protocolHistoryBackend is a backend providing the historical protocol state API.
+type protocolHistoryBackend struct {
+	*mock.API
+	*mock.ProtocolHistoryAPI
+}
+
+func newProtocolHistoryBackend(t *testing.T) *protocolHistoryBackend {
+	return &protocolHistoryBackend{
+		API:                mock.NewAPI(t),
+		ProtocolHistoryAPI: mock.NewProtocolHistoryAPI(t),
+	}
+}
+
+// TestGetIdentitiesAtHeight tests local getIdentitiesAtHeight request.
+func TestGetIdentitiesAtHeight(t *testing.T) {
+	backend := newProtocolHistoryBackend(t)
+	identities := flow.IdentityList{
+		{NodeID: unittest.IdentifierFixture(), Role: flow.RoleConsensus, Address: "consensus-1.flow:3569", Weight: 1000},
+		{NodeID: unittest.IdentifierFixture(), Role: flow.RoleExecution, Address: "execution-1.flow:3569", Weight: 0},
+	}
+
+	t.Run("get identities at height", func(t *testing.T) {
+		backend.ProtocolHistoryAPI.
+			On("GetIdentitiesAtHeight", mocktestify.Anything, uint64(42)).
+			Return(identities, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", "/v1/identities?height=42", nil)
+		require.NoError(t, err)
+
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response []models.Identity
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response, len(identities))
+		for i, identity := range identities {
+			assert.Equal(t, identity.NodeID.String(), response[i].NodeId)
+			assert.Equal(t, identity.Role.String(), response[i].Role)
+			assert.Equal(t, identity.Address, response[i].Address)
+			assert.Equal(t, util.FromUint64(identity.Weight), response[i].Weight)
+		}
+	})
+
+	t.Run("get identities at latest finalized height", func(t *testing.T) {
+		block := unittest.BlockHeaderFixture()
+
+		backend.API.
+			On("GetLatestBlockHeader", mocktestify.Anything, false).
+			Return(block, flow.BlockStatusFinalized, nil).
+			Once()
+		backend.ProtocolHistoryAPI.
+			On("GetIdentitiesAtHeight", mocktestify.Anything, block.Height).
+			Return(identities, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", "/v1/identities", nil)
+		require.NoError(t, err)
+
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("height not archived", func(t *testing.T) {
+		backend.ProtocolHistoryAPI.
+			On("GetIdentitiesAtHeight", mocktestify.Anything, uint64(7)).
+			Return(nil, status.Error(codes.NotFound, "below root height")).
+			Once()
+
+		req, err := http.NewRequest("GET", "/v1/identities?height=7", nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusNotFound, `{"code":404, "message":"Flow resource not found: below root height"}`, backend)
+	})
+
+	t.Run("invalid height", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/identities?height=abc", nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"invalid height format"}`, backend)
+	})
+
+	t.Run("unsupported by backend", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/identities?height=42", nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusNotImplemented,
+			`{"code":501, "message":"historical protocol state is not supported by this node"}`, mock.NewAPI(t))
+	})
+}
+
+// TestGetEpochAtHeight tests local getEpochAtHeight request.
+func TestGetEpochAtHeight(t *testing.T) {
+	backend := newProtocolHistoryBackend(t)
+
+	t.Run("get epoch at height", func(t *testing.T) {
+		firstHeight := uint64(10)
+		finalHeight := uint64(99)
+		nodeID := unittest.IdentifierFixture()
+		epoch := &access.Epoch{
+			Phase:       flow.EpochPhaseSetup,
+			FirstHeight: &firstHeight,
+			FinalHeight: &finalHeight,
+			Setup: &flow.EpochSetup{
+				Counter:            3,
+				FirstView:          100,
+				DKGPhase1FinalView: 150,
+				DKGPhase2FinalView: 160,
+				DKGPhase3FinalView: 170,
+				FinalView:          199,
+				Assignments:        flow.AssignmentList{{nodeID}},
+				RandomSource:       unittest.SeedFixture(flow.EpochSetupRandomSourceLength),
+			},
+		}
+
+		backend.ProtocolHistoryAPI.
+			On("GetEpochAtHeight", mocktestify.Anything, uint64(42)).
+			Return(epoch, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", "/v1/epochs?height=42", nil)
+		require.NoError(t, err)
+
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response models.Epoch
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "3", response.Counter)
+		assert.Equal(t, flow.EpochPhaseSetup.String(), response.Phase)
+		assert.Equal(t, "10", response.FirstHeight)
+		assert.Equal(t, "99", response.FinalHeight)
+		assert.Equal(t, "100", response.FirstView)
+		assert.Equal(t, "199", response.FinalView)
+		assert.Equal(t, [][]string{{nodeID.String()}}, response.Clusters)
+		assert.Empty(t, response.DkgGroupKey)
+	})
+
+	t.Run("height not archived", func(t *testing.T) {
+		backend.ProtocolHistoryAPI.
+			On("GetEpochAtHeight", mocktestify.Anything, uint64(7)).
+			Return(nil, status.Error(codes.NotFound, "below root height")).
+			Once()
+
+		req, err := http.NewRequest("GET", "/v1/epochs?height=7", nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusNotFound, `{"code":404, "message":"Flow resource not found: below root height"}`, backend)
+	})
+
+	t.Run("unsupported by backend", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/epochs?height=42", nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusNotImplemented,
+			`{"code":501, "message":"historical protocol state is not supported by this node"}`, mock.NewAPI(t))
+	})
+}
@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// GetRegisterProof handler retrieves a batch proof of register values at a sealed block height and returns the response
+func GetRegisterProof(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetRegisterProofRequest()
+	if err != nil {
+		return nil, models.NewBadRequestError(err)
+	}
+
+	proofs, err := registerProofAPI(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := proofBlockHeader(r, backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := proofs.GetRegisterWithProof(r.Context(), req.RegisterIDs, header.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.RegisterProof
+	response.Build(proof)
+	return response, nil
+}
+
+// GetAccountProof handler retrieves a batch proof of the registers defining an account at a sealed block height and returns the response
+func GetAccountProof(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetAccountProofRequest()
+	if err != nil {
+		return nil, models.NewBadRequestError(err)
+	}
+
+	proofs, err := registerProofAPI(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := proofBlockHeader(r, backend, req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := proofs.GetAccountWithProof(r.Context(), req.Address, header.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.AccountProof
+	response.Build(proof)
+	return response, nil
+}
+
+// registerProofAPI returns the proof API of the backend, or an error if the backend does not
+// provide proofs.
+func registerProofAPI(backend access.API) (access.RegisterProofAPI, error) {
+	proofs, ok := backend.(access.RegisterProofAPI)
+	if !ok {
+		err := fmt.Errorf("proofs are not supported by this node")
+		return nil, models.NewRestError(http.StatusNotImplemented, err.Error(), err)
+	}
+	return proofs, nil
+}
+
+// proofBlockHeader returns the header of the block at the requested height, resolving the special
+// height value 'sealed'. Proofs are only available for sealed blocks, so 'final' is rejected.
+func proofBlockHeader(r *request.Request, backend access.API, height uint64) (*flow.Header, error) {
+	if height == request.FinalHeight {
+		return nil, models.NewBadRequestError(fmt.Errorf("proofs are only available for sealed blocks"))
+	}
+	if height == request.SealedHeight {
+		header, _, err := backend.GetLatestBlockHeader(r.Context(), true)
+		if err != nil {
+			return nil, err
+		}
+		return header, nil
+	}
+
+	header, _, err := backend.GetBlockHeaderByHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
+}
@@ -0,0 +1,264 @@
+package routes
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	mocktestify "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func registerProofURL(t *testing.T, registers []string, height string) string {
+	u, err := url.ParseRequestURI("/v1/registers/proof")
+	require.NoError(t, err)
+	q := u.Query()
+
+	if len(registers) > 0 {
+		q.Add("registers", strings.Join(registers, ","))
+	}
+	if height != "" {
+		q.Add("block_height", height)
+	}
+
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func accountProofURL(t *testing.T, address string, height string) string {
+	u, err := url.ParseRequestURI(fmt.Sprintf("/v1/accounts/%s/proof", address))
+	require.NoError(t, err)
+	q := u.Query()
+
+	if height != "" {
+		q.Add("block_height", height)
+	}
+
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// proofBackend is a backend providing the register proof API.
+type proofBackend struct {
+	*mock.API
+	*mock.RegisterProofAPI
+}
+
+func newProofBackend(t *testing.T) *proofBackend {
+	return &proofBackend{
+		API:              mock.NewAPI(t),
+		RegisterProofAPI: mock.NewRegisterProofAPI(t),
+	}
+}
+
+func proofFixture() *ledger.TrieBatchProof {
+	proof := ledger.NewTrieBatchProof()
+	proof.Proofs = append(proof.Proofs, ledger.NewTrieProof())
+	return proof
+}
+
+// TestGetRegisterProof tests local getRegisterProof request.
+func TestGetRegisterProof(t *testing.T) {
+	backend := newProofBackend(t)
+
+	owner := unittest.RandomAddressFixture()
+	registerIDs := []flow.RegisterID{
+		flow.NewRegisterID(string(owner.Bytes()), "public_key_0"),
+		{Owner: "", Key: "uuid"},
+	}
+	rawRegisters := []string{
+		hex.EncodeToString(owner.Bytes()) + "." + hex.EncodeToString([]byte("public_key_0")),
+		"." + hex.EncodeToString([]byte("uuid")),
+	}
+
+	t.Run("get register proof at height", func(t *testing.T) {
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(100))
+		proof := &access.RegisterProof{
+			BlockID:         block.ID(),
+			StateCommitment: unittest.StateCommitmentFixture(),
+			RegisterIDs:     registerIDs,
+			Proof:           proofFixture(),
+		}
+
+		backend.API.
+			On("GetBlockHeaderByHeight", mocktestify.Anything, block.Height).
+			Return(block, flow.BlockStatusSealed, nil).
+			Once()
+		backend.RegisterProofAPI.
+			On("GetRegisterWithProof", mocktestify.Anything, registerIDs, block.ID()).
+			Return(proof, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", registerProofURL(t, rawRegisters, "100"), nil)
+		require.NoError(t, err)
+
+		expected := fmt.Sprintf(`{
+			"block_id": "%s",
+			"state_commitment": "%s",
+			"registers": [
+				{"owner": "%s", "key": "%s"},
+				{"owner": "", "key": "%s"}
+			],
+			"proof": "%s"
+		}`,
+			block.ID(),
+			hex.EncodeToString(proof.StateCommitment[:]),
+			hex.EncodeToString(owner.Bytes()), hex.EncodeToString([]byte("public_key_0")),
+			hex.EncodeToString([]byte("uuid")),
+			base64.StdEncoding.EncodeToString(ledger.EncodeTrieBatchProof(proof.Proof)),
+		)
+
+		assertOKResponse(t, req, expected, backend)
+	})
+
+	t.Run("get register proof at latest sealed block", func(t *testing.T) {
+		block := unittest.BlockHeaderFixture()
+		proof := &access.RegisterProof{
+			BlockID:     block.ID(),
+			RegisterIDs: registerIDs,
+			Proof:       proofFixture(),
+		}
+
+		backend.API.
+			On("GetLatestBlockHeader", mocktestify.Anything, true).
+			Return(block, flow.BlockStatusSealed, nil).
+			Once()
+		backend.RegisterProofAPI.
+			On("GetRegisterWithProof", mocktestify.Anything, registerIDs, block.ID()).
+			Return(proof, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", registerProofURL(t, rawRegisters, ""), nil)
+		require.NoError(t, err)
+
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("get register proof of unavailable state", func(t *testing.T) {
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(100))
+
+		backend.API.
+			On("GetBlockHeaderByHeight", mocktestify.Anything, block.Height).
+			Return(block, flow.BlockStatusSealed, nil).
+			Once()
+		backend.RegisterProofAPI.
+			On("GetRegisterWithProof", mocktestify.Anything, registerIDs, block.ID()).
+			Return(nil, status.Error(codes.NotFound, "not indexed")).
+			Once()
+
+		req, err := http.NewRequest("GET", registerProofURL(t, rawRegisters, "100"), nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusNotFound, `{"code":404, "message":"Flow resource not found: not indexed"}`, backend)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		tests := []struct {
+			url string
+			out string
+		}{
+			{registerProofURL(t, nil, ""), `{"code":400, "message":"at least one register must be provided"}`},
+			{registerProofURL(t, []string{"abc"}, ""), `{"code":400, "message":"invalid register \"abc\": expected <owner>.<key>"}`},
+			{registerProofURL(t, []string{"zz.00"}, ""), `{"code":400, "message":"invalid register owner \"zz\": encoding/hex: invalid byte: U+007A 'z'"}`},
+			{registerProofURL(t, []string{"00.00"}, ""), `{"code":400, "message":"invalid register owner \"00\": must be empty or an address"}`},
+			{registerProofURL(t, []string{"."}, ""), `{"code":400, "message":"invalid register \".\": key must not be empty"}`},
+			{registerProofURL(t, rawRegisters, "final"), `{"code":400, "message":"proofs are only available for sealed blocks"}`},
+		}
+
+		for i, test := range tests {
+			req, err := http.NewRequest("GET", test.url, nil)
+			require.NoError(t, err)
+			assertResponse(t, req, http.StatusBadRequest, test.out, backend)
+			require.NoError(t, err, fmt.Sprintf("test #%d failed", i))
+		}
+	})
+}
+
+// TestGetAccountProof tests local getAccountProof request.
+func TestGetAccountProof(t *testing.T) {
+	backend := newProofBackend(t)
+
+	t.Run("get account proof at height", func(t *testing.T) {
+		address := unittest.RandomAddressFixture()
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(100))
+		proof := &access.AccountProof{
+			BlockID:         block.ID(),
+			StateCommitment: unittest.StateCommitmentFixture(),
+			Address:         address,
+			Proof:           proofFixture(),
+		}
+
+		backend.API.
+			On("GetBlockHeaderByHeight", mocktestify.Anything, block.Height).
+			Return(block, flow.BlockStatusSealed, nil).
+			Once()
+		backend.RegisterProofAPI.
+			On("GetAccountWithProof", mocktestify.Anything, address, block.ID()).
+			Return(proof, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", accountProofURL(t, address.String(), "100"), nil)
+		require.NoError(t, err)
+
+		expected := fmt.Sprintf(`{
+			"block_id": "%s",
+			"state_commitment": "%s",
+			"address": "%s",
+			"proof": "%s"
+		}`,
+			block.ID(),
+			hex.EncodeToString(proof.StateCommitment[:]),
+			address,
+			base64.StdEncoding.EncodeToString(ledger.EncodeTrieBatchProof(proof.Proof)),
+		)
+
+		assertOKResponse(t, req, expected, backend)
+	})
+
+	t.Run("get proof of missing account", func(t *testing.T) {
+		address := unittest.RandomAddressFixture()
+		block := unittest.BlockHeaderFixture()
+
+		backend.API.
+			On("GetLatestBlockHeader", mocktestify.Anything, true).
+			Return(block, flow.BlockStatusSealed, nil).
+			Once()
+		backend.RegisterProofAPI.
+			On("GetAccountWithProof", mocktestify.Anything, address, block.ID()).
+			Return(nil, status.Error(codes.NotFound, "account not found")).
+			Once()
+
+		req, err := http.NewRequest("GET", accountProofURL(t, address.String(), "sealed"), nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusNotFound, `{"code":404, "message":"Flow resource not found: account not found"}`, backend)
+	})
+
+	t.Run("proofs not supported", func(t *testing.T) {
+		req, err := http.NewRequest("GET", accountProofURL(t, unittest.RandomAddressFixture().String(), ""), nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusNotImplemented, `{"code":501, "message":"proofs are not supported by this node"}`, mock.NewAPI(t))
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		req, err := http.NewRequest("GET", accountProofURL(t, "123", ""), nil)
+		require.NoError(t, err)
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"invalid address"}`, backend)
+	})
+}
@@ -17,6 +17,17 @@ import (
 )
 
 func NewRouter(backend access.API, logger zerolog.Logger, chain flow.Chain, restCollector module.RestMetrics) (*mux.Router, error) {
+	return newRouter(Routes, backend, logger, chain, restCollector), nil
+}
+
+// NewSealingSegmentRouter returns a router serving only the sealing segment routes, which are consumed
+// by nodes bootstrapping from this node rather than by Access API clients.
+func NewSealingSegmentRouter(backend access.API, logger zerolog.Logger, chain flow.Chain, restCollector module.RestMetrics) (*mux.Router, error) {
+	return newRouter(SealingSegmentRoutes, backend, logger, chain, restCollector), nil
+}
+
+// newRouter returns a router serving the given routes.
+func newRouter(routes []route, backend access.API, logger zerolog.Logger, chain flow.Chain, restCollector module.RestMetrics) *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
 	v1SubRouter := router.PathPrefix("/v1").Subrouter()
 
@@ -28,7 +39,7 @@ func NewRouter(backend access.API, logger zerolog.Logger, chain flow.Chain, rest
 
 	linkGenerator := models.NewLinkGeneratorImpl(v1SubRouter)
 
-	for _, r := range Routes {
+	for _, r := range routes {
 		h := NewHandler(logger, backend, r.Handler, linkGenerator, chain)
 		v1SubRouter.
 			Methods(r.Method).
@@ -36,7 +47,7 @@ func NewRouter(backend access.API, logger zerolog.Logger, chain flow.Chain, rest
 			Name(r.Name).
 			Handler(h)
 	}
-	return router, nil
+	return router
 }
 
 type route struct {
@@ -106,6 +117,26 @@ var Routes = []route{{
 	Pattern: "/accounts/{address}/keys/{index}",
 	Name:    "getAccountKeyByIndex",
 	Handler: GetAccountKeyByIndex,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/proof",
+	Name:    "getAccountProof",
+	Handler: GetAccountProof,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/registers/proof",
+	Name:    "getRegisterProof",
+	Handler: GetRegisterProof,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/identities",
+	Name:    "getIdentitiesAtHeight",
+	Handler: GetIdentitiesAtHeight,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/epochs",
+	Name:    "getEpochAtHeight",
+	Handler: GetEpochAtHeight,
 }, {
 	Method:  http.MethodGet,
 	Pattern: "/events",
@@ -123,6 +154,19 @@ var Routes = []route{{
 	Handler: GetNodeVersionInfo,
 }}
 
+// SealingSegmentRoutes are served by a separate server, see NewSealingSegmentRouter.
+var SealingSegmentRoutes = []route{{
+	Method:  http.MethodGet,
+	Pattern: "/sealing_segments",
+	Name:    "getSealingSegmentManifest",
+	Handler: GetSealingSegmentManifest,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/sealing_segments/{id}/chunks/{index}",
+	Name:    "getSealingSegmentChunk",
+	Handler: GetSealingSegmentChunk,
+}}
+
 var routeUrlMap = map[string]string{}
 var routeRE = regexp.MustCompile(`(?i)/v1/(\w+)(/(\w+)(/(\w+))?)?`)
 
@@ -130,6 +174,9 @@ func init() {
 	for _, r := range Routes {
 		routeUrlMap[r.Pattern] = r.Name
 	}
+	for _, r := range SealingSegmentRoutes {
+		routeUrlMap[r.Pattern] = r.Name
+	}
 }
 
 func URLToRoute(url string) (string, error) {
@@ -164,7 +211,9 @@ func normalizeURL(url string) (string, error) {
 	case 64:
 		// id based resource. e.g. /v1/blocks/1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef
 		parts = append(parts, "{id}")
-		if matches[0][5] != "" {
+		if matches[0][5] == "chunks" {
+			parts = append(parts, "chunks", "{index}")
+		} else if matches[0][5] != "" {
 			parts = append(parts, matches[0][5])
 		}
 	case 16:
@@ -172,6 +221,8 @@ func normalizeURL(url string) (string, error) {
 		parts = append(parts, "{address}")
 		if matches[0][5] == "keys" {
 			parts = append(parts, "keys", "{index}")
+		} else if matches[0][5] != "" {
+			parts = append(parts, matches[0][5])
 		}
 	default:
 		// named resource. e.g. /v1/network/parameters
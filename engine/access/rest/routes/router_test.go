@@ -74,6 +74,36 @@ func TestParseURL(t *testing.T) {
 			url:      "/v1/accounts/6a587be304c1224c/keys/0",
 			expected: "getAccountKeyByIndex",
 		},
+		{
+			name:     "/v1/accounts/{address}/proof",
+			url:      "/v1/accounts/6a587be304c1224c/proof",
+			expected: "getAccountProof",
+		},
+		{
+			name:     "/v1/registers/proof",
+			url:      "/v1/registers/proof",
+			expected: "getRegisterProof",
+		},
+		{
+			name:     "/v1/sealing_segments",
+			url:      "/v1/sealing_segments",
+			expected: "getSealingSegmentManifest",
+		},
+		{
+			name:     "/v1/sealing_segments/{id}/chunks/{index}",
+			url:      "/v1/sealing_segments/53730d3f3d2d2f46cb910b16db817d3a62adaaa72fdb3a92ee373c37c5b55a76/chunks/3",
+			expected: "getSealingSegmentChunk",
+		},
+		{
+			name:     "/v1/identities",
+			url:      "/v1/identities",
+			expected: "getIdentitiesAtHeight",
+		},
+		{
+			name:     "/v1/epochs",
+			url:      "/v1/epochs",
+			expected: "getEpochAtHeight",
+		},
 		{
 			name:     "/v1/events",
 			url:      "/v1/events",
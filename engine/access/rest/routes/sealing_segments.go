@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+)
+
+// The sealing segment manifests and chunks are returned in the encoding of the segmentstream package,
+// as they are consumed by bootstrapping nodes rather than Access API clients.
+
+// GetSealingSegmentManifest handler retrieves the manifest of the sealing segment of a finalized block and returns the response
+func GetSealingSegmentManifest(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetSealingSegmentManifestRequest()
+	if err != nil {
+		return nil, models.NewBadRequestError(err)
+	}
+
+	segments, err := sealingSegmentAPI(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	// a zero block ID requests the manifest of a recent finalized block
+	return segments.GetSealingSegmentManifest(r.Context(), req.BlockID)
+}
+
+// GetSealingSegmentChunk handler retrieves a chunk of the sealing segment of a finalized block and returns the response
+func GetSealingSegmentChunk(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetSealingSegmentChunkRequest()
+	if err != nil {
+		return nil, models.NewBadRequestError(err)
+	}
+
+	segments, err := sealingSegmentAPI(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return segments.GetSealingSegmentChunk(r.Context(), req.HeadID, req.Index)
+}
+
+// sealingSegmentAPI returns the sealing segment API of the backend, or an error if the backend does
+// not stream sealing segments.
+func sealingSegmentAPI(backend access.API) (access.SealingSegmentAPI, error) {
+	segments, ok := backend.(access.SealingSegmentAPI)
+	if !ok {
+		err := fmt.Errorf("sealing segment streaming is not supported by this node")
+		return nil, models.NewRestError(http.StatusNotImplemented, err.Error(), err)
+	}
+	return segments, nil
+}
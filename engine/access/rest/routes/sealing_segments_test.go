@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	mocktestify "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/segmentstream"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// sealingSegmentBackend is a backend providing the sealing segment API.
+type sealingSegmentBackend struct {
+	*mock.API
+	*mock.SealingSegmentAPI
+}
+
+func newSealingSegmentBackend(t *testing.T) *sealingSegmentBackend {
+	return &sealingSegmentBackend{
+		API:               mock.NewAPI(t),
+		SealingSegmentAPI: mock.NewSealingSegmentAPI(t),
+	}
+}
+
+// executeSealingSegmentRequest executes the request with the router serving the sealing segment routes.
+func executeSealingSegmentRequest(req *http.Request, backend access.API) (*httptest.ResponseRecorder, error) {
+	router, err := NewSealingSegmentRouter(backend, zerolog.Nop(), flow.Testnet.Chain(), metrics.NewNoopCollector())
+	if err != nil {
+		return nil, err
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr, nil
+}
+
+func assertSealingSegmentResponse(t *testing.T, req *http.Request, status int, expectedRespBody string, backend access.API) {
+	rr, err := executeSealingSegmentRequest(req, backend)
+	require.NoError(t, err)
+	require.JSONEq(t, expectedRespBody, rr.Body.String())
+	require.Equal(t, status, rr.Code)
+}
+
+// TestGetSealingSegmentManifest tests local getSealingSegmentManifest request.
+func TestGetSealingSegmentManifest(t *testing.T) {
+	backend := newSealingSegmentBackend(t)
+
+	t.Run("get manifest of latest finalized block", func(t *testing.T) {
+		block := unittest.BlockHeaderFixture()
+		manifest := &segmentstream.Manifest{
+			HeadID:     block.ID(),
+			HeadHeight: block.Height,
+			ChunkSize:  segmentstream.DefaultChunkSize,
+		}
+
+		backend.SealingSegmentAPI.
+			On("GetSealingSegmentManifest", mocktestify.Anything, flow.ZeroID).
+			Return(manifest, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", "/v1/sealing_segments", nil)
+		require.NoError(t, err)
+
+		rr, err := executeSealingSegmentRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response segmentstream.Manifest
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, manifest.HeadID, response.HeadID)
+		assert.Equal(t, manifest.HeadHeight, response.HeadHeight)
+	})
+
+	t.Run("get manifest of unknown block", func(t *testing.T) {
+		blockID := unittest.IdentifierFixture()
+
+		backend.SealingSegmentAPI.
+			On("GetSealingSegmentManifest", mocktestify.Anything, blockID).
+			Return(nil, status.Error(codes.NotFound, "not finalized")).
+			Once()
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("/v1/sealing_segments?block_id=%s", blockID), nil)
+		require.NoError(t, err)
+
+		assertSealingSegmentResponse(t, req, http.StatusNotFound, `{"code":404, "message":"Flow resource not found: not finalized"}`, backend)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		blockID := unittest.IdentifierFixture()
+
+		backend.SealingSegmentAPI.
+			On("GetSealingSegmentManifest", mocktestify.Anything, blockID).
+			Return(nil, status.Error(codes.ResourceExhausted, "rate limited")).
+			Once()
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("/v1/sealing_segments?block_id=%s", blockID), nil)
+		require.NoError(t, err)
+
+		assertSealingSegmentResponse(t, req, http.StatusTooManyRequests, `{"code":429, "message":"Failed to process request: rate limited"}`, backend)
+	})
+
+	t.Run("not served by the public router", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/sealing_segments", nil)
+		require.NoError(t, err)
+
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("invalid block ID", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/sealing_segments?block_id=abc", nil)
+		require.NoError(t, err)
+
+		assertSealingSegmentResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"invalid ID format"}`, backend)
+	})
+
+	t.Run("unsupported by backend", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v1/sealing_segments", nil)
+		require.NoError(t, err)
+
+		assertSealingSegmentResponse(t, req, http.StatusNotImplemented,
+			`{"code":501, "message":"sealing segment streaming is not supported by this node"}`, mock.NewAPI(t))
+	})
+}
+
+// TestGetSealingSegmentChunk tests local getSealingSegmentChunk request.
+func TestGetSealingSegmentChunk(t *testing.T) {
+	backend := newSealingSegmentBackend(t)
+	headID := unittest.IdentifierFixture()
+
+	t.Run("get chunk", func(t *testing.T) {
+		block := unittest.BlockFixture()
+		chunk := &segmentstream.Chunk{
+			HeadID: headID,
+			Index:  3,
+			Blocks: []*flow.Block{&block},
+		}
+
+		backend.SealingSegmentAPI.
+			On("GetSealingSegmentChunk", mocktestify.Anything, headID, uint(3)).
+			Return(chunk, nil).
+			Once()
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("/v1/sealing_segments/%s/chunks/3", headID), nil)
+		require.NoError(t, err)
+
+		rr, err := executeSealingSegmentRequest(req, backend)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response segmentstream.Chunk
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, chunk.Index, response.Index)
+		require.Len(t, response.Blocks, 1)
+		assert.Equal(t, block.ID(), response.Blocks[0].ID())
+	})
+
+	t.Run("invalid chunk index", func(t *testing.T) {
+		req, err := http.NewRequest("GET", fmt.Sprintf("/v1/sealing_segments/%s/chunks/abc", headID), nil)
+		require.NoError(t, err)
+
+		assertSealingSegmentResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"invalid chunk index: value must be an unsigned 64 bit integer"}`, backend)
+	})
+}
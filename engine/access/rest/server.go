@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"crypto/tls"
 	"net/http"
 	"time"
 
@@ -38,3 +39,21 @@ func NewServer(serverAPI access.API, listenAddress string, logger zerolog.Logger
 		IdleTimeout:  time.Second * 60,
 	}, nil
 }
+
+// NewSealingSegmentServer returns an HTTPS server serving the sealing segment routes with the given TLS
+// config, for nodes bootstrapping from this node.
+func NewSealingSegmentServer(serverAPI access.API, listenAddress string, logger zerolog.Logger, chain flow.Chain, restCollector module.RestMetrics, tlsConfig *tls.Config) (*http.Server, error) {
+	router, err := routes.NewSealingSegmentRouter(serverAPI, logger, chain, restCollector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Server{
+		Addr:         listenAddress,
+		Handler:      router,
+		TLSConfig:    tlsConfig,
+		WriteTimeout: time.Second * 60,
+		ReadTimeout:  time.Second * 15,
+		IdleTimeout:  time.Second * 60,
+	}, nil
+}
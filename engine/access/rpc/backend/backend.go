@@ -59,6 +59,9 @@ var fixedENIdentifiers flow.IdentifierList
 // Block details related calls are handled by backendBlockDetails.
 // Event related calls are handled by backendEvents.
 // Account related calls are handled by backendAccounts.
+// Register proof related calls are handled by backendRegisterProofs.
+// Sealing segment streaming calls are handled by backendSealingSegments.
+// Historical protocol state calls are handled by backendProtocolHistory.
 //
 // All remaining calls are handled by the base Backend in this file.
 type Backend struct {
@@ -70,6 +73,9 @@ type Backend struct {
 	backendAccounts
 	backendExecutionResults
 	backendNetwork
+	backendRegisterProofs
+	backendSealingSegments
+	backendProtocolHistory
 
 	state             protocol.State
 	chainID           flow.ChainID
@@ -201,6 +207,10 @@ func New(
 			chainID:              chainID,
 			snapshotHistoryLimit: snapshotHistoryLimit,
 		},
+		backendRegisterProofs: backendRegisterProofs{
+			state:   state,
+			headers: headers,
+		},
 		collections:       collections,
 		executionReceipts: executionReceipts,
 		connFactory:       connFactory,
@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// EpochArchive provides the epochs of the protocol state for every epoch since the root block.
+type EpochArchive interface {
+	// EpochAtHeight returns the archived epoch containing the finalized block at the given height.
+	// Expected errors during normal operation:
+	//   - storage.ErrNotFound if the height is below the root height, or has not been archived yet
+	EpochAtHeight(height uint64) (*flow.ArchivedEpoch, error)
+
+	// EpochByCounter returns the archived epoch with the given counter.
+	// Expected errors during normal operation:
+	//   - storage.ErrNotFound if the epoch has not been archived
+	EpochByCounter(counter uint64) (*flow.ArchivedEpoch, error)
+
+	// IdentitiesAtHeight returns the identity table at the finalized block at the given height.
+	// Expected errors during normal operation:
+	//   - storage.ErrNotFound if the height is below the root height, or has not been archived yet
+	IdentitiesAtHeight(height uint64) (flow.IdentityList, error)
+}
+
+var _ access.ProtocolHistoryAPI = (*Backend)(nil)
+
+type backendProtocolHistory struct {
+	archive EpochArchive // nil if the node does not archive epochs
+}
+
+// SetEpochArchive enables the historical protocol state endpoints, serving them from the given archive.
+// It must be called before the backend serves any requests.
+func (b *Backend) SetEpochArchive(archive EpochArchive) {
+	b.backendProtocolHistory.archive = archive
+}
+
+// GetIdentitiesAtHeight returns the identity table at the finalized block at the given height.
+func (b *backendProtocolHistory) GetIdentitiesAtHeight(_ context.Context, height uint64) (flow.IdentityList, error) {
+	if b.archive == nil {
+		return nil, status.Errorf(codes.Unimplemented, "historical protocol state is not enabled on this node")
+	}
+
+	identities, err := b.archive.IdentitiesAtHeight(height)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(fmt.Errorf("failed to get identities at height %d: %w", height, err))
+	}
+	return identities, nil
+}
+
+// GetEpochAtHeight returns the epoch containing the finalized block at the given height.
+func (b *backendProtocolHistory) GetEpochAtHeight(_ context.Context, height uint64) (*access.Epoch, error) {
+	if b.archive == nil {
+		return nil, status.Errorf(codes.Unimplemented, "historical protocol state is not enabled on this node")
+	}
+
+	epoch, err := b.archive.EpochAtHeight(height)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(fmt.Errorf("failed to get epoch at height %d: %w", height, err))
+	}
+
+	// the final height of the epoch is defined once the first block of the next epoch is finalized
+	var finalHeight *uint64
+	next, err := b.archive.EpochByCounter(epoch.Counter() + 1)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, status.Errorf(codes.Internal, "failed to get epoch following epoch %d: %v", epoch.Counter(), err)
+	}
+	if err == nil && next.FirstHeight != nil {
+		final := *next.FirstHeight - 1
+		finalHeight = &final
+	}
+
+	return &access.Epoch{
+		Phase:       epoch.PhaseAtHeight(height),
+		FirstHeight: epoch.FirstHeight,
+		FinalHeight: finalHeight,
+		Setup:       epoch.Setup,
+		Commit:      epoch.Commit,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func (suite *Suite) newProtocolHistoryBackend(archive EpochArchive) *Backend {
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		nil,
+		nil,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+		nil,
+		false,
+		false,
+	)
+	if archive != nil {
+		backend.SetEpochArchive(archive)
+	}
+	return backend
+}
+
+func (suite *Suite) TestGetIdentitiesAtHeight() {
+	identities := flow.IdentityList{
+		{NodeID: unittest.IdentifierFixture(), Role: flow.RoleConsensus, Weight: 1000},
+		{NodeID: unittest.IdentifierFixture(), Role: flow.RoleExecution, Weight: 0},
+	}
+
+	suite.Run("identities at archived height", func() {
+		archive := backendmock.NewEpochArchive(suite.T())
+		archive.On("IdentitiesAtHeight", uint64(42)).Return(identities, nil).Once()
+		backend := suite.newProtocolHistoryBackend(archive)
+
+		actual, err := backend.GetIdentitiesAtHeight(context.Background(), 42)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(identities, actual)
+	})
+
+	suite.Run("height not archived", func() {
+		archive := backendmock.NewEpochArchive(suite.T())
+		archive.On("IdentitiesAtHeight", uint64(7)).Return(nil, fmt.Errorf("below root height: %w", storage.ErrNotFound)).Once()
+		backend := suite.newProtocolHistoryBackend(archive)
+
+		_, err := backend.GetIdentitiesAtHeight(context.Background(), 7)
+		suite.Assert().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("not enabled", func() {
+		backend := suite.newProtocolHistoryBackend(nil)
+
+		_, err := backend.GetIdentitiesAtHeight(context.Background(), 42)
+		suite.Assert().Equal(codes.Unimplemented, status.Code(err))
+	})
+}
+
+func (suite *Suite) TestGetEpochAtHeight() {
+	firstHeight := uint64(10)
+	setupPhaseHeight := uint64(50)
+	epoch := &flow.ArchivedEpoch{
+		Setup:            &flow.EpochSetup{Counter: 3, FirstView: 100, FinalView: 199},
+		FirstHeight:      &firstHeight,
+		SetupPhaseHeight: &setupPhaseHeight,
+	}
+
+	suite.Run("epoch which has ended", func() {
+		nextFirstHeight := uint64(100)
+		archive := backendmock.NewEpochArchive(suite.T())
+		archive.On("EpochAtHeight", uint64(60)).Return(epoch, nil).Once()
+		archive.On("EpochByCounter", uint64(4)).Return(&flow.ArchivedEpoch{
+			Setup:       &flow.EpochSetup{Counter: 4},
+			FirstHeight: &nextFirstHeight,
+		}, nil).Once()
+		backend := suite.newProtocolHistoryBackend(archive)
+
+		actual, err := backend.GetEpochAtHeight(context.Background(), 60)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(epoch.Setup, actual.Setup)
+		suite.Assert().Equal(flow.EpochPhaseSetup, actual.Phase)
+		suite.Assert().Equal(firstHeight, *actual.FirstHeight)
+		suite.Require().NotNil(actual.FinalHeight)
+		suite.Assert().Equal(nextFirstHeight-1, *actual.FinalHeight)
+	})
+
+	suite.Run("epoch which has not ended", func() {
+		archive := backendmock.NewEpochArchive(suite.T())
+		archive.On("EpochAtHeight", uint64(20)).Return(epoch, nil).Once()
+		archive.On("EpochByCounter", uint64(4)).Return(nil, storage.ErrNotFound).Once()
+		backend := suite.newProtocolHistoryBackend(archive)
+
+		actual, err := backend.GetEpochAtHeight(context.Background(), 20)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(flow.EpochPhaseStaking, actual.Phase)
+		suite.Assert().Nil(actual.FinalHeight)
+	})
+
+	suite.Run("height not archived", func() {
+		archive := backendmock.NewEpochArchive(suite.T())
+		archive.On("EpochAtHeight", uint64(7)).Return(nil, fmt.Errorf("below root height: %w", storage.ErrNotFound)).Once()
+		backend := suite.newProtocolHistoryBackend(archive)
+
+		_, err := backend.GetEpochAtHeight(context.Background(), 7)
+		suite.Assert().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("not enabled", func() {
+		backend := suite.newProtocolHistoryBackend(nil)
+
+		_, err := backend.GetEpochAtHeight(context.Background(), 42)
+		suite.Assert().Equal(codes.Unimplemented, status.Code(err))
+	})
+}
@@ -0,0 +1,158 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// DefaultMaxRegistersPerProof is the default maximum number of registers proven by a single request.
+const DefaultMaxRegistersPerProof = 100
+
+// RegisterProver provides batch proofs of register values at the sealed execution state of blocks.
+type RegisterProver interface {
+	// RegistersWithProof returns the sealed state commitment of the given block, and a batch proof of
+	// the values of the given registers against it.
+	// Expected errors during normal operation:
+	//   - storage.ErrNotFound if the execution state of the block is not available
+	RegistersWithProof(blockID flow.Identifier, registerIDs []flow.RegisterID) (flow.StateCommitment, *ledger.TrieBatchProof, error)
+}
+
+var _ access.RegisterProofAPI = (*Backend)(nil)
+
+type backendRegisterProofs struct {
+	state   protocol.State
+	headers storage.Headers
+	prover  RegisterProver // nil if the node does not support register proofs
+}
+
+// SetRegisterProver enables the register proof endpoints, serving proofs from the given prover.
+// It must be called before the backend serves any requests.
+func (b *Backend) SetRegisterProver(prover RegisterProver) {
+	b.backendRegisterProofs.prover = prover
+}
+
+// GetRegisterWithProof returns a batch proof of the values of the given registers at the sealed
+// execution state of the given block.
+func (b *backendRegisterProofs) GetRegisterWithProof(
+	_ context.Context,
+	registerIDs []flow.RegisterID,
+	blockID flow.Identifier,
+) (*access.RegisterProof, error) {
+	if len(registerIDs) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no registers requested")
+	}
+	if len(registerIDs) > DefaultMaxRegistersPerProof {
+		return nil, status.Errorf(codes.InvalidArgument, "too many registers requested: %d (max %d)", len(registerIDs), DefaultMaxRegistersPerProof)
+	}
+
+	commit, proof, err := b.registersWithProof(blockID, registerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &access.RegisterProof{
+		BlockID:         blockID,
+		StateCommitment: commit,
+		RegisterIDs:     registerIDs,
+		Proof:           proof,
+	}, nil
+}
+
+// GetAccountWithProof returns a batch proof of the registers defining the given account at the
+// sealed execution state of the given block. Accounts defined by more than DefaultMaxRegistersPerProof
+// registers, one per public key and per contract in addition to the account header registers, are
+// rejected with codes.ResourceExhausted.
+func (b *backendRegisterProofs) GetAccountWithProof(
+	_ context.Context,
+	address flow.Address,
+	blockID flow.Identifier,
+) (*access.AccountProof, error) {
+	// the header registers of the account determine the remaining registers to prove
+	commit, proof, err := b.registersWithProof(blockID, access.AccountHeaderRegisterIDs(address))
+	if err != nil {
+		return nil, err
+	}
+	headerValues, err := access.VerifyRegisterProof(commit, access.AccountHeaderRegisterIDs(address), proof)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read account header registers: %v", err)
+	}
+	registerIDs, err := access.AccountRegisterIDs(address, headerValues)
+	if err != nil {
+		if errors.Is(err, access.ErrAccountNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account %s not found at block %v", address, blockID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to decode account header registers: %v", err)
+	}
+	if len(registerIDs) > DefaultMaxRegistersPerProof {
+		return nil, status.Errorf(codes.ResourceExhausted, "account %s is defined by too many registers: %d (max %d)", address, len(registerIDs), DefaultMaxRegistersPerProof)
+	}
+
+	_, proof, err = b.registersWithProof(blockID, registerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &access.AccountProof{
+		BlockID:         blockID,
+		StateCommitment: commit,
+		Address:         address,
+		Proof:           proof,
+	}, nil
+}
+
+// registersWithProof returns the sealed state commitment of the given block, and a batch proof of the
+// given registers against it. Returned errors are gRPC status errors.
+func (b *backendRegisterProofs) registersWithProof(
+	blockID flow.Identifier,
+	registerIDs []flow.RegisterID,
+) (flow.StateCommitment, *ledger.TrieBatchProof, error) {
+	if b.prover == nil {
+		return flow.DummyStateCommitment, nil, status.Errorf(codes.Unimplemented, "register proofs are not enabled on this node")
+	}
+
+	err := b.checkSealed(blockID)
+	if err != nil {
+		return flow.DummyStateCommitment, nil, err
+	}
+
+	commit, proof, err := b.prover.RegistersWithProof(blockID, registerIDs)
+	if err != nil {
+		return flow.DummyStateCommitment, nil, rpc.ConvertStorageError(fmt.Errorf("failed to prove registers at block %v: %w", blockID, err))
+	}
+	return commit, proof, nil
+}
+
+// checkSealed returns a gRPC status error if the given block is not a finalized and sealed block.
+func (b *backendRegisterProofs) checkSealed(blockID flow.Identifier) error {
+	header, err := b.headers.ByBlockID(blockID)
+	if err != nil {
+		return rpc.ConvertStorageError(err)
+	}
+	finalizedID, err := b.headers.BlockIDByHeight(header.Height)
+	if err != nil {
+		return rpc.ConvertStorageError(err)
+	}
+	if finalizedID != blockID {
+		return status.Errorf(codes.NotFound, "block %v is not finalized", blockID)
+	}
+
+	sealed, err := b.state.Sealed().Head()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+	if header.Height > sealed.Height {
+		return status.Errorf(codes.FailedPrecondition, "block %v at height %d is not sealed (latest sealed height %d)", blockID, header.Height, sealed.Height)
+	}
+	return nil
+}
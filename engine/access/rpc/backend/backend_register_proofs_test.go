@@ -0,0 +1,240 @@
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// registerStateProver returns a prover creating proofs of the given registers for the given block.
+func registerStateProver(t *testing.T, blockID flow.Identifier, registers map[flow.RegisterID]flow.RegisterValue) *backendmock.RegisterProver {
+	paths := make([]ledger.Path, 0, len(registers))
+	payloads := make([]ledger.Payload, 0, len(registers))
+	for registerID, value := range registers {
+		key := convert.RegisterIDToLedgerKey(registerID)
+		path, err := pathfinder.KeyToPath(key, access.RegisterProofPathFinderVersion)
+		require.NoError(t, err)
+		paths = append(paths, path)
+		payloads = append(payloads, *ledger.NewPayload(key, value))
+	}
+	state, _, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, true)
+	require.NoError(t, err)
+
+	prover := backendmock.NewRegisterProver(t)
+	prover.
+		On("RegistersWithProof", blockID, mock.Anything).
+		Return(func(_ flow.Identifier, registerIDs []flow.RegisterID) (flow.StateCommitment, *ledger.TrieBatchProof, error) {
+			// as the execution state does for unset registers, expand the trie with empty payloads
+			// without changing its root hash
+			provenPaths := make([]ledger.Path, 0, len(registerIDs))
+			var unsetPaths []ledger.Path
+			var unsetPayloads []ledger.Payload
+			for _, registerID := range registerIDs {
+				path, err := pathfinder.KeyToPath(convert.RegisterIDToLedgerKey(registerID), access.RegisterProofPathFinderVersion)
+				require.NoError(t, err)
+				provenPaths = append(provenPaths, path)
+				if _, ok := registers[registerID]; !ok {
+					unsetPaths = append(unsetPaths, path)
+					unsetPayloads = append(unsetPayloads, *ledger.EmptyPayload())
+				}
+			}
+			expanded, _, err := trie.NewTrieWithUpdatedRegisters(state, unsetPaths, unsetPayloads, false)
+			require.NoError(t, err)
+			return flow.StateCommitment(state.RootHash()), expanded.UnsafeProofs(provenPaths), nil
+		})
+	return prover
+}
+
+// setupSealedBlock sets up the storage and state mocks for a finalized block below the latest sealed block.
+func (suite *Suite) setupSealedBlock() *flow.Header {
+	header := unittest.BlockHeaderFixture()
+	sealed := unittest.BlockHeaderWithParentFixture(header)
+
+	suite.headers.On("ByBlockID", header.ID()).Return(header, nil)
+	suite.headers.On("BlockIDByHeight", header.Height).Return(header.ID(), nil)
+	suite.state.On("Sealed").Return(suite.snapshot, nil)
+	suite.snapshot.On("Head").Return(sealed, nil)
+
+	return header
+}
+
+func (suite *Suite) newRegisterProofsBackend(prover RegisterProver) *Backend {
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		suite.headers,
+		nil,
+		nil,
+		nil,
+		nil,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+		nil,
+		false,
+		false,
+	)
+	if prover != nil {
+		backend.SetRegisterProver(prover)
+	}
+	return backend
+}
+
+func (suite *Suite) TestGetRegisterWithProof() {
+	header := suite.setupSealedBlock()
+	owner := string(unittest.RandomAddressFixture().Bytes())
+	registers := map[flow.RegisterID]flow.RegisterValue{
+		flow.NewRegisterID(owner, "a"): []byte{1, 2, 3},
+		flow.NewRegisterID(owner, "b"): []byte{4, 5},
+		{Owner: "", Key: "uuid"}:       []byte{6},
+	}
+	requested := []flow.RegisterID{
+		flow.NewRegisterID(owner, "b"),
+		flow.NewRegisterID(owner, "unset"),
+		flow.NewRegisterID("", "uuid"),
+	}
+
+	suite.Run("proof verifies", func() {
+		backend := suite.newRegisterProofsBackend(registerStateProver(suite.T(), header.ID(), registers))
+
+		registerProof, err := backend.GetRegisterWithProof(context.Background(), requested, header.ID())
+		suite.Require().NoError(err)
+		suite.Assert().Equal(header.ID(), registerProof.BlockID)
+
+		values, err := registerProof.Verify(registerProof.StateCommitment)
+		suite.Require().NoError(err)
+		suite.Assert().Equal([]flow.RegisterValue{registers[requested[0]], nil, registers[requested[2]]}, values)
+	})
+
+	suite.Run("not enabled", func() {
+		backend := suite.newRegisterProofsBackend(nil)
+
+		_, err := backend.GetRegisterWithProof(context.Background(), requested, header.ID())
+		suite.Assert().Equal(codes.Unimplemented, status.Code(err))
+	})
+
+	suite.Run("no registers", func() {
+		backend := suite.newRegisterProofsBackend(backendmock.NewRegisterProver(suite.T()))
+
+		_, err := backend.GetRegisterWithProof(context.Background(), nil, header.ID())
+		suite.Assert().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("too many registers", func() {
+		backend := suite.newRegisterProofsBackend(backendmock.NewRegisterProver(suite.T()))
+
+		registerIDs := make([]flow.RegisterID, DefaultMaxRegistersPerProof+1)
+		_, err := backend.GetRegisterWithProof(context.Background(), registerIDs, header.ID())
+		suite.Assert().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("block not sealed", func() {
+		backend := suite.newRegisterProofsBackend(backendmock.NewRegisterProver(suite.T()))
+
+		unsealed := unittest.BlockHeaderWithParentFixture(header)
+		unsealed.Height = header.Height + 2
+		suite.headers.On("ByBlockID", unsealed.ID()).Return(unsealed, nil)
+		suite.headers.On("BlockIDByHeight", unsealed.Height).Return(unsealed.ID(), nil)
+
+		_, err := backend.GetRegisterWithProof(context.Background(), requested, unsealed.ID())
+		suite.Assert().Equal(codes.FailedPrecondition, status.Code(err))
+	})
+
+	suite.Run("state not available", func() {
+		prover := backendmock.NewRegisterProver(suite.T())
+		prover.On("RegistersWithProof", header.ID(), requested).Return(flow.DummyStateCommitment, nil, storage.ErrNotFound)
+		backend := suite.newRegisterProofsBackend(prover)
+
+		_, err := backend.GetRegisterWithProof(context.Background(), requested, header.ID())
+		suite.Assert().Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+func (suite *Suite) TestGetAccountWithProof() {
+	header := suite.setupSealedBlock()
+	address := unittest.RandomAddressFixture()
+
+	accountKey := flow.AccountPublicKey{
+		Index:     0,
+		PublicKey: unittest.KeyFixture(crypto.ECDSAP256).PublicKey(),
+		SignAlgo:  crypto.ECDSAP256,
+		HashAlgo:  hash.SHA3_256,
+		Weight:    1000,
+	}
+	encodedKey, err := flow.EncodeAccountPublicKey(accountKey)
+	suite.Require().NoError(err)
+	accountStatus := environment.NewAccountStatus()
+	accountStatus.SetPublicKeyCount(1)
+	contractNames, err := cbor.Marshal([]string{"Foo"})
+	suite.Require().NoError(err)
+	code := []byte("access(all) contract Foo {}")
+
+	registers := map[flow.RegisterID]flow.RegisterValue{
+		flow.AccountStatusRegisterID(address):   accountStatus.ToBytes(),
+		flow.ContractNamesRegisterID(address):   contractNames,
+		flow.PublicKeyRegisterID(address, 0):    encodedKey,
+		flow.ContractRegisterID(address, "Foo"): code,
+		{Owner: "", Key: "uuid"}:                []byte{1},
+	}
+
+	suite.Run("proof verifies", func() {
+		backend := suite.newRegisterProofsBackend(registerStateProver(suite.T(), header.ID(), registers))
+
+		accountProof, err := backend.GetAccountWithProof(context.Background(), address, header.ID())
+		suite.Require().NoError(err)
+
+		account, err := accountProof.Verify(accountProof.StateCommitment)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(address, account.Address)
+		suite.Require().Len(account.Keys, 1)
+		suite.Assert().True(accountKey.PublicKey.Equals(account.Keys[0].PublicKey))
+		suite.Assert().Equal(map[string][]byte{"Foo": code}, account.Contracts)
+	})
+
+	suite.Run("account not found", func() {
+		backend := suite.newRegisterProofsBackend(registerStateProver(suite.T(), header.ID(), registers))
+
+		_, err := backend.GetAccountWithProof(context.Background(), unittest.RandomAddressFixture(), header.ID())
+		suite.Assert().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("too many registers", func() {
+		manyKeysStatus := environment.NewAccountStatus()
+		manyKeysStatus.SetPublicKeyCount(DefaultMaxRegistersPerProof)
+		manyKeys := map[flow.RegisterID]flow.RegisterValue{
+			flow.AccountStatusRegisterID(address): manyKeysStatus.ToBytes(),
+			flow.ContractNamesRegisterID(address): contractNames,
+		}
+		backend := suite.newRegisterProofsBackend(registerStateProver(suite.T(), header.ID(), manyKeys))
+
+		_, err := backend.GetAccountWithProof(context.Background(), address, header.ID())
+		suite.Assert().Equal(codes.ResourceExhausted, status.Code(err))
+	})
+}
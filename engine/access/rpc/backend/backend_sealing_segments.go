@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/segmentstream"
+)
+
+var _ access.SealingSegmentAPI = (*Backend)(nil)
+
+type backendSealingSegments struct {
+	provider *segmentstream.Provider // nil if the node does not stream sealing segments
+}
+
+// SetSealingSegmentProvider enables the sealing segment endpoints, serving sealing segments from the
+// given provider. It must be called before the backend serves any requests.
+func (b *Backend) SetSealingSegmentProvider(provider *segmentstream.Provider) {
+	b.backendSealingSegments.provider = provider
+}
+
+// GetSealingSegmentManifest returns the manifest of the sealing segment of the given finalized block,
+// or of a recent finalized block if the block ID is flow.ZeroID.
+func (b *backendSealingSegments) GetSealingSegmentManifest(_ context.Context, blockID flow.Identifier) (*segmentstream.Manifest, error) {
+	if b.provider == nil {
+		return nil, status.Errorf(codes.Unimplemented, "sealing segment streaming is not enabled on this node")
+	}
+
+	var manifest *segmentstream.Manifest
+	var err error
+	if blockID == flow.ZeroID {
+		manifest, err = b.provider.LatestManifest()
+	} else {
+		manifest, err = b.provider.Manifest(blockID)
+	}
+	if err != nil {
+		return nil, convertSealingSegmentError(fmt.Errorf("failed to get sealing segment manifest of block %v: %w", blockID, err))
+	}
+	return manifest, nil
+}
+
+// GetSealingSegmentChunk returns the chunk with the given index of the sealing segment of the given
+// finalized block. Chunks past the sealing segment hold the finalized blocks following the block.
+func (b *backendSealingSegments) GetSealingSegmentChunk(_ context.Context, headID flow.Identifier, index uint) (*segmentstream.Chunk, error) {
+	if b.provider == nil {
+		return nil, status.Errorf(codes.Unimplemented, "sealing segment streaming is not enabled on this node")
+	}
+
+	chunk, err := b.provider.Chunk(headID, index)
+	if err != nil {
+		return nil, convertSealingSegmentError(fmt.Errorf("failed to get chunk %d of sealing segment of block %v: %w", index, headID, err))
+	}
+	return chunk, nil
+}
+
+// convertSealingSegmentError converts an error of the sealing segment provider into a gRPC status error.
+func convertSealingSegmentError(err error) error {
+	if errors.Is(err, segmentstream.ErrBuildRateLimited) {
+		return status.Errorf(codes.ResourceExhausted, "%v", err)
+	}
+	return rpc.ConvertStorageError(err)
+}
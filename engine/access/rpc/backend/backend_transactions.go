@@ -10,7 +10,9 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/entities"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-go/access"
@@ -40,6 +42,14 @@ type backendTransactions struct {
 	previousAccessNodes []accessproto.AccessAPIClient
 	log                 zerolog.Logger
 	nodeCommunicator    *NodeCommunicator
+	forwarded           *rpc.ForwardedTransactions // suppresses duplicate forwarding of submitted transactions, nil if disabled
+}
+
+// SetForwardedTransactions enables suppressing duplicate forwarding of submitted transactions,
+// using the given cache of recently forwarded transactions.
+// It must be called before the backend serves any requests.
+func (b *Backend) SetForwardedTransactions(forwarded *rpc.ForwardedTransactions) {
+	b.backendTransactions.forwarded = forwarded
 }
 
 // SendTransaction forwards the transaction to the collection node
@@ -55,11 +65,16 @@ func (b *backendTransactions) SendTransaction(
 	}
 
 	// send the transaction to the collection node if valid
-	err = b.trySendTransaction(ctx, tx)
+	receipt, duplicate, err := b.forwardTransaction(ctx, tx)
 	if err != nil {
 		b.transactionMetrics.TransactionSubmissionFailed()
 		return rpc.ConvertError(err, "failed to send transaction to a collection node", codes.Internal)
 	}
+	b.setTransactionReceiptHeader(ctx, receipt)
+	if duplicate {
+		// the transaction was stored and registered for retries when it was first forwarded
+		return nil
+	}
 
 	b.transactionMetrics.TransactionReceived(tx.ID(), now)
 
@@ -76,18 +91,73 @@ func (b *backendTransactions) SendTransaction(
 	return nil
 }
 
-// trySendTransaction tries to transaction to a collection node
-func (b *backendTransactions) trySendTransaction(ctx context.Context, tx *flow.TransactionBody) error {
+// forwardTransaction sends the transaction to a collection node, unless it was recently forwarded
+// already. Recently forwarded transactions are answered with the receipt obtained when forwarding
+// the transaction originally, and duplicate is true. If the same transaction is being forwarded
+// concurrently, forwardTransaction waits for its outcome.
+// It returns the receipt of the collection node, which is nil if no receipt is available.
+func (b *backendTransactions) forwardTransaction(ctx context.Context, tx *flow.TransactionBody) (
+	receipt *flow.TransactionAcceptanceReceipt,
+	duplicate bool,
+	err error,
+) {
+	if b.forwarded == nil {
+		receipt, err = b.trySendTransaction(ctx, tx)
+		return receipt, false, err
+	}
+
+	txID := tx.ID()
+	receipt, reserved, err := b.forwarded.Reserve(ctx, txID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !reserved {
+		b.log.Debug().Hex("tx_id", txID[:]).Msg("skipping forwarding of recently forwarded transaction")
+		return receipt, true, nil
+	}
+
+	receipt, err = b.trySendTransaction(ctx, tx)
+	if err != nil {
+		// release the transaction, so that it is forwarded when it is submitted again
+		b.forwarded.Release(txID)
+		return nil, false, err
+	}
+	b.forwarded.Complete(txID, receipt)
+	return receipt, false, nil
+}
+
+// setTransactionReceiptHeader returns the receipt of the collection node to the client in
+// the response header, if the request was received via gRPC and a receipt is available.
+func (b *backendTransactions) setTransactionReceiptHeader(ctx context.Context, receipt *flow.TransactionAcceptanceReceipt) {
+	if receipt == nil {
+		return
+	}
+	md, err := rpc.TransactionReceiptMetadata(receipt)
+	if err != nil {
+		b.log.Warn().Err(err).Msg("could not encode transaction receipt")
+		return
+	}
+	// fails if the request was not received via gRPC (e.g. via the REST API), in which
+	// case there is no way to return the receipt
+	_ = grpc.SetHeader(ctx, md)
+}
+
+// trySendTransaction tries to transaction to a collection node. Transactions rejected by
+// a collection node are submitted to other members of the responsible cluster.
+// It returns the verified acceptance receipt of the collection node which accepted the
+// transaction, or nil if the collection node did not return a receipt.
+func (b *backendTransactions) trySendTransaction(ctx context.Context, tx *flow.TransactionBody) (*flow.TransactionAcceptanceReceipt, error) {
 
 	// if a collection node rpc client was provided at startup, just use that
 	if b.staticCollectionRPC != nil {
-		return b.grpcTxSend(ctx, b.staticCollectionRPC, tx)
+		// the identity of a static collection node is unknown, so we cannot verify its receipts
+		return nil, b.grpcTxSend(ctx, b.staticCollectionRPC, tx)
 	}
 
 	// otherwise choose all collection nodes to try
 	collNodes, err := b.chooseCollectionNodes(tx)
 	if err != nil {
-		return fmt.Errorf("failed to determine collection node for tx %x: %w", tx, err)
+		return nil, fmt.Errorf("failed to determine collection node for tx %x: %w", tx, err)
 	}
 
 	var sendError error
@@ -99,10 +169,11 @@ func (b *backendTransactions) trySendTransaction(ctx context.Context, tx *flow.T
 	defer logAnyError()
 
 	// try sending the transaction to one of the chosen collection nodes
+	var receipt *flow.TransactionAcceptanceReceipt
 	sendError = b.nodeCommunicator.CallAvailableNode(
 		collNodes,
 		func(node *flow.Identity) error {
-			err = b.sendTransactionToCollector(ctx, tx, node.Address)
+			receipt, err = b.sendTransactionToCollector(ctx, tx, node)
 			if err != nil {
 				return err
 			}
@@ -111,7 +182,7 @@ func (b *backendTransactions) trySendTransaction(ctx context.Context, tx *flow.T
 		nil,
 	)
 
-	return sendError
+	return receipt, sendError
 }
 
 // chooseCollectionNodes finds a random subset of size sampleSize of collection node addresses from the
@@ -133,25 +204,44 @@ func (b *backendTransactions) chooseCollectionNodes(tx *flow.TransactionBody) (f
 	return targetNodes, nil
 }
 
-// sendTransactionToCollection sends the transaction to the given collection node via grpc
+// sendTransactionToCollection sends the transaction to the given collection node via grpc.
+// It returns the verified acceptance receipt of the collection node, or nil if the collection
+// node did not return a valid receipt.
 func (b *backendTransactions) sendTransactionToCollector(ctx context.Context,
 	tx *flow.TransactionBody,
-	collectionNodeAddr string) error {
+	collectionNode *flow.Identity) (*flow.TransactionAcceptanceReceipt, error) {
 
+	collectionNodeAddr := collectionNode.Address
 	collectionRPC, closer, err := b.connFactory.GetAccessAPIClient(collectionNodeAddr)
 	if err != nil {
-		return fmt.Errorf("failed to connect to collection node at %s: %w", collectionNodeAddr, err)
+		return nil, fmt.Errorf("failed to connect to collection node at %s: %w", collectionNodeAddr, err)
 	}
 	defer closer.Close()
 
-	err = b.grpcTxSend(ctx, collectionRPC, tx)
+	var header, trailer metadata.MD
+	err = b.grpcTxSend(ctx, collectionRPC, tx, grpc.Header(&header), grpc.Trailer(&trailer))
 	if err != nil {
-		return fmt.Errorf("failed to send transaction to collection node at %s: %w", collectionNodeAddr, err)
+		return nil, fmt.Errorf("failed to send transaction to collection node at %s: %w", collectionNodeAddr, err)
 	}
-	return nil
+
+	log := b.log.With().Hex("collection_node_id", collectionNode.NodeID[:]).Logger()
+	receipt, ok, err := rpc.TransactionReceiptFromMetadata(metadata.Join(header, trailer))
+	if err != nil {
+		log.Warn().Err(err).Msg("collection node returned malformed transaction receipt")
+		return nil, nil
+	}
+	if !ok {
+		return nil, nil
+	}
+	err = rpc.VerifyTransactionReceipt(receipt, tx.ID(), collectionNode)
+	if err != nil {
+		log.Warn().Err(err).Msg("collection node returned invalid transaction receipt")
+		return nil, nil
+	}
+	return receipt, nil
 }
 
-func (b *backendTransactions) grpcTxSend(ctx context.Context, client accessproto.AccessAPIClient, tx *flow.TransactionBody) error {
+func (b *backendTransactions) grpcTxSend(ctx context.Context, client accessproto.AccessAPIClient, tx *flow.TransactionBody, opts ...grpc.CallOption) error {
 	colReq := &accessproto.SendTransactionRequest{
 		Transaction: convert.TransactionToMessage(*tx),
 	}
@@ -160,7 +250,7 @@ func (b *backendTransactions) grpcTxSend(ctx context.Context, client accessproto
 	ctx, cancel := context.WithDeadline(ctx, clientDeadline)
 	defer cancel()
 
-	_, err := client.SendTransaction(ctx, colReq)
+	_, err := client.SendTransaction(ctx, colReq, opts...)
 	return err
 }
 
@@ -171,7 +261,8 @@ func (b *backendTransactions) SendRawTransaction(
 ) error {
 
 	// send the transaction to the collection node
-	return b.trySendTransaction(ctx, tx)
+	_, err := b.trySendTransaction(ctx, tx)
+	return err
 }
 
 func (b *backendTransactions) GetTransaction(ctx context.Context, txID flow.Identifier) (*flow.TransactionBody, error) {
@@ -0,0 +1,106 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import (
+	flow "github.com/onflow/flow-go/model/flow"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EpochArchive is an autogenerated mock type for the EpochArchive type
+type EpochArchive struct {
+	mock.Mock
+}
+
+// EpochAtHeight provides a mock function with given fields: height
+func (_m *EpochArchive) EpochAtHeight(height uint64) (*flow.ArchivedEpoch, error) {
+	ret := _m.Called(height)
+
+	var r0 *flow.ArchivedEpoch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint64) (*flow.ArchivedEpoch, error)); ok {
+		return rf(height)
+	}
+	if rf, ok := ret.Get(0).(func(uint64) *flow.ArchivedEpoch); ok {
+		r0 = rf(height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.ArchivedEpoch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EpochByCounter provides a mock function with given fields: counter
+func (_m *EpochArchive) EpochByCounter(counter uint64) (*flow.ArchivedEpoch, error) {
+	ret := _m.Called(counter)
+
+	var r0 *flow.ArchivedEpoch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint64) (*flow.ArchivedEpoch, error)); ok {
+		return rf(counter)
+	}
+	if rf, ok := ret.Get(0).(func(uint64) *flow.ArchivedEpoch); ok {
+		r0 = rf(counter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.ArchivedEpoch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(counter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IdentitiesAtHeight provides a mock function with given fields: height
+func (_m *EpochArchive) IdentitiesAtHeight(height uint64) (flow.IdentityList, error) {
+	ret := _m.Called(height)
+
+	var r0 flow.IdentityList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint64) (flow.IdentityList, error)); ok {
+		return rf(height)
+	}
+	if rf, ok := ret.Get(0).(func(uint64) flow.IdentityList); ok {
+		r0 = rf(height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(flow.IdentityList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEpochArchive interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEpochArchive creates a new instance of EpochArchive. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEpochArchive(t mockConstructorTestingTNewEpochArchive) *EpochArchive {
+	mock := &EpochArchive{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import (
+	ledger "github.com/onflow/flow-go/ledger"
+	flow "github.com/onflow/flow-go/model/flow"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RegisterProver is an autogenerated mock type for the RegisterProver type
+type RegisterProver struct {
+	mock.Mock
+}
+
+// RegistersWithProof provides a mock function with given fields: blockID, registerIDs
+func (_m *RegisterProver) RegistersWithProof(blockID flow.Identifier, registerIDs []flow.RegisterID) (flow.StateCommitment, *ledger.TrieBatchProof, error) {
+	ret := _m.Called(blockID, registerIDs)
+
+	var r0 flow.StateCommitment
+	var r1 *ledger.TrieBatchProof
+	var r2 error
+	if rf, ok := ret.Get(0).(func(flow.Identifier, []flow.RegisterID) (flow.StateCommitment, *ledger.TrieBatchProof, error)); ok {
+		return rf(blockID, registerIDs)
+	}
+	if rf, ok := ret.Get(0).(func(flow.Identifier, []flow.RegisterID) flow.StateCommitment); ok {
+		r0 = rf(blockID, registerIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(flow.StateCommitment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(flow.Identifier, []flow.RegisterID) *ledger.TrieBatchProof); ok {
+		r1 = rf(blockID, registerIDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ledger.TrieBatchProof)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(flow.Identifier, []flow.RegisterID) error); ok {
+		r2 = rf(blockID, registerIDs)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+type mockConstructorTestingTNewRegisterProver interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRegisterProver creates a new instance of RegisterProver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRegisterProver(t mockConstructorTestingTNewRegisterProver) *RegisterProver {
+	mock := &RegisterProver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
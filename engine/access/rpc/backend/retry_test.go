@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc/codes"
@@ -10,6 +11,7 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/access"
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -189,3 +191,64 @@ func (suite *Suite) TestSuccessfulTransactionsDontRetry() {
 
 	suite.assertAllExpectations()
 }
+
+// TestForwardTransactionDeduplication tests that a transaction submitted repeatedly is forwarded
+// only once, that a transaction which could not be forwarded is forwarded when submitted again,
+// and that retries of forwarded transactions are not suppressed.
+func (suite *Suite) TestForwardTransactionDeduplication() {
+	ctx := context.Background()
+	transactionBody := unittest.TransactionBodyFixture()
+
+	backend := New(suite.state,
+		suite.colClient,
+		nil,
+		suite.blocks,
+		suite.headers,
+		suite.collections,
+		suite.transactions,
+		suite.receipts,
+		suite.results,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+		nil,
+		false,
+		false,
+	)
+	forwarded, err := rpc.NewForwardedTransactions(10, time.Minute)
+	suite.Require().NoError(err)
+	backend.SetForwardedTransactions(forwarded)
+
+	suite.colClient.On("SendTransaction", mock.Anything, mock.Anything).
+		Return(nil, status.Error(codes.Unavailable, "collection node unavailable")).
+		Once()
+	suite.colClient.On("SendTransaction", mock.Anything, mock.Anything).
+		Return(&access.SendTransactionResponse{}, nil)
+
+	// a transaction which could not be forwarded is not remembered
+	_, _, err = backend.forwardTransaction(ctx, &transactionBody)
+	suite.Require().Error(err)
+	suite.colClient.AssertNumberOfCalls(suite.T(), "SendTransaction", 1)
+
+	_, duplicate, err := backend.forwardTransaction(ctx, &transactionBody)
+	suite.Require().NoError(err)
+	suite.Assert().False(duplicate)
+	suite.colClient.AssertNumberOfCalls(suite.T(), "SendTransaction", 2)
+
+	// duplicate submissions are not forwarded
+	_, duplicate, err = backend.forwardTransaction(ctx, &transactionBody)
+	suite.Require().NoError(err)
+	suite.Assert().True(duplicate)
+	suite.colClient.AssertNumberOfCalls(suite.T(), "SendTransaction", 2)
+
+	// retries are always forwarded
+	err = backend.SendRawTransaction(ctx, &transactionBody)
+	suite.Require().NoError(err)
+	suite.colClient.AssertNumberOfCalls(suite.T(), "SendTransaction", 3)
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -34,6 +35,8 @@ type Config struct {
 	TransportCredentials   credentials.TransportCredentials // the secure GRPC credentials
 	HTTPListenAddr         string                           // the HTTP web proxy address as ip:port
 	RESTListenAddr         string                           // the REST server address as ip:port (if empty the REST server will not be started)
+	SealingSegmentAddr     string                           // the sealing segment server address as ip:port (if empty the sealing segment server will not be started)
+	SealingSegmentTLS      *tls.Config                      // the TLS config of the sealing segment server
 	CollectionAddr         string                           // the address of the upstream collection node
 	HistoricalAccessAddrs  string                           // the list of all access nodes from previous spork
 
@@ -58,6 +61,7 @@ type Engine struct {
 	secureGrpcServer   *grpcserver.GrpcServer // the secure gRPC server
 	httpServer         *http.Server
 	restServer         *http.Server
+	sealingSegServer   *http.Server
 	config             Config
 	chain              flow.Chain
 
@@ -118,6 +122,7 @@ func NewBuilder(log zerolog.Logger,
 		}).
 		AddWorker(eng.serveGRPCWebProxyWorker).
 		AddWorker(eng.serveREST).
+		AddWorker(eng.serveSealingSegments).
 		AddWorker(finalizedCacheWorker).
 		AddWorker(backendNotifierWorker).
 		AddWorker(eng.shutdownWorker).
@@ -154,6 +159,12 @@ func (e *Engine) shutdown() {
 			e.log.Error().Err(err).Msg("error stopping http REST server")
 		}
 	}
+	if e.sealingSegServer != nil {
+		err := e.sealingSegServer.Shutdown(ctx)
+		if err != nil {
+			e.log.Error().Err(err).Msg("error stopping sealing segment server")
+		}
+	}
 }
 
 // OnFinalizedBlock responds to block finalization events.
@@ -243,3 +254,42 @@ func (e *Engine) serveREST(ctx irrecoverable.SignalerContext, ready component.Re
 		ctx.Throw(err)
 	}
 }
+
+// serveSealingSegments is a worker routine which starts the HTTPS server streaming sealing segments to
+// bootstrapping nodes. The server authenticates itself with a certificate of the node's networking key,
+// so that bootstrapping nodes can pin the key.
+func (e *Engine) serveSealingSegments(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	if e.config.SealingSegmentAddr == "" {
+		e.log.Debug().Msg("no sealing segment address specified - not starting the server")
+		ready()
+		return
+	}
+
+	log := e.log.With().Str("sealing_segment_address", e.config.SealingSegmentAddr).Logger()
+	log.Info().Msg("starting sealing segment server on address")
+
+	s, err := rest.NewSealingSegmentServer(e.restHandler, e.config.SealingSegmentAddr, e.log, e.chain, e.restCollector, e.config.SealingSegmentTLS)
+	if err != nil {
+		log.Err(err).Msg("failed to initialize the sealing segment server")
+		ctx.Throw(err)
+		return
+	}
+	e.sealingSegServer = s
+
+	l, err := net.Listen("tcp", e.config.SealingSegmentAddr)
+	if err != nil {
+		log.Err(err).Msg("failed to start the sealing segment server")
+		ctx.Throw(err)
+		return
+	}
+	ready()
+
+	err = e.sealingSegServer.Serve(tls.NewListener(l, e.config.SealingSegmentTLS)) // blocking call
+	if err != nil {
+		if errors.Is(err, http.ErrServerClosed) {
+			return
+		}
+		log.Err(err).Msg("fatal error in sealing segment server")
+		ctx.Throw(err)
+	}
+}
@@ -129,6 +129,35 @@ func (e *Engine) Start(ctx irrecoverable.SignalerContext) {
 	if err != nil {
 		ctx.Throw(fmt.Errorf("could not check or start previous epoch components: %w", err))
 	}
+
+	// (5) drop the transaction pools of epochs whose cluster consensus was not re-started,
+	// in particular pools restored from persisted transactions after a restart
+	err = e.pruneTransactionPoolsOnStartup(finalSnapshot)
+	if err != nil {
+		ctx.Throw(fmt.Errorf("could not prune transaction pools: %w", err))
+	}
+}
+
+// pruneTransactionPoolsOnStartup removes the transaction pools of all epochs before the oldest
+// epoch with running cluster consensus. If no cluster consensus is running, the pools of all
+// epochs before the current epoch are removed.
+// No errors are expected during normal operation.
+func (e *Engine) pruneTransactionPoolsOnStartup(finalSnapshot protocol.Snapshot) error {
+	oldest, err := finalSnapshot.Epochs().Current().Counter()
+	if err != nil {
+		return fmt.Errorf("could not get current epoch counter: %w", err)
+	}
+
+	e.mu.RLock()
+	for counter := range e.epochs {
+		if counter < oldest {
+			oldest = counter
+		}
+	}
+	e.mu.RUnlock()
+
+	e.pools.PruneBefore(oldest)
+	return nil
 }
 
 // checkShouldStartCurrentEpochComponentsOnStartup checks whether we should instantiate
@@ -308,11 +308,16 @@ func (suite *Suite) TestStartAfterEpochBoundary_WithinTxExpiry() {
 	prevEpoch.On("FinalHeight").Return(prevEpochFinalHeight, nil)
 	suite.header.Height = prevEpochFinalHeight + 1
 	suite.heights.On("OnHeight", prevEpochFinalHeight+flow.DefaultTransactionExpiry+1, mock.Anything)
+	// the transaction pool of the previous epoch has been restored after a restart
+	tx := unittest.TransactionBodyFixture()
+	suite.pools.ForEpoch(suite.counter - 1).Add(&tx)
 
 	suite.StartEngine()
 	// previous epoch components should have been started
 	suite.AssertEpochStarted(suite.counter - 1)
 	suite.AssertEpochStarted(suite.counter)
+	// the transaction pool of the previous epoch should have been retained
+	suite.Assert().True(suite.pools.ForEpoch(suite.counter - 1).Has(tx.ID()))
 }
 
 // TestStartAfterEpochBoundary_BeyondTxExpiry tests starting the engine shortly after an epoch transition.
@@ -330,11 +335,16 @@ func (suite *Suite) TestStartAfterEpochBoundary_BeyondTxExpiry() {
 	prevEpochFinalHeight := uint64(100)
 	prevEpoch.On("FinalHeight").Return(prevEpochFinalHeight, nil)
 	suite.header.Height = prevEpochFinalHeight + flow.DefaultTransactionExpiry + 100
+	// the transaction pool of the previous epoch has been restored after a restart
+	tx := unittest.TransactionBodyFixture()
+	suite.pools.ForEpoch(suite.counter - 1).Add(&tx)
 
 	suite.StartEngine()
 	// previous epoch components should not have been started
 	suite.AssertEpochStarted(suite.counter)
 	suite.Assert().Len(suite.components, 1)
+	// the transaction pool of the previous epoch should have been pruned
+	suite.Assert().EqualValues(0, suite.pools.CombinedSize())
 }
 
 // TestStartAfterEpochBoundary_NotApprovedForPreviousEpoch tests starting the engine
@@ -1,6 +1,9 @@
 package ingest
 
 import (
+	"time"
+
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -22,16 +25,23 @@ type Config struct {
 	MaxCollectionByteSize uint64
 	// maximum number of un-processed transaction messages to hold in the queue.
 	MaxMessageQueueSize uint
+	// maximum number of propagated transactions remembered to suppress duplicate propagation,
+	// 0 disables the suppression
+	ForwardedTransactionsCacheSize uint
+	// how long a propagated transaction is remembered to suppress duplicate propagation
+	ForwardedTransactionsTTL time.Duration
 }
 
 func DefaultConfig() Config {
 	return Config{
-		ExpiryBuffer:           flow.DefaultTransactionExpiryBuffer,
-		MaxGasLimit:            flow.DefaultMaxTransactionGasLimit,
-		MaxTransactionByteSize: flow.DefaultMaxTransactionByteSize,
-		MaxCollectionByteSize:  flow.DefaultMaxCollectionByteSize,
-		CheckScriptsParse:      true,
-		PropagationRedundancy:  2,
-		MaxMessageQueueSize:    10_000,
+		ExpiryBuffer:                   flow.DefaultTransactionExpiryBuffer,
+		MaxGasLimit:                    flow.DefaultMaxTransactionGasLimit,
+		MaxTransactionByteSize:         flow.DefaultMaxTransactionByteSize,
+		MaxCollectionByteSize:          flow.DefaultMaxCollectionByteSize,
+		CheckScriptsParse:              true,
+		PropagationRedundancy:          2,
+		MaxMessageQueueSize:            10_000,
+		ForwardedTransactionsCacheSize: rpc.DefaultForwardedTransactionsCacheSize,
+		ForwardedTransactionsTTL:       rpc.DefaultForwardedTransactionsTTL,
 	}
 }
@@ -12,12 +12,14 @@ import (
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/fifoqueue"
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/module/mempool/epochs"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/signature"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/state/protocol"
@@ -39,6 +41,7 @@ type Engine struct {
 	messageHandler       *engine.MessageHandler
 	pools                *epochs.TransactionPools
 	transactionValidator *access.TransactionValidator
+	forwarded            *rpc.ForwardedTransactions // suppresses duplicate propagation of submitted transactions, nil if disabled
 
 	config Config
 }
@@ -59,18 +62,7 @@ func New(
 
 	logger := log.With().Str("engine", "ingest").Logger()
 
-	transactionValidator := access.NewTransactionValidator(
-		access.NewProtocolStateBlocks(state),
-		chain,
-		access.TransactionValidationOptions{
-			Expiry:                 flow.DefaultTransactionExpiry,
-			ExpiryBuffer:           config.ExpiryBuffer,
-			MaxGasLimit:            config.MaxGasLimit,
-			CheckScriptsParse:      config.CheckScriptsParse,
-			MaxTransactionByteSize: config.MaxTransactionByteSize,
-			MaxCollectionByteSize:  config.MaxCollectionByteSize,
-		},
-	)
+	transactionValidator := NewTransactionValidator(state, chain, config)
 
 	// FIFO queue for transactions
 	queue, err := fifoqueue.NewFifoQueue(
@@ -84,6 +76,15 @@ func New(
 	}
 	pendingTransactions := &engine.FifoMessageStore{FifoQueue: queue}
 
+	// a cache size of 0 disables the suppression of duplicate propagation
+	var forwarded *rpc.ForwardedTransactions
+	if config.ForwardedTransactionsCacheSize > 0 {
+		forwarded, err = rpc.NewForwardedTransactions(int(config.ForwardedTransactionsCacheSize), config.ForwardedTransactionsTTL)
+		if err != nil {
+			return nil, fmt.Errorf("could not create forwarded transactions cache: %w", err)
+		}
+	}
+
 	// define how inbound messages are mapped to message queues
 	handler := engine.NewMessageHandler(
 		logger,
@@ -111,6 +112,7 @@ func New(
 		pools:                pools,
 		config:               config,
 		transactionValidator: transactionValidator,
+		forwarded:            forwarded,
 	}
 
 	e.ComponentManager = component.NewComponentManagerBuilder().
@@ -126,6 +128,23 @@ func New(
 	return e, nil
 }
 
+// NewTransactionValidator returns the validator applied by the ingest engine to
+// inbound transactions, configured according to the given config.
+func NewTransactionValidator(state protocol.State, chain flow.Chain, config Config) *access.TransactionValidator {
+	return access.NewTransactionValidator(
+		access.NewProtocolStateBlocks(state),
+		chain,
+		access.TransactionValidationOptions{
+			Expiry:                 flow.DefaultTransactionExpiry,
+			ExpiryBuffer:           config.ExpiryBuffer,
+			MaxGasLimit:            config.MaxGasLimit,
+			CheckScriptsParse:      config.CheckScriptsParse,
+			MaxTransactionByteSize: config.MaxTransactionByteSize,
+			MaxCollectionByteSize:  config.MaxCollectionByteSize,
+		},
+	)
+}
+
 // Process processes a transaction message from the network and enqueues the
 // message. Validation and ingestion is performed in the processQueuedTransactions
 // worker.
@@ -162,6 +181,40 @@ func (e *Engine) ProcessTransaction(tx *flow.TransactionBody) error {
 	return e.onTransaction(e.me.NodeID(), tx)
 }
 
+// SubmitTransaction processes a transaction submitted via the GRPC API by an Access node,
+// like ProcessTransaction, and returns an acceptance receipt signed by this node. The receipt
+// names the cluster responsible for the transaction and the outcome of the submission.
+//
+// Returns:
+//   - component.ErrComponentShutdown if the engine has shut down.
+//   - engine.UnverifiableInputError if the reference block is unknown or if the
+//     node is not a member of any cluster in the reference epoch.
+//   - engine.InvalidInputError if the transaction is invalid.
+//   - other error for any other unexpected error condition.
+func (e *Engine) SubmitTransaction(tx *flow.TransactionBody) (*flow.TransactionAcceptanceReceipt, error) {
+	// do not process transactions after the engine has shut down
+	select {
+	case <-e.ComponentManager.ShutdownSignal():
+		return nil, component.ErrComponentShutdown
+	default:
+	}
+
+	body, err := e.handleTransaction(e.me.NodeID(), tx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := body.ID()
+	sig, err := e.me.Sign(id[:], signature.NewBLSHasher(signature.TransactionReceiptTag))
+	if err != nil {
+		return nil, fmt.Errorf("could not sign transaction receipt: %w", err)
+	}
+	return &flow.TransactionAcceptanceReceipt{
+		TransactionReceiptBody: *body,
+		CollectorSignature:     sig,
+	}, nil
+}
+
 // processQueuedTransactions is the main message processing loop for transaction messages.
 func (e *Engine) processQueuedTransactions(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
 	ready()
@@ -224,6 +277,14 @@ func (e *Engine) processAvailableMessages(ctx context.Context) error {
 //   - engine.InvalidInputError if the transaction is invalid.
 //   - other error for any other unexpected error condition.
 func (e *Engine) onTransaction(originID flow.Identifier, tx *flow.TransactionBody) error {
+	_, err := e.handleTransaction(originID, tx)
+	return err
+}
+
+// handleTransaction handles receipt of a new transaction, like onTransaction, and
+// returns the (unsigned) receipt body describing the outcome.
+// Expected errors are the same as for onTransaction.
+func (e *Engine) handleTransaction(originID flow.Identifier, tx *flow.TransactionBody) (*flow.TransactionReceiptBody, error) {
 
 	defer e.engMetrics.MessageHandled(metrics.EngineCollectionIngest, metrics.MessageTransaction)
 
@@ -241,7 +302,7 @@ func (e *Engine) onTransaction(originID flow.Identifier, tx *flow.TransactionBod
 	// fail fast if this is an unknown reference
 	_, err := refSnapshot.Head()
 	if err != nil {
-		return engine.NewUnverifiableInputError("could not get reference block for transaction (%x): %w", txID, err)
+		return nil, engine.NewUnverifiableInputError("could not get reference block for transaction (%x): %w", txID, err)
 	}
 
 	// using the transaction's reference block, determine which cluster we're in.
@@ -250,15 +311,15 @@ func (e *Engine) onTransaction(originID flow.Identifier, tx *flow.TransactionBod
 
 	localCluster, err := e.getLocalCluster(refEpoch)
 	if err != nil {
-		return fmt.Errorf("could not get local cluster: %w", err)
+		return nil, fmt.Errorf("could not get local cluster: %w", err)
 	}
 	clusters, err := refEpoch.Clustering()
 	if err != nil {
-		return fmt.Errorf("could not get clusters for reference epoch: %w", err)
+		return nil, fmt.Errorf("could not get clusters for reference epoch: %w", err)
 	}
 	txCluster, ok := clusters.ByTxID(txID)
 	if !ok {
-		return fmt.Errorf("could not get cluster responsible for tx: %x", txID)
+		return nil, fmt.Errorf("could not get cluster responsible for tx: %x", txID)
 	}
 
 	localClusterFingerPrint := localCluster.ID()
@@ -270,20 +331,41 @@ func (e *Engine) onTransaction(originID flow.Identifier, tx *flow.TransactionBod
 
 	// validate and ingest the transaction, so it is eligible for inclusion in
 	// a future collection proposed by this node
-	err = e.ingestTransaction(log, refEpoch, tx, txID, localClusterFingerPrint, txClusterFingerPrint)
+	admission, err := e.ingestTransaction(log, refEpoch, tx, txID, localClusterFingerPrint, txClusterFingerPrint)
 	if err != nil {
-		return fmt.Errorf("could not ingest transaction: %w", err)
+		return nil, fmt.Errorf("could not ingest transaction: %w", err)
 	}
 
 	// if the message was submitted internally (ie. via the Access API)
 	// propagate it to members of the responsible cluster (either our cluster
-	// or a different cluster)
+	// or a different cluster), unless we have recently done so already
 	if originID == e.me.NodeID() {
-		e.propagateTransaction(log, tx, txCluster)
+		reserved := true
+		if e.forwarded != nil {
+			// propagation does not block for long, hence we wait for concurrent propagation of
+			// the same transaction without a deadline
+			_, reserved, err = e.forwarded.Reserve(context.Background(), txID)
+			if err != nil {
+				return nil, fmt.Errorf("could not check for recent propagation of transaction: %w", err)
+			}
+		}
+		if reserved {
+			e.propagateTransaction(log, tx, txCluster)
+			if e.forwarded != nil {
+				e.forwarded.Complete(txID, nil)
+			}
+		} else {
+			log.Debug().Msg("skipping propagation of recently propagated transaction")
+		}
 	}
 
-	log.Info().Msg("transaction processed")
-	return nil
+	log.Info().Str("admission", admission.String()).Msg("transaction processed")
+	return &flow.TransactionReceiptBody{
+		TransactionID: txID,
+		ClusterID:     txClusterFingerPrint,
+		Admission:     admission,
+		CollectorID:   e.me.NodeID(),
+	}, nil
 }
 
 // getLocalCluster returns the cluster this node is a part of for the given reference epoch.
@@ -326,7 +408,8 @@ func (e *Engine) getLocalCluster(refEpoch protocol.Epoch) (flow.IdentityList, er
 }
 
 // ingestTransaction validates and ingests the transaction, if it is routed to
-// our local cluster, is valid, and has not been seen previously.
+// our local cluster, is valid, and has not been seen previously. It returns the
+// outcome of the mempool admission.
 //
 // Returns:
 // * engine.InvalidInputError if the transaction is invalid.
@@ -338,10 +421,10 @@ func (e *Engine) ingestTransaction(
 	txID flow.Identifier,
 	localClusterFingerprint flow.Identifier,
 	txClusterFingerprint flow.Identifier,
-) error {
+) (flow.TransactionAdmission, error) {
 	epochCounter, err := refEpoch.Counter()
 	if err != nil {
-		return fmt.Errorf("could not get counter for reference epoch: %w", err)
+		return 0, fmt.Errorf("could not get counter for reference epoch: %w", err)
 	}
 
 	// use the transaction pool for the epoch the reference block is part of
@@ -350,22 +433,30 @@ func (e *Engine) ingestTransaction(
 	// short-circuit if we have already stored the transaction
 	if pool.Has(txID) {
 		log.Debug().Msg("received dupe transaction")
-		return nil
+		return flow.TransactionDuplicate, nil
 	}
 
 	// check if the transaction is valid
 	err = e.transactionValidator.Validate(tx)
 	if err != nil {
-		return engine.NewInvalidInputErrorf("invalid transaction (%x): %w", txID, err)
+		return 0, engine.NewInvalidInputErrorf("invalid transaction (%x): %w", txID, err)
 	}
 
 	// if our cluster is responsible for the transaction, add it to our local mempool
 	if localClusterFingerprint == txClusterFingerprint {
-		_ = pool.Add(tx)
+		// the mempool may decline the transaction, for example when its payer has reached
+		// the per-payer cap of a priority mempool; this is not an error of the transaction
+		added := pool.Add(tx)
+		if !added {
+			log.Debug().Msg("transaction not admitted to mempool")
+			e.colMetrics.TransactionRejected(txID)
+			return flow.TransactionRejected, nil
+		}
 		e.colMetrics.TransactionIngested(txID)
+		return flow.TransactionAdmitted, nil
 	}
 
-	return nil
+	return flow.TransactionRouted, nil
 }
 
 // propagateTransaction propagates the transaction to a number of the responsible
@@ -21,6 +21,7 @@ import (
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/mempool/epochs"
 	"github.com/onflow/flow-go/module/mempool/herocache"
+	mempoolmock "github.com/onflow/flow-go/module/mempool/mock"
 	"github.com/onflow/flow-go/module/metrics"
 	module "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/network"
@@ -313,6 +314,96 @@ func (suite *Suite) TestRoutingLocalCluster() {
 	suite.conduit.AssertExpectations(suite.T())
 }
 
+// should count transactions declined by the local mempool as rejected rather than ingested
+func (suite *Suite) TestRoutingLocalCluster_Rejected() {
+
+	local, _, ok := suite.clusters.ByNodeID(suite.me.NodeID())
+	suite.Require().True(ok)
+
+	// get a transaction that will be routed to local cluster
+	tx := unittest.TransactionBodyFixture()
+	tx.ReferenceBlockID = suite.root.ID()
+	tx = unittest.AlterTransactionForCluster(tx, suite.clusters, local, func(transaction *flow.TransactionBody) {})
+
+	// the mempool declines the transaction
+	pool := mempoolmock.NewTransactions(suite.T())
+	pool.On("Has", tx.ID()).Return(false)
+	pool.On("Add", &tx).Return(false).Once()
+	pools := epochs.NewTransactionPools(func(_ uint64) mempool.Transactions { return pool })
+
+	colMetrics := module.NewCollectionMetrics(suite.T())
+	colMetrics.On("TransactionRejected", tx.ID()).Once()
+
+	net := new(mocknetwork.Network)
+	net.On("Register", mock.Anything, mock.Anything).Return(suite.conduit, nil).Once()
+	noop := metrics.NewNoopCollector()
+	engine, err := New(zerolog.New(io.Discard), net, suite.state, noop, noop, colMetrics, suite.me, flow.Testnet.Chain(), pools, suite.conf)
+	suite.Require().NoError(err)
+
+	suite.conduit.
+		On("Multicast", &tx, suite.conf.PropagationRedundancy+1, local.NodeIDs()[0], local.NodeIDs()[1]).
+		Return(nil)
+
+	err = engine.ProcessTransaction(&tx)
+	suite.Require().NoError(err)
+	colMetrics.AssertNotCalled(suite.T(), "TransactionIngested", mock.Anything)
+}
+
+// should propagate a transaction submitted repeatedly only once
+func (suite *Suite) TestRoutingLocalCluster_Resubmitted() {
+
+	local, _, ok := suite.clusters.ByNodeID(suite.me.NodeID())
+	suite.Require().True(ok)
+
+	// get a transaction that will be routed to local cluster
+	tx := unittest.TransactionBodyFixture()
+	tx.ReferenceBlockID = suite.root.ID()
+	tx = unittest.AlterTransactionForCluster(tx, suite.clusters, local, func(transaction *flow.TransactionBody) {})
+
+	// should route to local cluster once
+	suite.conduit.
+		On("Multicast", &tx, suite.conf.PropagationRedundancy+1, local.NodeIDs()[0], local.NodeIDs()[1]).
+		Return(nil).Once()
+
+	err := suite.engine.ProcessTransaction(&tx)
+	suite.Require().NoError(err)
+	err = suite.engine.ProcessTransaction(&tx)
+	suite.Require().NoError(err)
+	suite.conduit.AssertExpectations(suite.T())
+}
+
+// should propagate a transaction submitted repeatedly every time, if the suppression of
+// duplicate propagation is disabled
+func (suite *Suite) TestRoutingLocalCluster_ResubmittedSuppressionDisabled() {
+
+	local, _, ok := suite.clusters.ByNodeID(suite.me.NodeID())
+	suite.Require().True(ok)
+
+	// get a transaction that will be routed to local cluster
+	tx := unittest.TransactionBodyFixture()
+	tx.ReferenceBlockID = suite.root.ID()
+	tx = unittest.AlterTransactionForCluster(tx, suite.clusters, local, func(transaction *flow.TransactionBody) {})
+
+	conf := suite.conf
+	conf.ForwardedTransactionsCacheSize = 0
+	net := new(mocknetwork.Network)
+	net.On("Register", mock.Anything, mock.Anything).Return(suite.conduit, nil).Once()
+	noop := metrics.NewNoopCollector()
+	engine, err := New(zerolog.New(io.Discard), net, suite.state, noop, noop, noop, suite.me, flow.Testnet.Chain(), suite.pools, conf)
+	suite.Require().NoError(err)
+
+	// should route to local cluster for each submission
+	suite.conduit.
+		On("Multicast", &tx, suite.conf.PropagationRedundancy+1, local.NodeIDs()[0], local.NodeIDs()[1]).
+		Return(nil).Twice()
+
+	err = engine.ProcessTransaction(&tx)
+	suite.Require().NoError(err)
+	err = engine.ProcessTransaction(&tx)
+	suite.Require().NoError(err)
+	suite.conduit.AssertExpectations(suite.T())
+}
+
 // should not store transactions for a different cluster and should propagate
 // to the responsible cluster
 func (suite *Suite) TestRoutingRemoteCluster() {
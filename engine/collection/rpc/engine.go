@@ -22,9 +22,10 @@ import (
 
 // Backend defines the core functionality required by the RPC API.
 type Backend interface {
-	// ProcessTransaction handles validating and ingesting a new transaction,
-	// ultimately for inclusion in a future collection.
-	ProcessTransaction(*flow.TransactionBody) error
+	// SubmitTransaction handles validating and ingesting a new transaction,
+	// ultimately for inclusion in a future collection. It returns a signed
+	// receipt describing the outcome of the submission.
+	SubmitTransaction(*flow.TransactionBody) (*flow.TransactionAcceptanceReceipt, error)
 }
 
 // Config defines the configurable options for the ingress server.
@@ -78,11 +79,13 @@ func New(
 
 	server := grpc.NewServer(grpcOpts...)
 
+	logger := log.With().Str("engine", "collection_rpc").Logger()
 	e := &Engine{
 		unit: engine.NewUnit(),
-		log:  log.With().Str("engine", "collection_rpc").Logger(),
+		log:  logger,
 		handler: &handler{
 			UnimplementedAccessAPIServer: access.UnimplementedAccessAPIServer{},
+			log:                          logger,
 			backend:                      backend,
 			chainID:                      chainID,
 		},
@@ -136,6 +139,7 @@ func (e *Engine) serve() {
 // handler implements a subset of the Observation API.
 type handler struct {
 	access.UnimplementedAccessAPIServer
+	log     zerolog.Logger
 	backend Backend
 	chainID flow.ChainID
 }
@@ -147,13 +151,17 @@ func (h *handler) Ping(_ context.Context, _ *access.PingRequest) (*access.PingRe
 
 // SendTransaction accepts new transactions and inputs them to the ingress
 // engine for validation and routing.
-func (h *handler) SendTransaction(_ context.Context, req *access.SendTransactionRequest) (*access.SendTransactionResponse, error) {
+//
+// The signed acceptance receipt of this node is returned in the rpc.TransactionReceiptHeader
+// response header. If the mempool of this node declined the transaction, a ResourceExhausted
+// error is returned, so that the caller can submit the transaction to another cluster member.
+func (h *handler) SendTransaction(ctx context.Context, req *access.SendTransactionRequest) (*access.SendTransactionResponse, error) {
 	tx, err := convert.MessageToTransaction(req.Transaction, h.chainID.Chain())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("failed to convert transaction: %v", err))
 	}
 
-	err = h.backend.ProcessTransaction(&tx)
+	receipt, err := h.backend.SubmitTransaction(&tx)
 	if engine.IsInvalidInputError(err) {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -163,5 +171,19 @@ func (h *handler) SendTransaction(_ context.Context, req *access.SendTransaction
 
 	txID := tx.ID()
 
+	md, err := rpc.TransactionReceiptMetadata(receipt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not encode transaction receipt: %v", err)
+	}
+	err = grpc.SetHeader(ctx, md)
+	if err != nil {
+		// only happens if the handler is called outside a gRPC server
+		h.log.Debug().Err(err).Msg("could not set transaction receipt header")
+	}
+
+	if !receipt.Admission.Accepted() {
+		return nil, status.Errorf(codes.ResourceExhausted, "transaction %x %s by collection node", txID, receipt.Admission)
+	}
+
 	return &access.SendTransactionResponse{Id: txID[:]}, nil
 }
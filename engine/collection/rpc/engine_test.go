@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow/protobuf/go/flow/access"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	rpcmock "github.com/onflow/flow-go/engine/collection/rpc/mock"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
@@ -20,14 +22,26 @@ func TestSubmitTransaction(t *testing.T) {
 	backend := new(rpcmock.Backend)
 
 	h := handler{
+		log:     unittest.Logger(),
 		chainID: flow.Testnet,
 		backend: backend,
 	}
 
 	tx := unittest.TransactionBodyFixture()
+	receiptFixture := func(admission flow.TransactionAdmission) *flow.TransactionAcceptanceReceipt {
+		return &flow.TransactionAcceptanceReceipt{
+			TransactionReceiptBody: flow.TransactionReceiptBody{
+				TransactionID: tx.ID(),
+				ClusterID:     unittest.IdentifierFixture(),
+				Admission:     admission,
+				CollectorID:   unittest.IdentifierFixture(),
+			},
+			CollectorSignature: unittest.SignatureFixture(),
+		}
+	}
 
 	t.Run("should submit transaction to engine", func(t *testing.T) {
-		backend.On("ProcessTransaction", &tx).Return(nil).Once()
+		backend.On("SubmitTransaction", &tx).Return(receiptFixture(flow.TransactionAdmitted), nil).Once()
 
 		res, err := h.SendTransaction(context.Background(), &access.SendTransactionRequest{
 			Transaction: convert.TransactionToMessage(tx),
@@ -35,15 +49,25 @@ func TestSubmitTransaction(t *testing.T) {
 		require.NoError(t, err)
 
 		// should submit the transaction to the engine
-		backend.AssertCalled(t, "ProcessTransaction", &tx)
+		backend.AssertCalled(t, "SubmitTransaction", &tx)
 
 		// should return the fingerprint of the submitted transaction
 		assert.Equal(t, tx.ID(), flow.HashToID(res.Id))
 	})
 
+	t.Run("should return error if mempool rejects transaction", func(t *testing.T) {
+		backend.On("SubmitTransaction", &tx).Return(receiptFixture(flow.TransactionRejected), nil).Once()
+
+		res, err := h.SendTransaction(context.Background(), &access.SendTransactionRequest{
+			Transaction: convert.TransactionToMessage(tx),
+		})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Nil(t, res)
+	})
+
 	t.Run("should pass through error", func(t *testing.T) {
 		expected := errors.New("error")
-		backend.On("ProcessTransaction", &tx).Return(expected).Once()
+		backend.On("SubmitTransaction", &tx).Return(nil, expected).Once()
 
 		res, err := h.SendTransaction(context.Background(), &access.SendTransactionRequest{
 			Transaction: convert.TransactionToMessage(tx),
@@ -53,7 +77,7 @@ func TestSubmitTransaction(t *testing.T) {
 		}
 
 		// should submit the transaction to the engine
-		backend.AssertCalled(t, "ProcessTransaction", &tx)
+		backend.AssertCalled(t, "SubmitTransaction", &tx)
 
 		// should only return the error
 		assert.Nil(t, res)
@@ -12,18 +12,30 @@ type Backend struct {
 	mock.Mock
 }
 
-// ProcessTransaction provides a mock function with given fields: _a0
-func (_m *Backend) ProcessTransaction(_a0 *flow.TransactionBody) error {
+// SubmitTransaction provides a mock function with given fields: _a0
+func (_m *Backend) SubmitTransaction(_a0 *flow.TransactionBody) (*flow.TransactionAcceptanceReceipt, error) {
 	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*flow.TransactionBody) error); ok {
+	var r0 *flow.TransactionAcceptanceReceipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*flow.TransactionBody) (*flow.TransactionAcceptanceReceipt, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(*flow.TransactionBody) *flow.TransactionAcceptanceReceipt); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TransactionAcceptanceReceipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*flow.TransactionBody) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 type mockConstructorTestingTNewBackend interface {
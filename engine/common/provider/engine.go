@@ -1,8 +1,10 @@
 package provider
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/rs/zerolog"
 	"github.com/vmihailenco/msgpack"
@@ -17,6 +19,7 @@ import (
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
@@ -52,13 +55,26 @@ type Engine struct {
 	channel        channels.Channel
 	requestHandler *engine.MessageHandler
 	requestQueue   engine.MessageStore
+	notifier       engine.Notifier
 	selector       flow.IdentityFilter
 	retrieve       RetrieveFunc
 	// buffered channel for EntityRequest workers to pick and process.
 	requestChannel chan *internal.EntityRequest
+
+	// the requests received through the request service of the network which await their response, by their ID.
+	serviceRequestsLock sync.Mutex
+	serviceRequests     map[uint64]chan<- serviceResponse
+	lastServiceRequest  uint64
+}
+
+// serviceResponse is the outcome of an entity request received through the request service of the network.
+type serviceResponse struct {
+	response *messages.EntityResponse
+	err      error
 }
 
 var _ network.MessageProcessor = (*Engine)(nil)
+var _ network.RequestHandler = (*Engine)(nil)
 
 // New creates a new provider engine, operating on the provided network channel, and accepting requests for entities
 // from a node within the set obtained by applying the provided selector filter. It uses the injected retrieve function
@@ -81,9 +97,10 @@ func New(
 		filter.Not(filter.HasNodeID(me.NodeID())),
 	)
 
+	notifier := engine.NewNotifier()
 	handler := engine.NewMessageHandler(
 		log,
-		engine.NewNotifier(),
+		notifier,
 		engine.Pattern{
 			// Match is called on every new message coming to this engine.
 			// Provider engine only expects EntityRequest.
@@ -114,15 +131,17 @@ func New(
 
 	// initialize the propagation engine with its dependencies
 	e := &Engine{
-		log:            log.With().Str("engine", "provider").Logger(),
-		metrics:        metrics,
-		state:          state,
-		channel:        channel,
-		selector:       selector,
-		retrieve:       retrieve,
-		requestHandler: handler,
-		requestQueue:   requestQueue,
-		requestChannel: make(chan *internal.EntityRequest, requestWorkers),
+		log:             log.With().Str("engine", "provider").Logger(),
+		metrics:         metrics,
+		state:           state,
+		channel:         channel,
+		selector:        selector,
+		retrieve:        retrieve,
+		requestHandler:  handler,
+		requestQueue:    requestQueue,
+		notifier:        notifier,
+		requestChannel:  make(chan *internal.EntityRequest, requestWorkers),
+		serviceRequests: make(map[uint64]chan<- serviceResponse),
 	}
 
 	// register the engine with the network layer and store the conduit
@@ -175,14 +194,135 @@ func (e *Engine) Process(channel channels.Channel, originID flow.Identifier, eve
 	return nil
 }
 
-// onEntityRequest processes an entity request message from a remote node.
+// onEntityRequest processes an entity request message from a remote node. The response to requests received through
+// the request service is handed over to the pending request, while other requests are answered by a unicast message.
 // Error returns:
 // * NetworkTransmissionError if there is a network error happens on transmitting the requested entities.
-// * InvalidInputError if the list of requested entities is invalid (empty).
+// * InvalidInputError if the list of requested entities is invalid (empty), or the requester is not authorized.
 // * generic error in case of unexpected failure or implementation bug.
 func (e *Engine) onEntityRequest(request *internal.EntityRequest) error {
 	defer e.metrics.MessageHandled(e.channel.String(), metrics.MessageEntityRequest)
 
+	res, err := e.entityResponse(request)
+	if request.ServiceRequestID != 0 {
+		e.respondToServiceRequest(request.ServiceRequestID, serviceResponse{response: res, err: err})
+	}
+	if err != nil {
+		return err
+	}
+
+	if request.ServiceRequestID == 0 {
+		err = e.con.Unicast(res, request.OriginId)
+		if err != nil {
+			return engine.NewNetworkTransmissionErrorf("could not send entity response: %w", err)
+		}
+	}
+
+	e.metrics.MessageSent(e.channel.String(), metrics.MessageEntityResponse)
+	e.log.Info().
+		Str("origin_id", request.OriginId.String()).
+		Strs("entity_ids", flow.IdentifierList(res.EntityIDs).Strings()).
+		Uint64("nonce", request.Nonce). // to match with the the entity request received log
+		Bool("request_service", request.ServiceRequestID != 0).
+		Msg("entity response sent")
+
+	return nil
+}
+
+// RegisterRequestService registers the engine as the handler of the request service of the network on its channel,
+// so that entity requests sent through the request service are served in addition to those sent as unicast messages.
+// Requests of both kinds share the request queue and the workers of the engine.
+// No errors are expected during normal operation.
+func (e *Engine) RegisterRequestService(net network.Network, opts ...network.RequestServiceOption) error {
+	_, err := net.RegisterRequestService(e.channel, e, opts...)
+	if err != nil {
+		return fmt.Errorf("could not register request service: %w", err)
+	}
+	return nil
+}
+
+// HandleRequest queues an entity request received through the request service of the network, and waits until a
+// worker of the engine has assembled the entity response to send back to the requester.
+// Error returns:
+// * InvalidInputError if the request is not an entity request, or the requester is not authorized.
+// * generic error if the request queue is full, the engine shuts down, the request is abandoned, or in case of
+// unexpected failure. The error is not propagated beyond the requester.
+func (e *Engine) HandleRequest(ctx context.Context, _ channels.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	select {
+	case <-e.cm.ShutdownSignal():
+		return nil, fmt.Errorf("provider engine is shutting down")
+	default:
+	}
+
+	req, ok := request.(*messages.EntityRequest)
+	if !ok {
+		return nil, engine.NewInvalidInputErrorf("invalid request type (%T)", request)
+	}
+
+	e.metrics.MessageReceived(e.channel.String(), metrics.MessageEntityRequest)
+
+	id, responses := e.addServiceRequest()
+	defer e.removeServiceRequest(id)
+
+	ok = e.requestQueue.Put(&engine.Message{
+		OriginID: originID,
+		Payload:  internal.ServiceEntityRequest{ID: id, Request: *req},
+	})
+	if !ok {
+		return nil, fmt.Errorf("entity request queue is full")
+	}
+	e.notifier.Notify()
+
+	select {
+	case res := <-responses:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return res.response, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-e.cm.ShutdownSignal():
+		return nil, fmt.Errorf("provider engine is shutting down")
+	}
+}
+
+// addServiceRequest registers a pending request service request, and returns its ID along with the channel its
+// response is delivered on.
+func (e *Engine) addServiceRequest() (uint64, <-chan serviceResponse) {
+	e.serviceRequestsLock.Lock()
+	defer e.serviceRequestsLock.Unlock()
+
+	e.lastServiceRequest++
+	responses := make(chan serviceResponse, 1)
+	e.serviceRequests[e.lastServiceRequest] = responses
+	return e.lastServiceRequest, responses
+}
+
+// removeServiceRequest removes a pending request service request, once it is answered or abandoned.
+func (e *Engine) removeServiceRequest(id uint64) {
+	e.serviceRequestsLock.Lock()
+	defer e.serviceRequestsLock.Unlock()
+	delete(e.serviceRequests, id)
+}
+
+// respondToServiceRequest delivers the response to the pending request service request with the given ID. Responses
+// to abandoned requests are dropped.
+func (e *Engine) respondToServiceRequest(id uint64, res serviceResponse) {
+	e.serviceRequestsLock.Lock()
+	responses, ok := e.serviceRequests[id]
+	e.serviceRequestsLock.Unlock()
+	if !ok {
+		return
+	}
+	// the channel is buffered for the single response of the request, hence sending does not block.
+	responses <- res
+}
+
+// entityResponse assembles the response to an entity request, containing the requested entities which are available.
+// Error returns:
+// * InvalidInputError if the requester is not authorized.
+// * generic error in case of unexpected failure or implementation bug.
+func (e *Engine) entityResponse(request *internal.EntityRequest) (*messages.EntityResponse, error) {
 	lg := e.log.With().
 		Str("origin_id", request.OriginId.String()).
 		Strs("entity_ids", flow.IdentifierList(request.EntityIds).Strings()).
@@ -192,8 +332,6 @@ func (e *Engine) onEntityRequest(request *internal.EntityRequest) error {
 		Uint64("nonce", request.Nonce).
 		Msg("entity request received")
 
-	// TODO: add reputation system to punish nodes for malicious behaviour (spam / repeated requests)
-
 	// then, we try to get the current identity of the requester and check it against the filter
 	// for the handler to make sure the requester is authorized for this resource
 	requesters, err := e.state.Final().Identities(filter.And(
@@ -201,10 +339,11 @@ func (e *Engine) onEntityRequest(request *internal.EntityRequest) error {
 		filter.HasNodeID(request.OriginId)),
 	)
 	if err != nil {
-		return fmt.Errorf("could not get requesters: %w", err)
+		return nil, fmt.Errorf("could not get requesters: %w", err)
 	}
 	if len(requesters) == 0 {
-		return engine.NewInvalidInputErrorf("invalid requester origin (%x)", request.OriginId)
+		e.reportUnauthorizedRequester(request.OriginId)
+		return nil, engine.NewInvalidInputErrorf("invalid requester origin (%x)", request.OriginId)
 	}
 
 	// try to retrieve each entity and skip missing ones
@@ -229,7 +368,7 @@ func (e *Engine) onEntityRequest(request *internal.EntityRequest) error {
 			continue
 		}
 		if err != nil {
-			return fmt.Errorf("could not retrieve entity (%x): %w", entityID, err)
+			return nil, fmt.Errorf("could not retrieve entity (%x): %w", entityID, err)
 		}
 		entities = append(entities, entity)
 		entityIDs = append(entityIDs, entityID)
@@ -241,7 +380,7 @@ func (e *Engine) onEntityRequest(request *internal.EntityRequest) error {
 	for _, entity := range entities {
 		blob, err := msgpack.Marshal(entity)
 		if err != nil {
-			return fmt.Errorf("could not encode entity (%x): %w", entity.ID(), err)
+			return nil, fmt.Errorf("could not encode entity (%x): %w", entity.ID(), err)
 		}
 		blobs = append(blobs, blob)
 	}
@@ -251,25 +390,23 @@ func (e *Engine) onEntityRequest(request *internal.EntityRequest) error {
 	// allows him to retry them immediately, rather than waiting for the expiry
 	// of the retry interval
 
-	// send back the response
-	res := &messages.EntityResponse{
+	return &messages.EntityResponse{
 		Nonce:     request.Nonce,
 		EntityIDs: entityIDs,
 		Blobs:     blobs,
-	}
-	err = e.con.Unicast(res, request.OriginId)
+	}, nil
+}
+
+// reportUnauthorizedRequester reports a requester which is not authorized to request the entities of the engine to the
+// application layer spam prevention (ALSP) protocol.
+func (e *Engine) reportUnauthorizedRequester(originID flow.Identifier) {
+	report, err := alsp.NewMisbehaviorReport(originID, alsp.UnAuthorizedSender)
 	if err != nil {
-		return engine.NewNetworkTransmissionErrorf("could not send entity response: %w", err)
+		// failing to create the report is a bug, but dropping the report does not harm the safety of the node
+		e.log.Error().Err(err).Hex("origin_id", logging.ID(originID)).Msg("could not create misbehavior report for unauthorized requester")
+		return
 	}
-
-	e.metrics.MessageSent(e.channel.String(), metrics.MessageEntityResponse)
-	e.log.Info().
-		Str("origin_id", request.OriginId.String()).
-		Strs("entity_ids", flow.IdentifierList(entityIDs).Strings()).
-		Uint64("nonce", request.Nonce). // to match with the the entity request received log
-		Msg("entity response sent")
-
-	return nil
+	e.con.ReportMisbehavior(report)
 }
 
 func (e *Engine) processQueuedRequestsShovellerWorker(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
@@ -305,17 +442,26 @@ func (e *Engine) processAvailableMessages(ctx irrecoverable.SignalerContext) {
 			return
 		}
 
-		requestEvent, ok := msg.Payload.(messages.EntityRequest)
-		if !ok {
-			// should never happen, as we only put EntityRequest in the queue,
+		var req *internal.EntityRequest
+		switch requestEvent := msg.Payload.(type) {
+		case messages.EntityRequest:
+			req = &internal.EntityRequest{
+				OriginId:  msg.OriginID,
+				EntityIds: requestEvent.EntityIDs,
+				Nonce:     requestEvent.Nonce,
+			}
+		case internal.ServiceEntityRequest:
+			req = &internal.EntityRequest{
+				OriginId:         msg.OriginID,
+				EntityIds:        requestEvent.Request.EntityIDs,
+				Nonce:            requestEvent.Request.Nonce,
+				ServiceRequestID: requestEvent.ID,
+			}
+		default:
+			// should never happen, as we only put entity requests in the queue,
 			// if it does happen, it means there is a bug in the queue implementation.
 			ctx.Throw(fmt.Errorf("invalid message type in entity request queue: %T", msg.Payload))
-		}
-
-		req := &internal.EntityRequest{
-			OriginId:  msg.OriginID,
-			EntityIds: requestEvent.EntityIDs,
-			Nonce:     requestEvent.Nonce,
+			return
 		}
 
 		lg := e.log.With().
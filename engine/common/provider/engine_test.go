@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vmihailenco/msgpack"
 
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/provider"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
@@ -19,6 +20,8 @@ import (
 	"github.com/onflow/flow-go/module/mempool/queue"
 	"github.com/onflow/flow-go/module/metrics"
 	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -423,6 +426,10 @@ func TestOnEntityRequestInvalidOrigin(t *testing.T) {
 	net := mocknetwork.NewNetwork(t)
 	con := mocknetwork.NewConduit(t)
 	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
+	// the unauthorized requester is reported to ALSP
+	con.On("ReportMisbehavior", mock.MatchedBy(func(report network.MisbehaviorReport) bool {
+		return report.OriginId() == originID && report.Reason() == alsp.UnAuthorizedSender
+	})).Once()
 	me := mockmodule.NewLocal(t)
 	me.On("NodeID").Return(unittest.IdentifierFixture())
 	requestQueue := queue.NewHeroStore(10, unittest.Logger(), metrics.NewNoopCollector())
@@ -451,3 +458,78 @@ func TestOnEntityRequestInvalidOrigin(t *testing.T) {
 	require.NoError(t, err)
 	unittest.RequireCloseBefore(t, e.Done(), 100*time.Millisecond, "could not stop engine")
 }
+
+// TestHandleRequest evaluates that entity requests received through the request service are served by the workers of
+// the engine, and that unauthorized requesters are rejected and reported to ALSP.
+func TestHandleRequest(t *testing.T) {
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := irrecoverable.NewMockSignalerContext(t, cancelCtx)
+
+	requesterID := unittest.IdentifierFixture()
+	unauthorizedID := unittest.IdentifierFixture()
+	identities := flow.IdentityList{{NodeID: requesterID, Role: flow.RoleAccess}, {NodeID: unauthorizedID, Role: flow.RoleAccess}}
+	coll := unittest.CollectionFixture(1)
+	retrieve := func(entityID flow.Identifier) (flow.Entity, error) {
+		if entityID != coll.ID() {
+			return nil, storage.ErrNotFound
+		}
+		return &coll, nil
+	}
+
+	final := protocol.NewSnapshot(t)
+	final.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return identities.Filter(selector)
+		},
+		nil,
+	)
+	state := protocol.NewState(t)
+	state.On("Final").Return(final, nil)
+
+	net := mocknetwork.NewNetwork(t)
+	con := mocknetwork.NewConduit(t)
+	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
+	me := mockmodule.NewLocal(t)
+	me.On("NodeID").Return(unittest.IdentifierFixture())
+
+	e, err := provider.New(
+		unittest.Logger(),
+		metrics.NewNoopCollector(),
+		net,
+		me,
+		state,
+		queue.NewHeroStore(10, unittest.Logger(), metrics.NewNoopCollector()),
+		provider.DefaultRequestProviderWorkers,
+		channels.TestNetworkChannel,
+		filter.HasNodeID(requesterID),
+		retrieve)
+	require.NoError(t, err)
+	e.Start(ctx)
+	unittest.RequireCloseBefore(t, e.Ready(), 100*time.Millisecond, "could not start engine")
+
+	t.Run("authorized requester", func(t *testing.T) {
+		request := &messages.EntityRequest{Nonce: rand.Uint64(), EntityIDs: []flow.Identifier{coll.ID(), unittest.IdentifierFixture()}}
+		res, err := e.HandleRequest(context.Background(), channels.TestNetworkChannel, requesterID, request)
+		require.NoError(t, err)
+
+		// the response is returned to the requester rather than sent by unicast
+		response := res.(*messages.EntityResponse)
+		require.Equal(t, request.Nonce, response.Nonce)
+		require.Equal(t, []flow.Identifier{coll.ID()}, response.EntityIDs)
+		con.AssertNotCalled(t, "Unicast", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthorized requester", func(t *testing.T) {
+		con.On("ReportMisbehavior", mock.MatchedBy(func(report network.MisbehaviorReport) bool {
+			return report.OriginId() == unauthorizedID && report.Reason() == alsp.UnAuthorizedSender
+		})).Once()
+
+		request := &messages.EntityRequest{Nonce: rand.Uint64(), EntityIDs: []flow.Identifier{coll.ID()}}
+		_, err := e.HandleRequest(context.Background(), channels.TestNetworkChannel, unauthorizedID, request)
+		require.True(t, engine.IsInvalidInputError(err))
+	})
+
+	cancel()
+	unittest.RequireCloseBefore(t, e.Done(), 100*time.Millisecond, "could not stop engine")
+}
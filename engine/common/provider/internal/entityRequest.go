@@ -1,9 +1,23 @@
 package internal
 
-import "github.com/onflow/flow-go/model/flow"
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+)
 
 type EntityRequest struct {
 	OriginId  flow.Identifier
 	EntityIds []flow.Identifier
 	Nonce     uint64
+	// ServiceRequestID identifies the pending request service request awaiting the response, it is zero if the
+	// request was received as a unicast message.
+	ServiceRequestID uint64
+}
+
+// ServiceEntityRequest is an entity request received through the request service of the network, as it is buffered in
+// the request queue of the provider. The ID is unique among the pending requests of the request service, so that
+// identical requests are not deduplicated by the queue while their requesters await a response.
+type ServiceEntityRequest struct {
+	ID      uint64
+	Request messages.EntityRequest
 }
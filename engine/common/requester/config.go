@@ -6,13 +6,14 @@ import (
 )
 
 type Config struct {
-	BatchInterval   time.Duration // minimum interval between requests
-	BatchThreshold  uint          // maximum batch size for one request
-	RetryInitial    time.Duration // interval after which we retry request for an entity
-	RetryFunction   RetryFunc     // function determining growth of retry interval
-	RetryMaximum    time.Duration // maximum interval for retrying request for an entity
-	RetryAttempts   uint          // maximum amount of request attempts per entity
-	ValidateStaking bool          // should staking of target/origin be checked
+	BatchInterval     time.Duration // minimum interval between requests
+	BatchThreshold    uint          // maximum batch size for one request
+	RetryInitial      time.Duration // interval after which we retry request for an entity
+	RetryFunction     RetryFunc     // function determining growth of retry interval
+	RetryMaximum      time.Duration // maximum interval for retrying request for an entity
+	RetryAttempts     uint          // maximum amount of request attempts per entity
+	ValidateStaking   bool          // should staking of target/origin be checked
+	UseRequestService bool          // should requests be sent through the request service of the network
 }
 
 type RetryFunc func(time.Duration) time.Duration
@@ -95,3 +96,12 @@ func WithValidateStaking(validateStaking bool) OptionFunc {
 		cfg.ValidateStaking = validateStaking
 	}
 }
+
+// WithRequestService sets the flag which determines if entities are requested through the request service of the
+// network, rather than by unicast messages on the conduit of the engine. Requests to providers which do not support the
+// request service fall back to unicast messages, so providers can be migrated gradually.
+func WithRequestService(useRequestService bool) OptionFunc {
+	return func(cfg *Config) {
+		cfg.UseRequestService = useRequestService
+	}
+}
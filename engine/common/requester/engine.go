@@ -1,6 +1,7 @@
 package requester
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
@@ -47,6 +48,10 @@ type Engine struct {
 	create   CreateFunc
 	handle   HandleFunc
 
+	// requestService is used to send requests if the engine is configured to use the request service of the network,
+	// nil otherwise.
+	requestService network.RequestService
+
 	// changing the following state variables must be guarded by unit.Lock()
 	items                 map[flow.Identifier]*Item
 	requests              map[uint64]*messages.EntityRequest
@@ -125,6 +130,15 @@ func New(log zerolog.Logger, metrics module.EngineMetrics, net network.Network,
 	}
 	e.con = con
 
+	if cfg.UseRequestService {
+		// the requester only sends requests, hence it registers no handler for inbound requests.
+		requestService, err := net.RegisterRequestService(channel, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not register request service: %w", err)
+		}
+		e.requestService = requestService
+	}
+
 	return e, nil
 }
 
@@ -425,9 +439,16 @@ func (e *Engine) dispatchRequest() (bool, error) {
 			Msg("sending entity request")
 	}
 
-	err = e.con.Unicast(req, providerID)
-	if err != nil {
-		return true, fmt.Errorf("could not send request for entities %v: %w", logging.IDs(entityIDs), err)
+	if e.requestService != nil {
+		// the response is awaited asynchronously, as the engine is locked while dispatching requests.
+		e.unit.Launch(func() {
+			e.requestThroughService(providerID, req)
+		})
+	} else {
+		err = e.con.Unicast(req, providerID)
+		if err != nil {
+			return true, fmt.Errorf("could not send request for entities %v: %w", logging.IDs(entityIDs), err)
+		}
 	}
 	e.requests[req.Nonce] = req
 
@@ -462,6 +483,43 @@ func (e *Engine) dispatchRequest() (bool, error) {
 	return true, nil
 }
 
+// requestThroughService sends the entity request to the provider through the request service, and processes its
+// response. Only if the provider does not serve requests through the request service, the request is sent as a unicast
+// message instead, and the response is processed upon its arrival on the conduit. Requests which fail for any other
+// reason, e.g., because the provider rejected the request or sent an invalid response, are not sent again until the
+// retry interval of their entities expires.
+func (e *Engine) requestThroughService(providerID flow.Identifier, req *messages.EntityRequest) {
+	lg := e.log.With().
+		Hex("provider", logging.ID(providerID)).
+		Uint64("nonce", req.Nonce).
+		Logger()
+
+	// the request is abandoned when it is due to be retried.
+	ctx, cancel := context.WithTimeout(e.unit.Ctx(), e.cfg.RetryInitial)
+	defer cancel()
+
+	res, err := e.requestService.Request(ctx, providerID, req)
+	switch {
+	case err == nil:
+	case network.IsErrRequestUnsupported(err):
+		lg.Debug().Err(err).Msg("provider does not serve the request service, falling back to unicast")
+		err = e.con.Unicast(req, providerID)
+		if err != nil {
+			lg.Error().Err(err).Msg("could not send entity request")
+		}
+		return
+	default:
+		// the entities are requested again once their retry interval expires.
+		lg.Debug().Err(err).Msg("entity request through request service failed")
+		return
+	}
+
+	err = e.process(providerID, res)
+	if err != nil {
+		engine.LogError(e.log, err)
+	}
+}
+
 // process processes events for the propagation engine on the consensus node.
 func (e *Engine) process(originID flow.Identifier, message interface{}) error {
 
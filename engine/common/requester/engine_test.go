@@ -1,6 +1,8 @@
 package requester
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
 	"testing"
 	"time"
@@ -19,6 +21,7 @@ import (
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -486,3 +489,105 @@ func TestOriginValidation(t *testing.T) {
 	// handler are called async, but this should be extremely quick
 	unittest.AssertClosesBefore(t, called, time.Second)
 }
+
+// TestDispatchRequestThroughService evaluates that requests are sent through the request service if the engine uses it,
+// and that they fall back to unicast messages if the provider does not serve the request service.
+func TestDispatchRequestThroughService(t *testing.T) {
+	targetID := unittest.IdentifierFixture()
+	identities := flow.IdentityList{{NodeID: targetID, Role: flow.RoleCollection}}
+
+	final := &protocol.Snapshot{}
+	final.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return identities.Filter(selector)
+		},
+		nil,
+	)
+	state := &protocol.State{}
+	state.On("Final").Return(final)
+
+	newEngine := func(con *mocknetwork.Conduit, requestService *mocknetwork.RequestService, handle HandleFunc) (*Engine, flow.Collection) {
+		collection := unittest.CollectionFixture(1)
+		request := &Engine{
+			unit:           engine.NewUnit(),
+			log:            unittest.Logger(),
+			metrics:        metrics.NewNoopCollector(),
+			cfg:            Config{RetryInitial: time.Second, RetryFunction: RetryConstant(), RetryMaximum: time.Second, RetryAttempts: 3, BatchThreshold: 10},
+			state:          state,
+			con:            con,
+			requestService: requestService,
+			items:          make(map[flow.Identifier]*Item),
+			requests:       make(map[uint64]*messages.EntityRequest),
+			selector:       filter.HasNodeID(targetID),
+			create:         func() flow.Entity { return &flow.Collection{} },
+			handle:         handle,
+		}
+		request.EntityByID(collection.ID(), filter.Any)
+		return request, collection
+	}
+
+	t.Run("response through request service", func(t *testing.T) {
+		done := make(chan struct{})
+		var received flow.Identifier
+		request, collection := newEngine(mocknetwork.NewConduit(t), mocknetwork.NewRequestService(t), func(_ flow.Identifier, entity flow.Entity) {
+			received = entity.ID()
+			close(done)
+		})
+
+		blob, err := msgpack.Marshal(collection)
+		require.NoError(t, err)
+		request.requestService.(*mocknetwork.RequestService).On("Request", mock.Anything, targetID, mock.Anything).Return(
+			func(_ context.Context, _ flow.Identifier, req interface{}) interface{} {
+				return &messages.EntityResponse{
+					Nonce:     req.(*messages.EntityRequest).Nonce,
+					EntityIDs: []flow.Identifier{collection.ID()},
+					Blobs:     [][]byte{blob},
+				}
+			}, nil).Once()
+
+		dispatched, err := request.dispatchRequest()
+		require.NoError(t, err)
+		require.True(t, dispatched)
+
+		unittest.AssertClosesBefore(t, done, time.Second)
+		require.Equal(t, collection.ID(), received)
+		request.unit.Lock()
+		require.NotContains(t, request.items, collection.ID())
+		request.unit.Unlock()
+	})
+
+	t.Run("fallback to unicast", func(t *testing.T) {
+		con := mocknetwork.NewConduit(t)
+		requestService := mocknetwork.NewRequestService(t)
+		request, collection := newEngine(con, requestService, func(flow.Identifier, flow.Entity) {})
+
+		requestService.On("Request", mock.Anything, targetID, mock.Anything).Return(nil, network.NewRequestUnsupportedErr(targetID, fmt.Errorf("protocols not supported"))).Once()
+		sent := make(chan struct{})
+		con.On("Unicast", mock.Anything, targetID).Run(func(args mock.Arguments) {
+			require.Equal(t, []flow.Identifier{collection.ID()}, args.Get(0).(*messages.EntityRequest).EntityIDs)
+			close(sent)
+		}).Return(nil).Once()
+
+		dispatched, err := request.dispatchRequest()
+		require.NoError(t, err)
+		require.True(t, dispatched)
+		unittest.AssertClosesBefore(t, sent, time.Second)
+	})
+
+	t.Run("no fallback on other failures", func(t *testing.T) {
+		// the conduit is strict, hence sending a unicast message fails the test
+		requestService := mocknetwork.NewRequestService(t)
+		request, _ := newEngine(mocknetwork.NewConduit(t), requestService, func(flow.Identifier, flow.Entity) {})
+
+		failed := make(chan struct{})
+		requestService.On("Request", mock.Anything, targetID, mock.Anything).Run(func(mock.Arguments) {
+			close(failed)
+		}).Return(nil, fmt.Errorf("could not decode response")).Once()
+
+		dispatched, err := request.dispatchRequest()
+		require.NoError(t, err)
+		require.True(t, dispatched)
+		unittest.AssertClosesBefore(t, failed, time.Second)
+		<-request.unit.Done()
+	})
+}
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultForwardedTransactionsCacheSize is the default number of forwarded transactions
+	// remembered for deduplication.
+	DefaultForwardedTransactionsCacheSize = 100_000
+	// DefaultForwardedTransactionsTTL is the default time for which a forwarded transaction is
+	// remembered. It roughly corresponds to the transaction expiry of 600 blocks.
+	DefaultForwardedTransactionsTTL = 10 * time.Minute
+)
+
+// forwardedTransaction is an entry of the ForwardedTransactions cache.
+type forwardedTransaction struct {
+	receipt   *flow.TransactionAcceptanceReceipt // may be nil
+	forwarded time.Time                          // zero while the transaction is being forwarded
+	done      chan struct{}                      // closed once the transaction was forwarded, or its forwarding was released
+}
+
+// ForwardedTransactions is a bounded cache of recently forwarded transactions, used to
+// suppress forwarding the same transaction more than once. Each component of a node which
+// forwards transactions holds its own cache, so that a transaction submitted repeatedly to
+// the component is forwarded at most once within the cache's time-to-live.
+//
+// Forwarding is reserved atomically: while one caller forwards a transaction, concurrent
+// callers submitting the same transaction wait for the outcome, instead of forwarding it again.
+//
+// For each transaction, the cache optionally holds the acceptance receipt returned by the
+// collection node which the transaction was forwarded to, so that duplicate submissions
+// can be answered with the original receipt.
+//
+// ForwardedTransactions is safe for concurrent use.
+type ForwardedTransactions struct {
+	mu    sync.Mutex
+	cache *lru.Cache[flow.Identifier, *forwardedTransaction]
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewForwardedTransactions returns a new cache remembering at most size transactions for
+// the given time-to-live.
+// No errors are expected during normal operation.
+func NewForwardedTransactions(size int, ttl time.Duration) (*ForwardedTransactions, error) {
+	// wake up callers waiting for a transaction whose reservation is evicted or released
+	onEvict := func(_ flow.Identifier, entry *forwardedTransaction) {
+		if !entry.isForwarded() {
+			close(entry.done)
+		}
+	}
+	cache, err := lru.NewWithEvict[flow.Identifier, *forwardedTransaction](size, onEvict)
+	if err != nil {
+		return nil, fmt.Errorf("could not create forwarded transactions cache: %w", err)
+	}
+	return &ForwardedTransactions{
+		cache: cache,
+		ttl:   ttl,
+		now:   time.Now,
+	}, nil
+}
+
+// Reserve atomically checks whether the transaction was forwarded within the time-to-live,
+// and otherwise reserves forwarding it for the caller. If the transaction is being forwarded
+// by another caller, Reserve waits until the forwarding has completed or was released.
+//
+// It returns true if the caller must forward the transaction, and subsequently call either
+// Complete or Release. Otherwise, it returns false along with the acceptance receipt recorded
+// for the transaction, which may be nil.
+//
+// Expected errors during normal operation:
+//   - context.Canceled or context.DeadlineExceeded if the context is done while waiting
+func (f *ForwardedTransactions) Reserve(ctx context.Context, txID flow.Identifier) (*flow.TransactionAcceptanceReceipt, bool, error) {
+	for {
+		f.mu.Lock()
+		entry, ok := f.cache.Get(txID)
+		if ok && entry.isForwarded() && f.now().Sub(entry.forwarded) > f.ttl {
+			f.cache.Remove(txID)
+			ok = false
+		}
+		if !ok {
+			f.cache.Add(txID, &forwardedTransaction{done: make(chan struct{})})
+			f.mu.Unlock()
+			return nil, true, nil
+		}
+		if entry.isForwarded() {
+			f.mu.Unlock()
+			return entry.receipt, false, nil
+		}
+		done := entry.done
+		f.mu.Unlock()
+
+		// wait for the concurrent forwarding, and check again for its outcome
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-done:
+		}
+	}
+}
+
+// Complete records that the transaction reserved by the caller was forwarded, along with the
+// acceptance receipt returned for it (which may be nil).
+func (f *ForwardedTransactions) Complete(txID flow.Identifier, receipt *flow.TransactionAcceptanceReceipt) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.cache.Peek(txID)
+	if ok && !entry.isForwarded() {
+		entry.receipt = receipt
+		entry.forwarded = f.now()
+		close(entry.done)
+		return
+	}
+	// the reservation was evicted from the cache in the meantime
+	entry = &forwardedTransaction{
+		receipt:   receipt,
+		forwarded: f.now(),
+		done:      make(chan struct{}),
+	}
+	close(entry.done)
+	f.cache.Add(txID, entry)
+}
+
+// Release releases the reservation of a transaction which could not be forwarded, so that it
+// will be forwarded by the next caller.
+func (f *ForwardedTransactions) Release(txID flow.Identifier) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.cache.Peek(txID)
+	if !ok || entry.isForwarded() {
+		return
+	}
+	f.cache.Remove(txID) // closes the done channel upon eviction
+}
+
+// isForwarded returns true if the transaction was forwarded, false if it is being forwarded.
+func (e *forwardedTransaction) isForwarded() bool {
+	return !e.forwarded.IsZero()
+}
@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestForwardedTransactions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cache, err := NewForwardedTransactions(2, time.Minute)
+	require.NoError(t, err)
+	cache.now = func() time.Time { return now }
+
+	txID := unittest.IdentifierFixture()
+	receipt := &flow.TransactionAcceptanceReceipt{
+		TransactionReceiptBody: flow.TransactionReceiptBody{
+			TransactionID: txID,
+			Admission:     flow.TransactionAdmitted,
+		},
+	}
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		_, reserved, err := cache.Reserve(ctx, txID)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+	})
+
+	t.Run("forwarded transaction", func(t *testing.T) {
+		cache.Complete(txID, receipt)
+		actual, reserved, err := cache.Reserve(ctx, txID)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, receipt, actual)
+	})
+
+	t.Run("expired entry", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		_, reserved, err := cache.Reserve(ctx, txID)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+	})
+
+	t.Run("released entry", func(t *testing.T) {
+		cache.Release(txID)
+		_, reserved, err := cache.Reserve(ctx, txID)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+		cache.Complete(txID, nil)
+	})
+
+	t.Run("bounded size", func(t *testing.T) {
+		first := unittest.IdentifierFixture()
+		cache.Complete(first, nil)
+		cache.Complete(unittest.IdentifierFixture(), nil)
+		cache.Complete(unittest.IdentifierFixture(), nil)
+		_, reserved, err := cache.Reserve(ctx, first)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+	})
+}
+
+// TestForwardedTransactions_Concurrent tests that concurrent submissions of a transaction being
+// forwarded wait for the outcome of the forwarding.
+func TestForwardedTransactions_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewForwardedTransactions(10, time.Minute)
+	require.NoError(t, err)
+	txID := unittest.IdentifierFixture()
+
+	_, reserved, err := cache.Reserve(ctx, txID)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	t.Run("waiting caller is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, _, err := cache.Reserve(cancelled, txID)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("forwarding is released", func(t *testing.T) {
+		results := make(chan bool, 1)
+		go func() {
+			_, reserved, err := cache.Reserve(ctx, txID)
+			assert.NoError(t, err)
+			results <- reserved
+		}()
+		assert.Never(t, func() bool { return len(results) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+
+		// the waiting caller takes over forwarding the transaction
+		cache.Release(txID)
+		assert.True(t, <-results)
+	})
+
+	t.Run("forwarding is completed", func(t *testing.T) {
+		receipt := &flow.TransactionAcceptanceReceipt{
+			TransactionReceiptBody: flow.TransactionReceiptBody{TransactionID: txID},
+		}
+		results := make(chan *flow.TransactionAcceptanceReceipt, 1)
+		go func() {
+			actual, reserved, err := cache.Reserve(ctx, txID)
+			assert.NoError(t, err)
+			assert.False(t, reserved)
+			results <- actual
+		}()
+		assert.Never(t, func() bool { return len(results) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+
+		cache.Complete(txID, receipt)
+		assert.Equal(t, receipt, <-results)
+	})
+}
+
+func TestTransactionReceiptMetadata(t *testing.T) {
+	receipt := &flow.TransactionAcceptanceReceipt{
+		TransactionReceiptBody: flow.TransactionReceiptBody{
+			TransactionID: unittest.IdentifierFixture(),
+			ClusterID:     unittest.IdentifierFixture(),
+			Admission:     flow.TransactionRouted,
+			CollectorID:   unittest.IdentifierFixture(),
+		},
+		CollectorSignature: unittest.SignatureFixture(),
+	}
+
+	md, err := TransactionReceiptMetadata(receipt)
+	require.NoError(t, err)
+
+	decoded, ok, err := TransactionReceiptFromMetadata(md)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, receipt, decoded)
+
+	_, ok, err = TransactionReceiptFromMetadata(nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/signature"
+)
+
+// TransactionReceiptHeader is the gRPC response header carrying the transaction acceptance
+// receipt of a collection node, in response to a SendTransaction request. Since the
+// SendTransaction response message is defined by the public Access API, the receipt is
+// returned as response metadata, so that clients unaware of receipts are unaffected.
+const TransactionReceiptHeader = "flow-transaction-receipt-bin"
+
+// TransactionReceiptMetadata returns the gRPC metadata carrying the given receipt.
+// No errors are expected during normal operation.
+func TransactionReceiptMetadata(receipt *flow.TransactionAcceptanceReceipt) (metadata.MD, error) {
+	encoded, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode transaction receipt: %w", err)
+	}
+	return metadata.Pairs(TransactionReceiptHeader, string(encoded)), nil
+}
+
+// TransactionReceiptFromMetadata extracts the transaction receipt from gRPC metadata.
+// Returns false if the metadata does not carry a receipt.
+// Returns an error if the metadata carries a receipt which cannot be decoded.
+func TransactionReceiptFromMetadata(md metadata.MD) (*flow.TransactionAcceptanceReceipt, bool, error) {
+	values := md.Get(TransactionReceiptHeader)
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	var receipt flow.TransactionAcceptanceReceipt
+	err := json.Unmarshal([]byte(values[0]), &receipt)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not decode transaction receipt: %w", err)
+	}
+	return &receipt, true, nil
+}
+
+// VerifyTransactionReceipt checks that the receipt is issued by the given collection node
+// for the given transaction, and that its signature is valid w.r.t. the node's staking key.
+// Returns an error if the receipt is invalid.
+func VerifyTransactionReceipt(receipt *flow.TransactionAcceptanceReceipt, txID flow.Identifier, collector *flow.Identity) error {
+	if receipt.TransactionID != txID {
+		return fmt.Errorf("receipt is for transaction %x, expected %x", receipt.TransactionID, txID)
+	}
+	if receipt.CollectorID != collector.NodeID {
+		return fmt.Errorf("receipt is issued by %x, expected %x", receipt.CollectorID, collector.NodeID)
+	}
+	id := receipt.ID()
+	valid, err := collector.StakingPubKey.Verify(receipt.CollectorSignature, id[:], signature.NewBLSHasher(signature.TransactionReceiptTag))
+	if err != nil {
+		return fmt.Errorf("could not verify receipt signature: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("invalid receipt signature by %x", collector.NodeID)
+	}
+	return nil
+}
@@ -70,6 +70,10 @@ func (n *Network) RegisterPingService(pid protocol.ID, provider network.PingInfo
 	return n.net.RegisterPingService(pid, provider)
 }
 
+func (n *Network) RegisterRequestService(channel channels.Channel, handler network.RequestHandler, opts ...network.RequestServiceOption) (network.RequestService, error) {
+	return n.net.RegisterRequestService(channel, handler, opts...)
+}
+
 // Register will subscribe the given engine with the spitter on the given channel, and all registered
 // engines will be notified with incoming messages on the channel.
 // The returned Conduit can be used to send messages to engines on other nodes subscribed to the same channel
@@ -267,6 +267,20 @@ func (e *ReactorEngine) handleEpochCommittedPhaseStarted(currentEpochCounter uin
 		Uint64("next_epoch", nextEpochCounter).   // the epoch the just-finished DKG was preparing for
 		Logger()
 
+	// A recovery epoch, which ends epoch fallback mode, may reuse the DKG of the current epoch.
+	// In this case, our beacon key for the current epoch is also our beacon key for the next
+	// epoch, superseding the result of the DKG for the next epoch (which typically failed).
+	reused, err := e.reuseCurrentBeaconKey(currentEpochCounter, firstBlock)
+	if err != nil {
+		// TODO use irrecoverable context
+		log.Fatal().Err(err).Msg("checking beacon key consistency: could not check whether next epoch reuses current DKG")
+		return
+	}
+	if reused {
+		log.Info().Msgf("next epoch reuses the DKG of the current epoch, reusing my beacon key for epoch %d", nextEpochCounter)
+		return
+	}
+
 	// Check whether we have already set the end state for this DKG.
 	// This can happen if the DKG failed locally, if we failed to generate
 	// a local private beacon key, or if we crashed while performing this
@@ -335,6 +349,59 @@ func (e *ReactorEngine) handleEpochCommittedPhaseStarted(currentEpochCounter uin
 	log.Info().Msgf("successfully ended DKG, my beacon pub key for epoch %d is %s", nextEpochCounter, localPubKey)
 }
 
+// reuseCurrentBeaconKey stores our beacon key for the current epoch as our beacon key
+// for the next epoch, if the next epoch reuses the DKG of the current epoch. This is
+// the case for a recovery epoch, committed while epoch fallback mode is in effect.
+// Returns true if the key was reused, and false if the next epoch has its own DKG, or
+// we have no safe beacon key for the current epoch consistent with the next epoch.
+// No errors are expected during normal operation.
+func (e *ReactorEngine) reuseCurrentBeaconKey(currentEpochCounter uint64, firstBlock *flow.Header) (bool, error) {
+	epochs := e.State.AtBlockID(firstBlock.ID()).Epochs()
+	currentDKG, err := epochs.Current().DKG()
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve current DKG info: %w", err)
+	}
+	nextDKG, err := epochs.Next().DKG()
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve next DKG info: %w", err)
+	}
+	if !nextDKG.GroupKey().Equals(currentDKG.GroupKey()) {
+		return false, nil
+	}
+
+	endState, err := e.dkgState.GetDKGEndState(currentEpochCounter)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve dkg end state for current epoch: %w", err)
+	}
+	if endState != flow.DKGEndStateSuccess {
+		return false, nil
+	}
+	myBeaconPrivKey, err := e.dkgState.RetrieveMyBeaconPrivateKey(currentEpochCounter)
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve beacon private key for current epoch: %w", err)
+	}
+
+	nextDKGPubKey, err := nextDKG.KeyShare(e.me.NodeID())
+	if protocol.IsIdentityNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve my beacon public key for next epoch: %w", err)
+	}
+	if !nextDKGPubKey.Equals(myBeaconPrivKey.PublicKey()) {
+		return false, nil
+	}
+
+	err = e.dkgState.RecoverMyBeaconPrivateKey(currentEpochCounter+1, myBeaconPrivKey)
+	if err != nil {
+		return false, fmt.Errorf("could not store beacon private key for next epoch: %w", err)
+	}
+	return true, nil
+}
+
 // TODO document error returns
 func (e *ReactorEngine) getDKGInfo(firstBlockID flow.Identifier) (*dkgInfo, error) {
 	currEpoch := e.State.AtBlockID(firstBlockID).Epochs().Current()
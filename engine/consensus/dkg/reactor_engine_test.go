@@ -266,6 +266,8 @@ type ReactorEngineSuite_CommittedPhase struct {
 	myLocalBeaconKey     crypto.PrivateKey // my locally computed beacon key
 	myGlobalBeaconPubKey crypto.PublicKey  // my public key, as dictated by global DKG
 	dkgEndState          flow.DKGEndState  // backend for DGKState.
+	currentGroupKey      crypto.PublicKey  // group key of the current epoch's DKG
+	nextGroupKey         crypto.PublicKey  // group key of the next epoch's DKG
 	firstBlock           *flow.Header      // first block of EpochCommitted phase
 	warnsLogged          int               // count # of warn-level logs
 
@@ -326,14 +328,23 @@ func (suite *ReactorEngineSuite_CommittedPhase) SetupTest() {
 		},
 	)
 
+	// by default the next epoch runs its own DKG
+	suite.currentGroupKey = unittest.RandomBeaconPriv().PublicKey()
+	suite.nextGroupKey = unittest.RandomBeaconPriv().PublicKey()
+
+	currentDKG := new(protocol.DKG)
+	currentDKG.On("GroupKey").Return(func() crypto.PublicKey { return suite.currentGroupKey })
+
 	currentEpoch := new(protocol.Epoch)
 	currentEpoch.On("Counter").Return(suite.epochCounter, nil)
+	currentEpoch.On("DKG").Return(currentDKG, nil)
 
 	nextDKG := new(protocol.DKG)
 	nextDKG.On("KeyShare", id).Return(
 		func(_ flow.Identifier) crypto.PublicKey { return suite.myGlobalBeaconPubKey },
 		func(_ flow.Identifier) error { return nil },
 	)
+	nextDKG.On("GroupKey").Return(func() crypto.PublicKey { return suite.nextGroupKey })
 
 	nextEpoch := new(protocol.Epoch)
 	nextEpoch.On("Counter").Return(suite.NextEpochCounter(), nil)
@@ -429,6 +440,26 @@ func (suite *ReactorEngineSuite_CommittedPhase) TestLocalDKGFailure() {
 	suite.Assert().Equal(flow.DKGEndStateDKGFailure, suite.dkgEndState)
 }
 
+// TestRecoveryEpochReusesDKG tests the path where the next epoch is a recovery epoch,
+// which reuses the DKG of the current epoch, after the DKG for the next epoch failed locally.
+// We should:
+// * store our current beacon key as the beacon key for the next epoch, overwriting the DKG end state
+func (suite *ReactorEngineSuite_CommittedPhase) TestRecoveryEpochReusesDKG() {
+
+	// the recovery epoch reuses the DKG of the current epoch, in which we have a key
+	suite.nextGroupKey = suite.currentGroupKey
+	suite.dkgEndState = flow.DKGEndStateDKGFailure
+	myCurrentBeaconKey := unittest.RandomBeaconPriv().PrivateKey
+	suite.myGlobalBeaconPubKey = myCurrentBeaconKey.PublicKey()
+	suite.dkgState.On("GetDKGEndState", suite.epochCounter).Return(flow.DKGEndStateSuccess, nil)
+	suite.dkgState.On("RetrieveMyBeaconPrivateKey", suite.epochCounter).Return(myCurrentBeaconKey, nil)
+	suite.dkgState.On("RecoverMyBeaconPrivateKey", suite.NextEpochCounter(), myCurrentBeaconKey).Return(nil).Once()
+
+	suite.engine.EpochCommittedPhaseStarted(suite.epochCounter, suite.firstBlock)
+	suite.Require().Equal(0, suite.warnsLogged)
+	suite.dkgState.AssertExpectations(suite.T())
+}
+
 // TestStartupInCommittedPhase_DKGSuccess tests that the dkg end state is correctly
 // set when starting in EpochCommitted phase and a successful DKG
 func (suite *ReactorEngineSuite_CommittedPhase) TestStartupInCommittedPhase_DKGSuccess() {
@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/engine/execution"
 	"github.com/onflow/flow-go/fvm/storage/snapshot"
 	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/trace"
@@ -56,11 +57,8 @@ type ExecutionState interface {
 }
 
 const (
-	KeyPartOwner = uint16(0)
-	// @deprecated - controller was used only by the very first
-	// version of cadence for access controll which was retired later on
-	// KeyPartController = uint16(1)
-	KeyPartKey = uint16(2)
+	KeyPartOwner = convert.KeyPartOwner
+	KeyPartKey   = convert.KeyPartKey
 )
 
 type state struct {
@@ -80,10 +78,7 @@ type state struct {
 }
 
 func RegisterIDToKey(reg flow.RegisterID) ledger.Key {
-	return ledger.NewKey([]ledger.KeyPart{
-		ledger.NewKeyPart(KeyPartOwner, []byte(reg.Owner)),
-		ledger.NewKeyPart(KeyPartKey, []byte(reg.Key)),
-	})
+	return convert.RegisterIDToLedgerKey(reg)
 }
 
 // NewExecutionState returns a new execution state access layer for the given ledger storage.
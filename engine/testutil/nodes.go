@@ -1013,7 +1013,8 @@ func VerificationNode(t testing.TB,
 			node.State,
 			node.Me,
 			chunkVerifier,
-			approvalStorage)
+			approvalStorage,
+			storage.NewChunkFaultReports(node.PublicDB))
 		require.Nil(t, err)
 	}
 
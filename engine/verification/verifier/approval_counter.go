@@ -0,0 +1,41 @@
+package verifier
+
+import (
+	"errors"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// StoredApprovalCounter is an ApprovalCounter backed by the result approvals stored by the node.
+//
+// A verification node only learns about the approvals of other verification nodes once they are
+// aggregated into a seal, at which point the block is sealed and its chunks are prioritised as
+// sealed. For chunks of unsealed blocks, the count is therefore limited to the approval of this
+// node, which it stored and pushed to the sealing process when it verified the chunk before. This
+// is the case for chunks handed to the verifier again, e.g. after a restart of the node.
+type StoredApprovalCounter struct {
+	approvals storage.ResultApprovals
+}
+
+var _ ApprovalCounter = (*StoredApprovalCounter)(nil)
+
+// NewStoredApprovalCounter creates an approval counter backed by the given result approvals.
+func NewStoredApprovalCounter(approvals storage.ResultApprovals) *StoredApprovalCounter {
+	return &StoredApprovalCounter{
+		approvals: approvals,
+	}
+}
+
+// ApprovalCount returns 1 if the node stored an approval for the chunk with the given index of the
+// given execution result, and 0 otherwise. Returns false if the approvals could not be read.
+func (c *StoredApprovalCounter) ApprovalCount(resultID flow.Identifier, chunkIndex uint64) (uint, bool) {
+	_, err := c.approvals.ByChunk(resultID, chunkIndex)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, true
+	}
+	if err != nil {
+		return 0, false
+	}
+	return 1, true
+}
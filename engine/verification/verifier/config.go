@@ -0,0 +1,42 @@
+package verifier
+
+import (
+	"runtime"
+)
+
+// Config is the configuration of the verifier engine.
+type Config struct {
+	// CPUBudget is the maximum number of chunks verified in parallel.
+	CPUBudget uint
+	// ApprovalCounter optionally provides the number of approvals the sealing process has
+	// gathered for chunks, which is used to prioritise chunks lacking approvals. May be nil,
+	// in which case chunks are prioritised by block height only.
+	ApprovalCounter ApprovalCounter
+}
+
+// DefaultConfig returns the default configuration of the verifier engine, which verifies
+// as many chunks in parallel as there are CPUs.
+func DefaultConfig() Config {
+	return Config{
+		CPUBudget: uint(runtime.NumCPU()),
+	}
+}
+
+type OptionFunc func(*Config)
+
+// WithCPUBudget sets the maximum number of chunks verified in parallel. A budget of zero
+// keeps the default of one chunk per CPU.
+func WithCPUBudget(budget uint) OptionFunc {
+	return func(cfg *Config) {
+		if budget > 0 {
+			cfg.CPUBudget = budget
+		}
+	}
+}
+
+// WithApprovalCounter sets the source of approval counts used to prioritise chunks.
+func WithApprovalCounter(counter ApprovalCounter) OptionFunc {
+	return func(cfg *Config) {
+		cfg.ApprovalCounter = counter
+	}
+}
@@ -3,6 +3,7 @@ package verifier
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/attribute"
@@ -43,6 +44,9 @@ type Engine struct {
 	chVerif        module.ChunkVerifier       // used to verify chunks
 	spockHasher    hash.Hasher                // used for generating spocks
 	approvals      storage.ResultApprovals    // used to store result approvals
+	faults         storage.ChunkFaultReports  // used to store chunk faults for offline investigation
+	scheduler      *Scheduler                 // used to prioritise chunks and bound verification parallelism
+	approvalCounts ApprovalCounter            // used to look up approvals gathered for chunks, may be nil
 }
 
 // New creates and returns a new instance of a verifier engine.
@@ -55,8 +59,15 @@ func New(
 	me module.Local,
 	chVerif module.ChunkVerifier,
 	approvals storage.ResultApprovals,
+	faults storage.ChunkFaultReports,
+	opts ...OptionFunc,
 ) (*Engine, error) {
 
+	config := DefaultConfig()
+	for _, apply := range opts {
+		apply(&config)
+	}
+
 	e := &Engine{
 		unit:           engine.NewUnit(),
 		log:            log.With().Str("engine", "verifier").Logger(),
@@ -68,6 +79,9 @@ func New(
 		approvalHasher: utils.NewResultApprovalHasher(),
 		spockHasher:    signature.NewBLSHasher(signature.SPOCKTag),
 		approvals:      approvals,
+		faults:         faults,
+		scheduler:      NewScheduler(config.CPUBudget, metrics),
+		approvalCounts: config.ApprovalCounter,
 	}
 
 	var err error
@@ -198,6 +212,8 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 
 	// if any fault found with the chunk
 	if chFault != nil {
+		e.storeFaultReport(vc, chFault)
+
 		switch chFault.(type) {
 		case *chmodels.CFMissingRegisterTouch:
 			e.log.Warn().
@@ -283,6 +299,47 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 	return nil
 }
 
+// storeFaultReport persists the chunk fault along with all inputs of the chunk verification, so
+// that the verification can be replayed offline. Storing the report is best-effort, failures
+// are logged and do not affect the verification outcome.
+func (e *Engine) storeFaultReport(vc *verification.VerifiableChunkData, chFault chmodels.ChunkFault) {
+	log := e.log.With().
+		Hex("result_id", logging.ID(chFault.ExecutionResultID())).
+		Uint64("chunk_index", chFault.ChunkIndex()).
+		Logger()
+
+	report := &chmodels.FaultReport{
+		ReportedAt:        time.Now().UTC(),
+		VerifierID:        e.me.NodeID(),
+		ChainID:           vc.Header.ChainID,
+		IsSystemChunk:     vc.IsSystemChunk,
+		Chunk:             vc.Chunk,
+		Header:            vc.Header,
+		Result:            vc.Result,
+		ChunkDataPack:     vc.ChunkDataPack,
+		EndState:          vc.EndState,
+		TransactionOffset: vc.TransactionOffset,
+	}
+	report.SetOutcome(chFault)
+
+	if vc.Snapshot != nil {
+		randomSource, err := vc.Snapshot.RandomSource()
+		if err != nil {
+			// the report is still useful without the source of randomness, as long as the chunk
+			// does not consume randomness
+			log.Warn().Err(err).Msg("could not retrieve source of randomness for chunk fault report")
+		}
+		report.RandomSource = randomSource
+	}
+
+	err := e.faults.Store(report)
+	if err != nil {
+		log.Error().Err(err).Msg("could not store chunk fault report")
+		return
+	}
+	log.Info().Str("chunk_fault_type", report.FaultType).Msg("chunk fault report stored")
+}
+
 // GenerateResultApproval generates result approval for specific chunk of an execution receipt.
 func GenerateResultApproval(
 	me module.Local,
@@ -348,8 +405,17 @@ func (e *Engine) verifiableChunkHandler(originID flow.Identifier, ch *verificati
 
 	log.Info().Msg("verifiable chunk received")
 
-	// starts verification of chunk
-	err := e.verify(ctx, originID, ch)
+	// waits for a verification slot, chunks most urgently needed for sealing go first
+	priority := e.prioritize(ch)
+	var err error
+	schedErr := e.scheduler.Run(e.unit.Ctx(), priority, func() {
+		// starts verification of chunk
+		err = e.verify(ctx, originID, ch)
+	})
+	if schedErr != nil {
+		log.Info().Err(schedErr).Msg("chunk verification aborted while waiting for a verification slot")
+		return nil
+	}
 
 	if err != nil {
 		log.Info().Err(err).Msg("could not verify chunk")
@@ -359,6 +425,33 @@ func (e *Engine) verifiableChunkHandler(originID flow.Identifier, ch *verificati
 	return nil
 }
 
+// prioritize determines how urgently sealing needs the verification of the chunk, based on the
+// latest sealed block and, if available, the approvals already gathered for the chunk.
+func (e *Engine) prioritize(ch *verification.VerifiableChunkData) ChunkPriority {
+	priority := ChunkPriority{
+		Class:  PriorityUnsealed,
+		Height: ch.Header.Height,
+	}
+
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		// prioritising is best-effort, the chunk is verified regardless
+		e.log.Warn().Err(err).Msg("could not retrieve latest sealed block for prioritising chunk")
+	} else if ch.Header.Height <= sealed.Height {
+		priority.Class = PrioritySealed
+		return priority
+	}
+
+	if e.approvalCounts != nil {
+		approvals, ok := e.approvalCounts.ApprovalCount(ch.Result.ID(), ch.Chunk.Index)
+		if ok {
+			priority.Approvals = approvals
+		}
+	}
+
+	return priority
+}
+
 func (e *Engine) approvalRequestHandler(originID flow.Identifier, req *messages.ApprovalRequest) error {
 
 	log := e.log.With().
@@ -3,13 +3,16 @@ package verifier_test
 import (
 	"crypto/rand"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
 	testifymock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/atomic"
 
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/crypto/hash"
@@ -21,11 +24,13 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/verification"
 	realModule "github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
 	mockmodule "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
 	mockstorage "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -44,6 +49,7 @@ type VerifierEngineTestSuite struct {
 	pullCon   *mocknetwork.Conduit
 	metrics   *mockmodule.VerificationMetrics // mocks performance monitoring metrics
 	approvals *mockstorage.ResultApprovals
+	faults    *mockstorage.ChunkFaultReports
 }
 
 func TestVerifierEngine(t *testing.T) {
@@ -61,6 +67,7 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 	suite.metrics = &mockmodule.VerificationMetrics{}
 	suite.chain = flow.Testnet.Chain()
 	suite.approvals = &mockstorage.ResultApprovals{}
+	suite.faults = &mockstorage.ChunkFaultReports{}
 
 	suite.approvals.On("Store", mock.Anything).Return(nil)
 	suite.approvals.On("Index", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -74,6 +81,13 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 		Once()
 
 	suite.state.On("Final").Return(suite.ss)
+	suite.state.On("Sealed").Return(suite.ss)
+	suite.ss.On("Head").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(0)), nil)
+
+	// mocks metrics of the verification scheduler
+	suite.metrics.On("SetVerifierQueueDepth", testifymock.Anything, testifymock.Anything).Return()
+	suite.metrics.On("SetVerifierChunksInProgress", testifymock.Anything).Return()
+	suite.metrics.On("OnChunkVerifiedAtVerifier", testifymock.Anything, testifymock.Anything).Return()
 
 	// Mocks the signature oracle of the engine
 	//
@@ -106,7 +120,8 @@ func (suite *VerifierEngineTestSuite) TestNewEngine() *verifier.Engine {
 		suite.state,
 		suite.me,
 		ChunkVerifierMock{},
-		suite.approvals)
+		suite.approvals,
+		suite.faults)
 	require.Nil(suite.T(), err)
 
 	suite.net.AssertExpectations(suite.T())
@@ -194,6 +209,17 @@ func (suite *VerifierEngineTestSuite) TestVerifyUnhappyPaths() {
 	// emission of result approval
 	suite.metrics.On("OnResultApprovalDispatchedInNetworkByVerifier").Return()
 
+	// every chunk fault should be persisted
+	reportedFaults := make(map[uint64]string)
+	suite.faults.
+		On("Store", testifymock.Anything).
+		Return(nil).
+		Run(func(args testifymock.Arguments) {
+			report, ok := args[0].(*chmodel.FaultReport)
+			suite.Require().True(ok)
+			reportedFaults[report.ChunkIndex()] = report.FaultType
+		})
+
 	var tests = []struct {
 		vc          *verification.VerifiableChunkData
 		expectedErr error
@@ -206,6 +232,12 @@ func (suite *VerifierEngineTestSuite) TestVerifyUnhappyPaths() {
 		err := eng.ProcessLocal(test.vc)
 		suite.Assert().NoError(err)
 	}
+
+	suite.Assert().Equal(map[uint64]string{
+		1: chmodel.FaultTypeMissingRegisterTouch,
+		2: chmodel.FaultTypeInvalidVerifiableChunk,
+		3: chmodel.FaultTypeNonMatchingFinalState,
+	}, reportedFaults)
 }
 
 type ChunkVerifierMock struct {
@@ -248,3 +280,100 @@ func (v ChunkVerifierMock) Verify(vc *verification.VerifiableChunkData) ([]byte,
 	}
 
 }
+
+// queueDepthMetrics records the number of waiting chunks of unsealed blocks reported by the verifier engine.
+type queueDepthMetrics struct {
+	*metrics.NoopCollector
+	unsealed *atomic.Int64
+}
+
+func (m queueDepthMetrics) SetVerifierQueueDepth(class string, depth int) {
+	if class == verifier.PriorityUnsealed.String() {
+		m.unsealed.Store(int64(depth))
+	}
+}
+
+// TestVerifierEngine_ApprovalPriority verifies that, among waiting chunks of unsealed blocks, the
+// chunk which the node did not approve yet is verified before the chunk it already approved, even
+// though the block of the latter is lower.
+func TestVerifierEngine_ApprovalPriority(t *testing.T) {
+	myID := unittest.IdentifierFixture()
+	me := mockmodule.NewLocal(t)
+	me.On("NodeID").Return(myID)
+
+	net := mocknetwork.NewNetwork(t)
+	net.On("Register", channels.PushApprovals, testifymock.Anything).Return(mocknetwork.NewConduit(t), nil)
+	net.On("Register", channels.ProvideApprovalsByChunk, testifymock.Anything).Return(mocknetwork.NewConduit(t), nil)
+
+	sealed := protocol.NewSnapshot(t)
+	sealed.On("Head").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(1)), nil)
+	state := protocol.NewState(t)
+	state.On("Sealed").Return(sealed)
+
+	chunk := func(height uint64) *verification.VerifiableChunkData {
+		result := unittest.ExecutionResultFixture()
+		return &verification.VerifiableChunkData{
+			Header: unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height)),
+			Result: result,
+			Chunk:  result.Chunks[0],
+		}
+	}
+	running := chunk(5)
+	approved := chunk(10)
+	unapproved := chunk(20)
+
+	// the node already approved the chunk of the lower block
+	approvals := mockstorage.NewResultApprovals(t)
+	approvals.On("ByChunk", approved.Result.ID(), approved.Chunk.Index).Return(unittest.ResultApprovalFixture(), nil)
+	approvals.On("ByChunk", testifymock.Anything, testifymock.Anything).Return(nil, storage.ErrNotFound)
+
+	// the chunk verifier blocks the first chunk until released, and records the order of verified chunks
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var verified []uint64
+	chVerifier := mockmodule.NewChunkVerifier(t)
+	chVerifier.On("Verify", testifymock.Anything).
+		Run(func(args testifymock.Arguments) {
+			vc := args.Get(0).(*verification.VerifiableChunkData)
+			if vc == running {
+				close(started)
+				<-release
+			}
+			verified = append(verified, vc.Header.Height)
+		}).
+		Return(nil, nil, errors.New("not verified"))
+
+	collector := queueDepthMetrics{NoopCollector: metrics.NewNoopCollector(), unsealed: atomic.NewInt64(0)}
+	eng, err := verifier.New(
+		unittest.Logger(),
+		collector,
+		trace.NewNoopTracer(),
+		net,
+		state,
+		me,
+		chVerifier,
+		approvals,
+		mockstorage.NewChunkFaultReports(t),
+		verifier.WithCPUBudget(1),
+		verifier.WithApprovalCounter(verifier.NewStoredApprovalCounter(approvals)))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	process := func(vc *verification.VerifiableChunkData) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, eng.ProcessLocal(vc))
+		}()
+	}
+
+	process(running)
+	unittest.RequireCloseBefore(t, started, time.Second, "first chunk was not verified")
+	process(approved)
+	process(unapproved)
+	require.Eventually(t, func() bool { return collector.unsealed.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	close(release)
+	unittest.RequireReturnsBefore(t, wg.Wait, time.Second, "chunks were not verified")
+	require.Equal(t, []uint64{5, 20, 10}, verified)
+}
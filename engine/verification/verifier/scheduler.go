@@ -0,0 +1,233 @@
+package verifier
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// PriorityClass is the coarse urgency of verifying a chunk for sealing.
+type PriorityClass int
+
+const (
+	// PriorityUnsealed is the class of chunks of unsealed blocks. These chunks are verified first.
+	PriorityUnsealed PriorityClass = iota
+	// PrioritySealed is the class of chunks of blocks which are already sealed. Verifying these
+	// chunks does not contribute to sealing anymore, so they are verified last.
+	PrioritySealed
+)
+
+// priorityClasses lists all priority classes, in order of decreasing urgency.
+var priorityClasses = []PriorityClass{PriorityUnsealed, PrioritySealed}
+
+// String returns the string representation of the priority class, as used in metrics.
+func (c PriorityClass) String() string {
+	switch c {
+	case PriorityUnsealed:
+		return "unsealed"
+	case PrioritySealed:
+		return "sealed"
+	default:
+		return "unknown"
+	}
+}
+
+// ChunkPriority describes how urgently sealing needs the verification of a chunk.
+type ChunkPriority struct {
+	Class     PriorityClass // coarse urgency class
+	Approvals uint          // number of approvals the sealing process has gathered for the chunk (zero if unknown)
+	Height    uint64        // height of the block the chunk belongs to; lower heights are sealed first
+}
+
+// higherThan returns true if a chunk with priority p should be verified before a chunk with
+// priority other. Chunks are ordered by class, then by the number of approvals gathered for
+// them, so that chunks lacking approvals go first, and then by block height, as sealing
+// proceeds in height order. Without known approval counts, chunks are ordered by height.
+func (p ChunkPriority) higherThan(other ChunkPriority) bool {
+	if p.Class != other.Class {
+		return p.Class < other.Class
+	}
+	if p.Approvals != other.Approvals {
+		return p.Approvals < other.Approvals
+	}
+	return p.Height < other.Height
+}
+
+// ApprovalCounter provides the number of result approvals which the sealing process has
+// gathered for a chunk.
+type ApprovalCounter interface {
+	// ApprovalCount returns the number of result approvals known for the chunk with the given
+	// index of the given execution result. Returns false if the count is not known.
+	ApprovalCount(resultID flow.Identifier, chunkIndex uint64) (uint, bool)
+}
+
+// waiter is a chunk verification waiting for a slot of the scheduler.
+type waiter struct {
+	priority ChunkPriority
+	seq      uint64        // arrival order, used to break ties in priority
+	granted  chan struct{} // closed once the waiter obtained a slot
+	index    int           // index in the queue, maintained by heap.Interface
+}
+
+// waiterQueue implements heap.Interface, ordering waiters by decreasing priority.
+type waiterQueue []*waiter
+
+func (q waiterQueue) Len() int { return len(q) }
+
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].priority == q[j].priority {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].priority.higherThan(q[j].priority)
+}
+
+func (q waiterQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waiterQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waiterQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
+
+// Scheduler bounds the number of chunks verified in parallel to a CPU budget. When more chunks
+// are submitted than the budget allows, they wait for a slot, and slots are granted in order of
+// chunk priority rather than arrival, so that chunks which sealing needs most urgently do not
+// wait behind less urgent ones.
+//
+// The scheduler does not run workers of its own: each chunk is verified on the goroutine that
+// submitted it, which blocks until the verification is done. This preserves the contract of the
+// verifier engine towards the fetcher engine, which relies on chunks being verified once
+// ProcessLocal returns.
+//
+// Scheduler is safe for concurrent use.
+type Scheduler struct {
+	mu      sync.Mutex
+	metrics module.VerificationMetrics
+	budget  uint                  // maximum number of chunks verified in parallel
+	running uint                  // number of chunks currently being verified
+	queue   waiterQueue           // chunks waiting for a slot
+	depths  map[PriorityClass]int // number of waiting chunks per priority class
+	nextSeq uint64
+}
+
+// NewScheduler creates a scheduler verifying at most budget chunks in parallel. A budget of
+// zero is treated as one.
+func NewScheduler(budget uint, metrics module.VerificationMetrics) *Scheduler {
+	if budget == 0 {
+		budget = 1
+	}
+	return &Scheduler{
+		metrics: metrics,
+		budget:  budget,
+		depths:  make(map[PriorityClass]int),
+	}
+}
+
+// Run waits for a verification slot, and then executes verify on the calling goroutine.
+// Waiting chunks are granted slots in order of priority.
+// Returns the context error if the context is cancelled before a slot is granted, in which
+// case verify is not executed.
+func (s *Scheduler) Run(ctx context.Context, priority ChunkPriority, verify func()) error {
+	queued := time.Now()
+	err := s.acquire(ctx, priority)
+	if err != nil {
+		return err
+	}
+	started := time.Now()
+	defer func() {
+		s.release()
+		s.metrics.OnChunkVerifiedAtVerifier(started.Sub(queued), time.Since(started))
+	}()
+
+	verify()
+	return nil
+}
+
+// acquire blocks until a verification slot is granted to a chunk with the given priority.
+func (s *Scheduler) acquire(ctx context.Context, priority ChunkPriority) error {
+	s.mu.Lock()
+	if s.running < s.budget && len(s.queue) == 0 {
+		s.running++
+		s.reportLocked()
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := &waiter{
+		priority: priority,
+		seq:      s.nextSeq,
+		granted:  make(chan struct{}),
+	}
+	s.nextSeq++
+	heap.Push(&s.queue, w)
+	s.depths[priority.Class]++
+	s.reportLocked()
+	s.mu.Unlock()
+
+	select {
+	case <-w.granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w.index < 0 {
+		// the slot was granted concurrently with the cancellation, hand it on
+		s.running--
+		s.grantLocked()
+	} else {
+		heap.Remove(&s.queue, w.index)
+		s.depths[priority.Class]--
+	}
+	s.reportLocked()
+	return ctx.Err()
+}
+
+// release frees a verification slot and grants it to the most urgent waiting chunk, if any.
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running--
+	s.grantLocked()
+	s.reportLocked()
+}
+
+// grantLocked grants free slots to the most urgent waiting chunks.
+// Must be called with the lock held.
+func (s *Scheduler) grantLocked() {
+	for s.running < s.budget && len(s.queue) > 0 {
+		w := heap.Pop(&s.queue).(*waiter)
+		s.depths[w.priority.Class]--
+		s.running++
+		close(w.granted)
+	}
+}
+
+// reportLocked reports the queue depths and the number of chunks being verified.
+// Must be called with the lock held.
+func (s *Scheduler) reportLocked() {
+	for _, class := range priorityClasses {
+		s.metrics.SetVerifierQueueDepth(class.String(), s.depths[class])
+	}
+	s.metrics.SetVerifierChunksInProgress(int(s.running))
+}
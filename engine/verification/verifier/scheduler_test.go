@@ -0,0 +1,206 @@
+package verifier
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	"github.com/onflow/flow-go/module/metrics"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// queued returns the number of chunks waiting for a slot of the scheduler.
+func (s *Scheduler) queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// TestScheduler_PriorityOrder verifies that once the budget is exhausted, waiting chunks are
+// verified in order of priority rather than arrival.
+func TestScheduler_PriorityOrder(t *testing.T) {
+	scheduler := NewScheduler(1, metrics.NewNoopCollector())
+
+	// occupy the only slot until all other chunks are queued
+	blocked := make(chan struct{})
+	running := make(chan struct{})
+	go func() {
+		_ = scheduler.Run(context.Background(), ChunkPriority{}, func() {
+			close(running)
+			<-blocked
+		})
+	}()
+	unittest.RequireCloseBefore(t, running, time.Second, "first chunk should run immediately")
+
+	priorities := []ChunkPriority{
+		{Class: PrioritySealed, Height: 1},
+		{Class: PriorityUnsealed, Height: 12},
+		{Class: PriorityUnsealed, Height: 11},
+		{Class: PriorityUnsealed, Height: 10, Approvals: 1},
+		{Class: PriorityUnsealed, Height: 10},
+	}
+
+	var mu sync.Mutex
+	var order []ChunkPriority
+	var wg sync.WaitGroup
+	for i, priority := range priorities {
+		wg.Add(1)
+		go func(priority ChunkPriority) {
+			defer wg.Done()
+			err := scheduler.Run(context.Background(), priority, func() {
+				mu.Lock()
+				order = append(order, priority)
+				mu.Unlock()
+			})
+			assert.NoError(t, err)
+		}(priority)
+		// wait for the chunk to be queued, so that arrival order is deterministic
+		expected := i + 1
+		require.Eventually(t, func() bool { return scheduler.queued() == expected }, time.Second, time.Millisecond)
+	}
+
+	close(blocked)
+	unittest.RequireReturnsBefore(t, wg.Wait, time.Second, "all chunks should be verified")
+
+	assert.Equal(t, []ChunkPriority{
+		{Class: PriorityUnsealed, Height: 10},
+		{Class: PriorityUnsealed, Height: 11},
+		{Class: PriorityUnsealed, Height: 12},
+		{Class: PriorityUnsealed, Height: 10, Approvals: 1},
+		{Class: PrioritySealed, Height: 1},
+	}, order)
+}
+
+// approvalCounts is an ApprovalCounter backed by a map of known approval counts per chunk.
+type approvalCounts map[flow.Identifier]map[uint64]uint
+
+func (c approvalCounts) ApprovalCount(resultID flow.Identifier, chunkIndex uint64) (uint, bool) {
+	count, ok := c[resultID][chunkIndex]
+	return count, ok
+}
+
+// TestEngine_Prioritize verifies that chunks of unsealed blocks are prioritised by the approvals
+// gathered for them if known, and by height otherwise.
+func TestEngine_Prioritize(t *testing.T) {
+	sealed := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(100))
+	snapshot := protocolmock.NewSnapshot(t)
+	snapshot.On("Head").Return(sealed, nil)
+	state := protocolmock.NewState(t)
+	state.On("Sealed").Return(snapshot)
+
+	chunk := func(height uint64) *verification.VerifiableChunkData {
+		result := unittest.ExecutionResultFixture()
+		return &verification.VerifiableChunkData{
+			Header: unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height)),
+			Result: result,
+			Chunk:  result.Chunks[0],
+		}
+	}
+	approved := chunk(110)
+	unknown := chunk(120)
+	old := chunk(90)
+
+	t.Run("without approval counts", func(t *testing.T) {
+		e := &Engine{log: unittest.Logger(), state: state}
+		assert.Equal(t, ChunkPriority{Class: PriorityUnsealed, Height: 110}, e.prioritize(approved))
+		assert.Equal(t, ChunkPriority{Class: PriorityUnsealed, Height: 120}, e.prioritize(unknown))
+		assert.Equal(t, ChunkPriority{Class: PrioritySealed, Height: 90}, e.prioritize(old))
+		assert.True(t, e.prioritize(approved).higherThan(e.prioritize(unknown)))
+	})
+
+	t.Run("with approval counts", func(t *testing.T) {
+		counts := approvalCounts{
+			approved.Result.ID(): {approved.Chunk.Index: 2},
+			old.Result.ID():      {old.Chunk.Index: 0},
+		}
+		e := &Engine{log: unittest.Logger(), state: state, approvalCounts: counts}
+		assert.Equal(t, ChunkPriority{Class: PriorityUnsealed, Height: 110, Approvals: 2}, e.prioritize(approved))
+		assert.Equal(t, ChunkPriority{Class: PriorityUnsealed, Height: 120}, e.prioritize(unknown))
+		assert.Equal(t, ChunkPriority{Class: PrioritySealed, Height: 90}, e.prioritize(old))
+		// the chunk lacking approvals goes first, even though its block is higher
+		assert.True(t, e.prioritize(unknown).higherThan(e.prioritize(approved)))
+		assert.True(t, e.prioritize(approved).higherThan(e.prioritize(old)))
+	})
+}
+
+// TestScheduler_Budget verifies that no more chunks than the budget are verified in parallel.
+func TestScheduler_Budget(t *testing.T) {
+	const budget = 3
+	scheduler := NewScheduler(budget, metrics.NewNoopCollector())
+
+	var mu sync.Mutex
+	inProgress, maxInProgress := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(height uint64) {
+			defer wg.Done()
+			err := scheduler.Run(context.Background(), ChunkPriority{Height: height}, func() {
+				mu.Lock()
+				inProgress++
+				if inProgress > maxInProgress {
+					maxInProgress = inProgress
+				}
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				inProgress--
+				mu.Unlock()
+			})
+			assert.NoError(t, err)
+		}(uint64(i))
+	}
+	unittest.RequireReturnsBefore(t, wg.Wait, 5*time.Second, "all chunks should be verified")
+	assert.LessOrEqual(t, maxInProgress, budget)
+}
+
+// TestScheduler_Cancel verifies that a chunk waiting for a slot is dropped when its context is
+// cancelled, without consuming the slot.
+func TestScheduler_Cancel(t *testing.T) {
+	scheduler := NewScheduler(1, metrics.NewNoopCollector())
+
+	blocked := make(chan struct{})
+	running := make(chan struct{})
+	go func() {
+		_ = scheduler.Run(context.Background(), ChunkPriority{}, func() {
+			close(running)
+			<-blocked
+		})
+	}()
+	unittest.RequireCloseBefore(t, running, time.Second, "first chunk should run immediately")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- scheduler.Run(ctx, ChunkPriority{}, func() {
+			t.Error("cancelled chunk should not be verified")
+		})
+	}()
+	require.Eventually(t, func() bool { return scheduler.queued() == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("cancelled chunk should stop waiting")
+	}
+	assert.Equal(t, 0, scheduler.queued())
+
+	// the slot is still usable once released
+	close(blocked)
+	verified := false
+	unittest.RequireReturnsBefore(t, func() {
+		require.NoError(t, scheduler.Run(context.Background(), ChunkPriority{}, func() { verified = true }))
+	}, time.Second, "slot should be released")
+	assert.True(t, verified)
+}
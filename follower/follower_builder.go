@@ -389,6 +389,8 @@ func (builder *FollowerServiceBuilder) initNetwork(nodeID module.Local,
 			AlspMetrics:             builder.Metrics.Network,
 			HeroCacheMetricsFactory: builder.HeroCacheMetricsFactory(),
 			NetworkType:             network.PublicNetwork,
+			SpamRecordStore:         builder.AlspSpamRecordStore(network.PublicNetwork),
+			PersistenceInterval:     builder.FlowConfig.NetworkConfig.AlspConfig.SpamRecordPersistenceInterval,
 		},
 	})
 	if err != nil {
@@ -766,5 +768,10 @@ func (builder *FollowerServiceBuilder) initMiddleware(nodeID flow.Identifier,
 		middleware.WithMessageValidators(validators...),
 	)
 	builder.Middleware = mw
+
+	// nodes disallow-listed by the operator before a restart are disallow-listed again.
+	if disallowListWrapper, ok := builder.IdentityProvider.(*cache.NodeDisallowListingWrapper); ok {
+		disallowListWrapper.NotifyDisallowList()
+	}
 	return builder.Middleware
 }
@@ -35,6 +35,7 @@ const (
 
 	EventNameEpochSetup    = "EpochSetup"
 	EventNameEpochCommit   = "EpochCommit"
+	EventNameEpochRecover  = "EpochRecover"
 	EventNameVersionBeacon = "VersionBeacon"
 
 	//  Unqualified names of service event contract functions (not including address prefix or contract name)
@@ -85,6 +86,7 @@ type SystemContracts struct {
 type ServiceEvents struct {
 	EpochSetup    ServiceEvent
 	EpochCommit   ServiceEvent
+	EpochRecover  ServiceEvent
 	VersionBeacon ServiceEvent
 }
 
@@ -93,6 +95,7 @@ func (se ServiceEvents) All() []ServiceEvent {
 	return []ServiceEvent{
 		se.EpochSetup,
 		se.EpochCommit,
+		se.EpochRecover,
 		se.VersionBeacon,
 	}
 }
@@ -144,6 +147,11 @@ func ServiceEventsForChain(chainID flow.ChainID) (*ServiceEvents, error) {
 			ContractName: ContractNameEpoch,
 			Name:         EventNameEpochCommit,
 		},
+		EpochRecover: ServiceEvent{
+			Address:      addresses[ContractNameEpoch],
+			ContractName: ContractNameEpoch,
+			Name:         EventNameEpochRecover,
+		},
 		VersionBeacon: ServiceEvent{
 			Address:      addresses[ContractNameNodeVersionBeacon],
 			ContractName: ContractNameNodeVersionBeacon,
@@ -125,5 +125,6 @@ func checkServiceEvents(t *testing.T, chainID flow.ChainID) {
 	// entries must match internal mapping
 	assert.Equal(t, epochContractAddr, events.EpochSetup.Address)
 	assert.Equal(t, epochContractAddr, events.EpochCommit.Address)
+	assert.Equal(t, epochContractAddr, events.EpochRecover.Address)
 	assert.Equal(t, versionContractAddr, events.VersionBeacon.Address)
 }
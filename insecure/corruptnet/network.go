@@ -154,6 +154,13 @@ func (n *Network) RegisterPingService(pingProtocolID protocol.ID, pingInfoProvid
 	return n.flowNetwork.RegisterPingService(pingProtocolID, pingInfoProvider)
 }
 
+// RegisterRequestService directly invokes the corresponding method on the underlying Flow network instance. It does not
+// perform any corruption and passes everything through as it is.
+// Returns a non nil error if fails to register with original Flow network.
+func (n *Network) RegisterRequestService(channel channels.Channel, handler flownet.RequestHandler, opts ...flownet.RequestServiceOption) (flownet.RequestService, error) {
+	return n.flowNetwork.RegisterRequestService(channel, handler, opts...)
+}
+
 // ProcessAttackerMessage is the central place for the corrupt network to process messages from an attacker.
 // The messages coming from an attacker can be destined to this corrupt node (on behalf of another node) (ingress message) or to another node (on behalf of this corrupt node) (egress message).
 // This is a Client Streaming gRPC end-point that allows a registered attack orchestrator to dictate messages to this corrupt
@@ -0,0 +1,23 @@
+// Package convert converts between the register model of flow and the keys of the ledger.
+package convert
+
+import (
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	KeyPartOwner = uint16(0)
+	// @deprecated - controller was used only by the very first
+	// version of cadence for access controll which was retired later on
+	// KeyPartController = uint16(1)
+	KeyPartKey = uint16(2)
+)
+
+// RegisterIDToLedgerKey converts a register ID to the ledger key the register is stored under.
+func RegisterIDToLedgerKey(registerID flow.RegisterID) ledger.Key {
+	return ledger.NewKey([]ledger.KeyPart{
+		ledger.NewKeyPart(KeyPartOwner, []byte(registerID.Owner)),
+		ledger.NewKeyPart(KeyPartKey, []byte(registerID.Key)),
+	})
+}
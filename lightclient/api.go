@@ -0,0 +1,27 @@
+// Package lightclient implements a light client, which follows the chain from a trusted protocol
+// snapshot using only block headers and QCs, and verifies register values with ledger proofs.
+package lightclient
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// API is the subset of the Access API which the light client follows the chain with. None of the
+// data returned by the API is trusted, the light client verifies all of it against the trusted
+// snapshot it was started from. API is implemented by access.API, so that the light client can run
+// against the backend of a local access node, a client of a remote access node or a stub.
+type API interface {
+	// GetLatestBlockHeader returns the latest finalized or sealed block header.
+	GetLatestBlockHeader(ctx context.Context, isSealed bool) (*flow.Header, flow.BlockStatus, error)
+
+	// GetBlockByHeight returns the finalized block, including its full payload, at the given height.
+	GetBlockByHeight(ctx context.Context, height uint64) (*flow.Block, flow.BlockStatus, error)
+
+	// GetExecutionResultForBlockID returns the sealed execution result for the given block.
+	GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error)
+}
+
+var _ API = (access.API)(nil)
@@ -0,0 +1,451 @@
+package lightclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// Client is a trust-minimised light client of the Flow chain. Starting from a trusted protocol snapshot,
+// it follows the chain of finalized blocks served by an untrusted API, using only block headers, the
+// QCs certifying them and the seals included in their payloads:
+//   - A block is accepted only if it is certified by a QC of the consensus committee of the epoch
+//     of the block's view, and considered finalized by the 2-chain finalization rule of HotStuff.
+//   - The state commitments of blocks sealed by finalized blocks are retained, so that register values
+//     can be verified against the execution state using ledger proofs.
+//   - Epoch transitions are followed through the EpochSetup and EpochCommit service events of sealed
+//     execution results, which define the consensus committee of the next epoch.
+//
+// Client is safe for concurrent use.
+type Client struct {
+	log          zerolog.Logger
+	api          API
+	config       Config
+	chainID      flow.ChainID
+	newValidator validatorFactory
+
+	syncLock sync.Mutex // serializes syncing with the API
+
+	mu        sync.RWMutex
+	finalized *flow.Header     // latest block known to be finalized
+	pending   []*flow.Block    // blocks descending from the finalized block, which are not yet finalized
+	epochs    []*epoch         // committed epochs, ordered by counter
+	setup     *flow.EpochSetup // sealed setup event of the next epoch, until its commit event is sealed
+	// sealed state commitments by block ID, and the order in which they were sealed
+	commits     map[flow.Identifier]flow.StateCommitment
+	commitOrder []flow.Identifier
+	latestSeal  *flow.Seal
+}
+
+// NewClient creates a light client starting from the given trusted snapshot. The snapshot must be
+// trusted by the caller, for instance because it was obtained from a trusted node or is the root
+// snapshot of the network.
+// No errors are expected during normal operation.
+func NewClient(log zerolog.Logger, api API, snapshot protocol.Snapshot, config Config) (*Client, error) {
+	return newClient(log, api, snapshot, config, newConsensusValidator)
+}
+
+func newClient(log zerolog.Logger, api API, snapshot protocol.Snapshot, config Config, newValidator validatorFactory) (*Client, error) {
+	if config.SealedCommitsLimit == 0 {
+		return nil, fmt.Errorf("sealed commits limit must be positive")
+	}
+
+	head, err := snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot head: %w", err)
+	}
+	result, seal, err := snapshot.SealedResult()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed result: %w", err)
+	}
+	commit, err := result.FinalStateCommitment()
+	if err != nil {
+		return nil, fmt.Errorf("could not get final state commitment of sealed result: %w", err)
+	}
+	if commit != seal.FinalState {
+		return nil, fmt.Errorf("inconsistent state commitment between sealed result (%x) and seal (%x)", commit, seal.FinalState)
+	}
+
+	c := &Client{
+		log:          log.With().Str("component", "light_client").Logger(),
+		api:          api,
+		config:       config,
+		chainID:      head.ChainID,
+		newValidator: newValidator,
+		finalized:    head,
+		commits:      make(map[flow.Identifier]flow.StateCommitment),
+		latestSeal:   seal,
+	}
+	c.addCommit(seal.BlockID, seal.FinalState)
+
+	current, err := newEpoch(snapshot.Epochs().Current(), newValidator)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize current epoch: %w", err)
+	}
+	c.epochs = append(c.epochs, current)
+
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch phase: %w", err)
+	}
+	switch phase {
+	case flow.EpochPhaseSetup:
+		c.setup, err = setupFromEpoch(snapshot.Epochs().Next())
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize next epoch setup: %w", err)
+		}
+	case flow.EpochPhaseCommitted:
+		next, err := newEpoch(snapshot.Epochs().Next(), newValidator)
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize next epoch: %w", err)
+		}
+		c.epochs = append(c.epochs, next)
+	}
+
+	return c, nil
+}
+
+// Sync follows the chain up to the latest finalized block reported by the API.
+// Expected errors during normal operation:
+//   - InvalidBlockError if the API served a block which failed verification
+//   - ErrUnknownEpoch if a block is certified in an epoch whose transition the light client has not followed
+func (c *Client) Sync(ctx context.Context) error {
+	header, _, err := c.api.GetLatestBlockHeader(ctx, false)
+	if err != nil {
+		return fmt.Errorf("could not get latest finalized block header: %w", err)
+	}
+	return c.SyncTo(ctx, header.Height)
+}
+
+// SyncTo requests and verifies all blocks up to the given height. Since a block is finalized only once
+// it is extended by a 2-chain, the blocks at the highest heights might remain pending until the next sync.
+// Expected errors during normal operation:
+//   - InvalidBlockError if the API served a block which failed verification
+//   - ErrUnknownEpoch if a block is certified in an epoch whose transition the light client has not followed
+func (c *Client) SyncTo(ctx context.Context, height uint64) error {
+	c.syncLock.Lock()
+	defer c.syncLock.Unlock()
+
+	for next := c.nextHeight(); next <= height; next++ {
+		block, _, err := c.api.GetBlockByHeight(ctx, next)
+		if err != nil {
+			return fmt.Errorf("could not get block at height %d: %w", next, err)
+		}
+		err = c.extend(ctx, block, next)
+		if err != nil {
+			// drop pending blocks, as any of them might be the one the invalid block was built upon
+			c.mu.Lock()
+			c.pending = nil
+			c.mu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// nextHeight returns the height of the next block to request.
+func (c *Client) nextHeight() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tip().Height + 1
+}
+
+// tip returns the header of the highest block known to the light client.
+// Caller must hold the lock.
+func (c *Client) tip() *flow.Header {
+	if len(c.pending) > 0 {
+		return c.pending[len(c.pending)-1].Header
+	}
+	return c.finalized
+}
+
+// extend verifies that the given block extends the highest known block, and finalizes all blocks
+// satisfying the finalization rule afterwards.
+// Expected errors during normal operation:
+//   - InvalidBlockError if the block failed verification
+//   - ErrUnknownEpoch if the block is certified in an unknown epoch
+func (c *Client) extend(ctx context.Context, block *flow.Block, height uint64) error {
+	c.mu.RLock()
+	parent := c.tip()
+	c.mu.RUnlock()
+
+	header := block.Header
+	if header == nil {
+		return NewInvalidBlockErrorf(height, "missing header")
+	}
+	if header.Height != height {
+		return NewInvalidBlockErrorf(height, "unexpected height %d", header.Height)
+	}
+	if header.ChainID != c.chainID {
+		return NewInvalidBlockErrorf(height, "unexpected chain ID %s (expected %s)", header.ChainID, c.chainID)
+	}
+	if header.ParentID != parent.ID() {
+		return NewInvalidBlockErrorf(height, "parent ID %x does not match block %x at height %d", header.ParentID, parent.ID(), parent.Height)
+	}
+	if header.ParentView != parent.View {
+		return NewInvalidBlockErrorf(height, "parent view %d does not match view %d of parent", header.ParentView, parent.View)
+	}
+	if header.View <= header.ParentView {
+		return NewInvalidBlockErrorf(height, "view %d is not larger than parent view %d", header.View, header.ParentView)
+	}
+	if block.Payload == nil {
+		return NewInvalidBlockErrorf(height, "missing payload")
+	}
+	if header.PayloadHash != block.Payload.Hash() {
+		return NewInvalidBlockErrorf(height, "payload hash does not match payload")
+	}
+
+	// the QC included in the block certifies its parent
+	err := c.validateQC(header.QuorumCertificate())
+	if err != nil {
+		if model.IsInvalidQCError(err) {
+			return NewInvalidBlockErrorf(height, "invalid QC for parent: %w", err)
+		}
+		return fmt.Errorf("could not validate QC of block at height %d: %w", height, err)
+	}
+
+	c.mu.Lock()
+	c.pending = append(c.pending, block)
+	c.mu.Unlock()
+
+	return c.finalize(ctx)
+}
+
+// validateQC validates the QC with the consensus committee of the epoch of the QC's view.
+// Expected errors during normal operation:
+//   - model.InvalidQCError if the QC is invalid
+//   - ErrUnknownEpoch if the view of the QC is not within a known epoch
+func (c *Client) validateQC(qc *flow.QuorumCertificate) error {
+	c.mu.RLock()
+	e, ok := c.epochByView(qc.View)
+	c.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("could not validate QC for view %d: %w", qc.View, ErrUnknownEpoch)
+	}
+
+	err := e.validator.ValidateQC(qc)
+	if errors.Is(err, model.ErrViewForUnknownEpoch) {
+		return fmt.Errorf("could not validate QC for view %d: %w", qc.View, ErrUnknownEpoch)
+	}
+	return err
+}
+
+// epochByView returns the epoch the given view is within.
+// Caller must hold the lock.
+func (c *Client) epochByView(view uint64) (*epoch, bool) {
+	for _, e := range c.epochs {
+		if e.firstView <= view && view <= e.finalView {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// finalize finalizes the pending blocks satisfying the 2-chain finalization rule: a block is finalized
+// once it has a certified child in the directly following view. As the QC for a block is included in
+// its child, a child is certified once the child itself has a pending child.
+// Expected errors during normal operation:
+//   - InvalidBlockError if the payload of a finalized block failed verification
+func (c *Client) finalize(ctx context.Context) error {
+	c.mu.RLock()
+	finalizedIndex := -1
+	for i := 0; i+2 < len(c.pending); i++ {
+		if c.pending[i+1].Header.View == c.pending[i].Header.View+1 {
+			finalizedIndex = i
+		}
+	}
+	var finalized []*flow.Block
+	if finalizedIndex >= 0 {
+		finalized = append(finalized, c.pending[:finalizedIndex+1]...)
+	}
+	c.mu.RUnlock()
+
+	for _, block := range finalized {
+		err := c.processSeals(ctx, block)
+		if err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		c.finalized = block.Header
+		c.pending = c.pending[1:]
+		c.mu.Unlock()
+
+		c.log.Debug().
+			Uint64("height", block.Header.Height).
+			Uint64("view", block.Header.View).
+			Hex("block_id", logging.Entity(block)).
+			Msg("block finalized")
+	}
+	return nil
+}
+
+// processSeals processes the seals included in the given finalized block in the order of the sealed
+// execution results, retaining the sealed state commitments and applying the sealed service events.
+// Expected errors during normal operation:
+//   - InvalidBlockError if the seals or the sealed results failed verification
+func (c *Client) processSeals(ctx context.Context, block *flow.Block) error {
+	height := block.Header.Height
+	if len(block.Payload.Seals) == 0 {
+		return nil
+	}
+
+	// results included in the payload need not be requested from the API
+	included := make(map[flow.Identifier]*flow.ExecutionResult, len(block.Payload.Results))
+	for _, result := range block.Payload.Results {
+		included[result.ID()] = result
+	}
+
+	type sealedResult struct {
+		seal   *flow.Seal
+		result *flow.ExecutionResult
+	}
+	byPreviousResultID := make(map[flow.Identifier]sealedResult, len(block.Payload.Seals))
+	for _, seal := range block.Payload.Seals {
+		result, ok := included[seal.ResultID]
+		if !ok {
+			var err error
+			result, err = c.api.GetExecutionResultForBlockID(ctx, seal.BlockID)
+			if err != nil {
+				return fmt.Errorf("could not get execution result for sealed block %x: %w", seal.BlockID, err)
+			}
+		}
+		if result.ID() != seal.ResultID {
+			return NewInvalidBlockErrorf(height, "execution result %x does not match result %x of seal for block %x", result.ID(), seal.ResultID, seal.BlockID)
+		}
+		if result.BlockID != seal.BlockID {
+			return NewInvalidBlockErrorf(height, "execution result is for block %x, but seal is for block %x", result.BlockID, seal.BlockID)
+		}
+		commit, err := result.FinalStateCommitment()
+		if err != nil {
+			return NewInvalidBlockErrorf(height, "sealed execution result %x has no final state commitment: %w", result.ID(), err)
+		}
+		if commit != seal.FinalState {
+			return NewInvalidBlockErrorf(height, "state commitment of seal for block %x does not match sealed execution result", seal.BlockID)
+		}
+		byPreviousResultID[result.PreviousResultID] = sealedResult{seal: seal, result: result}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the seals must form a chain of results extending the latest sealed result
+	previousResultID := c.latestSeal.ResultID
+	for range block.Payload.Seals {
+		next, ok := byPreviousResultID[previousResultID]
+		if !ok {
+			return NewInvalidBlockErrorf(height, "seals do not extend the latest sealed result %x", previousResultID)
+		}
+		for _, event := range next.result.ServiceEvents {
+			err := c.applyServiceEvent(event)
+			if err != nil {
+				return NewInvalidBlockErrorf(height, "invalid service event in sealed result %x: %w", next.result.ID(), err)
+			}
+		}
+		c.addCommit(next.seal.BlockID, next.seal.FinalState)
+		c.latestSeal = next.seal
+		previousResultID = next.seal.ResultID
+	}
+	return nil
+}
+
+// applyServiceEvent applies a sealed service event. Sealed EpochSetup and EpochCommit events introduce
+// the consensus committee of the next epoch, other service events are ignored.
+// Caller must hold the lock.
+// Any returned error indicates an invalid service event.
+func (c *Client) applyServiceEvent(event flow.ServiceEvent) error {
+	last := c.epochs[len(c.epochs)-1]
+
+	switch ev := event.Event.(type) {
+	case *flow.EpochSetup:
+		if ev.Counter != last.counter+1 {
+			return fmt.Errorf("setup event for epoch %d does not follow epoch %d", ev.Counter, last.counter)
+		}
+		c.setup = ev
+	case *flow.EpochCommit:
+		if c.setup == nil {
+			return fmt.Errorf("commit event for epoch %d without sealed setup event", ev.Counter)
+		}
+		err := validateEpochTransition(last, c.setup, ev)
+		if err != nil {
+			return fmt.Errorf("invalid epoch transition: %w", err)
+		}
+		next, err := newEpoch(inmem.NewCommittedEpoch(c.setup, ev), c.newValidator)
+		if err != nil {
+			return fmt.Errorf("could not create epoch %d: %w", ev.Counter, err)
+		}
+		c.epochs = append(c.epochs, next)
+		c.setup = nil
+
+		c.log.Info().
+			Uint64("counter", next.counter).
+			Uint64("first_view", next.firstView).
+			Uint64("final_view", next.finalView).
+			Msg("followed epoch transition")
+	}
+	return nil
+}
+
+// addCommit retains the sealed state commitment of the given block, dropping the oldest
+// commitment if the limit is reached.
+// Caller must hold the lock.
+func (c *Client) addCommit(blockID flow.Identifier, commit flow.StateCommitment) {
+	if _, ok := c.commits[blockID]; ok {
+		return
+	}
+	if uint(len(c.commitOrder)) >= c.config.SealedCommitsLimit {
+		delete(c.commits, c.commitOrder[0])
+		c.commitOrder = c.commitOrder[1:]
+	}
+	c.commits[blockID] = commit
+	c.commitOrder = append(c.commitOrder, blockID)
+}
+
+// FinalizedHeader returns the header of the latest block the light client verified to be finalized.
+func (c *Client) FinalizedHeader() *flow.Header {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.finalized
+}
+
+// LatestSeal returns the latest seal included in a finalized block.
+func (c *Client) LatestSeal() *flow.Seal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.latestSeal
+}
+
+// SealedCommit returns the sealed state commitment of the given block.
+// Expected errors during normal operation:
+//   - ErrUnknownSealedBlock if the block is not sealed, or its state commitment is no longer retained
+func (c *Client) SealedCommit(blockID flow.Identifier) (flow.StateCommitment, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	commit, ok := c.commits[blockID]
+	if !ok {
+		return flow.DummyStateCommitment, fmt.Errorf("could not get state commitment of block %x: %w", blockID, ErrUnknownSealedBlock)
+	}
+	return commit, nil
+}
+
+// EpochCounterAtView returns the counter of the committed epoch the given view is within.
+// Expected errors during normal operation:
+//   - ErrUnknownEpoch if the view is not within a known epoch
+func (c *Client) EpochCounterAtView(view uint64) (uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.epochByView(view)
+	if !ok {
+		return 0, fmt.Errorf("could not get epoch for view %d: %w", view, ErrUnknownEpoch)
+	}
+	return e.counter, nil
+}
@@ -0,0 +1,314 @@
+package lightclient
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/onflow/flow-go/crypto"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// stubAPI serves a fixed chain of blocks and execution results.
+type stubAPI struct {
+	blocks  map[uint64]*flow.Block
+	results map[flow.Identifier]*flow.ExecutionResult
+	latest  *flow.Header
+}
+
+var _ API = (*stubAPI)(nil)
+
+func newStubAPI() *stubAPI {
+	return &stubAPI{
+		blocks:  make(map[uint64]*flow.Block),
+		results: make(map[flow.Identifier]*flow.ExecutionResult),
+	}
+}
+
+func (s *stubAPI) addBlock(block *flow.Block) {
+	s.blocks[block.Header.Height] = block
+	s.latest = block.Header
+}
+
+func (s *stubAPI) GetLatestBlockHeader(_ context.Context, _ bool) (*flow.Header, flow.BlockStatus, error) {
+	return s.latest, flow.BlockStatusFinalized, nil
+}
+
+func (s *stubAPI) GetBlockByHeight(_ context.Context, height uint64) (*flow.Block, flow.BlockStatus, error) {
+	block, ok := s.blocks[height]
+	if !ok {
+		return nil, flow.BlockStatusUnknown, fmt.Errorf("no block at height %d", height)
+	}
+	return block, flow.BlockStatusFinalized, nil
+}
+
+func (s *stubAPI) GetExecutionResultForBlockID(_ context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	result, ok := s.results[blockID]
+	if !ok {
+		return nil, fmt.Errorf("no result for block %x", blockID)
+	}
+	return result, nil
+}
+
+type ClientSuite struct {
+	suite.Suite
+
+	api        *stubAPI
+	validator  *mocks.Validator
+	head       *flow.Header
+	rootResult *flow.ExecutionResult
+	rootSeal   *flow.Seal
+	setup      *flow.EpochSetup
+	client     *Client
+}
+
+func TestClient(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+func (s *ClientSuite) SetupTest() {
+	s.api = newStubAPI()
+	s.validator = mocks.NewValidator(s.T())
+	s.validator.On("ValidateQC", mock.Anything).Return(nil).Maybe()
+
+	s.head = unittest.BlockHeaderFixture(func(header *flow.Header) {
+		header.View = 1000
+	})
+	s.rootResult = unittest.ExecutionResultFixture()
+	s.rootSeal = unittest.Seal.Fixture(unittest.Seal.WithResult(s.rootResult))
+
+	// consensus participants without keys, as QCs are validated by the mocked validator
+	participants := make(flow.IdentityList, 0, 4)
+	for i := 0; i < 4; i++ {
+		participants = append(participants, &flow.Identity{
+			NodeID: unittest.IdentifierFixture(),
+			Role:   flow.RoleConsensus,
+			Weight: 1000,
+		})
+	}
+	s.setup = &flow.EpochSetup{
+		Counter:      1,
+		FirstView:    0,
+		FinalView:    10_000,
+		Participants: participants,
+		Assignments:  flow.AssignmentList{},
+		RandomSource: unittest.RandomBytes(flow.EpochSetupRandomSourceLength),
+	}
+	commit := &flow.EpochCommit{
+		Counter:            s.setup.Counter,
+		DKGParticipantKeys: unittest.PublicKeysFixture(len(participants), crypto.ECDSAP256),
+	}
+
+	snapshot := inmem.SnapshotFromEncodable(inmem.EncodableSnapshot{
+		Head:         s.head,
+		LatestSeal:   s.rootSeal,
+		LatestResult: s.rootResult,
+		Phase:        flow.EpochPhaseStaking,
+		Epochs: inmem.EncodableEpochs{
+			Current: encodableEpoch(s.T(), inmem.NewCommittedEpoch(s.setup, commit)),
+		},
+	})
+
+	var err error
+	s.client, err = newClient(unittest.Logger(), s.api, snapshot, DefaultConfig(), s.newValidator)
+	require.NoError(s.T(), err)
+}
+
+func (s *ClientSuite) newValidator(flow.IdentityList, protocol.DKG) (hotstuff.Validator, error) {
+	return s.validator, nil
+}
+
+// encodableEpoch converts the given committed epoch to its encodable representation.
+func encodableEpoch(t *testing.T, epoch protocol.Epoch) inmem.EncodableEpoch {
+	converted, err := inmem.FromEpoch(epoch)
+	require.NoError(t, err)
+	return converted.Encodable()
+}
+
+// extend appends a block with the given payload in the given view to the chain served by the API.
+func (s *ClientSuite) extend(parent *flow.Header, view uint64, payload flow.Payload) *flow.Block {
+	header := unittest.BlockHeaderWithParentFixture(parent)
+	header.View = view
+	header.LastViewTC = nil
+	header.PayloadHash = payload.Hash()
+	block := &flow.Block{Header: header, Payload: &payload}
+	s.api.addBlock(block)
+	return block
+}
+
+// extendEmpty appends blocks with empty payloads in the given views to the chain served by the API.
+func (s *ClientSuite) extendEmpty(parent *flow.Header, views ...uint64) []*flow.Block {
+	blocks := make([]*flow.Block, 0, len(views))
+	for _, view := range views {
+		block := s.extend(parent, view, flow.EmptyPayload())
+		blocks = append(blocks, block)
+		parent = block.Header
+	}
+	return blocks
+}
+
+// TestInitialState tests that the light client starts from the state of the trusted snapshot.
+func (s *ClientSuite) TestInitialState() {
+	assert.Equal(s.T(), s.head.ID(), s.client.FinalizedHeader().ID())
+	assert.Equal(s.T(), s.rootSeal, s.client.LatestSeal())
+
+	commit, err := s.client.SealedCommit(s.rootSeal.BlockID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), s.rootSeal.FinalState, commit)
+
+	_, err = s.client.SealedCommit(unittest.IdentifierFixture())
+	assert.ErrorIs(s.T(), err, ErrUnknownSealedBlock)
+
+	counter, err := s.client.EpochCounterAtView(s.head.View)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), s.setup.Counter, counter)
+
+	_, err = s.client.EpochCounterAtView(s.setup.FinalView + 1)
+	assert.ErrorIs(s.T(), err, ErrUnknownEpoch)
+}
+
+// TestSync_Finalization tests that blocks are finalized by the 2-chain rule only.
+func (s *ClientSuite) TestSync_Finalization() {
+	// views: [1001] [1003] [1004] [1005]
+	// 1003 has a direct child 1004, which is certified by 1005
+	blocks := s.extendEmpty(s.head, s.head.View+1, s.head.View+3, s.head.View+4, s.head.View+5)
+
+	err := s.client.Sync(context.Background())
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), blocks[1].ID(), s.client.FinalizedHeader().ID())
+
+	// the last block certifies the block in view 1005, which finalizes the block in view 1004
+	s.extendEmpty(blocks[3].Header, s.head.View+7)
+	err = s.client.Sync(context.Background())
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), s.head.View+4, s.client.FinalizedHeader().View)
+}
+
+// TestSync_InvalidBlock tests that blocks inconsistent with the chain are rejected.
+func (s *ClientSuite) TestSync_InvalidBlock() {
+	s.Run("wrong parent", func() {
+		s.SetupTest()
+		blocks := s.extendEmpty(s.head, s.head.View+1, s.head.View+2)
+		blocks[1].Header.ParentID = unittest.IdentifierFixture()
+
+		err := s.client.Sync(context.Background())
+		assert.True(s.T(), IsInvalidBlockError(err))
+	})
+	s.Run("wrong payload hash", func() {
+		s.SetupTest()
+		blocks := s.extendEmpty(s.head, s.head.View+1)
+		blocks[0].Payload.Seals = append(blocks[0].Payload.Seals, unittest.Seal.Fixture())
+
+		err := s.client.Sync(context.Background())
+		assert.True(s.T(), IsInvalidBlockError(err))
+	})
+	s.Run("invalid QC", func() {
+		s.SetupTest()
+		s.validator = mocks.NewValidator(s.T())
+		s.client.epochs[0].validator = s.validator
+		s.validator.On("ValidateQC", mock.Anything).Return(model.InvalidQCError{Err: fmt.Errorf("invalid signature")})
+		s.extendEmpty(s.head, s.head.View+1)
+
+		err := s.client.Sync(context.Background())
+		assert.True(s.T(), IsInvalidBlockError(err))
+		assert.Equal(s.T(), s.head.ID(), s.client.FinalizedHeader().ID())
+	})
+	s.Run("unknown epoch", func() {
+		s.SetupTest()
+		blocks := s.extendEmpty(s.head, s.setup.FinalView+1)
+		s.extendEmpty(blocks[0].Header, s.setup.FinalView+2)
+
+		err := s.client.Sync(context.Background())
+		assert.ErrorIs(s.T(), err, ErrUnknownEpoch)
+	})
+}
+
+// TestSync_EpochTransition tests that the light client retains sealed state commitments of finalized
+// blocks and follows the epoch transition through the sealed service events.
+func (s *ClientSuite) TestSync_EpochTransition() {
+	nextSetup := &flow.EpochSetup{
+		Counter:      s.setup.Counter + 1,
+		FirstView:    s.setup.FinalView + 1,
+		FinalView:    s.setup.FinalView + 10_000,
+		Participants: s.setup.Participants,
+		Assignments:  flow.AssignmentList{},
+		RandomSource: unittest.RandomBytes(flow.EpochSetupRandomSourceLength),
+	}
+	nextCommit := &flow.EpochCommit{
+		Counter:            nextSetup.Counter,
+		DKGGroupKey:        unittest.KeyFixture(crypto.ECDSAP256).PublicKey(),
+		DKGParticipantKeys: unittest.PublicKeysFixture(len(nextSetup.Participants), crypto.ECDSAP256),
+	}
+
+	setupResult := unittest.ExecutionResultFixture(unittest.WithPreviousResult(*s.rootResult))
+	setupResult.ServiceEvents = flow.ServiceEventList{nextSetup.ServiceEvent()}
+	commitResult := unittest.ExecutionResultFixture(unittest.WithPreviousResult(*setupResult))
+	commitResult.ServiceEvents = flow.ServiceEventList{nextCommit.ServiceEvent()}
+	setupSeal := unittest.Seal.Fixture(unittest.Seal.WithResult(setupResult))
+	commitSeal := unittest.Seal.Fixture(unittest.Seal.WithResult(commitResult))
+
+	// the result sealing the commit event is included in the payload, the other one is served by the API
+	s.api.results[setupResult.BlockID] = setupResult
+	payload := flow.Payload{
+		Seals:   []*flow.Seal{commitSeal, setupSeal},
+		Results: flow.ExecutionResultList{commitResult},
+	}
+	sealing := s.extend(s.head, s.head.View+1, payload)
+	s.extendEmpty(sealing.Header, s.head.View+2, s.head.View+3)
+
+	err := s.client.Sync(context.Background())
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), sealing.ID(), s.client.FinalizedHeader().ID())
+	assert.Equal(s.T(), commitSeal, s.client.LatestSeal())
+
+	for _, seal := range []*flow.Seal{setupSeal, commitSeal} {
+		commit, err := s.client.SealedCommit(seal.BlockID)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), seal.FinalState, commit)
+	}
+
+	counter, err := s.client.EpochCounterAtView(nextSetup.FirstView)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), nextSetup.Counter, counter)
+}
+
+// TestSync_InvalidSeals tests that seals which do not extend the latest sealed result, or whose
+// result does not match the seal, are rejected.
+func (s *ClientSuite) TestSync_InvalidSeals() {
+	s.Run("seal not extending latest sealed result", func() {
+		s.SetupTest()
+		result := unittest.ExecutionResultFixture()
+		seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+		payload := flow.Payload{Seals: []*flow.Seal{seal}, Results: flow.ExecutionResultList{result}}
+		sealing := s.extend(s.head, s.head.View+1, payload)
+		s.extendEmpty(sealing.Header, s.head.View+2, s.head.View+3)
+
+		err := s.client.Sync(context.Background())
+		assert.True(s.T(), IsInvalidBlockError(err))
+		assert.Equal(s.T(), s.rootSeal, s.client.LatestSeal())
+	})
+	s.Run("result not matching seal", func() {
+		s.SetupTest()
+		result := unittest.ExecutionResultFixture(unittest.WithPreviousResult(*s.rootResult))
+		seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+		// the API serves a different result for the sealed block
+		s.api.results[seal.BlockID] = unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(seal.BlockID))
+		sealing := s.extend(s.head, s.head.View+1, flow.Payload{Seals: []*flow.Seal{seal}})
+		s.extendEmpty(sealing.Header, s.head.View+2, s.head.View+3)
+
+		err := s.client.Sync(context.Background())
+		assert.True(s.T(), IsInvalidBlockError(err))
+	})
+}
@@ -0,0 +1,15 @@
+package lightclient
+
+// Config configures the light client.
+type Config struct {
+	// SealedCommitsLimit is the maximum number of state commitments of sealed blocks the light client
+	// keeps to verify register proofs against. When the limit is reached, the oldest commitment is dropped.
+	SealedCommitsLimit uint
+}
+
+// DefaultConfig returns the default configuration of the light client.
+func DefaultConfig() Config {
+	return Config{
+		SealedCommitsLimit: 1000,
+	}
+}
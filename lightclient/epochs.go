@@ -0,0 +1,148 @@
+package lightclient
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/signature"
+	"github.com/onflow/flow-go/consensus/hotstuff/validator"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// validatorFactory creates a validator for the QCs of the given consensus committee.
+type validatorFactory func(participants flow.IdentityList, dkg protocol.DKG) (hotstuff.Validator, error)
+
+// newConsensusValidator creates a validator verifying the QCs of the given consensus committee, which
+// are aggregated from staking and random beacon signatures.
+func newConsensusValidator(participants flow.IdentityList, dkg protocol.DKG) (hotstuff.Validator, error) {
+	committee, err := committees.NewStaticCommitteeWithDKG(participants, flow.Identifier{}, dkg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create static committee: %w", err)
+	}
+	verifier := verification.NewCombinedVerifier(committee, signature.NewConsensusSigDataPacker(committee))
+	return validator.New(committee, verifier), nil
+}
+
+// epoch is a committed epoch, whose consensus committee the light client verifies QCs with.
+type epoch struct {
+	counter   uint64
+	firstView uint64
+	finalView uint64
+	validator hotstuff.Validator
+}
+
+// newEpoch creates the epoch for the given committed protocol epoch.
+// No errors are expected for a committed epoch.
+func newEpoch(from protocol.Epoch, newValidator validatorFactory) (*epoch, error) {
+	counter, err := from.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get counter: %w", err)
+	}
+	firstView, err := from.FirstView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get first view: %w", err)
+	}
+	finalView, err := from.FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get final view: %w", err)
+	}
+	identities, err := from.InitialIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("could not get identities: %w", err)
+	}
+	dkg, err := from.DKG()
+	if err != nil {
+		return nil, fmt.Errorf("could not get dkg: %w", err)
+	}
+
+	v, err := newValidator(identities.Filter(filter.IsVotingConsensusCommitteeMember), dkg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create validator for epoch %d: %w", counter, err)
+	}
+
+	return &epoch{
+		counter:   counter,
+		firstView: firstView,
+		finalView: finalView,
+		validator: v,
+	}, nil
+}
+
+// setupFromEpoch reconstructs the setup event of an epoch, which has been set up but not committed.
+// No errors are expected for an epoch which has been set up.
+func setupFromEpoch(from protocol.Epoch) (*flow.EpochSetup, error) {
+	counter, err := from.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get counter: %w", err)
+	}
+	firstView, err := from.FirstView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get first view: %w", err)
+	}
+	dkgPhase1FinalView, err := from.DKGPhase1FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get dkg phase 1 final view: %w", err)
+	}
+	dkgPhase2FinalView, err := from.DKGPhase2FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get dkg phase 2 final view: %w", err)
+	}
+	dkgPhase3FinalView, err := from.DKGPhase3FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get dkg phase 3 final view: %w", err)
+	}
+	finalView, err := from.FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get final view: %w", err)
+	}
+	participants, err := from.InitialIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("could not get identities: %w", err)
+	}
+	clustering, err := from.Clustering()
+	if err != nil {
+		return nil, fmt.Errorf("could not get clustering: %w", err)
+	}
+	randomSource, err := from.RandomSource()
+	if err != nil {
+		return nil, fmt.Errorf("could not get random source: %w", err)
+	}
+
+	return &flow.EpochSetup{
+		Counter:            counter,
+		FirstView:          firstView,
+		DKGPhase1FinalView: dkgPhase1FinalView,
+		DKGPhase2FinalView: dkgPhase2FinalView,
+		DKGPhase3FinalView: dkgPhase3FinalView,
+		FinalView:          finalView,
+		Participants:       participants,
+		Assignments:        clustering.Assignments(),
+		RandomSource:       randomSource,
+	}, nil
+}
+
+// validateEpochTransition checks that the committed next epoch, given by its setup and commit events,
+// directly follows the current epoch.
+func validateEpochTransition(current *epoch, setup *flow.EpochSetup, commit *flow.EpochCommit) error {
+	if setup.Counter != current.counter+1 {
+		return fmt.Errorf("next epoch counter %d does not follow current epoch counter %d", setup.Counter, current.counter)
+	}
+	if setup.FirstView != current.finalView+1 {
+		return fmt.Errorf("next epoch first view %d does not follow current epoch final view %d", setup.FirstView, current.finalView)
+	}
+	if commit.Counter != setup.Counter {
+		return fmt.Errorf("inconsistent epoch counter between commit (%d) and setup (%d) events", commit.Counter, setup.Counter)
+	}
+	if len(setup.Assignments) != len(commit.ClusterQCs) {
+		return fmt.Errorf("number of clusters (%d) does not match number of QCs (%d)", len(setup.Assignments), len(commit.ClusterQCs))
+	}
+	participants := setup.Participants.Filter(filter.IsValidDKGParticipant)
+	if len(participants) != len(commit.DKGParticipantKeys) {
+		return fmt.Errorf("participant list (len=%d) does not match dkg key list (len=%d)", len(participants), len(commit.DKGParticipantKeys))
+	}
+	return nil
+}
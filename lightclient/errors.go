@@ -0,0 +1,45 @@
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownEpoch is returned when a QC for a view has to be verified, which is not within any epoch
+	// the light client knows the consensus committee of. This happens if the epoch transition was not
+	// followed, or if the network entered epoch fallback mode.
+	ErrUnknownEpoch = errors.New("view is not within a known epoch")
+
+	// ErrUnknownSealedBlock is returned when a register proof is verified against the state of a block,
+	// whose sealed state commitment the light client does not know.
+	ErrUnknownSealedBlock = errors.New("state commitment of block is not known")
+)
+
+// InvalidBlockError indicates that a block returned by the API failed verification, i.e. the API
+// served data that is inconsistent with the chain the light client follows.
+type InvalidBlockError struct {
+	Height uint64
+	err    error
+}
+
+func NewInvalidBlockErrorf(height uint64, msg string, args ...interface{}) error {
+	return InvalidBlockError{
+		Height: height,
+		err:    fmt.Errorf(msg, args...),
+	}
+}
+
+func (e InvalidBlockError) Unwrap() error {
+	return e.err
+}
+
+func (e InvalidBlockError) Error() string {
+	return fmt.Sprintf("invalid block at height %d: %s", e.Height, e.err.Error())
+}
+
+// IsInvalidBlockError returns whether the given error is an InvalidBlockError error
+func IsInvalidBlockError(err error) bool {
+	var errInvalidBlockError InvalidBlockError
+	return errors.As(err, &errInvalidBlockError)
+}
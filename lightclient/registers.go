@@ -0,0 +1,59 @@
+package lightclient
+
+import (
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// VerifyRegisters verifies the values of the given registers at the sealed execution state of the
+// given block, using the batch proof obtained from an untrusted execution or access node. The values
+// are returned in the order of the register IDs, where nil indicates an unset register.
+// Expected errors during normal operation:
+//   - ErrUnknownSealedBlock if the state commitment of the block is not known to the light client
+//   - access.InvalidProofError if the proof does not prove the values of all registers against the state commitment
+func (c *Client) VerifyRegisters(blockID flow.Identifier, registerIDs []flow.RegisterID, batchProof *ledger.TrieBatchProof) ([]flow.RegisterValue, error) {
+	commit, err := c.SealedCommit(blockID)
+	if err != nil {
+		return nil, err
+	}
+	return access.VerifyRegisterProof(commit, registerIDs, batchProof)
+}
+
+// VerifyAccountRegisters verifies the values of the given registers of an account at the sealed
+// execution state of the given block, using the batch proof obtained from an untrusted execution or
+// access node. The values are returned by register key, where nil indicates an unset register.
+// Expected errors during normal operation:
+//   - ErrUnknownSealedBlock if the state commitment of the block is not known to the light client
+//   - access.InvalidProofError if the proof does not prove the values of all registers against the state commitment
+func (c *Client) VerifyAccountRegisters(blockID flow.Identifier, address flow.Address, keys []string, batchProof *ledger.TrieBatchProof) (map[string]flow.RegisterValue, error) {
+	registerIDs := make([]flow.RegisterID, 0, len(keys))
+	for _, key := range keys {
+		registerIDs = append(registerIDs, flow.NewRegisterID(string(address.Bytes()), key))
+	}
+
+	values, err := c.VerifyRegisters(blockID, registerIDs, batchProof)
+	if err != nil {
+		return nil, err
+	}
+
+	registers := make(map[string]flow.RegisterValue, len(keys))
+	for i, key := range keys {
+		registers[key] = values[i]
+	}
+	return registers, nil
+}
+
+// VerifyAccount verifies the account proof obtained from an untrusted access node against the sealed
+// execution state of the proof's block, and returns the proven account. The account balance is not
+// covered by the proof and is therefore not set.
+// Expected errors during normal operation:
+//   - ErrUnknownSealedBlock if the state commitment of the block is not known to the light client
+//   - access.InvalidProofError if the proof does not prove the account against the state commitment
+func (c *Client) VerifyAccount(proof *access.AccountProof) (*flow.Account, error) {
+	commit, err := c.SealedCommit(proof.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	return proof.Verify(commit)
+}
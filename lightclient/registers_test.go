@@ -0,0 +1,165 @@
+package lightclient
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/convert"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// registerTrie builds a trie holding the given registers, and returns the proofs of the given
+// registers, including registers which are not set, against the trie's root hash.
+func registerTrie(t *testing.T, registers map[flow.RegisterID]flow.RegisterValue, proven []flow.RegisterID) (flow.StateCommitment, *ledger.TrieBatchProof) {
+	paths := make([]ledger.Path, 0, len(registers))
+	payloads := make([]ledger.Payload, 0, len(registers))
+	for registerID, value := range registers {
+		key := convert.RegisterIDToLedgerKey(registerID)
+		path, err := pathfinder.KeyToPath(key, access.RegisterProofPathFinderVersion)
+		require.NoError(t, err)
+		paths = append(paths, path)
+		payloads = append(payloads, *ledger.NewPayload(key, value))
+	}
+	mtrie, _, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, true)
+	require.NoError(t, err)
+
+	// as the execution state does for unset registers, expand the trie with empty payloads
+	// without changing its root hash, so that the proofs prove the registers are unset
+	provenPaths := make([]ledger.Path, 0, len(proven))
+	var unsetPaths []ledger.Path
+	var unsetPayloads []ledger.Payload
+	for _, registerID := range proven {
+		path, err := pathfinder.KeyToPath(convert.RegisterIDToLedgerKey(registerID), access.RegisterProofPathFinderVersion)
+		require.NoError(t, err)
+		provenPaths = append(provenPaths, path)
+		if _, ok := registers[registerID]; !ok {
+			unsetPaths = append(unsetPaths, path)
+			unsetPayloads = append(unsetPayloads, *ledger.EmptyPayload())
+		}
+	}
+	expanded, _, err := trie.NewTrieWithUpdatedRegisters(mtrie, unsetPaths, unsetPayloads, false)
+	require.NoError(t, err)
+	require.Equal(t, mtrie.RootHash(), expanded.RootHash())
+
+	return flow.StateCommitment(mtrie.RootHash()), expanded.UnsafeProofs(provenPaths)
+}
+
+// clientWithCommit returns a light client which knows the given sealed state commitment of the given block.
+func clientWithCommit(blockID flow.Identifier, commit flow.StateCommitment) *Client {
+	c := &Client{
+		log:     zerolog.Nop(),
+		config:  DefaultConfig(),
+		commits: make(map[flow.Identifier]flow.StateCommitment),
+	}
+	c.addCommit(blockID, commit)
+	return c
+}
+
+func TestVerifyRegisters(t *testing.T) {
+	address := unittest.RandomAddressFixture()
+	owner := string(address.Bytes())
+	registers := map[flow.RegisterID]flow.RegisterValue{
+		flow.NewRegisterID(owner, "balance"):            []byte{1, 2, 3},
+		flow.NewRegisterID(owner, "storage_used"):       []byte{4, 5},
+		flow.NewRegisterID(owner, "public_key_count"):   []byte{6},
+		flow.NewRegisterID("", "uuid"):                  []byte{7, 8, 9},
+		flow.NewRegisterID(owner+"other", "contract_1"): []byte{10},
+	}
+	unset := flow.NewRegisterID(owner, "unset")
+	proven := []flow.RegisterID{
+		flow.NewRegisterID(owner, "storage_used"),
+		unset,
+		flow.NewRegisterID(owner, "balance"),
+	}
+	commit, batchProof := registerTrie(t, registers, proven)
+	blockID := unittest.IdentifierFixture()
+
+	t.Run("valid proofs", func(t *testing.T) {
+		client := clientWithCommit(blockID, commit)
+		values, err := client.VerifyRegisters(blockID, proven, batchProof)
+		require.NoError(t, err)
+		assert.Equal(t, []flow.RegisterValue{registers[proven[0]], nil, registers[proven[2]]}, values)
+	})
+
+	t.Run("account registers", func(t *testing.T) {
+		client := clientWithCommit(blockID, commit)
+		values, err := client.VerifyAccountRegisters(blockID, address, []string{"balance", "unset"}, batchProof)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]flow.RegisterValue{
+			"balance": registers[proven[2]],
+			"unset":   nil,
+		}, values)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		client := clientWithCommit(blockID, commit)
+		_, err := client.VerifyRegisters(unittest.IdentifierFixture(), proven, batchProof)
+		assert.ErrorIs(t, err, ErrUnknownSealedBlock)
+	})
+
+	t.Run("wrong state commitment", func(t *testing.T) {
+		client := clientWithCommit(blockID, unittest.StateCommitmentFixture())
+		_, err := client.VerifyRegisters(blockID, proven, batchProof)
+		assert.True(t, access.IsInvalidProofError(err))
+	})
+
+	t.Run("missing proof", func(t *testing.T) {
+		client := clientWithCommit(blockID, commit)
+		_, err := client.VerifyRegisters(blockID, []flow.RegisterID{flow.NewRegisterID(owner, "public_key_count")}, batchProof)
+		assert.True(t, access.IsInvalidProofError(err))
+	})
+
+	t.Run("tampered value", func(t *testing.T) {
+		_, tampered := registerTrie(t, registers, proven)
+		for _, p := range tampered.Proofs {
+			key, err := p.Payload.Key()
+			require.NoError(t, err)
+			p.Payload = ledger.NewPayload(key, []byte{42})
+		}
+
+		client := clientWithCommit(blockID, commit)
+		_, err := client.VerifyRegisters(blockID, proven, tampered)
+		assert.True(t, access.IsInvalidProofError(err))
+	})
+
+	t.Run("unset register proven as set", func(t *testing.T) {
+		// prove the register is set in a different trie, which does not match the commitment
+		withUnset := make(map[flow.RegisterID]flow.RegisterValue, len(registers)+1)
+		for registerID, value := range registers {
+			withUnset[registerID] = value
+		}
+		withUnset[unset] = []byte{42}
+		_, forged := registerTrie(t, withUnset, proven)
+
+		client := clientWithCommit(blockID, commit)
+		_, err := client.VerifyRegisters(blockID, proven, forged)
+		assert.True(t, access.IsInvalidProofError(err))
+	})
+}
+
+// TestSealedCommitsLimit tests that the oldest sealed state commitments are dropped once the limit is reached.
+func TestSealedCommitsLimit(t *testing.T) {
+	c := &Client{
+		config:  Config{SealedCommitsLimit: 2},
+		commits: make(map[flow.Identifier]flow.StateCommitment),
+	}
+	blockIDs := unittest.IdentifierListFixture(3)
+	for _, blockID := range blockIDs {
+		c.addCommit(blockID, unittest.StateCommitmentFixture())
+	}
+
+	_, err := c.SealedCommit(blockIDs[0])
+	assert.ErrorIs(t, err, ErrUnknownSealedBlock)
+	for _, blockID := range blockIDs[1:] {
+		_, err := c.SealedCommit(blockID)
+		assert.NoError(t, err)
+	}
+}
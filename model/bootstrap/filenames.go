@@ -13,6 +13,12 @@ var (
 	// execution state
 	DirnameExecutionState = "execution-state"
 
+	// sealing segment downloaded from a bootstrap peer
+	DirnameSealingSegmentDownload = "sealing-segment-download"
+
+	// snapshots retrieved from access nodes during dynamic startup
+	DirnameDynamicStartupSnapshots = "dynamic-startup-snapshots"
+
 	// public genesis information
 	DirnamePublicBootstrap    = "public-root-information"
 	PathInternalNodeInfosPub  = filepath.Join(DirnamePublicBootstrap, "node-internal-infos.pub.json")
@@ -0,0 +1,87 @@
+package chunks
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Chunk fault types, as recorded in fault reports and logs.
+const (
+	FaultTypeMissingRegisterTouch    = "missing_register_touch"
+	FaultTypeNonMatchingFinalState   = "final_state_mismatch"
+	FaultTypeInvalidVerifiableChunk  = "invalid_verifiable_chunk"
+	FaultTypeInvalidEventsCollection = "invalid_event_collection"
+	FaultTypeInvalidServiceEvents    = "invalid_service_events"
+	FaultTypeUnknown                 = "unknown"
+)
+
+// FaultType returns the type of the given chunk fault.
+func FaultType(fault ChunkFault) string {
+	switch fault.(type) {
+	case *CFMissingRegisterTouch:
+		return FaultTypeMissingRegisterTouch
+	case *CFNonMatchingFinalState:
+		return FaultTypeNonMatchingFinalState
+	case *CFInvalidVerifiableChunk:
+		return FaultTypeInvalidVerifiableChunk
+	case *CFInvalidEventsCollection:
+		return FaultTypeInvalidEventsCollection
+	case *CFInvalidServiceEventsEmitted:
+		return FaultTypeInvalidServiceEvents
+	default:
+		return FaultTypeUnknown
+	}
+}
+
+// FaultReport is a self-contained record of a chunk fault found by a verification node. It
+// holds all inputs of the chunk verification, so that the verification can be replayed offline
+// to reproduce the disagreement with the execution node, along with the outcome observed by the
+// verification node.
+type FaultReport struct {
+	// fault
+	FaultType  string    // type of the chunk fault, see FaultType
+	Fault      string    // human-readable description of the chunk fault
+	ReportedAt time.Time // time the fault was found
+	VerifierID flow.Identifier
+
+	// inputs of the chunk verification
+	ChainID           flow.ChainID
+	IsSystemChunk     bool
+	Chunk             *flow.Chunk
+	Header            *flow.Header
+	Result            *flow.ExecutionResult
+	ChunkDataPack     *flow.ChunkDataPack
+	EndState          flow.StateCommitment // final state commitment of the chunk, as provided by the execution result
+	TransactionOffset uint32
+	RandomSource      []byte // source of randomness at the chunk's block, taken from the protocol state
+
+	// outcome of the chunk verification
+	ComputedEndState flow.StateCommitment // final state commitment computed by the verifier, for final state mismatches
+	MissingRegisters []string             // registers missing from the chunk data pack, for missing register touches
+	RegisterUpdates  flow.RegisterEntries // register updates computed by the verifier which change the start state, for final state mismatches
+}
+
+// ResultID returns the ID of the execution result containing the faulty chunk.
+func (r *FaultReport) ResultID() flow.Identifier {
+	return r.Result.ID()
+}
+
+// ChunkIndex returns the index of the faulty chunk.
+func (r *FaultReport) ChunkIndex() uint64 {
+	return r.Chunk.Index
+}
+
+// SetOutcome records the fault-specific outcome of the chunk verification in the report.
+func (r *FaultReport) SetOutcome(fault ChunkFault) {
+	r.FaultType = FaultType(fault)
+	r.Fault = fault.String()
+
+	switch cf := fault.(type) {
+	case *CFMissingRegisterTouch:
+		r.MissingRegisters = cf.RegisterIDs()
+	case *CFNonMatchingFinalState:
+		r.ComputedEndState = cf.Expected()
+		r.RegisterUpdates = cf.DivergingRegisters()
+	}
+}
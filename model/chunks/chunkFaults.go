@@ -42,6 +42,16 @@ func (cf CFMissingRegisterTouch) ExecutionResultID() flow.Identifier {
 	return cf.execResID
 }
 
+// RegisterIDs returns the string representation of the registers missing from the chunk data pack
+func (cf CFMissingRegisterTouch) RegisterIDs() []string {
+	return cf.regsterIDs
+}
+
+// TransactionID returns the ID of the first transaction of the chunk that required a missing register
+func (cf CFMissingRegisterTouch) TransactionID() flow.Identifier {
+	return cf.txID
+}
+
 // NewCFMissingRegisterTouch creates a new instance of Chunk Fault (MissingRegisterTouch)
 func NewCFMissingRegisterTouch(regsterIDs []string, chInx uint64, execResID flow.Identifier, txID flow.Identifier) *CFMissingRegisterTouch {
 	return &CFMissingRegisterTouch{regsterIDs: regsterIDs,
@@ -57,6 +67,7 @@ type CFNonMatchingFinalState struct {
 	computed   flow.StateCommitment
 	chunkIndex uint64
 	execResID  flow.Identifier
+	diverging  flow.RegisterEntries // register updates computed by the verifier which change the start state
 }
 
 func (cf CFNonMatchingFinalState) String() string {
@@ -73,6 +84,23 @@ func (cf CFNonMatchingFinalState) ExecutionResultID() flow.Identifier {
 	return cf.execResID
 }
 
+// Expected returns the final state commitment expected by the verifier, obtained by applying
+// the register updates of the chunk to the partial trie
+func (cf CFNonMatchingFinalState) Expected() flow.StateCommitment {
+	return cf.expected
+}
+
+// Computed returns the final state commitment computed by the execution node, as provided by the chunk
+func (cf CFNonMatchingFinalState) Computed() flow.StateCommitment {
+	return cf.computed
+}
+
+// DivergingRegisters returns the register updates computed by the verifier which change the value
+// of the register at the start state of the chunk. Returns nil if the registers were not recorded.
+func (cf CFNonMatchingFinalState) DivergingRegisters() flow.RegisterEntries {
+	return cf.diverging
+}
+
 // NewCFNonMatchingFinalState creates a new instance of Chunk Fault (NonMatchingFinalState)
 func NewCFNonMatchingFinalState(expected flow.StateCommitment, computed flow.StateCommitment, chInx uint64, execResID flow.Identifier) *CFNonMatchingFinalState {
 	return &CFNonMatchingFinalState{expected: expected,
@@ -81,6 +109,14 @@ func NewCFNonMatchingFinalState(expected flow.StateCommitment, computed flow.Sta
 		execResID:  execResID}
 }
 
+// NewCFNonMatchingFinalStateWithRegisters creates a new instance of Chunk Fault (NonMatchingFinalState),
+// recording the register updates computed by the verifier which change the start state of the chunk.
+func NewCFNonMatchingFinalStateWithRegisters(expected flow.StateCommitment, computed flow.StateCommitment, chInx uint64, execResID flow.Identifier, diverging flow.RegisterEntries) *CFNonMatchingFinalState {
+	cf := NewCFNonMatchingFinalState(expected, computed, chInx, execResID)
+	cf.diverging = diverging
+	return cf
+}
+
 // CFInvalidEventsCollection is returned when computed events collection hash is different from the chunk's one
 type CFInvalidEventsCollection struct {
 	expected   flow.Identifier
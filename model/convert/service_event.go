@@ -32,6 +32,8 @@ func ServiceEvent(chainID flow.ChainID, event flow.Event) (*flow.ServiceEvent, e
 		return convertServiceEventEpochSetup(event)
 	case events.EpochCommit.EventType():
 		return convertServiceEventEpochCommit(event)
+	case events.EpochRecover.EventType():
+		return convertServiceEventEpochRecover(event)
 	case events.VersionBeacon.EventType():
 		return convertServiceEventVersionBeacon(event)
 	default:
@@ -39,37 +41,131 @@ func ServiceEvent(chainID flow.ChainID, event flow.Event) (*flow.ServiceEvent, e
 	}
 }
 
+// Number of fields of the Cadence events, which are converted to the respective
+// protocol service events.
+const (
+	epochSetupFieldCount   = 9
+	epochCommitFieldCount  = 3
+	epochRecoverFieldCount = 11 // all fields of EpochSetup and EpochCommit, the counter is shared
+)
+
 // convertServiceEventEpochSetup converts a service event encoded as the generic
 // flow.Event type to a ServiceEvent type for an EpochSetup event
 func convertServiceEventEpochSetup(event flow.Event) (*flow.ServiceEvent, error) {
 
+	cdcEvent, err := decodeServiceEventPayload(event, "EpochSetup", epochSetupFieldCount)
+	if err != nil {
+		return nil, err
+	}
+
+	setup, err := convertEpochSetupFields(cdcEvent, "EpochSetup")
+	if err != nil {
+		return nil, err
+	}
+
+	// construct the service event
+	serviceEvent := &flow.ServiceEvent{
+		Type:  flow.ServiceEventSetup,
+		Event: setup,
+	}
+
+	return serviceEvent, nil
+}
+
+// convertServiceEventEpochCommit converts a service event encoded as the generic
+// flow.Event type to a ServiceEvent type for an EpochCommit event
+func convertServiceEventEpochCommit(event flow.Event) (*flow.ServiceEvent, error) {
+
+	cdcEvent, err := decodeServiceEventPayload(event, "EpochCommit", epochCommitFieldCount)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := convertEpochCommitFields(cdcEvent, "EpochCommit")
+	if err != nil {
+		return nil, err
+	}
+
+	// create the service event
+	serviceEvent := &flow.ServiceEvent{
+		Type:  flow.ServiceEventCommit,
+		Event: commit,
+	}
+
+	return serviceEvent, nil
+}
+
+// convertServiceEventEpochRecover converts a service event encoded as the generic
+// flow.Event type to a ServiceEvent type for an EpochRecover event.
+// The EpochRecover event contains all fields of the EpochSetup and EpochCommit
+// events for the recovery epoch.
+func convertServiceEventEpochRecover(event flow.Event) (*flow.ServiceEvent, error) {
+
+	cdcEvent, err := decodeServiceEventPayload(event, "EpochRecover", epochRecoverFieldCount)
+	if err != nil {
+		return nil, err
+	}
+
+	setup, err := convertEpochSetupFields(cdcEvent, "EpochRecover")
+	if err != nil {
+		return nil, err
+	}
+	commit, err := convertEpochCommitFields(cdcEvent, "EpochRecover")
+	if err != nil {
+		return nil, err
+	}
+
+	// create the service event
+	serviceEvent := &flow.ServiceEvent{
+		Type: flow.ServiceEventRecoverEpoch,
+		Event: &flow.EpochRecover{
+			EpochSetup:  *setup,
+			EpochCommit: *commit,
+		},
+	}
+
+	return serviceEvent, nil
+}
+
+// decodeServiceEventPayload decodes the CCF-encoded payload of the given service event
+// and checks that the Cadence event has at least the expected number of fields.
+func decodeServiceEventPayload(event flow.Event, eventName string, expectedFieldCount int) (cadence.Event, error) {
+
 	// decode bytes using ccf
 	payload, err := ccf.Decode(nil, event.Payload)
 	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal event payload: %w", err)
+		return cadence.Event{}, fmt.Errorf("could not unmarshal event payload: %w", err)
 	}
 
 	// NOTE: variable names prefixed with cdc represent cadence types
 	cdcEvent, ok := payload.(cadence.Event)
 	if !ok {
-		return nil, invalidCadenceTypeError("payload", payload, cadence.Event{})
+		return cadence.Event{}, invalidCadenceTypeError("payload", payload, cadence.Event{})
 	}
 
-	const expectedFieldCount = 9
 	if len(cdcEvent.Fields) < expectedFieldCount {
-		return nil, fmt.Errorf(
-			"insufficient fields in EpochSetup event (%d < %d)",
+		return cadence.Event{}, fmt.Errorf(
+			"insufficient fields in %s event (%d < %d)",
+			eventName,
 			len(cdcEvent.Fields),
 			expectedFieldCount,
 		)
 	}
 
 	if cdcEvent.Type() == nil {
-		return nil, fmt.Errorf("EpochSetup event doesn't have type")
+		return cadence.Event{}, fmt.Errorf("%s event doesn't have type", eventName)
 	}
 
+	return cdcEvent, nil
+}
+
+// convertEpochSetupFields converts the fields of the given Cadence event, which
+// specify the setup of an epoch, to an EpochSetup.
+func convertEpochSetupFields(cdcEvent cadence.Event, eventName string) (*flow.EpochSetup, error) {
+
 	// parse EpochSetup event
 
+	var ok bool
 	var counter cadence.UInt64
 	var firstView cadence.UInt64
 	var finalView cadence.UInt64
@@ -186,11 +282,12 @@ func convertServiceEventEpochSetup(event flow.Event) (*flow.ServiceEvent, error)
 		}
 	}
 
-	if foundFieldCount != expectedFieldCount {
+	if foundFieldCount != epochSetupFieldCount {
 		return nil, fmt.Errorf(
-			"EpochSetup event required fields not found (%d != %d)",
+			"%s event required fields not found (%d != %d)",
+			eventName,
 			foundFieldCount,
-			expectedFieldCount,
+			epochSetupFieldCount,
 		)
 	}
 
@@ -210,6 +307,7 @@ func convertServiceEventEpochSetup(event flow.Event) (*flow.ServiceEvent, error)
 		2*flow.EpochSetupRandomSourceLength,
 		string(randomSrcHex),
 	)
+	var err error
 	setup.RandomSource, err = hex.DecodeString(paddedRandomSrcHex)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -231,44 +329,15 @@ func convertServiceEventEpochSetup(event flow.Event) (*flow.ServiceEvent, error)
 		return nil, fmt.Errorf("could not convert participants: %w", err)
 	}
 
-	// construct the service event
-	serviceEvent := &flow.ServiceEvent{
-		Type:  flow.ServiceEventSetup,
-		Event: setup,
-	}
-
-	return serviceEvent, nil
+	return setup, nil
 }
 
-// convertServiceEventEpochCommit converts a service event encoded as the generic
-// flow.Event type to a ServiceEvent type for an EpochCommit event
-func convertServiceEventEpochCommit(event flow.Event) (*flow.ServiceEvent, error) {
-
-	// decode bytes using ccf
-	payload, err := ccf.Decode(nil, event.Payload)
-	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal event payload: %w", err)
-	}
-
-	cdcEvent, ok := payload.(cadence.Event)
-	if !ok {
-		return nil, invalidCadenceTypeError("payload", payload, cadence.Event{})
-	}
-
-	const expectedFieldCount = 3
-	if len(cdcEvent.Fields) < expectedFieldCount {
-		return nil, fmt.Errorf(
-			"insufficient fields in EpochCommit event (%d < %d)",
-			len(cdcEvent.Fields),
-			expectedFieldCount,
-		)
-	}
-
-	if cdcEvent.Type() == nil {
-		return nil, fmt.Errorf("EpochCommit event doesn't have type")
-	}
+// convertEpochCommitFields converts the fields of the given Cadence event, which
+// specify the commitment of an epoch, to an EpochCommit.
+func convertEpochCommitFields(cdcEvent cadence.Event, eventName string) (*flow.EpochCommit, error) {
 
 	// Extract EpochCommit event fields
+	var ok bool
 	var counter cadence.UInt64
 	var cdcClusterQCVotes cadence.Array
 	var cdcDKGKeys cadence.Array
@@ -313,11 +382,12 @@ func convertServiceEventEpochCommit(event flow.Event) (*flow.ServiceEvent, error
 		}
 	}
 
-	if foundFieldCount != expectedFieldCount {
+	if foundFieldCount != epochCommitFieldCount {
 		return nil, fmt.Errorf(
-			"EpochCommit event required fields not found (%d != %d)",
+			"%s event required fields not found (%d != %d)",
+			eventName,
 			foundFieldCount,
-			expectedFieldCount,
+			epochCommitFieldCount,
 		)
 	}
 
@@ -326,6 +396,7 @@ func convertServiceEventEpochCommit(event flow.Event) (*flow.ServiceEvent, error
 	}
 
 	// parse cluster qc votes
+	var err error
 	commit.ClusterQCs, err = convertClusterQCVotes(cdcClusterQCVotes.Values)
 	if err != nil {
 		return nil, fmt.Errorf("could not convert cluster qc votes: %w", err)
@@ -341,13 +412,7 @@ func convertServiceEventEpochCommit(event flow.Event) (*flow.ServiceEvent, error
 	commit.DKGGroupKey = dkgGroupKey
 	commit.DKGParticipantKeys = dkgParticipantKeys
 
-	// create the service event
-	serviceEvent := &flow.ServiceEvent{
-		Type:  flow.ServiceEventCommit,
-		Event: commit,
-	}
-
-	return serviceEvent, nil
+	return commit, nil
 }
 
 // convertClusterAssignments converts the Cadence representation of cluster
@@ -415,14 +415,21 @@ type EpochStatus struct {
 	// incorporated in this fork. When this happens, epoch fallback is triggered
 	// AFTER the fork is finalized.
 	InvalidServiceEventIncorporated bool
+	// NextEpochRecovered encodes whether the next epoch was specified by an
+	// EpochRecover service event incorporated in this fork while epoch fallback
+	// mode was in effect. In this case, the current epoch continues until the
+	// first view of the recovery epoch, which may be later than the current
+	// epoch's final view.
+	NextEpochRecovered bool
 }
 
 // Copy returns a copy of the epoch status.
 func (es *EpochStatus) Copy() *EpochStatus {
 	return &EpochStatus{
-		PreviousEpoch: es.PreviousEpoch,
-		CurrentEpoch:  es.CurrentEpoch,
-		NextEpoch:     es.NextEpoch,
+		PreviousEpoch:      es.PreviousEpoch,
+		CurrentEpoch:       es.CurrentEpoch,
+		NextEpoch:          es.NextEpoch,
+		NextEpochRecovered: es.NextEpochRecovered,
 	}
 }
 
@@ -0,0 +1,38 @@
+package flow
+
+// ArchivedEpoch is the record of an epoch retained by nodes which archive the protocol state. It
+// holds the service events specifying the epoch, which define the identity table of the epoch,
+// together with the heights of the finalized blocks at which the epoch and its phases started.
+//
+// An epoch is archived once its EpochSetup event is finalized. Heights of events which have not
+// been finalized yet are nil. For the epoch containing the root block of the archive, phases which
+// started before the root block are archived as starting at the root block.
+type ArchivedEpoch struct {
+	Setup  *EpochSetup
+	Commit *EpochCommit // nil until the EpochCommit event is finalized
+	// FirstHeight is the height of the first block of the epoch.
+	FirstHeight *uint64
+	// SetupPhaseHeight is the height of the first block of the epoch setup phase of this epoch,
+	// in which the next epoch is being set up.
+	SetupPhaseHeight *uint64
+	// CommittedPhaseHeight is the height of the first block of the epoch committed phase of this
+	// epoch, in which the next epoch has been committed.
+	CommittedPhaseHeight *uint64
+}
+
+// Counter returns the counter of the archived epoch.
+func (e *ArchivedEpoch) Counter() uint64 {
+	return e.Setup.Counter
+}
+
+// PhaseAtHeight returns the phase of the archived epoch at the given height, which must be a height
+// within the epoch.
+func (e *ArchivedEpoch) PhaseAtHeight(height uint64) EpochPhase {
+	if e.CommittedPhaseHeight != nil && height >= *e.CommittedPhaseHeight {
+		return EpochPhaseCommitted
+	}
+	if e.SetupPhaseHeight != nil && height >= *e.SetupPhaseHeight {
+		return EpochPhaseSetup
+	}
+	return EpochPhaseStaking
+}
@@ -0,0 +1,43 @@
+package flow
+
+// EpochRecover is a service event emitted by the service account to leave epoch
+// fallback mode without a spork. It contains the full specification of a recovery
+// epoch, which replaces the epoch the network would have transitioned into, had
+// the epoch preparation protocol completed successfully:
+//   - the EpochSetup contains the participants, cluster assignment and view ranges
+//     of the recovery epoch
+//   - the EpochCommit contains the root QCs of the collector clusters and the
+//     DKG output for the recovery epoch
+//
+// The recovery epoch is only accepted by the protocol state while epoch fallback
+// mode is in effect. It must directly follow the current epoch (counter increased
+// by one) and begin sufficiently far in the future for all nodes to prepare for it.
+type EpochRecover struct {
+	EpochSetup  EpochSetup
+	EpochCommit EpochCommit
+}
+
+func (recovery *EpochRecover) ServiceEvent() ServiceEvent {
+	return ServiceEvent{
+		Type:  ServiceEventRecoverEpoch,
+		Event: recovery,
+	}
+}
+
+// ID returns the hash of the event contents.
+func (recovery *EpochRecover) ID() Identifier {
+	return MakeID(struct {
+		SetupID  Identifier
+		CommitID Identifier
+	}{
+		SetupID:  recovery.EpochSetup.ID(),
+		CommitID: recovery.EpochCommit.ID(),
+	})
+}
+
+func (recovery *EpochRecover) EqualTo(other *EpochRecover) bool {
+	if !recovery.EpochSetup.EqualTo(&other.EpochSetup) {
+		return false
+	}
+	return recovery.EpochCommit.EqualTo(&other.EpochCommit)
+}
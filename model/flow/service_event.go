@@ -22,6 +22,7 @@ const (
 	ServiceEventSetup         ServiceEventType = "setup"
 	ServiceEventCommit        ServiceEventType = "commit"
 	ServiceEventVersionBeacon ServiceEventType = "version-beacon"
+	ServiceEventRecoverEpoch  ServiceEventType = "recover-epoch"
 )
 
 // ServiceEvent represents a service event, which is a special event that when
@@ -132,6 +133,8 @@ func (marshaller marshallerImpl) UnmarshalWithType(
 		event = new(EpochCommit)
 	case ServiceEventVersionBeacon:
 		event = new(VersionBeacon)
+	case ServiceEventRecoverEpoch:
+		event = new(EpochRecover)
 	default:
 		return ServiceEvent{}, fmt.Errorf("invalid type: %s", eventType)
 	}
@@ -236,6 +239,23 @@ func (se *ServiceEvent) EqualTo(other *ServiceEvent) (bool, error) {
 		}
 		return version.EqualTo(otherVersion), nil
 
+	case ServiceEventRecoverEpoch:
+		recovery, ok := se.Event.(*EpochRecover)
+		if !ok {
+			return false, fmt.Errorf(
+				"internal invalid type for ServiceEventRecoverEpoch: %T",
+				se.Event,
+			)
+		}
+		otherRecovery, ok := other.Event.(*EpochRecover)
+		if !ok {
+			return false, fmt.Errorf(
+				"internal invalid type for ServiceEventRecoverEpoch: %T",
+				other.Event,
+			)
+		}
+		return recovery.EqualTo(otherRecovery), nil
+
 	default:
 		return false, fmt.Errorf("unknown serice event type: %s", se.Type)
 	}
@@ -0,0 +1,77 @@
+package flow
+
+import (
+	"github.com/onflow/flow-go/crypto"
+)
+
+// TransactionAdmission is the outcome of submitting a transaction to a collection node.
+type TransactionAdmission uint8
+
+const (
+	// TransactionAdmitted indicates that the transaction was added to the mempool of the
+	// collection node, which is a member of the cluster responsible for the transaction.
+	TransactionAdmitted TransactionAdmission = iota + 1
+	// TransactionDuplicate indicates that the transaction was already in the mempool of
+	// the collection node.
+	TransactionDuplicate
+	// TransactionRejected indicates that the transaction is valid, but was declined by
+	// the mempool of the collection node, for example because the payer reached its cap.
+	// The transaction may be submitted to another member of the responsible cluster.
+	TransactionRejected
+	// TransactionRouted indicates that the collection node is not a member of the cluster
+	// responsible for the transaction, and has propagated it to the responsible cluster.
+	TransactionRouted
+)
+
+// String returns the string representation of the admission outcome.
+func (a TransactionAdmission) String() string {
+	switch a {
+	case TransactionAdmitted:
+		return "admitted"
+	case TransactionDuplicate:
+		return "duplicate"
+	case TransactionRejected:
+		return "rejected"
+	case TransactionRouted:
+		return "routed"
+	default:
+		return "unknown"
+	}
+}
+
+// Accepted returns true if the outcome means that the cluster responsible for the
+// transaction has received it, so it does not need to be submitted again.
+func (a TransactionAdmission) Accepted() bool {
+	return a == TransactionAdmitted || a == TransactionDuplicate || a == TransactionRouted
+}
+
+// TransactionReceiptBody holds the signed part of a transaction acceptance receipt.
+type TransactionReceiptBody struct {
+	TransactionID Identifier           // ID of the submitted transaction
+	ClusterID     Identifier           // fingerprint of the cluster responsible for the transaction
+	Admission     TransactionAdmission // outcome of the submission
+	CollectorID   Identifier           // ID of the collection node issuing the receipt
+}
+
+// ID returns the hash of the receipt body, which is signed by the collection node.
+func (b TransactionReceiptBody) ID() Identifier {
+	return MakeID(b)
+}
+
+// TransactionAcceptanceReceipt is issued by a collection node in response to a transaction
+// submission. It names the cluster responsible for the transaction and the outcome of
+// the submission, and is signed with the staking key of the collection node.
+type TransactionAcceptanceReceipt struct {
+	TransactionReceiptBody
+	CollectorSignature crypto.Signature // signature over the receipt body ID
+}
+
+// ID returns the hash of the receipt body.
+func (r TransactionAcceptanceReceipt) ID() Identifier {
+	return r.TransactionReceiptBody.ID()
+}
+
+// Checksum returns the checksum of the full receipt, including the signature.
+func (r TransactionAcceptanceReceipt) Checksum() Identifier {
+	return MakeID(r)
+}
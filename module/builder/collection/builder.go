@@ -368,6 +368,8 @@ func (b *Builder) buildPayload(buildCtx *blockBuildContext) (*cluster.Payload, e
 	var transactions []*flow.TransactionBody
 	var totalByteSize uint64
 	var totalGas uint64
+	// transactions are considered in the order returned by the mempool, hence a
+	// priority-aware mempool determines which transactions are included first
 	for _, tx := range b.transactions.All() {
 
 		// if we have reached maximum number of transactions, stop
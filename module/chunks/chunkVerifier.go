@@ -1,6 +1,7 @@
 package chunks
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -188,11 +189,12 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(
 	// unknown register tracks access to parts of the partial trie which
 	// are not expanded and values are unknown.
 	unknownRegTouch := make(map[flow.RegisterID]struct{})
+	startSnapshot := executionState.NewLedgerStorageSnapshot(
+		psmt,
+		chunkDataPack.StartState)
 	snapshotTree := snapshot.NewSnapshotTree(
 		&partialLedgerStorageSnapshot{
-			snapshot: executionState.NewLedgerStorageSnapshot(
-				psmt,
-				chunkDataPack.StartState),
+			snapshot:        startSnapshot,
 			unknownRegTouch: unknownRegTouch,
 		})
 	chunkState := fvmState.NewExecutionState(nil, fvmState.DefaultParameters())
@@ -304,7 +306,27 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(
 	// check if the end state commitment mentioned in the chunk matches
 	// what the partial trie is providing.
 	if flow.StateCommitment(expEndStateComm) != endState {
-		return nil, chmodels.NewCFNonMatchingFinalState(flow.StateCommitment(expEndStateComm), endState, chIndex, execResID), nil
+		return nil, chmodels.NewCFNonMatchingFinalStateWithRegisters(
+			flow.StateCommitment(expEndStateComm),
+			endState,
+			chIndex,
+			execResID,
+			divergingRegisters(startSnapshot, chunkExecutionSnapshot.UpdatedRegisters())), nil
 	}
 	return chunkExecutionSnapshot.SpockSecret, nil, nil
 }
+
+// divergingRegisters returns the register updates which change the value of the register at the
+// start state of the chunk. Updates writing back the start value leave the state commitment
+// unchanged, so they cannot account for a final state mismatch and are not worth recording.
+func divergingRegisters(start snapshot.StorageSnapshot, updates flow.RegisterEntries) flow.RegisterEntries {
+	diverging := make(flow.RegisterEntries, 0, len(updates))
+	for _, update := range updates {
+		value, err := start.Get(update.Key)
+		if err == nil && bytes.Equal(value, update.Value) {
+			continue
+		}
+		diverging = append(diverging, update)
+	}
+	return diverging
+}
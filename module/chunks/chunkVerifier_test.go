@@ -135,8 +135,10 @@ func (s *ChunkVerifierTestSuite) TestWrongEndState() {
 	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), chFaults)
 	assert.Nil(s.T(), spockSecret)
-	_, ok := chFaults.(*chunksmodels.CFNonMatchingFinalState)
-	assert.True(s.T(), ok)
+	fault, ok := chFaults.(*chunksmodels.CFNonMatchingFinalState)
+	require.True(s.T(), ok)
+	assert.Equal(s.T(), vch.EndState, fault.Computed())
+	assert.NotEqual(s.T(), vch.EndState, fault.Expected())
 }
 
 // TestFailedTx tests verification behavior in case
@@ -58,6 +58,9 @@ func (cache *epochRangeCache) combinedRange() (firstView uint64, finalView uint6
 
 // add inserts an epoch range to the cache.
 // Validates that epoch counters and view ranges are sequential.
+// A recovery epoch, committed while epoch fallback mode is in effect, may begin after
+// the final view of the latest cached epoch. In this case, the latest cached epoch
+// is extended until the first view of the recovery epoch, as in epoch fallback mode.
 // Adding the same epoch multiple times is a no-op.
 // Guarantees ordering and alignment properties of epochRangeCache are preserved.
 // No errors are expected during normal operation.
@@ -85,11 +88,16 @@ func (cache *epochRangeCache) add(epoch epochRange) error {
 	if epoch.counter != latestCachedEpoch.counter+1 {
 		return fmt.Errorf("non-sequential epoch counters: adding epoch %d when latest cached epoch is %d", epoch.counter, latestCachedEpoch.counter)
 	}
-	if epoch.firstView != latestCachedEpoch.finalView+1 {
+	if epoch.firstView <= latestCachedEpoch.finalView {
 		return fmt.Errorf("non-sequential epoch view ranges: adding range [%d,%d] when latest cached range is [%d,%d]",
 			epoch.firstView, epoch.finalView, latestCachedEpoch.firstView, latestCachedEpoch.finalView)
 	}
 
+	// recovery case - extend the latest cached epoch until the recovery epoch begins
+	if epoch.firstView > latestCachedEpoch.finalView+1 {
+		cache[2].finalView = epoch.firstView - 1
+	}
+
 	// typical case - displacing existing epoch ranges
 	// insert new epoch range, shifting existing epochs left
 	cache[0] = cache[1] // ejects oldest epoch
@@ -280,3 +288,9 @@ func (lookup *EpochLookup) EpochCommittedPhaseStarted(_ uint64, first *flow.Head
 func (lookup *EpochLookup) EpochEmergencyFallbackTriggered() {
 	lookup.epochFallbackIsTriggered.Store(true)
 }
+
+// EpochTransition resets the epoch fallback flag. While epoch fallback is in effect, the
+// only epoch transition is into a recovery epoch, which ends epoch fallback mode.
+func (lookup *EpochLookup) EpochTransition(_ uint64, _ *flow.Header) {
+	lookup.epochFallbackIsTriggered.Store(false)
+}
@@ -237,6 +237,50 @@ func (suite *EpochLookupSuite) TestProtocolEvents_CommittedEpoch() {
 	testEpochForViewWithFallback(suite.T(), suite.lookup, suite.state, suite.currEpoch, suite.nextEpoch)
 }
 
+// TestProtocolEvents_RecoveryEpoch tests correct processing of a recovery epoch being committed
+// while epoch fallback is triggered. The recovery epoch begins after the final view of the current
+// epoch, which is extended until the recovery epoch begins.
+func (suite *EpochLookupSuite) TestProtocolEvents_RecoveryEpoch() {
+	// initially, only current epoch is committed, then epoch fallback is triggered
+	suite.CommitEpochs(suite.currEpoch)
+	suite.CreateAndStartEpochLookup()
+	suite.WithLock(func() {
+		suite.epochFallbackTriggered = true
+	})
+	suite.lookup.EpochEmergencyFallbackTriggered()
+
+	// commit the recovery epoch, and emit a protocol event
+	recoveryEpoch := epochRange{counter: suite.currentEpochCounter + 1, firstView: suite.currEpoch.finalView + 51, finalView: suite.currEpoch.finalView + 150}
+	firstBlockOfCommittedPhase := unittest.BlockHeaderFixture()
+	suite.state.On("AtBlockID", firstBlockOfCommittedPhase.ID()).Return(suite.snapshot)
+	suite.CommitEpochs(recoveryEpoch)
+	suite.lookup.EpochCommittedPhaseStarted(suite.currentEpochCounter, firstBlockOfCommittedPhase)
+
+	// wait for the protocol event to be processed (async)
+	assert.Eventually(suite.T(), func() bool {
+		counter, err := suite.lookup.EpochForViewWithFallback(recoveryEpoch.firstView)
+		return err == nil && counter == recoveryEpoch.counter
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// views between the current and the recovery epoch belong to the current epoch
+	for _, view := range []uint64{suite.currEpoch.finalView + 1, recoveryEpoch.firstView - 1} {
+		counter, err := suite.lookup.EpochForViewWithFallback(view)
+		suite.Require().NoError(err)
+		suite.Assert().Equal(suite.currEpoch.counter, counter)
+	}
+	counter, err := suite.lookup.EpochForViewWithFallback(recoveryEpoch.finalView)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(recoveryEpoch.counter, counter)
+
+	// entering the recovery epoch ends epoch fallback
+	suite.WithLock(func() {
+		suite.epochFallbackTriggered = false
+	})
+	suite.lookup.EpochTransition(recoveryEpoch.counter, unittest.BlockHeaderFixture())
+	_, err = suite.lookup.EpochForViewWithFallback(recoveryEpoch.finalView + 1)
+	suite.Assert().ErrorIs(err, model.ErrViewForUnknownEpoch)
+}
+
 // testEpochForViewWithFallback accepts a constructed EpochLookup and state, and
 // validates correctness by issuing various queries, using the input state and
 // epochs as source of truth.
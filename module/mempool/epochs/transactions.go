@@ -48,6 +48,21 @@ func (t *TransactionPools) ForEpoch(epoch uint64) mempool.Transactions {
 	return pool
 }
 
+// PruneBefore clears and removes the transaction pools of all epochs with a counter
+// smaller than the given epoch.
+func (t *TransactionPools) PruneBefore(epoch uint64) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for counter, pool := range t.pools {
+		if counter < epoch {
+			pool.Clear()
+			delete(t.pools, counter)
+		}
+	}
+}
+
 // CombinedSize returns the sum of the sizes of all transaction pools.
 func (t *TransactionPools) CombinedSize() uint {
 
@@ -82,3 +82,29 @@ func TestCombinedSize(t *testing.T) {
 
 	assert.Equal(t, expected, pools.CombinedSize())
 }
+
+// test that pruning clears and removes the pools of earlier epochs only
+func TestPruneBefore(t *testing.T) {
+
+	create := func(_ uint64) mempool.Transactions {
+		return herocache.NewTransactions(100, unittest.Logger(), metrics.NewNoopCollector())
+	}
+	pools := epochs.NewTransactionPools(create)
+
+	txs := make(map[uint64]flow.TransactionBody)
+	for epoch := uint64(1); epoch <= 3; epoch++ {
+		tx := unittest.TransactionBodyFixture()
+		assert.True(t, pools.ForEpoch(epoch).Add(&tx))
+		txs[epoch] = tx
+	}
+	pruned := pools.ForEpoch(1)
+
+	pools.PruneBefore(2)
+	assert.EqualValues(t, 0, pruned.Size())
+	assert.EqualValues(t, 2, pools.CombinedSize())
+	assert.NotSame(t, pruned, pools.ForEpoch(1))
+	for epoch := uint64(2); epoch <= 3; epoch++ {
+		tx := txs[epoch]
+		assert.True(t, pools.ForEpoch(epoch).Has(tx.ID()))
+	}
+}
@@ -17,7 +17,7 @@ type Transactions struct {
 }
 
 // NewTransactions implements a transactions mempool based on hero cache.
-func NewTransactions(limit uint32, logger zerolog.Logger, collector module.HeroCacheMetrics) *Transactions {
+func NewTransactions(limit uint32, logger zerolog.Logger, collector module.HeroCacheMetrics, opts ...herocache.CacheOpt) *Transactions {
 	t := &Transactions{
 		c: stdmap.NewBackend(
 			stdmap.WithBackData(
@@ -25,12 +25,44 @@ func NewTransactions(limit uint32, logger zerolog.Logger, collector module.HeroC
 					herocache.DefaultOversizeFactor,
 					heropool.LRUEjection,
 					logger.With().Str("mempool", "transactions").Logger(),
-					collector))),
+					collector,
+					opts...))),
 	}
 
 	return t
 }
 
+// WithEjectionCallback returns a cache option which notifies the given callback of every
+// transaction ejected from the mempool to make room for a new transaction.
+// A nil callback is ignored.
+func WithEjectionCallback(onEjected func(*flow.TransactionBody)) herocache.CacheOpt {
+	if onEjected == nil {
+		return func(*herocache.Cache) {}
+	}
+	return herocache.WithTracer(transactionEjectionTracer(onEjected))
+}
+
+// transactionEjectionTracer implements herocache.Tracer, forwarding ejected transactions to a callback.
+type transactionEjectionTracer func(*flow.TransactionBody)
+
+var _ herocache.Tracer = transactionEjectionTracer(nil)
+
+func (t transactionEjectionTracer) EntityEjectionDueToEmergency(ejectedEntity flow.Entity) {
+	t.ejected(ejectedEntity)
+}
+
+func (t transactionEjectionTracer) EntityEjectionDueToFullCapacity(ejectedEntity flow.Entity) {
+	t.ejected(ejectedEntity)
+}
+
+func (t transactionEjectionTracer) ejected(entity flow.Entity) {
+	tx, ok := entity.(flow.TransactionBody)
+	if !ok {
+		panic(fmt.Sprintf("invalid entity in transaction pool (%T)", entity))
+	}
+	t(&tx)
+}
+
 // Has checks whether the transaction with the given hash is currently in
 // the memory pool.
 func (t Transactions) Has(id flow.Identifier) bool {
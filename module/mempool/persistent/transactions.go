@@ -0,0 +1,152 @@
+// Package persistent implements write-through persistence for mempools, so that
+// their contents survive a node restart.
+package persistent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// TransactionValidator validates transactions restored from the database before they
+// are re-added to the mempool. It is satisfied by access.TransactionValidator.
+type TransactionValidator interface {
+	Validate(tx *flow.TransactionBody) error
+}
+
+// Transactions is a write-through persistence layer for the transaction pool of one
+// epoch. Every transaction added to the wrapped mempool is persisted, and every
+// transaction removed or ejected from it is deleted from the database.
+//
+// Persistence is best-effort: failures to write to the database are logged and do not
+// affect the behaviour of the wrapped mempool.
+type Transactions struct {
+	mempool.Transactions
+	log   zerolog.Logger
+	db    *badger.DB
+	epoch uint64
+}
+
+var _ mempool.Transactions = (*Transactions)(nil)
+
+// NewTransactions returns a persistent transaction pool for the given epoch, wrapping the mempool
+// returned by create. The wrapped mempool must notify the given callback of every transaction
+// it ejects, so that ejected transactions are deleted from the database.
+func NewTransactions(
+	log zerolog.Logger,
+	db *badger.DB,
+	epoch uint64,
+	create func(onEjected func(*flow.TransactionBody)) mempool.Transactions,
+) *Transactions {
+	t := &Transactions{
+		log: log.With().
+			Str("mempool", "persistent_transactions").
+			Uint64("epoch", epoch).
+			Logger(),
+		db:    db,
+		epoch: epoch,
+	}
+	t.Transactions = create(t.onEjected)
+	return t
+}
+
+// PersistedEpochs returns the counters of all epochs with persisted transactions, in ascending order.
+// No errors are expected during normal operation.
+func PersistedEpochs(db *badger.DB) ([]uint64, error) {
+	var epochs []uint64
+	err := db.View(operation.RetrievePendingClusterTransactionEpochs(&epochs))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve epochs with persisted transactions: %w", err)
+	}
+	return epochs, nil
+}
+
+// Add adds the transaction to the wrapped mempool and persists it, if it was added.
+func (t *Transactions) Add(tx *flow.TransactionBody) bool {
+	added := t.Transactions.Add(tx)
+	if !added {
+		return false
+	}
+	err := t.db.Update(operation.UpsertPendingClusterTransaction(t.epoch, tx))
+	if err != nil {
+		t.log.Error().Err(err).Hex("tx_id", logging.ID(tx.ID())).Msg("could not persist pending transaction")
+	}
+	return true
+}
+
+// Remove removes the transaction from the wrapped mempool and from the database.
+func (t *Transactions) Remove(txID flow.Identifier) bool {
+	removed := t.Transactions.Remove(txID)
+	err := t.db.Update(operation.RemovePendingClusterTransaction(t.epoch, txID))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		t.log.Error().Err(err).Hex("tx_id", logging.ID(txID)).Msg("could not remove persisted pending transaction")
+	}
+	return removed
+}
+
+// Clear removes all transactions from the wrapped mempool and from the database.
+func (t *Transactions) Clear() {
+	t.Transactions.Clear()
+	err := t.db.Update(operation.RemoveAllPendingClusterTransactions(t.epoch))
+	if err != nil {
+		t.log.Error().Err(err).Msg("could not remove persisted pending transactions")
+	}
+}
+
+// Restore loads the transactions persisted for this epoch, revalidates them and re-adds
+// the valid ones to the wrapped mempool. Transactions which are no longer valid, in
+// particular expired transactions, are deleted from the database.
+//
+// Restore must be called before the mempool is used by other components.
+// Returns the number of restored and dropped transactions.
+// No errors are expected during normal operation.
+func (t *Transactions) Restore(validator TransactionValidator) (restored uint, dropped uint, err error) {
+	var txs []*flow.TransactionBody
+	err = t.db.View(operation.RetrievePendingClusterTransactions(t.epoch, &txs))
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not retrieve persisted pending transactions: %w", err)
+	}
+
+	for _, tx := range txs {
+		txID := tx.ID()
+		err := validator.Validate(tx)
+		if err != nil {
+			t.log.Debug().Err(err).Hex("tx_id", logging.ID(txID)).Msg("dropping persisted transaction which is no longer valid")
+			err = t.db.Update(operation.RemovePendingClusterTransaction(t.epoch, txID))
+			if err != nil {
+				return restored, dropped, fmt.Errorf("could not remove invalid persisted transaction (id=%x): %w", txID, err)
+			}
+			dropped++
+			continue
+		}
+
+		// add to the wrapped mempool directly, the transaction is already persisted
+		if t.Transactions.Add(tx) {
+			restored++
+		}
+	}
+
+	t.log.Info().
+		Uint("restored", restored).
+		Uint("dropped", dropped).
+		Msg("restored persisted transaction pool")
+
+	return restored, dropped, nil
+}
+
+// onEjected deletes a transaction ejected by the wrapped mempool from the database.
+func (t *Transactions) onEjected(tx *flow.TransactionBody) {
+	txID := tx.ID()
+	err := t.db.Update(operation.RemovePendingClusterTransaction(t.epoch, txID))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		t.log.Error().Err(err).Hex("tx_id", logging.ID(txID)).Msg("could not remove persisted ejected transaction")
+	}
+}
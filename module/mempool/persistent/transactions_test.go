@@ -0,0 +1,134 @@
+package persistent_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/module/mempool/herocache"
+	"github.com/onflow/flow-go/module/mempool/persistent"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// validatorFunc implements persistent.TransactionValidator with a function.
+type validatorFunc func(tx *flow.TransactionBody) error
+
+func (f validatorFunc) Validate(tx *flow.TransactionBody) error {
+	return f(tx)
+}
+
+func newPool(db *badger.DB, epoch uint64) *persistent.Transactions {
+	return newPoolWithLimit(db, epoch, 1000)
+}
+
+func newPoolWithLimit(db *badger.DB, epoch uint64, limit uint32) *persistent.Transactions {
+	return persistent.NewTransactions(unittest.Logger(), db, epoch, func(onEjected func(*flow.TransactionBody)) mempool.Transactions {
+		return herocache.NewTransactions(limit, unittest.Logger(), metrics.NewNoopCollector(), herocache.WithEjectionCallback(onEjected))
+	})
+}
+
+var acceptAll = validatorFunc(func(*flow.TransactionBody) error { return nil })
+
+// TestRestore verifies that transactions added to a persistent pool are restored by a new
+// pool for the same epoch, and that transactions failing validation are dropped.
+func TestRestore(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		valid := unittest.TransactionBodyFixture()
+		expired := unittest.TransactionBodyFixture()
+		removed := unittest.TransactionBodyFixture()
+
+		pool := newPool(db, 1)
+		require.True(t, pool.Add(&valid))
+		require.True(t, pool.Add(&expired))
+		require.True(t, pool.Add(&removed))
+		require.True(t, pool.Remove(removed.ID()))
+
+		validator := validatorFunc(func(tx *flow.TransactionBody) error {
+			if tx.ID() == expired.ID() {
+				return fmt.Errorf("expired")
+			}
+			return nil
+		})
+
+		// simulate a restart by creating a new pool on the same database
+		restartedPool := newPool(db, 1)
+		restored, dropped, err := restartedPool.Restore(validator)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, restored)
+		assert.EqualValues(t, 1, dropped)
+		assert.True(t, restartedPool.Has(valid.ID()))
+		assert.False(t, restartedPool.Has(expired.ID()))
+		assert.False(t, restartedPool.Has(removed.ID()))
+
+		// dropped transactions are deleted from the database
+		restored, dropped, err = newPool(db, 1).Restore(validator)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, restored)
+		assert.EqualValues(t, 0, dropped)
+	})
+}
+
+// TestPersistedEpochs verifies that the epochs with persisted transactions are listed, and that
+// restoring the pool of one epoch does not affect the persisted transactions of other epochs.
+func TestPersistedEpochs(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		tx1 := unittest.TransactionBodyFixture()
+		require.True(t, newPool(db, 1).Add(&tx1))
+		tx2 := unittest.TransactionBodyFixture()
+		require.True(t, newPool(db, 2).Add(&tx2))
+
+		epochs, err := persistent.PersistedEpochs(db)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1, 2}, epochs)
+
+		restored, _, err := newPool(db, 2).Restore(acceptAll)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, restored)
+
+		restored, _, err = newPool(db, 1).Restore(acceptAll)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, restored)
+	})
+}
+
+// TestEjection verifies that transactions ejected by the wrapped mempool are deleted from the database.
+func TestEjection(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		const limit = 10
+		pool := newPoolWithLimit(db, 1, limit)
+		for i := 0; i < 3*limit; i++ {
+			tx := unittest.TransactionBodyFixture()
+			require.True(t, pool.Add(&tx))
+		}
+
+		restartedPool := newPoolWithLimit(db, 1, limit)
+		restored, dropped, err := restartedPool.Restore(acceptAll)
+		require.NoError(t, err)
+		assert.EqualValues(t, pool.Size(), restored)
+		assert.EqualValues(t, 0, dropped)
+		assert.ElementsMatch(t, pool.All(), restartedPool.All())
+	})
+}
+
+// TestClear verifies that clearing the pool deletes all persisted transactions.
+func TestClear(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		pool := newPool(db, 1)
+		for i := 0; i < 10; i++ {
+			tx := unittest.TransactionBodyFixture()
+			require.True(t, pool.Add(&tx))
+		}
+		pool.Clear()
+		assert.EqualValues(t, 0, pool.Size())
+
+		restored, _, err := newPool(db, 1).Restore(acceptAll)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, restored)
+	})
+}
@@ -0,0 +1,106 @@
+package priority
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	DefaultMaxPayerTransactions uint    = 1000
+	DefaultAgePromotionInterval         = 10 * time.Second
+	DefaultPayerRankPenalty     float64 = 1
+)
+
+// Config is the configurable options for the priority transaction mempool.
+type Config struct {
+
+	// Policy determines the base priority of each transaction.
+	Policy Policy
+
+	// MaxPayerTransactions is the maximum number of transactions with a common
+	// payer which are held in the mempool at once. Transactions exceeding the cap
+	// are rejected until earlier transactions from the same payer are removed.
+	//
+	// A value of 0 indicates no cap.
+	MaxPayerTransactions uint
+
+	// UnlimitedPayers is a set of addresses which are not affected by the
+	// per-payer cap.
+	UnlimitedPayers map[flow.Address]struct{}
+
+	// AgePromotionInterval is the time after which a pending transaction is promoted
+	// by one full priority level. Transactions are promoted continuously, hence a
+	// transaction is ordered ahead of every transaction of the same payer rank added
+	// at least one interval later, since base priorities are normalized to [0, 1].
+	//
+	// A value of 0 disables age-based promotion.
+	AgePromotionInterval time.Duration
+
+	// PayerRankPenalty is the priority subtracted from each transaction for every
+	// transaction from the same payer which is ordered ahead of it. With a penalty of
+	// at least 1, a payer's second transaction is ordered behind the first transaction
+	// of every other payer of equal age, so that one payer cannot starve others.
+	//
+	// A value of 0 disables payer fairness ordering.
+	PayerRankPenalty float64
+
+	// OnEjected is notified of every transaction evicted from the full mempool to make
+	// room for a transaction with a higher priority. Nil if no notification is needed.
+	OnEjected func(*flow.TransactionBody)
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Policy:               FeePerEffortPolicy{},
+		MaxPayerTransactions: DefaultMaxPayerTransactions,
+		UnlimitedPayers:      make(map[flow.Address]struct{}), // no unlimited payers
+		AgePromotionInterval: DefaultAgePromotionInterval,
+		PayerRankPenalty:     DefaultPayerRankPenalty,
+	}
+}
+
+type Opt func(config *Config)
+
+func WithPolicy(policy Policy) Opt {
+	return func(c *Config) {
+		c.Policy = policy
+	}
+}
+
+func WithMaxPayerTransactions(max uint) Opt {
+	return func(c *Config) {
+		c.MaxPayerTransactions = max
+	}
+}
+
+func WithUnlimitedPayers(payers ...flow.Address) Opt {
+	lookup := make(map[flow.Address]struct{})
+	for _, payer := range payers {
+		lookup[payer] = struct{}{}
+	}
+	return func(c *Config) {
+		c.UnlimitedPayers = lookup
+	}
+}
+
+func WithAgePromotionInterval(interval time.Duration) Opt {
+	return func(c *Config) {
+		c.AgePromotionInterval = interval
+	}
+}
+
+func WithPayerRankPenalty(penalty float64) Opt {
+	return func(c *Config) {
+		if penalty < 0 {
+			penalty = 0
+		}
+		c.PayerRankPenalty = penalty
+	}
+}
+
+func WithEjectionCallback(onEjected func(*flow.TransactionBody)) Opt {
+	return func(c *Config) {
+		c.OnEjected = onEjected
+	}
+}
@@ -0,0 +1,127 @@
+package priority
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// unitInclusionEffort is the inclusion effort of a transaction with the default inclusion
+// effort of 1.0 (in UFix64 representation).
+const unitInclusionEffort = 100_000_000
+
+// Policy determines the base priority of a transaction in the transaction mempool.
+// Transactions with a higher priority are returned first by Transactions.All, and are
+// therefore considered first for inclusion in a collection.
+//
+// Base priorities should be normalized to the range [0, 1], so that they can be
+// combined with the age promotion and payer fairness adjustments applied by the mempool.
+// Implementations must be deterministic and safe for concurrent use.
+type Policy interface {
+	// Name returns the name of the policy, as used in configuration.
+	Name() string
+	// Priority returns the base priority of the transaction.
+	Priority(tx *flow.TransactionBody) float64
+}
+
+const (
+	// PolicyFIFO assigns every transaction the same priority, so that transactions are
+	// ordered by the time they were added to the mempool.
+	PolicyFIFO = "fifo"
+	// PolicyFee prioritises transactions with a higher gas limit, which bounds the
+	// execution fee the payer has committed to pay.
+	PolicyFee = "fee"
+	// PolicyInclusionEffort prioritises transactions which require less inclusion effort.
+	PolicyInclusionEffort = "inclusion-effort"
+	// PolicyFeePerEffort prioritises transactions by the fee the payer has committed to
+	// pay per unit of inclusion effort.
+	PolicyFeePerEffort = "fee-per-effort"
+)
+
+// ParsePolicy returns the Policy with the given name.
+// Returns an error if the name does not correspond to a known policy.
+func ParsePolicy(name string) (Policy, error) {
+	switch name {
+	case PolicyFIFO:
+		return FIFOPolicy{}, nil
+	case PolicyFee:
+		return FeePolicy{}, nil
+	case PolicyInclusionEffort:
+		return InclusionEffortPolicy{}, nil
+	case PolicyFeePerEffort:
+		return FeePerEffortPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown transaction priority policy: %s", name)
+	}
+}
+
+// FIFOPolicy orders transactions only by the time they were added to the mempool.
+type FIFOPolicy struct{}
+
+var _ Policy = FIFOPolicy{}
+
+func (FIFOPolicy) Name() string {
+	return PolicyFIFO
+}
+
+func (FIFOPolicy) Priority(*flow.TransactionBody) float64 {
+	return 0
+}
+
+// FeePolicy orders transactions by their gas limit, relative to the maximum gas limit.
+type FeePolicy struct{}
+
+var _ Policy = FeePolicy{}
+
+func (FeePolicy) Name() string {
+	return PolicyFee
+}
+
+func (FeePolicy) Priority(tx *flow.TransactionBody) float64 {
+	return normalizedGasLimit(tx)
+}
+
+// InclusionEffortPolicy orders transactions by the inverse of their inclusion effort.
+type InclusionEffortPolicy struct{}
+
+var _ Policy = InclusionEffortPolicy{}
+
+func (InclusionEffortPolicy) Name() string {
+	return PolicyInclusionEffort
+}
+
+func (InclusionEffortPolicy) Priority(tx *flow.TransactionBody) float64 {
+	return normalizedInverseEffort(tx)
+}
+
+// FeePerEffortPolicy orders transactions by their gas limit per unit of inclusion effort.
+type FeePerEffortPolicy struct{}
+
+var _ Policy = FeePerEffortPolicy{}
+
+func (FeePerEffortPolicy) Name() string {
+	return PolicyFeePerEffort
+}
+
+func (FeePerEffortPolicy) Priority(tx *flow.TransactionBody) float64 {
+	return normalizedGasLimit(tx) * normalizedInverseEffort(tx)
+}
+
+// normalizedGasLimit returns the gas limit of the transaction relative to the maximum
+// transaction gas limit, capped at 1.
+func normalizedGasLimit(tx *flow.TransactionBody) float64 {
+	if tx.GasLimit >= flow.DefaultMaxTransactionGasLimit {
+		return 1
+	}
+	return float64(tx.GasLimit) / float64(flow.DefaultMaxTransactionGasLimit)
+}
+
+// normalizedInverseEffort returns the inverse of the transaction's inclusion effort,
+// in units of the default inclusion effort, capped at 1.
+func normalizedInverseEffort(tx *flow.TransactionBody) float64 {
+	effort := tx.InclusionEffort()
+	if effort <= unitInclusionEffort {
+		return 1
+	}
+	return float64(unitInclusionEffort) / float64(effort)
+}
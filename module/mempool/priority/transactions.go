@@ -0,0 +1,375 @@
+package priority
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+)
+
+// entry is a transaction held in the priority mempool, together with the
+// metadata needed to order it.
+type entry struct {
+	tx    *flow.TransactionBody
+	id    flow.Identifier
+	key   float64     // age-adjusted priority, relative to the creation of the mempool
+	seq   uint64      // insertion sequence number, used to break ties in FIFO order
+	queue *payerQueue // queue of the transactions with the same payer
+}
+
+// Transactions implements a priority-aware transaction mempool for collection nodes.
+//
+// Transactions are returned by All in descending order of their effective priority,
+// which combines:
+//   - the base priority assigned by the configured Policy
+//   - an age-based promotion, so that low-priority transactions are eventually included
+//   - a per-payer rank penalty, so that many transactions from one payer cannot starve
+//     transactions from other payers
+//
+// Additionally, the number of transactions per payer held in the mempool can be capped.
+// Ties are broken by insertion order, hence with the FIFO policy and no fairness
+// settings the mempool behaves like an insertion-ordered pool.
+//
+// Since every transaction is promoted at the same rate, the age-adjusted order of two
+// transactions never changes while they are in the mempool. The transactions of each
+// payer are therefore kept in a sorted queue, and the payers in a heap ordered by the
+// effective priority of their last transaction, which is the transaction to evict first.
+//
+// Transactions is safe for concurrent use.
+type Transactions struct {
+	mu      sync.RWMutex
+	config  Config
+	limit   uint
+	now     func() time.Time
+	created time.Time // reference time of the age-adjusted priorities
+	nextSeq uint64
+	entries map[flow.Identifier]*entry
+	payers  map[flow.Address]*payerQueue
+	victims payerHeap // payers ordered by increasing effective priority of their last transaction
+}
+
+var _ mempool.Transactions = (*Transactions)(nil)
+
+// NewTransactions returns a new priority transaction mempool holding at most limit transactions.
+// When the mempool is full, a new transaction replaces the transaction with the lowest
+// effective priority, if the new transaction has a strictly higher effective priority.
+func NewTransactions(limit uint, opts ...Opt) *Transactions {
+	config := DefaultConfig()
+	for _, apply := range opts {
+		apply(&config)
+	}
+	if config.Policy == nil {
+		config.Policy = FIFOPolicy{}
+	}
+
+	t := &Transactions{
+		config: config,
+		limit:  limit,
+		now:    time.Now,
+	}
+	t.reset()
+	return t
+}
+
+// Has checks whether the transaction with the given ID is currently in the mempool.
+func (t *Transactions) Has(txID flow.Identifier) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.entries[txID]
+	return ok
+}
+
+// Add adds a transaction to the mempool. It returns false if the transaction was
+// already in the mempool, if the payer has reached its cap, or if the mempool is full
+// and the transaction does not have a higher effective priority than any stored transaction.
+func (t *Transactions) Add(tx *flow.TransactionBody) bool {
+	txID := tx.ID()
+	priority := t.config.Policy.Priority(tx)
+
+	// notify about the evicted transaction after releasing the lock
+	var ejected *flow.TransactionBody
+	defer func() {
+		if ejected != nil && t.config.OnEjected != nil {
+			t.config.OnEjected(ejected)
+		}
+	}()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.entries[txID]; ok {
+		return false
+	}
+
+	queue, ok := t.payers[tx.Payer]
+	if ok && t.config.MaxPayerTransactions > 0 && !t.isUnlimited(tx.Payer) {
+		if uint(len(queue.entries)) >= t.config.MaxPayerTransactions {
+			return false
+		}
+	}
+
+	e := &entry{
+		tx:  tx,
+		id:  txID,
+		key: t.agedPriority(priority, t.now()),
+		seq: t.nextSeq,
+	}
+
+	if t.limit > 0 && uint(len(t.entries)) >= t.limit {
+		// the new transaction would be ranked behind the transactions of its payer
+		// with a higher priority, hence it is subject to the same penalty
+		effective := e.key
+		if ok {
+			effective -= float64(queue.position(e)) * queue.penalty
+		}
+		lowest := t.victims[0]
+		if lowest.effective(len(lowest.entries)-1) >= effective {
+			return false
+		}
+		victim := lowest.last()
+		t.remove(victim)
+		ejected = victim.tx
+		queue, ok = t.payers[tx.Payer]
+	}
+
+	if !ok {
+		queue = &payerQueue{penalty: t.config.PayerRankPenalty}
+		if t.isUnlimited(tx.Payer) {
+			queue.penalty = 0
+		}
+		t.payers[tx.Payer] = queue
+		queue.insert(e)
+		heap.Push(&t.victims, queue)
+	} else {
+		queue.insert(e)
+		heap.Fix(&t.victims, queue.index)
+	}
+
+	t.entries[txID] = e
+	t.nextSeq++
+
+	return true
+}
+
+// Remove removes the transaction with the given ID from the mempool. It returns
+// true if the transaction was known and removed.
+func (t *Transactions) Remove(txID flow.Identifier) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.entries[txID]
+	if !ok {
+		return false
+	}
+	t.remove(e)
+	return true
+}
+
+// ByID returns the transaction with the given ID from the mempool.
+func (t *Transactions) ByID(txID flow.Identifier) (*flow.TransactionBody, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	e, ok := t.entries[txID]
+	if !ok {
+		return nil, false
+	}
+	return e.tx, true
+}
+
+// Size returns the number of transactions in the mempool.
+func (t *Transactions) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return uint(len(t.entries))
+}
+
+// All returns all transactions in the mempool, ordered by descending effective priority.
+// The sorted queues of the payers are merged, hence no sorting of the whole mempool is required.
+func (t *Transactions) All() []*flow.TransactionBody {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	cursors := make(cursorHeap, 0, len(t.payers))
+	for _, queue := range t.payers {
+		cursors = append(cursors, &cursor{queue: queue})
+	}
+	heap.Init(&cursors)
+
+	txs := make([]*flow.TransactionBody, 0, len(t.entries))
+	for len(cursors) > 0 {
+		next := cursors[0]
+		txs = append(txs, next.queue.entries[next.rank].tx)
+		next.rank++
+		if next.rank == len(next.queue.entries) {
+			heap.Pop(&cursors)
+			continue
+		}
+		heap.Fix(&cursors, 0)
+	}
+	return txs
+}
+
+// Clear removes all transactions from the mempool.
+func (t *Transactions) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reset()
+}
+
+// reset empties the mempool.
+// CAUTION: not concurrency safe, the caller must hold the write lock.
+func (t *Transactions) reset() {
+	t.created = t.now()
+	t.entries = make(map[flow.Identifier]*entry)
+	t.payers = make(map[flow.Address]*payerQueue)
+	t.victims = nil
+}
+
+// remove removes the entry from the mempool.
+// CAUTION: not concurrency safe, the caller must hold the write lock.
+func (t *Transactions) remove(e *entry) {
+	delete(t.entries, e.id)
+	queue := e.queue
+	queue.remove(e)
+	if len(queue.entries) == 0 {
+		heap.Remove(&t.victims, queue.index)
+		delete(t.payers, e.tx.Payer)
+		return
+	}
+	heap.Fix(&t.victims, queue.index)
+}
+
+// agedPriority returns the given base priority of a transaction added at the given time,
+// promoted by one priority level for every AgePromotionInterval the transaction was added
+// before any transaction added at the creation of the mempool. Transactions are promoted
+// continuously, so that the difference between the aged priorities of two transactions
+// is the difference of their base priorities, adjusted for the time between their additions.
+func (t *Transactions) agedPriority(priority float64, added time.Time) float64 {
+	if t.config.AgePromotionInterval <= 0 {
+		return priority
+	}
+	return priority - float64(added.Sub(t.created))/float64(t.config.AgePromotionInterval)
+}
+
+// isUnlimited returns true if the payer is exempt from the per-payer rules.
+func (t *Transactions) isUnlimited(payer flow.Address) bool {
+	_, ok := t.config.UnlimitedPayers[payer]
+	return ok
+}
+
+// behind returns true if entry a with priority pa should be ordered behind entry b
+// with priority pb. Entries of equal priority are ordered by insertion sequence.
+func behind(a *entry, pa float64, b *entry, pb float64) bool {
+	if pa != pb {
+		return pa < pb
+	}
+	return a.seq > b.seq
+}
+
+// payerQueue holds the transactions of one payer, ordered by descending age-adjusted priority.
+// The effective priority of a transaction is its age-adjusted priority, reduced by the penalty
+// for every transaction of the payer ordered ahead of it.
+type payerQueue struct {
+	entries []*entry
+	penalty float64 // rank penalty of the payer, 0 for unlimited payers
+	index   int     // index of the queue in the victims heap
+}
+
+// effective returns the effective priority of the entry with the given rank.
+func (q *payerQueue) effective(rank int) float64 {
+	return q.entries[rank].key - float64(rank)*q.penalty
+}
+
+// last returns the entry with the lowest effective priority of the payer.
+func (q *payerQueue) last() *entry {
+	return q.entries[len(q.entries)-1]
+}
+
+// position returns the rank at which the entry is or would be held in the queue.
+func (q *payerQueue) position(e *entry) int {
+	return sort.Search(len(q.entries), func(i int) bool {
+		return !behind(e, e.key, q.entries[i], q.entries[i].key)
+	})
+}
+
+// insert adds the entry to the queue, at the rank of its age-adjusted priority.
+func (q *payerQueue) insert(e *entry) {
+	rank := q.position(e)
+	q.entries = append(q.entries, nil)
+	copy(q.entries[rank+1:], q.entries[rank:])
+	q.entries[rank] = e
+	e.queue = q
+}
+
+// remove removes the entry from the queue.
+func (q *payerQueue) remove(e *entry) {
+	rank := q.position(e)
+	copy(q.entries[rank:], q.entries[rank+1:])
+	q.entries[len(q.entries)-1] = nil
+	q.entries = q.entries[:len(q.entries)-1]
+}
+
+// payerHeap implements heap.Interface, ordering payer queues by increasing effective priority
+// of their last transaction, so that the transaction to evict is the last of the first queue.
+type payerHeap []*payerQueue
+
+func (h payerHeap) Len() int { return len(h) }
+
+func (h payerHeap) Less(i, j int) bool {
+	ri, rj := len(h[i].entries)-1, len(h[j].entries)-1
+	return behind(h[i].entries[ri], h[i].effective(ri), h[j].entries[rj], h[j].effective(rj))
+}
+
+func (h payerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *payerHeap) Push(x interface{}) {
+	queue := x.(*payerQueue)
+	queue.index = len(*h)
+	*h = append(*h, queue)
+}
+
+func (h *payerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	queue := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return queue
+}
+
+// cursor is the rank of the next transaction of a payer to be returned by All.
+type cursor struct {
+	queue *payerQueue
+	rank  int
+}
+
+// cursorHeap implements heap.Interface, ordering cursors by decreasing effective priority
+// of their next transaction.
+type cursorHeap []*cursor
+
+func (h cursorHeap) Len() int { return len(h) }
+
+func (h cursorHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	return behind(b.queue.entries[b.rank], b.queue.effective(b.rank), a.queue.entries[a.rank], a.queue.effective(a.rank))
+}
+
+func (h cursorHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *cursorHeap) Push(x interface{}) { *h = append(*h, x.(*cursor)) }
+
+func (h *cursorHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	c := old[n-1]
+	*h = old[:n-1]
+	return c
+}
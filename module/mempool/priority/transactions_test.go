@@ -0,0 +1,217 @@
+package priority
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// txFixture returns a transaction with the given payer and gas limit.
+func txFixture(payer flow.Address, gasLimit uint64) *flow.TransactionBody {
+	tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.Payer = payer
+		tx.GasLimit = gasLimit
+	})
+	return &tx
+}
+
+// testClock is a manually advanced clock.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestPool(limit uint, opts ...Opt) (*Transactions, *testClock) {
+	clock := &testClock{now: time.Now()}
+	pool := NewTransactions(limit, opts...)
+	pool.now = clock.Now
+	return pool, clock
+}
+
+func TestTransactionPool(t *testing.T) {
+	pool, _ := newTestPool(1000)
+	tx1 := txFixture(unittest.RandomAddressFixture(), 100)
+	tx2 := txFixture(unittest.RandomAddressFixture(), 100)
+
+	assert.True(t, pool.Add(tx1))
+	assert.True(t, pool.Add(tx2))
+	assert.False(t, pool.Add(tx1), "should not add duplicate")
+	assert.EqualValues(t, 2, pool.Size())
+	assert.True(t, pool.Has(tx1.ID()))
+
+	actual, ok := pool.ByID(tx2.ID())
+	require.True(t, ok)
+	assert.Equal(t, tx2, actual)
+
+	assert.True(t, pool.Remove(tx2.ID()))
+	assert.False(t, pool.Remove(tx2.ID()))
+	assert.Equal(t, []*flow.TransactionBody{tx1}, pool.All())
+
+	pool.Clear()
+	assert.EqualValues(t, 0, pool.Size())
+	assert.Empty(t, pool.All())
+}
+
+// TestOrdering_FIFO verifies that with the FIFO policy and no fairness settings
+// transactions are returned in insertion order.
+func TestOrdering_FIFO(t *testing.T) {
+	pool, _ := newTestPool(1000, WithPolicy(FIFOPolicy{}), WithPayerRankPenalty(0), WithAgePromotionInterval(0))
+	payer := unittest.RandomAddressFixture()
+
+	expected := make([]*flow.TransactionBody, 0, 10)
+	for i := 0; i < 10; i++ {
+		tx := txFixture(payer, uint64(i))
+		require.True(t, pool.Add(tx))
+		expected = append(expected, tx)
+	}
+	assert.Equal(t, expected, pool.All())
+}
+
+// TestOrdering_Fee verifies that transactions are ordered by descending fee priority.
+func TestOrdering_Fee(t *testing.T) {
+	pool, _ := newTestPool(1000, WithPolicy(FeePolicy{}), WithPayerRankPenalty(0))
+
+	low := txFixture(unittest.RandomAddressFixture(), 10)
+	mid := txFixture(unittest.RandomAddressFixture(), 100)
+	high := txFixture(unittest.RandomAddressFixture(), 1000)
+	require.True(t, pool.Add(low))
+	require.True(t, pool.Add(high))
+	require.True(t, pool.Add(mid))
+
+	assert.Equal(t, []*flow.TransactionBody{high, mid, low}, pool.All())
+}
+
+// TestOrdering_PayerFairness verifies that many high-priority transactions from a single
+// payer do not starve a lower-priority transaction from another payer.
+func TestOrdering_PayerFairness(t *testing.T) {
+	pool, _ := newTestPool(1000, WithPolicy(FeePolicy{}))
+
+	spammer := unittest.RandomAddressFixture()
+	for i := 0; i < 100; i++ {
+		require.True(t, pool.Add(txFixture(spammer, flow.DefaultMaxTransactionGasLimit)))
+	}
+	other := txFixture(unittest.RandomAddressFixture(), 10)
+	require.True(t, pool.Add(other))
+
+	all := pool.All()
+	require.Len(t, all, 101)
+	assert.Equal(t, spammer, all[0].Payer)
+	assert.Equal(t, other, all[1])
+}
+
+// TestOrdering_AgePromotion verifies that a low-priority transaction is promoted above
+// newer high-priority transactions once it has waited long enough.
+func TestOrdering_AgePromotion(t *testing.T) {
+	pool, clock := newTestPool(1000, WithPolicy(FeePolicy{}), WithAgePromotionInterval(time.Second))
+
+	old := txFixture(unittest.RandomAddressFixture(), 1)
+	require.True(t, pool.Add(old))
+
+	clock.Advance(50 * time.Millisecond)
+	fresh := txFixture(unittest.RandomAddressFixture(), 1000)
+	require.True(t, pool.Add(fresh))
+	assert.Equal(t, []*flow.TransactionBody{fresh, old}, pool.All())
+
+	clock.Advance(time.Second)
+	later := txFixture(unittest.RandomAddressFixture(), 1000)
+	require.True(t, pool.Add(later))
+	assert.Equal(t, []*flow.TransactionBody{fresh, old, later}, pool.All())
+}
+
+// TestMaxPayerTransactions verifies that the per-payer cap is enforced, except for unlimited payers.
+func TestMaxPayerTransactions(t *testing.T) {
+	payer := unittest.RandomAddressFixture()
+	unlimited := unittest.RandomAddressFixture()
+	pool, _ := newTestPool(1000, WithMaxPayerTransactions(2), WithUnlimitedPayers(unlimited))
+
+	first := txFixture(payer, 1)
+	require.True(t, pool.Add(first))
+	require.True(t, pool.Add(txFixture(payer, 2)))
+	assert.False(t, pool.Add(txFixture(payer, 3)), "should reject transaction exceeding payer cap")
+
+	// removing a transaction frees up capacity for the payer
+	require.True(t, pool.Remove(first.ID()))
+	assert.True(t, pool.Add(txFixture(payer, 3)))
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, pool.Add(txFixture(unlimited, uint64(i))))
+	}
+}
+
+// TestLimit verifies that a full mempool replaces its lowest-priority transaction only
+// with a transaction of strictly higher priority.
+func TestLimit(t *testing.T) {
+	pool, _ := newTestPool(2, WithPolicy(FeePolicy{}), WithAgePromotionInterval(0))
+
+	low := txFixture(unittest.RandomAddressFixture(), 10)
+	high := txFixture(unittest.RandomAddressFixture(), 1000)
+	require.True(t, pool.Add(low))
+	require.True(t, pool.Add(high))
+
+	assert.False(t, pool.Add(txFixture(unittest.RandomAddressFixture(), 10)))
+	assert.True(t, pool.Has(low.ID()))
+
+	higher := txFixture(unittest.RandomAddressFixture(), 100)
+	assert.True(t, pool.Add(higher))
+	assert.False(t, pool.Has(low.ID()))
+	assert.EqualValues(t, 2, pool.Size())
+}
+
+// TestLimit_PayerFairness verifies that a full mempool evicts the transactions of a payer
+// flooding the mempool, before transactions of other payers with a lower base priority.
+func TestLimit_PayerFairness(t *testing.T) {
+	pool, _ := newTestPool(10, WithPolicy(FeePolicy{}), WithAgePromotionInterval(0))
+
+	other := txFixture(unittest.RandomAddressFixture(), 10)
+	require.True(t, pool.Add(other))
+
+	spammer := unittest.RandomAddressFixture()
+	for i := 0; i < 20; i++ {
+		pool.Add(txFixture(spammer, flow.DefaultMaxTransactionGasLimit))
+	}
+	assert.EqualValues(t, 10, pool.Size())
+	assert.True(t, pool.Has(other.ID()))
+
+	newcomer := txFixture(unittest.RandomAddressFixture(), 10)
+	assert.True(t, pool.Add(newcomer))
+	assert.True(t, pool.Has(other.ID()))
+	assert.EqualValues(t, 10, pool.Size())
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, name := range []string{PolicyFIFO, PolicyFee, PolicyInclusionEffort, PolicyFeePerEffort} {
+		policy, err := ParsePolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, policy.Name())
+	}
+	_, err := ParsePolicy("unknown")
+	assert.Error(t, err)
+}
+
+// TestLimit_EjectionCallback verifies that transactions evicted from a full mempool are reported.
+func TestLimit_EjectionCallback(t *testing.T) {
+	var ejected []*flow.TransactionBody
+	pool, _ := newTestPool(1, WithPolicy(FeePolicy{}), WithEjectionCallback(func(tx *flow.TransactionBody) {
+		ejected = append(ejected, tx)
+	}))
+
+	low := txFixture(unittest.RandomAddressFixture(), 10)
+	require.True(t, pool.Add(low))
+	assert.False(t, pool.Add(txFixture(unittest.RandomAddressFixture(), 1)))
+	assert.Empty(t, ejected)
+
+	require.True(t, pool.Add(txFixture(unittest.RandomAddressFixture(), 100)))
+	assert.Equal(t, []*flow.TransactionBody{low}, ejected)
+}
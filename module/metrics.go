@@ -187,6 +187,7 @@ type NetworkCoreMetrics interface {
 	NetworkInboundQueueMetrics
 	AlspMetrics
 	NetworkSecurityMetrics
+	NetworkCompressionMetrics
 
 	// OutboundMessageSent collects metrics related to a message sent by the node.
 	OutboundMessageSent(sizeBytes int, topic string, protocol string, messageType string)
@@ -226,6 +227,17 @@ type AlspMetrics interface {
 	OnMisbehaviorReported(channel string, misbehaviorType string)
 }
 
+// NetworkCompressionMetrics encapsulates the metrics collectors for the per-channel compression of messages.
+type NetworkCompressionMetrics interface {
+	// OnMessageCompressed is called when an outgoing message was compressed.
+	// Args:
+	// - channel: the channel the message was sent on
+	// - codec: the compression codec negotiated for the message
+	// - uncompressedSize: the size of the message before compression, in bytes
+	// - compressedSize: the size of the message after compression, in bytes
+	OnMessageCompressed(channel string, codec string, uncompressedSize int, compressedSize int)
+}
+
 // NetworkMetrics is the blanket abstraction that encapsulates the metrics collectors for the networking layer.
 type NetworkMetrics interface {
 	LibP2PMetrics
@@ -266,12 +278,23 @@ type ComplianceMetrics interface {
 	CurrentDKGPhase2FinalView(view uint64)
 	CurrentDKGPhase3FinalView(view uint64)
 	EpochEmergencyFallbackTriggered()
+	EpochEmergencyFallbackRecovered()
 }
 
 type CleanerMetrics interface {
 	RanGC(took time.Duration)
 }
 
+// DatabaseSizeMetrics reports the disk usage of the protocol database per key prefix.
+type DatabaseSizeMetrics interface {
+	// PrefixSize reports the number of keys and the total size of the keys and values in bytes
+	// stored under the given prefix.
+	PrefixSize(prefix string, keys uint64, sizeBytes uint64)
+
+	// RanCompaction records a successful online compaction of the database.
+	RanCompaction(took time.Duration)
+}
+
 type CacheMetrics interface {
 	// CacheEntries report the total number of cached items
 	CacheEntries(resource string, entries uint)
@@ -374,6 +397,11 @@ type CollectionMetrics interface {
 	// a tx->col span for the transaction.
 	TransactionIngested(txID flow.Identifier)
 
+	// TransactionRejected is called when a valid transaction is declined by the
+	// transaction pool of the node, for example because its payer has reached the
+	// per-payer cap. It increments the total count of rejected transactions.
+	TransactionRejected(txID flow.Identifier)
+
 	// ClusterBlockProposed is called when a new collection is proposed by us or
 	// any other node in the cluster.
 	ClusterBlockProposed(block *cluster.Block)
@@ -473,6 +501,18 @@ type VerificationMetrics interface {
 	// OnResultApprovalDispatchedInNetwork increments a counter that keeps track of number of result approvals dispatched in the network
 	// by verifier engine.
 	OnResultApprovalDispatchedInNetworkByVerifier()
+
+	// SetVerifierQueueDepth sets a gauge that keeps track of number of verifiable chunks of the given priority class waiting
+	// at verifier engine for a verification slot.
+	SetVerifierQueueDepth(priorityClass string, depth int)
+
+	// SetVerifierChunksInProgress sets a gauge that keeps track of number of verifiable chunks being verified in parallel
+	// by verifier engine.
+	SetVerifierChunksInProgress(count int)
+
+	// OnChunkVerifiedAtVerifier records the time a verifiable chunk waited for a verification slot at verifier engine, and
+	// the time it took to verify it.
+	OnChunkVerifiedAtVerifier(wait time.Duration, verification time.Duration)
 }
 
 // LedgerMetrics provides an interface to record Ledger Storage metrics.
@@ -548,6 +588,10 @@ type BitswapMetrics interface {
 	DupBlobsReceived(prefix string, n uint64)
 	DupDataReceived(prefix string, n uint64)
 	MessagesReceived(prefix string, n uint64)
+	// TopPeersBandwidth records the total bytes of blob data received from and sent to the peers with the most data
+	// exchanged, keyed by peer ID. The peers replace those previously recorded for the prefix, so that the number of
+	// reported peers stays bounded.
+	TopPeersBandwidth(prefix string, received map[string]uint64, sent map[string]uint64)
 }
 
 type ExecutionDataRequesterMetrics interface {
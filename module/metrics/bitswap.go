@@ -15,6 +15,8 @@ type BitswapCollector struct {
 	dupBlobsReceived *prometheus.GaugeVec
 	dupDataReceived  *prometheus.GaugeVec
 	messagesReceived *prometheus.GaugeVec
+	peerDataReceived *prometheus.GaugeVec
+	peerDataSent     *prometheus.GaugeVec
 }
 
 func NewBitswapCollector() *BitswapCollector {
@@ -73,6 +75,18 @@ func NewBitswapCollector() *BitswapCollector {
 			Subsystem: subsystemBitswap,
 			Help:      "the number of messages received",
 		}, []string{"prefix"}),
+		peerDataReceived: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "peer_data_received_bytes",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemBitswap,
+			Help:      "the amount of blob data received from each of the peers with the most data exchanged",
+		}, []string{LabelPrefix, LabelPeerID}),
+		peerDataSent: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "peer_data_sent_bytes",
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemBitswap,
+			Help:      "the amount of blob data sent to each of the peers with the most data exchanged",
+		}, []string{LabelPrefix, LabelPeerID}),
 	}
 
 	return bc
@@ -113,3 +127,16 @@ func (bc *BitswapCollector) DupDataReceived(prefix string, n uint64) {
 func (bc *BitswapCollector) MessagesReceived(prefix string, n uint64) {
 	bc.messagesReceived.WithLabelValues(prefix).Set(float64(n))
 }
+
+// TopPeersBandwidth records the blob data exchanged with the given peers, removing the peers previously recorded for
+// the prefix which are no longer among them.
+func (bc *BitswapCollector) TopPeersBandwidth(prefix string, received map[string]uint64, sent map[string]uint64) {
+	bc.peerDataReceived.DeletePartialMatch(prometheus.Labels{LabelPrefix: prefix})
+	bc.peerDataSent.DeletePartialMatch(prometheus.Labels{LabelPrefix: prefix})
+	for peerID, n := range received {
+		bc.peerDataReceived.WithLabelValues(prefix, peerID).Set(float64(n))
+	}
+	for peerID, n := range sent {
+		bc.peerDataSent.WithLabelValues(prefix, peerID).Set(float64(n))
+	}
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBitswapCollector_TopPeersBandwidth tests that the bandwidth of the top peers is reported per peer, and that peers
+// which are no longer among the top peers of a prefix are removed, while the peers of other prefixes are kept.
+func TestBitswapCollector_TopPeersBandwidth(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = reg
+	collector := NewBitswapCollector()
+
+	// reported returns the reported values of the metric with the given name, keyed by prefix and peer ID
+	reported := func(name string) map[[2]string]float64 {
+		families, err := reg.Gather()
+		require.NoError(t, err)
+
+		values := make(map[[2]string]float64)
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				var key [2]string
+				for _, label := range metric.GetLabel() {
+					switch label.GetName() {
+					case LabelPrefix:
+						key[0] = label.GetValue()
+					case LabelPeerID:
+						key[1] = label.GetValue()
+					}
+				}
+				values[key] = metric.GetGauge().GetValue()
+			}
+		}
+		return values
+	}
+	received := "network_bitswap_peer_data_received_bytes"
+	sent := "network_bitswap_peer_data_sent_bytes"
+
+	collector.TopPeersBandwidth("a", map[string]uint64{"peer-1": 10, "peer-2": 20}, map[string]uint64{"peer-1": 1, "peer-2": 2})
+	collector.TopPeersBandwidth("b", map[string]uint64{"peer-1": 30}, map[string]uint64{"peer-1": 3})
+	assert.Equal(t, map[[2]string]float64{
+		{"a", "peer-1"}: 10,
+		{"a", "peer-2"}: 20,
+		{"b", "peer-1"}: 30,
+	}, reported(received))
+	assert.Equal(t, map[[2]string]float64{
+		{"a", "peer-1"}: 1,
+		{"a", "peer-2"}: 2,
+		{"b", "peer-1"}: 3,
+	}, reported(sent))
+
+	// peer-1 departed from the top peers of prefix a
+	collector.TopPeersBandwidth("a", map[string]uint64{"peer-2": 25, "peer-3": 15}, map[string]uint64{"peer-2": 5, "peer-3": 0})
+	assert.Equal(t, map[[2]string]float64{
+		{"a", "peer-2"}: 25,
+		{"a", "peer-3"}: 15,
+		{"b", "peer-1"}: 30,
+	}, reported(received))
+	assert.Equal(t, map[[2]string]float64{
+		{"a", "peer-2"}: 5,
+		{"a", "peer-3"}: 0,
+		{"b", "peer-1"}: 3,
+	}, reported(sent))
+}
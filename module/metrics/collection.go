@@ -12,6 +12,7 @@ import (
 type CollectionCollector struct {
 	tracer               module.Tracer
 	transactionsIngested prometheus.Counter       // tracks the number of ingested transactions
+	transactionsRejected prometheus.Counter       // tracks the number of transactions declined by the transaction pool
 	finalizedHeight      *prometheus.GaugeVec     // tracks the finalized height
 	proposals            *prometheus.HistogramVec // tracks the number/size of PROPOSED collections
 	guarantees           *prometheus.HistogramVec // counts the number/size of FINALIZED collections
@@ -28,6 +29,12 @@ func NewCollectionCollector(tracer module.Tracer) *CollectionCollector {
 			Help:      "count of transactions ingested by this node",
 		}),
 
+		transactionsRejected: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespaceCollection,
+			Name:      "rejected_transactions_total",
+			Help:      "count of valid transactions declined by the transaction pool of this node",
+		}),
+
 		finalizedHeight: promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespaceCollection,
 			Subsystem: subsystemProposal,
@@ -61,6 +68,11 @@ func (cc *CollectionCollector) TransactionIngested(txID flow.Identifier) {
 	cc.transactionsIngested.Inc()
 }
 
+// TransactionRejected counts a valid transaction which was declined by the transaction pool.
+func (cc *CollectionCollector) TransactionRejected(txID flow.Identifier) {
+	cc.transactionsRejected.Inc()
+}
+
 // ClusterBlockProposed tracks the size and number of proposals, as well as
 // starting the collection->guarantee span.
 func (cc *CollectionCollector) ClusterBlockProposed(block *cluster.Block) {
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/module"
+)
+
+// CompressionMetrics is the metrics collector for the per-channel compression of outgoing messages.
+type CompressionMetrics struct {
+	// Tracks the number of bytes of compressed messages before compression.
+	uncompressedBytes *prometheus.CounterVec
+	// Tracks the number of bytes of compressed messages after compression.
+	compressedBytes *prometheus.CounterVec
+	// Tracks the number of bytes saved by compression.
+	bytesSaved *prometheus.CounterVec
+
+	prefix string
+}
+
+var _ module.NetworkCompressionMetrics = (*CompressionMetrics)(nil)
+
+func NewCompressionMetrics(prefix string) *CompressionMetrics {
+	cm := &CompressionMetrics{prefix: prefix}
+
+	cm.uncompressedBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemCompression,
+			Name:      cm.prefix + "uncompressed_bytes_total",
+			Help:      "the number of bytes of compressed outgoing messages before compression",
+		}, []string{LabelChannel, LabelCompressionCodec},
+	)
+
+	cm.compressedBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemCompression,
+			Name:      cm.prefix + "compressed_bytes_total",
+			Help:      "the number of bytes of compressed outgoing messages after compression",
+		}, []string{LabelChannel, LabelCompressionCodec},
+	)
+
+	cm.bytesSaved = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemCompression,
+			Name:      cm.prefix + "bytes_saved_total",
+			Help:      "the number of bytes saved by compressing outgoing messages",
+		}, []string{LabelChannel, LabelCompressionCodec},
+	)
+
+	return cm
+}
+
+// OnMessageCompressed is called when an outgoing message was compressed. Messages which grew by compression
+// do not count towards the bytes saved.
+func (cm *CompressionMetrics) OnMessageCompressed(channel string, codec string, uncompressedSize int, compressedSize int) {
+	cm.uncompressedBytes.WithLabelValues(channel, codec).Add(float64(uncompressedSize))
+	cm.compressedBytes.WithLabelValues(channel, codec).Add(float64(compressedSize))
+	if saved := uncompressedSize - compressedSize; saved > 0 {
+		cm.bytesSaved.WithLabelValues(channel, codec).Add(float64(saved))
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/module"
+)
+
+type DatabaseSizeCollector struct {
+	prefixKeys         *prometheus.GaugeVec
+	prefixSize         *prometheus.GaugeVec
+	compactionDuration prometheus.Histogram
+}
+
+var _ module.DatabaseSizeMetrics = (*DatabaseSizeCollector)(nil)
+
+func NewDatabaseSizeCollector() *DatabaseSizeCollector {
+	return &DatabaseSizeCollector{
+		prefixKeys: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceStorage,
+			Subsystem: subsystemBadger,
+			Name:      "prefix_keys",
+			Help:      "the number of keys stored under each key prefix of the protocol database",
+		}, []string{LabelPrefix}),
+		prefixSize: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceStorage,
+			Subsystem: subsystemBadger,
+			Name:      "prefix_size_bytes",
+			Help:      "the total size of the keys and values stored under each key prefix of the protocol database",
+		}, []string{LabelPrefix}),
+		compactionDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceStorage,
+			Subsystem: subsystemBadger,
+			Name:      "compaction_runtime_s",
+			Buckets:   []float64{1, 10, 60, 60 * 5, 60 * 15, 60 * 60},
+			Help:      "the time spent on online compaction of the protocol database",
+		}),
+	}
+}
+
+// PrefixSize reports the number of keys and the total size of the keys and values in bytes
+// stored under the given prefix.
+func (dc *DatabaseSizeCollector) PrefixSize(prefix string, keys uint64, sizeBytes uint64) {
+	dc.prefixKeys.WithLabelValues(prefix).Set(float64(keys))
+	dc.prefixSize.WithLabelValues(prefix).Set(float64(sizeBytes))
+}
+
+// RanCompaction records a successful online compaction of the database.
+func (dc *DatabaseSizeCollector) RanCompaction(duration time.Duration) {
+	dc.compactionDuration.Observe(duration.Seconds())
+}
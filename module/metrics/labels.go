@@ -24,6 +24,9 @@ const (
 	LabelStatusCode          = "code"
 	LabelMethod              = "method"
 	LabelService             = "service"
+	LabelCompressionCodec    = "codec"
+	LabelPeerID              = "peer_id"
+	LabelPrefix              = "prefix"
 )
 
 const (
@@ -30,6 +30,7 @@ const (
 	subsystemRateLimiting = "ratelimit"
 	subsystemAlsp         = "alsp"
 	subsystemSecurity     = "security"
+	subsystemCompression  = "compression"
 )
 
 // Storage subsystems represent the various components of the storage layer.
@@ -28,6 +28,7 @@ type NetworkCollector struct {
 	*GossipSubLocalMeshMetrics
 	*GossipSubRpcValidationInspectorMetrics
 	*AlspMetrics
+	*CompressionMetrics
 	outboundMessageSize          *prometheus.HistogramVec
 	inboundMessageSize           *prometheus.HistogramVec
 	duplicateMessagesDropped     *prometheus.CounterVec
@@ -79,6 +80,7 @@ func NewNetworkCollector(logger zerolog.Logger, opts ...NetworkCollectorOpt) *Ne
 	nc.GossipSubScoreMetrics = NewGossipSubScoreMetrics(nc.prefix)
 	nc.GossipSubRpcValidationInspectorMetrics = NewGossipSubRPCValidationInspectorMetrics(nc.prefix)
 	nc.AlspMetrics = NewAlspMetrics()
+	nc.CompressionMetrics = NewCompressionMetrics(nc.prefix)
 
 	nc.outboundMessageSize = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -50,6 +50,7 @@ func (nc *NoopCollector) DataSent(prefix string, n uint64)
 func (nc *NoopCollector) DupBlobsReceived(prefix string, n uint64)                               {}
 func (nc *NoopCollector) DupDataReceived(prefix string, n uint64)                                {}
 func (nc *NoopCollector) MessagesReceived(prefix string, n uint64)                               {}
+func (nc *NoopCollector) TopPeersBandwidth(string, map[string]uint64, map[string]uint64)         {}
 func (nc *NoopCollector) NetworkMessageSent(sizeBytes int, topic string, messageType string)     {}
 func (nc *NoopCollector) NetworkMessageReceived(sizeBytes int, topic string, messageType string) {}
 func (nc *NoopCollector) NetworkDuplicateMessagesDropped(topic string, messageType string)       {}
@@ -75,6 +76,8 @@ func (nc *NoopCollector) OnDNSLookupRequestDropped()
 func (nc *NoopCollector) UnstakedOutboundConnections(_ uint)                                     {}
 func (nc *NoopCollector) UnstakedInboundConnections(_ uint)                                      {}
 func (nc *NoopCollector) RanGC(duration time.Duration)                                           {}
+func (nc *NoopCollector) PrefixSize(prefix string, keys uint64, sizeBytes uint64)                {}
+func (nc *NoopCollector) RanCompaction(duration time.Duration)                                   {}
 func (nc *NoopCollector) BadgerLSMSize(sizeBytes int64)                                          {}
 func (nc *NoopCollector) BadgerVLogSize(sizeBytes int64)                                         {}
 func (nc *NoopCollector) BadgerNumReads(n int64)                                                 {}
@@ -98,6 +101,7 @@ func (nc *NoopCollector) CurrentDKGPhase1FinalView(view uint64)
 func (nc *NoopCollector) CurrentDKGPhase2FinalView(view uint64)                                  {}
 func (nc *NoopCollector) CurrentDKGPhase3FinalView(view uint64)                                  {}
 func (nc *NoopCollector) EpochEmergencyFallbackTriggered()                                       {}
+func (nc *NoopCollector) EpochEmergencyFallbackRecovered()                                       {}
 func (nc *NoopCollector) CacheEntries(resource string, entries uint)                             {}
 func (nc *NoopCollector) CacheHit(resource string)                                               {}
 func (nc *NoopCollector) CacheNotFound(resource string)                                          {}
@@ -122,6 +126,7 @@ func (nc *NoopCollector) ValidatorProcessingDuration(duration time.Duration)
 func (nc *NoopCollector) PayloadProductionDuration(duration time.Duration)                       {}
 func (nc *NoopCollector) TimeoutCollectorsRange(uint64, uint64, int)                             {}
 func (nc *NoopCollector) TransactionIngested(txID flow.Identifier)                               {}
+func (nc *NoopCollector) TransactionRejected(txID flow.Identifier)                               {}
 func (nc *NoopCollector) ClusterBlockProposed(*cluster.Block)                                    {}
 func (nc *NoopCollector) ClusterBlockFinalized(*cluster.Block)                                   {}
 func (nc *NoopCollector) StartCollectionToFinalized(collectionID flow.Identifier)                {}
@@ -135,6 +140,9 @@ func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)
 func (nc *NoopCollector) OnExecutionResultReceivedAtAssignerEngine()                             {}
 func (nc *NoopCollector) OnVerifiableChunkReceivedAtVerifierEngine()                             {}
 func (nc *NoopCollector) OnResultApprovalDispatchedInNetworkByVerifier()                         {}
+func (nc *NoopCollector) SetVerifierQueueDepth(string, int)                                      {}
+func (nc *NoopCollector) SetVerifierChunksInProgress(int)                                        {}
+func (nc *NoopCollector) OnChunkVerifiedAtVerifier(time.Duration, time.Duration)                 {}
 func (nc *NoopCollector) SetMaxChunkDataPackAttemptsForNextUnsealedHeightAtRequester(attempts uint64) {
 }
 func (nc *NoopCollector) OnFinalizedBlockArrivedAtAssigner(height uint64)                       {}
@@ -311,6 +319,8 @@ func (nc *NoopCollector) AsyncProcessingFinished(string, time.Duration)
 func (nc *NoopCollector) OnMisbehaviorReported(string, string) {}
 func (nc *NoopCollector) OnViolationReportSkipped()            {}
 
+func (nc *NoopCollector) OnMessageCompressed(string, string, int, int) {}
+
 var _ ObserverMetrics = (*NoopCollector)(nil)
 
 func (nc *NoopCollector) RecordRPC(handler, rpc string, code codes.Code) {}
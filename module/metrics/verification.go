@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/onflow/flow-go/module"
@@ -39,8 +41,12 @@ type VerificationCollector struct {
 	maxChunkDataPackRequestAttemptForNextUnsealedHeight prometheus.Gauge
 
 	// Verifier Engine
-	receivedVerifiableChunkTotalVerifier prometheus.Counter // total verifiable chunks received by verifier engine
-	sentResultApprovalTotalVerifier      prometheus.Counter // total result approvals sent by verifier engine
+	receivedVerifiableChunkTotalVerifier prometheus.Counter   // total verifiable chunks received by verifier engine
+	sentResultApprovalTotalVerifier      prometheus.Counter   // total result approvals sent by verifier engine
+	queueDepthVerifier                   *prometheus.GaugeVec // verifiable chunks waiting for a verification slot, per priority class
+	chunksInProgressVerifier             prometheus.Gauge     // verifiable chunks being verified in parallel
+	chunkQueueWaitVerifier               prometheus.Histogram // time verifiable chunks wait for a verification slot
+	chunkVerificationDurationVerifier    prometheus.Histogram // time to verify a verifiable chunk
 
 }
 
@@ -173,6 +179,36 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		Help:      "total number of emitted result approvals by verifier engine",
 	})
 
+	queueDepthVerifier := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "chunk_queue_depth",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "number of verifiable chunks waiting for a verification slot at verifier engine, per priority class",
+	}, []string{LabelPriority})
+
+	chunksInProgressVerifier := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "chunks_in_progress",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "number of verifiable chunks being verified in parallel by verifier engine",
+	})
+
+	chunkQueueWaitVerifier := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:      "chunk_queue_wait_seconds",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "time verifiable chunks wait for a verification slot at verifier engine",
+		Buckets:   []float64{.01, .1, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	})
+
+	chunkVerificationDurationVerifier := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:      "chunk_verification_seconds",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "time to verify a verifiable chunk at verifier engine",
+		Buckets:   []float64{.01, .1, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	})
+
 	// registers all metrics and panics if any fails.
 	registerer.MustRegister(
 		// job consumers
@@ -200,7 +236,11 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 
 		// verifier engine
 		receivedVerifiableChunksTotalVerifier,
-		sentResultApprovalTotalVerifier)
+		sentResultApprovalTotalVerifier,
+		queueDepthVerifier,
+		chunksInProgressVerifier,
+		chunkQueueWaitVerifier,
+		chunkVerificationDurationVerifier)
 
 	vc := &VerificationCollector{
 		tracer: tracer,
@@ -224,6 +264,10 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		// verifier
 		sentResultApprovalTotalVerifier:      sentResultApprovalTotalVerifier,
 		receivedVerifiableChunkTotalVerifier: receivedVerifiableChunksTotalVerifier,
+		queueDepthVerifier:                   queueDepthVerifier,
+		chunksInProgressVerifier:             chunksInProgressVerifier,
+		chunkQueueWaitVerifier:               chunkQueueWaitVerifier,
+		chunkVerificationDurationVerifier:    chunkVerificationDurationVerifier,
 
 		// requester
 		receivedChunkDataPackRequestsTotalRequester:         receivedChunkDataPackRequestsTotalRequester,
@@ -257,6 +301,25 @@ func (vc *VerificationCollector) OnResultApprovalDispatchedInNetworkByVerifier()
 	vc.sentResultApprovalTotalVerifier.Inc()
 }
 
+// SetVerifierQueueDepth sets a gauge that keeps track of number of verifiable chunks of the given priority class waiting
+// at verifier engine for a verification slot.
+func (vc *VerificationCollector) SetVerifierQueueDepth(priorityClass string, depth int) {
+	vc.queueDepthVerifier.WithLabelValues(priorityClass).Set(float64(depth))
+}
+
+// SetVerifierChunksInProgress sets a gauge that keeps track of number of verifiable chunks being verified in parallel
+// by verifier engine.
+func (vc *VerificationCollector) SetVerifierChunksInProgress(count int) {
+	vc.chunksInProgressVerifier.Set(float64(count))
+}
+
+// OnChunkVerifiedAtVerifier records the time a verifiable chunk waited for a verification slot at verifier engine, and
+// the time it took to verify it.
+func (vc *VerificationCollector) OnChunkVerifiedAtVerifier(wait time.Duration, verification time.Duration) {
+	vc.chunkQueueWaitVerifier.Observe(wait.Seconds())
+	vc.chunkVerificationDurationVerifier.Observe(verification.Seconds())
+}
+
 // OnFinalizedBlockArrivedAtAssigner sets a gauge that keeps track of number of the latest block height arrives
 // at assigner engine. Note that it assumes blocks are coming to assigner engine in strictly increasing order of their height.
 func (vc *VerificationCollector) OnFinalizedBlockArrivedAtAssigner(height uint64) {
@@ -49,6 +49,11 @@ func (_m *BitswapMetrics) Peers(prefix string, n int) {
 	_m.Called(prefix, n)
 }
 
+// TopPeersBandwidth provides a mock function with given fields: prefix, received, sent
+func (_m *BitswapMetrics) TopPeersBandwidth(prefix string, received map[string]uint64, sent map[string]uint64) {
+	_m.Called(prefix, received, sent)
+}
+
 // Wantlist provides a mock function with given fields: prefix, n
 func (_m *BitswapMetrics) Wantlist(prefix string, n int) {
 	_m.Called(prefix, n)
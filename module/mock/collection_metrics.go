@@ -29,6 +29,11 @@ func (_m *CollectionMetrics) TransactionIngested(txID flow.Identifier) {
 	_m.Called(txID)
 }
 
+// TransactionRejected provides a mock function with given fields: txID
+func (_m *CollectionMetrics) TransactionRejected(txID flow.Identifier) {
+	_m.Called(txID)
+}
+
 type mockConstructorTestingTNewCollectionMetrics interface {
 	mock.TestingT
 	Cleanup(func())
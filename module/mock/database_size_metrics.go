@@ -0,0 +1,39 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// DatabaseSizeMetrics is an autogenerated mock type for the DatabaseSizeMetrics type
+type DatabaseSizeMetrics struct {
+	mock.Mock
+}
+
+// PrefixSize provides a mock function with given fields: prefix, keys, sizeBytes
+func (_m *DatabaseSizeMetrics) PrefixSize(prefix string, keys uint64, sizeBytes uint64) {
+	_m.Called(prefix, keys, sizeBytes)
+}
+
+// RanCompaction provides a mock function with given fields: took
+func (_m *DatabaseSizeMetrics) RanCompaction(took time.Duration) {
+	_m.Called(took)
+}
+
+type mockConstructorTestingTNewDatabaseSizeMetrics interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewDatabaseSizeMetrics creates a new instance of DatabaseSizeMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewDatabaseSizeMetrics(t mockConstructorTestingTNewDatabaseSizeMetrics) *DatabaseSizeMetrics {
+	mock := &DatabaseSizeMetrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
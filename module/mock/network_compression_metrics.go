@@ -0,0 +1,30 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import mock "github.com/stretchr/testify/mock"
+
+// NetworkCompressionMetrics is an autogenerated mock type for the NetworkCompressionMetrics type
+type NetworkCompressionMetrics struct {
+	mock.Mock
+}
+
+// OnMessageCompressed provides a mock function with given fields: channel, codec, uncompressedSize, compressedSize
+func (_m *NetworkCompressionMetrics) OnMessageCompressed(channel string, codec string, uncompressedSize int, compressedSize int) {
+	_m.Called(channel, codec, uncompressedSize, compressedSize)
+}
+
+type mockConstructorTestingTNewNetworkCompressionMetrics interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewNetworkCompressionMetrics creates a new instance of NetworkCompressionMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewNetworkCompressionMetrics(t mockConstructorTestingTNewNetworkCompressionMetrics) *NetworkCompressionMetrics {
+	mock := &NetworkCompressionMetrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -45,6 +45,11 @@ func (_m *NetworkCoreMetrics) MessageRemoved(priority int) {
 	_m.Called(priority)
 }
 
+// OnMessageCompressed provides a mock function with given fields: channel, codec, uncompressedSize, compressedSize
+func (_m *NetworkCoreMetrics) OnMessageCompressed(channel string, codec string, uncompressedSize int, compressedSize int) {
+	_m.Called(channel, codec, uncompressedSize, compressedSize)
+}
+
 // OnMisbehaviorReported provides a mock function with given fields: channel, misbehaviorType
 func (_m *NetworkCoreMetrics) OnMisbehaviorReported(channel string, misbehaviorType string) {
 	_m.Called(channel, misbehaviorType)
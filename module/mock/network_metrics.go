@@ -240,6 +240,11 @@ func (_m *NetworkMetrics) OnMeshMessageDeliveredUpdated(_a0 channels.Topic, _a1
 	_m.Called(_a0, _a1)
 }
 
+// OnMessageCompressed provides a mock function with given fields: channel, codec, uncompressedSize, compressedSize
+func (_m *NetworkMetrics) OnMessageCompressed(channel string, codec string, uncompressedSize int, compressedSize int) {
+	_m.Called(channel, codec, uncompressedSize, compressedSize)
+}
+
 // OnMisbehaviorReported provides a mock function with given fields: channel, misbehaviorType
 func (_m *NetworkMetrics) OnMisbehaviorReported(channel string, misbehaviorType string) {
 	_m.Called(channel, misbehaviorType)
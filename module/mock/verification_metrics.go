@@ -2,7 +2,11 @@
 
 package mock
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // VerificationMetrics is an autogenerated mock type for the VerificationMetrics type
 type VerificationMetrics struct {
@@ -64,6 +68,11 @@ func (_m *VerificationMetrics) OnChunksAssignmentDoneAtAssigner(chunks int) {
 	_m.Called(chunks)
 }
 
+// OnChunkVerifiedAtVerifier provides a mock function with given fields: wait, verification
+func (_m *VerificationMetrics) OnChunkVerifiedAtVerifier(wait time.Duration, verification time.Duration) {
+	_m.Called(wait, verification)
+}
+
 // OnExecutionResultReceivedAtAssignerEngine provides a mock function with given fields:
 func (_m *VerificationMetrics) OnExecutionResultReceivedAtAssignerEngine() {
 	_m.Called()
@@ -94,6 +103,16 @@ func (_m *VerificationMetrics) SetMaxChunkDataPackAttemptsForNextUnsealedHeightA
 	_m.Called(attempts)
 }
 
+// SetVerifierChunksInProgress provides a mock function with given fields: count
+func (_m *VerificationMetrics) SetVerifierChunksInProgress(count int) {
+	_m.Called(count)
+}
+
+// SetVerifierQueueDepth provides a mock function with given fields: priorityClass, depth
+func (_m *VerificationMetrics) SetVerifierQueueDepth(priorityClass string, depth int) {
+	_m.Called(priorityClass, depth)
+}
+
 type mockConstructorTestingTNewVerificationMetrics interface {
 	mock.TestingT
 	Cleanup(func())
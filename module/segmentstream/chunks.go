@@ -0,0 +1,243 @@
+package segmentstream
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+// Split splits the sealing segment of the given snapshot into chunks of at most chunkSize blocks,
+// and returns the manifest describing them.
+// No errors are expected during normal operation.
+func Split(snapshot *inmem.Snapshot, chunkSize uint) (*Manifest, []*Chunk, error) {
+	if chunkSize == 0 {
+		return nil, nil, fmt.Errorf("chunk size must be positive")
+	}
+
+	enc := snapshot.Encodable()
+	if enc.Head == nil || enc.SealingSegment == nil || len(enc.SealingSegment.Blocks) == 0 {
+		return nil, nil, fmt.Errorf("snapshot has no sealing segment")
+	}
+	headID := enc.Head.ID()
+
+	// copy the blocks, as appending to the extra blocks might modify the snapshot's blocks
+	blocks := make([]*flow.Block, 0, len(enc.SealingSegment.ExtraBlocks)+len(enc.SealingSegment.Blocks))
+	blocks = append(blocks, enc.SealingSegment.ExtraBlocks...)
+	blocks = append(blocks, enc.SealingSegment.Blocks...)
+	manifest := &Manifest{
+		HeadID:      headID,
+		HeadHeight:  enc.Head.Height,
+		ChunkSize:   chunkSize,
+		ExtraBlocks: uint(len(enc.SealingSegment.ExtraBlocks)),
+	}
+	var chunks []*Chunk
+	for start := 0; start < len(blocks); start += int(chunkSize) {
+		end := start + int(chunkSize)
+		if end > len(blocks) {
+			end = len(blocks)
+		}
+
+		chunk := &Chunk{
+			HeadID: headID,
+			Index:  uint(len(chunks)),
+			Blocks: blocks[start:end],
+		}
+		info := ChunkInfo{
+			Index:    chunk.Index,
+			BlockIDs: make([]flow.Identifier, 0, len(chunk.Blocks)),
+		}
+		for _, block := range chunk.Blocks {
+			info.BlockIDs = append(info.BlockIDs, block.ID())
+		}
+		chunks = append(chunks, chunk)
+		manifest.Chunks = append(manifest.Chunks, info)
+	}
+
+	// the blocks are transferred in chunks, so they are stripped from the snapshot in the manifest
+	segment := *enc.SealingSegment
+	segment.Blocks = nil
+	segment.ExtraBlocks = nil
+	enc.SealingSegment = &segment
+	manifest.Snapshot = enc
+
+	return manifest, chunks, nil
+}
+
+// Assemble verifies the given segment chunks against the manifest and assembles the protocol
+// snapshot described by the manifest. The chunks must be given in index order.
+// Expected errors during normal operation:
+//   - InvalidChunkError if the manifest or any of the chunks is invalid
+func Assemble(manifest *Manifest, chunks []*Chunk) (*inmem.Snapshot, error) {
+	err := manifest.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) != len(manifest.Chunks) {
+		return nil, NewInvalidChunkErrorf("expected %d segment chunks, got %d", len(manifest.Chunks), len(chunks))
+	}
+
+	var blocks []*flow.Block
+	for i, chunk := range chunks {
+		if chunk.Index != uint(i) {
+			return nil, NewInvalidChunkErrorf("expected segment chunk %d, got chunk %d", i, chunk.Index)
+		}
+		err := manifest.VerifyChunk(chunk)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, chunk.Blocks...)
+	}
+
+	enc := manifest.Snapshot
+	segment := *enc.SealingSegment
+	segment.ExtraBlocks = blocks[:manifest.ExtraBlocks:manifest.ExtraBlocks]
+	segment.Blocks = blocks[manifest.ExtraBlocks:]
+	enc.SealingSegment = &segment
+
+	return inmem.SnapshotFromEncodable(enc), nil
+}
+
+// Validate checks the internal consistency of the manifest. The manifest is considered valid if
+// the head of its snapshot is certified by the snapshot's quorum certificate and is the highest
+// block of the chunks. The signatures of the quorum certificate are not verified here.
+// Expected errors during normal operation:
+//   - InvalidChunkError if the manifest is invalid
+func (m *Manifest) Validate() error {
+	if m.ChunkSize == 0 {
+		return NewInvalidChunkErrorf("manifest has zero chunk size")
+	}
+
+	head := m.Snapshot.Head
+	if head == nil {
+		return NewInvalidChunkErrorf("manifest snapshot has no head")
+	}
+	if head.ID() != m.HeadID || head.Height != m.HeadHeight {
+		return NewInvalidChunkErrorf("manifest snapshot head (id=%x, height=%d) does not match manifest head (id=%x, height=%d)",
+			head.ID(), head.Height, m.HeadID, m.HeadHeight)
+	}
+	qc := m.Snapshot.QuorumCertificate
+	if qc == nil || qc.BlockID != m.HeadID || qc.View != head.View {
+		return NewInvalidChunkErrorf("manifest snapshot quorum certificate does not certify head %x", m.HeadID)
+	}
+	segment := m.Snapshot.SealingSegment
+	if segment == nil {
+		return NewInvalidChunkErrorf("manifest snapshot has no sealing segment")
+	}
+	if len(segment.Blocks) != 0 || len(segment.ExtraBlocks) != 0 {
+		return NewInvalidChunkErrorf("manifest snapshot sealing segment must not contain blocks")
+	}
+
+	var blocks uint
+	var last flow.Identifier
+	for i, info := range m.Chunks {
+		if info.Index != uint(i) {
+			return NewInvalidChunkErrorf("expected chunk %d, got chunk %d", i, info.Index)
+		}
+		if len(info.BlockIDs) == 0 || uint(len(info.BlockIDs)) > m.ChunkSize {
+			return NewInvalidChunkErrorf("chunk %d has %d blocks (chunk size %d)", i, len(info.BlockIDs), m.ChunkSize)
+		}
+		blocks += uint(len(info.BlockIDs))
+		last = info.BlockIDs[len(info.BlockIDs)-1]
+	}
+	if m.ExtraBlocks >= blocks {
+		return NewInvalidChunkErrorf("manifest has %d extra blocks, but only %d blocks", m.ExtraBlocks, blocks)
+	}
+	if last != m.HeadID {
+		return NewInvalidChunkErrorf("last block %x of the segment is not the head %x", last, m.HeadID)
+	}
+
+	return nil
+}
+
+// VerifyChunk verifies that the given segment chunk holds exactly the blocks listed in the
+// manifest, and that the blocks are linked to their parents.
+// Expected errors during normal operation:
+//   - InvalidChunkError if the chunk is not a valid segment chunk of the manifest
+func (m *Manifest) VerifyChunk(chunk *Chunk) error {
+	if chunk.HeadID != m.HeadID {
+		return NewInvalidChunkErrorf("chunk %d is for head %x, expected head %x", chunk.Index, chunk.HeadID, m.HeadID)
+	}
+	if m.IsFollowing(chunk.Index) {
+		return NewInvalidChunkErrorf("chunk %d is not a segment chunk", chunk.Index)
+	}
+
+	info := m.Chunks[chunk.Index]
+	if len(chunk.Blocks) != len(info.BlockIDs) {
+		return NewInvalidChunkErrorf("chunk %d has %d blocks, expected %d", chunk.Index, len(chunk.Blocks), len(info.BlockIDs))
+	}
+
+	for i, block := range chunk.Blocks {
+		err := verifyPayload(block)
+		if err != nil {
+			return NewInvalidChunkErrorf("invalid block %d of chunk %d: %w", i, chunk.Index, err)
+		}
+		if block.ID() != info.BlockIDs[i] {
+			return NewInvalidChunkErrorf("block %d of chunk %d has id %x, expected %x", i, chunk.Index, block.ID(), info.BlockIDs[i])
+		}
+
+		// the first block of the segment is not linked, as its parent is not part of the segment
+		var parentID flow.Identifier
+		switch {
+		case i > 0:
+			parentID = info.BlockIDs[i-1]
+		case chunk.Index > 0:
+			previous := m.Chunks[chunk.Index-1].BlockIDs
+			parentID = previous[len(previous)-1]
+		default:
+			continue
+		}
+		if block.Header.ParentID != parentID {
+			return NewInvalidChunkErrorf("block %d of chunk %d has parent %x, expected %x", i, chunk.Index, block.Header.ParentID, parentID)
+		}
+	}
+
+	return nil
+}
+
+// VerifyFollowingChunk verifies that the given following chunk holds consecutive finalized blocks
+// descending from the given parent, which is the snapshot's head for the first following chunk,
+// and the last block of the previous following chunk otherwise.
+// Expected errors during normal operation:
+//   - InvalidChunkError if the chunk is not a valid following chunk of the manifest
+func (m *Manifest) VerifyFollowingChunk(parent *flow.Header, chunk *Chunk) error {
+	if chunk.HeadID != m.HeadID {
+		return NewInvalidChunkErrorf("chunk %d is for head %x, expected head %x", chunk.Index, chunk.HeadID, m.HeadID)
+	}
+	if !m.IsFollowing(chunk.Index) {
+		return NewInvalidChunkErrorf("chunk %d is not a following chunk", chunk.Index)
+	}
+	if uint(len(chunk.Blocks)) > m.ChunkSize {
+		return NewInvalidChunkErrorf("chunk %d has %d blocks (chunk size %d)", chunk.Index, len(chunk.Blocks), m.ChunkSize)
+	}
+
+	from, _ := m.FollowingHeights(chunk.Index)
+	if len(chunk.Blocks) > 0 && parent.Height+1 != from {
+		return NewInvalidChunkErrorf("parent height %d does not precede chunk %d starting at height %d", parent.Height, chunk.Index, from)
+	}
+
+	for i, block := range chunk.Blocks {
+		err := verifyPayload(block)
+		if err != nil {
+			return NewInvalidChunkErrorf("invalid block %d of chunk %d: %w", i, chunk.Index, err)
+		}
+		if block.Header.ParentID != parent.ID() || block.Header.Height != parent.Height+1 || block.Header.ParentView != parent.View {
+			return NewInvalidChunkErrorf("block %d of chunk %d does not extend block %x", i, chunk.Index, parent.ID())
+		}
+		parent = block.Header
+	}
+
+	return nil
+}
+
+// verifyPayload checks that the payload of the block matches the payload hash of its header.
+// As the block ID commits to the payload hash, this binds the payload to the block ID.
+func verifyPayload(block *flow.Block) error {
+	if block.Header == nil || block.Payload == nil {
+		return fmt.Errorf("block is incomplete")
+	}
+	if block.Header.PayloadHash != block.Payload.Hash() {
+		return fmt.Errorf("payload of block %x does not match payload hash", block.ID())
+	}
+	return nil
+}
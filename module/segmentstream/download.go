@@ -0,0 +1,223 @@
+package segmentstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sethvargo/go-retry"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	utilsio "github.com/onflow/flow-go/utils/io"
+)
+
+const (
+	// FilenameManifest is the name of the file holding the manifest of a download.
+	FilenameManifest = "manifest.json"
+	// FilenameChunk is the name of the file holding a downloaded chunk, %06d is replaced by the chunk index.
+	FilenameChunk = "chunk-%06d.json"
+)
+
+// DownloaderConfig configures the retries of failed chunk downloads.
+type DownloaderConfig struct {
+	RetryBaseWait time.Duration // wait before the first retry, doubled for each further retry
+	RetryMax      uint64        // maximum number of retries per request
+}
+
+func DefaultDownloaderConfig() DownloaderConfig {
+	return DownloaderConfig{
+		RetryBaseWait: time.Second,
+		RetryMax:      8,
+	}
+}
+
+// Downloader downloads the sealing segment of a finalized block and the blocks following it from a
+// source, persisting the manifest and each verified chunk in a directory. An interrupted download
+// is resumed from the chunks in the directory, which are verified again before they are used.
+type Downloader struct {
+	log    zerolog.Logger
+	config DownloaderConfig
+	source Source
+	dir    string
+}
+
+// NewDownloader returns a new downloader persisting its progress in the given directory.
+func NewDownloader(log zerolog.Logger, config DownloaderConfig, source Source, dir string) *Downloader {
+	return &Downloader{
+		log:    log.With().Str("component", "sealing_segment_downloader").Logger(),
+		config: config,
+		source: source,
+		dir:    dir,
+	}
+}
+
+// Download downloads the protocol snapshot at the given finalized block, or at the latest finalized
+// block of the source if the block ID is flow.ZeroID, and the finalized blocks following it which
+// are available at the source. A download in the directory for a different block is discarded.
+// Expected errors during normal operation:
+//   - InvalidChunkError if the manifest served by the source is invalid
+func (d *Downloader) Download(ctx context.Context, blockID flow.Identifier) (*inmem.Snapshot, []*flow.Block, error) {
+	manifest, err := d.manifest(ctx, blockID)
+	if err != nil {
+		return nil, nil, err
+	}
+	log := d.log.With().
+		Hex("head_id", manifest.HeadID[:]).
+		Uint64("head_height", manifest.HeadHeight).
+		Logger()
+	log.Info().Int("chunks", len(manifest.Chunks)).Msg("downloading sealing segment")
+
+	chunks := make([]*Chunk, 0, len(manifest.Chunks))
+	for index := uint(0); index < manifest.SegmentChunks(); index++ {
+		chunk, err := d.chunk(ctx, manifest, index, manifest.VerifyChunk)
+		if err != nil {
+			return nil, nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	snapshot, err := Assemble(manifest, chunks)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not assemble snapshot: %w", err)
+	}
+
+	// download following blocks until the source has no complete chunk of finalized blocks left
+	var following []*flow.Block
+	parent := manifest.Snapshot.Head
+	for index := manifest.SegmentChunks(); ; index++ {
+		verify := func(chunk *Chunk) error {
+			return manifest.VerifyFollowingChunk(parent, chunk)
+		}
+		chunk, err := d.chunk(ctx, manifest, index, verify)
+		if err != nil {
+			return nil, nil, err
+		}
+		following = append(following, chunk.Blocks...)
+		if uint(len(chunk.Blocks)) < manifest.ChunkSize {
+			break
+		}
+		parent = chunk.Blocks[len(chunk.Blocks)-1].Header
+	}
+
+	log.Info().Int("following_blocks", len(following)).Msg("downloaded sealing segment")
+	return snapshot, following, nil
+}
+
+// manifest returns the manifest persisted by a previous download of the given block, or downloads
+// and persists it otherwise.
+func (d *Downloader) manifest(ctx context.Context, blockID flow.Identifier) (*Manifest, error) {
+	path := filepath.Join(d.dir, FilenameManifest)
+
+	var manifest Manifest
+	err := readJSON(path, &manifest)
+	if err == nil && (blockID == flow.ZeroID || manifest.HeadID == blockID) && manifest.Validate() == nil {
+		d.log.Info().Hex("head_id", manifest.HeadID[:]).Msg("resuming sealing segment download")
+		return &manifest, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		d.log.Warn().Err(err).Msg("discarding unreadable sealing segment manifest")
+	}
+
+	// chunks of a previous download belong to a different manifest
+	err = os.RemoveAll(d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not remove previous download: %w", err)
+	}
+
+	var downloaded *Manifest
+	err = d.retry(ctx, func(ctx context.Context) error {
+		downloaded, err = d.source.Manifest(ctx, blockID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not download manifest: %w", err)
+	}
+	err = downloaded.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if blockID != flow.ZeroID && downloaded.HeadID != blockID {
+		return nil, NewInvalidChunkErrorf("source served manifest for block %x, expected %x", downloaded.HeadID, blockID)
+	}
+
+	err = utilsio.WriteJSON(path, downloaded)
+	if err != nil {
+		return nil, fmt.Errorf("could not persist manifest: %w", err)
+	}
+	return downloaded, nil
+}
+
+// chunk returns the chunk with the given index, loading it from the directory if it was persisted
+// by a previous download and still verifies, and downloading it from the source otherwise. Chunks
+// which are invalid are downloaded again. Only complete chunks are persisted, as the following
+// chunks which are not complete yet grow as blocks are finalized.
+func (d *Downloader) chunk(ctx context.Context, manifest *Manifest, index uint, verify func(*Chunk) error) (*Chunk, error) {
+	path := filepath.Join(d.dir, fmt.Sprintf(FilenameChunk, index))
+
+	var chunk Chunk
+	err := readJSON(path, &chunk)
+	if err == nil && chunk.Index == index {
+		err = verify(&chunk)
+		if err == nil {
+			return &chunk, nil
+		}
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		d.log.Warn().Err(err).Uint("index", index).Msg("discarding invalid persisted chunk")
+	}
+
+	var downloaded *Chunk
+	err = d.retry(ctx, func(ctx context.Context) error {
+		downloaded, err = d.source.Chunk(ctx, manifest.HeadID, index)
+		if err != nil {
+			return err
+		}
+		if downloaded.Index != index {
+			return NewInvalidChunkErrorf("source served chunk %d, expected chunk %d", downloaded.Index, index)
+		}
+		return verify(downloaded)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not download chunk %d: %w", index, err)
+	}
+
+	if uint(len(downloaded.Blocks)) == manifest.ChunkSize || !manifest.IsFollowing(index) {
+		err = utilsio.WriteJSON(path, downloaded)
+		if err != nil {
+			return nil, fmt.Errorf("could not persist chunk %d: %w", index, err)
+		}
+	}
+	return downloaded, nil
+}
+
+// retry calls f until it succeeds, retrying with exponential backoff.
+func (d *Downloader) retry(ctx context.Context, f func(context.Context) error) error {
+	backoff := retry.NewExponential(d.config.RetryBaseWait)
+	backoff = retry.WithMaxRetries(d.config.RetryMax, backoff)
+
+	attempt := 0
+	return retry.Do(ctx, backoff, func(ctx context.Context) error {
+		attempt++
+		err := f(ctx)
+		if err != nil {
+			d.log.Warn().Err(err).Int("attempt", attempt).Msg("sealing segment request failed")
+			return retry.RetryableError(err)
+		}
+		return nil
+	})
+}
+
+// readJSON decodes the JSON file at the given path into target.
+func readJSON(path string, target interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
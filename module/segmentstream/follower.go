@@ -0,0 +1,49 @@
+package segmentstream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// ExtendFollowing adds the downloaded blocks following the root block of the given state to the
+// state, so that they don't have to be synchronized again once the node has started. The blocks
+// must be consecutive, starting with a child of the root block. Each block is certified by the
+// quorum certificate in its child, so the last block is not added.
+//
+// The quorum certificate of each block is validated against the committee of the state before the
+// certified block is added. This includes the certificate of the root block in the first block, so
+// that all added blocks are certified by the committee of the root snapshot.
+// No errors are expected during normal operation.
+func ExtendFollowing(ctx context.Context, state protocol.FollowerState, validator hotstuff.Validator, blocks []*flow.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	root, err := state.Params().FinalizedRoot()
+	if err != nil {
+		return fmt.Errorf("could not get root block: %w", err)
+	}
+	if blocks[0].Header.ParentID != root.ID() {
+		return fmt.Errorf("first following block %x does not extend root block %x", blocks[0].ID(), root.ID())
+	}
+
+	for i, block := range blocks {
+		err := validator.ValidateQC(block.Header.QuorumCertificate())
+		if err != nil {
+			return fmt.Errorf("invalid quorum certificate in block %x: %w", block.ID(), err)
+		}
+		if i == 0 {
+			continue
+		}
+
+		parent := blocks[i-1]
+		err = state.ExtendCertified(ctx, parent, block.Header.QuorumCertificate())
+		if err != nil {
+			return fmt.Errorf("could not extend state with block %x: %w", parent.ID(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package segmentstream
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ErrBuildRateLimited is returned when the sealing segment of a block has to be split, but the
+// provider has reached its rate limit of splitting sealing segments.
+var ErrBuildRateLimited = errors.New("rate limit of splitting sealing segments reached")
+
+// ProviderConfig configures a Provider.
+type ProviderConfig struct {
+	// ChunkSize is the maximum number of blocks per chunk.
+	ChunkSize uint
+	// CachedSegments is the number of most recently split sealing segments kept in memory.
+	CachedSegments int
+	// LatestLag is the maximum number of heights the head of a cached segment may be below the
+	// latest finalized block to be served to requests for the latest finalized block.
+	LatestLag uint64
+	// BuildRate is the maximum rate at which sealing segments are split.
+	BuildRate rate.Limit
+	// BuildBurst is the maximum number of sealing segments split in a burst.
+	BuildBurst int
+}
+
+// DefaultProviderConfig returns the default configuration of a Provider.
+func DefaultProviderConfig() ProviderConfig {
+	return ProviderConfig{
+		ChunkSize:      DefaultChunkSize,
+		CachedSegments: 4,
+		LatestLag:      DefaultChunkSize,
+		BuildRate:      rate.Every(5 * time.Second),
+		BuildBurst:     2,
+	}
+}
+
+// segment is a sealing segment split into chunks.
+type segment struct {
+	manifest *Manifest
+	chunks   []*Chunk
+}
+
+// Provider serves manifests and chunks of the sealing segments of finalized blocks from the
+// local protocol state.
+//
+// Building the snapshot of a block requires reading the whole sealing segment from the database,
+// so the provider keeps the most recently split segments, from which the chunk requests of
+// bootstrapping nodes are served. Requests for the latest finalized block are served from the
+// most recently split segment while its head is recent enough, so that concurrent bootstrapping
+// nodes share a segment. Segments are split one at a time and at a limited rate.
+type Provider struct {
+	state   protocol.State
+	headers storage.Headers
+	blocks  storage.Blocks
+	config  ProviderConfig
+
+	segments *lru.Cache[flow.Identifier, *segment]
+	limiter  *rate.Limiter
+	snapshot func(blockID flow.Identifier) (*inmem.Snapshot, error) // builds the snapshot at a finalized block
+
+	mu     sync.Mutex // serializes splitting sealing segments
+	newest *Manifest  // manifest of the split segment with the highest head, nil if none
+}
+
+// NewProvider returns a new provider with the given configuration.
+// No errors are expected during normal operation.
+func NewProvider(config ProviderConfig, state protocol.State, headers storage.Headers, blocks storage.Blocks) (*Provider, error) {
+	if config.ChunkSize == 0 {
+		return nil, fmt.Errorf("chunk size must be above 0")
+	}
+	segments, err := lru.New[flow.Identifier, *segment](config.CachedSegments)
+	if err != nil {
+		return nil, fmt.Errorf("could not create segment cache: %w", err)
+	}
+
+	return &Provider{
+		state:    state,
+		headers:  headers,
+		blocks:   blocks,
+		config:   config,
+		segments: segments,
+		limiter:  rate.NewLimiter(config.BuildRate, config.BuildBurst),
+		snapshot: func(blockID flow.Identifier) (*inmem.Snapshot, error) {
+			return inmem.FromSnapshot(state.AtBlockID(blockID))
+		},
+	}, nil
+}
+
+// Manifest returns the manifest of the sealing segment of the given finalized block.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if the block is not known or not finalized
+//   - ErrBuildRateLimited if the segment is not cached and the rate limit of splitting segments is reached
+func (p *Provider) Manifest(blockID flow.Identifier) (*Manifest, error) {
+	s, err := p.split(blockID)
+	if err != nil {
+		return nil, err
+	}
+	return s.manifest, nil
+}
+
+// LatestManifest returns the manifest of the sealing segment of a recent finalized block. This is
+// the most recently split segment if its head is at most LatestLag heights below the latest
+// finalized block, and the segment of the latest finalized block otherwise.
+// Expected errors during normal operation:
+//   - ErrBuildRateLimited if the segment is not cached and the rate limit of splitting segments is reached
+func (p *Provider) LatestManifest() (*Manifest, error) {
+	final, err := p.state.Final().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized block: %w", err)
+	}
+
+	headID := final.ID()
+	p.mu.Lock()
+	if p.newest != nil && final.Height-p.newest.HeadHeight <= p.config.LatestLag {
+		headID = p.newest.HeadID
+	}
+	p.mu.Unlock()
+
+	return p.Manifest(headID)
+}
+
+// Chunk returns the chunk with the given index of the sealing segment of the given finalized
+// block. Indices past the last segment chunk return the finalized blocks following the block,
+// which are empty if no such blocks are finalized yet.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if the block is not known or not finalized
+//   - ErrBuildRateLimited if the segment is not cached and the rate limit of splitting segments is reached
+func (p *Provider) Chunk(headID flow.Identifier, index uint) (*Chunk, error) {
+	s, err := p.split(headID)
+	if err != nil {
+		return nil, err
+	}
+	manifest := s.manifest
+	if !manifest.IsFollowing(index) {
+		return s.chunks[index], nil
+	}
+
+	final, err := p.state.Final().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized block: %w", err)
+	}
+	from, to := manifest.FollowingHeights(index)
+	if to > final.Height {
+		to = final.Height
+	}
+
+	chunk := &Chunk{
+		HeadID: headID,
+		Index:  index,
+	}
+	for height := from; height <= to; height++ {
+		block, err := p.blocks.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+		chunk.Blocks = append(chunk.Blocks, block)
+	}
+	return chunk, nil
+}
+
+// split returns the sealing segment of the given finalized block split into chunks, splitting it
+// unless it is cached.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if the block is not known or not finalized
+//   - ErrBuildRateLimited if the segment is not cached and the rate limit of splitting segments is reached
+func (p *Provider) split(blockID flow.Identifier) (*segment, error) {
+	if s, ok := p.segments.Get(blockID); ok {
+		return s, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the segment may have been split while waiting for the lock
+	if s, ok := p.segments.Get(blockID); ok {
+		return s, nil
+	}
+
+	header, err := p.headers.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get block %x: %w", blockID, err)
+	}
+	finalizedID, err := p.headers.BlockIDByHeight(header.Height)
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized block at height %d: %w", header.Height, err)
+	}
+	if finalizedID != blockID {
+		return nil, fmt.Errorf("block %x is not finalized: %w", blockID, storage.ErrNotFound)
+	}
+
+	if !p.limiter.Allow() {
+		return nil, ErrBuildRateLimited
+	}
+
+	snapshot, err := p.snapshot(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not build snapshot at block %x: %w", blockID, err)
+	}
+	manifest, chunks, err := Split(snapshot, p.config.ChunkSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not split sealing segment of block %x: %w", blockID, err)
+	}
+
+	s := &segment{manifest: manifest, chunks: chunks}
+	p.segments.Add(blockID, s)
+	if p.newest == nil || manifest.HeadHeight > p.newest.HeadHeight {
+		p.newest = manifest
+	}
+	return s, nil
+}
@@ -0,0 +1,96 @@
+// Package segmentstream implements a chunked transfer protocol for the sealing segment of a
+// protocol state snapshot and the finalized blocks following it.
+//
+// A node bootstrapping from a peer first retrieves the Manifest of a finalized block, which holds
+// the protocol snapshot at that block with the blocks of the sealing segment stripped, and the IDs
+// of all segment blocks split into chunks. The segment blocks are then downloaded chunk by chunk.
+// Each chunk is verified against the manifest on its own, so that downloads can be resumed after
+// an interruption without downloading verified chunks again. Chunks with an index past the last
+// segment chunk hold the finalized blocks following the head of the snapshot.
+package segmentstream
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+// DefaultChunkSize is the default number of blocks per chunk.
+const DefaultChunkSize = 100
+
+// Manifest describes the chunks of the sealing segment of a protocol snapshot.
+type Manifest struct {
+	// HeadID is the ID of the snapshot's head, the highest block of the sealing segment.
+	HeadID flow.Identifier
+	// HeadHeight is the height of the snapshot's head.
+	HeadHeight uint64
+	// ChunkSize is the maximum number of blocks per chunk.
+	ChunkSize uint
+	// ExtraBlocks is the number of leading segment blocks which are extra blocks of the segment.
+	ExtraBlocks uint
+	// Chunks lists the IDs of the segment blocks per chunk, in ascending height order.
+	Chunks []ChunkInfo
+	// Snapshot is the protocol snapshot at the head, without the blocks of the sealing segment.
+	Snapshot inmem.EncodableSnapshot
+}
+
+// ChunkInfo lists the IDs of the blocks of a segment chunk.
+type ChunkInfo struct {
+	Index    uint
+	BlockIDs []flow.Identifier
+}
+
+// Chunk is a range of consecutive blocks in ascending height order. Chunks with an index below
+// the number of segment chunks in the manifest hold blocks of the sealing segment, and chunks with
+// a higher index hold finalized blocks following the head. An empty following chunk indicates that
+// no further blocks are finalized yet.
+type Chunk struct {
+	HeadID flow.Identifier
+	Index  uint
+	Blocks []*flow.Block
+}
+
+// SegmentChunks returns the number of chunks holding blocks of the sealing segment.
+func (m *Manifest) SegmentChunks() uint {
+	return uint(len(m.Chunks))
+}
+
+// IsFollowing returns true if the chunk with the given index holds blocks following the head.
+func (m *Manifest) IsFollowing(index uint) bool {
+	return index >= m.SegmentChunks()
+}
+
+// FollowingHeights returns the height range of the blocks held by the following chunk with the
+// given index. The index must be at least the number of segment chunks.
+func (m *Manifest) FollowingHeights(index uint) (uint64, uint64) {
+	from := m.HeadHeight + 1 + uint64(index-m.SegmentChunks())*uint64(m.ChunkSize)
+	return from, from + uint64(m.ChunkSize) - 1
+}
+
+// InvalidChunkError is returned when a chunk or manifest does not match the data it is expected
+// to hold.
+type InvalidChunkError struct {
+	err error
+}
+
+func NewInvalidChunkErrorf(msg string, args ...interface{}) error {
+	return InvalidChunkError{
+		err: fmt.Errorf(msg, args...),
+	}
+}
+
+func (e InvalidChunkError) Unwrap() error {
+	return e.err
+}
+
+func (e InvalidChunkError) Error() string {
+	return e.err.Error()
+}
+
+// IsInvalidChunkError returns whether the given error is an InvalidChunkError error
+func IsInvalidChunkError(err error) bool {
+	var errInvalidChunk InvalidChunkError
+	return errors.As(err, &errInvalidChunk)
+}
@@ -0,0 +1,375 @@
+package segmentstream
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	hotstuffmock "github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// snapshotFixture returns a snapshot whose sealing segment holds the given number of extra blocks
+// and blocks, and the given number of blocks following the head of the snapshot.
+func snapshotFixture(extraBlocks, blocks, following int) (*inmem.Snapshot, []*flow.Block) {
+	chain := unittest.ChainFixtureFrom(extraBlocks+blocks+following, unittest.BlockHeaderFixture())
+	head := chain[extraBlocks+blocks-1]
+
+	snapshot := inmem.SnapshotFromEncodable(inmem.EncodableSnapshot{
+		Head: head.Header,
+		SealingSegment: &flow.SealingSegment{
+			ExtraBlocks: chain[:extraBlocks],
+			Blocks:      chain[extraBlocks : extraBlocks+blocks],
+			LatestSeals: map[flow.Identifier]flow.Identifier{},
+		},
+		QuorumCertificate: unittest.CertifyBlock(head.Header),
+	})
+	return snapshot, chain[extraBlocks+blocks:]
+}
+
+func blockIDs(blocks []*flow.Block) []flow.Identifier {
+	ids := make([]flow.Identifier, 0, len(blocks))
+	for _, block := range blocks {
+		ids = append(ids, block.ID())
+	}
+	return ids
+}
+
+func TestSplitAssemble(t *testing.T) {
+	snapshot, _ := snapshotFixture(2, 5, 0)
+	segment, err := snapshot.SealingSegment()
+	require.NoError(t, err)
+
+	manifest, chunks, err := Split(snapshot, 3)
+	require.NoError(t, err)
+	require.NoError(t, manifest.Validate())
+	assert.Len(t, manifest.Chunks, 3)
+	assert.Len(t, chunks, 3)
+	assert.Equal(t, uint(2), manifest.ExtraBlocks)
+	assert.Empty(t, manifest.Snapshot.SealingSegment.Blocks)
+	assert.Empty(t, manifest.Snapshot.SealingSegment.ExtraBlocks)
+
+	assembled, err := Assemble(manifest, chunks)
+	require.NoError(t, err)
+	assembledSegment, err := assembled.SealingSegment()
+	require.NoError(t, err)
+	assert.Equal(t, blockIDs(segment.ExtraBlocks), blockIDs(assembledSegment.ExtraBlocks))
+	assert.Equal(t, blockIDs(segment.Blocks), blockIDs(assembledSegment.Blocks))
+
+	// the blocks of the original snapshot are not stripped
+	assert.Len(t, segment.Blocks, 5)
+}
+
+func TestVerifyChunk(t *testing.T) {
+	snapshot, _ := snapshotFixture(0, 6, 0)
+
+	t.Run("valid", func(t *testing.T) {
+		manifest, chunks, err := Split(snapshot, 3)
+		require.NoError(t, err)
+		for _, chunk := range chunks {
+			assert.NoError(t, manifest.VerifyChunk(chunk))
+		}
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		manifest, chunks, err := Split(snapshot, 3)
+		require.NoError(t, err)
+		block := *chunks[1].Blocks[0]
+		payload := unittest.PayloadFixture(unittest.WithGuarantees(unittest.CollectionGuaranteeFixture()))
+		block.Payload = &payload
+		chunks[1].Blocks = []*flow.Block{&block, chunks[1].Blocks[1], chunks[1].Blocks[2]}
+
+		err = manifest.VerifyChunk(chunks[1])
+		assert.True(t, IsInvalidChunkError(err))
+	})
+
+	t.Run("reordered blocks", func(t *testing.T) {
+		manifest, chunks, err := Split(snapshot, 3)
+		require.NoError(t, err)
+		chunk := &Chunk{
+			HeadID: chunks[0].HeadID,
+			Index:  0,
+			Blocks: []*flow.Block{chunks[0].Blocks[1], chunks[0].Blocks[0], chunks[0].Blocks[2]},
+		}
+
+		err = manifest.VerifyChunk(chunk)
+		assert.True(t, IsInvalidChunkError(err))
+	})
+
+	t.Run("chunk of other head", func(t *testing.T) {
+		manifest, chunks, err := Split(snapshot, 3)
+		require.NoError(t, err)
+		chunks[0].HeadID = unittest.IdentifierFixture()
+
+		err = manifest.VerifyChunk(chunks[0])
+		assert.True(t, IsInvalidChunkError(err))
+	})
+
+	t.Run("manifest not certifying head", func(t *testing.T) {
+		manifest, chunks, err := Split(snapshot, 3)
+		require.NoError(t, err)
+		manifest.Snapshot.QuorumCertificate = unittest.QuorumCertificateFixture()
+
+		_, err = Assemble(manifest, chunks)
+		assert.True(t, IsInvalidChunkError(err))
+	})
+}
+
+func TestVerifyFollowingChunk(t *testing.T) {
+	snapshot, following := snapshotFixture(0, 2, 4)
+	manifest, _, err := Split(snapshot, 2)
+	require.NoError(t, err)
+	head, err := snapshot.Head()
+	require.NoError(t, err)
+
+	first := &Chunk{HeadID: manifest.HeadID, Index: 1, Blocks: following[:2]}
+	second := &Chunk{HeadID: manifest.HeadID, Index: 2, Blocks: following[2:]}
+	assert.NoError(t, manifest.VerifyFollowingChunk(head, first))
+	assert.NoError(t, manifest.VerifyFollowingChunk(following[1].Header, second))
+
+	// following chunks must extend the given parent
+	err = manifest.VerifyFollowingChunk(head, second)
+	assert.True(t, IsInvalidChunkError(err))
+
+	// segment chunks are not following chunks
+	err = manifest.VerifyFollowingChunk(head, &Chunk{HeadID: manifest.HeadID, Index: 0})
+	assert.True(t, IsInvalidChunkError(err))
+}
+
+// sourceStub serves the chunks of a snapshot and the blocks following it, failing requests for
+// the chunks in failing.
+type sourceStub struct {
+	manifest  *Manifest
+	chunks    []*Chunk
+	following []*flow.Block
+	failing   map[uint]bool
+	requested []uint
+}
+
+func newSourceStub(t *testing.T, snapshot *inmem.Snapshot, following []*flow.Block, chunkSize uint) *sourceStub {
+	manifest, chunks, err := Split(snapshot, chunkSize)
+	require.NoError(t, err)
+	return &sourceStub{
+		manifest:  manifest,
+		chunks:    chunks,
+		following: following,
+		failing:   make(map[uint]bool),
+	}
+}
+
+func (s *sourceStub) Manifest(_ context.Context, _ flow.Identifier) (*Manifest, error) {
+	return s.manifest, nil
+}
+
+func (s *sourceStub) Chunk(_ context.Context, headID flow.Identifier, index uint) (*Chunk, error) {
+	s.requested = append(s.requested, index)
+	if s.failing[index] {
+		return nil, fmt.Errorf("chunk %d unavailable", index)
+	}
+	if !s.manifest.IsFollowing(index) {
+		return s.chunks[index], nil
+	}
+
+	from, to := s.manifest.FollowingHeights(index)
+	chunk := &Chunk{HeadID: headID, Index: index}
+	for _, block := range s.following {
+		if block.Header.Height >= from && block.Header.Height <= to {
+			chunk.Blocks = append(chunk.Blocks, block)
+		}
+	}
+	return chunk, nil
+}
+
+func TestDownload(t *testing.T) {
+	snapshot, following := snapshotFixture(1, 4, 5)
+	source := newSourceStub(t, snapshot, following, 2)
+	config := DownloaderConfig{RetryBaseWait: time.Millisecond, RetryMax: 0}
+	dir := t.TempDir()
+
+	// the first download is interrupted by an unavailable chunk
+	source.failing[1] = true
+	_, _, err := NewDownloader(zerolog.Nop(), config, source, dir).Download(context.Background(), flow.ZeroID)
+	require.Error(t, err)
+	assert.Equal(t, []uint{0, 1}, source.requested)
+
+	// the resumed download only requests the chunks which were not downloaded yet
+	source.failing = make(map[uint]bool)
+	source.requested = nil
+	downloaded, downloadedFollowing, err := NewDownloader(zerolog.Nop(), config, source, dir).Download(context.Background(), flow.ZeroID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2, 3, 4, 5}, source.requested)
+
+	segment, err := snapshot.SealingSegment()
+	require.NoError(t, err)
+	downloadedSegment, err := downloaded.SealingSegment()
+	require.NoError(t, err)
+	assert.Equal(t, blockIDs(segment.AllBlocks()), blockIDs(downloadedSegment.AllBlocks()))
+	assert.Equal(t, blockIDs(following), blockIDs(downloadedFollowing))
+
+	// complete following chunks are persisted, the last incomplete one is requested again
+	source.requested = nil
+	_, _, err = NewDownloader(zerolog.Nop(), config, source, dir).Download(context.Background(), flow.ZeroID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{5}, source.requested)
+}
+
+func TestDownload_InvalidChunk(t *testing.T) {
+	snapshot, _ := snapshotFixture(0, 4, 0)
+	source := newSourceStub(t, snapshot, nil, 2)
+	source.chunks[1] = source.chunks[0]
+	config := DownloaderConfig{RetryBaseWait: time.Millisecond, RetryMax: 1}
+
+	_, _, err := NewDownloader(zerolog.Nop(), config, source, t.TempDir()).Download(context.Background(), flow.ZeroID)
+	assert.True(t, IsInvalidChunkError(err))
+	assert.Equal(t, []uint{0, 1, 1}, source.requested)
+}
+
+// providerFixture returns a provider serving the given snapshots, which are all finalized, and counts
+// the number of snapshots built.
+func providerFixture(t *testing.T, config ProviderConfig, final *flow.Header, snapshots ...*inmem.Snapshot) (*Provider, *int) {
+	headers := storagemock.NewHeaders(t)
+	bySnapshotID := make(map[flow.Identifier]*inmem.Snapshot)
+	for _, snapshot := range snapshots {
+		head, err := snapshot.Head()
+		require.NoError(t, err)
+		bySnapshotID[head.ID()] = snapshot
+		headers.On("ByBlockID", head.ID()).Return(head, nil).Maybe()
+		headers.On("BlockIDByHeight", head.Height).Return(head.ID(), nil).Maybe()
+	}
+
+	finalSnapshot := protocolmock.NewSnapshot(t)
+	finalSnapshot.On("Head").Return(final, nil).Maybe()
+	state := protocolmock.NewState(t)
+	state.On("Final").Return(finalSnapshot).Maybe()
+
+	provider, err := NewProvider(config, state, headers, storagemock.NewBlocks(t))
+	require.NoError(t, err)
+
+	built := 0
+	provider.snapshot = func(blockID flow.Identifier) (*inmem.Snapshot, error) {
+		built++
+		return bySnapshotID[blockID], nil
+	}
+	return provider, &built
+}
+
+func TestProvider(t *testing.T) {
+	first, _ := snapshotFixture(0, 3, 0)
+	second, _ := snapshotFixture(0, 3, 0)
+	third, _ := snapshotFixture(0, 3, 0)
+	headID := func(snapshot *inmem.Snapshot) flow.Identifier {
+		head, err := snapshot.Head()
+		require.NoError(t, err)
+		return head.ID()
+	}
+
+	t.Run("caches several segments", func(t *testing.T) {
+		config := DefaultProviderConfig()
+		config.CachedSegments = 2
+		config.BuildRate = rate.Inf
+		provider, built := providerFixture(t, config, unittest.BlockHeaderFixture(), first, second, third)
+
+		// alternating requests of two heads are served from the cache
+		for i := 0; i < 3; i++ {
+			for _, snapshot := range []*inmem.Snapshot{first, second} {
+				manifest, err := provider.Manifest(headID(snapshot))
+				require.NoError(t, err)
+				assert.Equal(t, headID(snapshot), manifest.HeadID)
+			}
+		}
+		assert.Equal(t, 2, *built)
+
+		// a third head evicts the least recently used segment
+		_, err := provider.Chunk(headID(third), 0)
+		require.NoError(t, err)
+		_, err = provider.Manifest(headID(first))
+		require.NoError(t, err)
+		assert.Equal(t, 4, *built)
+	})
+
+	t.Run("rate limits splitting segments", func(t *testing.T) {
+		config := DefaultProviderConfig()
+		config.BuildRate = rate.Every(time.Hour)
+		config.BuildBurst = 1
+		provider, built := providerFixture(t, config, unittest.BlockHeaderFixture(), first, second)
+
+		_, err := provider.Manifest(headID(first))
+		require.NoError(t, err)
+		_, err = provider.Manifest(headID(second))
+		require.ErrorIs(t, err, ErrBuildRateLimited)
+
+		// cached segments are still served
+		_, err = provider.Chunk(headID(first), 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *built)
+	})
+
+	t.Run("serves recent segment as latest", func(t *testing.T) {
+		head, err := first.Head()
+		require.NoError(t, err)
+		final := unittest.BlockHeaderWithParentFixture(head)
+		final.Height = head.Height + 10
+
+		config := DefaultProviderConfig()
+		config.LatestLag = 10
+		config.BuildRate = rate.Inf
+		provider, built := providerFixture(t, config, final, first)
+
+		_, err = provider.Manifest(head.ID())
+		require.NoError(t, err)
+		manifest, err := provider.LatestManifest()
+		require.NoError(t, err)
+		assert.Equal(t, head.ID(), manifest.HeadID)
+		assert.Equal(t, 1, *built)
+	})
+}
+
+func TestExtendFollowing(t *testing.T) {
+	root := unittest.BlockHeaderFixture()
+	chain := unittest.ChainFixtureFrom(4, root)
+
+	params := protocolmock.NewParams(t)
+	params.On("FinalizedRoot").Return(root, nil)
+
+	t.Run("valid", func(t *testing.T) {
+		state := protocolmock.NewFollowerState(t)
+		state.On("Params").Return(params)
+		validator := hotstuffmock.NewValidator(t)
+		validator.On("ValidateQC", mock.Anything).Return(nil).Times(len(chain))
+		for i := 0; i+1 < len(chain); i++ {
+			state.On("ExtendCertified", mock.Anything, chain[i], chain[i+1].Header.QuorumCertificate()).Return(nil).Once()
+		}
+
+		require.NoError(t, ExtendFollowing(context.Background(), state, validator, chain))
+	})
+
+	t.Run("invalid quorum certificate", func(t *testing.T) {
+		state := protocolmock.NewFollowerState(t)
+		state.On("Params").Return(params)
+		validator := hotstuffmock.NewValidator(t)
+		validator.On("ValidateQC", chain[0].Header.QuorumCertificate()).Return(nil).Once()
+		validator.On("ValidateQC", chain[1].Header.QuorumCertificate()).Return(fmt.Errorf("invalid signature")).Once()
+
+		// the block certified by the invalid certificate is not added
+		require.Error(t, ExtendFollowing(context.Background(), state, validator, chain))
+		state.AssertNotCalled(t, "ExtendCertified", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not extending root block", func(t *testing.T) {
+		state := protocolmock.NewFollowerState(t)
+		state.On("Params").Return(params)
+
+		require.Error(t, ExtendFollowing(context.Background(), state, hotstuffmock.NewValidator(t), chain[1:]))
+	})
+}
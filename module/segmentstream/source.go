@@ -0,0 +1,98 @@
+package segmentstream
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Source provides manifests and chunks of sealing segments, typically served by a peer.
+type Source interface {
+	// Manifest returns the manifest of the sealing segment of the given finalized block, or of the
+	// latest finalized block if the block ID is flow.ZeroID.
+	Manifest(ctx context.Context, blockID flow.Identifier) (*Manifest, error)
+
+	// Chunk returns the chunk with the given index of the sealing segment of the given finalized block.
+	Chunk(ctx context.Context, headID flow.Identifier, index uint) (*Chunk, error)
+}
+
+// HTTPSource retrieves sealing segments from the sealing segment server of an access node.
+type HTTPSource struct {
+	client  *http.Client
+	baseURL string
+}
+
+var _ Source = (*HTTPSource)(nil)
+
+// NewHTTPSource returns a source retrieving sealing segments from the server at the given base URL,
+// such as https://access-001:8071. Connections are established with the given TLS config, which
+// authenticates the server.
+func NewHTTPSource(baseURL string, timeout time.Duration, tlsConfig *tls.Config) *HTTPSource {
+	return &HTTPSource{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *HTTPSource) Manifest(ctx context.Context, blockID flow.Identifier) (*Manifest, error) {
+	query := url.Values{}
+	if blockID != flow.ZeroID {
+		query.Set("block_id", blockID.String())
+	}
+
+	var manifest Manifest
+	err := s.get(ctx, "/v1/sealing_segments", query, &manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func (s *HTTPSource) Chunk(ctx context.Context, headID flow.Identifier, index uint) (*Chunk, error) {
+	var chunk Chunk
+	err := s.get(ctx, fmt.Sprintf("/v1/sealing_segments/%s/chunks/%d", headID, index), nil, &chunk)
+	if err != nil {
+		return nil, err
+	}
+	return &chunk, nil
+}
+
+// get retrieves the resource at the given path and decodes the JSON response into target.
+func (s *HTTPSource) get(ctx context.Context, path string, query url.Values, target interface{}) error {
+	u := s.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not get %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("could not get %s: status %d: %s", u, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(target)
+	if err != nil {
+		return fmt.Errorf("could not decode response of %s: %w", u, err)
+	}
+	return nil
+}
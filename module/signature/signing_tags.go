@@ -56,6 +56,8 @@ var (
 	SPOCKTag = tag("SPoCK")
 	// DKGMessageTag is used for DKG messages
 	DKGMessageTag = tag("DKG_Message")
+	// TransactionReceiptTag is used for transaction acceptance receipts issued by collection nodes
+	TransactionReceiptTag = tag("Transaction_Receipt")
 )
 
 // NewBLSHasher returns a hasher to be used for BLS signing and verifying
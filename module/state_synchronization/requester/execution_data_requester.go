@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/onflow/flow-go/module/state_synchronization"
 	"github.com/onflow/flow-go/module/state_synchronization/requester/jobs"
 	"github.com/onflow/flow-go/module/util"
+	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/logging"
@@ -386,6 +388,13 @@ func (e *executionDataRequester) processFetchRequest(parentCtx irrecoverable.Sig
 	ctx, cancel := context.WithTimeout(parentCtx, fetchTimeout)
 	defer cancel()
 
+	// the heights right above the highest consecutive downloaded height hold back the notifications of all higher
+	// heights, hence their blobs are requested first.
+	ctx = network.WithBlobRequestHint(ctx, network.BlobRequestHint{
+		Height:   height,
+		Priority: blobRequestPriority(height, e.blockConsumer.LastProcessedIndex()),
+	})
+
 	execData, err := e.execDataCache.ByBlockID(ctx, blockID)
 
 	e.metrics.ExecutionDataFetchFinished(time.Since(start), err == nil, height)
@@ -449,6 +458,20 @@ func (e *executionDataRequester) notifyConsumers(executionData *execution_data.B
 	}
 }
 
+// blobRequestPriority returns the priority of the blob requests for the given height, which decreases with the distance
+// to the highest consecutive downloaded height. The next height to download has priority zero, the same as requests
+// without a priority hint.
+func blobRequestPriority(height uint64, lastProcessed uint64) int32 {
+	if height <= lastProcessed+1 {
+		return 0
+	}
+	distance := height - lastProcessed - 1
+	if distance > math.MaxInt32 {
+		return -math.MaxInt32
+	}
+	return -int32(distance)
+}
+
 func isInvalidBlobError(err error) bool {
 	var malformedDataError *execution_data.MalformedDataError
 	var blobSizeLimitExceededError *execution_data.BlobSizeLimitExceededError
@@ -0,0 +1,28 @@
+package alsp
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordAdmin provides operators with access to the spam records of the ALSP module, to inspect and manually
+// adjust the penalties of nodes, e.g., during production incidents.
+type SpamRecordAdmin interface {
+	// SpamRecords returns a copy of all spam records.
+	SpamRecords() []model.ProtocolSpamRecord
+
+	// SpamRecord returns a copy of the spam record of the given node.
+	// Returns the record and true if the record exists, nil and false otherwise.
+	SpamRecord(originId flow.Identifier) (*model.ProtocolSpamRecord, bool)
+
+	// SetPenalty sets the penalty of the given node, creating its spam record if it does not exist. The penalty must
+	// not be positive. Disallow-listing and allow-listing of the node follow from the new penalty at the next heartbeat,
+	// exactly as for penalties resulting from misbehavior reports.
+	// Returns the updated record. The returned error is benign and indicates an invalid penalty.
+	SetPenalty(originId flow.Identifier, penalty float64) (*model.ProtocolSpamRecord, error)
+
+	// ClearSpamRecord removes the spam record of the given node, and allow-lists the node if it is disallow-listed
+	// by the ALSP module.
+	// Returns true if the record is removed, false if the record does not exist.
+	ClearSpamRecord(originId flow.Identifier) bool
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/onflow/flow-go/network/alsp/internal"
 	"github.com/onflow/flow-go/network/alsp/model"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -39,6 +40,9 @@ var (
 	// ErrHeartBeatIntervalNotSet is returned when the heartbeat interval is not set, it is a fatal irrecoverable error,
 	// and the ALSP module cannot be initialized.
 	ErrHeartBeatIntervalNotSet = errors.New("heartbeat interval is not set")
+	// ErrPersistenceIntervalNotSet is returned when the spam records are persisted but the persistence interval is not set,
+	// it is a fatal irrecoverable error, and the ALSP module cannot be initialized.
+	ErrPersistenceIntervalNotSet = errors.New("persistence interval is not set")
 )
 
 type SpamRecordCacheFactory func(zerolog.Logger, uint32, module.HeroCacheMetrics) alsp.SpamRecordCache
@@ -79,9 +83,13 @@ type MisbehaviorReportManager struct {
 
 	// workerPool is the worker pool for handling the misbehavior reports in a thread-safe and non-blocking manner.
 	workerPool *worker.Pool[internal.ReportedMisbehaviorWork]
+
+	// store is the local storage the spam records are persisted to. It is nil when persistence is disabled.
+	store alsp.SpamRecordStore
 }
 
 var _ network.MisbehaviorReportManager = (*MisbehaviorReportManager)(nil)
+var _ alsp.SpamRecordAdmin = (*MisbehaviorReportManager)(nil)
 
 type MisbehaviorReportManagerConfig struct {
 	Logger zerolog.Logger
@@ -107,7 +115,16 @@ type MisbehaviorReportManagerConfig struct {
 	// HeartBeatInterval is the interval between the heartbeats. Heartbeat is a recurring event that is used to
 	// apply recurring actions, e.g., decay the penalty of the misbehaving nodes.
 	HeartBeatInterval time.Duration
-	Opts              []MisbehaviorReportManagerOption
+	// SpamRecordStore is the local storage the spam records are persisted to, so that the penalties of misbehaving
+	// nodes survive restarts. On startup, the persisted spam records are restored and decayed for the downtime of the
+	// node as if the node had been running. Optional: when nil, the spam records are kept in memory only.
+	SpamRecordStore alsp.SpamRecordStore
+	// PersistenceInterval is the interval between two consecutive snapshots of the spam records to the SpamRecordStore.
+	// A final snapshot is taken when the ALSP module shuts down. The ALSP module shuts down along with the network,
+	// hence the node must close the database of the store only after its network components are done, as the node
+	// builders do in their post-shutdown cleanup. It is only used when SpamRecordStore is set.
+	PersistenceInterval time.Duration
+	Opts                []MisbehaviorReportManagerOption
 }
 
 // validate validates the MisbehaviorReportManagerConfig instance. It returns an error if the config is invalid.
@@ -130,6 +147,9 @@ func (c MisbehaviorReportManagerConfig) validate() error {
 	if c.HeartBeatInterval == 0 {
 		return ErrHeartBeatIntervalNotSet
 	}
+	if c.SpamRecordStore != nil && c.PersistenceInterval == 0 {
+		return ErrPersistenceIntervalNotSet
+	}
 	return nil
 }
 
@@ -176,6 +196,7 @@ func NewMisbehaviorReportManager(cfg *MisbehaviorReportManagerConfig, consumer n
 		disablePenalty:          cfg.DisablePenalty,
 		disallowListingConsumer: consumer,
 		cacheFactory:            defaultSpamRecordCacheFactory(),
+		store:                   cfg.SpamRecordStore,
 	}
 
 	store := queue.NewHeroStore(
@@ -197,14 +218,38 @@ func NewMisbehaviorReportManager(cfg *MisbehaviorReportManagerConfig, consumer n
 		cfg.SpamRecordCacheSize,
 		metrics.ApplicationLayerSpamRecordCacheMetricFactory(cfg.HeroCacheMetricsFactory, cfg.NetworkType))
 
+	// the persisted spam records are restored before any worker starts, so that misbehavior reports received
+	// on startup are applied on top of the restored penalties.
+	var restored flow.IdentifierList
+	if m.store != nil {
+		var err error
+		restored, err = m.restoreSpamRecords(cfg.HeartBeatInterval)
+		if err != nil {
+			return nil, fmt.Errorf("could not restore persisted spam records: %w", err)
+		}
+	}
+
 	builder := component.NewComponentManagerBuilder()
 	builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+		// nodes that were disallow-listed by the ALSP module before the restart are disallow-listed again.
+		for _, id := range restored {
+			m.disallowListingConsumer.OnDisallowListNotification(&network.DisallowListingUpdate{
+				FlowIds: flow.IdentifierList{id},
+				Cause:   network.DisallowListedCauseAlsp,
+			})
+		}
 		ready()
 		m.heartbeatLoop(ctx, cfg.HeartBeatInterval) // blocking call
 	})
 	for i := 0; i < defaultMisbehaviorReportManagerWorkers; i++ {
 		builder.AddWorker(m.workerPool.WorkerLogic())
 	}
+	if m.store != nil {
+		builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			ready()
+			m.persistenceLoop(ctx, cfg.PersistenceInterval) // blocking call
+		})
+	}
 
 	m.Component = builder.Build()
 
@@ -304,50 +349,32 @@ func (m *MisbehaviorReportManager) onHeartbeat() error {
 
 	for _, id := range allIds {
 		penalty, err := m.cache.Adjust(id, func(record model.ProtocolSpamRecord) (model.ProtocolSpamRecord, error) {
-			if record.Penalty > 0 {
-				// sanity check; this should never happen.
-				return record, fmt.Errorf("illegal state: spam record %x has positive penalty %f", id, record.Penalty)
-			}
-			if record.Decay <= 0 {
-				// sanity check; this should never happen.
-				return record, fmt.Errorf("illegal state: spam record %x has non-positive decay %f", id, record.Decay)
+			record, disallowListed, allowListed, err := decay(record)
+			if err != nil {
+				return record, err
 			}
 
 			// TODO: this can be done in batch but at this stage let's send individual notifications.
 			//       (it requires enabling the batch mode end-to-end including the cache in middleware).
-			// as long as record.Penalty is NOT below model.DisallowListingThreshold,
-			// the node is considered allow-listed and can conduct inbound and outbound connections.
-			// Once it falls below model.DisallowListingThreshold, it needs to be disallow listed.
-			if record.Penalty < model.DisallowListingThreshold && !record.DisallowListed {
-				// cutoff counter keeps track of how many times the penalty has been below the threshold.
-				record.CutoffCounter++
-				record.DisallowListed = true
+			if disallowListed {
 				m.logger.Warn().
 					Str("key", logging.KeySuspicious).
 					Hex("identifier", logging.ID(id)).
 					Uint64("cutoff_counter", record.CutoffCounter).
 					Float64("decay_speed", record.Decay).
-					Bool("disallow_listed", record.DisallowListed).
+					Bool("disallow_listed", true).
 					Msg("node penalty is below threshold, disallow listing")
 				m.disallowListingConsumer.OnDisallowListNotification(&network.DisallowListingUpdate{
 					FlowIds: flow.IdentifierList{id},
 					Cause:   network.DisallowListedCauseAlsp, // sets the ALSP disallow listing cause on node
 				})
 			}
-			// each time we decay the penalty by the decay speed, the penalty is a negative number, and the decay speed
-			// is a positive number. So the penalty is getting closer to zero.
-			// We use math.Min() to make sure the penalty is never positive.
-			record.Penalty = math.Min(record.Penalty+record.Decay, 0)
-
-			// TODO: this can be done in batch but at this stage let's send individual notifications.
-			//       (it requires enabling the batch mode end-to-end including the cache in middleware).
-			if record.Penalty == float64(0) && record.DisallowListed {
-				record.DisallowListed = false
+			if allowListed {
 				m.logger.Info().
 					Hex("identifier", logging.ID(id)).
 					Uint64("cutoff_counter", record.CutoffCounter).
 					Float64("decay_speed", record.Decay).
-					Bool("disallow_listed", record.DisallowListed).
+					Bool("disallow_listed", false).
 					Msg("allow-listing a node that was disallow listed")
 				// Penalty has fully decayed to zero and the node can be back in the allow list.
 				m.disallowListingConsumer.OnAllowListNotification(&network.AllowListingUpdate{
@@ -380,6 +407,52 @@ func (m *MisbehaviorReportManager) onHeartbeat() error {
 	return nil
 }
 
+// decay applies the decay of a single heartbeat to the given spam record. It is a pure function, so that the same
+// decay can be replayed for the downtime of the node when persisted spam records are restored.
+// Args:
+//
+//	record: the spam record to decay.
+//
+// Returns:
+//
+//	the decayed record.
+//	disallowListed: true if the node must be disallow-listed, i.e., its penalty dropped below the disallow-listing threshold.
+//	allowListed: true if the node must be allow-listed again, i.e., its penalty fully decayed while being disallow-listed.
+//	error: if the record is in an illegal state. No error is expected during normal operation. Any returned error must
+//	be considered as irrecoverable.
+func decay(record model.ProtocolSpamRecord) (model.ProtocolSpamRecord, bool, bool, error) {
+	if record.Penalty > 0 {
+		// sanity check; this should never happen.
+		return record, false, false, fmt.Errorf("illegal state: spam record %x has positive penalty %f", record.OriginId, record.Penalty)
+	}
+	if record.Decay <= 0 {
+		// sanity check; this should never happen.
+		return record, false, false, fmt.Errorf("illegal state: spam record %x has non-positive decay %f", record.OriginId, record.Decay)
+	}
+
+	// as long as record.Penalty is NOT below model.DisallowListingThreshold,
+	// the node is considered allow-listed and can conduct inbound and outbound connections.
+	// Once it falls below model.DisallowListingThreshold, it needs to be disallow listed.
+	disallowListed := false
+	if record.Penalty < model.DisallowListingThreshold && !record.DisallowListed {
+		// cutoff counter keeps track of how many times the penalty has been below the threshold.
+		record.CutoffCounter++
+		record.DisallowListed = true
+		disallowListed = true
+	}
+	// each time we decay the penalty by the decay speed, the penalty is a negative number, and the decay speed
+	// is a positive number. So the penalty is getting closer to zero.
+	// We use math.Min() to make sure the penalty is never positive.
+	record.Penalty = math.Min(record.Penalty+record.Decay, 0)
+
+	allowListed := false
+	if record.Penalty == float64(0) && record.DisallowListed {
+		record.DisallowListed = false
+		allowListed = true
+	}
+	return record, disallowListed, allowListed, nil
+}
+
 // processMisbehaviorReport is the worker function that processes the misbehavior reports.
 // It is called by the worker pool.
 // It applies the penalty to the misbehaving node and updates the spam record cache.
@@ -428,3 +501,169 @@ func (m *MisbehaviorReportManager) processMisbehaviorReport(report internal.Repo
 	lg.Debug().Float64("updated_penalty", updatedPenalty).Msg("misbehavior report handled")
 	return nil
 }
+
+// restoreSpamRecords restores the persisted spam records into the spam record cache. The records are decayed for the
+// time elapsed since they were persisted, by replaying the decay of every heartbeat missed during the downtime of the node.
+// Args:
+//
+//	heartBeatInterval: the interval between two heartbeats.
+//
+// Returns:
+//
+//	the identifiers of the nodes that are still disallow-listed by the ALSP module after the decay.
+//	error: if the spam records cannot be restored. No error is expected during normal operation. Any returned error must
+//	be considered as irrecoverable.
+func (m *MisbehaviorReportManager) restoreSpamRecords(heartBeatInterval time.Duration) (flow.IdentifierList, error) {
+	snapshot, err := m.store.Retrieve()
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			m.logger.Info().Msg("no persisted spam records found, starting with empty spam records")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not retrieve persisted spam records: %w", err)
+	}
+
+	downtime := time.Since(snapshot.TakenAt)
+	heartbeats := uint64(0)
+	if downtime > 0 {
+		heartbeats = uint64(downtime / heartBeatInterval)
+	}
+
+	disallowListed := flow.IdentifierList{}
+	for _, record := range snapshot.Records {
+		for i := uint64(0); i < heartbeats; i++ {
+			if record.Penalty == float64(0) && !record.DisallowListed {
+				// the record is fully decayed, further heartbeats do not change it.
+				break
+			}
+			record, _, _, err = decay(record)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decay persisted spam record %x: %w", record.OriginId, err)
+			}
+		}
+
+		restored := record
+		_, err = m.cache.Adjust(record.OriginId, func(model.ProtocolSpamRecord) (model.ProtocolSpamRecord, error) {
+			return restored, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore persisted spam record %x: %w", record.OriginId, err)
+		}
+		if record.DisallowListed {
+			disallowListed = append(disallowListed, record.OriginId)
+		}
+	}
+
+	m.logger.Info().
+		Int("restored_records", len(snapshot.Records)).
+		Int("disallow_listed", len(disallowListed)).
+		Time("taken_at", snapshot.TakenAt).
+		Uint64("replayed_heartbeats", heartbeats).
+		Msg("persisted spam records restored")
+	return disallowListed, nil
+}
+
+// persistenceLoop periodically persists a snapshot of the spam records to the spam record store, and persists a final
+// snapshot when the context is canceled. It is a blocking function, and should be called in a separate goroutine.
+// Persistence is best-effort: failures are logged, and the spam records are retained in memory.
+// Args:
+//
+//	ctx: the context.
+//	interval: the interval between two snapshots.
+//
+// Returns:
+//
+//	none.
+func (m *MisbehaviorReportManager) persistenceLoop(ctx irrecoverable.SignalerContext, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			m.persistSpamRecords()
+			return
+		case <-ticker.C:
+			m.persistSpamRecords()
+		}
+	}
+}
+
+// persistSpamRecords persists a snapshot of the current spam records to the spam record store.
+func (m *MisbehaviorReportManager) persistSpamRecords() {
+	snapshot := &model.SpamRecordSnapshot{
+		TakenAt: time.Now(),
+		Records: m.SpamRecords(),
+	}
+	if err := m.store.Store(snapshot); err != nil {
+		m.logger.Error().Err(err).Msg("failed to persist spam records")
+		return
+	}
+	m.logger.Trace().Int("records", len(snapshot.Records)).Msg("spam records persisted")
+}
+
+// SpamRecords returns a copy of all spam records.
+func (m *MisbehaviorReportManager) SpamRecords() []model.ProtocolSpamRecord {
+	ids := m.cache.Identities()
+	records := make([]model.ProtocolSpamRecord, 0, len(ids))
+	for _, id := range ids {
+		record, ok := m.cache.Get(id)
+		if !ok {
+			// the record was removed concurrently.
+			continue
+		}
+		records = append(records, *record)
+	}
+	return records
+}
+
+// SpamRecord returns a copy of the spam record of the given node.
+// Returns the record and true if the record exists, nil and false otherwise.
+func (m *MisbehaviorReportManager) SpamRecord(originId flow.Identifier) (*model.ProtocolSpamRecord, bool) {
+	return m.cache.Get(originId)
+}
+
+// SetPenalty sets the penalty of the given node, creating its spam record if it does not exist. The penalty must
+// not be positive. Disallow-listing and allow-listing of the node follow from the new penalty at the next heartbeat,
+// exactly as for penalties resulting from misbehavior reports.
+// Returns the updated record. The returned error is benign and indicates an invalid penalty.
+func (m *MisbehaviorReportManager) SetPenalty(originId flow.Identifier, penalty float64) (*model.ProtocolSpamRecord, error) {
+	if penalty > 0 {
+		return nil, fmt.Errorf("penalty must not be positive, got %f", penalty)
+	}
+	var updated model.ProtocolSpamRecord
+	_, err := m.cache.Adjust(originId, func(record model.ProtocolSpamRecord) (model.ProtocolSpamRecord, error) {
+		record.Penalty = penalty
+		updated = record
+		return record, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set penalty of spam record %x: %w", originId, err)
+	}
+	m.logger.Warn().
+		Hex("identifier", logging.ID(originId)).
+		Float64("penalty", penalty).
+		Msg("penalty of node set manually")
+	return &updated, nil
+}
+
+// ClearSpamRecord removes the spam record of the given node, and allow-lists the node if it is disallow-listed
+// by the ALSP module.
+// Returns true if the record is removed, false if the record does not exist.
+func (m *MisbehaviorReportManager) ClearSpamRecord(originId flow.Identifier) bool {
+	record, ok := m.cache.Get(originId)
+	if !ok || !m.cache.Remove(originId) {
+		return false
+	}
+	m.logger.Warn().
+		Hex("identifier", logging.ID(originId)).
+		Float64("penalty", record.Penalty).
+		Bool("disallow_listed", record.DisallowListed).
+		Msg("spam record of node cleared manually")
+	if record.DisallowListed {
+		m.disallowListingConsumer.OnAllowListNotification(&network.AllowListingUpdate{
+			FlowIds: flow.IdentifierList{originId},
+			Cause:   network.DisallowListedCauseAlsp,
+		})
+	}
+	return true
+}
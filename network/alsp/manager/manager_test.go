@@ -31,6 +31,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p"
 	p2ptest "github.com/onflow/flow-go/network/p2p/test"
 	"github.com/onflow/flow-go/network/slashing"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -1651,3 +1652,142 @@ func managerCfgFixture(t *testing.T) *alspmgr.MisbehaviorReportManagerConfig {
 		HeroCacheMetricsFactory: metrics.NewNoopHeroCacheMetricsFactory(),
 	}
 }
+
+// TestSpamRecordPersistence_RestoreWithDecay tests that persisted spam records are restored on startup, with the decay
+// of the heartbeats missed during the downtime replayed. A node that is still disallow-listed after the decay is
+// disallow-listed again on startup, while a node whose penalty fully decayed during the downtime is restored as allow-listed.
+func TestSpamRecordPersistence_RestoreWithDecay(t *testing.T) {
+	cfg := managerCfgFixture(t)
+	cfg.HeartBeatInterval = time.Hour // no heartbeat ticks during the test
+	cfg.PersistenceInterval = time.Hour
+	consumer := mocknetwork.NewDisallowListNotificationConsumer(t)
+
+	decay := model.SpamRecordFactory()(unittest.IdentifierFixture()).Decay
+	stillDisallowListed := unittest.IdentifierFixture()
+	recovered := unittest.IdentifierFixture()
+	snapshot := &model.SpamRecordSnapshot{
+		// the node was down for 10 heartbeats.
+		TakenAt: time.Now().Add(-10*cfg.HeartBeatInterval - time.Minute),
+		Records: []model.ProtocolSpamRecord{
+			{OriginId: stillDisallowListed, Decay: decay, CutoffCounter: 1, DisallowListed: true, Penalty: model.DisallowListingThreshold},
+			{OriginId: recovered, Decay: decay, CutoffCounter: 1, DisallowListed: true, Penalty: -5 * decay},
+		},
+	}
+	store := mockalsp.NewSpamRecordStore(t)
+	store.On("Retrieve").Return(snapshot, nil).Once()
+	cfg.SpamRecordStore = store
+
+	m, err := alspmgr.NewMisbehaviorReportManager(cfg, consumer)
+	require.NoError(t, err)
+
+	record, ok := m.SpamRecord(stillDisallowListed)
+	require.True(t, ok)
+	require.Equal(t, float64(model.DisallowListingThreshold)+10*decay, record.Penalty)
+	require.True(t, record.DisallowListed)
+	require.Equal(t, uint64(1), record.CutoffCounter)
+
+	record, ok = m.SpamRecord(recovered)
+	require.True(t, ok)
+	require.Equal(t, float64(0), record.Penalty)
+	require.False(t, record.DisallowListed)
+	require.Equal(t, uint64(1), record.CutoffCounter)
+
+	// only the node that is still disallow-listed is disallow-listed again on startup.
+	consumer.On("OnDisallowListNotification", &network.DisallowListingUpdate{
+		FlowIds: flow.IdentifierList{stillDisallowListed},
+		Cause:   network.DisallowListedCauseAlsp,
+	}).Return().Once()
+
+	// a final snapshot of the restored records is persisted on shutdown.
+	persisted := make(chan *model.SpamRecordSnapshot, 1)
+	store.On("Store", mock.Anything).Run(func(args mock.Arguments) {
+		persisted <- args.Get(0).(*model.SpamRecordSnapshot)
+	}).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	m.Start(signalerCtx)
+	unittest.RequireCloseBefore(t, m.Ready(), 100*time.Millisecond, "ALSP manager did not start")
+
+	cancel()
+	unittest.RequireCloseBefore(t, m.Done(), 100*time.Millisecond, "ALSP manager did not stop")
+
+	select {
+	case s := <-persisted:
+		require.ElementsMatch(t, m.SpamRecords(), s.Records)
+	default:
+		t.Fatal("spam records were not persisted on shutdown")
+	}
+}
+
+// TestSpamRecordPersistence_NoSnapshot tests that the ALSP manager starts with empty spam records when no spam records
+// were persisted, and that persistence requires a persistence interval.
+func TestSpamRecordPersistence_NoSnapshot(t *testing.T) {
+	cfg := managerCfgFixture(t)
+	consumer := mocknetwork.NewDisallowListNotificationConsumer(t)
+	store := mockalsp.NewSpamRecordStore(t)
+	cfg.SpamRecordStore = store
+
+	m, err := alspmgr.NewMisbehaviorReportManager(cfg, consumer)
+	require.ErrorIs(t, err, alspmgr.ErrPersistenceIntervalNotSet)
+	require.Nil(t, m)
+
+	cfg.PersistenceInterval = time.Hour
+	store.On("Retrieve").Return(nil, storage.ErrNotFound).Once()
+	m, err = alspmgr.NewMisbehaviorReportManager(cfg, consumer)
+	require.NoError(t, err)
+	require.Empty(t, m.SpamRecords())
+}
+
+// TestSpamRecordAdmin tests the manual inspection, adjustment and clearing of the spam records. A penalty set below the
+// disallow-listing threshold disallow-lists the node at the next heartbeat, and clearing the spam record of a disallow-listed
+// node allow-lists the node.
+func TestSpamRecordAdmin(t *testing.T) {
+	cfg := managerCfgFixture(t)
+	consumer := mocknetwork.NewDisallowListNotificationConsumer(t)
+
+	m, err := alspmgr.NewMisbehaviorReportManager(cfg, consumer)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		unittest.RequireCloseBefore(t, m.Done(), 100*time.Millisecond, "ALSP manager did not stop")
+	}()
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	m.Start(signalerCtx)
+	unittest.RequireCloseBefore(t, m.Ready(), 100*time.Millisecond, "ALSP manager did not start")
+
+	originId := unittest.IdentifierFixture()
+	_, ok := m.SpamRecord(originId)
+	require.False(t, ok)
+	require.False(t, m.ClearSpamRecord(originId))
+
+	_, err = m.SetPenalty(originId, 1)
+	require.Error(t, err)
+
+	consumer.On("OnDisallowListNotification", &network.DisallowListingUpdate{
+		FlowIds: flow.IdentifierList{originId},
+		Cause:   network.DisallowListedCauseAlsp,
+	}).Return().Once()
+
+	record, err := m.SetPenalty(originId, 10*model.DisallowListingThreshold)
+	require.NoError(t, err)
+	require.Equal(t, originId, record.OriginId)
+	require.Equal(t, float64(10*model.DisallowListingThreshold), record.Penalty)
+	require.Len(t, m.SpamRecords(), 1)
+
+	require.Eventually(t, func() bool {
+		record, ok := m.SpamRecord(originId)
+		return ok && record.DisallowListed
+	}, 2*time.Second, 10*time.Millisecond, "node was not disallow-listed")
+
+	consumer.On("OnAllowListNotification", &network.AllowListingUpdate{
+		FlowIds: flow.IdentifierList{originId},
+		Cause:   network.DisallowListedCauseAlsp,
+	}).Return().Once()
+
+	require.True(t, m.ClearSpamRecord(originId))
+	_, ok = m.SpamRecord(originId)
+	require.False(t, ok)
+}
@@ -0,0 +1,81 @@
+package alspmgr
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// BadgerSpamRecordStore is a badger-backed store of the ALSP spam records. Snapshots are kept per networking type,
+// so that the private and public networks of a node can share the same database.
+type BadgerSpamRecordStore struct {
+	db          *badger.DB
+	networkType network.NetworkingType
+}
+
+var _ alsp.SpamRecordStore = (*BadgerSpamRecordStore)(nil)
+
+// NewBadgerSpamRecordStore creates a new store of the ALSP spam records of the given networking type in the given database.
+func NewBadgerSpamRecordStore(db *badger.DB, networkType network.NetworkingType) *BadgerSpamRecordStore {
+	return &BadgerSpamRecordStore{
+		db:          db,
+		networkType: networkType,
+	}
+}
+
+// Store persists the given snapshot, overwriting any previously stored snapshot.
+// No errors are expected during normal operations. Storing a snapshot after the database has been closed returns
+// an error instead of writing to the closed database.
+func (s *BadgerSpamRecordStore) Store(snapshot *model.SpamRecordSnapshot) error {
+	if s.db.IsClosed() {
+		return fmt.Errorf("could not persist spam records: database is closed")
+	}
+	persisted := &storage.AlspSpamRecordSnapshot{
+		TakenAt: snapshot.TakenAt,
+		Records: make([]storage.AlspSpamRecord, 0, len(snapshot.Records)),
+	}
+	for _, record := range snapshot.Records {
+		persisted.Records = append(persisted.Records, storage.AlspSpamRecord{
+			OriginID:       record.OriginId,
+			Decay:          record.Decay,
+			CutoffCounter:  record.CutoffCounter,
+			DisallowListed: record.DisallowListed,
+			Penalty:        record.Penalty,
+		})
+	}
+	err := s.db.Update(operation.UpsertAlspSpamRecords(s.networkType.String(), persisted))
+	if err != nil {
+		return fmt.Errorf("could not persist spam records: %w", err)
+	}
+	return nil
+}
+
+// Retrieve returns the latest stored snapshot.
+// Returns storage.ErrNotFound if no snapshot has been stored yet.
+func (s *BadgerSpamRecordStore) Retrieve() (*model.SpamRecordSnapshot, error) {
+	var persisted storage.AlspSpamRecordSnapshot
+	err := s.db.View(operation.RetrieveAlspSpamRecords(s.networkType.String(), &persisted))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve spam records: %w", err)
+	}
+	snapshot := &model.SpamRecordSnapshot{
+		TakenAt: persisted.TakenAt,
+		Records: make([]model.ProtocolSpamRecord, 0, len(persisted.Records)),
+	}
+	for _, record := range persisted.Records {
+		snapshot.Records = append(snapshot.Records, model.ProtocolSpamRecord{
+			OriginId:       record.OriginID,
+			Decay:          record.Decay,
+			CutoffCounter:  record.CutoffCounter,
+			DisallowListed: record.DisallowListed,
+			Penalty:        record.Penalty,
+		})
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,50 @@
+package alspmgr_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network"
+	alspmgr "github.com/onflow/flow-go/network/alsp/manager"
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestBadgerSpamRecordStore tests that the badger spam record store retrieves the spam records it stored, keeps the
+// spam records of the private and public networks apart, and refuses to store once the database is closed.
+func TestBadgerSpamRecordStore(t *testing.T) {
+	db, dir := unittest.TempBadgerDB(t)
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	private := alspmgr.NewBadgerSpamRecordStore(db, network.PrivateNetwork)
+	public := alspmgr.NewBadgerSpamRecordStore(db, network.PublicNetwork)
+
+	_, err := private.Retrieve()
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	snapshot := &model.SpamRecordSnapshot{
+		TakenAt: time.Now().UTC().Truncate(time.Second),
+		Records: []model.ProtocolSpamRecord{
+			{OriginId: unittest.IdentifierFixture(), Decay: 1000, Penalty: -10},
+			{OriginId: unittest.IdentifierFixture(), Decay: 100, CutoffCounter: 2, DisallowListed: true, Penalty: -100_000},
+		},
+	}
+	require.NoError(t, private.Store(snapshot))
+
+	restored, err := private.Retrieve()
+	require.NoError(t, err)
+	require.True(t, snapshot.TakenAt.Equal(restored.TakenAt))
+	require.Equal(t, snapshot.Records, restored.Records)
+
+	_, err = public.Retrieve()
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	require.NoError(t, db.Close())
+	require.Error(t, private.Store(snapshot))
+}
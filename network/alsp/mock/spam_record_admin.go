@@ -0,0 +1,112 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mockalsp
+
+import (
+	flow "github.com/onflow/flow-go/model/flow"
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordAdmin is an autogenerated mock type for the SpamRecordAdmin type
+type SpamRecordAdmin struct {
+	mock.Mock
+}
+
+// ClearSpamRecord provides a mock function with given fields: originId
+func (_m *SpamRecordAdmin) ClearSpamRecord(originId flow.Identifier) bool {
+	ret := _m.Called(originId)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(flow.Identifier) bool); ok {
+		r0 = rf(originId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// SetPenalty provides a mock function with given fields: originId, penalty
+func (_m *SpamRecordAdmin) SetPenalty(originId flow.Identifier, penalty float64) (*model.ProtocolSpamRecord, error) {
+	ret := _m.Called(originId, penalty)
+
+	var r0 *model.ProtocolSpamRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(flow.Identifier, float64) (*model.ProtocolSpamRecord, error)); ok {
+		return rf(originId, penalty)
+	}
+	if rf, ok := ret.Get(0).(func(flow.Identifier, float64) *model.ProtocolSpamRecord); ok {
+		r0 = rf(originId, penalty)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProtocolSpamRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(flow.Identifier, float64) error); ok {
+		r1 = rf(originId, penalty)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SpamRecord provides a mock function with given fields: originId
+func (_m *SpamRecordAdmin) SpamRecord(originId flow.Identifier) (*model.ProtocolSpamRecord, bool) {
+	ret := _m.Called(originId)
+
+	var r0 *model.ProtocolSpamRecord
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(flow.Identifier) (*model.ProtocolSpamRecord, bool)); ok {
+		return rf(originId)
+	}
+	if rf, ok := ret.Get(0).(func(flow.Identifier) *model.ProtocolSpamRecord); ok {
+		r0 = rf(originId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProtocolSpamRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(flow.Identifier) bool); ok {
+		r1 = rf(originId)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// SpamRecords provides a mock function with given fields:
+func (_m *SpamRecordAdmin) SpamRecords() []model.ProtocolSpamRecord {
+	ret := _m.Called()
+
+	var r0 []model.ProtocolSpamRecord
+	if rf, ok := ret.Get(0).(func() []model.ProtocolSpamRecord); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProtocolSpamRecord)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSpamRecordAdmin interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSpamRecordAdmin creates a new instance of SpamRecordAdmin. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSpamRecordAdmin(t mockConstructorTestingTNewSpamRecordAdmin) *SpamRecordAdmin {
+	mock := &SpamRecordAdmin{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
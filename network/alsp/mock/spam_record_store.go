@@ -0,0 +1,69 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mockalsp
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordStore is an autogenerated mock type for the SpamRecordStore type
+type SpamRecordStore struct {
+	mock.Mock
+}
+
+// Retrieve provides a mock function with given fields:
+func (_m *SpamRecordStore) Retrieve() (*model.SpamRecordSnapshot, error) {
+	ret := _m.Called()
+
+	var r0 *model.SpamRecordSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*model.SpamRecordSnapshot, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *model.SpamRecordSnapshot); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SpamRecordSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: snapshot
+func (_m *SpamRecordStore) Store(snapshot *model.SpamRecordSnapshot) error {
+	ret := _m.Called(snapshot)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.SpamRecordSnapshot) error); ok {
+		r0 = rf(snapshot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSpamRecordStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSpamRecordStore creates a new instance of SpamRecordStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSpamRecordStore(t mockConstructorTestingTNewSpamRecordStore) *SpamRecordStore {
+	mock := &SpamRecordStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// SpamRecordSnapshot is a point-in-time copy of the spam records of the ALSP module, as persisted to local storage
+// so that penalties survive restarts of the node.
+type SpamRecordSnapshot struct {
+	// TakenAt is the time the snapshot was taken. It is used to replay the decay of the penalties for the time
+	// the node was down when the snapshot is restored.
+	TakenAt time.Time
+
+	// Records are the spam records at the time of the snapshot. The disallow-listing state of each node is part of
+	// its record, i.e., the ALSP disallow-listing cause of a node is restored along with its record.
+	Records []ProtocolSpamRecord
+}
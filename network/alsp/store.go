@@ -0,0 +1,17 @@
+package alsp
+
+import (
+	"github.com/onflow/flow-go/network/alsp/model"
+)
+
+// SpamRecordStore is the local storage of the spam records of the ALSP module. It keeps the latest snapshot of the
+// spam records, so that the penalties of misbehaving nodes survive restarts of the node.
+type SpamRecordStore interface {
+	// Store persists the given snapshot, overwriting any previously stored snapshot.
+	// No errors are expected during normal operations.
+	Store(snapshot *model.SpamRecordSnapshot) error
+
+	// Retrieve returns the latest stored snapshot.
+	// Returns storage.ErrNotFound if no snapshot has been stored yet.
+	Retrieve() (*model.SpamRecordSnapshot, error)
+}
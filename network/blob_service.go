@@ -52,4 +52,28 @@ type BlobService interface {
 
 type BlobServiceOption func(BlobService)
 
+// BlobRequestHint carries hints about a request for blobs to the blob service, which uses them to schedule the request
+// and to pick the peers to request the blobs from.
+type BlobRequestHint struct {
+	// Height is the height of the block the requested blobs belong to.
+	Height uint64
+	// Priority is the priority of the request, requests with a higher priority are served first. Requests without a
+	// hint have priority zero.
+	Priority int32
+}
+
+type blobRequestHintKey struct{}
+
+// WithBlobRequestHint returns a copy of the context carrying the given hint, which applies to all blobs requested with
+// the returned context.
+func WithBlobRequestHint(ctx context.Context, hint BlobRequestHint) context.Context {
+	return context.WithValue(ctx, blobRequestHintKey{}, hint)
+}
+
+// BlobRequestHintFromContext returns the hint carried by the context, and false if the context carries no hint.
+func BlobRequestHintFromContext(ctx context.Context) (BlobRequestHint, bool) {
+	hint, ok := ctx.Value(blobRequestHintKey{}).(BlobRequestHint)
+	return hint, ok
+}
+
 var ErrBlobNotFound = errors.New("blobservice: key not found")
@@ -0,0 +1,179 @@
+package capture_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/channels"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/utils/unittest"
+	mocknet "github.com/onflow/flow-go/utils/unittest/network"
+)
+
+// TestFile_RoundTrip evaluates that records read from a capture file are the records written to it.
+func TestFile_RoundTrip(t *testing.T) {
+	records := []*capture.Record{
+		record(t, capture.DirectionOutbound, channels.ConsensusCommittee, vote()),
+		record(t, capture.DirectionInbound, channels.SyncCommittee, &messages.SyncRequest{Nonce: 1, Height: 10}),
+	}
+
+	buf := new(bytes.Buffer)
+	w := capture.NewWriter(buf)
+	for _, r := range records {
+		require.NoError(t, w.Write(r))
+	}
+
+	r := capture.NewReader(buf)
+	for _, expected := range records {
+		actual, err := r.Next()
+		require.NoError(t, err)
+		require.True(t, expected.Timestamp.Equal(actual.Timestamp))
+		actual.Timestamp = expected.Timestamp
+		require.Equal(t, expected, actual)
+	}
+	_, err := r.Next()
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = capture.NewReader(bytes.NewBufferString("NOTCAPTURE")).Next()
+	require.ErrorIs(t, err, capture.ErrInvalidFile)
+}
+
+// TestFilter_Match evaluates that records are matched by every criterion of the filter.
+func TestFilter_Match(t *testing.T) {
+	clusterChannel := channels.SyncCluster(flow.ChainID("cluster"))
+	r := record(t, capture.DirectionInbound, clusterChannel, &messages.SyncRequest{Nonce: 1, Height: 10})
+
+	require.True(t, capture.Filter{}.Match(r))
+	require.True(t, capture.Filter{Channels: channels.ChannelList{channels.Channel(channels.SyncClusterPrefix)}}.Match(r))
+	require.False(t, capture.Filter{Channels: channels.ChannelList{channels.ConsensusCommittee}}.Match(r))
+	require.True(t, capture.Filter{Origins: flow.IdentifierList{r.OriginID}}.Match(r))
+	require.False(t, capture.Filter{Origins: flow.IdentifierList{unittest.IdentifierFixture()}}.Match(r))
+	require.True(t, capture.Filter{Types: []string{"SyncRequest"}}.Match(r))
+	require.True(t, capture.Filter{Types: []string{"*messages.SyncRequest"}}.Match(r))
+	require.False(t, capture.Filter{Types: []string{"Request"}}.Match(r))
+	require.True(t, capture.Filter{Direction: capture.DirectionInbound}.Match(r))
+	require.False(t, capture.Filter{Direction: capture.DirectionOutbound}.Match(r))
+	require.True(t, capture.Filter{Since: r.Timestamp.Add(-time.Second), Until: r.Timestamp.Add(time.Second)}.Match(r))
+	require.False(t, capture.Filter{Since: r.Timestamp.Add(time.Second)}.Match(r))
+	require.False(t, capture.Filter{Until: r.Timestamp.Add(-time.Second)}.Match(r))
+}
+
+// TestCapturer_ReplayCapture evaluates that the messages sent through the conduits and received by the overlay of a
+// capturing node on its captured channels are captured, and that they are injected into the engines of a node when
+// replaying the capture file.
+func TestCapturer_ReplayCapture(t *testing.T) {
+	me := unittest.IdentifierFixture()
+	path := filepath.Join(t.TempDir(), "capture.bin")
+	capturer, err := capture.NewCapturer(unittest.Logger(), path, me, channels.ChannelList{channels.ConsensusCommittee})
+	require.NoError(t, err)
+
+	// outbound messages are captured by the conduits of captured channels only
+	sent := vote()
+	target := unittest.IdentifierFixture()
+	con := mocknetwork.NewConduit(t)
+	con.On("Unicast", sent, target).Return(nil).Once()
+	factory := mocknetwork.NewConduitFactory(t)
+	factory.On("NewConduit", mock.Anything, mock.Anything).Return(con, nil)
+
+	cf := capture.NewConduitFactory(factory, capturer)
+	captured, err := cf.NewConduit(context.Background(), channels.ConsensusCommittee)
+	require.NoError(t, err)
+	require.NoError(t, captured.Unicast(sent, target))
+	notCaptured, err := cf.NewConduit(context.Background(), channels.SyncCommittee)
+	require.NoError(t, err)
+	require.Equal(t, con, notCaptured)
+
+	// inbound messages are captured before being passed on to the overlay
+	origin := unittest.IdentifierFixture()
+	received := vote()
+	scope := incomingScope(t, origin, channels.ConsensusCommittee, received)
+	ov := mocknetwork.NewOverlay(t)
+	ov.On("Receive", scope).Return(nil).Once()
+	require.NoError(t, capture.NewOverlay(ov, capturer).Receive(scope))
+	require.NoError(t, capturer.Close())
+
+	// replaying the capture file injects the messages into the engines of the mock network
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	net := mocknet.NewNetwork()
+	engine := mocknet.NewEngine()
+	_, err = net.Register(channels.ConsensusCommittee, engine)
+	require.NoError(t, err)
+	engine.On("Process", channels.ConsensusCommittee, origin, received).Return(nil).Once()
+
+	// only the inbound message is replayed, even though the outbound message was captured on a registered channel
+	err = net.ReplayCapture(capture.NewReader(file), capture.Filter{})
+	require.NoError(t, err)
+	engine.AssertExpectations(t)
+
+	// outbound messages cannot be replayed as received messages
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	err = net.ReplayCapture(capture.NewReader(file), capture.Filter{Direction: capture.DirectionOutbound})
+	require.Error(t, err)
+
+	// the outbound message has been captured with the capturing node as origin
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	var replayed []*capture.Record
+	r := capture.NewReader(file)
+	for {
+		next, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		replayed = append(replayed, next)
+	}
+	require.Len(t, replayed, 2)
+	require.Equal(t, capture.DirectionOutbound, replayed[0].Direction)
+	require.Equal(t, me, replayed[0].OriginID)
+	require.Equal(t, flow.IdentifierList{target}, replayed[0].TargetIDs)
+	require.Equal(t, capture.DirectionInbound, replayed[1].Direction)
+	require.Equal(t, string(message.ProtocolTypeUnicast), replayed[1].Protocol)
+}
+
+func vote() *messages.BlockVote {
+	return &messages.BlockVote{
+		BlockID: unittest.IdentifierFixture(),
+		View:    1234,
+		SigData: unittest.SignatureFixture(),
+	}
+}
+
+// record returns a record of the given message, sent by a random origin.
+func record(t *testing.T, direction capture.Direction, channel channels.Channel, msg interface{}) *capture.Record {
+	encoded, err := cborcodec.NewCodec().Encode(msg)
+	require.NoError(t, err)
+	r, err := capture.NewRecord(direction, channel, unittest.IdentifierFixture(), unittest.IdentifierListFixture(2), encoded)
+	require.NoError(t, err)
+	return r
+}
+
+// incomingScope returns the scope of the given message received by unicast from the given origin.
+func incomingScope(t *testing.T, origin flow.Identifier, channel channels.Channel, msg interface{}) *network.IncomingMessageScope {
+	encoded, err := cborcodec.NewCodec().Encode(msg)
+	require.NoError(t, err)
+	scope, err := network.NewIncomingScope(origin, message.ProtocolTypeUnicast, &message.Message{
+		ChannelID: channel.String(),
+		Payload:   encoded,
+	}, msg)
+	require.NoError(t, err)
+	return scope
+}
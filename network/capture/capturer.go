@@ -0,0 +1,165 @@
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+)
+
+// queueSize is the maximum number of captured messages waiting to be written to the capture file. Messages captured
+// while the queue is full are dropped.
+const queueSize = 10_000
+
+// Capturer records the messages sent and received by a node on a set of channels to a capture file.
+// Captured messages are queued and written to the file by a background goroutine, so that the file is never written
+// on the delivery path of messages.
+// Capturing is best effort: messages which cannot be recorded, or which are captured while the queue is full, are
+// dropped, so that capturing never interferes with the delivery of messages. The implementation is concurrency-safe.
+type Capturer struct {
+	log      zerolog.Logger
+	me       flow.Identifier
+	channels channels.ChannelList
+	codec    network.Codec
+
+	mu      sync.RWMutex // guards closing the queue against concurrent captures
+	closed  bool
+	queue   chan *Record
+	done    chan struct{} // closed once all queued records are written
+	dropped *atomic.Uint64
+
+	file   *os.File
+	buf    *bufio.Writer
+	writer *Writer
+}
+
+// NewCapturer creates a capturer writing to the file at the given path, truncating the file if it exists.
+// Only messages on the given channels are captured; cluster channels are selected by their cluster channel prefix.
+// The identifier of the capturing node is recorded as origin of outbound messages.
+// No errors are expected during normal operations, unless the file cannot be created.
+func NewCapturer(log zerolog.Logger, path string, me flow.Identifier, captured channels.ChannelList) (*Capturer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create capture file: %w", err)
+	}
+	buf := bufio.NewWriter(file)
+
+	c := &Capturer{
+		log:      log.With().Str("component", "network_capture").Str("path", path).Logger(),
+		me:       me,
+		channels: captured,
+		codec:    cborcodec.NewCodec(),
+		queue:    make(chan *Record, queueSize),
+		done:     make(chan struct{}),
+		dropped:  atomic.NewUint64(0),
+		file:     file,
+		buf:      buf,
+		writer:   NewWriter(buf),
+	}
+	go c.writeLoop()
+	return c, nil
+}
+
+// Captures returns true if messages on the given channel are captured.
+func (c *Capturer) Captures(channel channels.Channel) bool {
+	return matchChannel(c.channels, channel)
+}
+
+// CaptureOutbound records an event sent by the capturing node on the given channel to the given targets.
+func (c *Capturer) CaptureOutbound(channel channels.Channel, event interface{}, targetIDs flow.IdentifierList) {
+	if !c.Captures(channel) {
+		return
+	}
+
+	encoded, err := c.codec.Encode(event)
+	if err != nil {
+		c.log.Warn().Err(err).Str("channel", channel.String()).Msg("could not encode outbound message for capture")
+		return
+	}
+	record, err := NewRecord(DirectionOutbound, channel, c.me, targetIDs, encoded)
+	if err != nil {
+		c.log.Warn().Err(err).Str("channel", channel.String()).Msg("could not capture outbound message")
+		return
+	}
+	c.write(record)
+}
+
+// CaptureInbound records a message received by the capturing node.
+func (c *Capturer) CaptureInbound(msg *network.IncomingMessageScope) {
+	if !c.Captures(msg.Channel()) {
+		return
+	}
+
+	record, err := NewRecord(DirectionInbound, msg.Channel(), msg.OriginId(), msg.TargetIDs(), msg.Proto().Payload)
+	if err != nil {
+		c.log.Warn().Err(err).Str("channel", msg.Channel().String()).Msg("could not capture inbound message")
+		return
+	}
+	record.Protocol = string(msg.Protocol())
+	c.write(record)
+}
+
+// write queues the record to be written to the capture file. The record is dropped if the capturer is closed or
+// the queue is full.
+func (c *Capturer) write(record *Record) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return
+	}
+	select {
+	case c.queue <- record:
+	default:
+		c.dropped.Inc()
+	}
+}
+
+// writeLoop writes the queued records to the capture file until the queue is closed.
+func (c *Capturer) writeLoop() {
+	defer close(c.done)
+	for record := range c.queue {
+		err := c.writer.Write(record)
+		if err != nil {
+			c.log.Warn().Err(err).Str("channel", record.Channel.String()).Msg("could not write captured message")
+		}
+	}
+}
+
+// Close writes all queued messages to the capture file, flushes and closes the file. Messages captured
+// after closing are dropped.
+// No errors are expected during normal operations.
+func (c *Capturer) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.queue)
+	c.mu.Unlock()
+
+	<-c.done
+	if dropped := c.dropped.Load(); dropped > 0 {
+		c.log.Warn().Uint64("dropped", dropped).Msg("captured messages dropped as the capture queue was full")
+	}
+
+	err := c.buf.Flush()
+	if err != nil {
+		_ = c.file.Close()
+		return fmt.Errorf("could not flush capture file: %w", err)
+	}
+	err = c.file.Close()
+	if err != nil {
+		return fmt.Errorf("could not close capture file: %w", err)
+	}
+	return nil
+}
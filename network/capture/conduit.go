@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// ConduitFactory is a conduit factory which wraps the conduits created by an underlying factory, so that
+// the messages sent through them are captured.
+type ConduitFactory struct {
+	network.ConduitFactory
+	capturer *Capturer
+}
+
+var _ network.ConduitFactory = (*ConduitFactory)(nil)
+
+// NewConduitFactory returns a conduit factory capturing the messages sent through the conduits of the given factory.
+func NewConduitFactory(factory network.ConduitFactory, capturer *Capturer) *ConduitFactory {
+	return &ConduitFactory{
+		ConduitFactory: factory,
+		capturer:       capturer,
+	}
+}
+
+// NewConduit creates a conduit on the specified channel with the underlying factory. If messages on the channel
+// are captured, the conduit is wrapped to capture all messages sent through it.
+func (f *ConduitFactory) NewConduit(ctx context.Context, channel channels.Channel) (network.Conduit, error) {
+	con, err := f.ConduitFactory.NewConduit(ctx, channel)
+	if err != nil {
+		return nil, fmt.Errorf("could not create conduit: %w", err)
+	}
+	if !f.capturer.Captures(channel) {
+		return con, nil
+	}
+	return &Conduit{
+		Conduit:  con,
+		channel:  channel,
+		capturer: f.capturer,
+	}, nil
+}
+
+// Conduit is a conduit which captures all messages sent through it before passing them on to the underlying conduit.
+type Conduit struct {
+	network.Conduit
+	channel  channels.Channel
+	capturer *Capturer
+}
+
+var _ network.Conduit = (*Conduit)(nil)
+
+func (c *Conduit) Publish(event interface{}, targetIDs ...flow.Identifier) error {
+	c.capturer.CaptureOutbound(c.channel, event, targetIDs)
+	return c.Conduit.Publish(event, targetIDs...)
+}
+
+func (c *Conduit) Unicast(event interface{}, targetID flow.Identifier) error {
+	c.capturer.CaptureOutbound(c.channel, event, flow.IdentifierList{targetID})
+	return c.Conduit.Unicast(event, targetID)
+}
+
+// Multicast captures the event with all candidate recipients as targets, as the actual recipients are
+// selected by the underlying conduit.
+func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Identifier) error {
+	c.capturer.CaptureOutbound(c.channel, event, targetIDs)
+	return c.Conduit.Multicast(event, num, targetIDs...)
+}
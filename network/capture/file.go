@@ -0,0 +1,132 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/onflow/flow-go/model/encoding/cbor"
+)
+
+const (
+	// magic identifies capture files.
+	magic = "FLOWCAP"
+	// version is the version of the capture file format.
+	version = byte(1)
+	// maxRecordSize is the maximum size of a single encoded record, which bounds the memory allocated when
+	// reading corrupted capture files.
+	maxRecordSize = 64 << 20
+)
+
+// ErrInvalidFile is returned when reading a file which is not a capture file of a supported version.
+var ErrInvalidFile = errors.New("invalid capture file")
+
+// Writer writes records to a capture file. A capture file starts with a header made of the magic "FLOWCAP"
+// and the format version, followed by the records, each encoded as CBOR and prefixed by its length as uvarint.
+// The implementation is not concurrency-safe.
+type Writer struct {
+	w             io.Writer
+	headerWritten bool
+	marshaler     *cbor.Marshaler
+}
+
+// NewWriter returns a writer of records to the given underlying writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:         w,
+		marshaler: cbor.NewMarshaler(),
+	}
+}
+
+// Write writes the record to the capture file, writing the header of the file first if necessary.
+// No errors are expected during normal operations, unless the underlying writer fails.
+func (w *Writer) Write(r *Record) error {
+	if !w.headerWritten {
+		_, err := w.w.Write(append([]byte(magic), version))
+		if err != nil {
+			return fmt.Errorf("could not write header: %w", err)
+		}
+		w.headerWritten = true
+	}
+
+	encoded, err := w.marshaler.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(encoded))
+	n := binary.PutUvarint(buf, uint64(len(encoded)))
+	buf = append(buf[:n], encoded...)
+
+	_, err = w.w.Write(buf)
+	if err != nil {
+		return fmt.Errorf("could not write record: %w", err)
+	}
+	return nil
+}
+
+// Reader reads records from a capture file. The implementation is not concurrency-safe.
+type Reader struct {
+	r          *bufio.Reader
+	headerRead bool
+	marshaler  *cbor.Marshaler
+}
+
+// NewReader returns a reader of records from the given underlying reader.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r:         bufio.NewReader(r),
+		marshaler: cbor.NewMarshaler(),
+	}
+}
+
+// Next reads the next record of the capture file.
+// Expected errors during normal operations:
+//   - io.EOF if there are no more records in the file.
+//   - ErrInvalidFile if the file is not a capture file or its content is corrupted.
+func (r *Reader) Next() (*Record, error) {
+	if !r.headerRead {
+		header := make([]byte, len(magic)+1)
+		_, err := io.ReadFull(r.r, header)
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not read header: %v", ErrInvalidFile, err)
+		}
+		if !bytes.Equal(header[:len(magic)], []byte(magic)) {
+			return nil, fmt.Errorf("%w: unexpected magic %q", ErrInvalidFile, header[:len(magic)])
+		}
+		if header[len(magic)] != version {
+			return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidFile, header[len(magic)])
+		}
+		r.headerRead = true
+	}
+
+	size, err := binary.ReadUvarint(r.r)
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read record size: %v", ErrInvalidFile, err)
+	}
+	if size > maxRecordSize {
+		return nil, fmt.Errorf("%w: record size %d exceeds max record size %d", ErrInvalidFile, size, maxRecordSize)
+	}
+
+	encoded := make([]byte, size)
+	_, err = io.ReadFull(r.r, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read record: %v", ErrInvalidFile, err)
+	}
+
+	var record Record
+	err = r.marshaler.Unmarshal(encoded, &record)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not decode record: %v", ErrInvalidFile, err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,27 @@
+package capture
+
+import (
+	"github.com/onflow/flow-go/network"
+)
+
+// Overlay is a network overlay which captures all messages received by the middleware before passing them on
+// to the underlying overlay.
+type Overlay struct {
+	network.Overlay
+	capturer *Capturer
+}
+
+var _ network.Overlay = (*Overlay)(nil)
+
+// NewOverlay returns an overlay capturing the messages received by the given overlay.
+func NewOverlay(ov network.Overlay, capturer *Capturer) *Overlay {
+	return &Overlay{
+		Overlay:  ov,
+		capturer: capturer,
+	}
+}
+
+func (o *Overlay) Receive(msg *network.IncomingMessageScope) error {
+	o.capturer.CaptureInbound(msg)
+	return o.Overlay.Receive(msg)
+}
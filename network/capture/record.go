@@ -0,0 +1,127 @@
+package capture
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/codec"
+)
+
+// Direction is the direction of a captured message, as seen by the capturing node.
+type Direction string
+
+const (
+	// DirectionInbound is the direction of messages received by the capturing node.
+	DirectionInbound Direction = "inbound"
+	// DirectionOutbound is the direction of messages sent by the capturing node.
+	DirectionOutbound Direction = "outbound"
+)
+
+// Record is a captured network message.
+type Record struct {
+	Timestamp time.Time        // time the message was captured
+	Direction Direction        // direction of the message
+	Channel   channels.Channel // channel the message was sent on
+	OriginID  flow.Identifier  // sender of the message
+	TargetIDs flow.IdentifierList
+	Protocol  string // networking protocol (unicast or pubsub) of inbound messages, empty for outbound messages
+
+	MessageCode uint8  // code of the message in the network codec
+	MessageType string // type of the message, e.g., *messages.BlockProposal
+	Payload     []byte // CBOR encoding of the message
+}
+
+// NewRecord creates a record of a message encoded by the network codec, i.e., a payload prefixed by its message code.
+// No errors are expected during normal operations, unless the payload is malformed.
+func NewRecord(direction Direction, channel channels.Channel, originID flow.Identifier, targetIDs flow.IdentifierList, encoded []byte) (*Record, error) {
+	code, err := codec.MessageCodeFromPayload(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not get message code: %w", err)
+	}
+	_, what, err := codec.InterfaceFromMessageCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("could not get message type: %w", err)
+	}
+
+	return &Record{
+		Timestamp:   time.Now(),
+		Direction:   direction,
+		Channel:     channel,
+		OriginID:    originID,
+		TargetIDs:   targetIDs,
+		MessageCode: code.Uint8(),
+		MessageType: what,
+		Payload:     encoded[1:],
+	}, nil
+}
+
+// Decode decodes the captured message with the given network codec.
+// No errors are expected during normal operations, unless the record is malformed.
+func (r *Record) Decode(c network.Codec) (interface{}, error) {
+	encoded := make([]byte, 0, len(r.Payload)+1)
+	encoded = append(encoded, r.MessageCode)
+	encoded = append(encoded, r.Payload...)
+
+	msg, err := c.Decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode captured %s: %w", r.MessageType, err)
+	}
+	return msg, nil
+}
+
+// Filter selects captured messages. Empty criteria match all messages.
+type Filter struct {
+	Channels  channels.ChannelList // channels of the messages, cluster channels match their cluster channel prefix
+	Origins   flow.IdentifierList  // senders of the messages
+	Types     []string             // types of the messages
+	Direction Direction            // direction of the messages
+	Since     time.Time            // earliest capture time
+	Until     time.Time            // latest capture time
+}
+
+// Match returns true if the record matches all criteria of the filter.
+func (f Filter) Match(r *Record) bool {
+	if len(f.Channels) > 0 && !matchChannel(f.Channels, r.Channel) {
+		return false
+	}
+	if len(f.Origins) > 0 && !f.Origins.Contains(r.OriginID) {
+		return false
+	}
+	if len(f.Types) > 0 && !matchType(f.Types, r.MessageType) {
+		return false
+	}
+	if f.Direction != "" && f.Direction != r.Direction {
+		return false
+	}
+	if !f.Since.IsZero() && r.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && r.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// matchChannel returns true if the channel is part of the given channels, either by name or by cluster channel prefix.
+func matchChannel(list channels.ChannelList, channel channels.Channel) bool {
+	if list.Contains(channel) {
+		return true
+	}
+	prefix, ok := channels.ClusterChannelPrefix(channel)
+	return ok && list.Contains(channels.Channel(prefix))
+}
+
+// matchType returns true if the message type is part of the given types. Types match with or without their package
+// qualifier, e.g., both "*messages.BlockProposal" and "BlockProposal" match block proposals.
+func matchType(types []string, messageType string) bool {
+	for _, t := range types {
+		if t == messageType || strings.HasSuffix(messageType, "."+t) {
+			return true
+		}
+	}
+	return false
+}
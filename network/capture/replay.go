@@ -0,0 +1,43 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+)
+
+// ReplayFunc is called with every decoded message replayed from a capture file.
+type ReplayFunc func(channel channels.Channel, originID flow.Identifier, event interface{}) error
+
+// Replay decodes the records of the capture file read by the given reader that match the filter, and passes the
+// decoded messages in their captured order to the given function. Records of both directions are passed on, unless
+// the filter restricts the direction. Replay stops at the first error.
+// No errors are expected during normal operations, unless the capture file is invalid or the function errors.
+func Replay(r *Reader, filter Filter, fn ReplayFunc) error {
+	codec := cborcodec.NewCodec()
+	for {
+		record, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read record: %w", err)
+		}
+		if !filter.Match(record) {
+			continue
+		}
+
+		event, err := record.Decode(codec)
+		if err != nil {
+			return err
+		}
+		err = fn(record.Channel, record.OriginID, event)
+		if err != nil {
+			return fmt.Errorf("could not replay %s on channel %s: %w", record.MessageType, record.Channel, err)
+		}
+	}
+}
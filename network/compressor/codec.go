@@ -0,0 +1,59 @@
+package compressor
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// Codec is a compression codec which can be selected as the compression policy of a channel.
+type Codec string
+
+const (
+	// CodecNone disables compression.
+	CodecNone Codec = "none"
+	// CodecLz4 compresses with LZ4, which trades compression ratio for speed.
+	CodecLz4 Codec = "lz4"
+	// CodecZstd compresses with zstd.
+	CodecZstd Codec = "zstd"
+	// CodecZstdDict compresses with zstd using the default dictionary, which is trained on the encodings of
+	// small and repetitive messages such as votes. Without a dictionary, these messages are too small for the
+	// compression to save any bytes.
+	CodecZstdDict Codec = "zstd-dict"
+)
+
+// Codecs lists all compression codecs, except CodecNone.
+var Codecs = []Codec{CodecLz4, CodecZstd, CodecZstdDict}
+
+// String returns the name of the codec.
+func (c Codec) String() string {
+	return string(c)
+}
+
+// ParseCodec parses the name of a compression codec. The empty string is parsed as CodecNone.
+// Returns an error if the name is not a known codec.
+func ParseCodec(name string) (Codec, error) {
+	switch codec := Codec(name); codec {
+	case "", CodecNone:
+		return CodecNone, nil
+	case CodecLz4, CodecZstd, CodecZstdDict:
+		return codec, nil
+	default:
+		return "", fmt.Errorf("unknown compression codec: %s", name)
+	}
+}
+
+// NewCompressor returns the compressor of the given codec.
+// Returns an error if the codec is CodecNone or unknown.
+func NewCompressor(codec Codec) (network.Compressor, error) {
+	switch codec {
+	case CodecLz4:
+		return NewLz4Compressor(), nil
+	case CodecZstd:
+		return NewZstdCompressor(), nil
+	case CodecZstdDict:
+		return NewZstdDictCompressor(DefaultDictionary()), nil
+	default:
+		return nil, fmt.Errorf("no compressor for codec: %s", codec)
+	}
+}
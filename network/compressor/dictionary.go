@@ -0,0 +1,138 @@
+package compressor
+
+import (
+	"bytes"
+	"hash/crc32"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go/model/encoding/cbor"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+)
+
+const (
+	// DefaultDictionarySize is the maximum size of the default dictionary in bytes.
+	DefaultDictionarySize = 4 * 1024
+
+	// dictionarySegmentLength is the length of the segments the dictionary trainer selects from the samples.
+	dictionarySegmentLength = 8
+
+	// minDictionaryID is the smallest dictionary ID which is not reserved by the zstd format.
+	minDictionaryID = 1 << 15
+	// maxDictionaryID is the largest dictionary ID which is not reserved by the zstd format and still
+	// fits into two bytes of the frame header.
+	maxDictionaryID = 1<<16 - 1
+)
+
+var (
+	defaultDictionary     []byte
+	defaultDictionaryOnce sync.Once
+)
+
+// DefaultDictionary returns the dictionary used by CodecZstdDict. It is trained on the encodings of
+// the small and repetitive messages of the consensus, cluster consensus and synchronization protocols.
+// The dictionary is deterministic, so that all nodes running the same software version train the same
+// dictionary. Nodes running different software versions may train different dictionaries, which is
+// why the dictionary ID is part of the protocol ID negotiated for dictionary-compressed streams.
+func DefaultDictionary() []byte {
+	defaultDictionaryOnce.Do(func() {
+		defaultDictionary = TrainDictionary(dictionarySamples(), DefaultDictionarySize)
+	})
+	return defaultDictionary
+}
+
+// DictionaryID returns the zstd dictionary ID of the given raw content dictionary, which is derived
+// from a checksum of the dictionary content and lies outside the ID ranges reserved by the zstd format.
+func DictionaryID(dict []byte) uint32 {
+	return minDictionaryID + crc32.ChecksumIEEE(dict)%(maxDictionaryID-minDictionaryID+1)
+}
+
+// TrainDictionary trains a raw content dictionary of at most maxSize bytes on the given samples.
+// The dictionary is made of the fixed-length segments which occur in most samples, as these are the
+// segments compression of future messages can most likely refer to. The most frequent segments are
+// placed at the end of the dictionary, where references to them are cheapest to encode.
+// Training is deterministic: the same samples always result in the same dictionary.
+func TrainDictionary(samples [][]byte, maxSize int) []byte {
+	// count the number of samples each segment occurs in
+	counts := make(map[string]int)
+	for _, sample := range samples {
+		seen := make(map[string]struct{})
+		for i := 0; i+dictionarySegmentLength <= len(sample); i++ {
+			segment := string(sample[i : i+dictionarySegmentLength])
+			if _, ok := seen[segment]; ok {
+				continue
+			}
+			seen[segment] = struct{}{}
+			counts[segment]++
+		}
+	}
+
+	segments := make([]string, 0, len(counts))
+	for segment, count := range counts {
+		// segments occurring in a single sample are not repetitive
+		if count > 1 {
+			segments = append(segments, segment)
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		if counts[segments[i]] != counts[segments[j]] {
+			return counts[segments[i]] > counts[segments[j]]
+		}
+		return segments[i] < segments[j]
+	})
+
+	// select the most frequent segments, skipping segments which are already covered by the dictionary
+	selected := make([][]byte, 0)
+	var content []byte
+	for _, segment := range segments {
+		if len(content)+len(segment) > maxSize {
+			break
+		}
+		if bytes.Contains(content, []byte(segment)) {
+			continue
+		}
+		selected = append(selected, []byte(segment))
+		content = append(content, segment...)
+	}
+
+	dict := make([]byte, 0, len(content))
+	for i := len(selected) - 1; i >= 0; i-- {
+		dict = append(dict, selected[i]...)
+	}
+	return dict
+}
+
+// dictionarySamples returns the samples the default dictionary is trained on. The samples are encoded the
+// same way the network codec encodes messages, with deterministic but varying field values.
+func dictionarySamples() [][]byte {
+	samples := make([][]byte, 0)
+	for i := uint64(0); i < 16; i++ {
+		var id flow.Identifier
+		for j := range id {
+			id[j] = byte(i*31 + uint64(j)*7)
+		}
+		sig := make([]byte, 48)
+		for j := range sig {
+			sig[j] = byte(i*17 + uint64(j)*13)
+		}
+		vote := &messages.BlockVote{BlockID: id, View: 1000 + i, SigData: sig}
+		for _, msg := range []interface{}{
+			vote,
+			(*messages.ClusterBlockVote)(vote),
+			&messages.TimeoutObject{TimeoutTick: i, View: 1000 + i, NewestQC: &flow.QuorumCertificate{View: 999 + i, BlockID: id, SignerIndices: sig[:8], SigData: sig}, SigData: sig},
+			&messages.SyncRequest{Nonce: i * 7919, Height: 1000 + i},
+			&messages.SyncResponse{Nonce: i * 7919, Height: 1000 + i},
+			&messages.RangeRequest{Nonce: i * 7919, FromHeight: 1000 + i, ToHeight: 1010 + i},
+			&messages.BatchRequest{Nonce: i * 7919, BlockIDs: []flow.Identifier{id}},
+		} {
+			sample, err := cbor.EncMode.Marshal(msg)
+			if err != nil {
+				// the samples are fixed and always encodable
+				panic(err)
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
@@ -0,0 +1,135 @@
+package compressor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Compressed payloads are framed with a single header byte identifying the codec. Header bytes lie
+// above all message codes of the network codec, which are placed as first byte of plain payloads, so
+// that compressed and plain payloads can be told apart.
+const (
+	payloadHeaderLz4      byte = 0xC1
+	payloadHeaderZstd     byte = 0xC2
+	payloadHeaderZstdDict byte = 0xC3
+)
+
+// MinPayloadHeader is the smallest header byte of compressed payloads. All message codes of the network
+// codec must be smaller than this value.
+const MinPayloadHeader = payloadHeaderLz4
+
+var payloadHeaders = map[Codec]byte{
+	CodecLz4:      payloadHeaderLz4,
+	CodecZstd:     payloadHeaderZstd,
+	CodecZstdDict: payloadHeaderZstdDict,
+}
+
+// IsCompressedPayload returns true if the message payload was compressed by CompressPayload.
+func IsCompressedPayload(payload []byte) bool {
+	_, ok := payloadCodec(payload)
+	return ok
+}
+
+// CompressPayload compresses a message payload with the given codec and frames it with the header of the codec.
+// Returns an error if the codec is CodecNone or unknown, or the compression fails.
+func CompressPayload(codec Codec, payload []byte) ([]byte, error) {
+	header, ok := payloadHeaders[codec]
+	if !ok {
+		return nil, fmt.Errorf("no payload header for codec: %s", codec)
+	}
+	c, err := NewCompressor(codec)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(header)
+	w, err := c.NewWriter(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("could not create compressor writer: %w", err)
+	}
+	_, err = w.Write(payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not compress payload: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not close compressor writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressPayload decompresses a message payload compressed by CompressPayload, and returns the plain payload
+// along with the codec it was compressed with. Payloads which are not compressed are returned as is, along with
+// CodecNone.
+// Returns an error if the payload cannot be decompressed, or its plain size exceeds maxSize bytes.
+func DecompressPayload(payload []byte, maxSize int) ([]byte, Codec, error) {
+	codec, ok := payloadCodec(payload)
+	if !ok {
+		return payload, CodecNone, nil
+	}
+	c, err := NewCompressor(codec)
+	if err != nil {
+		return nil, codec, err
+	}
+
+	r, err := c.NewReader(bytes.NewReader(payload[1:]))
+	if err != nil {
+		return nil, codec, fmt.Errorf("could not create compressor reader: %w", err)
+	}
+	defer r.Close()
+
+	// read one byte more than allowed to detect oversized payloads without decompressing them entirely
+	plain, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, codec, fmt.Errorf("could not decompress payload: %w", err)
+	}
+	if len(plain) > maxSize {
+		return nil, codec, fmt.Errorf("decompressed payload exceeds max size %d", maxSize)
+	}
+	return plain, codec, nil
+}
+
+// DecompressPayloadPrefix returns the first n bytes of the plain payload of a message payload compressed by
+// CompressPayload, decompressing only as much of the payload as needed. Payloads which are not compressed are
+// returned truncated to n bytes. Fewer than n bytes are returned if the plain payload is shorter.
+// Returns an error if the prefix of the payload cannot be decompressed.
+func DecompressPayloadPrefix(payload []byte, n int) ([]byte, error) {
+	codec, ok := payloadCodec(payload)
+	if !ok {
+		if len(payload) > n {
+			return payload[:n], nil
+		}
+		return payload, nil
+	}
+	c, err := NewCompressor(codec)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := c.NewReader(bytes.NewReader(payload[1:]))
+	if err != nil {
+		return nil, fmt.Errorf("could not create compressor reader: %w", err)
+	}
+	defer r.Close()
+
+	prefix, err := io.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress payload: %w", err)
+	}
+	return prefix, nil
+}
+
+// payloadCodec returns the codec a payload was compressed with, or false if the payload is not compressed.
+func payloadCodec(payload []byte) (Codec, bool) {
+	if len(payload) == 0 {
+		return "", false
+	}
+	for codec, header := range payloadHeaders {
+		if payload[0] == header {
+			return codec, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,137 @@
+package compressor_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network/codec"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestCodecs_RoundTrip evaluates that reading what has been written by the compressor of each codec yields
+// the same data.
+func TestCodecs_RoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("hello world, "), 100)
+
+	for _, codec := range compressor.Codecs {
+		t.Run(codec.String(), func(t *testing.T) {
+			c, err := compressor.NewCompressor(codec)
+			require.NoError(t, err)
+
+			buf := new(bytes.Buffer)
+			w, err := c.NewWriter(buf)
+			require.NoError(t, err)
+			_, err = w.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			require.Less(t, buf.Len(), len(data))
+
+			r, err := c.NewReader(buf)
+			require.NoError(t, err)
+			read, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, data, read)
+		})
+	}
+}
+
+// TestPayload_RoundTrip evaluates that compressed payloads are decompressed to the original payload with the
+// codec they were compressed with, and that plain payloads are left as is.
+func TestPayload_RoundTrip(t *testing.T) {
+	payload := encodedVote(t)
+
+	for _, c := range compressor.Codecs {
+		t.Run(c.String(), func(t *testing.T) {
+			compressed, err := compressor.CompressPayload(c, payload)
+			require.NoError(t, err)
+			require.True(t, compressor.IsCompressedPayload(compressed))
+
+			plain, actual, err := compressor.DecompressPayload(compressed, len(payload))
+			require.NoError(t, err)
+			require.Equal(t, c, actual)
+			require.Equal(t, payload, plain)
+
+			// payloads exceeding the max size are rejected
+			_, _, err = compressor.DecompressPayload(compressed, len(payload)-1)
+			require.Error(t, err)
+
+			// the prefix of the plain payload is decompressed without regard to the max size
+			prefix, err := compressor.DecompressPayloadPrefix(compressed, 1)
+			require.NoError(t, err)
+			require.Equal(t, payload[:1], prefix)
+		})
+	}
+
+	t.Run("plain payload", func(t *testing.T) {
+		require.False(t, compressor.IsCompressedPayload(payload))
+		plain, actual, err := compressor.DecompressPayload(payload, len(payload))
+		require.NoError(t, err)
+		require.Equal(t, compressor.CodecNone, actual)
+		require.Equal(t, payload, plain)
+
+		prefix, err := compressor.DecompressPayloadPrefix(payload, 1)
+		require.NoError(t, err)
+		require.Equal(t, payload[:1], prefix)
+	})
+}
+
+// TestPayload_HeadersAboveMessageCodes evaluates that plain payloads, which start with their message code,
+// are never mistaken for compressed payloads.
+func TestPayload_HeadersAboveMessageCodes(t *testing.T) {
+	require.Less(t, codec.CodeMax.Uint8(), compressor.MinPayloadHeader)
+}
+
+// TestZstdDict_SavesBytes evaluates that the dictionary codec compresses small timeout objects, which are too
+// small for compression without a dictionary to save bytes.
+func TestZstdDict_SavesBytes(t *testing.T) {
+	payload, err := cborcodec.NewCodec().Encode(&messages.TimeoutObject{
+		TimeoutTick: 3,
+		View:        1234,
+		NewestQC:    unittest.QuorumCertificateFixture(),
+		SigData:     unittest.SignatureFixture(),
+	})
+	require.NoError(t, err)
+
+	withDict, err := compressor.CompressPayload(compressor.CodecZstdDict, payload)
+	require.NoError(t, err)
+	withoutDict, err := compressor.CompressPayload(compressor.CodecZstd, payload)
+	require.NoError(t, err)
+
+	require.Less(t, len(withDict), len(payload))
+	require.Less(t, len(withDict), len(withoutDict))
+}
+
+// TestTrainDictionary evaluates that dictionary training is deterministic and respects the max size.
+func TestTrainDictionary(t *testing.T) {
+	samples := [][]byte{
+		[]byte("BlockID: 1, View: 10, SigData: abc"),
+		[]byte("BlockID: 2, View: 11, SigData: def"),
+		[]byte("BlockID: 3, View: 12, SigData: ghi"),
+	}
+
+	dict := compressor.TrainDictionary(samples, 64)
+	require.NotEmpty(t, dict)
+	require.LessOrEqual(t, len(dict), 64)
+	require.Equal(t, dict, compressor.TrainDictionary(samples, 64))
+	require.Contains(t, string(dict), "BlockID:")
+
+	require.Equal(t, compressor.DefaultDictionary(), compressor.DefaultDictionary())
+	require.LessOrEqual(t, len(compressor.DefaultDictionary()), compressor.DefaultDictionarySize)
+}
+
+// encodedVote returns a block vote encoded by the network codec.
+func encodedVote(t *testing.T) []byte {
+	payload, err := cborcodec.NewCodec().Encode(&messages.BlockVote{
+		BlockID: unittest.IdentifierFixture(),
+		View:    1234,
+		SigData: unittest.SignatureFixture(),
+	})
+	require.NoError(t, err)
+	return payload
+}
@@ -0,0 +1,59 @@
+package compressor
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/onflow/flow-go/network"
+)
+
+var _ network.Compressor = (*ZstdCompressor)(nil)
+
+// ZstdCompressor is a zstd compressor, optionally using a raw content dictionary.
+// Compressed data can only be decompressed by a compressor using the same dictionary.
+type ZstdCompressor struct {
+	dict   []byte
+	dictID uint32
+}
+
+// NewZstdCompressor returns a zstd compressor without dictionary.
+func NewZstdCompressor() *ZstdCompressor {
+	return &ZstdCompressor{}
+}
+
+// NewZstdDictCompressor returns a zstd compressor using the given raw content dictionary.
+func NewZstdDictCompressor(dict []byte) *ZstdCompressor {
+	return &ZstdCompressor{
+		dict:   dict,
+		dictID: DictionaryID(dict),
+	}
+}
+
+func (z ZstdCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	opts := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+	if z.dict != nil {
+		opts = append(opts, zstd.WithDecoderDictRaw(z.dictID, z.dict))
+	}
+	d, err := zstd.NewReader(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
+
+func (z ZstdCompressor) NewWriter(w io.Writer) (network.WriteCloseFlusher, error) {
+	// messages are compressed one stream at a time, hence the encoder does not need to spawn
+	// goroutines of its own.
+	opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+	if z.dict != nil {
+		// dictionaries are meant for small messages, for which the checksum is a significant overhead.
+		// Integrity of messages is already guaranteed by the secure channels of libp2p.
+		// The fastest encoder level makes the best use of raw content dictionaries on small messages.
+		opts = append(opts,
+			zstd.WithEncoderDictRaw(z.dictID, z.dict),
+			zstd.WithEncoderCRC(false),
+			zstd.WithEncoderLevel(zstd.SpeedFastest))
+	}
+	return zstd.NewWriter(w, opts...)
+}
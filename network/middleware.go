@@ -58,6 +58,10 @@ type Middleware interface {
 	// NewPingService creates a new PingService for the given ping protocol ID.
 	NewPingService(pingProtocol protocol.ID, provider PingInfoProvider) PingService
 
+	// NewRequestService creates a new RequestService for the given channel, handling the inbound requests with the
+	// given handler if it is not nil.
+	NewRequestService(channel channels.Channel, handler RequestHandler, opts ...RequestServiceOption) RequestService
+
 	IsConnected(nodeID flow.Identifier) (bool, error)
 }
 
@@ -101,6 +101,29 @@ func (_m *Middleware) NewPingService(pingProtocol protocol.ID, provider network.
 	return r0
 }
 
+// NewRequestService provides a mock function with given fields: channel, handler, opts
+func (_m *Middleware) NewRequestService(channel channels.Channel, handler network.RequestHandler, opts ...network.RequestServiceOption) network.RequestService {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, channel, handler)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 network.RequestService
+	if rf, ok := ret.Get(0).(func(channels.Channel, network.RequestHandler, ...network.RequestServiceOption) network.RequestService); ok {
+		r0 = rf(channel, handler, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(network.RequestService)
+		}
+	}
+
+	return r0
+}
+
 // OnAllowListNotification provides a mock function with given fields: _a0
 func (_m *Middleware) OnAllowListNotification(_a0 *network.AllowListingUpdate) {
 	_m.Called(_a0)
@@ -116,6 +116,26 @@ func (mr *MockNetworkMockRecorder) RegisterPingService(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPingService", reflect.TypeOf((*MockNetwork)(nil).RegisterPingService), arg0, arg1)
 }
 
+// RegisterRequestService mocks base method.
+func (m *MockNetwork) RegisterRequestService(arg0 channels.Channel, arg1 network.RequestHandler, arg2 ...network.RequestServiceOption) (network.RequestService, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterRequestService", varargs...)
+	ret0, _ := ret[0].(network.RequestService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterRequestService indicates an expected call of RegisterRequestService.
+func (mr *MockNetworkMockRecorder) RegisterRequestService(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRequestService", reflect.TypeOf((*MockNetwork)(nil).RegisterRequestService), varargs...)
+}
+
 // Start mocks base method.
 func (m *MockNetwork) Start(arg0 irrecoverable.SignalerContext) {
 	m.ctrl.T.Helper()
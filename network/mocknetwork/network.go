@@ -137,6 +137,39 @@ func (_m *Network) RegisterPingService(pingProtocolID protocol.ID, pingInfoProvi
 	return r0, r1
 }
 
+// RegisterRequestService provides a mock function with given fields: channel, handler, opts
+func (_m *Network) RegisterRequestService(channel channels.Channel, handler network.RequestHandler, opts ...network.RequestServiceOption) (network.RequestService, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, channel, handler)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 network.RequestService
+	var r1 error
+	if rf, ok := ret.Get(0).(func(channels.Channel, network.RequestHandler, ...network.RequestServiceOption) (network.RequestService, error)); ok {
+		return rf(channel, handler, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(channels.Channel, network.RequestHandler, ...network.RequestServiceOption) network.RequestService); ok {
+		r0 = rf(channel, handler, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(network.RequestService)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(channels.Channel, network.RequestHandler, ...network.RequestServiceOption) error); ok {
+		r1 = rf(channel, handler, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields: _a0
 func (_m *Network) Start(_a0 irrecoverable.SignalerContext) {
 	_m.Called(_a0)
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mocknetwork
+
+import (
+	context "context"
+
+	channels "github.com/onflow/flow-go/network/channels"
+
+	flow "github.com/onflow/flow-go/model/flow"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RequestHandler is an autogenerated mock type for the RequestHandler type
+type RequestHandler struct {
+	mock.Mock
+}
+
+// HandleRequest provides a mock function with given fields: ctx, channel, originID, request
+func (_m *RequestHandler) HandleRequest(ctx context.Context, channel channels.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	ret := _m.Called(ctx, channel, originID, request)
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, channels.Channel, flow.Identifier, interface{}) (interface{}, error)); ok {
+		return rf(ctx, channel, originID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, channels.Channel, flow.Identifier, interface{}) interface{}); ok {
+		r0 = rf(ctx, channel, originID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, channels.Channel, flow.Identifier, interface{}) error); ok {
+		r1 = rf(ctx, channel, originID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRequestHandler interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRequestHandler creates a new instance of RequestHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRequestHandler(t mockConstructorTestingTNewRequestHandler) *RequestHandler {
+	mock := &RequestHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
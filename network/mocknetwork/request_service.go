@@ -0,0 +1,56 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mocknetwork
+
+import (
+	context "context"
+
+	flow "github.com/onflow/flow-go/model/flow"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RequestService is an autogenerated mock type for the RequestService type
+type RequestService struct {
+	mock.Mock
+}
+
+// Request provides a mock function with given fields: ctx, targetID, request
+func (_m *RequestService) Request(ctx context.Context, targetID flow.Identifier, request interface{}) (interface{}, error) {
+	ret := _m.Called(ctx, targetID, request)
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, interface{}) (interface{}, error)); ok {
+		return rf(ctx, targetID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, interface{}) interface{}); ok {
+		r0 = rf(ctx, targetID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Identifier, interface{}) error); ok {
+		r1 = rf(ctx, targetID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRequestService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRequestService creates a new instance of RequestService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRequestService(t mockConstructorTestingTNewRequestService) *RequestService {
+	mock := &RequestService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -20,6 +20,29 @@ type Config struct {
 	// that are not part of protocol state should be trimmed
 	// TODO: solely a fallback mechanism, can be removed upon reliable behavior in production.
 	NetworkConnectionPruning bool `mapstructure:"networking-connection-pruning"`
+	// TopologyFanout is the number of neighbors each node has per role and channel in the partial topology.
+	// When set, connections to nodes outside the topology are pruned (if connection pruning is enabled).
+	// Zero keeps the node fully connected to all other nodes.
+	TopologyFanout uint `mapstructure:"networking-topology-fanout"`
+	// ChannelCompression is the compression codec of channels, as list of channel=codec pairs. Codecs are negotiated with
+	// the remote peers for unicast messages, and channels missing from the list are not compressed. Pubsub messages are
+	// only compressed if PubSubCompression is enabled.
+	ChannelCompression []string `mapstructure:"channel-compression"`
+	// PubSubCompression enables the compression of pubsub messages with the codec of their channel. Otherwise, only
+	// unicast messages are compressed. Pubsub messages are relayed by peers regardless of whether the receivers support
+	// the codec, hence it must only be enabled when all nodes of the network support the codecs, i.e., at a spork.
+	PubSubCompression bool `mapstructure:"pubsub-compression"`
+	// InboundQueueChannelWeights is the weight of channels in the fair inbound message queue, as list of channel=weight
+	// pairs. Cluster channels are configured by their cluster channel prefix, and channels not listed have weight 1.
+	InboundQueueChannelWeights []string `mapstructure:"inbound-queue-channel-weights"`
+	// InboundQueueChannelCapacity is the maximum number of inbound messages buffered per channel. When the buffer of
+	// a channel is full, the messages of the origin with the most buffered messages are dropped.
+	InboundQueueChannelCapacity int `validate:"gt=0" mapstructure:"inbound-queue-channel-capacity"`
+	// CaptureFile is the file the messages sent and received on the capture channels are captured to, for debugging.
+	// Capturing is disabled when empty.
+	CaptureFile string `mapstructure:"network-capture-file"`
+	// CaptureChannels is the list of channels to capture messages of, cluster channels are selected by their prefix.
+	CaptureChannels []string `mapstructure:"network-capture-channels"`
 	// PreferredUnicastProtocols list of unicast protocols in preferred order
 	PreferredUnicastProtocols       []string      `mapstructure:"preferred-unicast-protocols"`
 	NetworkReceivedMessageCacheSize uint32        `validate:"gt=0" mapstructure:"received-message-cache-size"`
@@ -67,4 +90,12 @@ type AlspConfig struct {
 	// HeartBeatInterval is the interval between heartbeats sent by the ALSP module. The heartbeats are recurring
 	// events that are used to perform critical ALSP tasks, such as updating the spam records cache.
 	HearBeatInterval time.Duration `mapstructure:"alsp-heart-beat-interval"`
+
+	// PersistSpamRecords indicates whether the spam records are persisted to the database of the node, so that the
+	// penalties of misbehaving nodes survive restarts. Persisted penalties are decayed for the downtime of the node.
+	PersistSpamRecords bool `mapstructure:"alsp-persist-spam-records"`
+
+	// SpamRecordPersistenceInterval is the interval between two consecutive snapshots of the spam records to the
+	// database, when PersistSpamRecords is enabled. A final snapshot is taken on shutdown.
+	SpamRecordPersistenceInterval time.Duration `mapstructure:"alsp-spam-record-persistence-interval"`
 }
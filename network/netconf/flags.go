@@ -21,6 +21,13 @@ const (
 	unicastCreateStreamRetryDelay     = "unicast-create-stream-retry-delay"
 	dnsCacheTTL                       = "dns-cache-ttl"
 	disallowListNotificationCacheSize = "disallow-list-notification-cache-size"
+	topologyFanout                    = "networking-topology-fanout"
+	channelCompression                = "channel-compression"
+	pubSubCompression                 = "pubsub-compression"
+	captureFile                       = "network-capture-file"
+	captureChannels                   = "network-capture-channels"
+	inboundQueueChannelWeights        = "inbound-queue-channel-weights"
+	inboundQueueChannelCapacity       = "inbound-queue-channel-capacity"
 	// unicast rate limiters config
 	dryRun              = "unicast-dry-run"
 	lockoutDuration     = "unicast-lockout-duration"
@@ -43,6 +50,8 @@ const (
 	rpcSentTrackerQueueCacheSize = "gossipsub-rpc-sent-tracker-queue-cache-size"
 	rpcSentTrackerNumOfWorkers   = "gossipsub-rpc-sent-tracker-workers"
 	scoreTracerInterval          = "gossipsub-score-tracer-interval"
+	scoreHistorySize             = "gossipsub-score-history-size"
+	scoreHistoryMaxPeers         = "gossipsub-score-history-max-peers"
 	// gossipsub validation inspector
 	gossipSubRPCInspectorNotificationCacheSize                 = "gossipsub-rpc-inspector-notification-cache-size"
 	validationInspectorNumberOfWorkers                         = "gossipsub-rpc-validation-inspector-workers"
@@ -59,20 +68,29 @@ const (
 	metricsInspectorNumberOfWorkers = "gossipsub-rpc-metrics-inspector-workers"
 	metricsInspectorCacheSize       = "gossipsub-rpc-metrics-inspector-cache-size"
 
+	// gossipsub publish message inspector
+	publishInspectorSampleSizePercentage = "gossipsub-rpc-publish-inspection-sample-size-percentage"
+	publishInspectorMaxSampleSize        = "gossipsub-rpc-publish-max-sample-size"
+	publishInspectorRateLimit            = "gossipsub-rpc-publish-rate-limit"
+
 	alspDisabled            = "alsp-disable-penalty"
 	alspSpamRecordCacheSize = "alsp-spam-record-cache-size"
 	alspSpamRecordQueueSize = "alsp-spam-report-queue-size"
 	alspHearBeatInterval    = "alsp-heart-beat-interval"
+	alspPersistSpamRecords  = "alsp-persist-spam-records"
+	alspPersistenceInterval = "alsp-spam-record-persistence-interval"
 )
 
 func AllFlagNames() []string {
 	return []string{
 		networkingConnectionPruning, preferredUnicastsProtocols, receivedMessageCacheSize, peerUpdateInterval, unicastMessageTimeout, unicastCreateStreamRetryDelay,
-		dnsCacheTTL, disallowListNotificationCacheSize, dryRun, lockoutDuration, messageRateLimit, bandwidthRateLimit, bandwidthBurstLimit, memoryLimitRatio,
+		dnsCacheTTL, disallowListNotificationCacheSize, topologyFanout, channelCompression, pubSubCompression, captureFile, captureChannels, inboundQueueChannelWeights, inboundQueueChannelCapacity, dryRun, lockoutDuration, messageRateLimit, bandwidthRateLimit, bandwidthBurstLimit, memoryLimitRatio,
 		fileDescriptorsRatio, peerBaseLimitConnsInbound, highWatermark, lowWatermark, gracePeriod, silencePeriod, peerScoring, localMeshLogInterval, rpcSentTrackerCacheSize, rpcSentTrackerQueueCacheSize, rpcSentTrackerNumOfWorkers,
-		scoreTracerInterval, gossipSubRPCInspectorNotificationCacheSize, validationInspectorNumberOfWorkers, validationInspectorInspectMessageQueueCacheSize, validationInspectorClusterPrefixedTopicsReceivedCacheSize,
+		scoreTracerInterval, scoreHistorySize, scoreHistoryMaxPeers, gossipSubRPCInspectorNotificationCacheSize, validationInspectorNumberOfWorkers, validationInspectorInspectMessageQueueCacheSize, validationInspectorClusterPrefixedTopicsReceivedCacheSize,
 		validationInspectorClusterPrefixedTopicsReceivedCacheDecay, validationInspectorClusterPrefixHardThreshold, ihaveSyncSampleSizePercentage, ihaveAsyncSampleSizePercentage,
-		ihaveMaxSampleSize, metricsInspectorNumberOfWorkers, metricsInspectorCacheSize, alspDisabled, alspSpamRecordCacheSize, alspSpamRecordQueueSize, alspHearBeatInterval,
+		ihaveMaxSampleSize, metricsInspectorNumberOfWorkers, metricsInspectorCacheSize,
+		publishInspectorSampleSizePercentage, publishInspectorMaxSampleSize, publishInspectorRateLimit, alspDisabled, alspSpamRecordCacheSize, alspSpamRecordQueueSize, alspHearBeatInterval,
+		alspPersistSpamRecords, alspPersistenceInterval,
 	}
 }
 
@@ -89,6 +107,13 @@ func InitializeNetworkFlags(flags *pflag.FlagSet, config *Config) {
 	flags.Uint32(receivedMessageCacheSize, config.NetworkReceivedMessageCacheSize, "incoming message cache size at networking layer")
 	flags.Uint32(disallowListNotificationCacheSize, config.DisallowListNotificationCacheSize, "cache size for notification events from disallow list")
 	flags.Duration(peerUpdateInterval, config.PeerUpdateInterval, "how often to refresh the peer connections for the node")
+	flags.Uint(topologyFanout, config.TopologyFanout, "number of neighbors per role and channel in the partial topology of the networking layer, 0 keeps the node fully connected")
+	flags.StringSlice(channelCompression, config.ChannelCompression, "compression codec (none, lz4, zstd or zstd-dict) per channel as channel=codec pairs, e.g., consensus-committee=zstd-dict")
+	flags.Bool(pubSubCompression, config.PubSubCompression, "compress pubsub messages with the codec of their channel, must only be enabled at a spork when all nodes support the codecs; otherwise only unicast messages are compressed")
+	flags.String(captureFile, config.CaptureFile, "file to capture the messages sent and received on the capture channels to, empty disables capturing")
+	flags.StringSlice(inboundQueueChannelWeights, config.InboundQueueChannelWeights, "weights of channels in the fair inbound message queue as channel=weight pairs, channels not listed have weight 1")
+	flags.Int(inboundQueueChannelCapacity, config.InboundQueueChannelCapacity, "maximum number of inbound messages buffered per channel")
+	flags.StringSlice(captureChannels, config.CaptureChannels, "channels to capture messages of, cluster channels are selected by their prefix, e.g., sync-cluster")
 	flags.Duration(unicastMessageTimeout, config.UnicastMessageTimeout, "how long a unicast transmission can take to complete")
 	// unicast manager options
 	flags.Duration(unicastCreateStreamRetryDelay, config.UnicastCreateStreamRetryDelay, "Initial delay between failing to establish a connection with another node and retrying. This delay increases exponentially (exponential backoff) with the number of subsequent failures to establish a connection.")
@@ -110,6 +135,8 @@ func InitializeNetworkFlags(flags *pflag.FlagSet, config *Config) {
 	flags.Bool(peerScoring, config.GossipSubConfig.PeerScoring, "enabling peer scoring on pubsub network")
 	flags.Duration(localMeshLogInterval, config.GossipSubConfig.LocalMeshLogInterval, "logging interval for local mesh in gossipsub")
 	flags.Duration(scoreTracerInterval, config.GossipSubConfig.ScoreTracerInterval, "logging interval for peer score tracer in gossipsub, set to 0 to disable")
+	flags.Uint32(scoreHistorySize, config.GossipSubConfig.ScoreHistorySize, "number of peer score snapshots of the gossipsub score tracer retained per peer, set to 0 to disable the score history")
+	flags.Uint32(scoreHistoryMaxPeers, config.GossipSubConfig.ScoreHistoryMaxPeers, "maximum number of peers the gossipsub score history is retained for")
 	flags.Uint32(rpcSentTrackerCacheSize, config.GossipSubConfig.RPCSentTrackerCacheSize, "cache size of the rpc sent tracker used by the gossipsub mesh tracer.")
 	flags.Uint32(rpcSentTrackerQueueCacheSize, config.GossipSubConfig.RPCSentTrackerQueueCacheSize, "cache size of the rpc sent tracker worker queue.")
 	flags.Int(rpcSentTrackerNumOfWorkers, config.GossipSubConfig.RpcSentTrackerNumOfWorkers, "number of workers for the rpc sent tracker worker pool.")
@@ -122,6 +149,9 @@ func InitializeNetworkFlags(flags *pflag.FlagSet, config *Config) {
 	// gossipsub RPC control message metrics observer inspector configuration
 	flags.Int(metricsInspectorNumberOfWorkers, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCMetricsInspectorConfigs.NumberOfWorkers, "cache size for gossipsub RPC metrics inspector events worker pool queue.")
 	flags.Uint32(metricsInspectorCacheSize, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCMetricsInspectorConfigs.CacheSize, "cache size for gossipsub RPC metrics inspector events worker pool.")
+	flags.Float64(publishInspectorSampleSizePercentage, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCPublishInspectorConfigs.SampleSizePercentage, "percentage of the messages published within a gossipsub RPC to inspect")
+	flags.Float64(publishInspectorMaxSampleSize, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCPublishInspectorConfigs.MaxSampleSize, "max number of the messages published within a gossipsub RPC to inspect")
+	flags.Int(publishInspectorRateLimit, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCPublishInspectorConfigs.RateLimit, "number of messages per second a peer is allowed to publish within gossipsub RPCs, 0 disables rate limiting")
 	// networking event notifications
 	flags.Uint32(gossipSubRPCInspectorNotificationCacheSize, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCInspectorNotificationCacheSize, "cache size for notification events from gossipsub rpc inspector")
 	// application layer spam prevention (alsp) protocol
@@ -129,6 +159,8 @@ func InitializeNetworkFlags(flags *pflag.FlagSet, config *Config) {
 	flags.Uint32(alspSpamRecordCacheSize, config.AlspConfig.SpamRecordCacheSize, "size of spam record cache, recommended to be 10x the number of authorized nodes")
 	flags.Uint32(alspSpamRecordQueueSize, config.AlspConfig.SpamReportQueueSize, "size of spam report queue, recommended to be 100x the number of authorized nodes")
 	flags.Duration(alspHearBeatInterval, config.AlspConfig.HearBeatInterval, "interval between two consecutive heartbeat events at alsp, recommended to leave it as default unless you know what you are doing.")
+	flags.Bool(alspPersistSpamRecords, config.AlspConfig.PersistSpamRecords, "persist the spam records of the alsp protocol to the database, so that penalties of misbehaving nodes survive restarts")
+	flags.Duration(alspPersistenceInterval, config.AlspConfig.SpamRecordPersistenceInterval, "interval between two consecutive snapshots of the alsp spam records to the database, when persistence is enabled")
 
 	flags.Float64(ihaveSyncSampleSizePercentage, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCValidationInspectorConfigs.IHaveSyncInspectSampleSizePercentage, "percentage of ihave messages to sample during synchronous validation")
 	flags.Float64(ihaveAsyncSampleSizePercentage, config.GossipSubConfig.GossipSubRPCInspectorsConfig.GossipSubRPCValidationInspectorConfigs.IHaveAsyncInspectSampleSizePercentage, "percentage of ihave messages to sample during asynchronous validation")
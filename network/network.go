@@ -52,6 +52,12 @@ type Network interface {
 
 	// RegisterPingService registers a ping protocol handler for the given protocol ID
 	RegisterPingService(pingProtocolID protocol.ID, pingInfoProvider PingInfoProvider) (PingService, error)
+
+	// RegisterRequestService registers a request service on the given channel. The handler handles the inbound requests
+	// on the channel, and may be nil if the service is only used to send requests; on a single node, at most one handler
+	// can be registered on a channel at any given time.
+	// The returned RequestService can be used to send requests to the request services of other nodes on the channel.
+	RegisterRequestService(channel channels.Channel, handler RequestHandler, opts ...RequestServiceOption) (RequestService, error)
 }
 
 // Adapter is a wrapper around the Network implementation. It only exposes message dissemination functionalities.
@@ -3,6 +3,7 @@ package blob
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -14,12 +15,14 @@ import (
 	provider "github.com/ipfs/go-ipfs-provider"
 	"github.com/ipfs/go-ipfs-provider/simple"
 	"github.com/libp2p/go-libp2p/core/host"
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/libp2p/go-libp2p/core/routing"
 	"github.com/onflow/go-bitswap"
 	bsmsg "github.com/onflow/go-bitswap/message"
 	bsnet "github.com/onflow/go-bitswap/network"
+	"github.com/onflow/go-bitswap/tracer"
 	"github.com/rs/zerolog"
 	"golang.org/x/time/rate"
 
@@ -35,21 +38,45 @@ import (
 	ipld "github.com/ipfs/go-ipld-format"
 )
 
+const (
+	// DefaultMaxConcurrentRequests is the default maximum number of blob requests served concurrently.
+	DefaultMaxConcurrentRequests = 32
+
+	// preferredProviderTagValue is the value of the connection manager tag of the preferred providers of a request.
+	preferredProviderTagValue = 10
+
+	// reportedTopPeers is the number of peers with the most data exchanged whose bandwidth is reported as metrics.
+	reportedTopPeers = 10
+)
+
 type blobService struct {
 	prefix string
 	component.Component
+	host         host.Host
 	blockService blockservice.BlockService
 	blockStore   blockstore.Blockstore
 	reprovider   provider.Reprovider
 	config       *BlobServiceConfig
+	metrics      module.BitswapMetrics
+	logger       zerolog.Logger
+
+	scheduler *requestScheduler
+	providers *ProviderTracker
+
+	taggedMu sync.Mutex
+	tagged   map[peer.ID]int // number of requests in progress preferring each tagged peer
 }
 
 var _ network.BlobService = (*blobService)(nil)
 var _ component.Component = (*blobService)(nil)
 
 type BlobServiceConfig struct {
-	ReprovideInterval time.Duration    // the interval at which the DHT provider entries are refreshed
-	BitswapOptions    []bitswap.Option // options to pass to the Bitswap service
+	ReprovideInterval     time.Duration    // the interval at which the DHT provider entries are refreshed
+	BitswapOptions        []bitswap.Option // options to pass to the Bitswap service
+	Tracer                tracer.Tracer    // tracer of the messages exchanged by the Bitswap service, may be nil
+	MaxConcurrentRequests int              // the maximum number of blob requests served concurrently, zero for no limit
+	PreferredProviders    int              // the maximum number of recent providers preferred for a request
+	ProviderHeightWindow  uint64           // the maximum height distance for a recent provider to be preferred
 }
 
 // WithReprovideInterval sets the interval at which DHT provider entries are refreshed
@@ -59,13 +86,38 @@ func WithReprovideInterval(d time.Duration) network.BlobServiceOption {
 	}
 }
 
-// WithBitswapOptions sets additional options for Bitswap exchange
+// WithBitswapOptions sets additional options for Bitswap exchange.
+// Note: the blob service installs its own Bitswap tracer, use WithTracer rather than bitswap.WithTracer.
 func WithBitswapOptions(opts ...bitswap.Option) network.BlobServiceOption {
 	return func(bs network.BlobService) {
 		bs.(*blobService).config.BitswapOptions = opts
 	}
 }
 
+// WithTracer sets the tracer of the messages exchanged by the Bitswap service
+func WithTracer(t tracer.Tracer) network.BlobServiceOption {
+	return func(bs network.BlobService) {
+		bs.(*blobService).config.Tracer = t
+	}
+}
+
+// WithMaxConcurrentRequests sets the maximum number of blob requests served concurrently. Further requests wait until
+// a request completes, and are served by decreasing priority, see network.BlobRequestHint.
+func WithMaxConcurrentRequests(n int) network.BlobServiceOption {
+	return func(bs network.BlobService) {
+		bs.(*blobService).config.MaxConcurrentRequests = n
+	}
+}
+
+// WithPreferredProviders sets the maximum number of peers preferred for a request for the blobs of a given height, and
+// the maximum distance between that height and the heights the peers recently served data for.
+func WithPreferredProviders(n int, heightWindow uint64) network.BlobServiceOption {
+	return func(bs network.BlobService) {
+		bs.(*blobService).config.PreferredProviders = n
+		bs.(*blobService).config.ProviderHeightWindow = heightWindow
+	}
+}
+
 // WithHashOnRead sets whether or not the blobstore will rehash the blob data on read
 // When set, calls to GetBlob will fail with an error if the hash of the data in storage does not
 // match its CID
@@ -86,6 +138,14 @@ func WithRateLimit(r float64, b int) network.BlobServiceOption {
 	}
 }
 
+// WithProviderTracker sets the tracker of the peers providing blobs and of the data exchanged with them. By default,
+// the blob service creates its own tracker.
+func WithProviderTracker(tracker *ProviderTracker) network.BlobServiceOption {
+	return func(bs network.BlobService) {
+		bs.(*blobService).providers = tracker
+	}
+}
+
 // NewBlobService creates a new BlobService.
 func NewBlobService(
 	host host.Host,
@@ -99,19 +159,32 @@ func NewBlobService(
 	bsNetwork := bsnet.NewFromIpfsHost(host, r, bsnet.Prefix(protocol.ID(prefix)))
 	bs := &blobService{
 		prefix: prefix,
+		host:   host,
 		config: &BlobServiceConfig{
-			ReprovideInterval: 12 * time.Hour,
+			ReprovideInterval:     12 * time.Hour,
+			MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+			PreferredProviders:    DefaultPreferredProviders,
+			ProviderHeightWindow:  DefaultProviderHeightWindow,
 		},
 		blockStore: blockstore.NewBlockstore(ds),
+		metrics:    metrics,
+		tagged:     make(map[peer.ID]int),
+		logger:     logger.With().Str("component", "blob_service").Str("prefix", prefix).Logger(),
 	}
 
 	for _, opt := range opts {
 		opt(bs)
 	}
 
+	bs.scheduler = newRequestScheduler(bs.config.MaxConcurrentRequests)
+	if bs.providers == nil {
+		bs.providers = NewProviderTracker(bs.config.ProviderHeightWindow, DefaultProviderTTL)
+	}
+	bitswapOptions := append(append([]bitswap.Option{}, bs.config.BitswapOptions...), bitswap.WithTracer(&accountingTracer{bs: bs}))
+
 	cm := component.NewComponentManagerBuilder().
 		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
-			btswp := bitswap.New(ctx, bsNetwork, bs.blockStore, bs.config.BitswapOptions...)
+			btswp := bitswap.New(ctx, bsNetwork, bs.blockStore, bitswapOptions...)
 			bs.blockService = blockservice.New(bs.blockStore, btswp)
 
 			ready()
@@ -124,7 +197,7 @@ func NewBlobService(
 				case <-ticker.C:
 					stat, err := btswp.Stat()
 					if err != nil {
-						logger.Err(err).Str("component", "blob_service").Str("prefix", prefix).Msg("failed to get bitswap stats")
+						bs.logger.Err(err).Msg("failed to get bitswap stats")
 						continue
 					}
 
@@ -137,6 +210,8 @@ func NewBlobService(
 					metrics.DupBlobsReceived(prefix, stat.DupBlksReceived)
 					metrics.DupDataReceived(prefix, stat.DupDataReceived)
 					metrics.MessagesReceived(prefix, stat.MessagesReceived)
+
+					bs.reportPeerBandwidth()
 				}
 			}
 		}).
@@ -174,16 +249,11 @@ func (bs *blobService) TriggerReprovide(ctx context.Context) error {
 }
 
 func (bs *blobService) GetBlob(ctx context.Context, c cid.Cid) (blobs.Blob, error) {
-	blob, err := bs.blockService.GetBlock(ctx, c)
-	if ipld.IsNotFound(err) {
-		return nil, network.ErrBlobNotFound
-	}
-
-	return blob, err
+	return bs.getBlob(ctx, c, bs.blockService.GetBlock)
 }
 
 func (bs *blobService) GetBlobs(ctx context.Context, ks []cid.Cid) <-chan blobs.Blob {
-	return bs.blockService.GetBlocks(ctx, ks)
+	return bs.getBlobs(ctx, ks, bs.blockService.GetBlocks)
 }
 
 func (bs *blobService) AddBlob(ctx context.Context, b blobs.Blob) error {
@@ -199,21 +269,194 @@ func (bs *blobService) DeleteBlob(ctx context.Context, c cid.Cid) error {
 }
 
 func (bs *blobService) GetSession(ctx context.Context) network.BlobGetter {
-	return &blobServiceSession{blockservice.NewSession(ctx, bs.blockService)}
+	return &blobServiceSession{bs, blockservice.NewSession(ctx, bs.blockService)}
+}
+
+// getBlob gets the blob with the given getter once the request is admitted by the scheduler, according to the hint
+// carried by the context.
+func (bs *blobService) getBlob(ctx context.Context, c cid.Cid, get func(context.Context, cid.Cid) (blocks.Block, error)) (blobs.Blob, error) {
+	hint, hinted := network.BlobRequestHintFromContext(ctx)
+	release, err := bs.scheduler.acquire(ctx, hint.Priority)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if hinted {
+		ks := []cid.Cid{c}
+		bs.providers.Want(ks, hint.Height)
+		defer bs.providers.Unwant(ks)
+		defer bs.preferProviders(ctx, hint.Height)()
+	}
+
+	blob, err := get(ctx, c)
+	if ipld.IsNotFound(err) {
+		return nil, network.ErrBlobNotFound
+	}
+
+	return blob, err
+}
+
+// getBlobs gets the blobs with the given getter once the request is admitted by the scheduler, according to the hint
+// carried by the context. The returned channel is closed once all blobs are returned, or the request is abandoned.
+func (bs *blobService) getBlobs(ctx context.Context, ks []cid.Cid, get func(context.Context, []cid.Cid) <-chan blocks.Block) <-chan blobs.Blob {
+	out := make(chan blobs.Blob, len(ks))
+
+	go func() {
+		defer close(out)
+
+		hint, hinted := network.BlobRequestHintFromContext(ctx)
+		release, err := bs.scheduler.acquire(ctx, hint.Priority)
+		if err != nil {
+			return
+		}
+		defer release()
+
+		if hinted {
+			bs.providers.Want(ks, hint.Height)
+			defer bs.providers.Unwant(ks)
+			defer bs.preferProviders(ctx, hint.Height)()
+		}
+
+		for blob := range get(ctx, ks) {
+			select {
+			case out <- blob:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// preferProviders makes sure the service is connected to the peers which recently served data for heights near the
+// given height, so that they receive the wants of the request, and protects these connections from being trimmed by
+// the connection manager while the request is in progress.
+// Returns the function releasing the protection of the connections, which must be called once the request completes.
+func (bs *blobService) preferProviders(ctx context.Context, height uint64) func() {
+	if bs.config.PreferredProviders <= 0 {
+		return func() {}
+	}
+
+	preferred := bs.providers.Preferred(height, bs.config.PreferredProviders)
+	for _, pid := range preferred {
+		bs.tagProvider(pid)
+		if bs.host.Network().Connectedness(pid) == libp2pnet.Connected {
+			continue
+		}
+
+		pid := pid
+		go func() {
+			err := bs.host.Connect(ctx, peer.AddrInfo{ID: pid})
+			if err != nil {
+				bs.logger.Debug().Err(err).Str("peer_id", pid.String()).Msg("could not connect to preferred provider")
+			}
+		}()
+	}
+
+	return func() {
+		for _, pid := range preferred {
+			bs.untagProvider(pid)
+		}
+	}
+}
+
+// tagProvider tags the connection to the given peer as the one of a preferred provider, for one more request.
+func (bs *blobService) tagProvider(pid peer.ID) {
+	bs.taggedMu.Lock()
+	defer bs.taggedMu.Unlock()
+
+	if bs.tagged[pid] == 0 {
+		bs.host.ConnManager().TagPeer(pid, bs.providerTag(), preferredProviderTagValue)
+	}
+	bs.tagged[pid]++
+}
+
+// untagProvider removes the tag of the connection to the given peer once no request in progress prefers it anymore.
+func (bs *blobService) untagProvider(pid peer.ID) {
+	bs.taggedMu.Lock()
+	defer bs.taggedMu.Unlock()
+
+	bs.tagged[pid]--
+	if bs.tagged[pid] > 0 {
+		return
+	}
+	delete(bs.tagged, pid)
+	bs.host.ConnManager().UntagPeer(pid, bs.providerTag())
+}
+
+func (bs *blobService) providerTag() string {
+	return "blob-provider:" + bs.prefix
 }
 
 type blobServiceSession struct {
+	bs      *blobService
 	session *blockservice.Session
 }
 
 var _ network.BlobGetter = (*blobServiceSession)(nil)
 
 func (s *blobServiceSession) GetBlob(ctx context.Context, c cid.Cid) (blobs.Blob, error) {
-	return s.session.GetBlock(ctx, c)
+	return s.bs.getBlob(ctx, c, s.session.GetBlock)
 }
 
 func (s *blobServiceSession) GetBlobs(ctx context.Context, ks []cid.Cid) <-chan blobs.Blob {
-	return s.session.GetBlocks(ctx, ks)
+	return s.bs.getBlobs(ctx, ks, s.session.GetBlocks)
+}
+
+// reportPeerBandwidth forgets the peers no longer exchanging data, and reports the bandwidth of the peers with the most
+// data exchanged. Only the top peers are reported, as the number of peers is unbounded on the public network.
+func (bs *blobService) reportPeerBandwidth() {
+	bs.providers.Prune()
+	top := bs.providers.TopPeers(reportedTopPeers)
+	received := make(map[string]uint64, len(top))
+	sent := make(map[string]uint64, len(top))
+	for _, p := range top {
+		received[p.PeerID.String()] = p.Received
+		sent[p.PeerID.String()] = p.Sent
+	}
+	bs.metrics.TopPeersBandwidth(bs.prefix, received, sent)
+}
+
+// accountingTracer accounts the blobs exchanged with each peer by the Bitswap service, records the providers of the
+// wanted blobs, and forwards all messages to the configured tracer.
+type accountingTracer struct {
+	bs *blobService
+}
+
+var _ tracer.Tracer = (*accountingTracer)(nil)
+
+func (t *accountingTracer) MessageReceived(pid peer.ID, msg bsmsg.BitSwapMessage) {
+	blks := msg.Blocks()
+	if len(blks) > 0 {
+		ks := make([]cid.Cid, len(blks))
+		size := uint64(0)
+		for i, blk := range blks {
+			ks[i] = blk.Cid()
+			size += uint64(len(blk.RawData()))
+		}
+		t.bs.providers.Received(pid, ks, size)
+	}
+
+	if t.bs.config.Tracer != nil {
+		t.bs.config.Tracer.MessageReceived(pid, msg)
+	}
+}
+
+func (t *accountingTracer) MessageSent(pid peer.ID, msg bsmsg.BitSwapMessage) {
+	blks := msg.Blocks()
+	if len(blks) > 0 {
+		size := uint64(0)
+		for _, blk := range blks {
+			size += uint64(len(blk.RawData()))
+		}
+		t.bs.providers.Sent(pid, size)
+	}
+
+	if t.bs.config.Tracer != nil {
+		t.bs.config.Tracer.MessageSent(pid, msg)
+	}
 }
 
 type rateLimitedBlockStore struct {
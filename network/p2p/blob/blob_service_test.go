@@ -1,16 +1,25 @@
 package blob_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
 	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/blobs"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
 	modmock "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/p2p/blob"
 	"github.com/onflow/flow-go/utils/unittest"
 )
@@ -129,3 +138,64 @@ func mockIdentity(t *testing.T, role flow.Role) (*flow.Identity, peer.ID) {
 
 	return identity, peerID
 }
+
+// TestBlobService_PeerBandwidth evaluates that blobs requested with a hint are fetched from a connected peer, and that
+// the data exchanged is accounted per peer on both sides.
+func TestBlobService_PeerBandwidth(t *testing.T) {
+	mn, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mn.Close() })
+	hosts := mn.Hosts()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+
+	const prefix = "/flow/test"
+	data := blobs.NewBlob([]byte("execution data"))
+	size := uint64(len(data.RawData()))
+
+	providerTracker := blob.NewProviderTracker(blob.DefaultProviderHeightWindow, blob.DefaultProviderTTL)
+	requesterTracker := blob.NewProviderTracker(blob.DefaultProviderHeightWindow, blob.DefaultProviderTTL)
+	provider := blob.NewBlobService(hosts[0], nullRouting{}, prefix, dssync.MutexWrap(datastore.NewMapDatastore()), metrics.NewNoopCollector(), unittest.Logger(), blob.WithProviderTracker(providerTracker))
+	requester := blob.NewBlobService(hosts[1], nullRouting{}, prefix, dssync.MutexWrap(datastore.NewMapDatastore()), metrics.NewNoopCollector(), unittest.Logger(), blob.WithProviderTracker(requesterTracker))
+	provider.Start(signalerCtx)
+	requester.Start(signalerCtx)
+	unittest.RequireComponentsReadyBefore(t, time.Second, provider, requester)
+
+	require.NoError(t, provider.AddBlob(ctx, data))
+
+	getCtx, getCancel := context.WithTimeout(network.WithBlobRequestHint(ctx, network.BlobRequestHint{Height: 10, Priority: 1}), 5*time.Second)
+	defer getCancel()
+	var fetched []blobs.Blob
+	for b := range requester.GetSession(getCtx).GetBlobs(getCtx, []cid.Cid{data.Cid()}) {
+		fetched = append(fetched, b)
+	}
+	require.Len(t, fetched, 1)
+	require.Equal(t, data.RawData(), fetched[0].RawData())
+
+	require.Eventually(t, func() bool {
+		_, sent := providerTracker.Bandwidth(hosts[1].ID())
+		return sent == size
+	}, time.Second, 10*time.Millisecond, "data sent was not accounted")
+	require.Eventually(t, func() bool {
+		received, _ := requesterTracker.Bandwidth(hosts[0].ID())
+		return received == size
+	}, time.Second, 10*time.Millisecond, "data received was not accounted")
+
+	cancel()
+	unittest.RequireComponentsDoneBefore(t, time.Second, provider, requester)
+}
+
+// nullRouting is a content routing that neither announces nor finds any providers.
+type nullRouting struct{}
+
+func (nullRouting) Provide(context.Context, cid.Cid, bool) error {
+	return nil
+}
+
+func (nullRouting) FindProvidersAsync(context.Context, cid.Cid, int) <-chan peer.AddrInfo {
+	ch := make(chan peer.AddrInfo)
+	close(ch)
+	return ch
+}
@@ -0,0 +1,245 @@
+package blob
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// DefaultProviderHeightWindow is the default maximum distance between the height of a request and the heights a
+	// peer recently served data for, for the peer to be preferred for the request.
+	DefaultProviderHeightWindow = 100
+
+	// DefaultProviderTTL is the default duration after which a peer that did not serve any data is no longer preferred.
+	DefaultProviderTTL = 10 * time.Minute
+
+	// DefaultPreferredProviders is the default maximum number of peers preferred for a request.
+	DefaultPreferredProviders = 3
+)
+
+// ProviderTracker tracks the peers which recently served blobs, along with the heights of the blocks the blobs belong
+// to, as well as the amount of data exchanged with each peer.
+// Blobs are attributed to heights while they are wanted with a height hint, see network.BlobRequestHint.
+// Peers which have not exchanged any data for longer than the TTL are forgotten by Prune, so that the number of
+// tracked peers stays bounded by the peers recently active.
+type ProviderTracker struct {
+	mu        sync.Mutex
+	window    uint64
+	ttl       time.Duration
+	wanted    map[cid.Cid]*wantedBlob
+	providers map[peer.ID]*providerRecord
+	now       func() time.Time
+}
+
+type wantedBlob struct {
+	height uint64
+	count  int // number of requests wanting the blob
+}
+
+type providerRecord struct {
+	height     uint64    // highest height the peer served data for
+	lastSeen   time.Time // last time the peer served a wanted blob, zero if the peer is not a provider
+	lastActive time.Time // last time any data was exchanged with the peer
+	received   uint64    // total bytes of blobs received from the peer
+	sent       uint64    // total bytes of blobs sent to the peer
+}
+
+// PeerBandwidth is the amount of blob data exchanged with a peer.
+type PeerBandwidth struct {
+	PeerID   peer.ID
+	Received uint64 // total bytes of blobs received from the peer
+	Sent     uint64 // total bytes of blobs sent to the peer
+}
+
+// NewProviderTracker returns a new provider tracker.
+// Args:
+//   - window: the maximum distance between the height of a request and the height a peer served data for, for the
+//     peer to be preferred for the request.
+//   - ttl: the duration after which a peer that did not serve any data is no longer preferred.
+func NewProviderTracker(window uint64, ttl time.Duration) *ProviderTracker {
+	return &ProviderTracker{
+		window:    window,
+		ttl:       ttl,
+		wanted:    make(map[cid.Cid]*wantedBlob),
+		providers: make(map[peer.ID]*providerRecord),
+		now:       time.Now,
+	}
+}
+
+// Want records that the given blobs are wanted for the block at the given height, until Unwant is called for them.
+func (t *ProviderTracker) Want(ks []cid.Cid, height uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, c := range ks {
+		if w, ok := t.wanted[c]; ok {
+			w.count++
+			continue
+		}
+		t.wanted[c] = &wantedBlob{height: height, count: 1}
+	}
+}
+
+// Unwant records that the given blobs are no longer wanted by a request which previously called Want for them.
+func (t *ProviderTracker) Unwant(ks []cid.Cid) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, c := range ks {
+		w, ok := t.wanted[c]
+		if !ok {
+			continue
+		}
+		w.count--
+		if w.count <= 0 {
+			delete(t.wanted, c)
+		}
+	}
+}
+
+// Received records the blobs received from the given peer, of the given total size in bytes. Peers serving wanted
+// blobs are recorded as providers of the heights of the blobs.
+func (t *ProviderTracker) Received(pid peer.ID, ks []cid.Cid, size uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record := t.record(pid)
+	record.received += size
+	record.lastActive = t.now()
+	for _, c := range ks {
+		w, ok := t.wanted[c]
+		if !ok {
+			continue
+		}
+		record.lastSeen = t.now()
+		if w.height > record.height {
+			record.height = w.height
+		}
+	}
+}
+
+// Sent records that blobs of the given total size in bytes were sent to the given peer.
+func (t *ProviderTracker) Sent(pid peer.ID, size uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record := t.record(pid)
+	record.sent += size
+	record.lastActive = t.now()
+}
+
+// Preferred returns up to n peers which recently served data for heights within the window of the given height,
+// ordered by the distance of the heights they served and then by how recently they served them.
+func (t *ProviderTracker) Preferred(height uint64, n int) []peer.ID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	type candidate struct {
+		pid      peer.ID
+		distance uint64
+		lastSeen time.Time
+	}
+
+	now := t.now()
+	var candidates []candidate
+	for pid, record := range t.providers {
+		if record.lastSeen.IsZero() {
+			continue
+		}
+		if now.Sub(record.lastSeen) > t.ttl {
+			// the peer is no longer considered a provider, while its bandwidth accounting is kept.
+			record.lastSeen = time.Time{}
+			record.height = 0
+			continue
+		}
+		distance := distanceOf(height, record.height)
+		if distance > t.window {
+			continue
+		}
+		candidates = append(candidates, candidate{pid: pid, distance: distance, lastSeen: record.lastSeen})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance == candidates[j].distance {
+			return candidates[i].lastSeen.After(candidates[j].lastSeen)
+		}
+		return candidates[i].distance < candidates[j].distance
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+
+	peers := make([]peer.ID, len(candidates))
+	for i, c := range candidates {
+		peers[i] = c.pid
+	}
+	return peers
+}
+
+// Bandwidth returns the total bytes of blobs received from and sent to the given peer.
+func (t *ProviderTracker) Bandwidth(pid peer.ID) (received uint64, sent uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record, ok := t.providers[pid]
+	if !ok {
+		return 0, 0
+	}
+	return record.received, record.sent
+}
+
+// TopPeers returns up to n peers with the most blob data exchanged, ordered by the total bytes received from and sent
+// to them.
+func (t *ProviderTracker) TopPeers(n int) []PeerBandwidth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	peers := make([]PeerBandwidth, 0, len(t.providers))
+	for pid, record := range t.providers {
+		peers = append(peers, PeerBandwidth{PeerID: pid, Received: record.received, Sent: record.sent})
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Received+peers[i].Sent > peers[j].Received+peers[j].Sent
+	})
+	if len(peers) > n {
+		peers = peers[:n]
+	}
+	return peers
+}
+
+// Prune forgets the peers which have not exchanged any data for longer than the TTL, including their bandwidth
+// accounting. Returns the number of peers still tracked.
+func (t *ProviderTracker) Prune() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	for pid, record := range t.providers {
+		if now.Sub(record.lastActive) > t.ttl {
+			delete(t.providers, pid)
+		}
+	}
+	return len(t.providers)
+}
+
+// record returns the record of the given peer, creating it if needed.
+// Note: this function is not thread-safe and should be called with the lock held.
+func (t *ProviderTracker) record(pid peer.ID) *providerRecord {
+	record, ok := t.providers[pid]
+	if !ok {
+		record = &providerRecord{}
+		t.providers[pid] = record
+	}
+	return record
+}
+
+func distanceOf(a uint64, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
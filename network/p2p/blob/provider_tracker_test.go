@@ -0,0 +1,96 @@
+package blob_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/p2p/blob"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestProviderTracker_Preferred evaluates that peers which served wanted blobs are preferred for requests of nearby
+// heights, closest heights first, while peers serving unwanted blobs are not.
+func TestProviderTracker_Preferred(t *testing.T) {
+	tracker := blob.NewProviderTracker(10, time.Minute)
+	near, far, unwanted, outside := peerID(t), peerID(t), peerID(t), peerID(t)
+
+	serve := func(pid peer.ID, height uint64) {
+		ks := []cid.Cid{flow.IdToCid(unittest.IdentifierFixture())}
+		tracker.Want(ks, height)
+		tracker.Received(pid, ks, 100)
+		tracker.Unwant(ks)
+	}
+	serve(near, 100)
+	serve(far, 95)
+	serve(outside, 50)
+	tracker.Received(unwanted, []cid.Cid{flow.IdToCid(unittest.IdentifierFixture())}, 100)
+
+	require.Equal(t, []peer.ID{near, far}, tracker.Preferred(101, 3))
+	require.Equal(t, []peer.ID{near}, tracker.Preferred(101, 1))
+	require.Equal(t, []peer.ID{outside}, tracker.Preferred(45, 3))
+}
+
+// TestProviderTracker_Bandwidth evaluates that the data exchanged with each peer is accounted.
+func TestProviderTracker_Bandwidth(t *testing.T) {
+	tracker := blob.NewProviderTracker(10, time.Minute)
+	pid := peerID(t)
+
+	tracker.Received(pid, []cid.Cid{flow.IdToCid(unittest.IdentifierFixture())}, 100)
+	tracker.Received(pid, []cid.Cid{flow.IdToCid(unittest.IdentifierFixture())}, 50)
+	tracker.Sent(pid, 20)
+
+	received, sent := tracker.Bandwidth(pid)
+	require.Equal(t, uint64(150), received)
+	require.Equal(t, uint64(20), sent)
+
+	received, sent = tracker.Bandwidth(peerID(t))
+	require.Zero(t, received)
+	require.Zero(t, sent)
+}
+
+// TestProviderTracker_TopPeers evaluates that the peers with the most data exchanged are returned first.
+func TestProviderTracker_TopPeers(t *testing.T) {
+	tracker := blob.NewProviderTracker(10, time.Minute)
+	small, large, medium := peerID(t), peerID(t), peerID(t)
+
+	tracker.Sent(small, 10)
+	tracker.Received(large, []cid.Cid{flow.IdToCid(unittest.IdentifierFixture())}, 100)
+	tracker.Sent(large, 50)
+	tracker.Received(medium, []cid.Cid{flow.IdToCid(unittest.IdentifierFixture())}, 60)
+
+	require.Equal(t, []blob.PeerBandwidth{
+		{PeerID: large, Received: 100, Sent: 50},
+		{PeerID: medium, Received: 60},
+	}, tracker.TopPeers(2))
+	require.Len(t, tracker.TopPeers(10), 3)
+}
+
+// TestProviderTracker_Prune evaluates that peers which did not exchange any data within the TTL are forgotten, so
+// that the number of tracked peers stays bounded.
+func TestProviderTracker_Prune(t *testing.T) {
+	const ttl = 50 * time.Millisecond
+	tracker := blob.NewProviderTracker(10, ttl)
+	inactive, active := peerID(t), peerID(t)
+
+	tracker.Sent(inactive, 10)
+	time.Sleep(2 * ttl)
+	tracker.Sent(active, 20)
+
+	require.Equal(t, 1, tracker.Prune())
+	received, sent := tracker.Bandwidth(inactive)
+	require.Zero(t, received)
+	require.Zero(t, sent)
+	_, sent = tracker.Bandwidth(active)
+	require.Equal(t, uint64(20), sent)
+}
+
+func peerID(t *testing.T) peer.ID {
+	pid, err := unittest.PeerIDFromFlowID(&flow.Identity{NetworkPubKey: unittest.NetworkingPrivKeyFixture().PublicKey()})
+	require.NoError(t, err)
+	return pid
+}
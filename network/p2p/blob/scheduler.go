@@ -0,0 +1,114 @@
+package blob
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// requestScheduler limits the number of concurrent blob requests, and admits waiting requests by decreasing priority,
+// and in the order they arrived for requests of the same priority.
+type requestScheduler struct {
+	mu       sync.Mutex
+	capacity int // maximum number of admitted requests, zero for no limit
+	admitted int
+	waiting  waitQueue
+	sequence uint64 // sequence number of the waiting requests, used to order requests of the same priority
+}
+
+func newRequestScheduler(capacity int) *requestScheduler {
+	return &requestScheduler{capacity: capacity}
+}
+
+// acquire blocks until the request with the given priority is admitted, and returns the function releasing its slot,
+// which must be called exactly once when the request completes.
+// Returns the error of the context if it is done before the request is admitted.
+func (s *requestScheduler) acquire(ctx context.Context, priority int32) (func(), error) {
+	s.mu.Lock()
+	if s.capacity <= 0 || (s.admitted < s.capacity && s.waiting.Len() == 0) {
+		s.admitted++
+		s.mu.Unlock()
+		return s.release, nil
+	}
+
+	s.sequence++
+	w := &waiter{priority: priority, sequence: s.sequence, admit: make(chan struct{})}
+	heap.Push(&s.waiting, w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.admit:
+		return s.release, nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if w.index < 0 {
+			// the request was admitted concurrently, hand its slot over to the next waiting request.
+			s.admitted--
+			s.admitNext()
+		} else {
+			heap.Remove(&s.waiting, w.index)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// release releases the slot of an admitted request, admitting the next waiting request.
+func (s *requestScheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.admitted--
+	s.admitNext()
+}
+
+// admitNext admits waiting requests while there are free slots.
+// Note: this function is not thread-safe and should be called with the lock held.
+func (s *requestScheduler) admitNext() {
+	for s.admitted < s.capacity && s.waiting.Len() > 0 {
+		w := heap.Pop(&s.waiting).(*waiter)
+		s.admitted++
+		close(w.admit)
+	}
+}
+
+type waiter struct {
+	priority int32
+	sequence uint64
+	index    int // index of the waiter in the queue, -1 once it is admitted
+	admit    chan struct{}
+}
+
+// waitQueue is a max-heap of the waiting requests, ordered by their priority and then by the order they arrived.
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].priority == q[j].priority {
+		return q[i].sequence < q[j].sequence
+	}
+	return q[i].priority > q[j].priority
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
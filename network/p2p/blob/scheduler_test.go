@@ -0,0 +1,66 @@
+package blob
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestRequestScheduler_Priority evaluates that waiting requests are admitted by decreasing priority, and in the order
+// they arrived for the same priority.
+func TestRequestScheduler_Priority(t *testing.T) {
+	s := newRequestScheduler(1)
+	release, err := s.acquire(context.Background(), 0)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var admitted []int
+	wg := sync.WaitGroup{}
+	for i, priority := range []int32{-2, 0, -1, 0} {
+		i, priority := i, priority
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := s.acquire(context.Background(), priority)
+			require.NoError(t, err)
+			mu.Lock()
+			admitted = append(admitted, i)
+			mu.Unlock()
+			release()
+		}()
+		// wait for the request to be queued, so that requests arrive in order.
+		require.Eventually(t, func() bool {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.waiting.Len() == i+1
+		}, time.Second, time.Millisecond)
+	}
+
+	release()
+	unittest.RequireReturnsBefore(t, wg.Wait, time.Second, "requests were not admitted")
+	require.Equal(t, []int{1, 3, 2, 0}, admitted)
+}
+
+// TestRequestScheduler_Cancel evaluates that a canceled waiting request gives up its place without leaking a slot.
+func TestRequestScheduler_Cancel(t *testing.T) {
+	s := newRequestScheduler(1)
+	release, err := s.acquire(context.Background(), 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = s.acquire(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release, err = s.acquire(context.Background(), 0)
+	require.NoError(t, err)
+	release()
+	require.Zero(t, s.admitted)
+	require.Zero(t, s.waiting.Len())
+}
@@ -59,6 +59,10 @@ type GossipSubBuilder interface {
 	// If the gossipsub score tracer interval has already been set, a fatal error is logged.
 	SetGossipSubScoreTracerInterval(time.Duration)
 
+	// SetGossipSubScoreHistory enables the peer score history of the gossipsub score tracer with the given configuration.
+	// If the gossipsub score history has already been set, a fatal error is logged.
+	SetGossipSubScoreHistory(*p2pconf.GossipSubScoreHistoryConfig)
+
 	// SetGossipSubTracer sets the gossipsub tracer of the builder.
 	// If the gossipsub tracer has already been set, a fatal error is logged.
 	SetGossipSubTracer(PubSubTracer)
@@ -135,6 +139,7 @@ type NodeBuilder interface {
 	SetRateLimiterDistributor(UnicastRateLimiterDistributor) NodeBuilder
 	SetGossipSubTracer(PubSubTracer) NodeBuilder
 	SetGossipSubScoreTracerInterval(time.Duration) NodeBuilder
+	SetGossipSubScoreHistory(*p2pconf.GossipSubScoreHistoryConfig) NodeBuilder
 	OverrideDefaultRpcInspectorSuiteFactory(GossipSubRpcInspectorSuiteFactoryFunc) NodeBuilder
 	Build() (LibP2PNode, error)
 }
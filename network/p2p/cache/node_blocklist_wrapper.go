@@ -106,6 +106,21 @@ func (w *NodeDisallowListingWrapper) Update(disallowList flow.IdentifierList) er
 	return nil
 }
 
+// NotifyDisallowList notifies the update consumer of the current disallow-list. The disallow-list is persisted,
+// while the disallow-listing causes of the networking layer are held in memory only. Hence, this function must be
+// called once the update consumer is initialized on startup, so that the nodes disallow-listed by the operator are
+// disallow-listed again after a restart.
+func (w *NodeDisallowListingWrapper) NotifyDisallowList() {
+	disallowList := w.GetDisallowList()
+	if len(disallowList) == 0 {
+		return
+	}
+	w.updateConsumerOracle().OnDisallowListNotification(&network.DisallowListingUpdate{
+		FlowIds: disallowList,
+		Cause:   network.DisallowListedCauseAdmin,
+	})
+}
+
 // ClearDisallowList purges the set of blocked node IDs. Convenience function
 // equivalent to w.Update(nil). No errors are expected during normal operations.
 func (w *NodeDisallowListingWrapper) ClearDisallowList() error {
@@ -447,3 +447,38 @@ func (s *NodeDisallowListWrapperTestSuite) TestDataBasePersist() {
 		require.Equal(s.T(), disallowList2.Lookup(), w2.GetDisallowList().Lookup())
 	})
 }
+
+// TestNotifyDisallowList verifies that a wrapper initialized from the database notifies the update consumer of the
+// persisted disallow-list, so that the disallow-listing of nodes by the operator survives restarts.
+func (s *NodeDisallowListWrapperTestSuite) TestNotifyDisallowList() {
+	s.Run("empty disallow-list is not notified", func() {
+		s.wrapper.NotifyDisallowList()
+		s.updateConsumer.AssertNotCalled(s.T(), "OnDisallowListNotification", mock.Anything)
+	})
+
+	s.Run("persisted disallow-list is notified", func() {
+		disallowList := unittest.IdentifierListFixture(8)
+		s.updateConsumer.On("OnDisallowListNotification", &network.DisallowListingUpdate{
+			FlowIds: disallowList,
+			Cause:   network.DisallowListedCauseAdmin,
+		}).Return().Once()
+		require.NoError(s.T(), s.wrapper.Update(disallowList))
+
+		// newly created wrapper, as on a restart, notifies the disallow-list read from the database
+		w, err := cache.NewNodeDisallowListWrapper(s.provider, s.DB, func() network.DisallowListNotificationConsumer {
+			return s.updateConsumer
+		})
+		require.NoError(s.T(), err)
+		s.updateConsumer.On("OnDisallowListNotification", mock.MatchedBy(func(update *network.DisallowListingUpdate) bool {
+			notified := update.FlowIds.Lookup()
+			for _, id := range disallowList {
+				if _, ok := notified[id]; !ok {
+					return false
+				}
+			}
+			return update.Cause == network.DisallowListedCauseAdmin && len(update.FlowIds) == len(disallowList)
+		})).Return().Once()
+		w.NotifyDisallowList()
+		s.updateConsumer.AssertNumberOfCalls(s.T(), "OnDisallowListNotification", 2)
+	})
+}
@@ -11,6 +11,15 @@ import (
 	flownet "github.com/onflow/flow-go/network"
 )
 
+// ByteCounter is implemented by compressed streams, and reports the number of bytes written to the stream
+// before and after compression.
+type ByteCounter interface {
+	// WrittenBytes returns the number of bytes written to the stream so far, before and after compression.
+	WrittenBytes() (uncompressed uint64, compressed uint64)
+}
+
+var _ ByteCounter = (*compressedStream)(nil)
+
 // compressedStream is an internal networking layer data structure,
 // which implements a compression mechanism as a wrapper around a native
 // libp2p stream.
@@ -23,6 +32,21 @@ type compressedStream struct {
 
 	r io.ReadCloser
 	w flownet.WriteCloseFlusher
+
+	uncompressed uint64          // number of bytes written to the stream before compression
+	counter      *countingWriter // counts the bytes written to the underlying stream after compression
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	io.Writer
+	n uint64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.Writer.Write(b)
+	c.n += uint64(n)
+	return n, err
 }
 
 // NewCompressedStream creates a compressed stream with gzip as default compressor.
@@ -30,9 +54,10 @@ func NewCompressedStream(s network.Stream, compressor flownet.Compressor) (*comp
 	c := &compressedStream{
 		Stream:     s,
 		compressor: compressor,
+		counter:    &countingWriter{Writer: s},
 	}
 
-	w, err := c.compressor.NewWriter(s)
+	w, err := c.compressor.NewWriter(c.counter)
 	if err != nil {
 		return nil, fmt.Errorf("could not create compressor writer: %w", err)
 	}
@@ -47,10 +72,19 @@ func (c *compressedStream) Write(b []byte) (int, error) {
 	defer c.writeLock.Unlock()
 
 	n, err := c.w.Write(b)
+	c.uncompressed += uint64(n)
 
 	return n, multierr.Combine(err, c.w.Flush())
 }
 
+// WrittenBytes returns the number of bytes written to the stream so far, before and after compression.
+func (c *compressedStream) WrittenBytes() (uint64, uint64) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	return c.uncompressed, c.counter.n
+}
+
 func (c *compressedStream) Read(b []byte) (int, error) {
 	c.readLock.Lock()
 	defer c.readLock.Unlock()
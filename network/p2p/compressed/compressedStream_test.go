@@ -49,6 +49,11 @@ func TestHappyPath(t *testing.T) {
 
 	unittest.RequireReturnsBefore(t, writeWG.Wait, 1*time.Second, "timeout for writing on stream")
 	unittest.RequireReturnsBefore(t, readWG.Wait, 1*time.Second, "timeout for reading from stream")
+
+	// written bytes are counted before and after compression
+	uncompressed, compressed := mca.WrittenBytes()
+	require.Equal(t, uint64(textByteLen), uncompressed)
+	require.Greater(t, compressed, uint64(0))
 }
 
 // TestUnhappyPath evaluates that sending uncompressed data to the compressed end of a stream results
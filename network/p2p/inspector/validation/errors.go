@@ -112,3 +112,29 @@ func IsErrUnstakedPeer(err error) bool {
 	var e ErrUnstakedPeer
 	return errors.As(err, &e)
 }
+
+// ErrUnauthorizedPublish error that indicates a message published within an RPC is not authorized, either because its
+// sender is not allowed to take part in the topic, or because the originator of the message is not authorized to send it.
+type ErrUnauthorizedPublish struct {
+	topic channels.Topic
+	err   error
+}
+
+func (e ErrUnauthorizedPublish) Error() string {
+	return fmt.Sprintf("unauthorized message published on topic %s: %s", e.topic, e.err)
+}
+
+func (e ErrUnauthorizedPublish) Unwrap() error {
+	return e.err
+}
+
+// NewUnauthorizedPublishErr returns a new ErrUnauthorizedPublish.
+func NewUnauthorizedPublishErr(topic channels.Topic, err error) ErrUnauthorizedPublish {
+	return ErrUnauthorizedPublish{topic: topic, err: err}
+}
+
+// IsErrUnauthorizedPublish returns true if an error is ErrUnauthorizedPublish.
+func IsErrUnauthorizedPublish(err error) bool {
+	var e ErrUnauthorizedPublish
+	return errors.As(err, &e)
+}
@@ -0,0 +1,258 @@
+package validation
+
+import (
+	"fmt"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/util"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/inspector/internal/ratelimit"
+	p2pmsg "github.com/onflow/flow-go/network/p2p/message"
+	"github.com/onflow/flow-go/network/p2p/p2pconf"
+	"github.com/onflow/flow-go/utils/logging"
+	flowrand "github.com/onflow/flow-go/utils/rand"
+)
+
+const (
+	// publishInspectorComponentName the rpc publish message inspector component name.
+	publishInspectorComponentName = "gossipsub_rpc_publish_message_inspector"
+)
+
+// PublishMessageInspector RPC message inspector that inspects the messages published within an RPC, rather than its
+// control messages. The number of messages published by each peer is rate limited, and a random sample of the published
+// messages of each RPC on non-public channels is checked for:
+//  1. a valid flow topic,
+//  2. the role of the sender of the RPC being allowed to take part in the topic, and
+//  3. the originator of the message being authorized to publish the message type on the channel of the topic.
+//
+// When any of these rules is broken, the RPC is rejected and feedback is given via the peer scoring notifier.
+// As honest peers only forward messages which passed their topic validators, the sender of the RPC is held responsible
+// for unauthorized messages, even if it is not their originator.
+type PublishMessageInspector struct {
+	component.Component
+	logger  zerolog.Logger
+	sporkID flow.Identifier
+	// config publish message inspection configuration.
+	config *p2pconf.GossipSubRPCPublishInspectorConfigs
+	// distributor used to disseminate invalid RPC message notifications.
+	distributor p2p.GossipSubInspectorNotifDistributor
+	idProvider  module.IdentityProvider
+	rateLimiter p2p.BasicRateLimiter
+}
+
+var _ p2p.GossipSubRPCInspector = (*PublishMessageInspector)(nil)
+
+// NewPublishMessageInspector returns a new PublishMessageInspector.
+// Args:
+//   - logger: the logger used by the inspector.
+//   - sporkID: the current spork ID.
+//   - config: inspector configuration.
+//   - distributor: gossipsub inspector notification distributor.
+//   - idProvider: identity provider is used to get the flow identity of the sender and originator of messages.
+//
+// Returns:
+//   - *PublishMessageInspector: a new publish message inspector.
+func NewPublishMessageInspector(
+	logger zerolog.Logger,
+	sporkID flow.Identifier,
+	config *p2pconf.GossipSubRPCPublishInspectorConfigs,
+	distributor p2p.GossipSubInspectorNotifDistributor,
+	idProvider module.IdentityProvider) *PublishMessageInspector {
+	lg := logger.With().Str("component", publishInspectorComponentName).Logger()
+	limiter := ratelimit.NewControlMessageRateLimiter(lg, rate.Limit(config.RateLimit), config.RateLimit)
+
+	p := &PublishMessageInspector{
+		logger:      lg,
+		sporkID:     sporkID,
+		config:      config,
+		distributor: distributor,
+		idProvider:  idProvider,
+		rateLimiter: limiter,
+	}
+
+	p.Component = component.NewComponentManagerBuilder().
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			ready()
+			limiter.Start(ctx)
+		}).
+		Build()
+	return p
+}
+
+// Inspect is called by gossipsub upon reception of an rpc from a remote node. It rate limits the messages published
+// within the rpc, and validates a random sample of them.
+// All returned errors are benign, and cause the rejection of the rpc by the gossipsub node:
+//   - ErrRateLimitedControlMsg: if the sender exceeds its rate of published messages.
+//   - ErrUnstakedPeer: if the sender is not a staked node.
+//   - channels.InvalidTopicErr: if a sampled message is published on an invalid topic.
+//   - ErrUnauthorizedPublish: if a sampled message is not authorized.
+func (p *PublishMessageInspector) Inspect(from peer.ID, rpc *pubsub.RPC) error {
+	messages := rpc.GetPublish()
+	count := len(messages)
+	if count == 0 {
+		return nil
+	}
+
+	err := p.inspect(from, messages)
+	if err == nil {
+		return nil
+	}
+
+	lg := p.logger.With().
+		Str("peer_id", from.String()).
+		Int("publish_msg_count", count).
+		Logger()
+	lg.Warn().
+		Err(err).
+		Bool(logging.KeySuspicious, true).
+		Msg("rpc publish message inspection failed, rejecting rpc")
+	disErr := p.distributor.Distribute(p2p.NewInvalidControlMessageNotification(from, p2pmsg.RpcPublishMessage, uint64(count), err))
+	if disErr != nil {
+		lg.Error().
+			Err(disErr).
+			Bool(logging.KeySuspicious, true).
+			Msg("failed to distribute invalid publish message notification")
+		return disErr
+	}
+	return err
+}
+
+// Name returns the name of the rpc inspector.
+func (p *PublishMessageInspector) Name() string {
+	return publishInspectorComponentName
+}
+
+// inspect checks the rate of published messages of the sender, and validates a random sample of the messages.
+// All returned errors are benign, see Inspect.
+func (p *PublishMessageInspector) inspect(from peer.ID, messages []*pubsub_pb.Message) error {
+	// an RPC consumes at most the burst of the rate limiter, as a larger number of messages could never be allowed.
+	n := len(messages)
+	if p.config.RateLimit > 0 && n > p.config.RateLimit {
+		n = p.config.RateLimit
+	}
+	if !p.rateLimiter.Allow(from, n) {
+		return NewRateLimitedControlMsgErr(p2pmsg.RpcPublishMessage)
+	}
+
+	sender, ok := p.idProvider.ByPeerID(from)
+	if !ok {
+		return NewUnstakedPeerErr(fmt.Errorf("failed to get flow identity for peer: %s", from))
+	}
+
+	sampleSize := util.SampleN(len(messages), p.config.MaxSampleSize, p.config.SampleSizePercentage)
+	// sample the indices rather than the messages, as the order of the published messages must not be altered.
+	indices := make([]int, len(messages))
+	for i := range indices {
+		indices[i] = i
+	}
+	err := flowrand.Samples(uint(len(indices)), sampleSize, func(i, j uint) {
+		indices[i], indices[j] = indices[j], indices[i]
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get random sample of published messages: %w", err)
+	}
+
+	for _, i := range indices[:sampleSize] {
+		err := p.validateMessage(sender, messages[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMessage validates the topic of a published message against the role of the sender of the RPC, and the message
+// itself against the role of its originator.
+// Expected error returns during normal operations:
+//   - channels.InvalidTopicErr: if the message is published on an invalid topic.
+//   - ErrUnauthorizedPublish: if the message is not authorized.
+func (p *PublishMessageInspector) validateMessage(sender *flow.Identity, msg *pubsub_pb.Message) error {
+	topic := channels.Topic(msg.GetTopic())
+	channel, ok := channels.ChannelFromTopic(topic)
+	if !ok {
+		return channels.NewInvalidTopicErr(topic, fmt.Errorf("failed to get channel from topic"))
+	}
+	if channels.IsPublicChannel(channel) {
+		// public channels are open to unstaked nodes, hence their messages are not subject to role based authorization.
+		return nil
+	}
+	if !channels.IsClusterChannel(channel) {
+		err := channels.IsValidNonClusterFlowTopic(topic, p.sporkID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the sender must be subscribed to the topic to forward its messages, hence its role must be allowed on the channel.
+	roles, ok := channels.RolesByChannel(channel)
+	if !ok || !roles.Contains(sender.Role) {
+		return NewUnauthorizedPublishErr(topic, fmt.Errorf("sender role %s is not allowed on channel %s", sender.Role, channel))
+	}
+
+	return p.validateOriginator(topic, channel, msg)
+}
+
+// validateOriginator ensures the originator of a published message is authorized to publish the message type on the
+// channel, as defined by the message authorization configs.
+// Expected error returns during normal operations:
+//   - ErrUnauthorizedPublish: if the message is not authorized.
+func (p *PublishMessageInspector) validateOriginator(topic channels.Topic, channel channels.Channel, msg *pubsub_pb.Message) error {
+	originPeerID, err := peer.IDFromBytes(msg.GetFrom())
+	if err != nil {
+		return NewUnauthorizedPublishErr(topic, fmt.Errorf("invalid originator: %w", err))
+	}
+	originator, ok := p.idProvider.ByPeerID(originPeerID)
+	if !ok {
+		return NewUnauthorizedPublishErr(topic, fmt.Errorf("unknown originator: %s", originPeerID))
+	}
+	if originator.Ejected {
+		return NewUnauthorizedPublishErr(topic, fmt.Errorf("ejected originator: %s", originator.NodeID))
+	}
+
+	var flowMsg message.Message
+	err = flowMsg.Unmarshal(msg.GetData())
+	if err != nil {
+		return NewUnauthorizedPublishErr(topic, fmt.Errorf("could not unmarshal message: %w", err))
+	}
+	// only the message code is needed for authorization, hence only the first byte of the payload is decompressed,
+	// which bounds the cost of the inspection independently of the size of the message.
+	prefix, err := compressor.DecompressPayloadPrefix(flowMsg.Payload, 1)
+	if err != nil {
+		return NewUnauthorizedPublishErr(topic, fmt.Errorf("could not decompress payload: %w", err))
+	}
+	msgCode, err := codec.MessageCodeFromPayload(prefix)
+	if err != nil {
+		return NewUnauthorizedPublishErr(topic, err)
+	}
+	msgInterface, what, err := codec.InterfaceFromMessageCode(msgCode)
+	if err != nil {
+		return NewUnauthorizedPublishErr(topic, err)
+	}
+	conf, err := message.GetMessageAuthConfig(msgInterface)
+	if err != nil {
+		return NewUnauthorizedPublishErr(topic, err)
+	}
+
+	// handle special case for cluster prefixed channels
+	if prefix, ok := channels.ClusterChannelPrefix(channel); ok {
+		channel = channels.Channel(prefix)
+	}
+	err = conf.EnsureAuthorized(originator.Role, channel, message.ProtocolTypePubSub)
+	if err != nil {
+		return NewUnauthorizedPublishErr(topic, fmt.Errorf("%s by %s originator: %w", what, originator.Role, err))
+	}
+	return nil
+}
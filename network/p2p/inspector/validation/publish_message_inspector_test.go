@@ -0,0 +1,183 @@
+package validation_test
+
+import (
+	"testing"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/channels"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/inspector/validation"
+	p2pmsg "github.com/onflow/flow-go/network/p2p/message"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/network/p2p/p2pconf"
+	p2ptest "github.com/onflow/flow-go/network/p2p/test"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestPublishMessageInspector evaluates that the messages published within an RPC are validated against the roles of
+// the sender of the RPC and of the originators of the messages, and that violations are reported to the distributor.
+func TestPublishMessageInspector(t *testing.T) {
+	sporkID := unittest.IdentifierFixture()
+	idProvider := mockmodule.NewIdentityProvider(t)
+	identity := func(role flow.Role) peer.ID {
+		pid := p2ptest.PeerIdFixture(t)
+		idProvider.On("ByPeerID", pid).Return(&flow.Identity{NodeID: unittest.IdentifierFixture(), Role: role}, true).Maybe()
+		return pid
+	}
+	consensus := identity(flow.RoleConsensus)
+	otherConsensus := identity(flow.RoleConsensus)
+	access := identity(flow.RoleAccess)
+	execution := identity(flow.RoleExecution)
+	unstaked := p2ptest.PeerIdFixture(t)
+	idProvider.On("ByPeerID", unstaked).Return(nil, false).Maybe()
+
+	proposal := unittest.ProposalFixture()
+	consensusTopic := channels.TopicFromChannel(channels.ConsensusCommittee, sporkID).String()
+
+	t.Run("authorized messages", func(t *testing.T) {
+		distributor := mockp2p.NewGossipSubInspectorNotifDistributor(t)
+		inspector := newPublishInspector(t, sporkID, 100, distributor, idProvider)
+
+		rpc := publishRPC(
+			publishMessage(t, otherConsensus, consensusTopic, channels.ConsensusCommittee, proposal),
+			publishMessage(t, consensus, consensusTopic, channels.ConsensusCommittee, proposal))
+		require.NoError(t, inspector.Inspect(consensus, rpc))
+	})
+
+	t.Run("sender not allowed on channel", func(t *testing.T) {
+		distributor := expectNotification(t, access)
+		inspector := newPublishInspector(t, sporkID, 100, distributor, idProvider)
+
+		rpc := publishRPC(publishMessage(t, consensus, consensusTopic, channels.ConsensusCommittee, proposal))
+		err := inspector.Inspect(access, rpc)
+		require.True(t, validation.IsErrUnauthorizedPublish(err))
+	})
+
+	t.Run("originator not authorized to publish message", func(t *testing.T) {
+		distributor := expectNotification(t, consensus)
+		inspector := newPublishInspector(t, sporkID, 100, distributor, idProvider)
+
+		rpc := publishRPC(publishMessage(t, execution, consensusTopic, channels.ConsensusCommittee, proposal))
+		err := inspector.Inspect(consensus, rpc)
+		require.True(t, validation.IsErrUnauthorizedPublish(err))
+		require.ErrorIs(t, err, message.ErrUnauthorizedRole)
+	})
+
+	t.Run("invalid topic", func(t *testing.T) {
+		distributor := expectNotification(t, consensus)
+		inspector := newPublishInspector(t, sporkID, 100, distributor, idProvider)
+
+		otherSporkTopic := channels.TopicFromChannel(channels.ConsensusCommittee, unittest.IdentifierFixture()).String()
+		rpc := publishRPC(publishMessage(t, consensus, otherSporkTopic, channels.ConsensusCommittee, proposal))
+		err := inspector.Inspect(consensus, rpc)
+		require.True(t, channels.IsInvalidTopicErr(err))
+	})
+
+	t.Run("unstaked sender", func(t *testing.T) {
+		distributor := expectNotification(t, unstaked)
+		inspector := newPublishInspector(t, sporkID, 100, distributor, idProvider)
+
+		rpc := publishRPC(publishMessage(t, consensus, consensusTopic, channels.ConsensusCommittee, proposal))
+		err := inspector.Inspect(unstaked, rpc)
+		require.True(t, validation.IsErrUnstakedPeer(err))
+	})
+
+	t.Run("rate limited sender", func(t *testing.T) {
+		distributor := expectNotification(t, consensus)
+		inspector := newPublishInspector(t, sporkID, 2, distributor, idProvider)
+
+		msg := publishMessage(t, consensus, consensusTopic, channels.ConsensusCommittee, proposal)
+		require.NoError(t, inspector.Inspect(consensus, publishRPC(msg, msg)))
+		err := inspector.Inspect(consensus, publishRPC(msg))
+		require.True(t, validation.IsErrRateLimitedControlMsg(err))
+	})
+
+	t.Run("rpc larger than rate limit burst", func(t *testing.T) {
+		distributor := expectNotification(t, consensus)
+		inspector := newPublishInspector(t, sporkID, 2, distributor, idProvider)
+
+		// an rpc with more messages than the burst is not rejected for its size, but uses up the burst
+		msg := publishMessage(t, consensus, consensusTopic, channels.ConsensusCommittee, proposal)
+		require.NoError(t, inspector.Inspect(consensus, publishRPC(msg, msg, msg, msg, msg)))
+		err := inspector.Inspect(consensus, publishRPC(msg))
+		require.True(t, validation.IsErrRateLimitedControlMsg(err))
+	})
+
+	t.Run("public channel", func(t *testing.T) {
+		distributor := mockp2p.NewGossipSubInspectorNotifDistributor(t)
+		inspector := newPublishInspector(t, sporkID, 100, distributor, idProvider)
+
+		// messages on public channels may be originated by unstaked nodes
+		publicTopic := channels.TopicFromChannel(channels.PublicSyncCommittee, sporkID).String()
+		rpc := publishRPC(publishMessage(t, unstaked, publicTopic, channels.PublicSyncCommittee, &messages.SyncRequest{Nonce: 1, Height: 10}))
+		require.NoError(t, inspector.Inspect(consensus, rpc))
+	})
+
+	t.Run("compressed message", func(t *testing.T) {
+		distributor := expectNotification(t, consensus)
+		inspector := newPublishInspector(t, sporkID, 100, distributor, idProvider)
+
+		msg := publishMessage(t, execution, consensusTopic, channels.ConsensusCommittee, proposal)
+		var flowMsg message.Message
+		require.NoError(t, flowMsg.Unmarshal(msg.Data))
+		compressed, err := compressor.CompressPayload(compressor.CodecZstd, flowMsg.Payload)
+		require.NoError(t, err)
+		flowMsg.Payload = compressed
+		msg.Data, err = flowMsg.Marshal()
+		require.NoError(t, err)
+
+		err = inspector.Inspect(consensus, publishRPC(msg))
+		require.ErrorIs(t, err, message.ErrUnauthorizedRole)
+	})
+}
+
+// newPublishInspector returns a publish message inspector inspecting all published messages of an RPC.
+func newPublishInspector(t *testing.T, sporkID flow.Identifier, rateLimit int, distributor p2p.GossipSubInspectorNotifDistributor, idProvider *mockmodule.IdentityProvider) *validation.PublishMessageInspector {
+	return validation.NewPublishMessageInspector(unittest.Logger(), sporkID, &p2pconf.GossipSubRPCPublishInspectorConfigs{
+		SampleSizePercentage: 1,
+		MaxSampleSize:        100,
+		RateLimit:            rateLimit,
+	}, distributor, idProvider)
+}
+
+// expectNotification returns a distributor expecting a single invalid publish message notification about the given peer.
+func expectNotification(t *testing.T, from peer.ID) *mockp2p.GossipSubInspectorNotifDistributor {
+	distributor := mockp2p.NewGossipSubInspectorNotifDistributor(t)
+	distributor.On("Distribute", mock.MatchedBy(func(notification *p2p.InvCtrlMsgNotif) bool {
+		return notification.PeerID == from && notification.MsgType == p2pmsg.RpcPublishMessage
+	})).Return(nil).Once()
+	return distributor
+}
+
+func publishRPC(msgs ...*pubsub_pb.Message) *pubsub.RPC {
+	return &pubsub.RPC{RPC: pubsub_pb.RPC{Publish: msgs}}
+}
+
+// publishMessage returns a pubsub message originated by the given peer, carrying the event on the given channel.
+func publishMessage(t *testing.T, originator peer.ID, topic string, channel channels.Channel, event interface{}) *pubsub_pb.Message {
+	payload, err := cborcodec.NewCodec().Encode(event)
+	require.NoError(t, err)
+	msg := &message.Message{
+		ChannelID: channel.String(),
+		Payload:   payload,
+	}
+	data, err := msg.Marshal()
+	require.NoError(t, err)
+
+	return &pubsub_pb.Message{
+		From:  []byte(originator),
+		Data:  data,
+		Topic: &topic,
+	}
+}
@@ -12,6 +12,9 @@ const (
 	CtrlMsgIWant ControlMessageType = "IWANT"
 	CtrlMsgGraft ControlMessageType = "GRAFT"
 	CtrlMsgPrune ControlMessageType = "PRUNE"
+	// RpcPublishMessage is not a control message, it is the type of misbehaviours on the messages published
+	// within an RPC, which are reported along with the control message misbehaviours.
+	RpcPublishMessage ControlMessageType = "RpcPublishMessage"
 )
 
 // ControlMessageTypes returns list of all libp2p control message types.
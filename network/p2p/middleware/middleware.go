@@ -23,15 +23,20 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/compressor"
 	"github.com/onflow/flow-go/network/internal/p2putils"
 	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/blob"
+	"github.com/onflow/flow-go/network/p2p/compressed"
 	"github.com/onflow/flow-go/network/p2p/p2pnode"
 	"github.com/onflow/flow-go/network/p2p/ping"
+	"github.com/onflow/flow-go/network/p2p/request"
 	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
 	"github.com/onflow/flow-go/network/p2p/unicast/ratelimit"
 	"github.com/onflow/flow-go/network/p2p/utils"
@@ -99,6 +104,10 @@ type Middleware struct {
 	slashingViolationsConsumer network.ViolationsConsumer
 	unicastRateLimiters        *ratelimit.RateLimiters
 	authorizedSenderValidator  *validator.AuthorizedSenderValidator
+	channelCompression         map[channels.Channel]compressor.Codec
+	pubSubCompression          bool
+	compressionMetrics         module.NetworkCompressionMetrics
+	capturer                   *capture.Capturer
 }
 
 type OptionFn func(*Middleware)
@@ -115,6 +124,33 @@ func WithPreferredUnicastProtocols(unicasts []protocols.ProtocolName) OptionFn {
 	}
 }
 
+// WithChannelCompression sets the compression codec of each channel, and the metrics collector tracking the bytes
+// saved by compression. Cluster channels are configured by their cluster channel prefix. Channels without a codec are
+// not compressed, unless a node-wide preferred unicast protocol compresses unicast streams.
+func WithChannelCompression(policies map[channels.Channel]compressor.Codec, metrics module.NetworkCompressionMetrics) OptionFn {
+	return func(mw *Middleware) {
+		mw.channelCompression = policies
+		mw.compressionMetrics = metrics
+	}
+}
+
+// WithInboundCapture sets the capturer which records the messages received by the middleware on its captured
+// channels, before they are passed on to the overlay.
+func WithInboundCapture(capturer *capture.Capturer) OptionFn {
+	return func(mw *Middleware) {
+		mw.capturer = capturer
+	}
+}
+
+// WithPubSubCompression enables the compression of pubsub messages with the codec of their channel. By default, only
+// unicast messages are compressed: pubsub messages are relayed by peers regardless of whether the receivers support the
+// codec, so pubsub compression must only be enabled when all nodes of the network support the codecs, i.e., at a spork.
+func WithPubSubCompression(enabled bool) OptionFn {
+	return func(mw *Middleware) {
+		mw.pubSubCompression = enabled
+	}
+}
+
 // WithPeerManagerFilters sets a list of p2p.PeerFilter funcs that are used to
 // filter out peers provided by the peer manager PeersProvider.
 func WithPeerManagerFilters(peerManagerFilters []p2p.PeerFilter) OptionFn {
@@ -172,6 +208,8 @@ func NewMiddleware(cfg *Config, opts ...OptionFn) *Middleware {
 		idTranslator:          cfg.IdTranslator,
 		codec:                 cfg.Codec,
 		unicastRateLimiters:   ratelimit.NoopRateLimiters(),
+		channelCompression:    make(map[channels.Channel]compressor.Codec),
+		compressionMetrics:    metrics.NewNoopCollector(),
 	}
 
 	for _, opt := range opts {
@@ -247,6 +285,40 @@ func (m *Middleware) NewPingService(pingProtocol protocol.ID, provider network.P
 	return ping.NewPingService(m.libP2PNode.Host(), pingProtocol, m.log, provider)
 }
 
+func (m *Middleware) NewRequestService(channel channels.Channel, handler network.RequestHandler, opts ...network.RequestServiceOption) network.RequestService {
+	return request.NewRequestService(
+		m.libP2PNode.Host(),
+		protocols.RequestProtocolId(m.rootBlockID, channel),
+		channel,
+		m.codec,
+		m.idTranslator,
+		m.authorizeRequestMessage(channel),
+		m.slashingViolationsConsumer,
+		handler,
+		m.log,
+		opts...)
+}
+
+// authorizeRequestMessage returns a request.Authorizer which authorizes the requests and responses of the request
+// service on the given channel as unicast messages on the channel.
+func (m *Middleware) authorizeRequestMessage(channel channels.Channel) request.Authorizer {
+	return func(from peer.ID, payload []byte) (*flow.Identity, error) {
+		if m.authorizedSenderValidator == nil {
+			return nil, fmt.Errorf("middleware has not been started")
+		}
+		// the validator reports unauthorized messages to the slashing violations consumer.
+		_, err := m.authorizedSenderValidator.Validate(from, payload, channel, message.ProtocolTypeUnicast)
+		if err != nil {
+			return nil, fmt.Errorf("unauthorized message from peer %s: %w", from, err)
+		}
+		identity, ok := m.ov.Identity(from)
+		if !ok {
+			return nil, fmt.Errorf("could not get identity of peer %s", from)
+		}
+		return identity, nil
+	}
+}
+
 func (m *Middleware) peerIDs(flowIDs flow.IdentifierList) peer.IDSlice {
 	result := make([]peer.ID, 0, len(flowIDs))
 
@@ -291,6 +363,9 @@ func (m *Middleware) UpdateNodeAddresses() {
 }
 
 func (m *Middleware) SetOverlay(ov network.Overlay) {
+	if m.capturer != nil {
+		ov = capture.NewOverlay(ov, m.capturer)
+	}
 	m.ov = ov
 }
 
@@ -386,6 +461,12 @@ func (m *Middleware) SendDirect(msg *network.OutgoingMessageScope) error {
 	m.libP2PNode.Host().ConnManager().Protect(peerID, tag)
 	defer m.libP2PNode.Host().ConnManager().Unprotect(peerID, tag)
 
+	// negotiate the compression codec of the channel with the remote peer, by preferring the unicast protocol
+	// of the codec. If the remote peer does not support it, stream creation falls back to the other protocols.
+	if name, ok := protocols.CompressionUnicast(m.compressionCodec(msg.Channel())); ok {
+		ctx = protocols.WithPreferredProtocol(ctx, name)
+	}
+
 	// create new stream
 	// streams don't need to be reused and are fairly inexpensive to be created for each send.
 	// A stream creation does NOT incur an RTT as stream negotiation happens as part of the first message
@@ -404,6 +485,7 @@ func (m *Middleware) SendDirect(msg *network.OutgoingMessageScope) error {
 			if err != nil {
 				err = fmt.Errorf("failed to close the stream for %s: %w", msg.TargetIds()[0], err)
 			}
+			m.reportUnicastCompression(msg.Channel(), stream)
 		} else {
 			resetErr := stream.Reset()
 			if resetErr != nil {
@@ -438,6 +520,30 @@ func (m *Middleware) SendDirect(msg *network.OutgoingMessageScope) error {
 	return nil
 }
 
+// compressionCodec returns the compression codec of the given channel. Cluster channels use the codec of their
+// cluster channel prefix.
+func (m *Middleware) compressionCodec(channel channels.Channel) compressor.Codec {
+	if codec, ok := m.channelCompression[channel]; ok {
+		return codec
+	}
+	if prefix, ok := channels.ClusterChannelPrefix(channel); ok {
+		if codec, ok := m.channelCompression[channels.Channel(prefix)]; ok {
+			return codec
+		}
+	}
+	return compressor.CodecNone
+}
+
+// reportUnicastCompression reports the bytes saved by compressing the given stream, if it was compressed.
+func (m *Middleware) reportUnicastCompression(channel channels.Channel, s libp2pnetwork.Stream) {
+	counter, ok := s.(compressed.ByteCounter)
+	if !ok {
+		return
+	}
+	uncompressed, compressedSize := counter.WrittenBytes()
+	m.compressionMetrics.OnMessageCompressed(channel.String(), protocols.StreamCompression(s), int(uncompressed), int(compressedSize))
+}
+
 // handleIncomingStream handles an incoming stream from a remote peer
 // it is a callback that gets called for each incoming stream by libp2p with a new stream object
 func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
@@ -796,6 +902,11 @@ func (m *Middleware) Publish(msg *network.OutgoingMessageScope) error {
 
 	topic := channels.TopicFromChannel(msg.Channel(), m.rootBlockID)
 
+	data, err = m.compressPubSubMessage(msg, topic, data)
+	if err != nil {
+		return fmt.Errorf("failed to compress the message: %w", err)
+	}
+
 	// publish the bytes on the topic
 	err = m.libP2PNode.Publish(m.ctx, topic, data)
 	if err != nil {
@@ -805,6 +916,60 @@ func (m *Middleware) Publish(msg *network.OutgoingMessageScope) error {
 	return nil
 }
 
+// compressPubSubMessage compresses the payload of a message published on the given topic with the compression codec of
+// its channel, and returns the message bytes to be put on the wire. The plain message bytes are returned if pubsub
+// compression is disabled, the channel is not compressed, compression does not save any bytes, or not all peers of the
+// topic support the codec.
+//
+// Pubsub messages are not sent on streams of their own, hence the codec cannot be negotiated per message, and peers
+// relay the message to their own peers regardless of whether those support the codec. Hence, pubsub compression is
+// gated on a network-wide configuration (see WithPubSubCompression), which must only be enabled when all nodes support
+// the codecs. The unicast protocol ids advertised by the peers of the topic are checked in addition, as a safeguard
+// against peers without support of the codec.
+//
+// No errors are expected during normal operations.
+func (m *Middleware) compressPubSubMessage(msg *network.OutgoingMessageScope, topic channels.Topic, data []byte) ([]byte, error) {
+	if !m.pubSubCompression {
+		return data, nil
+	}
+	codec := m.compressionCodec(msg.Channel())
+	if codec == compressor.CodecNone {
+		return data, nil
+	}
+
+	peers := m.libP2PNode.ListPeers(topic.String())
+	if len(peers) == 0 {
+		return data, nil
+	}
+	for _, p := range peers {
+		peerProtocols, err := m.libP2PNode.Host().Peerstore().GetProtocols(p)
+		if err != nil || !protocols.SupportsCompression(peerProtocols, codec) {
+			return data, nil
+		}
+	}
+
+	payload, err := compressor.CompressPayload(codec, msg.Proto().Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not compress payload with codec %s: %w", codec, err)
+	}
+	compressedMsg := &message.Message{
+		ChannelID: msg.Proto().ChannelID,
+		TargetIDs: msg.Proto().TargetIDs,
+		Payload:   payload,
+	}
+	compressedData, err := compressedMsg.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the compressed message: %w", err)
+	}
+
+	if len(compressedData) >= len(data) {
+		return data, nil
+	}
+
+	m.compressionMetrics.OnMessageCompressed(msg.Channel().String(), codec.String(), len(data), len(compressedData))
+	return compressedData, nil
+}
+
 // IsConnected returns true if this node is connected to the node with id nodeID.
 // All errors returned from this function can be considered benign.
 func (m *Middleware) IsConnected(nodeID flow.Identifier) (bool, error) {
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mockp2p
+
+import (
+	p2p "github.com/onflow/flow-go/network/p2p"
+	mock "github.com/stretchr/testify/mock"
+
+	peer "github.com/libp2p/go-libp2p/core/peer"
+)
+
+// AppSpecificScoreExplainer is an autogenerated mock type for the AppSpecificScoreExplainer type
+type AppSpecificScoreExplainer struct {
+	mock.Mock
+}
+
+// AppSpecificScoreComponents provides a mock function with given fields: peerID
+func (_m *AppSpecificScoreExplainer) AppSpecificScoreComponents(peerID peer.ID) (p2p.AppSpecificScoreComponents, error) {
+	ret := _m.Called(peerID)
+
+	var r0 p2p.AppSpecificScoreComponents
+	var r1 error
+	if rf, ok := ret.Get(0).(func(peer.ID) (p2p.AppSpecificScoreComponents, error)); ok {
+		return rf(peerID)
+	}
+	if rf, ok := ret.Get(0).(func(peer.ID) p2p.AppSpecificScoreComponents); ok {
+		r0 = rf(peerID)
+	} else {
+		r0 = ret.Get(0).(p2p.AppSpecificScoreComponents)
+	}
+
+	if rf, ok := ret.Get(1).(func(peer.ID) error); ok {
+		r1 = rf(peerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAppSpecificScoreExplainer interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAppSpecificScoreExplainer creates a new instance of AppSpecificScoreExplainer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAppSpecificScoreExplainer(t mockConstructorTestingTNewAppSpecificScoreExplainer) *AppSpecificScoreExplainer {
+	mock := &AppSpecificScoreExplainer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -10,6 +10,8 @@ import (
 
 	p2p "github.com/onflow/flow-go/network/p2p"
 
+	p2pconf "github.com/onflow/flow-go/network/p2p/p2pconf"
+
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 
 	routing "github.com/libp2p/go-libp2p/core/routing"
@@ -68,6 +70,11 @@ func (_m *GossipSubBuilder) SetGossipSubFactory(_a0 p2p.GossipSubFactoryFunc) {
 	_m.Called(_a0)
 }
 
+// SetGossipSubScoreHistory provides a mock function with given fields: _a0
+func (_m *GossipSubBuilder) SetGossipSubScoreHistory(_a0 *p2pconf.GossipSubScoreHistoryConfig) {
+	_m.Called(_a0)
+}
+
 // SetGossipSubScoreTracerInterval provides a mock function with given fields: _a0
 func (_m *GossipSubBuilder) SetGossipSubScoreTracerInterval(_a0 time.Duration) {
 	_m.Called(_a0)
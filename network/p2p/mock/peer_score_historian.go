@@ -0,0 +1,88 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mockp2p
+
+import (
+	alsp "github.com/onflow/flow-go/network/alsp"
+	mock "github.com/stretchr/testify/mock"
+
+	p2p "github.com/onflow/flow-go/network/p2p"
+
+	peer "github.com/libp2p/go-libp2p/core/peer"
+)
+
+// PeerScoreHistorian is an autogenerated mock type for the PeerScoreHistorian type
+type PeerScoreHistorian struct {
+	mock.Mock
+}
+
+// PeerScoreHistories provides a mock function with given fields:
+func (_m *PeerScoreHistorian) PeerScoreHistories() map[peer.ID][]p2p.PeerScoreHistoryEntry {
+	ret := _m.Called()
+
+	var r0 map[peer.ID][]p2p.PeerScoreHistoryEntry
+	if rf, ok := ret.Get(0).(func() map[peer.ID][]p2p.PeerScoreHistoryEntry); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[peer.ID][]p2p.PeerScoreHistoryEntry)
+		}
+	}
+
+	return r0
+}
+
+// PeerScoreHistory provides a mock function with given fields: peerID
+func (_m *PeerScoreHistorian) PeerScoreHistory(peerID peer.ID) ([]p2p.PeerScoreHistoryEntry, bool) {
+	ret := _m.Called(peerID)
+
+	var r0 []p2p.PeerScoreHistoryEntry
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(peer.ID) ([]p2p.PeerScoreHistoryEntry, bool)); ok {
+		return rf(peerID)
+	}
+	if rf, ok := ret.Get(0).(func(peer.ID) []p2p.PeerScoreHistoryEntry); ok {
+		r0 = rf(peerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]p2p.PeerScoreHistoryEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(peer.ID) bool); ok {
+		r1 = rf(peerID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// RegisterAlspSpamRecords provides a mock function with given fields: records
+func (_m *PeerScoreHistorian) RegisterAlspSpamRecords(records alsp.SpamRecordAdmin) error {
+	ret := _m.Called(records)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(alsp.SpamRecordAdmin) error); ok {
+		r0 = rf(records)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPeerScoreHistorian interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPeerScoreHistorian creates a new instance of PeerScoreHistorian. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPeerScoreHistorian(t mockConstructorTestingTNewPeerScoreHistorian) *PeerScoreHistorian {
+	mock := &PeerScoreHistorian{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	alspmgr "github.com/onflow/flow-go/network/alsp/manager"
 	netcache "github.com/onflow/flow-go/network/cache"
 	"github.com/onflow/flow-go/network/channels"
@@ -55,6 +56,9 @@ type Network struct {
 	registerBlobServiceRequests chan *registerBlobServiceRequest
 	misbehaviorReportManager    network.MisbehaviorReportManager
 	slashingViolationsConsumer  network.ViolationsConsumer
+	inboundQueueCfg             *queue.FairMessageQueueConfig
+	inboundQueueOverflows       *queue.OverflowAggregator     // reports inbound queue overflows once per origin and interval
+	requestHandlers             map[channels.Channel]struct{} // channels with a registered request handler
 }
 
 var _ network.Network = &Network{}
@@ -99,6 +103,8 @@ type NetworkConfig struct {
 	ReceiveCache        *netcache.ReceiveCache
 	ConduitFactory      network.ConduitFactory
 	AlspCfg             *alspmgr.MisbehaviorReportManagerConfig
+	// InboundQueueCfg is the configuration of the inbound message queue, the default configuration is used when nil.
+	InboundQueueCfg *queue.FairMessageQueueConfig
 }
 
 // NetworkConfigOption is a function that can be used to override network config parmeters.
@@ -167,7 +173,13 @@ func NewNetwork(param *NetworkConfig, opts ...NetworkOption) (*Network, error) {
 		registerEngineRequests:      make(chan *registerEngineRequest),
 		registerBlobServiceRequests: make(chan *registerBlobServiceRequest),
 		misbehaviorReportManager:    misbehaviorMngr,
+		inboundQueueCfg:             param.InboundQueueCfg,
+		requestHandlers:             make(map[channels.Channel]struct{}),
 	}
+	if n.inboundQueueCfg == nil {
+		n.inboundQueueCfg = queue.DefaultFairMessageQueueConfig()
+	}
+	n.inboundQueueOverflows = queue.NewOverflowAggregator(n.reportInboundQueueOverflow)
 
 	for _, opt := range opts {
 		opt(n)
@@ -200,6 +212,10 @@ func NewNetwork(param *NetworkConfig, opts ...NetworkOption) (*Network, error) {
 			n.logger.Debug().Msg("misbehavior manager stopped")
 		}).
 		AddWorker(n.runMiddleware).
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			ready()
+			n.inboundQueueOverflows.Run(ctx, queue.DefaultOverflowReportInterval)
+		}).
 		AddWorker(n.processRegisterEngineRequests).
 		AddWorker(n.processRegisterBlobServiceRequests).Build()
 
@@ -256,8 +272,13 @@ func (n *Network) processRegisterBlobServiceRequests(parent irrecoverable.Signal
 
 func (n *Network) runMiddleware(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
 	// setup the message queue
-	// create priority queue
-	n.queue = queue.NewMessageQueue(ctx, queue.GetEventPriority, n.metrics)
+	// create priority queue which is fair across channels and origins
+	mq, err := queue.NewFairMessageQueue(ctx, queue.GetEventPriority, n.metrics, n.inboundQueueCfg, n.inboundQueueOverflows.OnOverflow)
+	if err != nil {
+		ctx.Throw(fmt.Errorf("could not create inbound message queue: %w", err))
+		return
+	}
+	n.queue = mq
 
 	// create workers to read from the queue and call queueSubmitFunc
 	queue.CreateQueueWorkers(ctx, queue.DefaultNumWorkers, n.queue, n.queueSubmitFunc)
@@ -334,6 +355,28 @@ func (n *Network) RegisterPingService(pingProtocol protocol.ID, provider network
 	}
 }
 
+// RegisterRequestService registers a request service on the given channel, handling the inbound requests with the
+// given handler if it is not nil.
+// Returns an error if the network has shut down, or another handler is already registered on the channel.
+func (n *Network) RegisterRequestService(channel channels.Channel, handler network.RequestHandler, opts ...network.RequestServiceOption) (network.RequestService, error) {
+	select {
+	case <-n.ComponentManager.ShutdownSignal():
+		return nil, ErrNetworkShutdown
+	default:
+	}
+
+	if handler != nil {
+		n.Lock()
+		defer n.Unlock()
+
+		if _, ok := n.requestHandlers[channel]; ok {
+			return nil, fmt.Errorf("request handler already registered on channel %s", channel)
+		}
+		n.requestHandlers[channel] = struct{}{}
+	}
+	return n.mw.NewRequestService(channel, handler, opts...), nil
+}
+
 // RegisterBlobService registers a BlobService on the given channel.
 // The returned BlobService can be used to request blobs from the network.
 func (n *Network) RegisterBlobService(channel channels.Channel, ds datastore.Batching, opts ...network.BlobServiceOption) (network.BlobService, error) {
@@ -523,6 +566,27 @@ func (n *Network) sendOnChannel(channel channels.Channel, msg interface{}, targe
 	return nil
 }
 
+// reportInboundQueueOverflow reports the origin of overflows of the inbound queue buffer of the given channel to
+// the application layer spam prevention (ALSP) protocol, as its messages take an unreasonable share of the
+// resources of the node. Overflows are aggregated per origin and channel, so that a flooding origin is reported
+// once per report interval regardless of the number of its dropped messages.
+func (n *Network) reportInboundQueueOverflow(channel channels.Channel, originID flow.Identifier, overflows uint64) {
+	n.logger.Warn().
+		Str("channel", channel.String()).
+		Hex("origin_id", logging.ID(originID)).
+		Uint64("overflows", overflows).
+		Bool(logging.KeySuspicious, true).
+		Msg("inbound queue buffer of channel overflown, dropped messages")
+
+	report, err := alsp.NewMisbehaviorReport(originID, alsp.ResourceIntensiveRequest)
+	if err != nil {
+		// failing to create the report is a bug, but dropping the report does not harm the safety of the node
+		n.logger.Error().Err(err).Hex("origin_id", logging.ID(originID)).Msg("could not create misbehavior report for inbound queue overflow")
+		return
+	}
+	n.ReportMisbehaviorOnChannel(channel, report)
+}
+
 // queueSubmitFunc submits the message to the engine synchronously. It is the callback for the queue worker
 // when it gets a message from the queue
 func (n *Network) queueSubmitFunc(message interface{}) {
@@ -562,6 +626,12 @@ func (n *Network) Topology() flow.IdentityList {
 	return n.topology.Fanout(n.Identities())
 }
 
+// MisbehaviorReportManager returns the misbehavior report manager of the network, which applies the penalties of the
+// application layer spam prevention (ALSP) protocol.
+func (n *Network) MisbehaviorReportManager() network.MisbehaviorReportManager {
+	return n.misbehaviorReportManager
+}
+
 // ReportMisbehaviorOnChannel reports the misbehavior of a node on sending a message to the current node that appears
 // valid based on the networking layer but is considered invalid by the current node based on the Flow protocol.
 // The misbehavior report is sent to the current node's networking layer on the given channel to be processed.
@@ -42,6 +42,8 @@ type Builder struct {
 	gossipSubConfigFunc          p2p.GossipSubAdapterConfigFunc
 	gossipSubPeerScoring         bool          // whether to enable gossipsub peer scoring
 	gossipSubScoreTracerInterval time.Duration // the interval at which the gossipsub score tracer logs the peer scores.
+	// gossipSubScoreHistoryCfg is the configuration of the peer score history of the score tracer, nil if disabled.
+	gossipSubScoreHistoryCfg *p2pconf.GossipSubScoreHistoryConfig
 	// gossipSubTracer is a callback interface that is called by the gossipsub implementation upon
 	// certain events. Currently, we use it to log and observe the local mesh of the node.
 	gossipSubTracer          p2p.PubSubTracer
@@ -139,6 +141,16 @@ func (g *Builder) SetGossipSubScoreTracerInterval(gossipSubScoreTracerInterval t
 	g.gossipSubScoreTracerInterval = gossipSubScoreTracerInterval
 }
 
+// SetGossipSubScoreHistory enables the peer score history of the gossipsub score tracer with the given configuration.
+// If the gossipsub score history has already been set, a fatal error is logged.
+func (g *Builder) SetGossipSubScoreHistory(cfg *p2pconf.GossipSubScoreHistoryConfig) {
+	if g.gossipSubScoreHistoryCfg != nil {
+		g.logger.Fatal().Msg("gossipsub score history has already been set")
+		return
+	}
+	g.gossipSubScoreHistoryCfg = cfg
+}
+
 // SetGossipSubTracer sets the gossipsub tracer of the builder.
 // If the gossipsub tracer has already been set, a fatal error is logged.
 func (g *Builder) SetGossipSubTracer(gossipSubTracer p2p.PubSubTracer) {
@@ -269,7 +281,19 @@ func defaultInspectorSuite() p2p.GossipSubRpcInspectorSuiteFactoryFunc {
 			return nil, fmt.Errorf("failed to create new control message valiadation inspector: %w", err)
 		}
 
-		return inspectorbuilder.NewGossipSubInspectorSuite([]p2p.GossipSubRPCInspector{metricsInspector, rpcValidationInspector}, notificationDistributor), nil
+		inspectors := []p2p.GossipSubRPCInspector{metricsInspector, rpcValidationInspector}
+		// the publish message inspector authorizes messages by the roles of staked nodes, hence it is only installed
+		// on the private network, where all peers are staked.
+		if networkType == network.PrivateNetwork {
+			inspectors = append(inspectors, validation.NewPublishMessageInspector(
+				logger,
+				sporkId,
+				&inspectorCfg.GossipSubRPCPublishInspectorConfigs,
+				notificationDistributor,
+				idProvider))
+		}
+
+		return inspectorbuilder.NewGossipSubInspectorSuite(inspectors, notificationDistributor), nil
 	}
 }
 
@@ -319,11 +343,21 @@ func (g *Builder) Build(ctx irrecoverable.SignalerContext) (p2p.PubSubAdapter, e
 		gossipSubConfigs.WithScoreOption(scoreOpt)
 
 		if g.gossipSubScoreTracerInterval > 0 {
+			var opts []tracer.GossipSubScoreTracerOption
+			if g.gossipSubScoreHistoryCfg != nil {
+				history := tracer.NewGossipSubScoreHistory(
+					g.logger,
+					g.gossipSubScoreHistoryCfg,
+					g.idProvider,
+					scoreOpt.AppSpecificScoreExplainer())
+				opts = append(opts, tracer.WithScoreHistory(history))
+			}
 			scoreTracer = tracer.NewGossipSubScoreTracer(
 				g.logger,
 				g.idProvider,
 				g.metricsCfg.Metrics,
-				g.gossipSubScoreTracerInterval)
+				g.gossipSubScoreTracerInterval,
+				opts...)
 			gossipSubConfigs.WithScoreTracer(scoreTracer)
 		}
 
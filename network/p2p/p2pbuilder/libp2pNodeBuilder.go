@@ -189,6 +189,11 @@ func (builder *LibP2PNodeBuilder) SetGossipSubScoreTracerInterval(interval time.
 	return builder
 }
 
+func (builder *LibP2PNodeBuilder) SetGossipSubScoreHistory(cfg *p2pconf.GossipSubScoreHistoryConfig) p2p.NodeBuilder {
+	builder.gossipSubBuilder.SetGossipSubScoreHistory(cfg)
+	return builder
+}
+
 func (builder *LibP2PNodeBuilder) OverrideDefaultRpcInspectorSuiteFactory(factory p2p.GossipSubRpcInspectorSuiteFactoryFunc) p2p.NodeBuilder {
 	builder.gossipSubBuilder.OverrideDefaultRpcInspectorSuiteFactory(factory)
 	return builder
@@ -504,6 +509,9 @@ func DefaultNodeBuilder(
 
 	builder.SetGossipSubTracer(meshTracer)
 	builder.SetGossipSubScoreTracerInterval(gossipCfg.ScoreTracerInterval)
+	if gossipCfg.ScoreHistorySize > 0 {
+		builder.SetGossipSubScoreHistory(&gossipCfg.GossipSubScoreHistoryConfig)
+	}
 
 	if role != "ghost" {
 		r, _ := flow.ParseRole(role)
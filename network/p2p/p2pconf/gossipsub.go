@@ -31,6 +31,8 @@ type GossipSubTracerConfig struct {
 	LocalMeshLogInterval time.Duration `validate:"gt=0s" mapstructure:"gossipsub-local-mesh-logging-interval"`
 	// ScoreTracerInterval is the interval at which the score tracer logs the peer scores.
 	ScoreTracerInterval time.Duration `validate:"gt=0s" mapstructure:"gossipsub-score-tracer-interval"`
+	// GossipSubScoreHistoryConfig is the configuration of the peer score history kept by the score tracer.
+	GossipSubScoreHistoryConfig `mapstructure:",squash"`
 	// RPCSentTrackerCacheSize cache size of the rpc sent tracker used by the gossipsub mesh tracer.
 	RPCSentTrackerCacheSize uint32 `validate:"gt=0" mapstructure:"gossipsub-rpc-sent-tracker-cache-size"`
 	// RPCSentTrackerQueueCacheSize cache size of the rpc sent tracker queue used for async tracking.
@@ -38,3 +40,12 @@ type GossipSubTracerConfig struct {
 	// RpcSentTrackerNumOfWorkers number of workers for rpc sent tracker worker pool.
 	RpcSentTrackerNumOfWorkers int `validate:"gt=0" mapstructure:"gossipsub-rpc-sent-tracker-workers"`
 }
+
+// GossipSubScoreHistoryConfig is the config of the peer score history, which retains the latest peer score snapshots
+// of the score tracer for each peer.
+type GossipSubScoreHistoryConfig struct {
+	// ScoreHistorySize is the number of score snapshots retained per peer, 0 disables the score history.
+	ScoreHistorySize uint32 `mapstructure:"gossipsub-score-history-size"`
+	// ScoreHistoryMaxPeers is the maximum number of peers the score history is retained for.
+	ScoreHistoryMaxPeers uint32 `validate:"gt=0" mapstructure:"gossipsub-score-history-max-peers"`
+}
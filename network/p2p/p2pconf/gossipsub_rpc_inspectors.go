@@ -10,6 +10,8 @@ type GossipSubRPCInspectorsConfig struct {
 	GossipSubRPCValidationInspectorConfigs `mapstructure:",squash"`
 	// GossipSubRPCMetricsInspectorConfigs control message metrics inspector configuration.
 	GossipSubRPCMetricsInspectorConfigs `mapstructure:",squash"`
+	// GossipSubRPCPublishInspectorConfigs publish message inspector configuration.
+	GossipSubRPCPublishInspectorConfigs `mapstructure:",squash"`
 	// GossipSubRPCInspectorNotificationCacheSize size of the queue for notifications about invalid RPC messages.
 	GossipSubRPCInspectorNotificationCacheSize uint32 `mapstructure:"gossipsub-rpc-inspector-notification-cache-size"`
 }
@@ -136,3 +138,13 @@ type GossipSubRPCMetricsInspectorConfigs struct {
 	// CacheSize size of the queue used by worker pool for the control message metrics inspector.
 	CacheSize uint32 `validate:"gt=0" mapstructure:"gossipsub-rpc-metrics-inspector-cache-size"`
 }
+
+// GossipSubRPCPublishInspectorConfigs configuration of the inspection of the messages published within RPCs.
+type GossipSubRPCPublishInspectorConfigs struct {
+	// SampleSizePercentage the percentage of the published messages of an RPC to inspect in float64 form.
+	SampleSizePercentage float64 `validate:"gt=0,lte=1" mapstructure:"gossipsub-rpc-publish-inspection-sample-size-percentage"`
+	// MaxSampleSize the max number of the published messages of an RPC to inspect.
+	MaxSampleSize float64 `validate:"gte=1" mapstructure:"gossipsub-rpc-publish-max-sample-size"`
+	// RateLimit number of messages per second a peer is allowed to publish, use 0 to disable rate limiting.
+	RateLimit int `validate:"gte=0" mapstructure:"gossipsub-rpc-publish-rate-limit"`
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/collection"
 	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/network/alsp"
 )
 
 type ValidationResult int
@@ -278,3 +279,60 @@ type PeerScoreExposer interface {
 	// The returned map is keyed by topic name.
 	GetTopicScores(peerID peer.ID) (map[string]TopicScoreSnapshot, bool)
 }
+
+// AppSpecificScoreComponents is the breakdown of the application specific score of a peer into the penalties and
+// rewards applied by the Flow protocol.
+type AppSpecificScoreComponents struct {
+	// SpamPenalty (decayed) penalty for control message misbehaviours of the peer.
+	SpamPenalty float64
+	// StakingScore reward of staked peers, or penalty of access nodes and peers without a valid identity. The reward
+	// only makes up the application specific score if the peer has no other penalty.
+	StakingScore float64
+	// SubscriptionPenalty penalty for subscriptions of the peer to topics its role is not allowed to subscribe to.
+	SubscriptionPenalty float64
+}
+
+// AppSpecificScoreExplainer explains the application specific score of peers by its components.
+type AppSpecificScoreExplainer interface {
+	// AppSpecificScoreComponents returns the components of the current application specific score of the given peer.
+	// Any returned error is unexpected and indicates a failure of the underlying spam record cache.
+	AppSpecificScoreComponents(peerID peer.ID) (AppSpecificScoreComponents, error)
+}
+
+// PeerScoreHistoryEntry is the score of a peer at a given time, broken down into the components it is made of.
+type PeerScoreHistoryEntry struct {
+	// Timestamp time the score snapshot was taken.
+	Timestamp time.Time
+	// Score the overall score of the peer.
+	Score float64
+	// Topics the topic score components of the peer, keyed by topic name.
+	Topics map[string]TopicScoreSnapshot
+	// AppSpecificScore application specific score (set by Flow protocol).
+	AppSpecificScore float64
+	// AppSpecificScoreComponents components of the application specific score, nil if they are not available.
+	AppSpecificScoreComponents *AppSpecificScoreComponents
+	// IPColocationFactor IP colocation factor of the peer.
+	IPColocationFactor float64
+	// BehaviourPenalty behaviour penalty of the peer.
+	BehaviourPenalty float64
+	// AlspPenalty penalty applied to the peer by the application layer spam prevention (ALSP) protocol.
+	AlspPenalty float64
+	// AlspDisallowListed whether the peer is disallow-listed by the ALSP protocol.
+	AlspDisallowListed bool
+}
+
+// PeerScoreHistorian keeps a bounded history of the scores of peers, so that operators can explain score changes of
+// peers, e.g., during production incidents.
+type PeerScoreHistorian interface {
+	// PeerScoreHistory returns a copy of the score history of the given peer, ordered from the oldest to the newest entry.
+	// Returns the history and true if the peer has a history, nil and false otherwise.
+	PeerScoreHistory(peerID peer.ID) ([]PeerScoreHistoryEntry, bool)
+
+	// PeerScoreHistories returns a copy of the score histories of all peers.
+	PeerScoreHistories() map[peer.ID][]PeerScoreHistoryEntry
+
+	// RegisterAlspSpamRecords registers the spam records of the ALSP protocol, whose penalties are recorded in the
+	// score history from then on. The spam records can only be registered once.
+	// Returns an error if spam records are already registered, which is a benign error.
+	RegisterAlspSpamRecords(records alsp.SpamRecordAdmin) error
+}
@@ -0,0 +1,416 @@
+package request
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/multiformats/go-multistream"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	fnetwork "github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/internal/p2putils"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// status of a response, written as the first byte of the response.
+const (
+	statusOK byte = iota
+	// statusFailed indicates that the handler of the request returned an error, the payload is the error message.
+	statusFailed
+	// statusRejected indicates that the request was not handled, the payload is the reason.
+	statusRejected
+)
+
+// maxReasonSize is the maximum size of the error message of a failed or rejected request.
+const maxReasonSize = 1024
+
+// Authorizer authorizes the encoded message received from the given peer, and returns the flow identity of the peer.
+// Any returned error indicates that the message is not authorized, and is considered benign. The authorizer is
+// expected to report unauthorized messages as violations itself.
+type Authorizer func(from peer.ID, payload []byte) (*flow.Identity, error)
+
+// Service is a request service carrying requests and responses over dedicated libp2p streams. Each request is sent on
+// its own stream: the requester writes the length-prefixed encoded request and keeps the stream open until it reads the
+// response, or resets the stream when abandoning the request, which cancels the context of the handler. The handler
+// writes back the status of the request followed by the length-prefixed encoded response or error message.
+//
+// Both the requests and the responses are authorized against the roles of their senders, as if they were unicast
+// messages on the channel of the service. Requests and responses which cannot be decoded are reported as violations.
+// The number of inbound requests handled concurrently is limited per requesting peer, so that a single peer cannot
+// take up the handler of the service.
+type Service struct {
+	host         host.Host
+	protocolID   protocol.ID
+	channel      channels.Channel
+	codec        fnetwork.Codec
+	idTranslator p2p.IDTranslator
+	authorize    Authorizer
+	violations   fnetwork.ViolationsConsumer
+	// handler of the inbound requests, nil if the service only sends requests.
+	handler fnetwork.RequestHandler
+	config  *fnetwork.RequestServiceConfig
+	logger  zerolog.Logger
+
+	// inbound is the number of inbound requests being handled per requesting peer.
+	inboundLock sync.Mutex
+	inbound     map[peer.ID]int
+	// outbound is a semaphore limiting the number of concurrent outbound requests.
+	outbound chan struct{}
+}
+
+var _ fnetwork.RequestService = (*Service)(nil)
+
+// NewRequestService creates a request service on the given channel. If the handler is not nil, it is registered as the
+// handler of the inbound requests on the protocol id of the service.
+// Args:
+//   - h: the libp2p host.
+//   - protocolID: the protocol id of the service, which must be unique for the channel.
+//   - channel: the channel of the service, used for authorizing requests and responses.
+//   - codec: the codec to encode and decode requests and responses.
+//   - idTranslator: translates the flow ids of targets to peer ids.
+//   - authorize: authorizes inbound requests and responses.
+//   - violations: consumer of the violations of requests and responses which cannot be decoded.
+//   - handler: handler of the inbound requests, may be nil.
+//   - logger: the logger of the service.
+//   - opts: options to override the default configuration.
+func NewRequestService(
+	h host.Host,
+	protocolID protocol.ID,
+	channel channels.Channel,
+	codec fnetwork.Codec,
+	idTranslator p2p.IDTranslator,
+	authorize Authorizer,
+	violations fnetwork.ViolationsConsumer,
+	handler fnetwork.RequestHandler,
+	logger zerolog.Logger,
+	opts ...fnetwork.RequestServiceOption) *Service {
+	config := fnetwork.DefaultRequestServiceConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	s := &Service{
+		host:         h,
+		protocolID:   protocolID,
+		channel:      channel,
+		codec:        codec,
+		idTranslator: idTranslator,
+		authorize:    authorize,
+		violations:   violations,
+		handler:      handler,
+		config:       config,
+		logger: logger.With().
+			Str("component", "request_service").
+			Str("channel", channel.String()).
+			Logger(),
+		inbound:  make(map[peer.ID]int),
+		outbound: make(chan struct{}, config.MaxConcurrentOutboundRequests),
+	}
+
+	if handler != nil {
+		h.SetStreamHandler(protocolID, s.handleStream)
+	}
+	return s
+}
+
+// Request sends the request to the target node and waits for its response.
+// All errors returned from this function can be considered benign, see network.RequestService.
+func (s *Service) Request(ctx context.Context, targetID flow.Identifier, request interface{}) (interface{}, error) {
+	payload, err := s.codec.Encode(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode request %T: %w", request, err)
+	}
+	if len(payload) > s.config.MaxRequestSize {
+		return nil, fmt.Errorf("request %T of size %d exceeds max request size %d", request, len(payload), s.config.MaxRequestSize)
+	}
+
+	peerID, err := s.idTranslator.GetPeerID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get peer id of target %s: %w", targetID, err)
+	}
+
+	select {
+	case s.outbound <- struct{}{}:
+		defer func() { <-s.outbound }()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("could not send request to %s: %w", targetID, ctx.Err())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	response, err := s.request(ctx, peerID, targetID, payload)
+	if err != nil && ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+		// the stream was reset due to the request being abandoned, surface the cause rather than the stream error.
+		return nil, fmt.Errorf("request to %s abandoned: %w", targetID, ctx.Err())
+	}
+	return response, err
+}
+
+// request sends the encoded request on a new stream to the given peer, and reads its response.
+func (s *Service) request(ctx context.Context, peerID peer.ID, targetID flow.Identifier, payload []byte) (interface{}, error) {
+	stream, err := s.host.NewStream(ctx, peerID, s.protocolID)
+	if err != nil {
+		if errors.Is(err, multistream.ErrNotSupported[protocol.ID]{}) {
+			return nil, fnetwork.NewRequestUnsupportedErr(targetID, err)
+		}
+		return nil, fmt.Errorf("could not create stream to %s: %w", targetID, err)
+	}
+
+	// the stream is reset as soon as the request is abandoned or its deadline expires, which unblocks any pending read
+	// or write.
+	done := make(chan struct{})
+	defer close(done)
+	go resetOnDone(ctx, stream, done)
+
+	err = writeFrame(stream, payload)
+	if err != nil {
+		_ = stream.Reset()
+		return nil, fmt.Errorf("could not write request to %s: %w", targetID, err)
+	}
+	reader := bufio.NewReader(stream)
+	status, err := reader.ReadByte()
+	if err != nil {
+		_ = stream.Reset()
+		return nil, fmt.Errorf("could not read response status from %s: %w", targetID, err)
+	}
+	maxSize := s.config.MaxResponseSize
+	if status != statusOK {
+		maxSize = maxReasonSize
+	}
+	data, err := readFrame(reader, maxSize)
+	if err != nil {
+		_ = stream.Reset()
+		return nil, fmt.Errorf("could not read response from %s: %w", targetID, err)
+	}
+	_ = stream.Close()
+
+	switch status {
+	case statusOK:
+	case statusFailed:
+		return nil, fnetwork.NewRequestFailedErr(targetID, string(data))
+	case statusRejected:
+		return nil, fnetwork.NewRequestRejectedErr(targetID, string(data))
+	default:
+		return nil, fmt.Errorf("invalid response status %d from %s", status, targetID)
+	}
+
+	identity, err := s.authorize(peerID, data)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized response from %s: %w", targetID, err)
+	}
+	response, err := s.decode(peerID, identity, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode response from %s: %w", targetID, err)
+	}
+	return response, nil
+}
+
+// handleStream handles an inbound request stream.
+func (s *Service) handleStream(stream network.Stream) {
+	lg := p2putils.StreamLogger(s.logger, stream)
+
+	remotePeer := stream.Conn().RemotePeer()
+	if !s.acquireInbound(remotePeer) {
+		lg.Debug().Msg("rejecting request, too many concurrent requests of peer")
+		s.respond(lg, stream, statusRejected, []byte("too many concurrent requests"))
+		return
+	}
+	defer s.releaseInbound(remotePeer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+	// done is closed before the context is canceled upon return, so that the completed stream is not reset.
+	done := make(chan struct{})
+	defer close(done)
+	go resetOnDone(ctx, stream, done)
+
+	reader := bufio.NewReader(stream)
+	payload, err := readFrame(reader, s.config.MaxRequestSize)
+	if err != nil {
+		lg.Debug().Err(err).Msg("could not read request")
+		_ = stream.Reset()
+		return
+	}
+
+	// the requester writes nothing but the request, so any further read blocks until the requester closes the stream
+	// after reading the response, or resets the stream when abandoning the request, which cancels the handler.
+	handlerCtx, abandon := context.WithCancel(ctx)
+	defer abandon()
+	go func() {
+		_, err := reader.ReadByte()
+		if err != nil && !errors.Is(err, io.EOF) {
+			abandon()
+		}
+	}()
+
+	origin, err := s.authorize(remotePeer, payload)
+	if err != nil {
+		lg.Warn().
+			Err(err).
+			Bool(logging.KeySuspicious, true).
+			Msg("rejecting unauthorized request")
+		s.respond(lg, stream, statusRejected, []byte("unauthorized request"))
+		return
+	}
+	request, err := s.decode(remotePeer, origin, payload)
+	if err != nil {
+		lg.Warn().
+			Err(err).
+			Bool(logging.KeySuspicious, true).
+			Msg("could not decode request")
+		s.respond(lg, stream, statusRejected, []byte("invalid request"))
+		return
+	}
+
+	response, err := s.handler.HandleRequest(handlerCtx, s.channel, origin.NodeID, request)
+	if err != nil {
+		lg.Debug().Err(err).Hex("origin_id", logging.ID(origin.NodeID)).Msg("request failed")
+		s.respond(lg, stream, statusFailed, []byte(err.Error()))
+		return
+	}
+
+	data, err := s.codec.Encode(response)
+	if err != nil {
+		lg.Error().Err(err).Msgf("could not encode response %T", response)
+		s.respond(lg, stream, statusFailed, []byte("could not encode response"))
+		return
+	}
+	if len(data) > s.config.MaxResponseSize {
+		lg.Warn().
+			Int("size", len(data)).
+			Int("max_size", s.config.MaxResponseSize).
+			Msgf("response %T exceeds max response size", response)
+		s.respond(lg, stream, statusFailed, []byte("response exceeds max response size"))
+		return
+	}
+	s.respond(lg, stream, statusOK, data)
+}
+
+// acquireInbound reserves the handling of an inbound request of the given peer. Returns false if the peer has reached
+// the maximum number of concurrently handled requests.
+func (s *Service) acquireInbound(pid peer.ID) bool {
+	s.inboundLock.Lock()
+	defer s.inboundLock.Unlock()
+
+	if s.inbound[pid] >= s.config.MaxConcurrentInboundRequestsPerPeer {
+		return false
+	}
+	s.inbound[pid]++
+	return true
+}
+
+// releaseInbound releases the reservation of an inbound request of the given peer.
+func (s *Service) releaseInbound(pid peer.ID) {
+	s.inboundLock.Lock()
+	defer s.inboundLock.Unlock()
+
+	s.inbound[pid]--
+	if s.inbound[pid] <= 0 {
+		delete(s.inbound, pid)
+	}
+}
+
+// decode decodes an authorized request or response sent by the given peer, and reports payloads which cannot be decoded
+// as violations of the sender.
+// Any returned error indicates an invalid payload, and is considered benign.
+func (s *Service) decode(from peer.ID, sender *flow.Identity, payload []byte) (interface{}, error) {
+	decoded, err := s.codec.Decode(payload)
+	if err == nil {
+		return decoded, nil
+	}
+
+	violation := &fnetwork.Violation{
+		Identity: sender,
+		PeerID:   from.String(),
+		OriginID: sender.NodeID,
+		Channel:  s.channel,
+		Protocol: message.ProtocolTypeUnicast,
+		Err:      err,
+	}
+	if codec.IsErrUnknownMsgCode(err) {
+		s.violations.OnUnknownMsgTypeError(violation)
+	} else {
+		s.violations.OnInvalidMsgError(violation)
+	}
+	return nil, err
+}
+
+// respond writes the status and data of the response to the stream and closes it. Error messages exceeding the max
+// reason size are truncated.
+func (s *Service) respond(lg zerolog.Logger, stream network.Stream, status byte, data []byte) {
+	if status != statusOK && len(data) > maxReasonSize {
+		data = data[:maxReasonSize]
+	}
+
+	writer := bufio.NewWriter(stream)
+	err := writer.WriteByte(status)
+	if err == nil {
+		err = writeFrame(writer, data)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		lg.Debug().Err(err).Msg("could not write response")
+		_ = stream.Reset()
+		return
+	}
+
+	err = stream.Close()
+	if err != nil {
+		lg.Debug().Err(err).Msg("could not close stream")
+	}
+}
+
+// resetOnDone resets the stream when the context is done, unless the done channel is closed before.
+func resetOnDone(ctx context.Context, stream network.Stream, done <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+		select {
+		case <-done:
+		default:
+			_ = stream.Reset()
+		}
+	case <-done:
+	}
+}
+
+// writeFrame writes the data prefixed by its uvarint encoded length.
+func writeFrame(w io.Writer, data []byte) error {
+	prefix := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(prefix, uint64(len(data)))
+	_, err := w.Write(append(prefix[:n], data...))
+	return err
+}
+
+// readFrame reads data prefixed by its uvarint encoded length, which must not exceed the given max size.
+func readFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read frame size: %w", err)
+	}
+	if size > uint64(maxSize) {
+		return nil, fmt.Errorf("frame size %d exceeds max size %d", size, maxSize)
+	}
+	data := make([]byte, size)
+	_, err = io.ReadFull(r, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not read frame: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,206 @@
+package request_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/network/p2p/request"
+	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestRequestService_Request evaluates that a request is answered with the response of the handler of the target node,
+// and that failures of the handler are returned to the requester.
+func TestRequestService_Request(t *testing.T) {
+	requester, provider := newRequestServices(t, authorizeAll)
+	req := &messages.EntityRequest{Nonce: 1, EntityIDs: unittest.IdentifierListFixture(2)}
+	res := &messages.EntityResponse{Nonce: 1, EntityIDs: req.EntityIDs, Blobs: [][]byte{{1}, {2}}}
+
+	provider.handler.On("HandleRequest", mock.Anything, channels.RequestCollections, requester.nodeID, req).Return(res, nil).Once()
+	response, err := requester.service.Request(context.Background(), provider.nodeID, req)
+	require.NoError(t, err)
+	require.Equal(t, res, response)
+
+	provider.handler.On("HandleRequest", mock.Anything, channels.RequestCollections, requester.nodeID, req).Return(nil, fmt.Errorf("not available")).Once()
+	_, err = requester.service.Request(context.Background(), provider.nodeID, req)
+	require.True(t, network.IsErrRequestFailed(err))
+	require.Contains(t, err.Error(), "not available")
+}
+
+// TestRequestService_Unauthorized evaluates that unauthorized requests are rejected without being handled.
+func TestRequestService_Unauthorized(t *testing.T) {
+	requester, provider := newRequestServices(t, func(peer.ID, []byte) (*flow.Identity, error) {
+		return nil, fmt.Errorf("unauthorized")
+	})
+
+	_, err := requester.service.Request(context.Background(), provider.nodeID, &messages.EntityRequest{Nonce: 1})
+	require.True(t, network.IsErrRequestRejected(err))
+	provider.handler.AssertNotCalled(t, "HandleRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestRequestService_SizeLimits evaluates that requests and responses exceeding the max sizes are neither sent nor
+// returned.
+func TestRequestService_SizeLimits(t *testing.T) {
+	requester, provider := newRequestServices(t, authorizeAll, network.WithMaxRequestSize(1024), network.WithMaxResponseSize(1024))
+
+	_, err := requester.service.Request(context.Background(), provider.nodeID, &messages.EntityRequest{EntityIDs: unittest.IdentifierListFixture(100)})
+	require.ErrorContains(t, err, "exceeds max request size")
+
+	req := &messages.EntityRequest{Nonce: 1, EntityIDs: unittest.IdentifierListFixture(1)}
+	res := &messages.EntityResponse{Nonce: 1, Blobs: [][]byte{make([]byte, 2048)}}
+	provider.handler.On("HandleRequest", mock.Anything, mock.Anything, mock.Anything, req).Return(res, nil).Once()
+	_, err = requester.service.Request(context.Background(), provider.nodeID, req)
+	require.True(t, network.IsErrRequestFailed(err))
+}
+
+// TestRequestService_Concurrency evaluates that inbound requests of a peer exceeding the concurrency limit per peer are
+// rejected, and that canceling a request abandons it on both the requester and the target node.
+func TestRequestService_Concurrency(t *testing.T) {
+	requester, provider := newRequestServices(t, authorizeAll, network.WithMaxConcurrentRequests(1, 2))
+
+	blocked := &messages.EntityRequest{Nonce: 1, EntityIDs: unittest.IdentifierListFixture(1)}
+	handling := make(chan struct{})
+	abandoned := make(chan struct{})
+	provider.handler.On("HandleRequest", mock.Anything, mock.Anything, mock.Anything, blocked).
+		Run(func(args mock.Arguments) {
+			close(handling)
+			<-args.Get(0).(context.Context).Done()
+			close(abandoned)
+		}).
+		Return(nil, context.Canceled).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := requester.service.Request(ctx, provider.nodeID, blocked)
+		errs <- err
+	}()
+	unittest.RequireCloseBefore(t, handling, time.Second, "request was not handled")
+
+	// the requester has reached its limit of concurrent requests, so its further requests are rejected.
+	_, err := requester.service.Request(context.Background(), provider.nodeID, &messages.EntityRequest{Nonce: 2, EntityIDs: unittest.IdentifierListFixture(1)})
+	require.True(t, network.IsErrRequestRejected(err))
+
+	cancel()
+	unittest.RequireCloseBefore(t, abandoned, time.Second, "request was not abandoned by the handler")
+	select {
+	case err := <-errs:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("request was not abandoned by the requester")
+	}
+}
+
+// TestRequestService_Unsupported evaluates that requests to nodes which do not serve the request service fail with
+// ErrRequestUnsupported, which distinguishes them from requests failed or rejected by the target node.
+func TestRequestService_Unsupported(t *testing.T) {
+	requester, provider := newRequestServices(t, authorizeAll)
+	provider.host.RemoveStreamHandler(provider.protocolID)
+
+	_, err := requester.service.Request(context.Background(), provider.nodeID, &messages.EntityRequest{Nonce: 1})
+	require.True(t, network.IsErrRequestUnsupported(err))
+	require.False(t, network.IsErrRequestFailed(err))
+	require.False(t, network.IsErrRequestRejected(err))
+}
+
+// TestRequestService_InvalidRequest evaluates that requests which cannot be decoded are rejected without being handled,
+// and reported as violations of the requester.
+func TestRequestService_InvalidRequest(t *testing.T) {
+	requester, provider := newRequestServices(t, authorizeAll)
+
+	reported := make(chan struct{})
+	provider.violations.On("OnInvalidMsgError", mock.MatchedBy(func(violation *network.Violation) bool {
+		return violation.OriginID == requester.nodeID && violation.Channel == channels.RequestCollections
+	})).Run(func(mock.Arguments) { close(reported) }).Once()
+
+	// a truncated encoding of a valid request
+	payload, err := cborcodec.NewCodec().Encode(&messages.EntityRequest{Nonce: 1, EntityIDs: unittest.IdentifierListFixture(2)})
+	require.NoError(t, err)
+	stream, err := requester.host.NewStream(context.Background(), provider.host.ID(), provider.protocolID)
+	require.NoError(t, err)
+	defer stream.Close()
+	frame := append([]byte{byte(len(payload) / 2)}, payload[:len(payload)/2]...)
+	_, err = stream.Write(frame)
+	require.NoError(t, err)
+
+	unittest.RequireCloseBefore(t, reported, time.Second, "invalid request was not reported")
+	provider.handler.AssertNotCalled(t, "HandleRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+type requestNode struct {
+	nodeID     flow.Identifier
+	host       host.Host
+	protocolID protocol.ID
+	service    *request.Service
+	handler    *mocknetwork.RequestHandler
+	violations *mocknetwork.ViolationsConsumer
+}
+
+func authorizeAll(peer.ID, []byte) (*flow.Identity, error) {
+	return nil, nil
+}
+
+// newRequestServices returns two connected nodes running a request service on the same channel. The given authorizer
+// decides whether messages are authorized, while the flow ids of the peers are resolved by the test.
+func newRequestServices(t *testing.T, authorize request.Authorizer, opts ...network.RequestServiceOption) (*requestNode, *requestNode) {
+	mn, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mn.Close() })
+
+	hosts := mn.Hosts()
+	nodes := make([]*requestNode, len(hosts))
+	translator := mockp2p.NewIDTranslator(t)
+	for i, h := range hosts {
+		nodes[i] = &requestNode{nodeID: unittest.IdentifierFixture(), host: h}
+		translator.On("GetPeerID", nodes[i].nodeID).Return(h.ID(), nil).Maybe()
+	}
+	flowID := func(pid peer.ID) flow.Identifier {
+		for _, node := range nodes {
+			if node.host.ID() == pid {
+				return node.nodeID
+			}
+		}
+		return flow.ZeroID
+	}
+
+	protocolID := protocols.RequestProtocolId(unittest.IdentifierFixture(), channels.RequestCollections)
+	for _, node := range nodes {
+		node.protocolID = protocolID
+		node.handler = mocknetwork.NewRequestHandler(t)
+		node.violations = mocknetwork.NewViolationsConsumer(t)
+		node.service = request.NewRequestService(
+			node.host,
+			protocolID,
+			channels.RequestCollections,
+			cborcodec.NewCodec(),
+			translator,
+			func(from peer.ID, payload []byte) (*flow.Identity, error) {
+				_, err := authorize(from, payload)
+				if err != nil {
+					return nil, err
+				}
+				return &flow.Identity{NodeID: flowID(from)}, nil
+			},
+			node.violations,
+			node.handler,
+			unittest.Logger(),
+			opts...)
+	}
+	return nodes[0], nodes[1]
+}
@@ -47,6 +47,9 @@ const (
 	iHaveMisbehaviourPenalty = -10
 	// iWantMisbehaviourPenalty is the penalty applied to the application specific penalty when a peer conducts a iWant misbehaviour.
 	iWantMisbehaviourPenalty = -10
+	// rpcPublishMessageMisbehaviourPenalty is the penalty applied to the application specific penalty when a peer conducts a
+	// misbehaviour on the messages published within its RPCs.
+	rpcPublishMessageMisbehaviourPenalty = -10
 )
 
 // GossipSubCtrlMsgPenaltyValue is the penalty value for each control message type.
@@ -55,6 +58,8 @@ type GossipSubCtrlMsgPenaltyValue struct {
 	Prune float64 // penalty value for an individual prune message misbehaviour.
 	IHave float64 // penalty value for an individual iHave message misbehaviour.
 	IWant float64 // penalty value for an individual iWant message misbehaviour.
+	// RpcPublishMessage penalty value for an individual misbehaviour on the messages published within an RPC.
+	RpcPublishMessage float64
 }
 
 // DefaultGossipSubCtrlMsgPenaltyValue returns the default penalty value for each control message type.
@@ -64,6 +69,8 @@ func DefaultGossipSubCtrlMsgPenaltyValue() GossipSubCtrlMsgPenaltyValue {
 		Prune: pruneMisbehaviourPenalty,
 		IHave: iHaveMisbehaviourPenalty,
 		IWant: iWantMisbehaviourPenalty,
+
+		RpcPublishMessage: rpcPublishMessageMisbehaviourPenalty,
 	}
 }
 
@@ -132,6 +139,7 @@ func NewGossipSubAppSpecificScoreRegistry(config *GossipSubAppSpecificScoreRegis
 }
 
 var _ p2p.GossipSubInvCtrlMsgNotifConsumer = (*GossipSubAppSpecificScoreRegistry)(nil)
+var _ p2p.AppSpecificScoreExplainer = (*GossipSubAppSpecificScoreRegistry)(nil)
 
 // AppSpecificScoreFunc returns the application specific penalty function that is called by the GossipSub protocol to determine the application specific penalty of a peer.
 func (r *GossipSubAppSpecificScoreRegistry) AppSpecificScoreFunc() func(peer.ID) float64 {
@@ -188,6 +196,30 @@ func (r *GossipSubAppSpecificScoreRegistry) AppSpecificScoreFunc() func(peer.ID)
 	}
 }
 
+// AppSpecificScoreComponents returns the components of the current application specific score of the given peer, i.e.,
+// the spam, staking and subscription penalties and rewards the application specific score function is made of.
+// Any returned error is unexpected and indicates a failure of the spam record cache.
+func (r *GossipSubAppSpecificScoreRegistry) AppSpecificScoreComponents(pid peer.ID) (p2p.AppSpecificScoreComponents, error) {
+	components := p2p.AppSpecificScoreComponents{}
+
+	spamRecord, err, spamRecordExists := r.spamScoreCache.Get(pid)
+	if err != nil {
+		return components, fmt.Errorf("could not get spam record of peer %s: %w", pid, err)
+	}
+	if spamRecordExists {
+		components.SpamPenalty = spamRecord.Penalty
+	}
+
+	stakingScore, flowId, role := r.stakingScore(pid)
+	components.StakingScore = stakingScore
+	if stakingScore >= 0 {
+		// subscription penalty can be considered only for staked peers, as the role of non-staked peers is unknown.
+		components.SubscriptionPenalty = r.subscriptionPenalty(pid, flowId, role)
+	}
+
+	return components, nil
+}
+
 func (r *GossipSubAppSpecificScoreRegistry) stakingScore(pid peer.ID) (float64, flow.Identifier, flow.Role) {
 	lg := r.logger.With().Str("peer_id", pid.String()).Logger()
 
@@ -262,6 +294,8 @@ func (r *GossipSubAppSpecificScoreRegistry) OnInvalidControlMessageNotification(
 			record.Penalty += r.penalty.IHave
 		case p2pmsg.CtrlMsgIWant:
 			record.Penalty += r.penalty.IWant
+		case p2pmsg.RpcPublishMessage:
+			record.Penalty += r.penalty.RpcPublishMessage
 		default:
 			// the error is considered fatal as it means that we have an unsupported misbehaviour type, we should crash the node to prevent routing attack vulnerability.
 			lg.Fatal().Str("misbehavior_type", notification.MsgType.String()).Msg("unknown misbehaviour type")
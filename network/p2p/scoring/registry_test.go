@@ -11,6 +11,7 @@ import (
 	testifymock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/network/p2p"
@@ -199,6 +200,47 @@ func testSpamRecordWithSubscriptionPenalty(t *testing.T, messageType p2pmsg.Cont
 	assert.Less(t, math.Abs(expectedPenalty+scoring.DefaultInvalidSubscriptionPenalty-score), 10e-3)
 }
 
+// TestAppSpecificScoreComponents tests that the components of the app specific score of a peer add up to the app specific
+// score, so that the app specific score of a peer can be explained by its spam, staking and subscription penalties.
+func TestAppSpecificScoreComponents(t *testing.T) {
+	staked := peer.ID("peer-1")
+	unknown := peer.ID("peer-2")
+	reg, _ := newGossipSubAppSpecificScoreRegistry(
+		t,
+		func(cfg *scoring.GossipSubAppSpecificScoreRegistryConfig) {
+			identity := &flow.Identity{NodeID: unittest.IdentifierFixture(), Role: flow.RoleConsensus}
+			cfg.IdProvider.(*mock.IdentityProvider).On("ByPeerID", staked).Return(identity, true).Maybe()
+		},
+		withInvalidSubscriptions(staked),
+		withUnknownIdentity(unknown))
+
+	for _, pid := range []peer.ID{staked, unknown} {
+		reg.OnInvalidControlMessageNotification(&p2p.InvCtrlMsgNotif{
+			PeerID:  pid,
+			MsgType: p2pmsg.CtrlMsgGraft,
+			Count:   1,
+		})
+	}
+
+	components, err := reg.AppSpecificScoreComponents(staked)
+	require.NoError(t, err)
+	assert.Less(t, math.Abs(penaltyValueFixtures().Graft-components.SpamPenalty), 10e-3)
+	assert.Equal(t, scoring.DefaultStakedIdentityReward, components.StakingScore)
+	assert.Equal(t, scoring.DefaultInvalidSubscriptionPenalty, components.SubscriptionPenalty)
+	// the staking reward is not applied to peers with penalties.
+	score := reg.AppSpecificScoreFunc()(staked)
+	assert.Less(t, math.Abs(components.SpamPenalty+components.SubscriptionPenalty-score), 10e-3)
+
+	components, err = reg.AppSpecificScoreComponents(unknown)
+	require.NoError(t, err)
+	assert.Less(t, math.Abs(penaltyValueFixtures().Graft-components.SpamPenalty), 10e-3)
+	assert.Equal(t, scoring.DefaultUnknownIdentityPenalty, components.StakingScore)
+	// the subscriptions of peers without a valid identity are not checked.
+	assert.Zero(t, components.SubscriptionPenalty)
+	score = reg.AppSpecificScoreFunc()(unknown)
+	assert.Less(t, math.Abs(components.SpamPenalty+components.StakingScore-score), 10e-3)
+}
+
 // TestSpamPenaltyDecaysInCache tests that the spam penalty records decay over time in the cache.
 func TestSpamPenaltyDecaysInCache(t *testing.T) {
 	peerID := peer.ID("peer-1")
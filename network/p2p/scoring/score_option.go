@@ -299,6 +299,8 @@ type ScoreOption struct {
 	peerThresholdParams *pubsub.PeerScoreThresholds
 	validator           p2p.SubscriptionValidator
 	appScoreFunc        func(peer.ID) float64
+	// appScoreExplainer explains the app specific score function, nil if the app specific score function is overridden.
+	appScoreExplainer p2p.AppSpecificScoreExplainer
 }
 
 type ScoreOptionConfig struct {
@@ -403,12 +405,15 @@ func NewScoreOption(cfg *ScoreOptionConfig) *ScoreOption {
 		validator:       validator,
 		peerScoreParams: defaultPeerScoreParams(),
 		appScoreFunc:    scoreRegistry.AppSpecificScoreFunc(),
+
+		appScoreExplainer: scoreRegistry,
 	}
 
 	// set the app specific penalty function for the penalty option
 	// if the app specific penalty function is not set, use the default one
 	if cfg.appScoreFunc != nil {
 		s.appScoreFunc = cfg.appScoreFunc
+		s.appScoreExplainer = nil
 		s.logger.
 			Warn().
 			Str(logging.KeyNetworkingSecurity, "true").
@@ -439,6 +444,12 @@ func NewScoreOption(cfg *ScoreOptionConfig) *ScoreOption {
 	return s
 }
 
+// AppSpecificScoreExplainer returns the explainer of the application specific score of peers, or nil if the application
+// specific score function is overridden.
+func (s *ScoreOption) AppSpecificScoreExplainer() p2p.AppSpecificScoreExplainer {
+	return s.appScoreExplainer
+}
+
 func (s *ScoreOption) SetSubscriptionProvider(provider *SubscriptionProvider) error {
 	return s.validator.RegisterSubscriptionProvider(provider)
 }
@@ -0,0 +1,205 @@
+package tracer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/p2pconf"
+)
+
+// GossipSubScoreHistory keeps the history of the peer score snapshots of the gossipsub score tracer. Each snapshot is
+// recorded together with the components of the application specific score and the penalty of the peer in the
+// application layer spam prevention (ALSP) protocol, so that a drop of the score of a peer can be explained afterwards.
+//
+// The history of each peer is kept in a ring buffer of bounded size, and the number of peers the history is retained
+// for is bounded as well: when a new peer exceeds the bound, the history of the least recently updated peer is dropped.
+type GossipSubScoreHistory struct {
+	logger     zerolog.Logger
+	idProvider module.IdentityProvider
+	// explainer explains the application specific score of peers, nil if the components are not available.
+	explainer p2p.AppSpecificScoreExplainer
+	size      int
+	maxPeers  int
+
+	lock        sync.RWMutex
+	alspRecords alsp.SpamRecordAdmin
+	histories   map[peer.ID]*scoreRing
+}
+
+var _ p2p.PeerScoreHistorian = (*GossipSubScoreHistory)(nil)
+
+// scoreRing is a ring buffer of the score history of a single peer.
+type scoreRing struct {
+	entries []p2p.PeerScoreHistoryEntry
+	next    int // index the next entry is written to
+	full    bool
+	updated time.Time // timestamp of the latest entry
+}
+
+// NewGossipSubScoreHistory returns a new score history.
+// Args:
+//   - logger: the logger of the score history.
+//   - cfg: the score history configuration, the score history size must be positive.
+//   - idProvider: identity provider used to translate peer ids to the flow ids of the ALSP spam records.
+//   - explainer: explainer of the application specific score, may be nil if the components are not available.
+//
+// Returns:
+//   - *GossipSubScoreHistory: a new score history.
+func NewGossipSubScoreHistory(
+	logger zerolog.Logger,
+	cfg *p2pconf.GossipSubScoreHistoryConfig,
+	idProvider module.IdentityProvider,
+	explainer p2p.AppSpecificScoreExplainer) *GossipSubScoreHistory {
+	return &GossipSubScoreHistory{
+		logger:     logger.With().Str("component", "gossipsub_score_history").Logger(),
+		idProvider: idProvider,
+		explainer:  explainer,
+		size:       int(cfg.ScoreHistorySize),
+		maxPeers:   int(cfg.ScoreHistoryMaxPeers),
+		histories:  make(map[peer.ID]*scoreRing),
+	}
+}
+
+// RegisterAlspSpamRecords registers the spam records of the ALSP protocol, whose penalties are recorded in the score
+// history from then on.
+// Returns an error if spam records are already registered, which is a benign error.
+func (h *GossipSubScoreHistory) RegisterAlspSpamRecords(records alsp.SpamRecordAdmin) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.alspRecords != nil {
+		return fmt.Errorf("alsp spam records already registered")
+	}
+	h.alspRecords = records
+	return nil
+}
+
+// Record records the given peer score snapshots, taken at the given time, in the history of their peers.
+func (h *GossipSubScoreHistory) Record(timestamp time.Time, snapshots map[peer.ID]*p2p.PeerScoreSnapshot) {
+	h.lock.RLock()
+	alspRecords := h.alspRecords
+	h.lock.RUnlock()
+
+	// the entries are assembled outside the lock, as explaining the scores involves the caches of the score registry
+	// and the ALSP protocol.
+	entries := make(map[peer.ID]p2p.PeerScoreHistoryEntry, len(snapshots))
+	for peerID, snapshot := range snapshots {
+		entries[peerID] = h.entry(timestamp, peerID, snapshot, alspRecords)
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for peerID, entry := range entries {
+		ring, ok := h.histories[peerID]
+		if !ok {
+			if len(h.histories) >= h.maxPeers {
+				h.evictLeastRecentlyUpdated()
+			}
+			ring = &scoreRing{entries: make([]p2p.PeerScoreHistoryEntry, h.size)}
+			h.histories[peerID] = ring
+		}
+		ring.add(entry)
+	}
+}
+
+// PeerScoreHistory returns a copy of the score history of the given peer, ordered from the oldest to the newest entry.
+// Returns the history and true if the peer has a history, nil and false otherwise.
+func (h *GossipSubScoreHistory) PeerScoreHistory(peerID peer.ID) ([]p2p.PeerScoreHistoryEntry, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	ring, ok := h.histories[peerID]
+	if !ok {
+		return nil, false
+	}
+	return ring.list(), true
+}
+
+// PeerScoreHistories returns a copy of the score histories of all peers.
+func (h *GossipSubScoreHistory) PeerScoreHistories() map[peer.ID][]p2p.PeerScoreHistoryEntry {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	histories := make(map[peer.ID][]p2p.PeerScoreHistoryEntry, len(h.histories))
+	for peerID, ring := range h.histories {
+		histories[peerID] = ring.list()
+	}
+	return histories
+}
+
+// entry assembles the history entry of the given peer score snapshot.
+func (h *GossipSubScoreHistory) entry(timestamp time.Time, peerID peer.ID, snapshot *p2p.PeerScoreSnapshot, alspRecords alsp.SpamRecordAdmin) p2p.PeerScoreHistoryEntry {
+	entry := p2p.PeerScoreHistoryEntry{
+		Timestamp:          timestamp,
+		Score:              snapshot.Score,
+		Topics:             make(map[string]p2p.TopicScoreSnapshot, len(snapshot.Topics)),
+		AppSpecificScore:   snapshot.AppSpecificScore,
+		IPColocationFactor: snapshot.IPColocationFactor,
+		BehaviourPenalty:   snapshot.BehaviourPenalty,
+	}
+	for topic, topicSnapshot := range snapshot.Topics {
+		entry.Topics[topic] = *topicSnapshot
+	}
+
+	if h.explainer != nil {
+		components, err := h.explainer.AppSpecificScoreComponents(peerID)
+		if err != nil {
+			h.logger.Error().Err(err).Str("peer_id", peerID.String()).Msg("could not explain application specific score")
+		} else {
+			entry.AppSpecificScoreComponents = &components
+		}
+	}
+
+	if alspRecords != nil {
+		if identity, ok := h.idProvider.ByPeerID(peerID); ok {
+			if record, ok := alspRecords.SpamRecord(identity.NodeID); ok {
+				entry.AlspPenalty = record.Penalty
+				entry.AlspDisallowListed = record.DisallowListed
+			}
+		}
+	}
+
+	return entry
+}
+
+// evictLeastRecentlyUpdated drops the history of the peer with the oldest latest entry.
+// Note: this function is not thread-safe and should be called with the lock held.
+func (h *GossipSubScoreHistory) evictLeastRecentlyUpdated() {
+	var oldest peer.ID
+	var oldestUpdate time.Time
+	for peerID, ring := range h.histories {
+		if oldest == "" || ring.updated.Before(oldestUpdate) {
+			oldest = peerID
+			oldestUpdate = ring.updated
+		}
+	}
+	delete(h.histories, oldest)
+}
+
+// add adds the entry to the ring buffer, overwriting the oldest entry if the buffer is full.
+func (r *scoreRing) add(entry p2p.PeerScoreHistoryEntry) {
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.updated = entry.Timestamp
+}
+
+// list returns the entries of the ring buffer, ordered from the oldest to the newest entry.
+func (r *scoreRing) list() []p2p.PeerScoreHistoryEntry {
+	if !r.full {
+		return append([]p2p.PeerScoreHistoryEntry(nil), r.entries[:r.next]...)
+	}
+	list := make([]p2p.PeerScoreHistoryEntry, 0, len(r.entries))
+	list = append(list, r.entries[r.next:]...)
+	return append(list, r.entries[:r.next]...)
+}
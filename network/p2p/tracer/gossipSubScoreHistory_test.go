@@ -0,0 +1,154 @@
+package tracer_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	mockalsp "github.com/onflow/flow-go/network/alsp/mock"
+	"github.com/onflow/flow-go/network/alsp/model"
+	"github.com/onflow/flow-go/network/p2p"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/network/p2p/p2pconf"
+	p2ptest "github.com/onflow/flow-go/network/p2p/test"
+	"github.com/onflow/flow-go/network/p2p/tracer"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestGossipSubScoreHistory_RingBuffer evaluates that the score history of a peer retains the latest snapshots up to the
+// configured size, ordered from the oldest to the newest.
+func TestGossipSubScoreHistory_RingBuffer(t *testing.T) {
+	history := tracer.NewGossipSubScoreHistory(unittest.Logger(), &p2pconf.GossipSubScoreHistoryConfig{
+		ScoreHistorySize:     3,
+		ScoreHistoryMaxPeers: 10,
+	}, mockmodule.NewIdentityProvider(t), nil)
+
+	pid := p2ptest.PeerIdFixture(t)
+	_, ok := history.PeerScoreHistory(pid)
+	require.False(t, ok)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		history.Record(start.Add(time.Duration(i)*time.Minute), map[peer.ID]*p2p.PeerScoreSnapshot{
+			pid: {Score: float64(-i)},
+		})
+
+		entries, ok := history.PeerScoreHistory(pid)
+		require.True(t, ok)
+		require.Len(t, entries, min(i+1, 3))
+		// the newest entry is the last one.
+		require.Equal(t, float64(-i), entries[len(entries)-1].Score)
+	}
+
+	entries, ok := history.PeerScoreHistory(pid)
+	require.True(t, ok)
+	for i, entry := range entries {
+		require.Equal(t, float64(-(i + 2)), entry.Score)
+		require.Equal(t, start.Add(time.Duration(i+2)*time.Minute), entry.Timestamp)
+		require.Nil(t, entry.AppSpecificScoreComponents)
+	}
+}
+
+// TestGossipSubScoreHistory_MaxPeers evaluates that the score history is retained for at most the configured number of
+// peers, dropping the history of the least recently updated peer.
+func TestGossipSubScoreHistory_MaxPeers(t *testing.T) {
+	history := tracer.NewGossipSubScoreHistory(unittest.Logger(), &p2pconf.GossipSubScoreHistoryConfig{
+		ScoreHistorySize:     3,
+		ScoreHistoryMaxPeers: 2,
+	}, mockmodule.NewIdentityProvider(t), nil)
+
+	stale := p2ptest.PeerIdFixture(t)
+	active := p2ptest.PeerIdFixture(t)
+	joined := p2ptest.PeerIdFixture(t)
+
+	start := time.Now()
+	history.Record(start, map[peer.ID]*p2p.PeerScoreSnapshot{stale: {}, active: {}})
+	history.Record(start.Add(time.Minute), map[peer.ID]*p2p.PeerScoreSnapshot{active: {}})
+	history.Record(start.Add(2*time.Minute), map[peer.ID]*p2p.PeerScoreSnapshot{active: {}, joined: {}})
+
+	histories := history.PeerScoreHistories()
+	require.Len(t, histories, 2)
+	require.Len(t, histories[active], 3)
+	require.Len(t, histories[joined], 1)
+	require.NotContains(t, histories, stale)
+}
+
+// TestGossipSubScoreHistory_Explanation evaluates that the score history breaks down the scores of peers into their
+// topic components, application specific score components and ALSP penalty.
+func TestGossipSubScoreHistory_Explanation(t *testing.T) {
+	idProvider := mockmodule.NewIdentityProvider(t)
+	explainer := mockp2p.NewAppSpecificScoreExplainer(t)
+	history := tracer.NewGossipSubScoreHistory(unittest.Logger(), &p2pconf.GossipSubScoreHistoryConfig{
+		ScoreHistorySize:     10,
+		ScoreHistoryMaxPeers: 10,
+	}, idProvider, explainer)
+
+	pid := p2ptest.PeerIdFixture(t)
+	unstaked := p2ptest.PeerIdFixture(t)
+	identity := &flow.Identity{NodeID: unittest.IdentifierFixture(), Role: flow.RoleConsensus}
+	idProvider.On("ByPeerID", pid).Return(identity, true)
+	idProvider.On("ByPeerID", unstaked).Return(nil, false)
+
+	components := p2p.AppSpecificScoreComponents{SpamPenalty: -20, StakingScore: 100}
+	explainer.On("AppSpecificScoreComponents", pid).Return(components, nil)
+	explainer.On("AppSpecificScoreComponents", unstaked).Return(p2p.AppSpecificScoreComponents{}, fmt.Errorf("cache failure"))
+
+	snapshot := &p2p.PeerScoreSnapshot{
+		Score:            -30,
+		AppSpecificScore: -20,
+		BehaviourPenalty: 1,
+		Topics: map[string]*p2p.TopicScoreSnapshot{
+			"topic": {TimeInMesh: time.Minute, InvalidMessageDeliveries: 2},
+		},
+	}
+
+	// before the ALSP spam records are registered, no ALSP penalty is recorded.
+	history.Record(time.Now(), map[peer.ID]*p2p.PeerScoreSnapshot{pid: snapshot, unstaked: snapshot})
+
+	records := mockalsp.NewSpamRecordAdmin(t)
+	records.On("SpamRecord", identity.NodeID).Return(&model.ProtocolSpamRecord{
+		OriginId:       identity.NodeID,
+		Penalty:        -500,
+		DisallowListed: true,
+	}, true)
+	require.NoError(t, history.RegisterAlspSpamRecords(records))
+	require.Error(t, history.RegisterAlspSpamRecords(records))
+
+	history.Record(time.Now(), map[peer.ID]*p2p.PeerScoreSnapshot{pid: snapshot, unstaked: snapshot})
+
+	entries, ok := history.PeerScoreHistory(pid)
+	require.True(t, ok)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		require.Equal(t, snapshot.Score, entry.Score)
+		require.Equal(t, snapshot.AppSpecificScore, entry.AppSpecificScore)
+		require.Equal(t, snapshot.BehaviourPenalty, entry.BehaviourPenalty)
+		require.Equal(t, map[string]p2p.TopicScoreSnapshot{"topic": *snapshot.Topics["topic"]}, entry.Topics)
+		require.Equal(t, &components, entry.AppSpecificScoreComponents)
+	}
+	require.Zero(t, entries[0].AlspPenalty)
+	require.False(t, entries[0].AlspDisallowListed)
+	require.Equal(t, float64(-500), entries[1].AlspPenalty)
+	require.True(t, entries[1].AlspDisallowListed)
+
+	// scores that cannot be explained are recorded without their components.
+	entries, ok = history.PeerScoreHistory(unstaked)
+	require.True(t, ok)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		require.Nil(t, entry.AppSpecificScoreComponents)
+		require.Zero(t, entry.AlspPenalty)
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
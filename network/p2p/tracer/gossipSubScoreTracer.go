@@ -10,6 +10,7 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/utils/logging"
@@ -33,15 +34,29 @@ type GossipSubScoreTracer struct {
 	snapshot          map[peer.ID]*p2p.PeerScoreSnapshot
 	snapShotUpdateReq chan map[peer.ID]*p2p.PeerScoreSnapshot
 	idProvider        module.IdentityProvider
+	// history records the peer score snapshots, nil if the score history is disabled.
+	history *GossipSubScoreHistory
 }
 
 var _ p2p.PeerScoreTracer = (*GossipSubScoreTracer)(nil)
+var _ p2p.PeerScoreHistorian = (*GossipSubScoreTracer)(nil)
+
+// GossipSubScoreTracerOption is an option of the gossipsub score tracer.
+type GossipSubScoreTracerOption func(*GossipSubScoreTracer)
+
+// WithScoreHistory sets the history the tracer records the peer score snapshots in.
+func WithScoreHistory(history *GossipSubScoreHistory) GossipSubScoreTracerOption {
+	return func(g *GossipSubScoreTracer) {
+		g.history = history
+	}
+}
 
 func NewGossipSubScoreTracer(
 	logger zerolog.Logger,
 	provider module.IdentityProvider,
 	collector module.GossipSubScoringMetrics,
-	updateInterval time.Duration) *GossipSubScoreTracer {
+	updateInterval time.Duration,
+	opts ...GossipSubScoreTracerOption) *GossipSubScoreTracer {
 	g := &GossipSubScoreTracer{
 		logger:            logger.With().Str("component", "gossipsub_score_tracer").Logger(),
 		updateInterval:    updateInterval,
@@ -52,6 +67,10 @@ func NewGossipSubScoreTracer(
 		idProvider:        provider,
 	}
 
+	for _, opt := range opts {
+		opt(g)
+	}
+
 	g.Component = component.NewComponentManagerBuilder().
 		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
 			ready()
@@ -149,6 +168,33 @@ func (g *GossipSubScoreTracer) GetTopicScores(peerID peer.ID) (map[string]p2p.To
 	return topicsSnapshot, true
 }
 
+// PeerScoreHistory returns a copy of the score history of the given peer, ordered from the oldest to the newest entry.
+// Returns the history and true if the peer has a history, nil and false otherwise, e.g., if the score history is disabled.
+func (g *GossipSubScoreTracer) PeerScoreHistory(peerID peer.ID) ([]p2p.PeerScoreHistoryEntry, bool) {
+	if g.history == nil {
+		return nil, false
+	}
+	return g.history.PeerScoreHistory(peerID)
+}
+
+// PeerScoreHistories returns a copy of the score histories of all peers, which is empty if the score history is disabled.
+func (g *GossipSubScoreTracer) PeerScoreHistories() map[peer.ID][]p2p.PeerScoreHistoryEntry {
+	if g.history == nil {
+		return make(map[peer.ID][]p2p.PeerScoreHistoryEntry)
+	}
+	return g.history.PeerScoreHistories()
+}
+
+// RegisterAlspSpamRecords registers the spam records of the ALSP protocol with the score history. It is a no-op if the
+// score history is disabled.
+// Returns an error if spam records are already registered, which is a benign error.
+func (g *GossipSubScoreTracer) RegisterAlspSpamRecords(records alsp.SpamRecordAdmin) error {
+	if g.history == nil {
+		return nil
+	}
+	return g.history.RegisterAlspSpamRecords(records)
+}
+
 func (g *GossipSubScoreTracer) logLoop(ctx irrecoverable.SignalerContext) {
 	g.logger.Debug().Msg("starting log loop")
 	for {
@@ -167,6 +213,9 @@ func (g *GossipSubScoreTracer) logLoop(ctx irrecoverable.SignalerContext) {
 			g.logger.Debug().Msg("received snapshot update")
 			g.updateSnapshot(snapshot)
 			g.logger.Debug().Msg("snapshot updated")
+			if g.history != nil {
+				g.history.Record(time.Now(), snapshot)
+			}
 			g.logPeerScores()
 			g.logger.Debug().Msg("peer scores logged")
 		}
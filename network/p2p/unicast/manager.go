@@ -39,6 +39,7 @@ type Manager struct {
 	logger                 zerolog.Logger
 	streamFactory          stream.Factory
 	protocols              []protocols.Protocol
+	accepted               map[protocols.ProtocolName]protocols.Protocol // all protocols incoming streams are accepted on, including non-preferred ones
 	defaultHandler         libp2pnet.StreamHandler
 	sporkId                flow.Identifier
 	connStatus             p2p.PeerConnections
@@ -60,6 +61,7 @@ func NewUnicastManager(logger zerolog.Logger,
 		sporkId:                sporkId,
 		connStatus:             connStatus,
 		peerDialing:            sync.Map{},
+		accepted:               make(map[protocols.ProtocolName]protocols.Protocol),
 		createStreamRetryDelay: createStreamRetryDelay,
 		metrics:                metrics,
 	}
@@ -67,6 +69,8 @@ func NewUnicastManager(logger zerolog.Logger,
 
 // WithDefaultHandler sets the default stream handler for this unicast manager. The default handler is utilized
 // as the core handler for other unicast protocols, e.g., compressions.
+// Incoming streams are accepted on the unicast protocols of all compression codecs, so that peers can negotiate
+// the compression codec of each channel, see protocols.WithPreferredProtocol.
 func (m *Manager) WithDefaultHandler(defaultHandler libp2pnet.StreamHandler) {
 	defaultProtocolID := protocols.FlowProtocolID(m.sporkId)
 	if len(m.protocols) > 0 {
@@ -84,21 +88,45 @@ func (m *Manager) WithDefaultHandler(defaultHandler libp2pnet.StreamHandler) {
 
 	m.streamFactory.SetStreamHandler(defaultProtocolID, defaultHandler)
 	m.logger.Info().Str("protocol_id", string(defaultProtocolID)).Msg("default unicast handler registered")
+
+	for _, name := range protocols.CompressionUnicasts() {
+		_, err := m.accept(name)
+		if err != nil {
+			// all compression codecs have a unicast protocol
+			panic(fmt.Errorf("could not accept compression unicast: %w", err))
+		}
+	}
+}
+
+// accept registers the stream handler of the given protocol name, without preferring the protocol for outgoing streams.
+// Returns the protocol, which is only registered once.
+func (m *Manager) accept(name protocols.ProtocolName) (protocols.Protocol, error) {
+	if u, ok := m.accepted[name]; ok {
+		return u, nil
+	}
+
+	factory, err := protocols.ToProtocolFactory(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not translate protocol name into factory: %w", err)
+	}
+
+	u := factory(m.logger, m.sporkId, m.defaultHandler)
+	m.accepted[name] = u
+	m.streamFactory.SetStreamHandler(u.ProtocolId(), u.Handler)
+	m.logger.Info().Str("protocol_id", string(u.ProtocolId())).Msg("unicast handler registered")
+
+	return u, nil
 }
 
 // Register registers given protocol name as preferred unicast. Each invocation of register prioritizes the current protocol
 // over previously registered ones.
 func (m *Manager) Register(protocol protocols.ProtocolName) error {
-	factory, err := protocols.ToProtocolFactory(protocol)
+	u, err := m.accept(protocol)
 	if err != nil {
-		return fmt.Errorf("could not translate protocol name into factory: %w", err)
+		return err
 	}
 
-	u := factory(m.logger, m.sporkId, m.defaultHandler)
-
 	m.protocols = append(m.protocols, u)
-	m.streamFactory.SetStreamHandler(u.ProtocolId(), u.Handler)
-	m.logger.Info().Str("protocol_id", string(u.ProtocolId())).Msg("unicast handler registered")
 
 	return nil
 }
@@ -106,10 +134,11 @@ func (m *Manager) Register(protocol protocols.ProtocolName) error {
 // CreateStream tries establishing a libp2p stream to the remote peer id. It tries creating streams in the descending order of preference until
 // it either creates a successful stream or runs out of options. Creating stream on each protocol is tried at most `maxAttempts`, and then falls
 // back to the less preferred one.
+// If the context carries a preferred protocol (see protocols.WithPreferredProtocol), that protocol is tried first.
 func (m *Manager) CreateStream(ctx context.Context, peerID peer.ID, maxAttempts int) (libp2pnet.Stream, []multiaddr.Multiaddr, error) {
 	var errs error
-	for i := len(m.protocols) - 1; i >= 0; i-- {
-		s, addrs, err := m.tryCreateStream(ctx, peerID, uint64(maxAttempts), m.protocols[i])
+	for _, protocol := range m.preferenceOrder(ctx) {
+		s, addrs, err := m.tryCreateStream(ctx, peerID, uint64(maxAttempts), protocol)
 		if err != nil {
 			errs = multierror.Append(errs, err)
 			continue
@@ -122,6 +151,27 @@ func (m *Manager) CreateStream(ctx context.Context, peerID peer.ID, maxAttempts
 	return nil, nil, fmt.Errorf("could not create stream on any available unicast protocol: %w", errs)
 }
 
+// preferenceOrder returns the unicast protocols in the descending order of preference for creating a stream under the given context.
+func (m *Manager) preferenceOrder(ctx context.Context) []protocols.Protocol {
+	ordered := make([]protocols.Protocol, 0, len(m.protocols)+1)
+
+	preferred, ok := protocols.PreferredProtocol(ctx)
+	if ok {
+		if u, ok := m.accepted[preferred]; ok {
+			ordered = append(ordered, u)
+		}
+	}
+
+	for i := len(m.protocols) - 1; i >= 0; i-- {
+		if len(ordered) > 0 && m.protocols[i].ProtocolId() == ordered[0].ProtocolId() {
+			continue
+		}
+		ordered = append(ordered, m.protocols[i])
+	}
+
+	return ordered
+}
+
 // tryCreateStream will retry createStream with the configured exponential backoff delay and maxAttempts.
 // During retries, each error encountered is aggregated in a multierror. If max attempts are made before a
 // stream can be successfully the multierror will be returned. During stream creation when IsErrDialInProgress
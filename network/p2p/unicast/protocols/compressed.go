@@ -0,0 +1,164 @@
+package protocols
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/network/p2p/compressed"
+)
+
+const (
+	Lz4CompressionUnicast      = ProtocolName("lz4-compression")
+	ZstdCompressionUnicast     = ProtocolName("zstd-compression")
+	ZstdDictCompressionUnicast = ProtocolName("zstd-dict-compression")
+)
+
+// compressionCodecs maps the compression unicast protocols to their compression codecs.
+var compressionCodecs = map[ProtocolName]compressor.Codec{
+	Lz4CompressionUnicast:      compressor.CodecLz4,
+	ZstdCompressionUnicast:     compressor.CodecZstd,
+	ZstdDictCompressionUnicast: compressor.CodecZstdDict,
+}
+
+// CompressionUnicasts returns the names of the unicast protocols of all compression codecs. Nodes accept incoming
+// streams on all of these protocols, so that peers can negotiate the compression codec of each channel.
+func CompressionUnicasts() []ProtocolName {
+	names := make([]ProtocolName, 0, len(compressor.Codecs))
+	for _, codec := range compressor.Codecs {
+		name, _ := CompressionUnicast(codec)
+		names = append(names, name)
+	}
+	return names
+}
+
+// CompressionUnicast returns the name of the unicast protocol of the given compression codec.
+// Returns false for CodecNone, which uses the default unicast protocol.
+func CompressionUnicast(codec compressor.Codec) (ProtocolName, bool) {
+	for name, c := range compressionCodecs {
+		if c == codec {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// compressionProtocolPrefix returns the protocol id prefix of streams compressed with the given codec, i.e., the
+// protocol id without the spork id. Returns false for CodecNone.
+func compressionProtocolPrefix(codec compressor.Codec) (string, bool) {
+	switch codec {
+	case compressor.CodecLz4:
+		return FlowLibP2PProtocolLz4CompressedOneToOne, true
+	case compressor.CodecZstd:
+		return FlowLibP2PProtocolZstdCompressedOneToOne, true
+	case compressor.CodecZstdDict:
+		dictID := compressor.DictionaryID(compressor.DefaultDictionary())
+		return fmt.Sprintf("%s%d/", FlowLibP2PProtocolZstdDictCompressedOneToOne, dictID), true
+	default:
+		return "", false
+	}
+}
+
+// FlowCompressedProtocolId returns the protocol id of streams compressed with the given codec. For CodecNone,
+// the default unicast protocol id is returned.
+func FlowCompressedProtocolId(codec compressor.Codec, sporkId flow.Identifier) protocol.ID {
+	prefix, ok := compressionProtocolPrefix(codec)
+	if !ok {
+		return FlowProtocolID(sporkId)
+	}
+	return protocol.ID(prefix + sporkId.String())
+}
+
+// SupportsCompression returns true if the given protocol ids, as advertised by a peer, include the unicast protocol
+// of the given codec, i.e., if the peer is able to decompress data compressed with the codec.
+func SupportsCompression(peerProtocols []protocol.ID, codec compressor.Codec) bool {
+	prefix, ok := compressionProtocolPrefix(codec)
+	if !ok {
+		return true
+	}
+	for _, p := range peerProtocols {
+		if strings.HasPrefix(string(p), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamCompression returns the name of the compression of the given stream, as negotiated through its protocol id,
+// i.e., either the name of a compression codec, "gzip", or "none" for plain streams.
+func StreamCompression(s libp2pnet.Stream) string {
+	p := string(s.Protocol())
+	if strings.HasPrefix(p, FlowLibP2PProtocolGzipCompressedOneToOne) {
+		return "gzip"
+	}
+	for _, codec := range compressor.Codecs {
+		prefix, _ := compressionProtocolPrefix(codec)
+		if strings.HasPrefix(p, prefix) {
+			return codec.String()
+		}
+	}
+	return compressor.CodecNone.String()
+}
+
+// CompressedStream is a stream compression which creates and returns a stream compressed with the compressor of a
+// compression codec out of input stream.
+type CompressedStream struct {
+	protocolId     protocol.ID
+	codec          compressor.Codec
+	defaultHandler libp2pnet.StreamHandler
+	logger         zerolog.Logger
+}
+
+func NewCompressedUnicast(logger zerolog.Logger, sporkId flow.Identifier, codec compressor.Codec, defaultHandler libp2pnet.StreamHandler) *CompressedStream {
+	return &CompressedStream{
+		protocolId:     FlowCompressedProtocolId(codec, sporkId),
+		codec:          codec,
+		defaultHandler: defaultHandler,
+		logger:         logger.With().Str("subsystem", "compressed-unicast").Str("codec", codec.String()).Logger(),
+	}
+}
+
+// UpgradeRawStream wraps compression and decompression of the codec around the plain libp2p stream.
+func (c CompressedStream) UpgradeRawStream(s libp2pnet.Stream) (libp2pnet.Stream, error) {
+	comp, err := compressor.NewCompressor(c.codec)
+	if err != nil {
+		return nil, fmt.Errorf("could not create compressor: %w", err)
+	}
+	return compressed.NewCompressedStream(s, comp)
+}
+
+func (c CompressedStream) Handler(s libp2pnet.Stream) {
+	// converts native libp2p stream to compressed stream
+	s, err := c.UpgradeRawStream(s)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("could not create compressed stream")
+		return
+	}
+	c.defaultHandler(s)
+}
+
+func (c CompressedStream) ProtocolId() protocol.ID {
+	return c.protocolId
+}
+
+type preferredProtocolKey struct{}
+
+// WithPreferredProtocol returns a copy of the context which makes stream creation try the given unicast protocol
+// before all other registered unicast protocols. This is how the compression codec of a channel is negotiated:
+// if the remote peer does not support the preferred protocol, stream creation falls back to the other protocols
+// in their order of preference.
+func WithPreferredProtocol(ctx context.Context, name ProtocolName) context.Context {
+	return context.WithValue(ctx, preferredProtocolKey{}, name)
+}
+
+// PreferredProtocol returns the unicast protocol preferred by the given context, if any.
+func PreferredProtocol(ctx context.Context) (ProtocolName, bool) {
+	name, ok := ctx.Value(preferredProtocolKey{}).(ProtocolName)
+	return name, ok
+}
@@ -0,0 +1,64 @@
+package protocols_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestCompressionUnicasts evaluates that every compression codec has a unicast protocol with a distinct protocol id.
+func TestCompressionUnicasts(t *testing.T) {
+	sporkId := unittest.IdentifierFixture()
+
+	names := protocols.CompressionUnicasts()
+	require.Len(t, names, len(compressor.Codecs))
+
+	ids := make(map[protocol.ID]struct{})
+	for _, codec := range compressor.Codecs {
+		name, ok := protocols.CompressionUnicast(codec)
+		require.True(t, ok)
+		require.Contains(t, names, name)
+
+		factory, err := protocols.ToProtocolFactory(name)
+		require.NoError(t, err)
+		u := factory(unittest.Logger(), sporkId, nil)
+		require.Equal(t, protocols.FlowCompressedProtocolId(codec, sporkId), u.ProtocolId())
+		ids[u.ProtocolId()] = struct{}{}
+	}
+	require.Len(t, ids, len(compressor.Codecs))
+
+	_, ok := protocols.CompressionUnicast(compressor.CodecNone)
+	require.False(t, ok)
+	require.Equal(t, protocols.FlowProtocolID(sporkId), protocols.FlowCompressedProtocolId(compressor.CodecNone, sporkId))
+}
+
+// TestSupportsCompression evaluates that support of a codec is derived from the protocol ids advertised by a peer,
+// regardless of its spork id.
+func TestSupportsCompression(t *testing.T) {
+	advertised := []protocol.ID{
+		protocols.FlowProtocolID(unittest.IdentifierFixture()),
+		protocols.FlowCompressedProtocolId(compressor.CodecZstdDict, unittest.IdentifierFixture()),
+	}
+
+	require.True(t, protocols.SupportsCompression(advertised, compressor.CodecZstdDict))
+	require.True(t, protocols.SupportsCompression(advertised, compressor.CodecNone))
+	require.False(t, protocols.SupportsCompression(advertised, compressor.CodecZstd))
+	require.False(t, protocols.SupportsCompression(advertised, compressor.CodecLz4))
+}
+
+// TestPreferredProtocol evaluates that the preferred unicast protocol is carried by the context.
+func TestPreferredProtocol(t *testing.T) {
+	_, ok := protocols.PreferredProtocol(context.Background())
+	require.False(t, ok)
+
+	ctx := protocols.WithPreferredProtocol(context.Background(), protocols.ZstdCompressionUnicast)
+	name, ok := protocols.PreferredProtocol(ctx)
+	require.True(t, ok)
+	require.Equal(t, protocols.ZstdCompressionUnicast, name)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
 )
 
 // Flow Libp2p protocols
@@ -26,8 +27,23 @@ const (
 	// FlowLibP2PPingProtocolPrefix is the Flow Ping protocol prefix
 	FlowLibP2PPingProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/ping/"
 
+	// FlowLibP2PRequestProtocolPrefix is the Flow request/response protocol prefix, the protocol ids of request services
+	// are suffixed with the spork id and the channel of the service.
+	FlowLibP2PRequestProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/request/"
+
 	// FlowLibP2PProtocolGzipCompressedOneToOne represents the protocol id for compressed streams under gzip compressor.
 	FlowLibP2PProtocolGzipCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/gzip/"
+
+	// FlowLibP2PProtocolLz4CompressedOneToOne represents the protocol id for compressed streams under lz4 compressor.
+	FlowLibP2PProtocolLz4CompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/lz4/"
+
+	// FlowLibP2PProtocolZstdCompressedOneToOne represents the protocol id for compressed streams under zstd compressor.
+	FlowLibP2PProtocolZstdCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/zstd/"
+
+	// FlowLibP2PProtocolZstdDictCompressedOneToOne represents the protocol id prefix for compressed streams under zstd
+	// compressor with dictionary. The prefix is suffixed with the dictionary ID, so that only peers using the same
+	// dictionary negotiate the protocol.
+	FlowLibP2PProtocolZstdDictCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/zstd-dict/"
 )
 
 // IsFlowProtocolStream returns true if the libp2p stream is for a Flow protocol
@@ -52,6 +68,11 @@ func PingProtocolId(sporkId flow.Identifier) protocol.ID {
 	return protocol.ID(FlowLibP2PPingProtocolPrefix + sporkId.String())
 }
 
+// RequestProtocolId returns the protocol id of the request service on the given channel.
+func RequestProtocolId(sporkId flow.Identifier, channel channels.Channel) protocol.ID {
+	return protocol.ID(FlowLibP2PRequestProtocolPrefix + sporkId.String() + "/" + channel.String())
+}
+
 type ProtocolName string
 type ProtocolFactory func(zerolog.Logger, flow.Identifier, libp2pnet.StreamHandler) Protocol
 
@@ -69,6 +90,10 @@ func ToProtocolFactory(name ProtocolName) (ProtocolFactory, error) {
 		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
 			return NewGzipCompressedUnicast(logger, sporkId, handler)
 		}, nil
+	case Lz4CompressionUnicast, ZstdCompressionUnicast, ZstdDictCompressionUnicast:
+		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
+			return NewCompressedUnicast(logger, sporkId, compressionCodecs[name], handler)
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown unicast protocol name: %s", name)
 	}
@@ -21,7 +21,7 @@ type UnicastManager interface {
 	Register(unicast protocols.ProtocolName) error
 	// CreateStream tries establishing a libp2p stream to the remote peer id. It tries creating streams in the descending order of preference until
 	// it either creates a successful stream or runs out of options. Creating stream on each protocol is tried at most `maxAttempts`, and then falls
-	// back to the less preferred one.
+	// back to the less preferred one. If the context carries a preferred protocol (see protocols.WithPreferredProtocol), that protocol is tried first.
 	// All errors returned from this function can be considered benign.
 	CreateStream(ctx context.Context, peerID peer.ID, maxAttempts int) (libp2pnet.Stream, []multiaddr.Multiaddr, error)
 }
@@ -0,0 +1,332 @@
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+const (
+	// DefaultChannelWeight is the weight of channels without configured weight.
+	DefaultChannelWeight = uint(1)
+	// DefaultChannelCapacity is the default maximum number of messages buffered per channel.
+	DefaultChannelCapacity = 10_000
+)
+
+// FairMessageQueueConfig is the configuration of the fair message queue.
+type FairMessageQueueConfig struct {
+	// ChannelWeights is the weight of each channel, i.e., the number of its messages removed from the queue in each
+	// round over all channels with pending messages. Cluster channels are configured by their cluster channel prefix.
+	ChannelWeights map[channels.Channel]uint
+	// DefaultWeight is the weight of channels missing from ChannelWeights.
+	DefaultWeight uint
+	// ChannelCapacity is the maximum number of messages buffered per channel.
+	ChannelCapacity int
+}
+
+// DefaultFairMessageQueueConfig returns the default configuration of the fair message queue, which weighs all
+// channels equally.
+func DefaultFairMessageQueueConfig() *FairMessageQueueConfig {
+	return &FairMessageQueueConfig{
+		ChannelWeights:  make(map[channels.Channel]uint),
+		DefaultWeight:   DefaultChannelWeight,
+		ChannelCapacity: DefaultChannelCapacity,
+	}
+}
+
+// OverflowConsumer is notified about the origin of a message dropped because the buffer of its channel was full.
+type OverflowConsumer func(channel channels.Channel, originID flow.Identifier)
+
+// FairMessageQueue is a message queue of QMessages which is fair across channels and origins. Messages are buffered
+// per channel and origin in priority queues ordered by the priority function. Channels with pending messages are
+// served in rounds, in which every channel gets as many messages removed as its weight (weighted round-robin).
+// Within a channel, origins are served in turns of one message each (round-robin), so that a single origin cannot
+// starve the other origins of the channel.
+//
+// The buffer of each channel is bounded. When a message arrives at a full channel buffer, the origin with the most
+// buffered messages on the channel is considered the source of the overflow: if it is the origin of the arriving
+// message, the arriving message is dropped. Otherwise, the lowest priority message of that origin is evicted to make
+// room for the arriving message. In both cases, the overflow consumer is notified about the source of the overflow.
+type FairMessageQueue struct {
+	cond         *sync.Cond
+	ctx          context.Context
+	priorityFunc MessagePriorityFunc
+	metrics      module.NetworkInboundQueueMetrics
+	config       *FairMessageQueueConfig
+	onOverflow   OverflowConsumer
+
+	queues  map[channels.Channel]*channelQueue
+	active  []*channelQueue // channels with pending messages in their round-robin order
+	current int             // index of the channel being served in active
+	size    int
+}
+
+var _ network.MessageQueue = (*FairMessageQueue)(nil)
+
+// channelQueue buffers the messages of a single channel, per origin.
+type channelQueue struct {
+	channel channels.Channel
+	weight  uint
+	credit  uint // number of messages the channel may still have removed in the current round
+	origins map[flow.Identifier]*priorityQueue
+	order   []flow.Identifier // origins with pending messages in their round-robin order
+	next    int               // index of the origin to serve next in order
+	size    int
+}
+
+// NewFairMessageQueue creates a fair message queue. The overflow consumer is called outside of the lock of the
+// queue, and is expected to be non-blocking.
+// Returns an error if the channel capacity of the config is smaller than 1, as the queue would drop all messages.
+func NewFairMessageQueue(ctx context.Context, priorityFunc MessagePriorityFunc, metrics module.NetworkInboundQueueMetrics, config *FairMessageQueueConfig, onOverflow OverflowConsumer) (*FairMessageQueue, error) {
+	if config.ChannelCapacity < 1 {
+		return nil, fmt.Errorf("invalid channel capacity %d, must be at least 1", config.ChannelCapacity)
+	}
+
+	mq := &FairMessageQueue{
+		cond:         sync.NewCond(&sync.Mutex{}),
+		ctx:          ctx,
+		priorityFunc: priorityFunc,
+		metrics:      metrics,
+		config:       config,
+		onOverflow:   onOverflow,
+		queues:       make(map[channels.Channel]*channelQueue),
+	}
+
+	// kick off a go routine to unblock queue readers on shutdown
+	go func() {
+		<-ctx.Done()
+		// unblock receive
+		mq.cond.Broadcast()
+	}()
+
+	return mq, nil
+}
+
+// Insert adds a QMessage to the buffer of its channel and origin. If the buffer of the channel is full, either the
+// message or a message of the origin with the most buffered messages on the channel is dropped.
+// Returns an error if the message is not a QMessage, its priority cannot be derived, or the queue is shut down.
+func (mq *FairMessageQueue) Insert(message interface{}) error {
+	if err := mq.ctx.Err(); err != nil {
+		return err
+	}
+
+	qm, ok := message.(QMessage)
+	if !ok {
+		return fmt.Errorf("invalid message format: %T", message)
+	}
+
+	// determine the message priority
+	priority, err := mq.priorityFunc(message)
+	if err != nil {
+		return fmt.Errorf("failed to derive message priority: %w", err)
+	}
+
+	item := &item{
+		message:   message,
+		priority:  int(priority),
+		timestamp: time.Now(),
+	}
+
+	overflowOrigin, overflow := mq.insert(qm, item)
+	if overflow {
+		mq.onOverflow(qm.Target, overflowOrigin)
+	}
+	return nil
+}
+
+// insert buffers the item of the given message, and returns the source of the overflow if the buffer of the channel
+// is full.
+func (mq *FairMessageQueue) insert(qm QMessage, item *item) (flow.Identifier, bool) {
+	mq.cond.L.Lock()
+	defer mq.cond.L.Unlock()
+
+	cq, ok := mq.queues[qm.Target]
+	if !ok {
+		cq = &channelQueue{
+			channel: qm.Target,
+			weight:  mq.weight(qm.Target),
+			origins: make(map[flow.Identifier]*priorityQueue),
+		}
+		mq.queues[qm.Target] = cq
+	}
+
+	var overflowOrigin flow.Identifier
+	active := cq.size > 0
+	overflow := cq.size >= mq.config.ChannelCapacity
+	if overflow {
+		overflowOrigin = cq.heaviestOrigin(qm.SenderID)
+		if overflowOrigin == qm.SenderID {
+			// the origin of the message is the source of the overflow, hence its message is dropped
+			return overflowOrigin, true
+		}
+		evicted := cq.evict(overflowOrigin)
+		mq.size--
+		mq.metrics.MessageRemoved(evicted.priority)
+	}
+
+	if !active {
+		mq.active = append(mq.active, cq)
+	}
+	cq.push(qm.SenderID, item)
+	mq.size++
+	mq.metrics.MessageAdded(item.priority)
+
+	// signal a waiting routine that a message is now available
+	mq.cond.Signal()
+
+	return overflowOrigin, overflow
+}
+
+// Remove removes the next message in the fair order of the queue. It blocks until a message is available,
+// and returns nil if the queue is shut down.
+func (mq *FairMessageQueue) Remove() interface{} {
+	mq.cond.L.Lock()
+	defer mq.cond.L.Unlock()
+	for mq.size == 0 {
+
+		// if the context has been canceled, don't wait
+		if err := mq.ctx.Err(); err != nil {
+			return nil
+		}
+
+		mq.cond.Wait()
+	}
+
+	cq := mq.active[mq.current]
+	if cq.credit == 0 {
+		// the channel starts its turn of the round
+		cq.credit = cq.weight
+	}
+	item := cq.pop()
+	cq.credit--
+	mq.size--
+
+	switch {
+	case cq.size == 0:
+		// the channel has no more pending messages, and leaves the round-robin order
+		cq.credit = 0
+		mq.active = append(mq.active[:mq.current], mq.active[mq.current+1:]...)
+		if mq.current >= len(mq.active) {
+			mq.current = 0
+		}
+	case cq.credit == 0:
+		// the channel has used up its turn
+		mq.current = (mq.current + 1) % len(mq.active)
+	}
+
+	// record metrics
+	mq.metrics.QueueDuration(time.Since(item.timestamp), item.priority)
+	mq.metrics.MessageRemoved(item.priority)
+
+	return item.message
+}
+
+// Len returns the total number of messages buffered in the queue.
+func (mq *FairMessageQueue) Len() int {
+	mq.cond.L.Lock()
+	defer mq.cond.L.Unlock()
+	return mq.size
+}
+
+// weight returns the weight of the given channel, falling back to the weight of its cluster channel prefix.
+func (mq *FairMessageQueue) weight(channel channels.Channel) uint {
+	if w, ok := mq.config.ChannelWeights[channel]; ok && w > 0 {
+		return w
+	}
+	if prefix, ok := channels.ClusterChannelPrefix(channel); ok {
+		if w, ok := mq.config.ChannelWeights[channels.Channel(prefix)]; ok && w > 0 {
+			return w
+		}
+	}
+	if mq.config.DefaultWeight > 0 {
+		return mq.config.DefaultWeight
+	}
+	return DefaultChannelWeight
+}
+
+// push buffers the item of the given origin.
+func (cq *channelQueue) push(originID flow.Identifier, item *item) {
+	pq, ok := cq.origins[originID]
+	if !ok {
+		pq = &priorityQueue{}
+		cq.origins[originID] = pq
+		cq.order = append(cq.order, originID)
+	}
+	heap.Push(pq, item)
+	cq.size++
+}
+
+// pop removes the highest priority item of the origin whose turn it is. The channel must have pending messages.
+func (cq *channelQueue) pop() *item {
+	originID := cq.order[cq.next]
+	pq := cq.origins[originID]
+	item := heap.Pop(pq).(*item)
+	cq.size--
+
+	if pq.Len() == 0 {
+		cq.removeOrigin(cq.next)
+		return item
+	}
+	cq.next = (cq.next + 1) % len(cq.order)
+	return item
+}
+
+// evict removes the lowest priority item of the given origin, preferring the newest of items with equal priority.
+// The origin must have pending messages.
+func (cq *channelQueue) evict(originID flow.Identifier) *item {
+	pq := cq.origins[originID]
+	lowest := (*pq)[0]
+	for _, it := range *pq {
+		if it.priority < lowest.priority || (it.priority == lowest.priority && it.timestamp.After(lowest.timestamp)) {
+			lowest = it
+		}
+	}
+	heap.Remove(pq, lowest.index)
+	cq.size--
+
+	if pq.Len() == 0 {
+		for i, id := range cq.order {
+			if id == originID {
+				cq.removeOrigin(i)
+				break
+			}
+		}
+	}
+	return lowest
+}
+
+// removeOrigin removes the origin at the given index of the round-robin order.
+func (cq *channelQueue) removeOrigin(index int) {
+	delete(cq.origins, cq.order[index])
+	cq.order = append(cq.order[:index], cq.order[index+1:]...)
+	if index < cq.next {
+		cq.next--
+	}
+	if cq.next >= len(cq.order) {
+		cq.next = 0
+	}
+}
+
+// heaviestOrigin returns the origin with the most buffered messages on the channel. Ties are resolved in favor of
+// the given sender, so that a sender at least as heavy as all other origins is held responsible for the overflow.
+func (cq *channelQueue) heaviestOrigin(senderID flow.Identifier) flow.Identifier {
+	heaviest := senderID
+	heaviestSize := 0
+	if pq, ok := cq.origins[senderID]; ok {
+		heaviestSize = pq.Len()
+	}
+	for _, originID := range cq.order {
+		if size := cq.origins[originID].Len(); size > heaviestSize {
+			heaviest = originID
+			heaviestSize = size
+		}
+	}
+	return heaviest
+}
@@ -0,0 +1,181 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/queue"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestFairMessageQueue_ChannelWeights evaluates that channels with pending messages are served in proportion
+// to their weights, regardless of how many messages they buffer.
+func TestFairMessageQueue_ChannelWeights(t *testing.T) {
+	cfg := queue.DefaultFairMessageQueueConfig()
+	cfg.ChannelWeights[channels.ConsensusCommittee] = 3
+	mq := newFairQueue(t, cfg, nil)
+
+	origin := unittest.IdentifierFixture()
+	// the sync channel is flooded before any consensus message arrives
+	for i := 0; i < 100; i++ {
+		require.NoError(t, mq.Insert(qMessage(channels.SyncCommittee, origin, i)))
+	}
+	for i := 0; i < 6; i++ {
+		require.NoError(t, mq.Insert(qMessage(channels.ConsensusCommittee, origin, i)))
+	}
+
+	// every round serves one sync message and three consensus messages
+	var served []channels.Channel
+	for i := 0; i < 8; i++ {
+		served = append(served, mq.Remove().(queue.QMessage).Target)
+	}
+	require.Equal(t, []channels.Channel{
+		channels.SyncCommittee,
+		channels.ConsensusCommittee, channels.ConsensusCommittee, channels.ConsensusCommittee,
+		channels.SyncCommittee,
+		channels.ConsensusCommittee, channels.ConsensusCommittee, channels.ConsensusCommittee,
+	}, served)
+	require.Equal(t, 98, mq.Len())
+}
+
+// TestFairMessageQueue_ClusterChannelWeights evaluates that cluster channels are weighted by their cluster channel prefix.
+func TestFairMessageQueue_ClusterChannelWeights(t *testing.T) {
+	cfg := queue.DefaultFairMessageQueueConfig()
+	cfg.ChannelWeights[channels.Channel(channels.ConsensusClusterPrefix)] = 2
+	mq := newFairQueue(t, cfg, nil)
+
+	origin := unittest.IdentifierFixture()
+	cluster := channels.ConsensusCluster(flow.ChainID("cluster"))
+	for i := 0; i < 4; i++ {
+		require.NoError(t, mq.Insert(qMessage(channels.SyncCommittee, origin, i)))
+		require.NoError(t, mq.Insert(qMessage(cluster, origin, i)))
+	}
+
+	var served []channels.Channel
+	for i := 0; i < 6; i++ {
+		served = append(served, mq.Remove().(queue.QMessage).Target)
+	}
+	require.Equal(t, []channels.Channel{
+		channels.SyncCommittee, cluster, cluster,
+		channels.SyncCommittee, cluster, cluster,
+	}, served)
+}
+
+// TestFairMessageQueue_OriginRoundRobin evaluates that the origins of a channel are served in turns, so that a
+// flooding origin does not delay the messages of other origins.
+func TestFairMessageQueue_OriginRoundRobin(t *testing.T) {
+	mq := newFairQueue(t, queue.DefaultFairMessageQueueConfig(), nil)
+
+	flooder := unittest.IdentifierFixture()
+	honest := unittest.IdentifierFixture()
+	for i := 0; i < 50; i++ {
+		require.NoError(t, mq.Insert(qMessage(channels.SyncCommittee, flooder, i)))
+	}
+	require.NoError(t, mq.Insert(qMessage(channels.SyncCommittee, honest, 0)))
+
+	require.Equal(t, flooder, mq.Remove().(queue.QMessage).SenderID)
+	require.Equal(t, honest, mq.Remove().(queue.QMessage).SenderID)
+	require.Equal(t, flooder, mq.Remove().(queue.QMessage).SenderID)
+}
+
+// TestFairMessageQueue_Overflow evaluates that the buffer of a channel is bounded, and that the origin with the most
+// buffered messages is held responsible for an overflow: its messages are dropped and it is reported.
+func TestFairMessageQueue_Overflow(t *testing.T) {
+	cfg := queue.DefaultFairMessageQueueConfig()
+	cfg.ChannelCapacity = 3
+
+	var reported []flow.Identifier
+	mq := newFairQueue(t, cfg, func(channel channels.Channel, originID flow.Identifier) {
+		require.Equal(t, channels.SyncCommittee, channel)
+		reported = append(reported, originID)
+	})
+
+	flooder := unittest.IdentifierFixture()
+	honest := unittest.IdentifierFixture()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, mq.Insert(qMessage(channels.SyncCommittee, flooder, i)))
+	}
+
+	// the flooder overflows the buffer, its message is dropped
+	require.NoError(t, mq.Insert(qMessage(channels.SyncCommittee, flooder, 3)))
+	require.Equal(t, []flow.Identifier{flooder}, reported)
+	require.Equal(t, 3, mq.Len())
+
+	// the message of the honest origin evicts the newest message of the flooder
+	require.NoError(t, mq.Insert(qMessage(channels.SyncCommittee, honest, 0)))
+	require.Equal(t, []flow.Identifier{flooder, flooder}, reported)
+	require.Equal(t, 3, mq.Len())
+
+	// other channels are not affected by the overflow
+	require.NoError(t, mq.Insert(qMessage(channels.ConsensusCommittee, flooder, 0)))
+	require.Equal(t, 4, mq.Len())
+
+	var served []queue.QMessage
+	for i := 0; i < 4; i++ {
+		served = append(served, mq.Remove().(queue.QMessage))
+	}
+	require.ElementsMatch(t, []queue.QMessage{
+		qMessage(channels.SyncCommittee, flooder, 0),
+		qMessage(channels.SyncCommittee, flooder, 1),
+		qMessage(channels.SyncCommittee, honest, 0),
+		qMessage(channels.ConsensusCommittee, flooder, 0),
+	}, served)
+	require.Equal(t, 0, mq.Len())
+}
+
+// TestFairMessageQueue_Shutdown evaluates that blocked readers are released when the queue is shut down.
+func TestFairMessageQueue_Shutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mq, err := queue.NewFairMessageQueue(ctx, fixedEventPriority, metrics.NewNoopCollector(), queue.DefaultFairMessageQueueConfig(), nil)
+	require.NoError(t, err)
+
+	removed := make(chan interface{})
+	go func() {
+		removed <- mq.Remove()
+	}()
+	cancel()
+
+	unittest.RequireReturnsBefore(t, func() {
+		require.Nil(t, <-removed)
+	}, time.Second, "reader was not released on shutdown")
+	require.Error(t, mq.Insert(qMessage(channels.SyncCommittee, unittest.IdentifierFixture(), 0)))
+}
+
+// TestFairMessageQueue_InvalidCapacity evaluates that a queue which would drop all messages is rejected.
+func TestFairMessageQueue_InvalidCapacity(t *testing.T) {
+	cfg := queue.DefaultFairMessageQueueConfig()
+	cfg.ChannelCapacity = 0
+
+	_, err := queue.NewFairMessageQueue(context.Background(), fixedEventPriority, metrics.NewNoopCollector(), cfg, nil)
+	require.Error(t, err)
+}
+
+func newFairQueue(t *testing.T, cfg *queue.FairMessageQueueConfig, onOverflow queue.OverflowConsumer) *queue.FairMessageQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	mq, err := queue.NewFairMessageQueue(ctx, fixedEventPriority, metrics.NewNoopCollector(), cfg, onOverflow)
+	require.NoError(t, err)
+	return mq
+}
+
+// fixedEventPriority assigns the same priority to all messages, so that messages of an origin are served in
+// insertion order.
+func fixedEventPriority(interface{}) (queue.Priority, error) {
+	return queue.MediumPriority, nil
+}
+
+// qMessage returns a queue message of the given channel and origin, distinguished by its sequence number.
+func qMessage(channel channels.Channel, originID flow.Identifier, seq int) queue.QMessage {
+	return queue.QMessage{
+		Payload:  seq,
+		Size:     1,
+		Target:   channel,
+		SenderID: originID,
+	}
+}
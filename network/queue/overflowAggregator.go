@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// DefaultOverflowReportInterval is the default interval at which aggregated overflows are reported.
+const DefaultOverflowReportInterval = 10 * time.Second
+
+// AggregatedOverflowConsumer is notified about the origin of messages dropped on a channel during an interval,
+// together with the number of overflows it caused.
+type AggregatedOverflowConsumer func(channel channels.Channel, originID flow.Identifier, overflows uint64)
+
+// overflowSource is the origin of overflows of the buffer of a channel.
+type overflowSource struct {
+	channel  channels.Channel
+	originID flow.Identifier
+}
+
+// OverflowAggregator counts the overflows of a message queue per channel and origin, and notifies its consumer about
+// each source of overflows at most once per interval. This keeps the cost of handling overflows independent of the
+// rate at which a flooding origin sends messages. The counts are reset every interval, hence the number of tracked
+// sources is bounded by the number of distinct sources within an interval.
+// The implementation is concurrency-safe.
+type OverflowAggregator struct {
+	mu        sync.Mutex
+	overflows map[overflowSource]uint64
+	consumer  AggregatedOverflowConsumer
+}
+
+// NewOverflowAggregator creates an overflow aggregator reporting to the given consumer.
+func NewOverflowAggregator(consumer AggregatedOverflowConsumer) *OverflowAggregator {
+	return &OverflowAggregator{
+		overflows: make(map[overflowSource]uint64),
+		consumer:  consumer,
+	}
+}
+
+// OnOverflow counts an overflow of the buffer of the given channel caused by the given origin. It is an
+// OverflowConsumer of the message queue, and is non-blocking.
+func (a *OverflowAggregator) OnOverflow(channel channels.Channel, originID flow.Identifier) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.overflows[overflowSource{channel: channel, originID: originID}]++
+}
+
+// Flush notifies the consumer about every source of overflows since the last flush, and resets the counts.
+// The consumer is called outside of the lock of the aggregator.
+func (a *OverflowAggregator) Flush() {
+	a.mu.Lock()
+	overflows := a.overflows
+	a.overflows = make(map[overflowSource]uint64)
+	a.mu.Unlock()
+
+	for source, count := range overflows {
+		a.consumer(source.channel, source.originID, count)
+	}
+}
+
+// Run flushes the aggregated overflows at the given interval until the context is done.
+func (a *OverflowAggregator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Flush()
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/queue"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestOverflowAggregator_Flush evaluates that overflows are reported once per channel and origin with their count,
+// and that the counts are reset by a flush.
+func TestOverflowAggregator_Flush(t *testing.T) {
+	type source struct {
+		channel  channels.Channel
+		originID flow.Identifier
+	}
+	reported := make(map[source]uint64)
+	calls := 0
+	aggregator := queue.NewOverflowAggregator(func(channel channels.Channel, originID flow.Identifier, overflows uint64) {
+		reported[source{channel, originID}] = overflows
+		calls++
+	})
+
+	flooder := unittest.IdentifierFixture()
+	other := unittest.IdentifierFixture()
+	for i := 0; i < 1000; i++ {
+		aggregator.OnOverflow(channels.SyncCommittee, flooder)
+	}
+	aggregator.OnOverflow(channels.ConsensusCommittee, flooder)
+	aggregator.OnOverflow(channels.SyncCommittee, other)
+
+	aggregator.Flush()
+	require.Equal(t, 3, calls)
+	require.Equal(t, map[source]uint64{
+		{channels.SyncCommittee, flooder}:      1000,
+		{channels.ConsensusCommittee, flooder}: 1,
+		{channels.SyncCommittee, other}:        1,
+	}, reported)
+
+	// nothing is reported without new overflows
+	aggregator.Flush()
+	require.Equal(t, 3, calls)
+}
@@ -79,3 +79,7 @@ func (r *RelayNetwork) RegisterBlobService(channel channels.Channel, store datas
 func (r *RelayNetwork) RegisterPingService(pid protocol.ID, provider network.PingInfoProvider) (network.PingService, error) {
 	return r.originNet.RegisterPingService(pid, provider)
 }
+
+func (r *RelayNetwork) RegisterRequestService(channel channels.Channel, handler network.RequestHandler, opts ...network.RequestServiceOption) (network.RequestService, error) {
+	return r.originNet.RegisterRequestService(channel, handler, opts...)
+}
@@ -0,0 +1,191 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+const (
+	// DefaultRequestTimeout is the default maximum time a request may take, from opening the stream until the response
+	// is read, if the context of the request carries no earlier deadline.
+	DefaultRequestTimeout = 10 * time.Second
+
+	// DefaultMaxRequestSize is the default maximum size of an encoded request.
+	DefaultMaxRequestSize = 1 << 20 // 1 mb
+
+	// DefaultMaxResponseSize is the default maximum size of an encoded response.
+	DefaultMaxResponseSize = 10 << 20 // 10 mb
+
+	// DefaultMaxConcurrentInboundRequestsPerPeer is the default maximum number of inbound requests of a single peer that
+	// a request service handles concurrently.
+	DefaultMaxConcurrentInboundRequestsPerPeer = 4
+
+	// DefaultMaxConcurrentOutboundRequests is the default maximum number of outbound requests of a request service
+	// that are in flight concurrently.
+	DefaultMaxConcurrentOutboundRequests = 64
+)
+
+// RequestHandler handles the inbound requests of a request service.
+type RequestHandler interface {
+	// HandleRequest handles the request received from the given origin on the given channel, and returns the response
+	// to send back to the origin. The context is canceled when the deadline of the request expires, or the requester
+	// abandons the request.
+	// Any returned error is sent back to the requester as a failed request, and is considered benign.
+	HandleRequest(ctx context.Context, channel channels.Channel, originID flow.Identifier, request interface{}) (interface{}, error)
+}
+
+// RequestHandlerFunc is an adapter to use an ordinary function as a RequestHandler.
+type RequestHandlerFunc func(ctx context.Context, channel channels.Channel, originID flow.Identifier, request interface{}) (interface{}, error)
+
+// HandleRequest calls f(ctx, channel, originID, request).
+func (f RequestHandlerFunc) HandleRequest(ctx context.Context, channel channels.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	return f(ctx, channel, originID, request)
+}
+
+// RequestService carries typed requests and their responses over dedicated unicast streams on a single channel. In
+// contrast to the fire-and-forget messages of a Conduit, each request is matched with its response by the stream it is
+// sent on, hence engines do not need to track nonces or timeouts of their own.
+type RequestService interface {
+	// Request sends the request to the target node and waits for its response. The request is abandoned when the
+	// context is canceled or its deadline expires, and at the latest after the timeout of the service.
+	// All errors returned from this function can be considered benign, in particular:
+	//   - ErrRequestUnsupported: if the target node does not serve requests on the channel of the service.
+	//   - ErrRequestFailed: if the target node failed to handle the request.
+	//   - ErrRequestRejected: if the target node rejected the request, e.g., because it is busy.
+	//   - context.Canceled / context.DeadlineExceeded: if the request was abandoned.
+	Request(ctx context.Context, targetID flow.Identifier, request interface{}) (interface{}, error)
+}
+
+// RequestServiceConfig is the configuration of a request service.
+type RequestServiceConfig struct {
+	// Timeout is the maximum time a request may take, if the context of the request carries no earlier deadline. It is
+	// also the maximum time an inbound request may take to be handled.
+	Timeout time.Duration
+	// MaxRequestSize is the maximum size of an encoded request, larger requests are neither sent nor read.
+	MaxRequestSize int
+	// MaxResponseSize is the maximum size of an encoded response, larger responses are neither sent nor read.
+	MaxResponseSize int
+	// MaxConcurrentInboundRequestsPerPeer is the maximum number of inbound requests of a single peer handled
+	// concurrently, further inbound requests of the peer are rejected until one of its requests is handled.
+	MaxConcurrentInboundRequestsPerPeer int
+	// MaxConcurrentOutboundRequests is the maximum number of outbound requests in flight concurrently, further
+	// outbound requests wait until a request completes or their context is done.
+	MaxConcurrentOutboundRequests int
+}
+
+// DefaultRequestServiceConfig returns the default configuration of a request service.
+func DefaultRequestServiceConfig() *RequestServiceConfig {
+	return &RequestServiceConfig{
+		Timeout:                             DefaultRequestTimeout,
+		MaxRequestSize:                      DefaultMaxRequestSize,
+		MaxResponseSize:                     DefaultMaxResponseSize,
+		MaxConcurrentInboundRequestsPerPeer: DefaultMaxConcurrentInboundRequestsPerPeer,
+		MaxConcurrentOutboundRequests:       DefaultMaxConcurrentOutboundRequests,
+	}
+}
+
+type RequestServiceOption func(*RequestServiceConfig)
+
+// WithRequestTimeout sets the maximum time a request may take.
+func WithRequestTimeout(timeout time.Duration) RequestServiceOption {
+	return func(cfg *RequestServiceConfig) {
+		cfg.Timeout = timeout
+	}
+}
+
+// WithMaxRequestSize sets the maximum size of an encoded request.
+func WithMaxRequestSize(size int) RequestServiceOption {
+	return func(cfg *RequestServiceConfig) {
+		cfg.MaxRequestSize = size
+	}
+}
+
+// WithMaxResponseSize sets the maximum size of an encoded response.
+func WithMaxResponseSize(size int) RequestServiceOption {
+	return func(cfg *RequestServiceConfig) {
+		cfg.MaxResponseSize = size
+	}
+}
+
+// WithMaxConcurrentRequests sets the maximum number of inbound requests of a single peer handled concurrently, and the
+// maximum number of outbound requests in flight concurrently.
+func WithMaxConcurrentRequests(inboundPerPeer int, outbound int) RequestServiceOption {
+	return func(cfg *RequestServiceConfig) {
+		cfg.MaxConcurrentInboundRequestsPerPeer = inboundPerPeer
+		cfg.MaxConcurrentOutboundRequests = outbound
+	}
+}
+
+// ErrRequestUnsupported indicates that the target node of a request does not serve requests on the channel of the
+// request service, e.g., because it runs an older software version.
+type ErrRequestUnsupported struct {
+	targetID flow.Identifier
+	err      error
+}
+
+func (e ErrRequestUnsupported) Error() string {
+	return fmt.Sprintf("target %s does not serve requests: %v", e.targetID, e.err)
+}
+
+func (e ErrRequestUnsupported) Unwrap() error {
+	return e.err
+}
+
+// NewRequestUnsupportedErr returns a new ErrRequestUnsupported.
+func NewRequestUnsupportedErr(targetID flow.Identifier, err error) ErrRequestUnsupported {
+	return ErrRequestUnsupported{targetID: targetID, err: err}
+}
+
+// IsErrRequestUnsupported returns whether an error is ErrRequestUnsupported.
+func IsErrRequestUnsupported(err error) bool {
+	var e ErrRequestUnsupported
+	return errors.As(err, &e)
+}
+
+// ErrRequestFailed indicates that the target node of a request failed to handle it.
+type ErrRequestFailed struct {
+	targetID flow.Identifier
+	reason   string
+}
+
+func (e ErrRequestFailed) Error() string {
+	return fmt.Sprintf("request to %s failed: %s", e.targetID, e.reason)
+}
+
+// NewRequestFailedErr returns a new ErrRequestFailed.
+func NewRequestFailedErr(targetID flow.Identifier, reason string) ErrRequestFailed {
+	return ErrRequestFailed{targetID: targetID, reason: reason}
+}
+
+// IsErrRequestFailed returns whether an error is ErrRequestFailed.
+func IsErrRequestFailed(err error) bool {
+	var e ErrRequestFailed
+	return errors.As(err, &e)
+}
+
+// ErrRequestRejected indicates that the target node of a request rejected it without handling it, e.g., because it
+// handles too many requests concurrently, or the requester is not authorized to send the request.
+type ErrRequestRejected struct {
+	targetID flow.Identifier
+	reason   string
+}
+
+func (e ErrRequestRejected) Error() string {
+	return fmt.Sprintf("request to %s rejected: %s", e.targetID, e.reason)
+}
+
+// NewRequestRejectedErr returns a new ErrRequestRejected.
+func NewRequestRejectedErr(targetID flow.Identifier, reason string) ErrRequestRejected {
+	return ErrRequestRejected{targetID: targetID, reason: reason}
+}
+
+// IsErrRequestRejected returns whether an error is ErrRequestRejected.
+func IsErrRequestRejected(err error) bool {
+	var e ErrRequestRejected
+	return errors.As(err, &e)
+}
@@ -79,6 +79,14 @@ func (b *Buffer) Deliver(sendOne func(*PendingMessage) bool) {
 	b.pending = append(unsent, b.pending...)
 }
 
+// hasPending returns true if the buffer holds pending messages.
+func (b *Buffer) hasPending() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	return len(b.pending) > 0
+}
+
 // takeAll takes all pending messages from the buffer and empties the buffer.
 func (b *Buffer) takeAll() []*PendingMessage {
 	b.Lock()
@@ -1,6 +1,7 @@
 package stub
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -17,14 +18,83 @@ type Hub struct {
 	sync.RWMutex
 	networks map[flow.Identifier]*Network
 	Buffer   *Buffer
+	// links models the links between the networks, nil if messages are buffered for delivery as soon as they are sent.
+	links *LinkModel
+}
+
+// WithLinkModel sets the link model of the Hub. Messages sent by the Network instances attached to the Hub are then
+// only buffered for delivery once they arrive according to the link model, as the clock of the link model advances.
+func WithLinkModel(links *LinkModel) func(*Hub) {
+	return func(h *Hub) {
+		h.links = links
+	}
 }
 
 // NewNetworkHub creates and returns a new Hub instance.
-func NewNetworkHub() *Hub {
-	return &Hub{
+func NewNetworkHub(opts ...func(*Hub)) *Hub {
+	h := &Hub{
 		networks: make(map[flow.Identifier]*Network),
 		Buffer:   NewBuffer(),
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// LinkModel returns the link model of the Hub, nil if the Hub has none.
+func (h *Hub) LinkModel() *LinkModel {
+	return h.links
+}
+
+// Advance advances the clock of the link model by the given duration, and buffers the messages which arrived in the
+// meantime for delivery, in the order of their arrival. It is a no-op if the Hub has no link model.
+func (h *Hub) Advance(d time.Duration) {
+	if h.links == nil {
+		return
+	}
+	for _, msg := range h.links.advance(d) {
+		h.Buffer.Save(msg)
+	}
+}
+
+// RunFor advances the clock of the link model by the given duration in steps of the given size. After each step, the
+// messages which arrived are delivered synchronously on processing, including the messages sent while processing them
+// which arrive within the same step.
+// The Hub must have a link model. Panics if the step is not positive, as the clock would never advance.
+func (h *Hub) RunFor(duration time.Duration, step time.Duration) {
+	if step <= 0 {
+		panic(fmt.Sprintf("step must be positive, got %v", step))
+	}
+	for elapsed := time.Duration(0); elapsed < duration; elapsed += step {
+		h.Advance(step)
+		for h.Buffer.hasPending() {
+			// all networks share the buffer of the hub, so any of them delivers all buffered messages.
+			h.RLock()
+			var network *Network
+			for _, net := range h.networks {
+				network = net
+				break
+			}
+			h.RUnlock()
+			if network == nil {
+				return
+			}
+			network.DeliverAll(true)
+
+			// messages sent while processing which arrive without delay are delivered within the same step.
+			h.Advance(0)
+		}
+	}
+}
+
+// InFlight returns the number of messages sent but not yet arrived according to the link model of the Hub.
+func (h *Hub) InFlight() int {
+	if h.links == nil {
+		return 0
+	}
+	return h.links.pending()
 }
 
 // DeliverAll delivers all the buffered messages in the Network instances attached to the Hub
@@ -0,0 +1,350 @@
+package stub
+
+import (
+	"container/heap"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/encoding/json"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// LatencyDistribution samples the latency of a message on a link from the given source of randomness.
+type LatencyDistribution func(rng *rand.Rand) time.Duration
+
+// ConstantLatency returns a latency distribution that always yields the given latency.
+func ConstantLatency(latency time.Duration) LatencyDistribution {
+	return func(*rand.Rand) time.Duration {
+		return latency
+	}
+}
+
+// UniformLatency returns a latency distribution that yields latencies uniformly distributed in [min, max].
+func UniformLatency(min time.Duration, max time.Duration) LatencyDistribution {
+	return func(rng *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(rng.Int63n(int64(max-min)+1))
+	}
+}
+
+// NormalLatency returns a latency distribution that yields normally distributed latencies with the given mean and
+// standard deviation, truncated at zero.
+func NormalLatency(mean time.Duration, stddev time.Duration) LatencyDistribution {
+	return func(rng *rand.Rand) time.Duration {
+		latency := time.Duration(rng.NormFloat64()*float64(stddev)) + mean
+		if latency < 0 {
+			return 0
+		}
+		return latency
+	}
+}
+
+// Link describes the conditions of the directed link from one node to another.
+type Link struct {
+	// Latency is the distribution of the latency of messages on the link, nil for no latency. As the latency of each
+	// message is sampled independently, messages may be reordered on the link.
+	Latency LatencyDistribution
+	// DropRate is the probability in [0, 1] of a message on the link being dropped.
+	DropRate float64
+	// Bandwidth is the capacity of the link in bytes per second, zero for unlimited capacity. Messages on a link with
+	// limited capacity are transmitted one after another, the size of a message being the size of its JSON encoding.
+	Bandwidth uint64
+}
+
+// LinkModel models the links between the nodes of a Hub, which delays, drops and reorders the messages exchanged by the
+// nodes accordingly, and splits the nodes into partitions that cannot communicate with each other.
+//
+// The link model runs on a simulated clock, which only advances when the Hub is advanced, and draws all randomness
+// from a seeded source. Hence, as long as the engines attached to the Hub process messages synchronously and
+// deterministically, a test using a link model behaves the same way on every run for a given seed.
+type LinkModel struct {
+	sync.Mutex
+	now         time.Time
+	rng         *rand.Rand
+	defaultLink Link
+	links       map[linkID]Link
+	busyUntil   map[linkID]time.Time // time until which a link with limited bandwidth is busy transmitting
+	// partition maps the nodes of the current partition to the index of their group, nil if there is no partition.
+	partition map[flow.Identifier]int
+	schedule  []*partitionChange // scheduled partitions and heals, ordered by their time
+	inFlight  inFlightQueue
+	sequence  uint64 // sequence number of the messages sent, used to order messages arriving at the same time
+}
+
+type linkID struct {
+	from flow.Identifier
+	to   flow.Identifier
+}
+
+// partitionChange is a scheduled change of the partition of the nodes, groups is nil for healing the partition.
+type partitionChange struct {
+	at     time.Time
+	groups []flow.IdentifierList
+}
+
+// NewLinkModel returns a new link model, which applies the given default link to every pair of nodes without a link
+// of their own.
+// Args:
+//   - seed: the seed of the source of randomness of the link model.
+//   - start: the initial time of the simulated clock.
+//   - defaultLink: the link between nodes without a link of their own.
+//
+// Returns:
+//   - *LinkModel: a new link model, without any partition.
+func NewLinkModel(seed int64, start time.Time, defaultLink Link) *LinkModel {
+	return &LinkModel{
+		now:         start,
+		rng:         rand.New(rand.NewSource(seed)),
+		defaultLink: defaultLink,
+		links:       make(map[linkID]Link),
+		busyUntil:   make(map[linkID]time.Time),
+	}
+}
+
+// Now returns the current time of the simulated clock.
+func (m *LinkModel) Now() time.Time {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.now
+}
+
+// SetLink sets the link from one node to another. The link in the opposite direction is not affected.
+func (m *LinkModel) SetLink(from flow.Identifier, to flow.Identifier, link Link) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.links[linkID{from: from, to: to}] = link
+}
+
+// SetLinks sets the links in both directions between each pair of the given nodes.
+func (m *LinkModel) SetLinks(nodeIDs flow.IdentifierList, link Link) {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, from := range nodeIDs {
+		for _, to := range nodeIDs {
+			if from != to {
+				m.links[linkID{from: from, to: to}] = link
+			}
+		}
+	}
+}
+
+// Partition immediately splits the nodes into the given groups. Messages between nodes of different groups are
+// dropped, including messages in flight that arrive while the partition lasts. Nodes which are not part of any group
+// keep communicating with all nodes. A new partition replaces the current one.
+func (m *LinkModel) Partition(groups ...flow.IdentifierList) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.setPartition(groups)
+}
+
+// Heal immediately heals the current partition.
+func (m *LinkModel) Heal() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.partition = nil
+}
+
+// SchedulePartition schedules the nodes to be split into the given groups at the given time of the simulated clock,
+// see Partition.
+func (m *LinkModel) SchedulePartition(at time.Time, groups ...flow.IdentifierList) {
+	m.scheduleChange(&partitionChange{at: at, groups: groups})
+}
+
+// ScheduleHeal schedules the partition to be healed at the given time of the simulated clock.
+func (m *LinkModel) ScheduleHeal(at time.Time) {
+	m.scheduleChange(&partitionChange{at: at})
+}
+
+func (m *LinkModel) scheduleChange(change *partitionChange) {
+	m.Lock()
+	defer m.Unlock()
+
+	// changes scheduled for the same time are applied in the order they were scheduled.
+	i := len(m.schedule)
+	for i > 0 && m.schedule[i-1].at.After(change.at) {
+		i--
+	}
+	m.schedule = append(m.schedule, nil)
+	copy(m.schedule[i+1:], m.schedule[i:])
+	m.schedule[i] = change
+}
+
+// send puts the message in flight to each of its targets, unless the message is dropped on the link to the target.
+func (m *LinkModel) send(msg *PendingMessage) {
+	m.Lock()
+	defer m.Unlock()
+
+	var size int
+	for _, targetID := range msg.TargetIDs {
+		id := linkID{from: msg.From, to: targetID}
+		link, ok := m.links[id]
+		if !ok {
+			link = m.defaultLink
+		}
+
+		if m.partitioned(id) || (link.DropRate > 0 && m.rng.Float64() < link.DropRate) {
+			continue
+		}
+
+		departure := m.now
+		if link.Bandwidth > 0 {
+			if size == 0 {
+				size = messageSize(msg)
+			}
+			if busyUntil := m.busyUntil[id]; busyUntil.After(departure) {
+				departure = busyUntil
+			}
+			departure = departure.Add(time.Duration(uint64(size) * uint64(time.Second) / link.Bandwidth))
+			m.busyUntil[id] = departure
+		}
+
+		arrival := departure
+		if link.Latency != nil {
+			arrival = arrival.Add(link.Latency(m.rng))
+		}
+
+		m.sequence++
+		heap.Push(&m.inFlight, &inFlightMessage{
+			arrival:  arrival,
+			sequence: m.sequence,
+			msg: &PendingMessage{
+				From:      msg.From,
+				Channel:   msg.Channel,
+				Event:     msg.Event,
+				TargetIDs: []flow.Identifier{targetID},
+			},
+		})
+	}
+}
+
+// advance advances the simulated clock by the given duration, applying the scheduled partition changes on the way.
+// It returns the messages which arrived in the meantime, in the order of their arrival.
+func (m *LinkModel) advance(d time.Duration) []*PendingMessage {
+	m.Lock()
+	defer m.Unlock()
+
+	until := m.now.Add(d)
+	var arrived []*PendingMessage
+	for {
+		var next *inFlightMessage
+		if m.inFlight.Len() > 0 && !m.inFlight[0].arrival.After(until) {
+			next = m.inFlight[0]
+		}
+
+		// partition changes take effect before messages arriving at the same time.
+		if len(m.schedule) > 0 && !m.schedule[0].at.After(until) && (next == nil || !m.schedule[0].at.After(next.arrival)) {
+			change := m.schedule[0]
+			m.schedule = m.schedule[1:]
+			m.now = laterOf(m.now, change.at)
+			m.setPartition(change.groups)
+			continue
+		}
+		if next == nil {
+			break
+		}
+
+		heap.Pop(&m.inFlight)
+		m.now = laterOf(m.now, next.arrival)
+		if !m.partitioned(linkID{from: next.msg.From, to: next.msg.TargetIDs[0]}) {
+			arrived = append(arrived, next.msg)
+		}
+	}
+	m.now = laterOf(m.now, until)
+
+	return arrived
+}
+
+// pending returns the number of messages in flight.
+func (m *LinkModel) pending() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.inFlight.Len()
+}
+
+// setPartition replaces the current partition by the given groups, nil for healing the partition.
+// Note: this function is not thread-safe and should be called with the lock held.
+func (m *LinkModel) setPartition(groups []flow.IdentifierList) {
+	if groups == nil {
+		m.partition = nil
+		return
+	}
+	m.partition = make(map[flow.Identifier]int)
+	for i, group := range groups {
+		for _, nodeID := range group {
+			m.partition[nodeID] = i
+		}
+	}
+}
+
+// partitioned returns true if the nodes of the link are in different groups of the current partition.
+// Note: this function is not thread-safe and should be called with the lock held.
+func (m *LinkModel) partitioned(id linkID) bool {
+	fromGroup, ok := m.partition[id.from]
+	if !ok {
+		return false
+	}
+	toGroup, ok := m.partition[id.to]
+	if !ok {
+		return false
+	}
+	return fromGroup != toGroup
+}
+
+// messageSize returns the size of the JSON encoding of the event of the message, which serves as an estimate of its size
+// on the wire.
+func messageSize(msg *PendingMessage) int {
+	payload, err := json.NewMarshaler().Marshal(msg.Event)
+	if err != nil {
+		return 0
+	}
+	return len(payload)
+}
+
+func laterOf(a time.Time, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+type inFlightMessage struct {
+	arrival  time.Time
+	sequence uint64
+	msg      *PendingMessage
+}
+
+// inFlightQueue is a min-heap of the messages in flight, ordered by their arrival and then by the order they were sent.
+type inFlightQueue []*inFlightMessage
+
+func (q inFlightQueue) Len() int { return len(q) }
+
+func (q inFlightQueue) Less(i, j int) bool {
+	if q[i].arrival.Equal(q[j].arrival) {
+		return q[i].sequence < q[j].sequence
+	}
+	return q[i].arrival.Before(q[j].arrival)
+}
+
+func (q inFlightQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *inFlightQueue) Push(x interface{}) {
+	*q = append(*q, x.(*inFlightMessage))
+}
+
+func (q *inFlightQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
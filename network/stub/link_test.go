@@ -0,0 +1,171 @@
+package stub_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/stub"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestLinkModel_Latency evaluates that messages are only delivered once their latency elapsed on the simulated clock,
+// in the order of their arrival.
+func TestLinkModel_Latency(t *testing.T) {
+	links := stub.NewLinkModel(1, time.Unix(0, 0), stub.Link{Latency: stub.ConstantLatency(100 * time.Millisecond)})
+	hub := stub.NewNetworkHub(stub.WithLinkModel(links))
+	sender, receiver := newLinkedNodes(t, hub)
+
+	fast := unittest.IdentifierFixture()
+	links.SetLink(sender.nodeID, fast, stub.Link{Latency: stub.ConstantLatency(10 * time.Millisecond)})
+	fastReceiver := newLinkedNode(t, hub, fast)
+
+	sender.send(t, 1, receiver.nodeID)
+	sender.send(t, 2, fast)
+	require.Equal(t, 2, hub.InFlight())
+
+	hub.RunFor(50*time.Millisecond, 10*time.Millisecond)
+	require.Empty(t, receiver.received())
+	require.Equal(t, []uint64{2}, fastReceiver.received())
+
+	hub.RunFor(50*time.Millisecond, 10*time.Millisecond)
+	require.Equal(t, []uint64{1}, receiver.received())
+	require.Zero(t, hub.InFlight())
+	require.Equal(t, time.Unix(0, 0).Add(100*time.Millisecond), links.Now())
+}
+
+// TestLinkModel_Deterministic evaluates that messages are dropped and reordered the same way for the same seed.
+func TestLinkModel_Deterministic(t *testing.T) {
+	run := func(seed int64) []uint64 {
+		links := stub.NewLinkModel(seed, time.Unix(0, 0), stub.Link{
+			Latency:  stub.UniformLatency(0, time.Second),
+			DropRate: 0.5,
+		})
+		hub := stub.NewNetworkHub(stub.WithLinkModel(links))
+		sender, receiver := newLinkedNodes(t, hub)
+		for nonce := uint64(0); nonce < 100; nonce++ {
+			sender.send(t, nonce, receiver.nodeID)
+		}
+		hub.RunFor(time.Second, 10*time.Millisecond)
+		return receiver.received()
+	}
+
+	received := run(42)
+	require.Equal(t, received, run(42))
+	// roughly half of the messages are dropped, and the remaining ones are reordered.
+	require.Greater(t, len(received), 25)
+	require.Less(t, len(received), 75)
+	require.False(t, isSorted(received))
+}
+
+// TestLinkModel_Partition evaluates that messages between partitioned nodes are dropped until the partition heals,
+// including messages in flight when the partition starts.
+func TestLinkModel_Partition(t *testing.T) {
+	start := time.Unix(0, 0)
+	links := stub.NewLinkModel(1, start, stub.Link{Latency: stub.ConstantLatency(100 * time.Millisecond)})
+	hub := stub.NewNetworkHub(stub.WithLinkModel(links))
+	sender, receiver := newLinkedNodes(t, hub)
+
+	links.SchedulePartition(start.Add(50*time.Millisecond), flow.IdentifierList{sender.nodeID}, flow.IdentifierList{receiver.nodeID})
+	links.ScheduleHeal(start.Add(time.Second))
+
+	// in flight when the partition starts.
+	sender.send(t, 1, receiver.nodeID)
+	hub.RunFor(500*time.Millisecond, 10*time.Millisecond)
+
+	// sent during the partition.
+	sender.send(t, 2, receiver.nodeID)
+	hub.RunFor(500*time.Millisecond, 10*time.Millisecond)
+	require.Empty(t, receiver.received())
+
+	// sent after the partition healed.
+	sender.send(t, 3, receiver.nodeID)
+	hub.RunFor(100*time.Millisecond, 10*time.Millisecond)
+	require.Equal(t, []uint64{3}, receiver.received())
+}
+
+// TestLinkModel_Bandwidth evaluates that messages on a link with limited bandwidth are transmitted one after another.
+func TestLinkModel_Bandwidth(t *testing.T) {
+	links := stub.NewLinkModel(1, time.Unix(0, 0), stub.Link{})
+	hub := stub.NewNetworkHub(stub.WithLinkModel(links))
+	sender, receiver := newLinkedNodes(t, hub)
+
+	// the encoding of each message is less than 100 bytes, hence each message takes less than a second to transmit.
+	links.SetLink(sender.nodeID, receiver.nodeID, stub.Link{Bandwidth: 100})
+	for nonce := uint64(0); nonce < 10; nonce++ {
+		sender.send(t, nonce, receiver.nodeID)
+	}
+
+	hub.RunFor(time.Second, 100*time.Millisecond)
+	received := receiver.received()
+	require.NotEmpty(t, received)
+	require.Less(t, len(received), 10)
+
+	hub.RunFor(10*time.Second, 100*time.Millisecond)
+	require.Len(t, receiver.received(), 10)
+	require.True(t, isSorted(receiver.received()))
+}
+
+// TestLinkModel_RunForInvalidStep verifies that running the hub with a step which would never advance the clock panics.
+func TestLinkModel_RunForInvalidStep(t *testing.T) {
+	hub := stub.NewNetworkHub(stub.WithLinkModel(stub.NewLinkModel(1, time.Unix(0, 0), stub.Link{})))
+	require.Panics(t, func() { hub.RunFor(time.Second, 0) })
+}
+
+// linkedNode is a node of a Hub with a link model, recording the nonces of the entity requests it receives.
+type linkedNode struct {
+	nodeID  flow.Identifier
+	conduit network.Conduit
+
+	mu    sync.Mutex
+	nonce []uint64
+}
+
+var _ network.MessageProcessor = (*linkedNode)(nil)
+
+func newLinkedNodes(t *testing.T, hub *stub.Hub) (*linkedNode, *linkedNode) {
+	return newLinkedNode(t, hub, unittest.IdentifierFixture()), newLinkedNode(t, hub, unittest.IdentifierFixture())
+}
+
+func newLinkedNode(t *testing.T, hub *stub.Hub, nodeID flow.Identifier) *linkedNode {
+	node := &linkedNode{nodeID: nodeID}
+	net := stub.NewNetwork(t, nodeID, hub)
+	con, err := net.Register(channels.TestNetworkChannel, node)
+	require.NoError(t, err)
+	node.conduit = con
+	return node
+}
+
+func (n *linkedNode) send(t *testing.T, nonce uint64, targetID flow.Identifier) {
+	require.NoError(t, n.conduit.Unicast(&messages.EntityRequest{Nonce: nonce}, targetID))
+}
+
+func (n *linkedNode) Process(_ channels.Channel, _ flow.Identifier, event interface{}) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.nonce = append(n.nonce, event.(*messages.EntityRequest).Nonce)
+	return nil
+}
+
+func (n *linkedNode) received() []uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]uint64(nil), n.nonce...)
+}
+
+func isSorted(nonces []uint64) bool {
+	for i := 1; i < len(nonces); i++ {
+		if nonces[i] < nonces[i-1] {
+			return false
+		}
+	}
+	return true
+}
@@ -171,7 +171,12 @@ func (n *Network) MulticastOnChannel(channel channels.Channel, event interface{}
 // buffer saves the message into the pending buffer of the Network hub.
 // Buffering process of a message imitates its transmission over an unreliable Network.
 // In specific, it emulates the process of dispatching the message out of the sender.
+// If the hub has a link model, the message is only saved into the buffer once it arrives according to the link model.
 func (n *Network) buffer(msg *PendingMessage) {
+	if n.hub.links != nil {
+		n.hub.links.send(msg)
+		return
+	}
 	n.hub.Buffer.Save(msg)
 }
 
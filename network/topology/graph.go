@@ -0,0 +1,299 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onflow/flow-go/crypto/random"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/state/protocol/prg"
+)
+
+// Graph is the topology graph of the network, as constructed by the partial topology. It is an undirected graph over
+// the nodes of the network, i.e., if node A is a neighbor of node B, then node B is a neighbor of node A as well.
+//
+// The graph is the union of the subgraphs of all node groups. A group is either the set of nodes of a role, or the set
+// of nodes of the roles subscribing to a channel. The subgraph of each group is connected, so that nodes of the same
+// role, as well as nodes of different roles subscribing to the same channel, can reach each other over the topology.
+type Graph struct {
+	ids       map[flow.Identifier]*flow.Identity
+	neighbors map[flow.Identifier]map[flow.Identifier]struct{}
+}
+
+// NodeGroup is a set of nodes which the topology graph keeps connected.
+type NodeGroup struct {
+	// Name identifies the group, it is the list of roles of the group, e.g., "consensus,verification".
+	Name string
+	// Members are the nodes of the group, in canonical order.
+	Members flow.IdentityList
+	// FullyConnected indicates that every member of the group is a neighbor of every other member.
+	FullyConnected bool
+}
+
+// NodeGroups returns the groups of nodes which the topology graph keeps connected: one group per role, and one
+// group per distinct set of roles subscribing to a (non-public, non-cluster) channel. Groups consisting only of
+// nodes of the given fully connected roles are fully connected.
+func NodeGroups(ids flow.IdentityList, fullyConnected flow.RoleList) []NodeGroup {
+	roleSets := make(map[string]flow.RoleList)
+	for _, role := range flow.Roles() {
+		roleSets[role.String()] = flow.RoleList{role}
+	}
+	for _, channel := range channels.Channels() {
+		if channels.IsPublicChannel(channel) {
+			continue
+		}
+		roles, ok := channels.RolesByChannel(channel)
+		if !ok || len(roles) == 0 {
+			continue
+		}
+		roleSets[roleSetName(roles)] = roles
+	}
+
+	names := make([]string, 0, len(roleSets))
+	for name := range roleSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	groups := make([]NodeGroup, 0, len(names))
+	for _, name := range names {
+		roles := roleSets[name]
+		full := true
+		for _, role := range roles {
+			full = full && fullyConnected.Contains(role)
+		}
+		groups = append(groups, NodeGroup{
+			Name:           name,
+			Members:        ids.Filter(filter.HasRole(roles...)).Sort(order.Canonical),
+			FullyConnected: full,
+		})
+	}
+	return groups
+}
+
+// unicastCounterparts are the pairs of roles whose nodes exchange messages over unicast on the critical path of every
+// block, and hence are kept directly connected:
+//   - execution nodes request the collections of every block from the collection nodes guaranteeing them.
+//   - execution nodes send the chunk data packs of every result to the verification nodes assigned to its chunks.
+//
+// Collection nodes exchange cluster consensus votes and sync responses over unicast with the members of their cluster,
+// which are connected by the cluster groups of the graph, and consensus nodes exchange votes with each other, which are
+// fully connected by default. All other unicast messages are responses to requests, e.g., sync responses of consensus
+// nodes or collection responses to access nodes, whose connections are established on demand.
+var unicastCounterparts = [][2]flow.Role{
+	{flow.RoleCollection, flow.RoleExecution},
+	{flow.RoleExecution, flow.RoleVerification},
+}
+
+// UnicastCounterparts returns the pairs of roles whose nodes are always neighbors in the topology graph, as they
+// exchange messages over unicast on the critical path of every block. Each pair is ordered by role.
+func UnicastCounterparts() [][2]flow.Role {
+	pairs := make([][2]flow.Role, len(unicastCounterparts))
+	copy(pairs, unicastCounterparts)
+	return pairs
+}
+
+// roleSetName returns the name of the group of nodes of the given roles, independent of the order of the roles.
+func roleSetName(roles flow.RoleList) string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.String())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// BuildGraph deterministically constructs the topology graph of the given nodes from the given seed. All nodes
+// constructing the graph from the same identities and seed obtain the same graph, so that neighborhoods are mutual.
+//
+// The graph first contains the edges required by the protocols:
+//   - the members of fully connected groups are all neighbors.
+//   - the members of each of the given clusters are all neighbors, as they run the cluster consensus. Collection nodes
+//     of different clusters are only connected through the groups they share.
+//   - nodes of roles communicating over unicast on the critical path of every block (see UnicastCounterparts) are
+//     neighbors, as unicast messages are sent directly to their recipient rather than disseminated over the topology.
+//
+// Then, the other groups are connected, from the largest to the smallest, reusing the edges already in the graph: the
+// members are shuffled and consecutive members not connected within the group yet are connected, which guarantees the
+// connectivity of the group. Each member with fewer than `fanout` neighbors within the group then picks random
+// neighbors among the members, with a probability proportional to their weight, so that nodes with more stake are better
+// connected. As the groups overlap, the degree of a node is bounded by its required edges and a small multiple of the
+// fanout, independent of the size of the network.
+//
+// No errors are expected during normal operation.
+func BuildGraph(ids flow.IdentityList, clusters flow.ClusterList, seed []byte, fanout uint, fullyConnected flow.RoleList) (*Graph, error) {
+	g := &Graph{
+		ids:       make(map[flow.Identifier]*flow.Identity, len(ids)),
+		neighbors: make(map[flow.Identifier]map[flow.Identifier]struct{}, len(ids)),
+	}
+	for _, id := range ids {
+		g.ids[id.NodeID] = id
+	}
+
+	// the edges which must be in the graph are added first, so that the groups reuse them to reach their fanout.
+	groups := NodeGroups(ids, fullyConnected)
+	for _, group := range groups {
+		if group.FullyConnected {
+			g.connectFully(group.Members)
+		}
+	}
+	for _, cluster := range clusters {
+		g.connectFully(cluster.Filter(func(member *flow.Identity) bool {
+			_, ok := g.ids[member.NodeID]
+			return ok
+		}))
+	}
+	for _, pair := range unicastCounterparts {
+		for _, a := range ids.Filter(filter.HasRole(pair[0])) {
+			for _, b := range ids.Filter(filter.HasRole(pair[1])) {
+				g.connect(a.NodeID, b.NodeID)
+			}
+		}
+	}
+
+	// larger groups are connected first, as their edges are likely reused by the smaller groups they overlap with.
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Members) > len(groups[j].Members)
+	})
+	for _, group := range groups {
+		if group.FullyConnected || len(group.Members) < 2 {
+			continue
+		}
+		rng, err := prg.New(seed, prg.NetworkingTopology, []byte(group.Name))
+		if err != nil {
+			return nil, fmt.Errorf("could not create random generator for group %s: %w", group.Name, err)
+		}
+		err = g.connectGroup(rng, group.Members.Copy(), fanout)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect group %s: %w", group.Name, err)
+		}
+	}
+
+	return g, nil
+}
+
+// connectGroup connects the given members, reusing the edges already in the graph among them. The members are shuffled,
+// and each member is connected to the next one if they are not connected within the group yet, which guarantees the
+// connectivity of the group. Then, each member with fewer than fanout neighbors within the group picks stake-weighted
+// random neighbors among the members until it has fanout neighbors within the group.
+func (g *Graph) connectGroup(rng random.Rand, members flow.IdentityList, fanout uint) error {
+	err := rng.Shuffle(len(members), func(i, j int) {
+		members[i], members[j] = members[j], members[i]
+	})
+	if err != nil {
+		return fmt.Errorf("could not shuffle members: %w", err)
+	}
+
+	// components of the subgraph induced by the members, as a union-find over their indices in the shuffled order
+	index := make(map[flow.Identifier]int, len(members))
+	for i, member := range members {
+		index[member.NodeID] = i
+	}
+	parent := make([]int, len(members))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i, member := range members {
+		for neighbor := range g.neighbors[member.NodeID] {
+			if j, ok := index[neighbor]; ok {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+	for i := 0; i+1 < len(members); i++ {
+		a, b := find(i), find(i+1)
+		if a == b {
+			continue
+		}
+		parent[a] = b
+		g.connect(members[i].NodeID, members[i+1].NodeID)
+	}
+
+	target := int(fanout)
+	if target > len(members)-1 {
+		target = len(members) - 1
+	}
+	if target == 0 {
+		return nil
+	}
+
+	// cumulative weights of the members; one is added to every weight so that nodes without weight can be picked too.
+	cumulative := make([]uint64, len(members))
+	total := uint64(0)
+	for i, member := range members {
+		total += member.Weight + 1
+		cumulative[i] = total
+	}
+
+	for _, member := range members {
+		degree := 0
+		for neighbor := range g.neighbors[member.NodeID] {
+			if _, ok := index[neighbor]; ok {
+				degree++
+			}
+		}
+		// draws are repeated when a member picks itself or an existing neighbor; the number of draws is bounded so that
+		// a few heavy-weight members cannot stall the construction. The group is connected regardless.
+		for draws := 0; degree < target && draws < 10*target; draws++ {
+			r := rng.UintN(total)
+			other := members[sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > r })]
+			if other.NodeID == member.NodeID || g.IsNeighbor(member.NodeID, other.NodeID) {
+				continue
+			}
+			g.connect(member.NodeID, other.NodeID)
+			degree++
+		}
+	}
+	return nil
+}
+
+// connectFully connects every given member to every other given member.
+func (g *Graph) connectFully(members flow.IdentityList) {
+	for i, a := range members {
+		for _, b := range members[i+1:] {
+			g.connect(a.NodeID, b.NodeID)
+		}
+	}
+}
+
+// connect adds an undirected edge between the given nodes.
+func (g *Graph) connect(a, b flow.Identifier) {
+	if a == b {
+		return
+	}
+	if g.neighbors[a] == nil {
+		g.neighbors[a] = make(map[flow.Identifier]struct{})
+	}
+	if g.neighbors[b] == nil {
+		g.neighbors[b] = make(map[flow.Identifier]struct{})
+	}
+	g.neighbors[a][b] = struct{}{}
+	g.neighbors[b][a] = struct{}{}
+}
+
+// Neighbors returns the neighbors of the given node in canonical order. It returns an empty list if the node
+// is not part of the graph.
+func (g *Graph) Neighbors(nodeID flow.Identifier) flow.IdentityList {
+	neighbors := make(flow.IdentityList, 0, len(g.neighbors[nodeID]))
+	for id := range g.neighbors[nodeID] {
+		neighbors = append(neighbors, g.ids[id])
+	}
+	return neighbors.Sort(order.Canonical)
+}
+
+// IsNeighbor returns true if the given nodes are neighbors in the graph.
+func (g *Graph) IsNeighbor(a, b flow.Identifier) bool {
+	_, ok := g.neighbors[a][b]
+	return ok
+}
@@ -0,0 +1,275 @@
+package topology_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/topology"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// The tests in this file form the test harness of the topology graph of the partial topology: they construct graphs
+// for networks of different sizes and compositions, and many seeds, and check the connectivity properties the
+// networking layer relies on.
+
+// networkComposition is the number of nodes of each role in a test network.
+type networkComposition map[flow.Role]int
+
+var compositions = map[string]networkComposition{
+	"tiny":         {flow.RoleCollection: 1, flow.RoleConsensus: 1, flow.RoleExecution: 1, flow.RoleVerification: 1, flow.RoleAccess: 1},
+	"small":        {flow.RoleCollection: 6, flow.RoleConsensus: 3, flow.RoleExecution: 2, flow.RoleVerification: 3, flow.RoleAccess: 2},
+	"mainnet-like": {flow.RoleCollection: 70, flow.RoleConsensus: 30, flow.RoleExecution: 10, flow.RoleVerification: 60, flow.RoleAccess: 80},
+}
+
+// identitiesFixture creates identities of the given composition, with random weights.
+// Identities are created without keys, which the topology does not need.
+func identitiesFixture(composition networkComposition) flow.IdentityList {
+	ids := flow.IdentityList{}
+	for role, count := range composition {
+		for i := 0; i < count; i++ {
+			ids = append(ids, &flow.Identity{
+				NodeID: unittest.IdentifierFixture(),
+				Role:   role,
+				Weight: uint64(1 + len(ids)%7*100),
+			})
+		}
+	}
+	return ids
+}
+
+// clustersFixture assigns the collection nodes of the given identities round-robin to the given number of clusters.
+func clustersFixture(ids flow.IdentityList, n int) flow.ClusterList {
+	clusters := make(flow.ClusterList, n)
+	for i, collector := range ids.Filter(filter.HasRole(flow.RoleCollection)).Sort(order.Canonical) {
+		clusters[i%n] = append(clusters[i%n], collector)
+	}
+	return clusters
+}
+
+// requireFullyConnected requires every given member to be a neighbor of every other given member.
+func requireFullyConnected(t *testing.T, graph *topology.Graph, members flow.IdentityList, msgAndArgs ...interface{}) {
+	for _, a := range members {
+		for _, b := range members {
+			if a.NodeID != b.NodeID {
+				require.True(t, graph.IsNeighbor(a.NodeID, b.NodeID), msgAndArgs...)
+			}
+		}
+	}
+}
+
+// requireConnected requires the subgraph of the graph induced by the given members to be connected.
+func requireConnected(t *testing.T, graph *topology.Graph, members flow.IdentityList, msgAndArgs ...interface{}) {
+	if len(members) == 0 {
+		return
+	}
+	lookup := members.Lookup()
+	visited := map[flow.Identifier]struct{}{members[0].NodeID: {}}
+	queue := []flow.Identifier{members[0].NodeID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range graph.Neighbors(current) {
+			if _, ok := lookup[neighbor.NodeID]; !ok {
+				continue
+			}
+			if _, ok := visited[neighbor.NodeID]; ok {
+				continue
+			}
+			visited[neighbor.NodeID] = struct{}{}
+			queue = append(queue, neighbor.NodeID)
+		}
+	}
+	require.Len(t, visited, len(members), msgAndArgs...)
+}
+
+// requireMutual requires every neighborhood in the graph to be mutual.
+func requireMutual(t *testing.T, graph *topology.Graph, ids flow.IdentityList) {
+	for _, id := range ids {
+		for _, neighbor := range graph.Neighbors(id.NodeID) {
+			require.True(t, graph.IsNeighbor(neighbor.NodeID, id.NodeID))
+			require.NotEqual(t, id.NodeID, neighbor.NodeID, "node must not be its own neighbor")
+		}
+	}
+}
+
+// TestBuildGraph_Connectivity checks, for many seeds, that the subgraph of every group (every role, and the roles of
+// every channel) is connected, that neighborhoods are mutual, and that fully connected roles and the members of each
+// cluster are fully connected.
+func TestBuildGraph_Connectivity(t *testing.T) {
+	for name, composition := range compositions {
+		t.Run(name, func(t *testing.T) {
+			ids := identitiesFixture(composition)
+			clusters := clustersFixture(ids, 2)
+			for trial := 0; trial < 20; trial++ {
+				seed := unittest.RandomBytes(32)
+				graph, err := topology.BuildGraph(ids, clusters, seed, 2, flow.RoleList{flow.RoleConsensus})
+				require.NoError(t, err)
+
+				requireMutual(t, graph, ids)
+				for _, group := range topology.NodeGroups(ids, flow.RoleList{flow.RoleConsensus}) {
+					requireConnected(t, graph, group.Members, "group %s is not connected (seed %x)", group.Name, seed)
+				}
+				requireConnected(t, graph, ids, "graph is not connected (seed %x)", seed)
+
+				requireFullyConnected(t, graph, ids.Filter(filter.HasRole(flow.RoleConsensus)), "consensus nodes must be fully connected")
+				for i, cluster := range clusters {
+					requireFullyConnected(t, graph, cluster, "members of cluster %d must be fully connected", i)
+				}
+			}
+		})
+	}
+}
+
+// TestBuildGraph_Deterministic checks that the same identities and seed always result in the same graph, independent
+// of the order of the identities, and that a different seed results in a different graph.
+func TestBuildGraph_Deterministic(t *testing.T) {
+	ids := identitiesFixture(compositions["mainnet-like"])
+	seed := unittest.RandomBytes(32)
+
+	graph, err := topology.BuildGraph(ids, nil, seed, topology.DefaultFanout, nil)
+	require.NoError(t, err)
+
+	shuffled := ids.Copy()
+	for i, j := 0, len(shuffled)-1; i < j; i, j = i+1, j-1 {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	same, err := topology.BuildGraph(shuffled, nil, seed, topology.DefaultFanout, nil)
+	require.NoError(t, err)
+
+	other, err := topology.BuildGraph(ids, nil, unittest.RandomBytes(32), topology.DefaultFanout, nil)
+	require.NoError(t, err)
+
+	differs := false
+	for _, id := range ids {
+		require.Equal(t, graph.Neighbors(id.NodeID), same.Neighbors(id.NodeID))
+		if !assert.ObjectsAreEqual(graph.Neighbors(id.NodeID), other.Neighbors(id.NodeID)) {
+			differs = true
+		}
+	}
+	require.True(t, differs, "graphs of different seeds should differ")
+}
+
+// TestBuildGraph_Fanout checks that the degree of nodes is bounded by the fanout, i.e., that the topology is partial,
+// and that nodes with more weight are better connected.
+func TestBuildGraph_Fanout(t *testing.T) {
+	ids := flow.IdentityList{}
+	for i := 0; i < 200; i++ {
+		weight := uint64(1)
+		if i%10 == 0 {
+			weight = 1000 // one in ten nodes holds most of the stake
+		}
+		ids = append(ids, &flow.Identity{NodeID: unittest.IdentifierFixture(), Role: flow.RoleAccess, Weight: weight})
+	}
+
+	const fanout = 3
+	graph, err := topology.BuildGraph(ids, nil, unittest.RandomBytes(32), fanout, nil)
+	require.NoError(t, err)
+
+	heavy, light := 0, 0
+	for i, id := range ids {
+		degree := len(graph.Neighbors(id.NodeID))
+		if i%10 == 0 {
+			heavy += degree
+			continue
+		}
+		light += degree
+		// a light node picks at most fanout neighbors, and is rarely picked by others.
+		require.Less(t, degree, len(ids)/4)
+	}
+	assert.Greater(t, heavy/20, light/180, "nodes with more weight should have more neighbors on average")
+}
+
+// TestBuildGraph_UnicastCounterparts checks that the unicast counterparts are authorized to exchange messages over
+// unicast, and that their nodes are always neighbors, independent of the fanout.
+func TestBuildGraph_UnicastCounterparts(t *testing.T) {
+	pairs := topology.UnicastCounterparts()
+	require.Contains(t, pairs, [2]flow.Role{flow.RoleExecution, flow.RoleVerification})
+	require.Contains(t, pairs, [2]flow.Role{flow.RoleCollection, flow.RoleExecution})
+	for _, pair := range pairs {
+		require.True(t, unicastAuthorized(pair[0], pair[1]), "%s and %s nodes do not exchange messages over unicast", pair[0], pair[1])
+	}
+
+	ids := identitiesFixture(compositions["mainnet-like"])
+	graph, err := topology.BuildGraph(ids, nil, unittest.RandomBytes(32), 1, nil)
+	require.NoError(t, err)
+	for _, pair := range pairs {
+		for _, a := range ids.Filter(filter.HasRole(pair[0])) {
+			for _, b := range ids.Filter(filter.HasRole(pair[1])) {
+				if a.NodeID != b.NodeID {
+					require.True(t, graph.IsNeighbor(a.NodeID, b.NodeID), "%s and %s nodes must be neighbors", pair[0], pair[1])
+				}
+			}
+		}
+	}
+}
+
+// unicastAuthorized returns true if nodes of one of the given roles are authorized to send messages over unicast on a
+// channel which nodes of the other role subscribe to.
+func unicastAuthorized(a, b flow.Role) bool {
+	for _, config := range message.GetAllMessageAuthConfigs() {
+		for channel, auth := range config.Config {
+			if !auth.AllowedProtocols.Contains(message.ProtocolTypeUnicast) {
+				continue
+			}
+			roles, ok := channels.RolesByChannel(channel)
+			if !ok {
+				continue
+			}
+			if auth.AuthorizedRoles.Contains(a) && roles.Contains(b) || auth.AuthorizedRoles.Contains(b) && roles.Contains(a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestBuildGraph_DegreeBound checks, for realistic network sizes and many seeds, that the degree of each node is bounded
+// by the edges the graph must contain for its role, plus a number of edges proportional to the fanout:
+//   - collection nodes: the other members of their cluster, and the execution nodes.
+//   - consensus nodes: the other consensus nodes.
+//   - execution nodes: the collection and verification nodes.
+//   - verification nodes: the execution nodes.
+//   - access nodes: none.
+func TestBuildGraph_DegreeBound(t *testing.T) {
+	networks := map[string]networkComposition{
+		"mainnet-like": compositions["mainnet-like"],
+		"large":        {flow.RoleCollection: 200, flow.RoleConsensus: 100, flow.RoleExecution: 20, flow.RoleVerification: 200, flow.RoleAccess: 300},
+	}
+	const numClusters = 5
+	const fanout = topology.DefaultFanout
+
+	for name, composition := range networks {
+		t.Run(name, func(t *testing.T) {
+			ids := identitiesFixture(composition)
+			clusters := clustersFixture(ids, numClusters)
+			clusterSize := len(clusters[0])
+
+			required := map[flow.Role]int{
+				flow.RoleCollection:   clusterSize - 1 + composition[flow.RoleExecution],
+				flow.RoleConsensus:    composition[flow.RoleConsensus] - 1,
+				flow.RoleExecution:    composition[flow.RoleCollection] + composition[flow.RoleVerification],
+				flow.RoleVerification: composition[flow.RoleExecution],
+				flow.RoleAccess:       0,
+			}
+
+			for trial := 0; trial < 10; trial++ {
+				seed := unittest.RandomBytes(32)
+				graph, err := topology.BuildGraph(ids, clusters, seed, fanout, topology.DefaultPartialTopologyConfig().FullyConnectedRoles)
+				require.NoError(t, err)
+
+				for _, id := range ids {
+					degree := len(graph.Neighbors(id.NodeID))
+					require.LessOrEqual(t, degree, required[id.Role]+5*fanout,
+						"degree of %s node exceeds bound (seed %x)", id.Role, seed)
+				}
+			}
+		})
+	}
+}
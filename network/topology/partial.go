@@ -0,0 +1,174 @@
+package topology
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/events"
+	"github.com/onflow/flow-go/state/protocol/prg"
+)
+
+// DefaultFanout is the default number of neighbors each node has within each group of the partial topology, of which
+// the neighbors missing after the required edges of the graph are picked at random.
+const DefaultFanout = 5
+
+// PartialTopologyConfig is the configuration of the partial topology.
+type PartialTopologyConfig struct {
+	// Fanout is the number of neighbors each node has within each group, of which the neighbors missing after the
+	// required edges of the graph are picked at random.
+	Fanout uint
+	// FullyConnectedRoles are the roles whose nodes are all connected to each other. Consensus nodes are fully
+	// connected by default, as they send their votes to the leader of each view over unicast. Collection nodes are
+	// fully connected within their cluster regardless of this setting.
+	FullyConnectedRoles flow.RoleList
+}
+
+// DefaultPartialTopologyConfig returns the default configuration of the partial topology.
+func DefaultPartialTopologyConfig() PartialTopologyConfig {
+	return PartialTopologyConfig{
+		Fanout:              DefaultFanout,
+		FullyConnectedRoles: flow.RoleList{flow.RoleConsensus},
+	}
+}
+
+type PartialTopologyOption func(*PartialTopologyConfig)
+
+// WithFanout sets the number of random neighbors each node picks in each group.
+func WithFanout(fanout uint) PartialTopologyOption {
+	return func(cfg *PartialTopologyConfig) {
+		cfg.Fanout = fanout
+	}
+}
+
+// WithFullyConnectedRoles sets the roles whose nodes are all connected to each other.
+func WithFullyConnectedRoles(roles ...flow.Role) PartialTopologyOption {
+	return func(cfg *PartialTopologyConfig) {
+		cfg.FullyConnectedRoles = roles
+	}
+}
+
+// PartialTopology is a role- and stake-aware topology, which connects each node only to a subset of the other nodes,
+// while keeping the nodes of each role, and the nodes of the roles subscribing to each channel, connected.
+// The fanout of the node is its set of neighbors in the topology graph constructed by BuildGraph.
+//
+// The topology graph is seeded by the source of randomness of the current epoch, and connects the members of each
+// collection cluster of the current epoch. Since all nodes construct the same
+// graph from the same identities and seed, the fanout is mutual: if node A is in the fanout of node B, then node B is in
+// the fanout of node A. Hence, connections to nodes outside the fanout can be pruned by the peer manager on both ends.
+// The topology is re-seeded with the randomness and clusters of the new epoch at each epoch transition, so that the
+// graph changes from one epoch to the next.
+//
+// PartialTopology is safe for concurrent use.
+type PartialTopology struct {
+	events.Noop
+	log   zerolog.Logger
+	me    flow.Identifier
+	state protocol.State
+	cfg   PartialTopologyConfig
+
+	mu       sync.Mutex
+	seed     []byte
+	clusters flow.ClusterList
+	// fanout of the last invocation of Fanout, which is reused as long as neither the identities nor the seed change.
+	cachedChecksum flow.Identifier
+	cachedFanout   flow.IdentityList
+}
+
+var _ network.Topology = (*PartialTopology)(nil)
+var _ protocol.Consumer = (*PartialTopology)(nil)
+
+// NewPartialTopology creates a partial topology for the given node, seeded by the source of randomness of the
+// current epoch and connecting the collection clusters of the current epoch.
+// No errors are expected during normal operation.
+func NewPartialTopology(log zerolog.Logger, me flow.Identifier, state protocol.State, opts ...PartialTopologyOption) (*PartialTopology, error) {
+	cfg := DefaultPartialTopologyConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	seed, clusters, err := epochTopologyParams(state.Final().Epochs().Current())
+	if err != nil {
+		return nil, fmt.Errorf("could not get topology parameters of current epoch: %w", err)
+	}
+
+	return &PartialTopology{
+		log:      log.With().Str("component", "partial_topology").Logger(),
+		me:       me,
+		state:    state,
+		cfg:      cfg,
+		seed:     seed,
+		clusters: clusters,
+	}, nil
+}
+
+// Fanout returns the neighbors of this node in the topology graph of the given identities.
+// If the graph cannot be constructed, all given identities are returned, i.e., the node falls back to being fully
+// connected rather than pruning connections based on an incomplete graph.
+func (t *PartialTopology) Fanout(ids flow.IdentityList) flow.IdentityList {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	checksum := ids.Checksum()
+	if t.cachedFanout != nil && checksum == t.cachedChecksum {
+		return t.cachedFanout
+	}
+
+	graph, err := BuildGraph(ids, t.clusters, t.seed, t.cfg.Fanout, t.cfg.FullyConnectedRoles)
+	if err != nil {
+		// the seed is validated when it is set, so the construction only fails on a broken random generator.
+		t.log.Error().Err(err).Msg("could not build topology graph, falling back to fully connected topology")
+		return ids.Filter(func(id *flow.Identity) bool { return id.NodeID != t.me })
+	}
+	fanout := graph.Neighbors(t.me)
+
+	t.cachedChecksum = checksum
+	t.cachedFanout = fanout
+	t.log.Info().
+		Int("identities", len(ids)).
+		Int("fanout", len(fanout)).
+		Msg("topology graph constructed")
+	return fanout
+}
+
+// EpochTransition re-seeds the topology with the source of randomness of the new epoch, and connects the collection
+// clusters of the new epoch.
+func (t *PartialTopology) EpochTransition(newEpochCounter uint64, first *flow.Header) {
+	seed, clusters, err := epochTopologyParams(t.state.AtBlockID(first.ID()).Epochs().Current())
+	if err != nil {
+		t.log.Error().Err(err).
+			Uint64("epoch_counter", newEpochCounter).
+			Msg("could not get topology parameters of new epoch, keeping topology of previous epoch")
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seed = seed
+	t.clusters = clusters
+	t.cachedFanout = nil
+	t.log.Info().Uint64("epoch_counter", newEpochCounter).Msg("topology re-seeded for new epoch")
+}
+
+// epochTopologyParams returns the source of randomness seeding the topology graph of the given epoch, validated to
+// seed a random generator, and the collection clusters of the epoch.
+// No errors are expected during normal operation.
+func epochTopologyParams(epoch protocol.Epoch) ([]byte, flow.ClusterList, error) {
+	seed, err := epoch.RandomSource()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get random source: %w", err)
+	}
+	_, err = prg.New(seed, prg.NetworkingTopology, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create random generator from random source: %w", err)
+	}
+	clustering, err := epoch.Clustering()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get clustering: %w", err)
+	}
+	return seed, clustering, nil
+}
@@ -0,0 +1,95 @@
+package topology_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/network/topology"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// snapshotWithRandomSource returns a snapshot whose current epoch has the given source of randomness and clusters.
+func snapshotWithRandomSource(t *testing.T, seed []byte, clusters flow.ClusterList) *protocol.Snapshot {
+	epoch := protocol.NewEpoch(t)
+	epoch.On("RandomSource").Return(seed, nil)
+	epoch.On("Clustering").Return(clusters, nil)
+	epochs := protocol.NewEpochQuery(t)
+	epochs.On("Current").Return(epoch)
+	snapshot := protocol.NewSnapshot(t)
+	snapshot.On("Epochs").Return(epochs)
+	return snapshot
+}
+
+// TestPartialTopology checks that the fanout of the partial topology is mutual among all nodes, that it keeps the
+// unicast counterparts and cluster members of each node, and that the topology is re-seeded with the source of
+// randomness and the clusters of the new epoch at epoch transitions.
+func TestPartialTopology(t *testing.T) {
+	ids := identitiesFixture(networkComposition{
+		flow.RoleCollection:   10,
+		flow.RoleConsensus:    5,
+		flow.RoleExecution:    5,
+		flow.RoleVerification: 30,
+		flow.RoleAccess:       30,
+	})
+	seed := unittest.RandomBytes(32)
+	clusters := clustersFixture(ids, 2)
+
+	state := protocol.NewState(t)
+	state.On("Final").Return(snapshotWithRandomSource(t, seed, clusters))
+
+	topologies := make(map[flow.Identifier]*topology.PartialTopology, len(ids))
+	for _, id := range ids {
+		top, err := topology.NewPartialTopology(unittest.Logger(), id.NodeID, state, topology.WithFanout(2))
+		require.NoError(t, err)
+		topologies[id.NodeID] = top
+	}
+
+	for _, id := range ids {
+		fanout := topologies[id.NodeID].Fanout(ids)
+		require.NotEmpty(t, fanout)
+		// execution nodes communicate over unicast with the nodes of most other roles.
+		if id.Role != flow.RoleExecution {
+			require.Less(t, len(fanout), len(ids)-1, "topology should be partial")
+		}
+		for _, neighbor := range fanout {
+			require.Contains(t, topologies[neighbor.NodeID].Fanout(ids).NodeIDs(), id.NodeID, "fanout should be mutual")
+		}
+	}
+
+	// verification nodes receive chunk data packs from execution nodes over unicast.
+	for _, verifier := range ids.Filter(filter.HasRole(flow.RoleVerification)) {
+		fanout := topologies[verifier.NodeID].Fanout(ids).Lookup()
+		for _, executor := range ids.Filter(filter.HasRole(flow.RoleExecution)) {
+			require.Contains(t, fanout, executor.NodeID, "unicast counterparts should be in the fanout")
+		}
+	}
+
+	// collection nodes are connected to the other members of their cluster.
+	for _, cluster := range clusters {
+		for _, member := range cluster {
+			fanout := topologies[member.NodeID].Fanout(ids).Lookup()
+			for _, other := range cluster {
+				if other.NodeID != member.NodeID {
+					require.Contains(t, fanout, other.NodeID, "cluster members should be in the fanout")
+				}
+			}
+		}
+	}
+
+	// the topology of the new epoch is seeded by the source of randomness and connects the clusters of the new epoch.
+	first := unittest.BlockHeaderFixture()
+	newSeed := unittest.RandomBytes(32)
+	newClusters := clustersFixture(ids, 3)
+	state.On("AtBlockID", first.ID()).Return(snapshotWithRandomSource(t, newSeed, newClusters))
+
+	me := ids[0].NodeID
+	topologies[me].EpochTransition(1, first)
+
+	expected, err := topology.BuildGraph(ids, newClusters, newSeed, 2, topology.DefaultPartialTopologyConfig().FullyConnectedRoles)
+	require.NoError(t, err)
+	require.Equal(t, expected.Neighbors(me), topologies[me].Fanout(ids))
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/compressor"
 	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/p2pnode"
 	"github.com/onflow/flow-go/network/validator"
 	_ "github.com/onflow/flow-go/utils/binstat"
 	"github.com/onflow/flow-go/utils/logging"
@@ -121,6 +123,19 @@ func TopicValidator(log zerolog.Logger, peerFilter func(peer.ID) error, validato
 			return p2p.ValidationReject
 		}
 
+		// decompress the payload if the sender compressed it with the compression codec of the channel, so that
+		// validators and the application layer only see plain payloads.
+		payload, codec, err := compressor.DecompressPayload(msg.Payload, p2pnode.DefaultMaxPubSubMsgSize)
+		if err != nil {
+			lg.Warn().
+				Err(err).
+				Str("codec", codec.String()).
+				Bool(logging.KeySuspicious, true).
+				Msg("could not decompress message payload")
+			return p2p.ValidationReject
+		}
+		msg.Payload = payload
+
 		rawMsg.ValidatorData = TopicValidatorData{
 			Message: &msg,
 			From:    from,
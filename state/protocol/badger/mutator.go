@@ -654,8 +654,22 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 		return fmt.Errorf("could not check persisted epoch emergency fallback flag: %w", err)
 	}
 
+	// if epoch fallback was previously triggered, check whether this block is the first block
+	// of a recovery epoch, which ends epoch fallback mode
+	epochFallbackRecovered := false
+	if epochFallbackTriggered {
+		epochFallbackTriggered, err = m.isEpochFallbackActive(epochStatus.CurrentEpoch.SetupID)
+		if err != nil {
+			return fmt.Errorf("could not check whether epoch fallback is active for finalized block: %w", err)
+		}
+		epochFallbackRecovered = !epochFallbackTriggered
+		if epochFallbackRecovered {
+			metrics = append(metrics, m.metrics.EpochEmergencyFallbackRecovered)
+		}
+	}
+
 	// if epoch fallback was not previously triggered, check whether this block triggers it
-	if !epochFallbackTriggered {
+	if !epochFallbackTriggered && !epochFallbackRecovered {
 		epochFallbackTriggered, err = m.epochFallbackTriggeredByFinalizedBlock(header, epochStatus, currentEpochSetup)
 		if err != nil {
 			return fmt.Errorf("could not check whether finalized block triggers epoch fallback: %w", err)
@@ -675,8 +689,16 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 	// Determine metric updates and protocol events related to epoch phase
 	// changes and epoch transitions.
 	// If epoch emergency fallback is triggered, the current epoch continues until
-	// the next spork - so skip these updates.
-	if !epochFallbackTriggered {
+	// the next spork or a recovery epoch begins - so skip these updates, except
+	// for the recovery epoch being committed.
+	if epochFallbackTriggered {
+		recoveryMetrics, recoveryEvents, err := m.epochRecoveryMetricsAndEventsOnBlockFinalized(header, epochStatus)
+		if err != nil {
+			return fmt.Errorf("could not determine epoch recovery metrics/events for finalized block: %w", err)
+		}
+		metrics = append(metrics, recoveryMetrics...)
+		events = append(events, recoveryEvents...)
+	} else {
 		epochPhaseMetrics, epochPhaseEvents, err := m.epochPhaseMetricsAndEventsOnBlockFinalized(block, epochStatus)
 		if err != nil {
 			return fmt.Errorf("could not determine epoch phase metrics/events for finalized block: %w", err)
@@ -706,7 +728,7 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 	// * Update the largest height of sealed and finalized block.
 	//   This value could actually stay the same if it has no seals in
 	//   its payload, in which case the parent's seal is the same.
-	// * set the epoch fallback flag, if it is triggered
+	// * set the epoch fallback flag, if it is triggered, or remove it, if a recovery epoch begins
 	err = operation.RetryOnConflict(m.db.Update, func(tx *badger.Txn) error {
 		err = operation.IndexBlockHeight(header.Height, blockID)(tx)
 		if err != nil {
@@ -720,6 +742,12 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 		if err != nil {
 			return fmt.Errorf("could not update sealed height: %w", err)
 		}
+		if epochFallbackRecovered {
+			err = operation.RemoveEpochEmergencyFallbackTriggered()(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove epoch fallback flag: %w", err)
+			}
+		}
 		if epochFallbackTriggered {
 			err = operation.SetEpochEmergencyFallbackTriggered(blockID)(tx)
 			if err != nil {
@@ -882,6 +910,44 @@ func (m *FollowerState) epochTransitionMetricsAndEventsOnBlockFinalized(block *f
 	return
 }
 
+// epochRecoveryMetricsAndEventsOnBlockFinalized determines metrics to update and protocol
+// events to emit, when the input block is the first finalized block in which a recovery epoch
+// was committed by an EpochRecover service event. Nodes prepare for the recovery epoch as for
+// any other committed epoch, hence we emit the same events as for the EpochCommit service event.
+//
+// This function should only be called when epoch fallback *is in effect* for the input block.
+// No errors are expected during normal operation.
+func (m *FollowerState) epochRecoveryMetricsAndEventsOnBlockFinalized(block *flow.Header, epochStatus *flow.EpochStatus) (
+	metrics []func(),
+	events []func(),
+	err error,
+) {
+	if !epochStatus.NextEpochRecovered {
+		return nil, nil, nil
+	}
+	parentStatus, err := m.epoch.statuses.ByBlockID(block.ParentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not retrieve epoch status for parent: %w", err)
+	}
+	if parentStatus.NextEpochRecovered {
+		// the recovery epoch was committed by an ancestor
+		return nil, nil, nil
+	}
+
+	recoverySetup, err := m.epoch.setups.ByID(epochStatus.NextEpoch.SetupID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not retrieve setup event for recovery epoch: %w", err)
+	}
+	// update current epoch phase
+	events = append(events, func() { m.metrics.CurrentEpochPhase(flow.EpochPhaseCommitted) })
+	// track epoch phase transition (->committed)
+	events = append(events, func() { m.consumer.EpochCommittedPhaseStarted(recoverySetup.Counter-1, block) })
+	// track final view of committed epoch
+	events = append(events, func() { m.metrics.CommittedEpochFinalView(recoverySetup.FinalView) })
+
+	return
+}
+
 // epochPhaseMetricsAndEventsOnBlockFinalized determines metrics to update and protocol
 // events to emit. Service Events embedded into an execution result take effect, when the
 // execution result's _seal is finalized_ (i.e. when the block holding a seal for the
@@ -936,6 +1002,8 @@ func (m *FollowerState) epochPhaseMetricsAndEventsOnBlockFinalized(block *flow.B
 					return nil, nil, fmt.Errorf("could not retrieve setup event for next epoch: %w", err)
 				}
 				events = append(events, func() { m.metrics.CommittedEpochFinalView(nextEpochSetup.FinalView) })
+			case *flow.EpochRecover:
+				// recovery epochs are only accepted while epoch fallback is in effect
 			case *flow.VersionBeacon:
 				// do nothing for now
 			default:
@@ -963,21 +1031,50 @@ func (m *FollowerState) epochPhaseMetricsAndEventsOnBlockFinalized(block *flow.B
 //     -> the parent's EpochStatus.NextEpoch is the current block's EpochStatus.CurrentEpoch
 //     b) FALLBACK PATH: Epoch fallback is triggered, we continue the current epoch:
 //     -> the parent's EpochStatus.CurrentEpoch also applies for the current block
+//  3. The next epoch is a recovery epoch, committed while epoch fallback was in effect:
+//     a) Block is before the recovery epoch (block.View < recoveryEpoch.FirstView)
+//     -> the parent's EpochStatus.CurrentEpoch also applies for the current block
+//     b) Block enters the recovery epoch (block.View ≥ recoveryEpoch.FirstView)
+//     -> the parent's EpochStatus.NextEpoch is the current block's EpochStatus.CurrentEpoch
 //
 // As the parent was a valid extension of the chain, by induction, the parent
 // satisfies all consistency requirements of the protocol.
 //
 // Returns the EpochStatus for the input block.
 // No error returns are expected under normal operations
-func (m *FollowerState) epochStatus(block *flow.Header, epochFallbackTriggered bool) (*flow.EpochStatus, error) {
+func (m *FollowerState) epochStatus(block *flow.Header) (*flow.EpochStatus, error) {
 	parentStatus, err := m.epoch.statuses.ByBlockID(block.ParentID)
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve epoch state for parent: %w", err)
 	}
+
+	// Case 3 (the next epoch is a recovery epoch):
+	if parentStatus.NextEpochRecovered {
+		recoverySetup, err := m.epoch.setups.ByID(parentStatus.NextEpoch.SetupID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve EpochSetup event for recovery epoch: %w", err)
+		}
+		// Case 3a (before the recovery epoch):
+		if block.View < recoverySetup.FirstView {
+			// IMPORTANT: copy the status to avoid modifying the parent status in the cache
+			return parentStatus.Copy(), nil
+		}
+		// Case 3b (first block of recovery epoch):
+		return flow.NewEpochStatus(
+			parentStatus.CurrentEpoch.SetupID, parentStatus.CurrentEpoch.CommitID,
+			parentStatus.NextEpoch.SetupID, parentStatus.NextEpoch.CommitID,
+			flow.ZeroID, flow.ZeroID,
+		)
+	}
+
 	parentSetup, err := m.epoch.setups.ByID(parentStatus.CurrentEpoch.SetupID)
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve EpochSetup event for parent: %w", err)
 	}
+	epochFallbackTriggered, err := m.isEpochFallbackActive(parentStatus.CurrentEpoch.SetupID)
+	if err != nil {
+		return nil, fmt.Errorf("could not check whether epoch fallback is active for parent: %w", err)
+	}
 
 	// Case 1 or 2b (still in parent block's epoch or epoch fallback triggered):
 	if block.View <= parentSetup.FinalView || epochFallbackTriggered {
@@ -1095,13 +1192,13 @@ func (m *FollowerState) versionBeaconOnBlockFinalized(
 //
 // No errors are expected during normal operation.
 func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdates []func(*transaction.Tx) error, err error) {
-	epochFallbackTriggered, err := m.isEpochEmergencyFallbackTriggered()
+	epochStatus, err := m.epochStatus(candidate.Header)
 	if err != nil {
-		return nil, fmt.Errorf("could not retrieve epoch fallback status: %w", err)
+		return nil, fmt.Errorf("could not determine epoch status for candidate block: %w", err)
 	}
-	epochStatus, err := m.epochStatus(candidate.Header, epochFallbackTriggered)
+	epochFallbackTriggered, err := m.isEpochFallbackActive(epochStatus.CurrentEpoch.SetupID)
 	if err != nil {
-		return nil, fmt.Errorf("could not determine epoch status for candidate block: %w", err)
+		return nil, fmt.Errorf("could not retrieve epoch fallback status: %w", err)
 	}
 	activeSetup, err := m.epoch.setups.ByID(epochStatus.CurrentEpoch.SetupID)
 	if err != nil {
@@ -1114,8 +1211,17 @@ func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdat
 	blockID := candidate.ID()
 	dbUpdates = append(dbUpdates, m.epoch.statuses.StoreTx(blockID, epochStatus))
 
-	// never process service events after epoch fallback is triggered
-	if epochStatus.InvalidServiceEventIncorporated || epochFallbackTriggered {
+	// after epoch fallback is triggered, only process recovery epochs
+	// note: this includes forks in which epoch fallback was triggered by an invalid service event
+	if epochFallbackTriggered {
+		recoveryUpdates, err := m.handleEpochRecoverServiceEvents(candidate, epochStatus, activeSetup)
+		if err != nil {
+			return nil, err
+		}
+		return append(dbUpdates, recoveryUpdates...), nil
+	}
+	// never process service events after epoch fallback is tentatively triggered in this fork
+	if epochStatus.InvalidServiceEventIncorporated {
 		return dbUpdates, nil
 	}
 
@@ -1197,6 +1303,12 @@ func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdat
 
 				// we'll insert the commit event when we insert the block
 				dbUpdates = append(dbUpdates, m.epoch.commits.StoreTx(ev))
+			case *flow.EpochRecover:
+				// recovery epochs are only accepted while epoch fallback is in effect
+				m.logger.Warn().
+					Hex("block_id", blockID[:]).
+					Uint64("recovery_epoch_counter", ev.EpochSetup.Counter).
+					Msg("ignoring EpochRecover service event, as epoch fallback is not in effect")
 			case *flow.VersionBeacon:
 				// do nothing for now
 			default:
@@ -1206,3 +1318,95 @@ func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdat
 	}
 	return
 }
+
+// handleEpochRecoverServiceEvents handles applying state changes which occur as a result
+// of EpochRecover service events being included in a block payload, while epoch fallback
+// mode is in effect. All other service events are ignored in epoch fallback mode.
+//
+// A valid EpochRecover service event specifies the next epoch of the candidate's epoch
+// status, which is committed immediately. The current epoch continues until the first
+// view of the recovery epoch. Invalid EpochRecover service events are ignored and
+// epoch fallback mode remains in effect.
+//
+// Return values:
+//   - dbUpdates - operations to insert the service events of the recovery epoch.
+//     The candidate's epoch status is modified in place.
+//
+// No errors are expected during normal operation.
+func (m *FollowerState) handleEpochRecoverServiceEvents(candidate *flow.Block, epochStatus *flow.EpochStatus, activeSetup *flow.EpochSetup) (dbUpdates []func(*transaction.Tx) error, err error) {
+	safetyThreshold, err := m.Params().EpochCommitSafetyThreshold()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch commit safety threshold: %w", err)
+	}
+
+	// block payload may not specify seals in order, so order them by block height before processing
+	orderedSeals, err := protocol.OrderedSeals(candidate.Payload, m.headers)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("ordering seals: parent payload contains seals for unknown block: %s", err.Error())
+		}
+		return nil, fmt.Errorf("unexpected error ordering seals: %w", err)
+	}
+	blockID := candidate.ID()
+	for _, seal := range orderedSeals {
+		result, err := m.results.ByID(seal.ResultID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get result (id=%x) for seal (id=%x): %w", seal.ResultID, seal.ID(), err)
+		}
+
+		for _, event := range result.ServiceEvents {
+			ev, ok := event.Event.(*flow.EpochRecover)
+			if !ok {
+				// skip other service event types
+				continue
+			}
+
+			// validate the service event
+			err := isValidEpochRecover(ev, activeSetup, epochStatus, candidate.Header.View, safetyThreshold)
+			if err != nil {
+				if protocol.IsInvalidServiceEventError(err) {
+					// an invalid recovery epoch is ignored, epoch fallback remains in effect
+					m.logger.Warn().
+						Err(err).
+						Hex("block_id", blockID[:]).
+						Uint64("recovery_epoch_counter", ev.EpochSetup.Counter).
+						Msg("ignoring invalid EpochRecover service event")
+					continue
+				}
+				return nil, fmt.Errorf("unexpected error validating EpochRecover service event: %w", err)
+			}
+
+			// the recovery epoch supersedes any EpochSetup event for the next epoch, which was
+			// incorporated before epoch fallback was triggered
+			epochStatus.NextEpoch.SetupID = ev.EpochSetup.ID()
+			epochStatus.NextEpoch.CommitID = ev.EpochCommit.ID()
+			epochStatus.NextEpochRecovered = true
+
+			// we'll insert the service events when we insert the block
+			dbUpdates = append(dbUpdates, m.epoch.setups.StoreTx(&ev.EpochSetup), m.epoch.commits.StoreTx(&ev.EpochCommit))
+		}
+	}
+	return dbUpdates, nil
+}
+
+// isEpochFallbackActive checks whether epoch fallback mode is in effect for a block, whose
+// current epoch is specified by the given EpochSetup ID. Epoch fallback mode is in effect if
+// it has been globally triggered while the same epoch was current. Once a recovery epoch
+// begins, epoch fallback mode is no longer in effect for blocks of the recovery epoch,
+// even before the global flag is removed by finalizing the first block of the recovery epoch.
+// No errors are expected during normal operation.
+func (m *FollowerState) isEpochFallbackActive(currentEpochSetupID flow.Identifier) (bool, error) {
+	var triggerBlockID flow.Identifier
+	err := m.db.View(operation.RetrieveEpochEmergencyFallbackTriggeredBlockID(&triggerBlockID))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not retrieve epoch fallback flag: %w", err)
+	}
+	triggerStatus, err := m.epoch.statuses.ByBlockID(triggerBlockID)
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve epoch status for block (id=%x) triggering epoch fallback: %w", triggerBlockID, err)
+	}
+	return triggerStatus.CurrentEpoch.SetupID == currentEpochSetupID, nil
+}
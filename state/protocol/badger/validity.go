@@ -166,6 +166,69 @@ func isValidExtendingEpochCommit(extendingCommit *flow.EpochCommit, extendingSet
 	return nil
 }
 
+// isValidEpochRecover checks whether an epoch recover service event being added
+// to the state is valid. The EpochRecover event is only processed while epoch
+// fallback mode is in effect, and specifies the epoch directly following the
+// currently active epoch, which has been extended by epoch fallback mode.
+// In addition to intrinsic validity of the contained EpochSetup and EpochCommit,
+// we check that:
+//   - no recovery epoch was accepted before for the currently active epoch
+//   - the recovery epoch begins after the final view of the active epoch
+//   - the recovery epoch begins more than safetyThreshold views after the view of
+//     the candidate block, which incorporates the recovery epoch. Thereby, nodes
+//     have the same time to prepare for the recovery epoch as for a regular epoch
+//     committed by the epoch commitment deadline.
+//
+// A previously incorporated EpochSetup event for the next epoch (without EpochCommit)
+// is superseded by the recovery epoch.
+// Assumes all inputs besides recovery are already validated.
+// Expected errors during normal operations:
+// * protocol.InvalidServiceEventError if the input service event is invalid to extend the currently active epoch status
+func isValidEpochRecover(recovery *flow.EpochRecover, activeSetup *flow.EpochSetup, status *flow.EpochStatus, candidateView uint64, safetyThreshold uint64) error {
+
+	// We should only have a single recovery epoch per epoch.
+	if status.NextEpochRecovered {
+		return protocol.NewInvalidServiceEventErrorf("duplicate epoch recover service event: %x", status.NextEpoch.CommitID)
+	}
+
+	setup := &recovery.EpochSetup
+	// The recovery epoch should have the counter increased by one.
+	if setup.Counter != activeSetup.Counter+1 {
+		return protocol.NewInvalidServiceEventErrorf("recovery epoch has invalid counter (%d => %d)", activeSetup.Counter, setup.Counter)
+	}
+
+	// The recovery epoch must not overlap with the active epoch.
+	if setup.FirstView <= activeSetup.FinalView {
+		return protocol.NewInvalidServiceEventErrorf(
+			"recovery epoch first view must be greater than current epoch final view (%d <= %d)",
+			setup.FirstView,
+			activeSetup.FinalView,
+		)
+	}
+
+	// The recovery epoch must leave sufficient time for all nodes to prepare for it.
+	if candidateView+safetyThreshold >= setup.FirstView {
+		return protocol.NewInvalidServiceEventErrorf(
+			"recovery epoch first view %d must be greater than view of incorporating block %d plus safety threshold %d",
+			setup.FirstView,
+			candidateView,
+			safetyThreshold,
+		)
+	}
+
+	err := verifyEpochSetup(setup, true)
+	if err != nil {
+		return protocol.NewInvalidServiceEventErrorf("invalid recovery epoch setup: %w", err)
+	}
+
+	err = isValidEpochCommit(&recovery.EpochCommit, setup)
+	if err != nil {
+		return protocol.NewInvalidServiceEventErrorf("invalid recovery epoch commit: %s", err)
+	}
+
+	return nil
+}
+
 // isValidEpochCommit checks whether an epoch commit service event is intrinsically valid.
 // Assumes the input flow.EpochSetup event has already been validated.
 // Expected errors during normal operations:
@@ -104,6 +104,76 @@ func TestBootstrapInvalidEpochCommit(t *testing.T) {
 	})
 }
 
+// recoveryFixture returns an active epoch setup together with a valid recovery epoch
+// following it, which starts 1000 views after the active epoch ends.
+func recoveryFixture() (*flow.EpochSetup, *flow.EpochRecover) {
+	_, result, _ := unittest.BootstrapFixture(participants)
+	activeSetup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+	setup := *activeSetup
+	commit := *result.ServiceEvents[1].Event.(*flow.EpochCommit)
+	setup.Counter++
+	commit.Counter++
+	setup.FirstView = activeSetup.FinalView + 1000
+	setup.DKGPhase1FinalView += setup.FirstView - activeSetup.FirstView
+	setup.DKGPhase2FinalView += setup.FirstView - activeSetup.FirstView
+	setup.DKGPhase3FinalView += setup.FirstView - activeSetup.FirstView
+	setup.FinalView += setup.FirstView - activeSetup.FirstView
+	return activeSetup, &flow.EpochRecover{EpochSetup: setup, EpochCommit: commit}
+}
+
+func TestEpochRecoverValidity(t *testing.T) {
+	safetyThreshold := uint64(100)
+
+	t.Run("valid recovery epoch", func(t *testing.T) {
+		activeSetup, recovery := recoveryFixture()
+		status := &flow.EpochStatus{}
+
+		err := isValidEpochRecover(recovery, activeSetup, status, activeSetup.FinalView, safetyThreshold)
+		require.NoError(t, err)
+	})
+
+	t.Run("duplicate recovery epoch", func(t *testing.T) {
+		activeSetup, recovery := recoveryFixture()
+		status := &flow.EpochStatus{NextEpochRecovered: true}
+
+		err := isValidEpochRecover(recovery, activeSetup, status, activeSetup.FinalView, safetyThreshold)
+		require.True(t, protocol.IsInvalidServiceEventError(err))
+	})
+
+	t.Run("invalid counter", func(t *testing.T) {
+		activeSetup, recovery := recoveryFixture()
+		recovery.EpochSetup.Counter++
+		recovery.EpochCommit.Counter++
+
+		err := isValidEpochRecover(recovery, activeSetup, &flow.EpochStatus{}, activeSetup.FinalView, safetyThreshold)
+		require.True(t, protocol.IsInvalidServiceEventError(err))
+	})
+
+	t.Run("overlapping with active epoch", func(t *testing.T) {
+		activeSetup, recovery := recoveryFixture()
+		recovery.EpochSetup.FirstView = activeSetup.FinalView
+
+		err := isValidEpochRecover(recovery, activeSetup, &flow.EpochStatus{}, activeSetup.FirstView, safetyThreshold)
+		require.True(t, protocol.IsInvalidServiceEventError(err))
+	})
+
+	t.Run("incorporated too late", func(t *testing.T) {
+		activeSetup, recovery := recoveryFixture()
+		candidateView := recovery.EpochSetup.FirstView - safetyThreshold
+
+		err := isValidEpochRecover(recovery, activeSetup, &flow.EpochStatus{}, candidateView, safetyThreshold)
+		require.True(t, protocol.IsInvalidServiceEventError(err))
+	})
+
+	t.Run("inconsistent commit", func(t *testing.T) {
+		activeSetup, recovery := recoveryFixture()
+		recovery.EpochCommit.DKGGroupKey = nil
+
+		err := isValidEpochRecover(recovery, activeSetup, &flow.EpochStatus{}, activeSetup.FinalView, safetyThreshold)
+		require.True(t, protocol.IsInvalidServiceEventError(err))
+	})
+}
+
 // TestEntityExpirySnapshotValidation tests that we perform correct sanity checks when
 // bootstrapping consensus nodes and access nodes we expect that we only bootstrap snapshots
 // with sufficient history.
@@ -112,6 +112,8 @@ func StrippedInmemSnapshot(snapshot EncodableSnapshot) EncodableSnapshot {
 		switch event.Type {
 		case flow.ServiceEventSetup:
 			removeAddress(event.Event.(*flow.EpochSetup).Participants)
+		case flow.ServiceEventRecoverEpoch:
+			removeAddress(event.Event.(*flow.EpochRecover).EpochSetup.Participants)
 		}
 	}
 	return snapshot
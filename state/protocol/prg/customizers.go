@@ -21,6 +21,8 @@ var (
 	VerificationChunkAssignment = customizerFromIndices(0, 2, 0)
 	// ExecutionEnvironment is the customizer for Flow's transaction execution environment
 	ExecutionEnvironment = customizerFromIndices(1)
+	// NetworkingTopology is the customizer for the construction of the partial topology of the networking layer
+	NetworkingTopology = customizerFromIndices(0, 3)
 	//
 	// clusterLeaderSelectionPrefix is the prefix used for CollectorClusterLeaderSelection
 	clusterLeaderSelectionPrefix = []uint16{0, 0}
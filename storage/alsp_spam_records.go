@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// AlspSpamRecord is the persisted spam record of a misbehaving node, as kept by the application layer spam
+// prevention (ALSP) module of the networking layer.
+type AlspSpamRecord struct {
+	OriginID       flow.Identifier
+	Decay          float64
+	CutoffCounter  uint64
+	DisallowListed bool
+	Penalty        float64
+}
+
+// AlspSpamRecordSnapshot is a point-in-time copy of the spam records of the ALSP module, so that the penalties of
+// misbehaving nodes survive restarts of the node.
+type AlspSpamRecordSnapshot struct {
+	// TakenAt is the time the snapshot was taken.
+	TakenAt time.Time
+	// Records are the spam records at the time of the snapshot.
+	Records []AlspSpamRecord
+}
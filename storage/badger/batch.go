@@ -4,8 +4,12 @@ import (
 	"sync"
 
 	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/storage"
 )
 
+var _ storage.BatchStorage = (*Batch)(nil)
+
 type Batch struct {
 	writer *badger.WriteBatch
 
@@ -21,7 +25,7 @@ func NewBatch(db *badger.DB) *Batch {
 	}
 }
 
-func (b *Batch) GetWriter() *badger.WriteBatch {
+func (b *Batch) GetWriter() storage.BatchWriter {
 	return b.writer
 }
 
@@ -0,0 +1,40 @@
+package badger
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// ChunkFaultReports implements persistent storage for chunk fault reports. Reports are rare
+// and large, so they are not cached.
+type ChunkFaultReports struct {
+	db *badger.DB
+}
+
+// NewChunkFaultReports creates a new chunk fault report storage.
+func NewChunkFaultReports(db *badger.DB) *ChunkFaultReports {
+	return &ChunkFaultReports{
+		db: db,
+	}
+}
+
+// Store persists a chunk fault report, replacing any report for the same chunk.
+// No errors are expected during normal operation.
+func (r *ChunkFaultReports) Store(report *chunks.FaultReport) error {
+	return operation.RetryOnConflict(r.db.Update, operation.UpsertChunkFaultReport(report))
+}
+
+// ByChunk retrieves the chunk fault report for the given chunk.
+// Error returns:
+//   - storage.ErrNotFound if no fault was reported for the chunk
+func (r *ChunkFaultReports) ByChunk(resultID flow.Identifier, chunkIndex uint64) (*chunks.FaultReport, error) {
+	var report chunks.FaultReport
+	err := r.db.View(operation.RetrieveChunkFaultReport(resultID, chunkIndex, &report))
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
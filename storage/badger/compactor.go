@@ -0,0 +1,352 @@
+package badger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+)
+
+// ErrCompactionRunning is returned when a compaction is requested while another one is still running.
+var ErrCompactionRunning = errors.New("compaction is already running")
+
+// ErrCompactionNotRunning is returned when canceling a compaction while none is running.
+var ErrCompactionNotRunning = errors.New("no compaction is running")
+
+// CompactionPhase is the phase a compaction is in.
+type CompactionPhase string
+
+const (
+	CompactionPhaseIdle      CompactionPhase = "idle"
+	CompactionPhasePending   CompactionPhase = "pending"
+	CompactionPhaseFlatten   CompactionPhase = "flatten"
+	CompactionPhaseValueLog  CompactionPhase = "value_log_gc"
+	CompactionPhaseCompleted CompactionPhase = "completed"
+	CompactionPhaseFailed    CompactionPhase = "failed"
+)
+
+// CompactionConfig configures a compaction of the database.
+type CompactionConfig struct {
+	// Flatten enables flattening the LSM tree into a single level before the value log GC, which
+	// drops obsolete key versions. Badger stops its background compactions until the flattening
+	// completes and can neither pause nor interrupt it, so the IO load is throttled by the number
+	// of flatten workers only.
+	Flatten bool
+	// FlattenWorkers is the number of concurrent workers merging the LSM tree into a single level.
+	FlattenWorkers int
+	// DiscardRatio is the minimum ratio of discardable data for a value log file to be rewritten.
+	DiscardRatio float64
+	// GCPause is the pause between two value log rewrites, which throttles the IO load of the compaction.
+	GCPause time.Duration
+	// MaxGCRounds is the maximum number of value log files rewritten by a single compaction.
+	MaxGCRounds uint
+}
+
+// DefaultCompactionConfig returns the default configuration of an online compaction, which
+// flattens the LSM tree with a single worker to limit the IO load on the running node.
+func DefaultCompactionConfig() CompactionConfig {
+	return CompactionConfig{
+		Flatten:        true,
+		FlattenWorkers: 1,
+		DiscardRatio:   0.5,
+		GCPause:        5 * time.Second,
+		MaxGCRounds:    100,
+	}
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c CompactionConfig) Validate() error {
+	if c.FlattenWorkers < 1 {
+		return fmt.Errorf("flatten workers must be at least 1, got %d", c.FlattenWorkers)
+	}
+	if c.DiscardRatio <= 0 || c.DiscardRatio >= 1 {
+		return fmt.Errorf("discard ratio must be in (0, 1), got %f", c.DiscardRatio)
+	}
+	if c.GCPause < 0 {
+		return fmt.Errorf("gc pause must not be negative, got %s", c.GCPause)
+	}
+	if c.MaxGCRounds == 0 {
+		return fmt.Errorf("max gc rounds must be above 0")
+	}
+	return nil
+}
+
+// CompactionResult summarizes a completed compaction.
+type CompactionResult struct {
+	FlattenDuration time.Duration // time spent flattening the LSM tree, zero if it was not flattened
+	RewrittenFiles  uint          // number of value log files rewritten by the value log GC
+	Duration        time.Duration // total time spent on the compaction
+}
+
+// CompactionProgress is a snapshot of the progress of the latest compaction started by a Compactor.
+type CompactionProgress struct {
+	Phase           CompactionPhase
+	Config          CompactionConfig
+	Started         time.Time     // zero if no compaction was started yet
+	FlattenDuration time.Duration // time spent flattening the LSM tree, zero until it was flattened
+	GCRounds        uint          // number of value log GC rounds run so far
+	RewrittenFiles  uint          // number of value log files rewritten so far
+	Result          *CompactionResult
+	Err             error
+}
+
+// Compactor runs compactions of a badger database.
+//
+// A compaction runs while the node keeps operating on the database. It first flattens the LSM tree
+// into a single level, dropping obsolete key versions, and then repeatedly runs the value log garbage
+// collection until no value log file is worth rewriting. The IO load is throttled by the number of
+// workers flattening the LSM tree and by pausing between value log rewrites. After flattening, the
+// compaction pauses as well, so badger's background compactions can catch up with the writes
+// received meanwhile.
+//
+// Compactions are either run synchronously with Compact, or submitted with Submit to the worker of
+// the Compactor, which runs them in the background until they complete, are canceled with Cancel, or
+// the Compactor is stopped. At most one compaction runs at a time.
+type Compactor struct {
+	component.Component
+	log     zerolog.Logger
+	db      *badger.DB
+	metrics module.DatabaseSizeMetrics
+	running *atomic.Bool
+	jobs    chan CompactionConfig
+
+	mu       sync.Mutex
+	progress CompactionProgress
+	cancel   context.CancelFunc // cancels the compaction run by the worker, nil if none is running
+}
+
+var _ component.Component = (*Compactor)(nil)
+
+// NewCompactor returns a compactor for the given database.
+func NewCompactor(log zerolog.Logger, db *badger.DB, metrics module.DatabaseSizeMetrics) *Compactor {
+	c := &Compactor{
+		log:      log.With().Str("component", "compactor").Logger(),
+		db:       db,
+		metrics:  metrics,
+		running:  atomic.NewBool(false),
+		jobs:     make(chan CompactionConfig, 1),
+		progress: CompactionProgress{Phase: CompactionPhaseIdle},
+	}
+
+	c.Component = component.NewComponentManagerBuilder().
+		AddWorker(c.compactionWorker).
+		Build()
+
+	return c
+}
+
+// Submit queues a compaction with the given configuration to be run in the background by the worker of
+// the compactor. Use Progress to follow the compaction and Cancel to stop it.
+// Expected errors during normal operations:
+//   - ErrCompactionRunning if another compaction is still running
+func (c *Compactor) Submit(config CompactionConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid compaction config: %w", err)
+	}
+	if !c.running.CompareAndSwap(false, true) {
+		return ErrCompactionRunning
+	}
+
+	c.mu.Lock()
+	c.progress = CompactionProgress{Phase: CompactionPhasePending, Config: config}
+	c.mu.Unlock()
+
+	// the channel has capacity for one job and a job is only queued while no other compaction runs
+	c.jobs <- config
+	return nil
+}
+
+// Cancel stops the compaction run in the background at the next opportunity.
+// Expected errors during normal operations:
+//   - ErrCompactionNotRunning if no compaction is running in the background
+func (c *Compactor) Cancel() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel == nil {
+		return ErrCompactionNotRunning
+	}
+	c.cancel()
+	return nil
+}
+
+// Progress returns the progress of the latest compaction.
+func (c *Compactor) Progress() CompactionProgress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.progress
+}
+
+// Compact runs a compaction of the database with the given configuration and blocks until it completed.
+// The compaction stops early before flattening and between value log rewrites if the context is canceled.
+// Expected errors during normal operations:
+//   - ErrCompactionRunning if another compaction is still running
+//   - context.Canceled or context.DeadlineExceeded if the context is done before the compaction completed
+func (c *Compactor) Compact(ctx context.Context, config CompactionConfig) (*CompactionResult, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid compaction config: %w", err)
+	}
+	if !c.running.CompareAndSwap(false, true) {
+		return nil, ErrCompactionRunning
+	}
+	defer c.running.Store(false)
+
+	c.mu.Lock()
+	c.progress = CompactionProgress{Phase: CompactionPhasePending, Config: config}
+	c.mu.Unlock()
+
+	return c.compact(ctx, config)
+}
+
+// compactionWorker runs the compactions submitted with Submit, one at a time.
+func (c *Compactor) compactionWorker(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case config := <-c.jobs:
+			jobCtx, cancel := context.WithCancel(ctx)
+			c.mu.Lock()
+			c.cancel = cancel
+			c.mu.Unlock()
+
+			_, err := c.compact(jobCtx, config)
+			if err != nil {
+				c.log.Warn().Err(err).Msg("compaction failed")
+			}
+
+			c.mu.Lock()
+			c.cancel = nil
+			c.mu.Unlock()
+			cancel()
+			c.running.Store(false)
+		}
+	}
+}
+
+// compact runs a compaction with the given configuration and records its progress.
+// The caller must hold the running flag.
+// Expected errors during normal operations:
+//   - context.Canceled or context.DeadlineExceeded if the context is done before the compaction completed
+func (c *Compactor) compact(ctx context.Context, config CompactionConfig) (*CompactionResult, error) {
+	result, err := c.runPhases(ctx, config)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.progress.Phase = CompactionPhaseFailed
+		c.progress.Err = err
+		return nil, err
+	}
+	c.progress.Phase = CompactionPhaseCompleted
+	c.progress.Result = result
+	return result, nil
+}
+
+// runPhases runs the phases of a compaction with the given configuration.
+// Expected errors during normal operations:
+//   - context.Canceled or context.DeadlineExceeded if the context is done before the compaction completed
+func (c *Compactor) runPhases(ctx context.Context, config CompactionConfig) (*CompactionResult, error) {
+	started := time.Now()
+	result := &CompactionResult{}
+
+	c.mu.Lock()
+	c.progress.Started = started
+	c.mu.Unlock()
+
+	c.log.Info().
+		Bool("flatten", config.Flatten).
+		Int("flatten_workers", config.FlattenWorkers).
+		Float64("discard_ratio", config.DiscardRatio).
+		Dur("gc_pause", config.GCPause).
+		Msg("starting compaction")
+
+	if config.Flatten {
+		err := ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+		c.setPhase(CompactionPhaseFlatten)
+
+		// NOTE: badger does not support interrupting the flattening, so a cancellation only takes
+		// effect once it completed
+		err = c.db.Flatten(config.FlattenWorkers)
+		if err != nil {
+			return nil, fmt.Errorf("could not flatten LSM tree: %w", err)
+		}
+		result.FlattenDuration = time.Since(started)
+		c.log.Info().Dur("duration", result.FlattenDuration).Msg("LSM tree flattened")
+
+		c.mu.Lock()
+		c.progress.FlattenDuration = result.FlattenDuration
+		c.mu.Unlock()
+
+		// give the background compactions, which were stopped while flattening, time to catch up
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(config.GCPause):
+		}
+	}
+
+	c.setPhase(CompactionPhaseValueLog)
+	for round := uint(0); round < config.MaxGCRounds; round++ {
+		err := ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.db.RunValueLogGC(config.DiscardRatio)
+		if errors.Is(err, badger.ErrNoRewrite) {
+			// no value log file has enough garbage to be rewritten
+			break
+		}
+		if errors.Is(err, badger.ErrRejected) {
+			// NOTE: this happens when the regular value log GC of the cleaner is running, we
+			// wait for it to complete and try again
+			c.log.Debug().Msg("value log gc already running, retrying after pause")
+		} else if err != nil {
+			return nil, fmt.Errorf("could not run value log gc: %w", err)
+		} else {
+			result.RewrittenFiles++
+		}
+
+		c.mu.Lock()
+		c.progress.GCRounds = round + 1
+		c.progress.RewrittenFiles = result.RewrittenFiles
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(config.GCPause):
+		}
+	}
+
+	result.Duration = time.Since(started)
+	c.log.Info().
+		Dur("duration", result.Duration).
+		Uint("rewritten_files", result.RewrittenFiles).
+		Msg("compaction completed")
+	c.metrics.RanCompaction(result.Duration)
+
+	return result, nil
+}
+
+// setPhase records the phase of the running compaction.
+func (c *Compactor) setPhase(phase CompactionPhase) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Phase = phase
+}
@@ -0,0 +1,207 @@
+package badger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	modulemock "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// TestCompactor tests that a compaction without flattening completes while the database is open,
+// keeps the stored data and reports its duration.
+func TestCompactor(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		commits := storeCommits(t, db)
+
+		metrics := modulemock.NewDatabaseSizeMetrics(t)
+		metrics.On("RanCompaction", mock.Anything).Once()
+		compactor := badgerstorage.NewCompactor(unittest.Logger(), db, metrics)
+
+		config := badgerstorage.DefaultCompactionConfig()
+		config.Flatten = false
+		config.GCPause = time.Millisecond
+		result, err := compactor.Compact(context.Background(), config)
+		require.NoError(t, err)
+		require.NotZero(t, result.Duration)
+		require.Zero(t, result.FlattenDuration)
+		require.Equal(t, badgerstorage.CompactionPhaseCompleted, compactor.Progress().Phase)
+
+		requireCommits(t, db, commits)
+	})
+}
+
+// TestCompactor_Flatten tests that a compaction flattens the LSM tree while the node keeps writing
+// to the database, and keeps the stored data.
+func TestCompactor_Flatten(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		commits := storeCommits(t, db)
+
+		metrics := modulemock.NewDatabaseSizeMetrics(t)
+		metrics.On("RanCompaction", mock.Anything).Once()
+		compactor := badgerstorage.NewCompactor(unittest.Logger(), db, metrics)
+
+		// keep writing to the database while it is compacted
+		done := make(chan struct{})
+		written := make(chan map[flow.Identifier]flow.StateCommitment)
+		go func() {
+			commits := make(map[flow.Identifier]flow.StateCommitment)
+			defer func() { written <- commits }()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				blockID := unittest.IdentifierFixture()
+				commit := unittest.StateCommitmentFixture()
+				if db.Update(operation.IndexStateCommitment(blockID, commit)) != nil {
+					return
+				}
+				commits[blockID] = commit
+			}
+		}()
+
+		config := badgerstorage.DefaultCompactionConfig()
+		config.GCPause = time.Millisecond
+		result, err := compactor.Compact(context.Background(), config)
+		close(done)
+		concurrent := <-written
+		require.NoError(t, err)
+		require.NotZero(t, result.FlattenDuration)
+		require.Equal(t, result.FlattenDuration, compactor.Progress().FlattenDuration)
+
+		requireCommits(t, db, commits)
+		requireCommits(t, db, concurrent)
+	})
+}
+
+// TestCompactor_Background tests that a compaction submitted to the worker runs in the background,
+// reports its progress, and that a second compaction is rejected while the first one is running.
+func TestCompactor_Background(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		storeCommits(t, db)
+
+		ran := make(chan struct{})
+		metrics := modulemock.NewDatabaseSizeMetrics(t)
+		metrics.On("RanCompaction", mock.Anything).
+			Run(func(mock.Arguments) { close(ran) }).
+			Once()
+		compactor := badgerstorage.NewCompactor(unittest.Logger(), db, metrics)
+
+		config := badgerstorage.DefaultCompactionConfig()
+		config.GCPause = time.Millisecond
+		require.NoError(t, compactor.Submit(config))
+		require.ErrorIs(t, compactor.Submit(config), badgerstorage.ErrCompactionRunning)
+		require.Equal(t, badgerstorage.CompactionPhasePending, compactor.Progress().Phase)
+
+		ctx, cancel := irrecoverable.NewMockSignalerContextWithCancel(t, context.Background())
+		compactor.Start(ctx)
+		unittest.RequireComponentsReadyBefore(t, time.Second, compactor)
+		unittest.RequireCloseBefore(t, ran, time.Second, "compaction did not complete")
+
+		require.Eventually(t, func() bool {
+			return compactor.Progress().Phase == badgerstorage.CompactionPhaseCompleted
+		}, time.Second, 10*time.Millisecond)
+		require.NotNil(t, compactor.Progress().Result)
+		require.ErrorIs(t, compactor.Cancel(), badgerstorage.ErrCompactionNotRunning)
+
+		cancel()
+		unittest.RequireCloseBefore(t, compactor.Done(), time.Second, "compactor did not stop")
+	})
+}
+
+// TestCompactor_Canceled tests that a compaction stops before its first phase if its context is canceled.
+func TestCompactor_Canceled(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		storeCommits(t, db)
+		compactor := badgerstorage.NewCompactor(unittest.Logger(), db, modulemock.NewDatabaseSizeMetrics(t))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := compactor.Compact(ctx, badgerstorage.DefaultCompactionConfig())
+		require.ErrorIs(t, err, context.Canceled)
+
+		progress := compactor.Progress()
+		require.Equal(t, badgerstorage.CompactionPhaseFailed, progress.Phase)
+		require.ErrorIs(t, progress.Err, context.Canceled)
+	})
+}
+
+// TestCompactor_InvalidConfig tests that a compaction with an invalid configuration is rejected.
+func TestCompactor_InvalidConfig(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		compactor := badgerstorage.NewCompactor(unittest.Logger(), db, modulemock.NewDatabaseSizeMetrics(t))
+
+		config := badgerstorage.DefaultCompactionConfig()
+		config.DiscardRatio = 1
+		_, err := compactor.Compact(context.Background(), config)
+		require.Error(t, err)
+		require.Error(t, compactor.Submit(config))
+
+		config = badgerstorage.DefaultCompactionConfig()
+		config.FlattenWorkers = 0
+		_, err = compactor.Compact(context.Background(), config)
+		require.Error(t, err)
+
+		config = badgerstorage.DefaultCompactionConfig()
+		config.GCPause = -time.Second
+		require.Error(t, compactor.Submit(config))
+	})
+}
+
+// storeCommits stores state commitments for random block IDs and returns them.
+func storeCommits(t *testing.T, db *badger.DB) map[flow.Identifier]flow.StateCommitment {
+	commits := make(map[flow.Identifier]flow.StateCommitment)
+	for i := 0; i < 100; i++ {
+		blockID := unittest.IdentifierFixture()
+		commit := unittest.StateCommitmentFixture()
+		require.NoError(t, db.Update(operation.IndexStateCommitment(blockID, commit)))
+		commits[blockID] = commit
+	}
+	return commits
+}
+
+// requireCommits requires that the given state commitments are stored in the database.
+func requireCommits(t *testing.T, db *badger.DB, commits map[flow.Identifier]flow.StateCommitment) {
+	for blockID, expected := range commits {
+		var actual flow.StateCommitment
+		require.NoError(t, db.View(operation.LookupStateCommitment(blockID, &actual)))
+		require.Equal(t, expected, actual)
+	}
+}
+
+// TestPrefixSizeReporter tests that the reporter reports the size of each key prefix on startup.
+func TestPrefixSizeReporter(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, db.Update(operation.IndexStateCommitment(unittest.IdentifierFixture(), unittest.StateCommitmentFixture())))
+		}
+
+		reported := make(chan struct{})
+		metrics := modulemock.NewDatabaseSizeMetrics(t)
+		metrics.On("PrefixSize", "commit", uint64(3), mock.AnythingOfType("uint64")).
+			Run(func(mock.Arguments) { close(reported) }).
+			Once()
+
+		reporter := badgerstorage.NewPrefixSizeReporter(unittest.Logger(), db, metrics, time.Hour)
+		ctx, cancel := irrecoverable.NewMockSignalerContextWithCancel(t, context.Background())
+		reporter.Start(ctx)
+		unittest.RequireComponentsReadyBefore(t, time.Second, reporter)
+		unittest.RequireCloseBefore(t, reported, time.Second, "prefix sizes were not reported")
+
+		cancel()
+		unittest.RequireCloseBefore(t, reporter.Done(), time.Second, "reporter did not stop")
+	})
+}
@@ -96,6 +96,33 @@ func (ds *DKGState) RetrieveMyBeaconPrivateKey(epochCounter uint64) (crypto.Priv
 	return encodableKey.PrivateKey, nil
 }
 
+// RecoverMyBeaconPrivateKey stores the random beacon private key for an epoch,
+// which reuses the DKG of a prior epoch, and sets the DKG end state for the epoch
+// to success. Any key and end state previously stored for the epoch are overwritten.
+//
+// CAUTION: the key must have been validated against the canonical key vector
+// of the epoch, as it is considered safe for signing once stored.
+// No errors expected during normal operation.
+func (ds *DKGState) RecoverMyBeaconPrivateKey(epochCounter uint64, key crypto.PrivateKey) error {
+	if key == nil {
+		return fmt.Errorf("will not store nil beacon key")
+	}
+	encodableKey := &encodable.RandomBeaconPrivKey{PrivateKey: key}
+	err := ds.db.Update(func(tx *badger.Txn) error {
+		err := operation.UpsertMyBeaconPrivateKey(epochCounter, encodableKey)(tx)
+		if err != nil {
+			return fmt.Errorf("could not store beacon private key: %w", err)
+		}
+		return operation.UpsertDKGEndStateForEpoch(epochCounter, flow.DKGEndStateSuccess)(tx)
+	})
+	if err != nil {
+		return err
+	}
+	// evict any previously stored key from the cache
+	ds.keyCache.Remove(epochCounter)
+	return nil
+}
+
 // SetDKGStarted sets the flag indicating the DKG has started for the given epoch.
 func (ds *DKGState) SetDKGStarted(epochCounter uint64) error {
 	return ds.db.Update(operation.InsertDKGStartedForEpoch(epochCounter))
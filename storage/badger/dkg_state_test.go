@@ -110,6 +110,46 @@ func TestDKGState_EndState(t *testing.T) {
 	})
 }
 
+func TestDKGState_RecoverBeaconKey(t *testing.T) {
+	unittest.RunWithTypedBadgerDB(t, bstorage.InitSecret, func(db *badger.DB) {
+		metrics := metrics.NewNoopCollector()
+		dkgState, err := bstorage.NewDKGState(metrics, db)
+		require.NoError(t, err)
+		safeKeys := bstorage.NewSafeBeaconPrivateKeys(dkgState)
+
+		epochCounter := rand.Uint64()
+
+		// the failed DKG for the epoch produced a key and end state
+		err = dkgState.InsertMyBeaconPrivateKey(epochCounter, unittest.RandomBeaconPriv().PrivateKey)
+		require.NoError(t, err)
+		err = dkgState.SetDKGEndState(epochCounter, flow.DKGEndStateDKGFailure)
+		require.NoError(t, err)
+		// ensure the key is cached
+		_, err = dkgState.RetrieveMyBeaconPrivateKey(epochCounter)
+		require.NoError(t, err)
+
+		t.Run("should fail to recover a nil key", func(t *testing.T) {
+			err = dkgState.RecoverMyBeaconPrivateKey(epochCounter, nil)
+			assert.Error(t, err)
+		})
+
+		t.Run("should overwrite key and end state", func(t *testing.T) {
+			expected := unittest.RandomBeaconPriv().PrivateKey
+			err = dkgState.RecoverMyBeaconPrivateKey(epochCounter, expected)
+			require.NoError(t, err)
+
+			endState, err := dkgState.GetDKGEndState(epochCounter)
+			require.NoError(t, err)
+			assert.Equal(t, flow.DKGEndStateSuccess, endState)
+
+			key, safe, err := safeKeys.RetrieveMyBeaconPrivateKey(epochCounter)
+			require.NoError(t, err)
+			assert.True(t, safe)
+			assert.Equal(t, expected, key)
+		})
+	})
+}
+
 func TestSafeBeaconPrivateKeys(t *testing.T) {
 	unittest.RunWithTypedBadgerDB(t, bstorage.InitSecret, func(db *badger.DB) {
 		metrics := metrics.NewNoopCollector()
@@ -0,0 +1,20 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+// UpsertAlspSpamRecords persists the snapshot of the ALSP spam records of the given networking type, overwriting
+// any previously persisted snapshot.
+// No errors are expected during normal operations.
+func UpsertAlspSpamRecords(networkType string, snapshot *storage.AlspSpamRecordSnapshot) func(*badger.Txn) error {
+	return upsert(makePrefix(codeAlspSpamRecords, networkType), snapshot)
+}
+
+// RetrieveAlspSpamRecords retrieves the snapshot of the ALSP spam records of the given networking type.
+// Returns storage.ErrNotFound if no snapshot was persisted.
+func RetrieveAlspSpamRecords(networkType string, snapshot *storage.AlspSpamRecordSnapshot) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeAlspSpamRecords, networkType), snapshot)
+}
@@ -0,0 +1,52 @@
+package operation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestAlspSpamRecords tests that snapshots of the ALSP spam records are persisted and retrieved per networking type,
+// and that a persisted snapshot is overwritten by the next one.
+func TestAlspSpamRecords(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var actual storage.AlspSpamRecordSnapshot
+		err := db.View(RetrieveAlspSpamRecords("private", &actual))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		snapshotFixture := func() *storage.AlspSpamRecordSnapshot {
+			return &storage.AlspSpamRecordSnapshot{
+				TakenAt: time.Now().UTC().Truncate(time.Second),
+				Records: []storage.AlspSpamRecord{
+					{OriginID: unittest.IdentifierFixture(), Decay: 1000, Penalty: -10},
+					{OriginID: unittest.IdentifierFixture(), Decay: 100, CutoffCounter: 2, DisallowListed: true, Penalty: -100_000},
+				},
+			}
+		}
+
+		private := snapshotFixture()
+		require.NoError(t, db.Update(UpsertAlspSpamRecords("private", private)))
+		public := snapshotFixture()
+		require.NoError(t, db.Update(UpsertAlspSpamRecords("public", public)))
+
+		require.NoError(t, db.View(RetrieveAlspSpamRecords("private", &actual)))
+		require.Equal(t, private.Records, actual.Records)
+		require.True(t, private.TakenAt.Equal(actual.TakenAt))
+
+		updated := snapshotFixture()
+		require.NoError(t, db.Update(UpsertAlspSpamRecords("private", updated)))
+
+		actual = storage.AlspSpamRecordSnapshot{}
+		require.NoError(t, db.View(RetrieveAlspSpamRecords("private", &actual)))
+		require.Equal(t, updated.Records, actual.Records)
+
+		actual = storage.AlspSpamRecordSnapshot{}
+		require.NoError(t, db.View(RetrieveAlspSpamRecords("public", &actual)))
+		require.Equal(t, public.Records, actual.Records)
+	})
+}
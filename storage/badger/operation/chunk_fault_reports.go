@@ -0,0 +1,40 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// UpsertChunkFaultReport persists a chunk fault report, keyed by execution result ID and chunk
+// index. A report for the same chunk is overwritten.
+// No errors are expected during normal operation.
+func UpsertChunkFaultReport(report *chunks.FaultReport) func(*badger.Txn) error {
+	return upsert(makePrefix(codeChunkFaultReport, report.ResultID(), report.ChunkIndex()), report)
+}
+
+// RetrieveChunkFaultReport retrieves the chunk fault report for the given chunk.
+// Error returns:
+//   - storage.ErrNotFound if no fault was reported for the chunk
+func RetrieveChunkFaultReport(resultID flow.Identifier, chunkIndex uint64, report *chunks.FaultReport) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeChunkFaultReport, resultID, chunkIndex), report)
+}
+
+// TraverseChunkFaultReports calls handle for each persisted chunk fault report, in order of
+// execution result ID and chunk index. Traversal stops at the first error returned by handle.
+func TraverseChunkFaultReports(handle func(report *chunks.FaultReport) error) func(*badger.Txn) error {
+	return traverse(makePrefix(codeChunkFaultReport), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var report chunks.FaultReport
+		create := func() interface{} {
+			return &report
+		}
+		handleReport := func() error {
+			return handle(&report)
+		}
+		return check, create, handleReport
+	})
+}
@@ -0,0 +1,71 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func chunkFaultReportFixture(chunkIndex uint64) *chunks.FaultReport {
+	vc := unittest.VerifiableChunkDataFixture(chunkIndex)
+	report := &chunks.FaultReport{
+		VerifierID:        unittest.IdentifierFixture(),
+		ChainID:           flow.Testnet,
+		Chunk:             vc.Chunk,
+		Header:            vc.Header,
+		Result:            vc.Result,
+		ChunkDataPack:     vc.ChunkDataPack,
+		EndState:          vc.EndState,
+		TransactionOffset: vc.TransactionOffset,
+		RandomSource:      unittest.RandomBytes(32),
+	}
+	report.SetOutcome(chunks.NewCFNonMatchingFinalStateWithRegisters(
+		unittest.StateCommitmentFixture(),
+		vc.EndState,
+		chunkIndex,
+		vc.Result.ID(),
+		flow.RegisterEntries{{Key: flow.NewRegisterID("owner", "key"), Value: []byte{1, 2, 3}}},
+	))
+	return report
+}
+
+func TestChunkFaultReports(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		first := chunkFaultReportFixture(0)
+		second := chunkFaultReportFixture(1)
+
+		var report chunks.FaultReport
+		err := db.View(RetrieveChunkFaultReport(first.ResultID(), first.ChunkIndex(), &report))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		require.NoError(t, db.Update(UpsertChunkFaultReport(first)))
+		require.NoError(t, db.Update(UpsertChunkFaultReport(second)))
+		// reporting a fault for the same chunk again overwrites the report
+		require.NoError(t, db.Update(UpsertChunkFaultReport(second)))
+
+		require.NoError(t, db.View(RetrieveChunkFaultReport(first.ResultID(), first.ChunkIndex(), &report)))
+		assert.Equal(t, first.ResultID(), report.ResultID())
+		assert.Equal(t, first.ChunkIndex(), report.ChunkIndex())
+		assert.Equal(t, chunks.FaultTypeNonMatchingFinalState, report.FaultType)
+		assert.Equal(t, first.EndState, report.EndState)
+		assert.Equal(t, first.ComputedEndState, report.ComputedEndState)
+		assert.Equal(t, first.RegisterUpdates, report.RegisterUpdates)
+		assert.Equal(t, first.ChunkDataPack.ID(), report.ChunkDataPack.ID())
+		assert.Equal(t, first.RandomSource, report.RandomSource)
+
+		var resultIDs []flow.Identifier
+		err = db.View(TraverseChunkFaultReports(func(report *chunks.FaultReport) error {
+			resultIDs = append(resultIDs, report.ResultID())
+			return nil
+		}))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []flow.Identifier{first.ResultID(), second.ResultID()}, resultIDs)
+	})
+}
@@ -20,6 +20,17 @@ func InsertMyBeaconPrivateKey(epochCounter uint64, info *encodable.RandomBeaconP
 	return insert(makePrefix(codeBeaconPrivateKey, epochCounter), info)
 }
 
+// UpsertMyBeaconPrivateKey stores the random beacon private key for the given epoch,
+// overwriting any key previously stored for the epoch.
+//
+// CAUTION: This method stores confidential information and should only be
+// used in the context of the secrets database. This is enforced in the above
+// layer (see storage.DKGState).
+// No errors expected during normal operation.
+func UpsertMyBeaconPrivateKey(epochCounter uint64, info *encodable.RandomBeaconPrivKey) func(*badger.Txn) error {
+	return upsert(makePrefix(codeBeaconPrivateKey, epochCounter), info)
+}
+
 // RetrieveMyBeaconPrivateKey retrieves the random beacon private key for the given epoch.
 //
 // CAUTION: This method stores confidential information and should only be
@@ -62,6 +73,13 @@ func InsertDKGEndStateForEpoch(epochCounter uint64, endState flow.DKGEndState) f
 	return insert(makePrefix(codeDKGEnded, epochCounter), endState)
 }
 
+// UpsertDKGEndStateForEpoch stores the DKG end state for the epoch, overwriting
+// any end state previously stored for the epoch.
+// No errors expected during normal operation.
+func UpsertDKGEndStateForEpoch(epochCounter uint64, endState flow.DKGEndState) func(*badger.Txn) error {
+	return upsert(makePrefix(codeDKGEnded, epochCounter), endState)
+}
+
 // RetrieveDKGEndStateForEpoch retrieves the DKG end state for the epoch.
 // Error returns: storage.ErrNotFound
 func RetrieveDKGEndStateForEpoch(epochCounter uint64, endState *flow.DKGEndState) func(*badger.Txn) error {
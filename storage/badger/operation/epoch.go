@@ -46,6 +46,15 @@ func SetEpochEmergencyFallbackTriggered(blockID flow.Identifier) func(txn *badge
 	return SkipDuplicates(insert(makePrefix(codeEpochEmergencyFallbackTriggered), blockID))
 }
 
+// RemoveEpochEmergencyFallbackTriggered removes the flag indicating that epoch
+// emergency fallback has been triggered. This happens when finalizing the first
+// block of a recovery epoch, which was specified by an EpochRecover service event.
+// Error returns:
+//   - storage.ErrNotFound if the flag is not set
+func RemoveEpochEmergencyFallbackTriggered() func(txn *badger.Txn) error {
+	return remove(makePrefix(codeEpochEmergencyFallbackTriggered))
+}
+
 // RetrieveEpochEmergencyFallbackTriggeredBlockID gets the block ID where epoch
 // emergency was triggered.
 func RetrieveEpochEmergencyFallbackTriggeredBlockID(blockID *flow.Identifier) func(*badger.Txn) error {
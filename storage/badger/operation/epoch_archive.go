@@ -0,0 +1,53 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// UpsertArchivedEpoch stores the archived epoch keyed by its epoch counter, replacing the archived
+// epoch with the same counter if it exists.
+// No errors are expected during normal operation.
+func UpsertArchivedEpoch(epoch *flow.ArchivedEpoch) func(*badger.Txn) error {
+	return upsert(makePrefix(codeArchivedEpoch, epoch.Counter()), epoch)
+}
+
+// RetrieveArchivedEpoch retrieves the archived epoch with the given epoch counter.
+// Returns storage.ErrNotFound if the epoch has not been archived.
+func RetrieveArchivedEpoch(counter uint64, epoch *flow.ArchivedEpoch) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeArchivedEpoch, counter), epoch)
+}
+
+// IndexArchivedEpochByHeight indexes the counter of an archived epoch by the lowest height archived
+// for the epoch, which is the height of the first block of the epoch, or the root height of the
+// archive for the epoch containing the root block.
+// Returns storage.ErrAlreadyExists if the height has already been indexed.
+func IndexArchivedEpochByHeight(height uint64, counter uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeArchivedHeight, height), counter)
+}
+
+// LookupArchivedEpochByHeight finds the counter of the archived epoch containing the given height,
+// which is the epoch indexed at the highest height at or below the given height.
+// Returns storage.ErrNotFound if no epoch has been archived at or below the given height.
+func LookupArchivedEpochByHeight(height uint64, counter *uint64) func(*badger.Txn) error {
+	return findHighestAtOrBelow(makePrefix(codeArchivedHeight), height, counter)
+}
+
+// InsertEpochArchiveHeight inserts the height of the highest finalized block processed by the epoch archive.
+// Returns storage.ErrAlreadyExists if the height has already been inserted.
+func InsertEpochArchiveHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeEpochArchiveHeight), height)
+}
+
+// UpdateEpochArchiveHeight updates the height of the highest finalized block processed by the epoch archive.
+// Returns storage.ErrNotFound if the height has not been inserted.
+func UpdateEpochArchiveHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeEpochArchiveHeight), height)
+}
+
+// RetrieveEpochArchiveHeight retrieves the height of the highest finalized block processed by the epoch archive.
+// Returns storage.ErrNotFound if the epoch archive has not been bootstrapped.
+func RetrieveEpochArchiveHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeEpochArchiveHeight), height)
+}
@@ -0,0 +1,92 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestArchivedEpoch_UpsertRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		firstHeight := uint64(100)
+		epoch := &flow.ArchivedEpoch{
+			Setup: &flow.EpochSetup{
+				Counter:      3,
+				FirstView:    1000,
+				FinalView:    1999,
+				RandomSource: unittest.SeedFixture(flow.EpochSetupRandomSourceLength),
+			},
+			FirstHeight: &firstHeight,
+		}
+
+		var actual flow.ArchivedEpoch
+		err := db.View(RetrieveArchivedEpoch(3, &actual))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		err = db.Update(UpsertArchivedEpoch(epoch))
+		require.NoError(t, err)
+		err = db.View(RetrieveArchivedEpoch(3, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, epoch, &actual)
+
+		// the archived epoch is replaced once its phases start
+		setupPhaseHeight := uint64(150)
+		epoch.SetupPhaseHeight = &setupPhaseHeight
+		err = db.Update(UpsertArchivedEpoch(epoch))
+		require.NoError(t, err)
+		err = db.View(RetrieveArchivedEpoch(3, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, epoch, &actual)
+	})
+}
+
+func TestArchivedEpoch_LookupByHeight(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var counter uint64
+
+		err := db.Update(IndexArchivedEpochByHeight(20, 2))
+		require.NoError(t, err)
+		err = db.Update(IndexArchivedEpochByHeight(35, 3))
+		require.NoError(t, err)
+
+		// indexing the same height twice is not allowed
+		err = db.Update(IndexArchivedEpochByHeight(35, 4))
+		require.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+		// no epoch is archived below the lowest indexed height
+		err = db.View(LookupArchivedEpochByHeight(19, &counter))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		for height, expected := range map[uint64]uint64{20: 2, 34: 2, 35: 3, 1000: 3} {
+			err = db.View(LookupArchivedEpochByHeight(height, &counter))
+			require.NoError(t, err)
+			assert.Equal(t, expected, counter, "unexpected epoch at height %d", height)
+		}
+	})
+}
+
+func TestEpochArchiveHeight_InsertUpdateRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var height uint64
+
+		err := db.View(RetrieveEpochArchiveHeight(&height))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+		err = db.Update(UpdateEpochArchiveHeight(10))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		err = db.Update(InsertEpochArchiveHeight(10))
+		require.NoError(t, err)
+		err = db.Update(UpdateEpochArchiveHeight(11))
+		require.NoError(t, err)
+
+		err = db.View(RetrieveEpochArchiveHeight(&height))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(11), height)
+	})
+}
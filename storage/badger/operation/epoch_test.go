@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -65,4 +66,31 @@ func TestEpochEmergencyFallback(t *testing.T) {
 			assert.Equal(t, blockID, storedBlockID)
 		})
 	})
+	t.Run("should be able to remove flag and set it again", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			// removing the unset flag should return ErrNotFound
+			err := db.Update(RemoveEpochEmergencyFallbackTriggered())
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			err = db.Update(SetEpochEmergencyFallbackTriggered(blockID))
+			assert.NoError(t, err)
+
+			// remove the flag, should be false now
+			err = db.Update(RemoveEpochEmergencyFallbackTriggered())
+			assert.NoError(t, err)
+			var triggered bool
+			err = db.View(CheckEpochEmergencyFallbackTriggered(&triggered))
+			assert.NoError(t, err)
+			assert.False(t, triggered)
+
+			// setting the flag again should store the new block ID
+			otherBlockID := unittest.IdentifierFixture()
+			err = db.Update(SetEpochEmergencyFallbackTriggered(otherBlockID))
+			assert.NoError(t, err)
+			var storedBlockID flow.Identifier
+			err = db.View(RetrieveEpochEmergencyFallbackTriggeredBlockID(&storedBlockID))
+			assert.NoError(t, err)
+			assert.Equal(t, otherBlockID, storedBlockID)
+		})
+	})
 }
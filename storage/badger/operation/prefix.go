@@ -34,6 +34,7 @@ const (
 	codeLastCompleteBlockHeight = 25 // the height of the last block for which all collections were received
 	codeEpochFirstHeight        = 26 // the height of the first block in a given epoch
 	codeSealedRootHeight        = 27 // the height of the highest sealed block contained in the root snapshot
+	codeEpochArchiveHeight      = 28 // the height of the highest finalized block processed by the epoch archive
 
 	// codes for single entity storage
 	// 31 was used for identities before epochs
@@ -46,6 +47,7 @@ const (
 	codeExecutionReceiptMeta = 36
 	codeResultApproval       = 37
 	codeChunk                = 38
+	codeChunkFaultReport     = 39
 
 	// codes for indexing single identifier by identifier/integeter
 	codeHeightToBlock              = 40 // index mapping height to block ID
@@ -74,6 +76,8 @@ const (
 	codeDKGStarted       = 64 // flag that the DKG for an epoch has been started
 	codeDKGEnded         = 65 // flag that the DKG for an epoch has ended (stores end state)
 	codeVersionBeacon    = 67 // flag for storing version beacons
+	codeArchivedEpoch    = 68 // archived epoch, keyed by epoch counter
+	codeArchivedHeight   = 69 // index mapping the lowest archived height of an epoch to its epoch counter
 
 	// code for ComputationResult upload status storage
 	// NOTE: for now only GCP uploader is supported. When other uploader (AWS e.g.) needs to
@@ -85,6 +89,12 @@ const (
 	codeJobQueue             = 71
 	codeJobQueuePointer      = 72
 
+	// codes for persisted mempools
+	codePendingClusterTransaction = 80 // pending transactions of a cluster transaction pool, keyed by epoch counter and tx ID
+
+	// codes for persisted networking layer state
+	codeAlspSpamRecords = 85 // snapshot of the ALSP spam records, keyed by networking type
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                = 100
 	codeCommit                       = 101
@@ -0,0 +1,165 @@
+package operation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// prefixNames maps the prefix codes of the protocol database to human-readable names,
+// used to report the disk usage per prefix.
+var prefixNames = map[byte]string{
+	codeMax:    "max",
+	codeDBType: "db_type",
+
+	codeSafetyData:   "safety_data",
+	codeLivenessData: "liveness_data",
+
+	codeSporkID:                    "spork_id",
+	codeProtocolVersion:            "protocol_version",
+	codeEpochCommitSafetyThreshold: "epoch_commit_safety_threshold",
+	codeSporkRootBlockHeight:       "spork_root_block_height",
+
+	codeFinalizedHeight:         "finalized_height",
+	codeSealedHeight:            "sealed_height",
+	codeClusterHeight:           "cluster_height",
+	codeExecutedBlock:           "executed_block",
+	codeFinalizedRootHeight:     "finalized_root_height",
+	codeLastCompleteBlockHeight: "last_complete_block_height",
+	codeEpochFirstHeight:        "epoch_first_height",
+	codeSealedRootHeight:        "sealed_root_height",
+	codeEpochArchiveHeight:      "epoch_archive_height",
+
+	codeHeader:      "header",
+	codeGuarantee:   "guarantee",
+	codeSeal:        "seal",
+	codeTransaction: "transaction",
+	codeCollection:  "collection",
+	// execution results and execution receipt metas share the same code
+	codeExecutionResult:  "execution_result_or_receipt_meta",
+	codeResultApproval:   "result_approval",
+	codeChunk:            "chunk",
+	codeChunkFaultReport: "chunk_fault_report",
+
+	codeHeightToBlock:              "height_to_block",
+	codeBlockIDToLatestSealID:      "block_to_latest_seal",
+	codeClusterBlockToRefBlock:     "cluster_block_to_ref_block",
+	codeRefHeightToClusterBlock:    "ref_height_to_cluster_block",
+	codeBlockIDToFinalizedSeal:     "block_to_finalized_seal",
+	codeBlockIDToQuorumCertificate: "block_to_quorum_certificate",
+
+	codeBlockChildren:     "block_children",
+	codePayloadGuarantees: "payload_guarantees",
+	codePayloadSeals:      "payload_seals",
+	codeCollectionBlock:   "collection_block",
+	codeOwnBlockReceipt:   "own_block_receipt",
+	codeBlockEpochStatus:  "block_epoch_status",
+	codePayloadReceipts:   "payload_receipts",
+	codePayloadResults:    "payload_results",
+	codeAllBlockReceipts:  "all_block_receipts",
+
+	codeEpochSetup:       "epoch_setup",
+	codeEpochCommit:      "epoch_commit",
+	codeBeaconPrivateKey: "beacon_private_key",
+	codeDKGStarted:       "dkg_started",
+	codeDKGEnded:         "dkg_ended",
+	codeVersionBeacon:    "version_beacon",
+	codeArchivedEpoch:    "archived_epoch",
+	codeArchivedHeight:   "archived_height",
+
+	codeComputationResults: "computation_results",
+
+	codeJobConsumerProcessed: "job_consumer_processed",
+	codeJobQueue:             "job_queue",
+	codeJobQueuePointer:      "job_queue_pointer",
+
+	codePendingClusterTransaction: "pending_cluster_transaction",
+
+	codeAlspSpamRecords: "alsp_spam_records",
+
+	codeChunkDataPack:                "chunk_data_pack",
+	codeCommit:                       "commit",
+	codeEvent:                        "event",
+	codeExecutionStateInteractions:   "execution_state_interactions",
+	codeTransactionResult:            "transaction_result",
+	codeFinalizedCluster:             "finalized_cluster",
+	codeServiceEvent:                 "service_event",
+	codeTransactionResultIndex:       "transaction_result_index",
+	codeIndexCollection:              "index_collection",
+	codeIndexExecutionResultByBlock:  "index_execution_result_by_block",
+	codeIndexCollectionByTransaction: "index_collection_by_transaction",
+	codeIndexResultApprovalByChunk:   "index_result_approval_by_chunk",
+
+	blockedNodeIDs: "blocked_node_ids",
+
+	codeExecutionFork:                   "execution_fork",
+	codeEpochEmergencyFallbackTriggered: "epoch_emergency_fallback_triggered",
+}
+
+// PrefixName returns the human-readable name of the given prefix code.
+// Unknown codes are named by their numeric value.
+func PrefixName(code byte) string {
+	name, ok := prefixNames[code]
+	if !ok {
+		return fmt.Sprintf("unknown_%d", code)
+	}
+	return name
+}
+
+// PrefixStats is the disk usage of all keys stored under the same prefix code.
+type PrefixStats struct {
+	Code       byte
+	Name       string
+	Keys       uint64 // number of keys stored under the prefix
+	KeyBytes   uint64 // total size of the keys in bytes
+	ValueBytes uint64 // total size of the values in bytes
+}
+
+// TotalBytes returns the total size of the keys and values stored under the prefix.
+func (s PrefixStats) TotalBytes() uint64 {
+	return s.KeyBytes + s.ValueBytes
+}
+
+// ComputePrefixStats iterates over all keys of the database and computes the disk usage of
+// each prefix code. Only the keys are read, the value sizes are taken from the key metadata,
+// so that the values stored in the value log are not loaded.
+// The stats are sorted by prefix code, prefix codes without any key are omitted.
+// No errors are expected during normal operation.
+func ComputePrefixStats(stats *[]PrefixStats) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		byCode := make(map[byte]*PrefixStats)
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := item.Key()
+			if len(key) == 0 {
+				continue
+			}
+
+			code := key[0]
+			s, ok := byCode[code]
+			if !ok {
+				s = &PrefixStats{Code: code, Name: PrefixName(code)}
+				byCode[code] = s
+			}
+			s.Keys++
+			s.KeyBytes += uint64(len(key))
+			s.ValueBytes += uint64(item.ValueSize())
+		}
+
+		result := make([]PrefixStats, 0, len(byCode))
+		for _, s := range byCode {
+			result = append(result, *s)
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].Code < result[j].Code
+		})
+		*stats = result
+		return nil
+	}
+}
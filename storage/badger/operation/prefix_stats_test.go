@@ -0,0 +1,87 @@
+package operation
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestComputePrefixStats(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var stats []PrefixStats
+		err := db.View(ComputePrefixStats(&stats))
+		require.NoError(t, err)
+		assert.Empty(t, stats)
+
+		for i := 0; i < 3; i++ {
+			err = db.Update(IndexStateCommitment(unittest.IdentifierFixture(), unittest.StateCommitmentFixture()))
+			require.NoError(t, err)
+		}
+		err = db.Update(IndexBlockHeight(10, unittest.IdentifierFixture()))
+		require.NoError(t, err)
+
+		err = db.View(ComputePrefixStats(&stats))
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+
+		// stats are sorted by prefix code
+		heights := stats[0]
+		assert.Equal(t, byte(codeHeightToBlock), heights.Code)
+		assert.Equal(t, "height_to_block", heights.Name)
+		assert.Equal(t, uint64(1), heights.Keys)
+		assert.Equal(t, uint64(1+8), heights.KeyBytes)
+		assert.NotZero(t, heights.ValueBytes)
+
+		commits := stats[1]
+		assert.Equal(t, byte(codeCommit), commits.Code)
+		assert.Equal(t, "commit", commits.Name)
+		assert.Equal(t, uint64(3), commits.Keys)
+		assert.Equal(t, uint64(3*(1+32)), commits.KeyBytes)
+		assert.Equal(t, commits.KeyBytes+commits.ValueBytes, commits.TotalBytes())
+	})
+}
+
+func TestPrefixName(t *testing.T) {
+	assert.Equal(t, "header", PrefixName(codeHeader))
+	assert.Equal(t, "unknown_250", PrefixName(250))
+}
+
+// TestPrefixNames tests that every prefix code declared in prefix.go has a name, so that no prefix
+// is reported as unknown when a new code is added.
+func TestPrefixNames(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "prefix.go", nil, 0)
+	require.NoError(t, err)
+
+	codes := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				if !strings.HasPrefix(name.Name, "code") {
+					continue
+				}
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				require.True(t, ok, "prefix code %s is not a literal", name.Name)
+				code, ok := constant.Uint64Val(constant.MakeFromLiteral(lit.Value, lit.Kind, 0))
+				require.True(t, ok, "prefix code %s is not an integer", name.Name)
+
+				assert.Contains(t, prefixNames, byte(code), "prefix code %s has no name", name.Name)
+				codes++
+			}
+		}
+	}
+	require.NotZero(t, codes)
+}
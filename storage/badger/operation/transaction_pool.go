@@ -0,0 +1,73 @@
+package operation
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// UpsertPendingClusterTransaction persists a pending transaction of the cluster transaction
+// pool for the given epoch, keyed by epoch counter and transaction ID.
+// No errors are expected during normal operation.
+func UpsertPendingClusterTransaction(epoch uint64, tx *flow.TransactionBody) func(*badger.Txn) error {
+	return upsert(makePrefix(codePendingClusterTransaction, epoch, tx.ID()), tx)
+}
+
+// RemovePendingClusterTransaction removes a persisted pending transaction of the cluster
+// transaction pool for the given epoch.
+// Error returns:
+//   - storage.ErrNotFound if the transaction was not persisted
+func RemovePendingClusterTransaction(epoch uint64, txID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePendingClusterTransaction, epoch, txID))
+}
+
+// RemoveAllPendingClusterTransactions removes all persisted pending transactions of the
+// cluster transaction pool for the given epoch.
+// No errors are expected during normal operation.
+func RemoveAllPendingClusterTransactions(epoch uint64) func(*badger.Txn) error {
+	return removeByPrefix(makePrefix(codePendingClusterTransaction, epoch))
+}
+
+// RetrievePendingClusterTransactions retrieves all persisted pending transactions of the
+// cluster transaction pool for the given epoch.
+// No errors are expected during normal operation.
+func RetrievePendingClusterTransactions(epoch uint64, txs *[]*flow.TransactionBody) func(*badger.Txn) error {
+	return traverse(makePrefix(codePendingClusterTransaction, epoch), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var tx flow.TransactionBody
+		create := func() interface{} {
+			return &tx
+		}
+		handle := func() error {
+			*txs = append(*txs, &tx)
+			return nil
+		}
+		return check, create, handle
+	})
+}
+
+// RetrievePendingClusterTransactionEpochs retrieves the counters of all epochs with persisted
+// pending transactions, in ascending order.
+// No errors are expected during normal operation.
+func RetrievePendingClusterTransactionEpochs(epochs *[]uint64) func(*badger.Txn) error {
+	return iterate(
+		makePrefix(codePendingClusterTransaction, uint64(0)),
+		makePrefix(codePendingClusterTransaction, uint64(math.MaxUint64)),
+		func() (checkFunc, createFunc, handleFunc) {
+			check := func(key []byte) bool {
+				epoch := binary.BigEndian.Uint64(key[1:9])
+				if len(*epochs) == 0 || (*epochs)[len(*epochs)-1] != epoch {
+					*epochs = append(*epochs, epoch)
+				}
+				return false // we only need the key
+			}
+			return check, nil, nil
+		},
+		withPrefetchValuesFalse,
+	)
+}
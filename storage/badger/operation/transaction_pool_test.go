@@ -0,0 +1,55 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestPendingClusterTransactions(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		tx1 := unittest.TransactionBodyFixture()
+		tx2 := unittest.TransactionBodyFixture()
+		tx3 := unittest.TransactionBodyFixture()
+
+		require.NoError(t, db.Update(UpsertPendingClusterTransaction(1, &tx1)))
+		require.NoError(t, db.Update(UpsertPendingClusterTransaction(2, &tx2)))
+		require.NoError(t, db.Update(UpsertPendingClusterTransaction(2, &tx3)))
+		// upserting an existing transaction is a no-op
+		require.NoError(t, db.Update(UpsertPendingClusterTransaction(2, &tx3)))
+
+		var txs []*flow.TransactionBody
+		require.NoError(t, db.View(RetrievePendingClusterTransactions(2, &txs)))
+		assert.ElementsMatch(t, []*flow.TransactionBody{&tx2, &tx3}, txs)
+
+		t.Run("remove single transaction", func(t *testing.T) {
+			require.NoError(t, db.Update(RemovePendingClusterTransaction(2, tx2.ID())))
+			err := db.Update(RemovePendingClusterTransaction(2, tx2.ID()))
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			var txs []*flow.TransactionBody
+			require.NoError(t, db.View(RetrievePendingClusterTransactions(2, &txs)))
+			assert.Equal(t, []*flow.TransactionBody{&tx3}, txs)
+		})
+
+		t.Run("retrieve epochs", func(t *testing.T) {
+			var epochs []uint64
+			require.NoError(t, db.View(RetrievePendingClusterTransactionEpochs(&epochs)))
+			assert.Equal(t, []uint64{1, 2}, epochs)
+		})
+
+		t.Run("remove all transactions of epoch", func(t *testing.T) {
+			require.NoError(t, db.Update(RemoveAllPendingClusterTransactions(2)))
+
+			var txs []*flow.TransactionBody
+			require.NoError(t, db.View(RetrievePendingClusterTransactions(2, &txs)))
+			assert.Empty(t, txs)
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package badger
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// DefaultPrefixSizeReportInterval is the default interval in which the disk usage of each key prefix is reported.
+const DefaultPrefixSizeReportInterval = time.Hour
+
+// PrefixSizeReporter uses component.ComponentManager to implement module.Startable and module.ReadyDoneAware
+// to run an internal goroutine which periodically computes the disk usage of each key prefix of the
+// database and reports it as metrics. Computing the disk usage iterates over all keys of the database,
+// but does not read the values from the value log.
+type PrefixSizeReporter struct {
+	component.Component
+	log      zerolog.Logger
+	db       *badger.DB
+	metrics  module.DatabaseSizeMetrics
+	interval time.Duration
+}
+
+var _ component.Component = (*PrefixSizeReporter)(nil)
+
+// NewPrefixSizeReporter returns a reporter that reports the disk usage of each key prefix once every
+// `interval` duration. If an interval of zero is passed in, the disk usage is not reported at all.
+func NewPrefixSizeReporter(log zerolog.Logger, db *badger.DB, metrics module.DatabaseSizeMetrics, interval time.Duration) *PrefixSizeReporter {
+	r := &PrefixSizeReporter{
+		log:      log.With().Str("component", "prefix_size_reporter").Logger(),
+		db:       db,
+		metrics:  metrics,
+		interval: interval,
+	}
+
+	// Disable if passed in 0 as interval
+	if r.interval == 0 {
+		r.Component = &module.NoopComponent{}
+		return r
+	}
+
+	r.Component = component.NewComponentManagerBuilder().
+		AddWorker(r.reportWorkerRoutine).
+		Build()
+
+	return r
+}
+
+// reportWorkerRoutine reports the disk usage on startup and then on a timely basis.
+func (r *PrefixSizeReporter) reportWorkerRoutine(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.report()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.report()
+		}
+	}
+}
+
+// report computes the disk usage of each key prefix and reports it as metrics.
+func (r *PrefixSizeReporter) report() {
+	started := time.Now()
+	var stats []operation.PrefixStats
+	err := r.db.View(operation.ComputePrefixStats(&stats))
+	if err != nil {
+		r.log.Error().Err(err).Msg("could not compute prefix sizes")
+		return
+	}
+
+	var total uint64
+	for _, s := range stats {
+		r.metrics.PrefixSize(s.Name, s.Keys, s.TotalBytes())
+		total += s.TotalBytes()
+	}
+
+	r.log.Debug().
+		Int("prefixes", len(stats)).
+		Uint64("total_bytes", total).
+		Dur("duration", time.Since(started)).
+		Msg("prefix sizes reported")
+}
@@ -0,0 +1,58 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/testutils"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestStorageSuite runs the storage tests shared by all database backends against badger.
+func TestStorageSuite(t *testing.T) {
+	testutils.RunStorageSuite(t, func(t *testing.T, f func(backend testutils.Backend)) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			f(&backend{db: db, metrics: metrics.NewNoopCollector()})
+		})
+	})
+}
+
+// backend implements testutils.Backend for a badger database.
+type backend struct {
+	db      *badger.DB
+	metrics *metrics.NoopCollector
+}
+
+var _ testutils.Backend = (*backend)(nil)
+
+func (b *backend) All() *storage.All {
+	return bstorage.InitAll(b.metrics, b.db)
+}
+
+func (b *backend) NewBatch() storage.BatchStorage {
+	return bstorage.NewBatch(b.db)
+}
+
+func (b *backend) NewMyExecutionReceipts() storage.MyExecutionReceipts {
+	results := bstorage.NewExecutionResults(b.metrics, b.db)
+	receipts := bstorage.NewExecutionReceipts(b.metrics, b.db, results, bstorage.DefaultCacheSize)
+	return bstorage.NewMyExecutionReceipts(b.metrics, b.db, receipts)
+}
+
+func (b *backend) IndexBlockHeight(height uint64, blockID flow.Identifier) error {
+	return operation.RetryOnConflict(b.db.Update, operation.IndexBlockHeight(height, blockID))
+}
+
+func (b *backend) IndexLatestSealAtBlock(blockID flow.Identifier, sealID flow.Identifier) error {
+	return operation.RetryOnConflict(b.db.Update, operation.IndexLatestSealAtBlock(blockID, sealID))
+}
+
+func (b *backend) IndexFinalizedSealByBlockID(blockID flow.Identifier, sealID flow.Identifier) error {
+	return operation.RetryOnConflict(b.db.Update, operation.IndexFinalizedSealByBlockID(blockID, sealID))
+}
@@ -1,15 +1,23 @@
 package storage
 
-import "github.com/dgraph-io/badger/v2"
-
+// Transaction is the write-only view of a database transaction.
 type Transaction interface {
 	Set(key, val []byte) error
 }
 
+// BatchWriter is the write-only view of a batch of updates, which are applied atomically when the batch is flushed.
+// It is implemented by the write batches of all database backends.
+type BatchWriter interface {
+	Transaction
+
+	// Delete deletes the value under the given key, if it exists.
+	Delete(key []byte) error
+}
+
 // BatchStorage serves as an abstraction over batch storage, adding ability to add ability to add extra
 // callbacks which fire after the batch is successfully flushed.
 type BatchStorage interface {
-	GetWriter() *badger.WriteBatch
+	GetWriter() BatchWriter
 
 	// OnSucceed adds a callback to execute after the batch has
 	// been successfully flushed.
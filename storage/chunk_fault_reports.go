@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ChunkFaultReports persists the chunk faults found by a verification node.
+type ChunkFaultReports interface {
+
+	// Store persists a chunk fault report, replacing any report for the same chunk.
+	Store(report *chunks.FaultReport) error
+
+	// ByChunk retrieves the chunk fault report for the given chunk.
+	// Error returns:
+	//   - storage.ErrNotFound if no fault was reported for the chunk
+	ByChunk(resultID flow.Identifier, chunkIndex uint64) (*chunks.FaultReport, error)
+}
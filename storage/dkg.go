@@ -41,6 +41,15 @@ type DKGState interface {
 	// to guarantee only keys safe for signing are returned
 	// Error returns: storage.ErrNotFound
 	RetrieveMyBeaconPrivateKey(epochCounter uint64) (crypto.PrivateKey, error)
+
+	// RecoverMyBeaconPrivateKey stores the random beacon private key for an epoch,
+	// which reuses the DKG of a prior epoch, and sets the DKG end state for the epoch
+	// to success. Any key and end state previously stored for the epoch are overwritten.
+	//
+	// CAUTION: the key must have been validated against the canonical key vector
+	// of the epoch, as it is considered safe for signing once stored.
+	// No errors expected during normal operation.
+	RecoverMyBeaconPrivateKey(epochCounter uint64, key crypto.PrivateKey) error
 }
 
 // SafeBeaconKeys is a safe way to access beacon keys.
@@ -0,0 +1,70 @@
+// Code generated by mockery v2.21.4. DO NOT EDIT.
+
+package mock
+
+import (
+	chunks "github.com/onflow/flow-go/model/chunks"
+	flow "github.com/onflow/flow-go/model/flow"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ChunkFaultReports is an autogenerated mock type for the ChunkFaultReports type
+type ChunkFaultReports struct {
+	mock.Mock
+}
+
+// ByChunk provides a mock function with given fields: resultID, chunkIndex
+func (_m *ChunkFaultReports) ByChunk(resultID flow.Identifier, chunkIndex uint64) (*chunks.FaultReport, error) {
+	ret := _m.Called(resultID, chunkIndex)
+
+	var r0 *chunks.FaultReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(flow.Identifier, uint64) (*chunks.FaultReport, error)); ok {
+		return rf(resultID, chunkIndex)
+	}
+	if rf, ok := ret.Get(0).(func(flow.Identifier, uint64) *chunks.FaultReport); ok {
+		r0 = rf(resultID, chunkIndex)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*chunks.FaultReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(flow.Identifier, uint64) error); ok {
+		r1 = rf(resultID, chunkIndex)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: report
+func (_m *ChunkFaultReports) Store(report *chunks.FaultReport) error {
+	ret := _m.Called(report)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*chunks.FaultReport) error); ok {
+		r0 = rf(report)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewChunkFaultReports interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewChunkFaultReports creates a new instance of ChunkFaultReports. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewChunkFaultReports(t mockConstructorTestingTNewChunkFaultReports) *ChunkFaultReports {
+	mock := &ChunkFaultReports{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
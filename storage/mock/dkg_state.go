@@ -76,6 +76,20 @@ func (_m *DKGState) InsertMyBeaconPrivateKey(epochCounter uint64, key crypto.Pri
 	return r0
 }
 
+// RecoverMyBeaconPrivateKey provides a mock function with given fields: epochCounter, key
+func (_m *DKGState) RecoverMyBeaconPrivateKey(epochCounter uint64, key crypto.PrivateKey) error {
+	ret := _m.Called(epochCounter, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, crypto.PrivateKey) error); ok {
+		r0 = rf(epochCounter, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RetrieveMyBeaconPrivateKey provides a mock function with given fields: epochCounter
 func (_m *DKGState) RetrieveMyBeaconPrivateKey(epochCounter uint64) (crypto.PrivateKey, error) {
 	ret := _m.Called(epochCounter)
@@ -0,0 +1,44 @@
+package pebble
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+)
+
+// InitAll initializes the storage modules backed by the given Pebble DB.
+// The modules which do not have a Pebble implementation yet (quorum certificates, epoch setups, commits
+// and statuses, version beacons and chunk data packs) are left nil.
+func InitAll(metrics module.CacheMetrics, db *pebble.DB) *storage.All {
+	headers := NewHeaders(metrics, db)
+	guarantees := NewGuarantees(metrics, db, DefaultCacheSize)
+	seals := NewSeals(metrics, db)
+	index := NewIndex(metrics, db)
+	results := NewExecutionResults(metrics, db)
+	receipts := NewExecutionReceipts(metrics, db, results, DefaultCacheSize)
+	payloads := NewPayloads(db, index, guarantees, seals, receipts, results)
+	blocks := NewBlocks(db, headers, payloads)
+
+	commits := NewCommits(metrics, db)
+	transactions := NewTransactions(metrics, db)
+	transactionResults := NewTransactionResults(metrics, db, 10000)
+	collections := NewCollections(db, transactions)
+	events := NewEvents(metrics, db)
+
+	return &storage.All{
+		Headers:            headers,
+		Guarantees:         guarantees,
+		Seals:              seals,
+		Index:              index,
+		Payloads:           payloads,
+		Blocks:             blocks,
+		Results:            results,
+		Receipts:           receipts,
+		Commits:            commits,
+		Transactions:       transactions,
+		TransactionResults: transactionResults,
+		Collections:        collections,
+		Events:             events,
+	}
+}
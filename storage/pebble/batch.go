@@ -0,0 +1,76 @@
+package pebble
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ storage.BatchStorage = (*Batch)(nil)
+
+// Batch is a batch of updates to a Pebble DB, which are applied atomically when the batch is flushed.
+type Batch struct {
+	writer *batchWriter
+
+	lock      sync.RWMutex
+	callbacks []func()
+}
+
+func NewBatch(db *pebble.DB) *Batch {
+	return &Batch{
+		writer:    &batchWriter{batch: db.NewBatch()},
+		callbacks: make([]func(), 0),
+	}
+}
+
+func (b *Batch) GetWriter() storage.BatchWriter {
+	return b.writer
+}
+
+// OnSucceed adds a callback to execute after the batch has
+// been successfully flushed.
+// useful for implementing the cache where we will only cache
+// after the batch has been successfully flushed
+func (b *Batch) OnSucceed(callback func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.callbacks = append(b.callbacks, callback)
+}
+
+// Flush will commit the Pebble batch with a sync to disk, in
+// addition, it will call the callbacks added by OnSucceed.
+// The batch can not be used anymore after it has been flushed.
+func (b *Batch) Flush() error {
+	err := b.writer.batch.Commit(pebble.Sync)
+	if err != nil {
+		return err
+	}
+	err = b.writer.batch.Close()
+	if err != nil {
+		return err
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for _, callback := range b.callbacks {
+		callback()
+	}
+	return nil
+}
+
+// batchWriter adapts a Pebble batch to the storage.BatchWriter interface.
+type batchWriter struct {
+	batch *pebble.Batch
+}
+
+var _ storage.BatchWriter = (*batchWriter)(nil)
+
+func (w *batchWriter) Set(key, val []byte) error {
+	return w.batch.Set(key, val, nil)
+}
+
+func (w *batchWriter) Delete(key []byte) error {
+	return w.batch.Delete(key, nil)
+}
@@ -0,0 +1,171 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// Blocks implements a simple block storage around a Pebble DB.
+type Blocks struct {
+	db       *pebble.DB
+	headers  *Headers
+	payloads *Payloads
+	// heightLock serializes the updates of the last full block height, as Pebble has no
+	// transactions to atomically check for the existing height and insert the new one.
+	heightLock sync.Mutex
+}
+
+var _ storage.Blocks = (*Blocks)(nil)
+
+// NewBlocks ...
+func NewBlocks(db *pebble.DB, headers *Headers, payloads *Payloads) *Blocks {
+	b := &Blocks{
+		db:       db,
+		headers:  headers,
+		payloads: payloads,
+	}
+	return b
+}
+
+// StoreTx is not supported by the Pebble storage, as Pebble has no transactions.
+func (b *Blocks) StoreTx(*flow.Block) func(*transaction.Tx) error {
+	return func(*transaction.Tx) error {
+		return errNotSupported
+	}
+}
+
+func (b *Blocks) storeBatch(block *flow.Block) func(storage.BatchStorage) error {
+	return func(batch storage.BatchStorage) error {
+		err := b.headers.storeBatch(block.Header)(batch)
+		if err != nil {
+			return fmt.Errorf("could not store header %v: %w", block.Header.ID(), err)
+		}
+		err = b.payloads.storeBatch(b.db, block.ID(), block.Payload)(batch)
+		if err != nil {
+			return fmt.Errorf("could not store payload: %w", err)
+		}
+		return nil
+	}
+}
+
+func (b *Blocks) retrieve(blockID flow.Identifier) func(pebble.Reader) (*flow.Block, error) {
+	return func(r pebble.Reader) (*flow.Block, error) {
+		header, err := b.headers.retrieve(blockID)(r)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve header: %w", err)
+		}
+		payload, err := b.payloads.retrieve(blockID)(r)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve payload: %w", err)
+		}
+		block := &flow.Block{
+			Header:  header,
+			Payload: payload,
+		}
+		return block, nil
+	}
+}
+
+// Store ...
+func (b *Blocks) Store(block *flow.Block) error {
+	return withBatch(b.db, b.storeBatch(block))
+}
+
+// ByID ...
+func (b *Blocks) ByID(blockID flow.Identifier) (*flow.Block, error) {
+	snap := b.db.NewSnapshot()
+	defer snap.Close()
+	return b.retrieve(blockID)(snap)
+}
+
+// ByHeight ...
+func (b *Blocks) ByHeight(height uint64) (*flow.Block, error) {
+	snap := b.db.NewSnapshot()
+	defer snap.Close()
+
+	blockID, err := b.headers.retrieveIdByHeight(height)(snap)
+	if err != nil {
+		return nil, err
+	}
+	return b.retrieve(blockID)(snap)
+}
+
+// ByCollectionID ...
+func (b *Blocks) ByCollectionID(collID flow.Identifier) (*flow.Block, error) {
+	var blockID flow.Identifier
+	err := operation.LookupCollectionBlock(collID, &blockID)(b.db)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up block: %w", err)
+	}
+	return b.ByID(blockID)
+}
+
+// IndexBlockForCollections ...
+func (b *Blocks) IndexBlockForCollections(blockID flow.Identifier, collIDs []flow.Identifier) error {
+	return withBatch(b.db, func(batch storage.BatchStorage) error {
+		for _, collID := range collIDs {
+			err := operation.IndexCollectionBlock(collID, blockID)(batch.GetWriter())
+			if err != nil {
+				return fmt.Errorf("could not index collection block (%x): %w", collID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// InsertLastFullBlockHeightIfNotExists inserts the last full block height
+func (b *Blocks) InsertLastFullBlockHeightIfNotExists(height uint64) error {
+	b.heightLock.Lock()
+	defer b.heightLock.Unlock()
+
+	var current uint64
+	err := operation.RetrieveLastCompleteBlockHeight(&current)(b.db)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not check LastFullBlockHeight: %w", err)
+	}
+
+	err = b.updateLastFullBlockHeight(height)
+	if err != nil {
+		return fmt.Errorf("could not set LastFullBlockHeight: %w", err)
+	}
+	return nil
+}
+
+// UpdateLastFullBlockHeight upsert (update or insert) the last full block height
+func (b *Blocks) UpdateLastFullBlockHeight(height uint64) error {
+	b.heightLock.Lock()
+	defer b.heightLock.Unlock()
+
+	err := b.updateLastFullBlockHeight(height)
+	if err != nil {
+		return fmt.Errorf("could not update LastFullBlockHeight: %w", err)
+	}
+	return nil
+}
+
+func (b *Blocks) updateLastFullBlockHeight(height uint64) error {
+	return withBatch(b.db, func(batch storage.BatchStorage) error {
+		return operation.InsertLastCompleteBlockHeight(height)(batch.GetWriter())
+	})
+}
+
+// GetLastFullBlockHeight ...
+func (b *Blocks) GetLastFullBlockHeight() (uint64, error) {
+	var h uint64
+	err := operation.RetrieveLastCompleteBlockHeight(&h)(b.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve LastFullBlockHeight: %w", err)
+	}
+	return h, nil
+}
@@ -0,0 +1,155 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+type Collections struct {
+	db           *pebble.DB
+	transactions *Transactions
+}
+
+var _ storage.Collections = (*Collections)(nil)
+
+func NewCollections(db *pebble.DB, transactions *Transactions) *Collections {
+	c := &Collections{
+		db:           db,
+		transactions: transactions,
+	}
+	return c
+}
+
+func (c *Collections) StoreLight(collection *flow.LightCollection) error {
+	err := withBatch(c.db, func(batch storage.BatchStorage) error {
+		return operation.InsertCollection(collection)(batch.GetWriter())
+	})
+	if err != nil {
+		return fmt.Errorf("could not insert collection: %w", err)
+	}
+	return nil
+}
+
+func (c *Collections) Store(collection *flow.Collection) error {
+	return withBatch(c.db, func(batch storage.BatchStorage) error {
+		light := collection.Light()
+		err := operation.InsertCollection(&light)(batch.GetWriter())
+		if err != nil {
+			return fmt.Errorf("could not insert collection: %w", err)
+		}
+
+		for _, tx := range collection.Transactions {
+			err = c.transactions.storeBatch(tx)(batch)
+			if err != nil {
+				return fmt.Errorf("could not insert transaction: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *Collections) ByID(colID flow.Identifier) (*flow.Collection, error) {
+	snap := c.db.NewSnapshot()
+	defer snap.Close()
+
+	var (
+		light      flow.LightCollection
+		collection flow.Collection
+	)
+
+	err := operation.RetrieveCollection(colID, &light)(snap)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve collection: %w", err)
+	}
+
+	for _, txID := range light.Transactions {
+		tx, err := c.transactions.retrieve(txID)(snap)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve transaction: %w", err)
+		}
+
+		collection.Transactions = append(collection.Transactions, tx)
+	}
+
+	return &collection, nil
+}
+
+func (c *Collections) LightByID(colID flow.Identifier) (*flow.LightCollection, error) {
+	var collection flow.LightCollection
+
+	err := operation.RetrieveCollection(colID, &collection)(c.db)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve collection: %w", err)
+	}
+
+	return &collection, nil
+}
+
+func (c *Collections) Remove(colID flow.Identifier) error {
+	err := withBatch(c.db, func(batch storage.BatchStorage) error {
+		return operation.RemoveCollection(colID)(batch.GetWriter())
+	})
+	if err != nil {
+		return fmt.Errorf("could not remove collection: %w", err)
+	}
+	return nil
+}
+
+// StoreLightAndIndexByTransaction inserts the light collection and indexes it by each of its transactions.
+// Transactions which are already indexed keep the collection they were first indexed with, see the
+// storage.Collections interface.
+// No errors are expected during normal operation.
+func (c *Collections) StoreLightAndIndexByTransaction(collection *flow.LightCollection) error {
+	snap := c.db.NewSnapshot()
+	defer snap.Close()
+
+	return withBatch(c.db, func(batch storage.BatchStorage) error {
+		err := operation.InsertCollection(collection)(batch.GetWriter())
+		if err != nil {
+			return fmt.Errorf("could not insert collection: %w", err)
+		}
+
+		for _, txID := range collection.Transactions {
+			var indexed flow.Identifier
+			err = operation.RetrieveCollectionID(txID, &indexed)(snap)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, storage.ErrNotFound) {
+				return fmt.Errorf("could not check transaction ID index: %w", err)
+			}
+			err = operation.IndexCollectionByTransaction(txID, collection.ID())(batch.GetWriter())
+			if err != nil {
+				return fmt.Errorf("could not insert transaction ID: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *Collections) LightByTransactionID(txID flow.Identifier) (*flow.LightCollection, error) {
+	snap := c.db.NewSnapshot()
+	defer snap.Close()
+
+	var collID flow.Identifier
+	err := operation.RetrieveCollectionID(txID, &collID)(snap)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve collection id: %w", err)
+	}
+
+	var collection flow.LightCollection
+	err = operation.RetrieveCollection(collID, &collection)(snap)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve collection: %w", err)
+	}
+
+	return &collection, nil
+}
@@ -0,0 +1,73 @@
+package pebble
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+type Commits struct {
+	db    *pebble.DB
+	cache *Cache[flow.Identifier, flow.StateCommitment]
+}
+
+var _ storage.Commits = (*Commits)(nil)
+
+func NewCommits(collector module.CacheMetrics, db *pebble.DB) *Commits {
+
+	store := func(blockID flow.Identifier, commit flow.StateCommitment) func(storage.BatchWriter) error {
+		return operation.IndexStateCommitment(blockID, commit)
+	}
+
+	retrieve := func(blockID flow.Identifier) func(pebble.Reader) (flow.StateCommitment, error) {
+		return func(r pebble.Reader) (flow.StateCommitment, error) {
+			var commit flow.StateCommitment
+			err := operation.LookupStateCommitment(blockID, &commit)(r)
+			return commit, err
+		}
+	}
+
+	c := &Commits{
+		db: db,
+		cache: newCache[flow.Identifier, flow.StateCommitment](collector, metrics.ResourceCommit,
+			withLimit[flow.Identifier, flow.StateCommitment](1000),
+			withStore(store),
+			withRetrieve(retrieve),
+		),
+	}
+
+	return c
+}
+
+func (c *Commits) Store(blockID flow.Identifier, commit flow.StateCommitment) error {
+	return withBatch(c.db, c.cache.PutBatch(blockID, commit))
+}
+
+// BatchStore stores Commit keyed by blockID in provided batch
+// No errors are expected during normal operation, even if no entries are matched.
+func (c *Commits) BatchStore(blockID flow.Identifier, commit flow.StateCommitment, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	return operation.IndexStateCommitment(blockID, commit)(writeBatch)
+}
+
+func (c *Commits) ByBlockID(blockID flow.Identifier) (flow.StateCommitment, error) {
+	val, err := c.cache.Get(blockID)(c.db)
+	if err != nil {
+		return flow.DummyStateCommitment, err
+	}
+	return val, nil
+}
+
+// BatchRemoveByBlockID removes Commit keyed by blockID in provided batch
+// No errors are expected during normal operation, even if no entries are matched.
+func (c *Commits) BatchRemoveByBlockID(blockID flow.Identifier, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	batch.OnSucceed(func() {
+		c.cache.Remove(blockID)
+	})
+	return operation.RemoveStateCommitment(blockID)(writeBatch)
+}
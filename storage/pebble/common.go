@@ -0,0 +1,26 @@
+package pebble
+
+import (
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+// withBatch creates a new batch, executes the given operation against it and flushes the batch
+// if the operation succeeded.
+// No errors are expected during normal operation, other than the ones returned by the operation.
+func withBatch(db *pebble.DB, op func(storage.BatchStorage) error) error {
+	batch := NewBatch(db)
+	err := op(batch)
+	if err != nil {
+		_ = batch.writer.batch.Close()
+		return err
+	}
+	return batch.Flush()
+}
+
+// errNotSupported is returned by the operations of the storage interfaces which are
+// specific to badger transactions, and are not supported by the Pebble implementation.
+var errNotSupported = errors.New("operation is not supported by the pebble storage")
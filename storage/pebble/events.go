@@ -0,0 +1,197 @@
+package pebble
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+type Events struct {
+	db    *pebble.DB
+	cache *Cache[flow.Identifier, []flow.Event]
+}
+
+var _ storage.Events = (*Events)(nil)
+
+func NewEvents(collector module.CacheMetrics, db *pebble.DB) *Events {
+	retrieve := func(blockID flow.Identifier) func(pebble.Reader) ([]flow.Event, error) {
+		return func(r pebble.Reader) ([]flow.Event, error) {
+			var events []flow.Event
+			err := operation.LookupEventsByBlockID(blockID, &events)(r)
+			return events, err
+		}
+	}
+
+	return &Events{
+		db: db,
+		cache: newCache[flow.Identifier, []flow.Event](collector, metrics.ResourceEvents,
+			withRetrieve(retrieve)),
+	}
+}
+
+// BatchStore stores events keyed by a blockID in provided batch
+// No errors are expected during normal operation, but it may return generic error
+// if Pebble fails to batch the events.
+func (e *Events) BatchStore(blockID flow.Identifier, blockEvents []flow.EventsList, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+
+	// pre-allocating and indexing faster than appending
+	sliceSize := 0
+	for _, b := range blockEvents {
+		sliceSize += len(b)
+	}
+
+	combinedEvents := make([]flow.Event, sliceSize)
+
+	eventIndex := 0
+
+	for _, events := range blockEvents {
+		for _, event := range events {
+			err := operation.InsertEvent(blockID, event)(writeBatch)
+			if err != nil {
+				return fmt.Errorf("cannot batch insert event: %w", err)
+			}
+			combinedEvents[eventIndex] = event
+			eventIndex++
+		}
+	}
+
+	callback := func() {
+		e.cache.Insert(blockID, combinedEvents)
+	}
+	batch.OnSucceed(callback)
+	return nil
+}
+
+// ByBlockID returns the events for the given block ID
+func (e *Events) ByBlockID(blockID flow.Identifier) ([]flow.Event, error) {
+	val, err := e.cache.Get(blockID)(e.db)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// ByBlockIDTransactionID returns the events for the given block ID and transaction ID
+func (e *Events) ByBlockIDTransactionID(blockID flow.Identifier, txID flow.Identifier) ([]flow.Event, error) {
+	events, err := e.ByBlockID(blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []flow.Event
+	for _, event := range events {
+		if event.TransactionID == txID {
+			matched = append(matched, event)
+		}
+	}
+	return matched, nil
+}
+
+// ByBlockIDTransactionIndex returns the events for the given block ID and transaction index
+func (e *Events) ByBlockIDTransactionIndex(blockID flow.Identifier, txIndex uint32) ([]flow.Event, error) {
+	events, err := e.ByBlockID(blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []flow.Event
+	for _, event := range events {
+		if event.TransactionIndex == txIndex {
+			matched = append(matched, event)
+		}
+	}
+	return matched, nil
+}
+
+// ByBlockIDEventType returns the events for the given block ID and event type
+func (e *Events) ByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error) {
+	events, err := e.ByBlockID(blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []flow.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched, nil
+}
+
+// BatchRemoveByBlockID removes events keyed by a blockID in provided batch
+// No errors are expected during normal operation, even if no entries are matched.
+func (e *Events) BatchRemoveByBlockID(blockID flow.Identifier, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	batch.OnSucceed(func() {
+		e.cache.Remove(blockID)
+	})
+	return operation.RemoveEventsByBlockID(e.db, blockID)(writeBatch)
+}
+
+type ServiceEvents struct {
+	db    *pebble.DB
+	cache *Cache[flow.Identifier, []flow.Event]
+}
+
+var _ storage.ServiceEvents = (*ServiceEvents)(nil)
+
+func NewServiceEvents(collector module.CacheMetrics, db *pebble.DB) *ServiceEvents {
+	retrieve := func(blockID flow.Identifier) func(pebble.Reader) ([]flow.Event, error) {
+		return func(r pebble.Reader) ([]flow.Event, error) {
+			var events []flow.Event
+			err := operation.LookupServiceEventsByBlockID(blockID, &events)(r)
+			return events, err
+		}
+	}
+
+	return &ServiceEvents{
+		db: db,
+		cache: newCache[flow.Identifier, []flow.Event](collector, metrics.ResourceEvents,
+			withRetrieve(retrieve)),
+	}
+}
+
+// BatchStore stores service events keyed by a blockID in provided batch
+// No errors are expected during normal operation, even if no entries are matched.
+func (e *ServiceEvents) BatchStore(blockID flow.Identifier, events []flow.Event, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	for _, event := range events {
+		err := operation.InsertServiceEvent(blockID, event)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("cannot batch insert service event: %w", err)
+		}
+	}
+
+	callback := func() {
+		e.cache.Insert(blockID, events)
+	}
+	batch.OnSucceed(callback)
+	return nil
+}
+
+// ByBlockID returns the events for the given block ID
+func (e *ServiceEvents) ByBlockID(blockID flow.Identifier) ([]flow.Event, error) {
+	val, err := e.cache.Get(blockID)(e.db)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// BatchRemoveByBlockID removes service events keyed by a blockID in provided batch
+// No errors are expected during normal operation, even if no entries are matched.
+func (e *ServiceEvents) BatchRemoveByBlockID(blockID flow.Identifier, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	batch.OnSucceed(func() {
+		e.cache.Remove(blockID)
+	})
+	return operation.RemoveServiceEventsByBlockID(e.db, blockID)(writeBatch)
+}
@@ -0,0 +1,66 @@
+package pebble
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// Guarantees implements persistent storage for collection guarantees.
+type Guarantees struct {
+	db    *pebble.DB
+	cache *Cache[flow.Identifier, *flow.CollectionGuarantee]
+}
+
+var _ storage.Guarantees = (*Guarantees)(nil)
+
+func NewGuarantees(collector module.CacheMetrics, db *pebble.DB, cacheSize uint) *Guarantees {
+
+	store := func(collID flow.Identifier, guarantee *flow.CollectionGuarantee) func(storage.BatchWriter) error {
+		return operation.InsertGuarantee(collID, guarantee)
+	}
+
+	retrieve := func(collID flow.Identifier) func(pebble.Reader) (*flow.CollectionGuarantee, error) {
+		return func(r pebble.Reader) (*flow.CollectionGuarantee, error) {
+			var guarantee flow.CollectionGuarantee
+			err := operation.RetrieveGuarantee(collID, &guarantee)(r)
+			return &guarantee, err
+		}
+	}
+
+	g := &Guarantees{
+		db: db,
+		cache: newCache[flow.Identifier, *flow.CollectionGuarantee](collector, metrics.ResourceGuarantee,
+			withLimit[flow.Identifier, *flow.CollectionGuarantee](cacheSize),
+			withStore(store),
+			withRetrieve(retrieve)),
+	}
+
+	return g
+}
+
+func (g *Guarantees) storeBatch(guarantee *flow.CollectionGuarantee) func(storage.BatchStorage) error {
+	return g.cache.PutBatch(guarantee.ID(), guarantee)
+}
+
+func (g *Guarantees) retrieve(collID flow.Identifier) func(pebble.Reader) (*flow.CollectionGuarantee, error) {
+	return func(r pebble.Reader) (*flow.CollectionGuarantee, error) {
+		val, err := g.cache.Get(collID)(r)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+}
+
+func (g *Guarantees) Store(guarantee *flow.CollectionGuarantee) error {
+	return withBatch(g.db, g.storeBatch(guarantee))
+}
+
+func (g *Guarantees) ByCollectionID(collID flow.Identifier) (*flow.CollectionGuarantee, error) {
+	return g.retrieve(collID)(g.db)
+}
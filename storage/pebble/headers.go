@@ -0,0 +1,165 @@
+package pebble
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// Headers implements a simple read-only header storage around a Pebble DB.
+type Headers struct {
+	db          *pebble.DB
+	cache       *Cache[flow.Identifier, *flow.Header]
+	heightCache *Cache[uint64, flow.Identifier]
+}
+
+var _ storage.Headers = (*Headers)(nil)
+
+func NewHeaders(collector module.CacheMetrics, db *pebble.DB) *Headers {
+
+	store := func(blockID flow.Identifier, header *flow.Header) func(storage.BatchWriter) error {
+		return operation.InsertHeader(blockID, header)
+	}
+
+	// CAUTION: should only be used to index FINALIZED blocks by their
+	// respective height
+	storeHeight := func(height uint64, id flow.Identifier) func(storage.BatchWriter) error {
+		return operation.IndexBlockHeight(height, id)
+	}
+
+	retrieve := func(blockID flow.Identifier) func(pebble.Reader) (*flow.Header, error) {
+		return func(r pebble.Reader) (*flow.Header, error) {
+			var header flow.Header
+			err := operation.RetrieveHeader(blockID, &header)(r)
+			return &header, err
+		}
+	}
+
+	retrieveHeight := func(height uint64) func(pebble.Reader) (flow.Identifier, error) {
+		return func(r pebble.Reader) (flow.Identifier, error) {
+			var id flow.Identifier
+			err := operation.LookupBlockHeight(height, &id)(r)
+			return id, err
+		}
+	}
+
+	h := &Headers{
+		db: db,
+		cache: newCache[flow.Identifier, *flow.Header](collector, metrics.ResourceHeader,
+			withLimit[flow.Identifier, *flow.Header](4*flow.DefaultTransactionExpiry),
+			withStore(store),
+			withRetrieve(retrieve)),
+
+		heightCache: newCache[uint64, flow.Identifier](collector, metrics.ResourceFinalizedHeight,
+			withLimit[uint64, flow.Identifier](4*flow.DefaultTransactionExpiry),
+			withStore(storeHeight),
+			withRetrieve(retrieveHeight)),
+	}
+
+	return h
+}
+
+func (h *Headers) storeBatch(header *flow.Header) func(storage.BatchStorage) error {
+	return h.cache.PutBatch(header.ID(), header)
+}
+
+func (h *Headers) retrieve(blockID flow.Identifier) func(pebble.Reader) (*flow.Header, error) {
+	return func(r pebble.Reader) (*flow.Header, error) {
+		val, err := h.cache.Get(blockID)(r)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+}
+
+// results in `storage.ErrNotFound` for unknown height
+func (h *Headers) retrieveIdByHeight(height uint64) func(pebble.Reader) (flow.Identifier, error) {
+	return func(r pebble.Reader) (flow.Identifier, error) {
+		blockID, err := h.heightCache.Get(height)(r)
+		if err != nil {
+			return flow.ZeroID, fmt.Errorf("failed to retrieve block ID for height %d: %w", height, err)
+		}
+		return blockID, nil
+	}
+}
+
+// Store stores the header. Headers are keyed by their ID, storing the same header twice is a no-op.
+func (h *Headers) Store(header *flow.Header) error {
+	return withBatch(h.db, h.storeBatch(header))
+}
+
+// IndexByHeight indexes the given finalized block by its height.
+// CAUTION: should only be used to index FINALIZED blocks by their respective height.
+// No errors are expected during normal operation.
+func (h *Headers) IndexByHeight(height uint64, blockID flow.Identifier) error {
+	return withBatch(h.db, h.heightCache.PutBatch(height, blockID))
+}
+
+func (h *Headers) ByBlockID(blockID flow.Identifier) (*flow.Header, error) {
+	return h.retrieve(blockID)(h.db)
+}
+
+func (h *Headers) ByHeight(height uint64) (*flow.Header, error) {
+	snap := h.db.NewSnapshot()
+	defer snap.Close()
+
+	blockID, err := h.retrieveIdByHeight(height)(snap)
+	if err != nil {
+		return nil, err
+	}
+	return h.retrieve(blockID)(snap)
+}
+
+// Exists returns true if a header with the given ID has been stored.
+// No errors are expected during normal operation.
+func (h *Headers) Exists(blockID flow.Identifier) (bool, error) {
+	// if the block is in the cache, return true
+	if ok := h.cache.IsCached(blockID); ok {
+		return ok, nil
+	}
+	// otherwise, check pebble store
+	var exists bool
+	err := operation.BlockExists(blockID, &exists)(h.db)
+	if err != nil {
+		return false, fmt.Errorf("could not check existence: %w", err)
+	}
+	return exists, nil
+}
+
+// BlockIDByHeight returns the block ID that is finalized at the given height. It is an optimized
+// version of `ByHeight` that skips retrieving the block. Expected errors during normal operations:
+//   - `storage.ErrNotFound` if no finalized block is known at given height.
+func (h *Headers) BlockIDByHeight(height uint64) (flow.Identifier, error) {
+	blockID, err := h.retrieveIdByHeight(height)(h.db)
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not lookup block id by height %d: %w", height, err)
+	}
+	return blockID, nil
+}
+
+func (h *Headers) ByParentID(parentID flow.Identifier) ([]*flow.Header, error) {
+	snap := h.db.NewSnapshot()
+	defer snap.Close()
+
+	var blockIDs flow.IdentifierList
+	err := operation.RetrieveBlockChildren(parentID, &blockIDs)(snap)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up children: %w", err)
+	}
+	headers := make([]*flow.Header, 0, len(blockIDs))
+	for _, blockID := range blockIDs {
+		header, err := h.retrieve(blockID)(snap)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve child (%x): %w", blockID, err)
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
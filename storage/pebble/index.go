@@ -0,0 +1,125 @@
+package pebble
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// Index implements a simple read-only payload storage around a Pebble DB.
+type Index struct {
+	db    *pebble.DB
+	cache *Cache[flow.Identifier, *flow.Index]
+}
+
+var _ storage.Index = (*Index)(nil)
+
+func NewIndex(collector module.CacheMetrics, db *pebble.DB) *Index {
+
+	store := func(blockID flow.Identifier, index *flow.Index) func(storage.BatchWriter) error {
+		return insertIndex(blockID, index)
+	}
+
+	retrieve := func(blockID flow.Identifier) func(pebble.Reader) (*flow.Index, error) {
+		return func(r pebble.Reader) (*flow.Index, error) {
+			var index flow.Index
+			err := retrieveIndex(blockID, &index)(r)
+			return &index, err
+		}
+	}
+
+	p := &Index{
+		db: db,
+		cache: newCache[flow.Identifier, *flow.Index](collector, metrics.ResourceIndex,
+			withLimit[flow.Identifier, *flow.Index](flow.DefaultTransactionExpiry+100),
+			withStore(store),
+			withRetrieve(retrieve)),
+	}
+
+	return p
+}
+
+func (i *Index) storeBatch(blockID flow.Identifier, index *flow.Index) func(storage.BatchStorage) error {
+	return i.cache.PutBatch(blockID, index)
+}
+
+func (i *Index) retrieve(blockID flow.Identifier) func(pebble.Reader) (*flow.Index, error) {
+	return func(r pebble.Reader) (*flow.Index, error) {
+		val, err := i.cache.Get(blockID)(r)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+}
+
+func (i *Index) Store(blockID flow.Identifier, index *flow.Index) error {
+	return withBatch(i.db, i.storeBatch(blockID, index))
+}
+
+func (i *Index) ByBlockID(blockID flow.Identifier) (*flow.Index, error) {
+	snap := i.db.NewSnapshot()
+	defer snap.Close()
+	return i.retrieve(blockID)(snap)
+}
+
+func insertIndex(blockID flow.Identifier, index *flow.Index) func(storage.BatchWriter) error {
+	return func(w storage.BatchWriter) error {
+		err := operation.IndexPayloadGuarantees(blockID, index.CollectionIDs)(w)
+		if err != nil {
+			return fmt.Errorf("could not store guarantee index: %w", err)
+		}
+		err = operation.IndexPayloadSeals(blockID, index.SealIDs)(w)
+		if err != nil {
+			return fmt.Errorf("could not store seal index: %w", err)
+		}
+		err = operation.IndexPayloadReceipts(blockID, index.ReceiptIDs)(w)
+		if err != nil {
+			return fmt.Errorf("could not store receipts index: %w", err)
+		}
+		err = operation.IndexPayloadResults(blockID, index.ResultIDs)(w)
+		if err != nil {
+			return fmt.Errorf("could not store results index: %w", err)
+		}
+		return nil
+	}
+}
+
+func retrieveIndex(blockID flow.Identifier, index *flow.Index) func(pebble.Reader) error {
+	return func(r pebble.Reader) error {
+		var collIDs []flow.Identifier
+		err := operation.LookupPayloadGuarantees(blockID, &collIDs)(r)
+		if err != nil {
+			return fmt.Errorf("could not retrieve guarantee index: %w", err)
+		}
+		var sealIDs []flow.Identifier
+		err = operation.LookupPayloadSeals(blockID, &sealIDs)(r)
+		if err != nil {
+			return fmt.Errorf("could not retrieve seal index: %w", err)
+		}
+		var receiptIDs []flow.Identifier
+		err = operation.LookupPayloadReceipts(blockID, &receiptIDs)(r)
+		if err != nil {
+			return fmt.Errorf("could not retrieve receipts index: %w", err)
+		}
+		var resultsIDs []flow.Identifier
+		err = operation.LookupPayloadResults(blockID, &resultsIDs)(r)
+		if err != nil {
+			return fmt.Errorf("could not retrieve results index: %w", err)
+		}
+
+		*index = flow.Index{
+			CollectionIDs: collIDs,
+			SealIDs:       sealIDs,
+			ReceiptIDs:    receiptIDs,
+			ResultIDs:     resultsIDs,
+		}
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// MyExecutionReceipts holds and indexes Execution Receipts.
+// MyExecutionReceipts is implemented as a wrapper around pebble.ExecutionReceipts
+// The wrapper adds the ability to "MY execution receipt", from the viewpoint
+// of an individual Execution Node.
+type MyExecutionReceipts struct {
+	genericReceipts *ExecutionReceipts
+	db              *pebble.DB
+	cache           *Cache[flow.Identifier, *flow.ExecutionReceipt]
+	// indexLock serializes the indexing of my receipts, as Pebble has no transactions to
+	// atomically check for an already indexed receipt and index the new one.
+	indexLock sync.Mutex
+}
+
+var _ storage.MyExecutionReceipts = (*MyExecutionReceipts)(nil)
+
+// NewMyExecutionReceipts creates instance of MyExecutionReceipts which is a wrapper wrapper around pebble.ExecutionReceipts
+// It's useful for execution nodes to keep track of produced execution receipts.
+func NewMyExecutionReceipts(collector module.CacheMetrics, db *pebble.DB, receipts *ExecutionReceipts) *MyExecutionReceipts {
+	store := func(blockID flow.Identifier, receipt *flow.ExecutionReceipt) func(storage.BatchWriter) error {
+		return operation.IndexOwnExecutionReceipt(blockID, receipt.ID())
+	}
+
+	retrieve := func(blockID flow.Identifier) func(pebble.Reader) (*flow.ExecutionReceipt, error) {
+		return func(r pebble.Reader) (*flow.ExecutionReceipt, error) {
+			var receiptID flow.Identifier
+			err := operation.LookupOwnExecutionReceipt(blockID, &receiptID)(r)
+			if err != nil {
+				return nil, fmt.Errorf("could not lookup receipt ID: %w", err)
+			}
+			receipt, err := receipts.byID(receiptID)(r)
+			if err != nil {
+				return nil, err
+			}
+			return receipt, nil
+		}
+	}
+
+	return &MyExecutionReceipts{
+		genericReceipts: receipts,
+		db:              db,
+		cache: newCache[flow.Identifier, *flow.ExecutionReceipt](collector, metrics.ResourceMyReceipt,
+			withLimit[flow.Identifier, *flow.ExecutionReceipt](flow.DefaultTransactionExpiry+100),
+			withStore(store),
+			withRetrieve(retrieve)),
+	}
+}
+
+// StoreMyReceipt stores the receipt and marks it as mine (trusted). My
+// receipts are indexed by the block whose result they compute. Currently,
+// we only support indexing a _single_ receipt per block. Attempting to
+// store conflicting receipts for the same block will error.
+func (m *MyExecutionReceipts) StoreMyReceipt(receipt *flow.ExecutionReceipt) error {
+	m.indexLock.Lock()
+	defer m.indexLock.Unlock()
+
+	blockID := receipt.ExecutionResult.BlockID
+	receiptID := receipt.ID()
+
+	// check if we are storing same receipt
+	var savedReceiptID flow.Identifier
+	err := operation.LookupOwnExecutionReceipt(blockID, &savedReceiptID)(m.db)
+	if err == nil {
+		if savedReceiptID == receiptID {
+			// if we are storing same receipt we shouldn't error
+			return nil
+		}
+		return fmt.Errorf("indexing my receipt %v failed: different receipt %v for the same block %v is already indexed", receiptID,
+			savedReceiptID, blockID)
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not check for an already indexed receipt: %w", err)
+	}
+
+	return withBatch(m.db, func(batch storage.BatchStorage) error {
+		err := m.genericReceipts.storeBatch(receipt)(batch)
+		if err != nil {
+			return fmt.Errorf("could not store receipt: %w", err)
+		}
+		err = m.cache.PutBatch(blockID, receipt)(batch)
+		if err != nil {
+			return fmt.Errorf("could not index receipt as one of my own: %w", err)
+		}
+		return nil
+	})
+}
+
+// BatchStoreMyReceipt stores blockID-to-my-receipt index entry keyed by blockID in a provided batch.
+// No errors are expected during normal operation
+// If entity fails marshalling, the error is wrapped in a generic error and returned.
+func (m *MyExecutionReceipts) BatchStoreMyReceipt(receipt *flow.ExecutionReceipt, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+
+	err := m.genericReceipts.BatchStore(receipt, batch)
+	if err != nil {
+		return fmt.Errorf("cannot batch store generic execution receipt inside my execution receipt batch store: %w", err)
+	}
+
+	err = operation.IndexOwnExecutionReceipt(receipt.ExecutionResult.BlockID, receipt.ID())(writeBatch)
+	if err != nil {
+		return fmt.Errorf("cannot batch index own execution receipt inside my execution receipt batch store: %w", err)
+	}
+
+	return nil
+}
+
+// MyReceipt retrieves my receipt for the given block.
+// Returns storage.ErrNotFound if no receipt was persisted for the block.
+func (m *MyExecutionReceipts) MyReceipt(blockID flow.Identifier) (*flow.ExecutionReceipt, error) {
+	snap := m.db.NewSnapshot()
+	defer snap.Close()
+	return m.cache.Get(blockID)(snap)
+}
+
+// BatchRemoveIndexByBlockID removes blockID-to-my-execution-receipt index entry keyed by a blockID in a provided batch
+// No errors are expected during normal operation, even if no entries are matched.
+func (m *MyExecutionReceipts) BatchRemoveIndexByBlockID(blockID flow.Identifier, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	batch.OnSucceed(func() {
+		m.cache.Remove(blockID)
+	})
+	return operation.RemoveOwnExecutionReceipt(blockID)(writeBatch)
+}
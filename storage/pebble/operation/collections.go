@@ -0,0 +1,30 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+func InsertCollection(collection *flow.LightCollection) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeCollection, collection.ID()), collection)
+}
+
+func RetrieveCollection(collID flow.Identifier, collection *flow.LightCollection) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeCollection, collID), collection)
+}
+
+func RemoveCollection(collID flow.Identifier) func(storage.BatchWriter) error {
+	return remove(makePrefix(codeCollection, collID))
+}
+
+// IndexCollectionByTransaction inserts a collection id keyed by a transaction id
+func IndexCollectionByTransaction(txID flow.Identifier, collectionID flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeIndexCollectionByTransaction, txID), collectionID)
+}
+
+// RetrieveCollectionID retrieves a collection id by transaction id
+func RetrieveCollectionID(txID flow.Identifier, collectionID *flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeIndexCollectionByTransaction, txID), collectionID)
+}
@@ -0,0 +1,27 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// IndexStateCommitment indexes a state commitment.
+//
+// State commitments are keyed by the block whose execution results in the state with the given commit.
+func IndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeCommit, blockID), commit)
+}
+
+// LookupStateCommitment gets a state commitment keyed by block ID
+//
+// State commitments are keyed by the block whose execution results in the state with the given commit.
+func LookupStateCommitment(blockID flow.Identifier, commit *flow.StateCommitment) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeCommit, blockID), commit)
+}
+
+// RemoveStateCommitment removes the state commitment by block ID
+func RemoveStateCommitment(blockID flow.Identifier) func(storage.BatchWriter) error {
+	return remove(makePrefix(codeCommit, blockID))
+}
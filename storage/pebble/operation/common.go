@@ -0,0 +1,215 @@
+package operation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Pebble does not support transactions. All writes are applied through a storage.BatchWriter,
+// which applies them atomically when the batch is committed, while all reads are executed
+// against a pebble.Reader, which is either the database itself or a consistent snapshot of it.
+// As a consequence, the write operations of this package can not check for the existence of
+// a key before writing it, and they overwrite any existing value. Storage implementations that
+// need insert-if-absent semantics check for the existence of the key before writing the batch.
+
+// insert will encode the given entity using msgpack and will write the resulting
+// binary data under the provided key in the given batch. If the value already exists
+// in the database it will be overridden.
+// No errors are expected during normal operation.
+func insert(key []byte, entity interface{}) func(storage.BatchWriter) error {
+	return func(w storage.BatchWriter) error {
+
+		// serialize the entity data
+		val, err := msgpack.Marshal(entity)
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not encode entity: %w", err)
+		}
+
+		// persist the entity data into the batch
+		err = w.Set(key, val)
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not store data: %w", err)
+		}
+		return nil
+	}
+}
+
+// remove removes the entry under the given key in the given batch.
+// If the key doesn't exist, this is a no-op.
+// No errors are expected during normal operation.
+func remove(key []byte) func(storage.BatchWriter) error {
+	return func(w storage.BatchWriter) error {
+		err := w.Delete(key)
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not delete item: %w", err)
+		}
+		return nil
+	}
+}
+
+// removeByPrefix removes all the entries whose key starts with the given prefix in the given batch.
+// The keys are read from the given reader. If no key matches, this is a no-op.
+// No errors are expected during normal operation.
+func removeByPrefix(r pebble.Reader, prefix []byte) func(storage.BatchWriter) error {
+	return func(w storage.BatchWriter) error {
+		it, err := r.NewIter(prefixIterOptions(prefix))
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not create iterator: %w", err)
+		}
+		defer it.Close()
+
+		for it.First(); it.Valid(); it.Next() {
+			// the key is only valid until the iterator is moved, and the batch keeps a reference to it
+			key := append([]byte(nil), it.Key()...)
+			err := w.Delete(key)
+			if err != nil {
+				return irrecoverable.NewExceptionf("could not delete item with prefix: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// retrieve will retrieve the binary data under the given key from the Pebble DB
+// and decode it into the given entity. The provided entity needs to be a
+// pointer to an initialized entity of the correct type.
+// Error returns:
+//   - storage.ErrNotFound if the key does not exist in the database
+//   - generic error in case of unexpected failure from the database layer, or failure
+//     to decode an existing database value
+func retrieve(key []byte, entity interface{}) func(pebble.Reader) error {
+	return func(r pebble.Reader) error {
+		val, closer, err := r.Get(key)
+		if errors.Is(err, pebble.ErrNotFound) {
+			return storage.ErrNotFound
+		}
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not load data: %w", err)
+		}
+		defer closer.Close()
+
+		err = msgpack.Unmarshal(val, entity)
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not decode entity: %w", err)
+		}
+		return nil
+	}
+}
+
+// exists returns true if a key exists in the database.
+// No errors are expected during normal operation.
+func exists(key []byte, keyExists *bool) func(pebble.Reader) error {
+	return func(r pebble.Reader) error {
+		_, closer, err := r.Get(key)
+		if err != nil {
+			// the key does not exist in the database
+			if errors.Is(err, pebble.ErrNotFound) {
+				*keyExists = false
+				return nil
+			}
+			// exception while checking for the key
+			return irrecoverable.NewExceptionf("could not load data: %w", err)
+		}
+		defer closer.Close()
+
+		// the key does exist in the database
+		*keyExists = true
+		return nil
+	}
+}
+
+// checkFunc is called during key iteration through the Pebble DB in order to
+// check whether we should process the given key-value pair.
+type checkFunc func(key []byte) bool
+
+// createFunc returns a pointer to an initialized entity that we can potentially
+// decode the next value into during a Pebble DB iteration.
+type createFunc func() interface{}
+
+// handleFunc is a function that starts the processing of the current key-value
+// pair during a Pebble DB iteration. It should be called after the key was checked
+// and the entity was decoded.
+// No errors are expected during normal operation. Any errors will halt the iteration.
+type handleFunc func() error
+
+// iterationFunc is a function provided to our low-level iteration function, which
+// returns the functions to check the key, to create the decode target and to process
+// the current key-value pair for each iteration step.
+type iterationFunc func() (checkFunc, createFunc, handleFunc)
+
+// traverse iterates over all keys with the given prefix, in increasing order.
+//
+// On each iteration, it will call the iteration function to initialize
+// functions specific to processing the given key-value pair.
+// No errors are expected during normal operation. Any errors returned by the
+// provided handleFunc will be propagated back to the caller of traverse.
+func traverse(prefix []byte, iteration iterationFunc) func(pebble.Reader) error {
+	return func(r pebble.Reader) error {
+		if len(prefix) == 0 {
+			return fmt.Errorf("prefix must not be empty")
+		}
+
+		it, err := r.NewIter(prefixIterOptions(prefix))
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not create iterator: %w", err)
+		}
+		defer it.Close()
+
+		for it.First(); it.Valid(); it.Next() {
+
+			// initialize processing functions for iteration
+			check, create, handle := iteration()
+
+			// check if we should process the item at all
+			ok := check(it.Key())
+			if !ok {
+				continue
+			}
+
+			// decode into the entity
+			entity := create()
+			err = msgpack.Unmarshal(it.Value(), entity)
+			if err != nil {
+				return irrecoverable.NewExceptionf("could not decode entity: %w", err)
+			}
+
+			// process the entity
+			err = handle()
+			if err != nil {
+				return fmt.Errorf("could not handle entity: %w", err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// prefixIterOptions returns the iterator options to iterate over all keys with the given prefix.
+// Unlike badger, where the iteration upper bound is padded with 0xff bytes up to the maximum key
+// length, Pebble iterators are bounded by the smallest key greater than all keys with the prefix.
+func prefixIterOptions(prefix []byte) *pebble.IterOptions {
+	return &pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: PrefixUpperBound(prefix),
+	}
+}
+
+// PrefixUpperBound returns the smallest key which is greater than all keys with the given prefix,
+// or nil if there is no such key, that is if the prefix only consists of 0xff bytes.
+func PrefixUpperBound(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i] = end[i] + 1
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
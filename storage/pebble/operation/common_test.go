@@ -0,0 +1,102 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type writer struct {
+	batch *pebble.Batch
+}
+
+func (w *writer) Set(key, val []byte) error {
+	return w.batch.Set(key, val, nil)
+}
+
+func (w *writer) Delete(key []byte) error {
+	return w.batch.Delete(key, nil)
+}
+
+// write applies the given write operation in a batch and commits it.
+func write(t *testing.T, db *pebble.DB, op func(storage.BatchWriter) error) {
+	batch := db.NewBatch()
+	require.NoError(t, op(&writer{batch: batch}))
+	require.NoError(t, batch.Commit(pebble.Sync))
+}
+
+func TestPrefixUpperBound(t *testing.T) {
+	assert.Equal(t, []byte{0x01, 0x03}, PrefixUpperBound([]byte{0x01, 0x02}))
+	assert.Equal(t, []byte{0x02}, PrefixUpperBound([]byte{0x01, 0xff}))
+	assert.Equal(t, []byte{0x01, 0x01}, PrefixUpperBound([]byte{0x01, 0x00, 0xff, 0xff}))
+	assert.Nil(t, PrefixUpperBound([]byte{0xff, 0xff}))
+}
+
+func TestInsertRetrieve(t *testing.T) {
+	unittest.RunWithPebbleDB(t, func(db *pebble.DB) {
+		key := []byte{0x01, 0x02}
+		expected := unittest.IdentifierFixture()
+
+		var actual flow.Identifier
+		err := retrieve(key, &actual)(db)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		write(t, db, insert(key, expected))
+
+		err = retrieve(key, &actual)(db)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		var keyExists bool
+		require.NoError(t, exists(key, &keyExists)(db))
+		assert.True(t, keyExists)
+
+		write(t, db, remove(key))
+
+		require.NoError(t, exists(key, &keyExists)(db))
+		assert.False(t, keyExists)
+	})
+}
+
+// TestTraverseRemoveByPrefix checks that traversing and removing by prefix only touch the keys with
+// the prefix, including the keys at the boundaries of the prefix range.
+func TestTraverseRemoveByPrefix(t *testing.T) {
+	unittest.RunWithPebbleDB(t, func(db *pebble.DB) {
+		prefix := []byte{0x01, 0xff}
+		inside := [][]byte{{0x01, 0xff}, {0x01, 0xff, 0x00}, {0x01, 0xff, 0xff, 0xff}}
+		outside := [][]byte{{0x01, 0xfe, 0xff}, {0x02}, {0x02, 0x00}}
+
+		ids := make(map[flow.Identifier]struct{})
+		for _, key := range inside {
+			id := unittest.IdentifierFixture()
+			ids[id] = struct{}{}
+			write(t, db, insert(key, id))
+		}
+		for _, key := range outside {
+			write(t, db, insert(key, unittest.IdentifierFixture()))
+		}
+
+		var found []flow.Identifier
+		require.NoError(t, traverse(prefix, lookup(&found))(db))
+		require.Len(t, found, len(inside))
+		for _, id := range found {
+			assert.Contains(t, ids, id)
+		}
+
+		write(t, db, removeByPrefix(db, prefix))
+
+		require.NoError(t, traverse(prefix, lookup(&found))(db))
+		assert.Empty(t, found)
+		for _, key := range outside {
+			var keyExists bool
+			require.NoError(t, exists(key, &keyExists)(db))
+			assert.True(t, keyExists)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+func eventPrefix(prefix byte, blockID flow.Identifier, event flow.Event) []byte {
+	return makePrefix(prefix, blockID, event.TransactionID, event.TransactionIndex, event.EventIndex)
+}
+
+func InsertEvent(blockID flow.Identifier, event flow.Event) func(storage.BatchWriter) error {
+	return insert(eventPrefix(codeEvent, blockID, event), event)
+}
+
+func InsertServiceEvent(blockID flow.Identifier, event flow.Event) func(storage.BatchWriter) error {
+	return insert(eventPrefix(codeServiceEvent, blockID, event), event)
+}
+
+func LookupEventsByBlockID(blockID flow.Identifier, events *[]flow.Event) func(pebble.Reader) error {
+	return traverse(makePrefix(codeEvent, blockID), eventIterationFunc(events))
+}
+
+func LookupServiceEventsByBlockID(blockID flow.Identifier, events *[]flow.Event) func(pebble.Reader) error {
+	return traverse(makePrefix(codeServiceEvent, blockID), eventIterationFunc(events))
+}
+
+// RemoveEventsByBlockID removes all events of the given block in the given batch, the keys are read from the given reader.
+func RemoveEventsByBlockID(r pebble.Reader, blockID flow.Identifier) func(storage.BatchWriter) error {
+	return removeByPrefix(r, makePrefix(codeEvent, blockID))
+}
+
+// RemoveServiceEventsByBlockID removes all service events of the given block in the given batch, the keys are read
+// from the given reader.
+func RemoveServiceEventsByBlockID(r pebble.Reader, blockID flow.Identifier) func(storage.BatchWriter) error {
+	return removeByPrefix(r, makePrefix(codeServiceEvent, blockID))
+}
+
+func eventIterationFunc(events *[]flow.Event) func() (checkFunc, createFunc, handleFunc) {
+	return func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var val flow.Event
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*events = append(*events, val)
+			return nil
+		}
+		return check, create, handle
+	}
+}
@@ -0,0 +1,53 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+func InsertHeader(headerID flow.Identifier, header *flow.Header) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeHeader, headerID), header)
+}
+
+func RetrieveHeader(blockID flow.Identifier, header *flow.Header) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeHeader, blockID), header)
+}
+
+// IndexBlockHeight indexes the height of a block. It should only be called on
+// finalized blocks.
+func IndexBlockHeight(height uint64, blockID flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeHeightToBlock, height), blockID)
+}
+
+// LookupBlockHeight retrieves finalized blocks by height.
+func LookupBlockHeight(height uint64, blockID *flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeHeightToBlock, height), blockID)
+}
+
+// BlockExists checks whether the block exists in the database.
+// No errors are expected during normal operation.
+func BlockExists(blockID flow.Identifier, blockExists *bool) func(pebble.Reader) error {
+	return exists(makePrefix(codeHeader, blockID), blockExists)
+}
+
+// IndexCollectionBlock indexes a block by a collection within that block.
+func IndexCollectionBlock(collID flow.Identifier, blockID flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeCollectionBlock, collID), blockID)
+}
+
+// LookupCollectionBlock looks up a block by a collection within that block.
+func LookupCollectionBlock(collID flow.Identifier, blockID *flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeCollectionBlock, collID), blockID)
+}
+
+// InsertBlockChildren insert an index to lookup the direct child of a block by its ID
+func InsertBlockChildren(blockID flow.Identifier, childrenIDs flow.IdentifierList) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeBlockChildren, blockID), childrenIDs)
+}
+
+// RetrieveBlockChildren the child block ID by parent block ID
+func RetrieveBlockChildren(blockID flow.Identifier, childrenIDs *flow.IdentifierList) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeBlockChildren, blockID), childrenIDs)
+}
@@ -0,0 +1,15 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+func InsertLastCompleteBlockHeight(height uint64) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeLastCompleteBlockHeight), height)
+}
+
+func RetrieveLastCompleteBlockHeight(height *uint64) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeLastCompleteBlockHeight), height)
+}
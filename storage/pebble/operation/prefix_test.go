@@ -0,0 +1,51 @@
+package operation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrefixCodes_MatchBadger verifies that the key codes are identical to the ones of the badger protocol database,
+// which is required to migrate a badger database to Pebble by copying its key-value pairs verbatim.
+func TestPrefixCodes_MatchBadger(t *testing.T) {
+	pebbleCodes := parsePrefixCodes(t, "prefix.go")
+	badgerCodes := parsePrefixCodes(t, filepath.Join("..", "..", "badger", "operation", "prefix.go"))
+
+	require.NotEmpty(t, badgerCodes)
+	require.Equal(t, badgerCodes, pebbleCodes)
+}
+
+// parsePrefixCodes returns the values of the key code constants declared in the given source file, by name.
+func parsePrefixCodes(t *testing.T, path string) map[string]uint64 {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	require.NoError(t, err)
+
+	codes := make(map[string]uint64)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				if !strings.HasPrefix(name.Name, "code") {
+					continue
+				}
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				require.Truef(t, ok && lit.Kind == token.INT, "key code %s in %s must be an integer literal", name.Name, path)
+				code, err := strconv.ParseUint(lit.Value, 0, 8)
+				require.NoError(t, err)
+				codes[name.Name] = code
+			}
+		}
+	}
+	return codes
+}
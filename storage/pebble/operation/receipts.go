@@ -0,0 +1,64 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// InsertExecutionReceiptMeta inserts an execution receipt meta by ID.
+func InsertExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeExecutionReceiptMeta, receiptID), meta)
+}
+
+// RetrieveExecutionReceiptMeta retrieves a execution receipt meta by ID.
+func RetrieveExecutionReceiptMeta(receiptID flow.Identifier, meta *flow.ExecutionReceiptMeta) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeExecutionReceiptMeta, receiptID), meta)
+}
+
+// IndexOwnExecutionReceipt inserts an execution receipt ID keyed by block ID
+func IndexOwnExecutionReceipt(blockID flow.Identifier, receiptID flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeOwnBlockReceipt, blockID), receiptID)
+}
+
+// LookupOwnExecutionReceipt finds execution receipt ID by block
+func LookupOwnExecutionReceipt(blockID flow.Identifier, receiptID *flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeOwnBlockReceipt, blockID), receiptID)
+}
+
+// RemoveOwnExecutionReceipt removes own execution receipt index by blockID
+func RemoveOwnExecutionReceipt(blockID flow.Identifier) func(storage.BatchWriter) error {
+	return remove(makePrefix(codeOwnBlockReceipt, blockID))
+}
+
+// IndexExecutionReceipts inserts an execution receipt ID keyed by block ID and receipt ID.
+// one block could have multiple receipts, even if they are from the same executor
+func IndexExecutionReceipts(blockID, receiptID flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeAllBlockReceipts, blockID, receiptID), receiptID)
+}
+
+// LookupExecutionReceipts finds all execution receipts by block ID
+func LookupExecutionReceipts(blockID flow.Identifier, receiptIDs *[]flow.Identifier) func(pebble.Reader) error {
+	return traverse(makePrefix(codeAllBlockReceipts, blockID), lookup(receiptIDs))
+}
+
+// lookup is the default iteration function allowing us to collect a list of
+// entity IDs from an index.
+func lookup(entityIDs *[]flow.Identifier) func() (checkFunc, createFunc, handleFunc) {
+	*entityIDs = make([]flow.Identifier, 0, len(*entityIDs))
+	return func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var entityID flow.Identifier
+		create := func() interface{} {
+			return &entityID
+		}
+		handle := func() error {
+			*entityIDs = append(*entityIDs, entityID)
+			return nil
+		}
+		return check, create, handle
+	}
+}
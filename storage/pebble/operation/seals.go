@@ -0,0 +1,60 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+func InsertSeal(sealID flow.Identifier, seal *flow.Seal) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeSeal, sealID), seal)
+}
+
+func RetrieveSeal(sealID flow.Identifier, seal *flow.Seal) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeSeal, sealID), seal)
+}
+
+func IndexPayloadSeals(blockID flow.Identifier, sealIDs []flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codePayloadSeals, blockID), sealIDs)
+}
+
+func LookupPayloadSeals(blockID flow.Identifier, sealIDs *[]flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codePayloadSeals, blockID), sealIDs)
+}
+
+func IndexPayloadReceipts(blockID flow.Identifier, receiptIDs []flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codePayloadReceipts, blockID), receiptIDs)
+}
+
+func IndexPayloadResults(blockID flow.Identifier, resultIDs []flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codePayloadResults, blockID), resultIDs)
+}
+
+func LookupPayloadReceipts(blockID flow.Identifier, receiptIDs *[]flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codePayloadReceipts, blockID), receiptIDs)
+}
+
+func LookupPayloadResults(blockID flow.Identifier, resultIDs *[]flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codePayloadResults, blockID), resultIDs)
+}
+
+// IndexLatestSealAtBlock persists the highest seal that was included in the fork up to (and including) blockID.
+func IndexLatestSealAtBlock(blockID flow.Identifier, sealID flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeBlockIDToLatestSealID, blockID), sealID)
+}
+
+// LookupLatestSealAtBlock finds the highest seal that was included in the fork up to (and including) blockID.
+func LookupLatestSealAtBlock(blockID flow.Identifier, sealID *flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeBlockIDToLatestSealID, blockID), sealID)
+}
+
+// IndexFinalizedSealByBlockID indexes the _finalized_ seal by the sealed block ID.
+func IndexFinalizedSealByBlockID(sealedBlockID flow.Identifier, sealID flow.Identifier) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeBlockIDToFinalizedSeal, sealedBlockID), sealID)
+}
+
+// LookupBySealedBlockID finds the seal for the given sealed block ID.
+func LookupBySealedBlockID(sealedBlockID flow.Identifier, sealID *flow.Identifier) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeBlockIDToFinalizedSeal, sealedBlockID), sealID)
+}
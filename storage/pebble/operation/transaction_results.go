@@ -0,0 +1,57 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+func InsertTransactionResult(blockID flow.Identifier, transactionResult *flow.TransactionResult) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeTransactionResult, blockID, transactionResult.TransactionID), transactionResult)
+}
+
+func IndexTransactionResult(blockID flow.Identifier, txIndex uint32, transactionResult *flow.TransactionResult) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeTransactionResultIndex, blockID, txIndex), transactionResult)
+}
+
+func RetrieveTransactionResult(blockID flow.Identifier, transactionID flow.Identifier, transactionResult *flow.TransactionResult) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeTransactionResult, blockID, transactionID), transactionResult)
+}
+
+func RetrieveTransactionResultByIndex(blockID flow.Identifier, txIndex uint32, transactionResult *flow.TransactionResult) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeTransactionResultIndex, blockID, txIndex), transactionResult)
+}
+
+// LookupTransactionResultsByBlockIDUsingIndex retrieves all tx results for a block, by using
+// tx_index index. This correctly handles cases of duplicate transactions within block.
+func LookupTransactionResultsByBlockIDUsingIndex(blockID flow.Identifier, txResults *[]flow.TransactionResult) func(pebble.Reader) error {
+	txErrIterFunc := func() (checkFunc, createFunc, handleFunc) {
+		check := func(_ []byte) bool {
+			return true
+		}
+		var val flow.TransactionResult
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*txResults = append(*txResults, val)
+			return nil
+		}
+		return check, create, handle
+	}
+
+	return traverse(makePrefix(codeTransactionResultIndex, blockID), txErrIterFunc)
+}
+
+// RemoveTransactionResultsByBlockID removes the transaction results and their index of the given block in the
+// given batch, the keys are read from the given reader.
+func RemoveTransactionResultsByBlockID(r pebble.Reader, blockID flow.Identifier) func(storage.BatchWriter) error {
+	return func(w storage.BatchWriter) error {
+		err := removeByPrefix(r, makePrefix(codeTransactionResult, blockID))(w)
+		if err != nil {
+			return err
+		}
+		return removeByPrefix(r, makePrefix(codeTransactionResultIndex, blockID))(w)
+	}
+}
@@ -0,0 +1,18 @@
+package operation
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// InsertTransaction inserts a transaction keyed by transaction fingerprint.
+func InsertTransaction(txID flow.Identifier, tx *flow.TransactionBody) func(storage.BatchWriter) error {
+	return insert(makePrefix(codeTransaction, txID), tx)
+}
+
+// RetrieveTransaction retrieves a transaction by fingerprint.
+func RetrieveTransaction(txID flow.Identifier, tx *flow.TransactionBody) func(pebble.Reader) error {
+	return retrieve(makePrefix(codeTransaction, txID), tx)
+}
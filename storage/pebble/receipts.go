@@ -0,0 +1,152 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// ExecutionReceipts implements storage for execution receipts.
+type ExecutionReceipts struct {
+	db      *pebble.DB
+	results *ExecutionResults
+	cache   *Cache[flow.Identifier, *flow.ExecutionReceipt]
+}
+
+var _ storage.ExecutionReceipts = (*ExecutionReceipts)(nil)
+
+// NewExecutionReceipts Creates ExecutionReceipts instance which is a database of receipts which
+// supports storing and indexing receipts by receipt ID and block ID.
+func NewExecutionReceipts(collector module.CacheMetrics, db *pebble.DB, results *ExecutionResults, cacheSize uint) *ExecutionReceipts {
+	store := func(receiptID flow.Identifier, receipt *flow.ExecutionReceipt) func(storage.BatchWriter) error {
+		return func(w storage.BatchWriter) error {
+			err := operation.InsertExecutionResult(&receipt.ExecutionResult)(w)
+			if err != nil {
+				return fmt.Errorf("could not store result: %w", err)
+			}
+			err = operation.InsertExecutionReceiptMeta(receiptID, receipt.Meta())(w)
+			if err != nil {
+				return fmt.Errorf("could not store receipt metadata: %w", err)
+			}
+			err = operation.IndexExecutionReceipts(receipt.ExecutionResult.BlockID, receiptID)(w)
+			if err != nil {
+				return fmt.Errorf("could not index receipt by the block it computes: %w", err)
+			}
+			return nil
+		}
+	}
+
+	retrieve := func(receiptID flow.Identifier) func(pebble.Reader) (*flow.ExecutionReceipt, error) {
+		return func(r pebble.Reader) (*flow.ExecutionReceipt, error) {
+			var meta flow.ExecutionReceiptMeta
+			err := operation.RetrieveExecutionReceiptMeta(receiptID, &meta)(r)
+			if err != nil {
+				return nil, fmt.Errorf("could not retrieve receipt meta: %w", err)
+			}
+			result, err := results.byID(meta.ResultID)(r)
+			if err != nil {
+				return nil, fmt.Errorf("could not retrieve result: %w", err)
+			}
+			return flow.ExecutionReceiptFromMeta(meta, *result), nil
+		}
+	}
+
+	return &ExecutionReceipts{
+		db:      db,
+		results: results,
+		cache: newCache[flow.Identifier, *flow.ExecutionReceipt](collector, metrics.ResourceReceipt,
+			withLimit[flow.Identifier, *flow.ExecutionReceipt](cacheSize),
+			withStore(store),
+			withRetrieve(retrieve)),
+	}
+}
+
+// storeBatch assembles the operations to store an arbitrary receipt.
+func (r *ExecutionReceipts) storeBatch(receipt *flow.ExecutionReceipt) func(storage.BatchStorage) error {
+	return func(batch storage.BatchStorage) error {
+		err := r.cache.PutBatch(receipt.ID(), receipt)(batch)
+		if err != nil {
+			return err
+		}
+		// the result is stored along with the receipt, cache it as well
+		batch.OnSucceed(func() {
+			r.results.cache.Insert(receipt.ExecutionResult.ID(), &receipt.ExecutionResult)
+		})
+		return nil
+	}
+}
+
+func (r *ExecutionReceipts) byID(receiptID flow.Identifier) func(pebble.Reader) (*flow.ExecutionReceipt, error) {
+	retrievalOps := r.cache.Get(receiptID) // assemble DB operations to retrieve receipt (no execution)
+	return func(reader pebble.Reader) (*flow.ExecutionReceipt, error) {
+		val, err := retrievalOps(reader) // execute operations to retrieve receipt
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+}
+
+func (r *ExecutionReceipts) byBlockID(blockID flow.Identifier) func(pebble.Reader) ([]*flow.ExecutionReceipt, error) {
+	return func(reader pebble.Reader) ([]*flow.ExecutionReceipt, error) {
+		var receiptIDs []flow.Identifier
+		err := operation.LookupExecutionReceipts(blockID, &receiptIDs)(reader)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("could not find receipt index for block: %w", err)
+		}
+
+		var receipts []*flow.ExecutionReceipt
+		for _, id := range receiptIDs {
+			receipt, err := r.byID(id)(reader)
+			if err != nil {
+				return nil, fmt.Errorf("could not find receipt with id %v: %w", id, err)
+			}
+			receipts = append(receipts, receipt)
+		}
+		return receipts, nil
+	}
+}
+
+func (r *ExecutionReceipts) Store(receipt *flow.ExecutionReceipt) error {
+	return withBatch(r.db, r.storeBatch(receipt))
+}
+
+func (r *ExecutionReceipts) BatchStore(receipt *flow.ExecutionReceipt, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+
+	err := r.results.BatchStore(&receipt.ExecutionResult, batch)
+	if err != nil {
+		return fmt.Errorf("cannot batch store execution result inside execution receipt batch store: %w", err)
+	}
+
+	err = operation.InsertExecutionReceiptMeta(receipt.ID(), receipt.Meta())(writeBatch)
+	if err != nil {
+		return fmt.Errorf("cannot batch store execution meta inside execution receipt batch store: %w", err)
+	}
+
+	err = operation.IndexExecutionReceipts(receipt.ExecutionResult.BlockID, receipt.ID())(writeBatch)
+	if err != nil {
+		return fmt.Errorf("cannot batch index execution receipt inside execution receipt batch store: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExecutionReceipts) ByID(receiptID flow.Identifier) (*flow.ExecutionReceipt, error) {
+	snap := r.db.NewSnapshot()
+	defer snap.Close()
+	return r.byID(receiptID)(snap)
+}
+
+func (r *ExecutionReceipts) ByBlockID(blockID flow.Identifier) (flow.ExecutionReceiptList, error) {
+	snap := r.db.NewSnapshot()
+	defer snap.Close()
+	return r.byBlockID(blockID)(snap)
+}
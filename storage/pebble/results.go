@@ -0,0 +1,161 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/transaction"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// ExecutionResults implements persistent storage for execution results.
+type ExecutionResults struct {
+	db    *pebble.DB
+	cache *Cache[flow.Identifier, *flow.ExecutionResult]
+	// indexLock serializes the indexing of results by block, as Pebble has no transactions
+	// to atomically check for an already indexed result and index the new one.
+	indexLock sync.Mutex
+}
+
+var _ storage.ExecutionResults = (*ExecutionResults)(nil)
+
+func NewExecutionResults(collector module.CacheMetrics, db *pebble.DB) *ExecutionResults {
+
+	store := func(_ flow.Identifier, result *flow.ExecutionResult) func(storage.BatchWriter) error {
+		return operation.InsertExecutionResult(result)
+	}
+
+	retrieve := func(resultID flow.Identifier) func(pebble.Reader) (*flow.ExecutionResult, error) {
+		return func(r pebble.Reader) (*flow.ExecutionResult, error) {
+			var result flow.ExecutionResult
+			err := operation.RetrieveExecutionResult(resultID, &result)(r)
+			return &result, err
+		}
+	}
+
+	res := &ExecutionResults{
+		db: db,
+		cache: newCache[flow.Identifier, *flow.ExecutionResult](collector, metrics.ResourceResult,
+			withLimit[flow.Identifier, *flow.ExecutionResult](flow.DefaultTransactionExpiry+100),
+			withStore(store),
+			withRetrieve(retrieve)),
+	}
+
+	return res
+}
+
+func (r *ExecutionResults) store(result *flow.ExecutionResult) func(storage.BatchStorage) error {
+	return r.cache.PutBatch(result.ID(), result)
+}
+
+func (r *ExecutionResults) byID(resultID flow.Identifier) func(pebble.Reader) (*flow.ExecutionResult, error) {
+	return func(reader pebble.Reader) (*flow.ExecutionResult, error) {
+		val, err := r.cache.Get(resultID)(reader)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+}
+
+func (r *ExecutionResults) byBlockID(blockID flow.Identifier) func(pebble.Reader) (*flow.ExecutionResult, error) {
+	return func(reader pebble.Reader) (*flow.ExecutionResult, error) {
+		var resultID flow.Identifier
+		err := operation.LookupExecutionResult(blockID, &resultID)(reader)
+		if err != nil {
+			return nil, fmt.Errorf("could not lookup execution result ID: %w", err)
+		}
+		return r.byID(resultID)(reader)
+	}
+}
+
+func (r *ExecutionResults) index(blockID, resultID flow.Identifier, force bool) error {
+	r.indexLock.Lock()
+	defer r.indexLock.Unlock()
+
+	if !force {
+		// when trying to index a result for a block, and there is already a result indexed for this block,
+		// double check if the indexed result is the same
+		var storedResultID flow.Identifier
+		err := operation.LookupExecutionResult(blockID, &storedResultID)(r.db)
+		if err == nil {
+			if storedResultID != resultID {
+				return fmt.Errorf("storing result that is different from the already stored one for block: %v, storing result: %v, stored result: %v. %w",
+					blockID, resultID, storedResultID, storage.ErrDataMismatch)
+			}
+			return nil
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("could not check for an already indexed result: %w", err)
+		}
+	}
+
+	return withBatch(r.db, func(batch storage.BatchStorage) error {
+		return operation.IndexExecutionResult(blockID, resultID)(batch.GetWriter())
+	})
+}
+
+func (r *ExecutionResults) Store(result *flow.ExecutionResult) error {
+	return withBatch(r.db, r.store(result))
+}
+
+func (r *ExecutionResults) BatchStore(result *flow.ExecutionResult, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	return operation.InsertExecutionResult(result)(writeBatch)
+}
+
+func (r *ExecutionResults) BatchIndex(blockID flow.Identifier, resultID flow.Identifier, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	return operation.IndexExecutionResult(blockID, resultID)(writeBatch)
+}
+
+func (r *ExecutionResults) ByID(resultID flow.Identifier) (*flow.ExecutionResult, error) {
+	return r.byID(resultID)(r.db)
+}
+
+// ByIDTx is not supported by the Pebble storage, as Pebble has no transactions.
+func (r *ExecutionResults) ByIDTx(flow.Identifier) func(*transaction.Tx) (*flow.ExecutionResult, error) {
+	return func(*transaction.Tx) (*flow.ExecutionResult, error) {
+		return nil, errNotSupported
+	}
+}
+
+// Index indexes the execution result by the block it computes. Indexing a different result
+// for a block that already has a result indexed fails with storage.ErrDataMismatch.
+func (r *ExecutionResults) Index(blockID flow.Identifier, resultID flow.Identifier) error {
+	err := r.index(blockID, resultID, false)
+	if err != nil {
+		return fmt.Errorf("could not index execution result: %w", err)
+	}
+	return nil
+}
+
+// ForceIndex indexes the execution result by the block it computes, overriding the result
+// already indexed for the block, if any.
+func (r *ExecutionResults) ForceIndex(blockID flow.Identifier, resultID flow.Identifier) error {
+	err := r.index(blockID, resultID, true)
+	if err != nil {
+		return fmt.Errorf("could not index execution result: %w", err)
+	}
+	return nil
+}
+
+func (r *ExecutionResults) ByBlockID(blockID flow.Identifier) (*flow.ExecutionResult, error) {
+	snap := r.db.NewSnapshot()
+	defer snap.Close()
+	return r.byBlockID(blockID)(snap)
+}
+
+// BatchRemoveIndexByBlockID removes blockID-to-executionResultID index entries keyed by blockID in a provided batch.
+// No errors are expected during normal operation, even if no entries are matched.
+func (r *ExecutionResults) BatchRemoveIndexByBlockID(blockID flow.Identifier, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	return operation.RemoveExecutionResultIndex(blockID)(writeBatch)
+}
@@ -0,0 +1,68 @@
+package pebble_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	pebblestorage "github.com/onflow/flow-go/storage/pebble"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+	"github.com/onflow/flow-go/storage/testutils"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestStorageSuite runs the storage tests shared by all database backends against pebble.
+func TestStorageSuite(t *testing.T) {
+	testutils.RunStorageSuite(t, func(t *testing.T, f func(backend testutils.Backend)) {
+		unittest.RunWithPebbleDB(t, func(db *pebble.DB) {
+			f(&backend{db: db, metrics: metrics.NewNoopCollector()})
+		})
+	})
+}
+
+// backend implements testutils.Backend for a pebble database.
+type backend struct {
+	db      *pebble.DB
+	metrics *metrics.NoopCollector
+}
+
+var _ testutils.Backend = (*backend)(nil)
+
+func (b *backend) All() *storage.All {
+	return pebblestorage.InitAll(b.metrics, b.db)
+}
+
+func (b *backend) NewBatch() storage.BatchStorage {
+	return pebblestorage.NewBatch(b.db)
+}
+
+func (b *backend) NewMyExecutionReceipts() storage.MyExecutionReceipts {
+	results := pebblestorage.NewExecutionResults(b.metrics, b.db)
+	receipts := pebblestorage.NewExecutionReceipts(b.metrics, b.db, results, pebblestorage.DefaultCacheSize)
+	return pebblestorage.NewMyExecutionReceipts(b.metrics, b.db, receipts)
+}
+
+func (b *backend) IndexBlockHeight(height uint64, blockID flow.Identifier) error {
+	return pebblestorage.NewHeaders(b.metrics, b.db).IndexByHeight(height, blockID)
+}
+
+func (b *backend) IndexLatestSealAtBlock(blockID flow.Identifier, sealID flow.Identifier) error {
+	return b.withBatch(operation.IndexLatestSealAtBlock(blockID, sealID))
+}
+
+func (b *backend) IndexFinalizedSealByBlockID(blockID flow.Identifier, sealID flow.Identifier) error {
+	return b.withBatch(operation.IndexFinalizedSealByBlockID(blockID, sealID))
+}
+
+// withBatch applies the given operation to a new batch and flushes it.
+func (b *backend) withBatch(op func(storage.BatchWriter) error) error {
+	batch := pebblestorage.NewBatch(b.db)
+	err := op(batch.GetWriter())
+	if err != nil {
+		return err
+	}
+	return batch.Flush()
+}
@@ -0,0 +1,156 @@
+package pebble
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+type TransactionResults struct {
+	db         *pebble.DB
+	cache      *Cache[transactionKey, flow.TransactionResult]
+	indexCache *Cache[transactionIndexKey, flow.TransactionResult]
+	blockCache *Cache[flow.Identifier, []flow.TransactionResult]
+}
+
+var _ storage.TransactionResults = (*TransactionResults)(nil)
+
+// transactionKey is the cache key of a transaction result by block ID and transaction ID.
+type transactionKey struct {
+	blockID flow.Identifier
+	txID    flow.Identifier
+}
+
+// transactionIndexKey is the cache key of a transaction result by block ID and transaction index.
+type transactionIndexKey struct {
+	blockID flow.Identifier
+	txIndex uint32
+}
+
+func NewTransactionResults(collector module.CacheMetrics, db *pebble.DB, transactionResultsCacheSize uint) *TransactionResults {
+	retrieve := func(key transactionKey) func(pebble.Reader) (flow.TransactionResult, error) {
+		return func(r pebble.Reader) (flow.TransactionResult, error) {
+			var txResult flow.TransactionResult
+			err := operation.RetrieveTransactionResult(key.blockID, key.txID, &txResult)(r)
+			if err != nil {
+				return flow.TransactionResult{}, err
+			}
+			return txResult, nil
+		}
+	}
+	retrieveIndex := func(key transactionIndexKey) func(pebble.Reader) (flow.TransactionResult, error) {
+		return func(r pebble.Reader) (flow.TransactionResult, error) {
+			var txResult flow.TransactionResult
+			err := operation.RetrieveTransactionResultByIndex(key.blockID, key.txIndex, &txResult)(r)
+			if err != nil {
+				return flow.TransactionResult{}, err
+			}
+			return txResult, nil
+		}
+	}
+	retrieveForBlock := func(blockID flow.Identifier) func(pebble.Reader) ([]flow.TransactionResult, error) {
+		return func(r pebble.Reader) ([]flow.TransactionResult, error) {
+			var txResults []flow.TransactionResult
+			err := operation.LookupTransactionResultsByBlockIDUsingIndex(blockID, &txResults)(r)
+			if err != nil {
+				return nil, err
+			}
+			return txResults, nil
+		}
+	}
+	return &TransactionResults{
+		db: db,
+		cache: newCache[transactionKey, flow.TransactionResult](collector, metrics.ResourceTransactionResults,
+			withLimit[transactionKey, flow.TransactionResult](transactionResultsCacheSize),
+			withRetrieve(retrieve),
+		),
+		indexCache: newCache[transactionIndexKey, flow.TransactionResult](collector, metrics.ResourceTransactionResultIndices,
+			withLimit[transactionIndexKey, flow.TransactionResult](transactionResultsCacheSize),
+			withRetrieve(retrieveIndex),
+		),
+		blockCache: newCache[flow.Identifier, []flow.TransactionResult](collector, metrics.ResourceTransactionResultIndices,
+			withLimit[flow.Identifier, []flow.TransactionResult](transactionResultsCacheSize),
+			withRetrieve(retrieveForBlock),
+		),
+	}
+}
+
+// BatchStore will store the transaction results for the given block ID in a batch
+func (tr *TransactionResults) BatchStore(blockID flow.Identifier, transactionResults []flow.TransactionResult, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+
+	for i, result := range transactionResults {
+		err := operation.InsertTransactionResult(blockID, &result)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("cannot batch insert tx result: %w", err)
+		}
+
+		err = operation.IndexTransactionResult(blockID, uint32(i), &result)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("cannot batch index tx result: %w", err)
+		}
+	}
+
+	batch.OnSucceed(func() {
+		for i, result := range transactionResults {
+			tr.cache.Insert(transactionKey{blockID: blockID, txID: result.TransactionID}, result)
+			tr.indexCache.Insert(transactionIndexKey{blockID: blockID, txIndex: uint32(i)}, result)
+		}
+		tr.blockCache.Insert(blockID, transactionResults)
+	})
+	return nil
+}
+
+// ByBlockIDTransactionID returns the runtime transaction result for the given block ID and transaction ID
+func (tr *TransactionResults) ByBlockIDTransactionID(blockID flow.Identifier, txID flow.Identifier) (*flow.TransactionResult, error) {
+	transactionResult, err := tr.cache.Get(transactionKey{blockID: blockID, txID: txID})(tr.db)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionResult, nil
+}
+
+// ByBlockIDTransactionIndex returns the runtime transaction result for the given block ID and transaction index
+func (tr *TransactionResults) ByBlockIDTransactionIndex(blockID flow.Identifier, txIndex uint32) (*flow.TransactionResult, error) {
+	transactionResult, err := tr.indexCache.Get(transactionIndexKey{blockID: blockID, txIndex: txIndex})(tr.db)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionResult, nil
+}
+
+// ByBlockID gets all transaction results for a block, ordered by transaction index
+func (tr *TransactionResults) ByBlockID(blockID flow.Identifier) ([]flow.TransactionResult, error) {
+	transactionResults, err := tr.blockCache.Get(blockID)(tr.db)
+	if err != nil {
+		return nil, err
+	}
+	return transactionResults, nil
+}
+
+// BatchRemoveByBlockID batch removes transaction results by block ID
+func (tr *TransactionResults) BatchRemoveByBlockID(blockID flow.Identifier, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+
+	// look up the results to remove, so that they can be evicted from the caches
+	var txResults []flow.TransactionResult
+	err := operation.LookupTransactionResultsByBlockIDUsingIndex(blockID, &txResults)(tr.db)
+	if err != nil {
+		return fmt.Errorf("could not look up tx results to remove: %w", err)
+	}
+
+	batch.OnSucceed(func() {
+		for i, result := range txResults {
+			tr.cache.Remove(transactionKey{blockID: blockID, txID: result.TransactionID})
+			tr.indexCache.Remove(transactionIndexKey{blockID: blockID, txIndex: uint32(i)})
+		}
+		tr.blockCache.Remove(blockID)
+	})
+	return operation.RemoveTransactionResultsByBlockID(tr.db, blockID)(writeBatch)
+}
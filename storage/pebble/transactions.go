@@ -0,0 +1,68 @@
+package pebble
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/pebble/operation"
+)
+
+// Transactions ...
+type Transactions struct {
+	db    *pebble.DB
+	cache *Cache[flow.Identifier, *flow.TransactionBody]
+}
+
+var _ storage.Transactions = (*Transactions)(nil)
+
+// NewTransactions ...
+func NewTransactions(cacheMetrics module.CacheMetrics, db *pebble.DB) *Transactions {
+	store := func(txID flow.Identifier, flowTX *flow.TransactionBody) func(storage.BatchWriter) error {
+		return operation.InsertTransaction(txID, flowTX)
+	}
+
+	retrieve := func(txID flow.Identifier) func(pebble.Reader) (*flow.TransactionBody, error) {
+		return func(r pebble.Reader) (*flow.TransactionBody, error) {
+			var flowTx flow.TransactionBody
+			err := operation.RetrieveTransaction(txID, &flowTx)(r)
+			return &flowTx, err
+		}
+	}
+
+	t := &Transactions{
+		db: db,
+		cache: newCache[flow.Identifier, *flow.TransactionBody](cacheMetrics, metrics.ResourceTransaction,
+			withLimit[flow.Identifier, *flow.TransactionBody](flow.DefaultTransactionExpiry+100),
+			withStore(store),
+			withRetrieve(retrieve)),
+	}
+
+	return t
+}
+
+// Store ...
+func (t *Transactions) Store(flowTx *flow.TransactionBody) error {
+	return withBatch(t.db, t.storeBatch(flowTx))
+}
+
+// ByID ...
+func (t *Transactions) ByID(txID flow.Identifier) (*flow.TransactionBody, error) {
+	return t.retrieve(txID)(t.db)
+}
+
+func (t *Transactions) storeBatch(flowTx *flow.TransactionBody) func(storage.BatchStorage) error {
+	return t.cache.PutBatch(flowTx.ID(), flowTx)
+}
+
+func (t *Transactions) retrieve(txID flow.Identifier) func(pebble.Reader) (*flow.TransactionBody, error) {
+	return func(r pebble.Reader) (*flow.TransactionBody, error) {
+		val, err := t.cache.Get(txID)(r)
+		if err != nil {
+			return nil, err
+		}
+		return val, err
+	}
+}
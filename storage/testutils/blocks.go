@@ -1,22 +1,19 @@
-package badger_test
+package testutils
 
 import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
-	badgerstorage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
-func TestBlocks(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		store := badgerstorage.NewBlocks(db, nil, nil)
+func testBlocks(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Blocks
 
 		// check retrieval of non-existing key
 		_, err := store.GetLastFullBlockHeight()
@@ -45,11 +42,10 @@ func TestBlocks(t *testing.T) {
 	})
 }
 
-func TestBlockStoreAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		cacheMetrics := &metrics.NoopCollector{}
+func testBlockStoreAndRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
 		// verify after storing a block should be able to retrieve it back
-		blocks := badgerstorage.InitAll(cacheMetrics, db).Blocks
+		blocks := backend.All().Blocks
 		block := unittest.FullBlockFixture()
 		block.SetPayload(unittest.PayloadFixture(unittest.WithAllTheFixins))
 
@@ -63,7 +59,7 @@ func TestBlockStoreAndRetrieve(t *testing.T) {
 
 		// verify after a restart, the block stored in the database is the same
 		// as the original
-		blocksAfterRestart := badgerstorage.InitAll(cacheMetrics, db).Blocks
+		blocksAfterRestart := backend.All().Blocks
 		receivedAfterRestart, err := blocksAfterRestart.ByID(block.ID())
 		require.NoError(t, err)
 
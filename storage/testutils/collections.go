@@ -1,23 +1,17 @@
-package badger_test
+package testutils
 
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
-	bstorage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
-func TestCollections(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-
-		metrics := metrics.NewNoopCollector()
-		transactions := bstorage.NewTransactions(metrics, db)
-		collections := bstorage.NewCollections(db, transactions)
+func testCollections(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		collections := backend.All().Collections
 
 		// create a light collection with three transactions
 		expected := unittest.CollectionFixture(3).Light()
@@ -43,15 +37,12 @@ func TestCollections(t *testing.T) {
 			require.Nil(t, err)
 			assert.Equal(t, expectedID, actualID)
 		}
-
 	})
 }
 
-func TestCollections_IndexDuplicateTx(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		transactions := bstorage.NewTransactions(metrics, db)
-		collections := bstorage.NewCollections(db, transactions)
+func testCollectionsIndexDuplicateTx(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		collections := backend.All().Collections
 
 		// create two collections which share 1 transaction
 		col1 := unittest.CollectionFixture(2)
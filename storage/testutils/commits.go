@@ -1,25 +1,20 @@
-package badger_test
+package testutils
 
 import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
-
-	badgerstorage "github.com/onflow/flow-go/storage/badger"
 )
 
-// TestCommitsStoreAndRetrieve tests that a commit can be stored, retrieved and attempted to be stored again without an error
-func TestCommitsStoreAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewCommits(metrics, db)
+// testCommitsStoreAndRetrieve tests that a commit can be stored, retrieved and attempted to be stored again without an error
+func testCommitsStoreAndRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Commits
 
 		// attempt to get a invalid commit
 		_, err := store.ByBlockID(unittest.IdentifierFixture())
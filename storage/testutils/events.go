@@ -1,23 +1,19 @@
-package badger_test
+package testutils
 
 import (
 	"math/rand"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/fvm/systemcontracts"
 	"github.com/onflow/flow-go/model/flow"
-	"github.com/onflow/flow-go/module/metrics"
-	badgerstorage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
-func TestEventStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewEvents(metrics, db)
+func testEventStoreRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Events
 
 		blockID := unittest.IdentifierFixture()
 		tx1ID := unittest.IdentifierFixture()
@@ -32,7 +28,7 @@ func TestEventStoreRetrieve(t *testing.T) {
 			{evt2_1},
 		}
 
-		batch := badgerstorage.NewBatch(db)
+		batch := backend.NewBatch()
 		// store event
 		err := store.BatchStore(blockID, expected, batch)
 		require.NoError(t, err)
@@ -81,7 +77,7 @@ func TestEventStoreRetrieve(t *testing.T) {
 
 		// test loading from database
 
-		newStore := badgerstorage.NewEvents(metrics, db)
+		newStore := backend.All().Events
 		actual, err = newStore.ByBlockID(blockID)
 		require.NoError(t, err)
 		require.Len(t, actual, 3)
@@ -91,10 +87,9 @@ func TestEventStoreRetrieve(t *testing.T) {
 	})
 }
 
-func TestEventRetrieveWithoutStore(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewEvents(metrics, db)
+func testEventRetrieveWithoutStore(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Events
 
 		blockID := unittest.IdentifierFixture()
 		txID := unittest.IdentifierFixture()
@@ -119,6 +114,5 @@ func TestEventRetrieveWithoutStore(t *testing.T) {
 		events, err = store.ByBlockIDTransactionIndex(blockID, txIndex)
 		require.NoError(t, err)
 		require.True(t, len(events) == 0)
-
 	})
 }
@@ -1,25 +1,18 @@
-package badger_test
+package testutils
 
 import (
 	"errors"
 	"testing"
 
-	"github.com/onflow/flow-go/storage/badger/operation"
-
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
-
-	badgerstorage "github.com/onflow/flow-go/storage/badger"
 )
 
-func TestHeaderStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		headers := badgerstorage.NewHeaders(metrics, db)
+func testHeaderStoreRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		headers := backend.All().Headers
 
 		block := unittest.BlockFixture()
 
@@ -28,7 +21,7 @@ func TestHeaderStoreRetrieve(t *testing.T) {
 		require.NoError(t, err)
 
 		// index the header
-		err = operation.RetryOnConflict(db.Update, operation.IndexBlockHeight(block.Header.Height, block.ID()))
+		err = backend.IndexBlockHeight(block.Header.Height, block.ID())
 		require.NoError(t, err)
 
 		// retrieve header by height
@@ -38,10 +31,9 @@ func TestHeaderStoreRetrieve(t *testing.T) {
 	})
 }
 
-func TestHeaderRetrieveWithoutStore(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		headers := badgerstorage.NewHeaders(metrics, db)
+func testHeaderRetrieveWithoutStore(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		headers := backend.All().Headers
 
 		header := unittest.BlockHeaderFixture()
 
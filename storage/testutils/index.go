@@ -1,23 +1,18 @@
-package badger_test
+package testutils
 
 import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
-
-	badgerstorage "github.com/onflow/flow-go/storage/badger"
 )
 
-func TestIndexStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewIndex(metrics, db)
+func testIndexStoreRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Index
 
 		blockID := unittest.IdentifierFixture()
 		expected := unittest.IndexFixture()
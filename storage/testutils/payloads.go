@@ -0,0 +1,40 @@
+package testutils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func testPayloadStoreRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Payloads
+
+		blockID := unittest.IdentifierFixture()
+		expected := unittest.PayloadFixture(unittest.WithAllTheFixins)
+
+		// store payload
+		err := store.Store(blockID, &expected)
+		require.NoError(t, err)
+
+		// fetch payload
+		payload, err := store.ByBlockID(blockID)
+		require.NoError(t, err)
+		require.Equal(t, &expected, payload)
+	})
+}
+
+func testPayloadRetrieveWithoutStore(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Payloads
+
+		blockID := unittest.IdentifierFixture()
+
+		_, err := store.ByBlockID(blockID)
+		require.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
@@ -1,29 +1,24 @@
-package badger_test
+package testutils
 
 import (
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
-	"github.com/onflow/flow-go/module/metrics"
-	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
-func TestExecutionReceiptsStorage(t *testing.T) {
-	withStore := func(t *testing.T, f func(store *bstorage.ExecutionReceipts)) {
-		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-			metrics := metrics.NewNoopCollector()
-			results := bstorage.NewExecutionResults(metrics, db)
-			store := bstorage.NewExecutionReceipts(metrics, db, results, bstorage.DefaultCacheSize)
-			f(store)
+func testExecutionReceiptsStorage(t *testing.T, run RunWithBackend) {
+	withStore := func(t *testing.T, f func(store storage.ExecutionReceipts)) {
+		run(t, func(backend Backend) {
+			f(backend.All().Receipts)
 		})
 	}
 
 	t.Run("get empty", func(t *testing.T) {
-		withStore(t, func(store *bstorage.ExecutionReceipts) {
+		withStore(t, func(store storage.ExecutionReceipts) {
 			block := unittest.BlockFixture()
 			receipts, err := store.ByBlockID(block.ID())
 			require.NoError(t, err)
@@ -32,7 +27,7 @@ func TestExecutionReceiptsStorage(t *testing.T) {
 	})
 
 	t.Run("store one get one", func(t *testing.T) {
-		withStore(t, func(store *bstorage.ExecutionReceipts) {
+		withStore(t, func(store storage.ExecutionReceipts) {
 			block := unittest.BlockFixture()
 			receipt1 := unittest.ReceiptForBlockFixture(&block)
 
@@ -52,7 +47,7 @@ func TestExecutionReceiptsStorage(t *testing.T) {
 	})
 
 	t.Run("store two for the same block", func(t *testing.T) {
-		withStore(t, func(store *bstorage.ExecutionReceipts) {
+		withStore(t, func(store storage.ExecutionReceipts) {
 			block := unittest.BlockFixture()
 
 			executor1 := unittest.IdentifierFixture()
@@ -75,7 +70,7 @@ func TestExecutionReceiptsStorage(t *testing.T) {
 	})
 
 	t.Run("store two for different blocks", func(t *testing.T) {
-		withStore(t, func(store *bstorage.ExecutionReceipts) {
+		withStore(t, func(store storage.ExecutionReceipts) {
 			block1 := unittest.BlockFixture()
 			block2 := unittest.BlockFixture()
 
@@ -103,7 +98,7 @@ func TestExecutionReceiptsStorage(t *testing.T) {
 	})
 
 	t.Run("indexing duplicated receipts should be ok", func(t *testing.T) {
-		withStore(t, func(store *bstorage.ExecutionReceipts) {
+		withStore(t, func(store storage.ExecutionReceipts) {
 			block1 := unittest.BlockFixture()
 
 			executor1 := unittest.IdentifierFixture()
@@ -123,7 +118,7 @@ func TestExecutionReceiptsStorage(t *testing.T) {
 	})
 
 	t.Run("indexing receipt from the same executor for same block should succeed", func(t *testing.T) {
-		withStore(t, func(store *bstorage.ExecutionReceipts) {
+		withStore(t, func(store storage.ExecutionReceipts) {
 			block1 := unittest.BlockFixture()
 
 			executor1 := unittest.IdentifierFixture()
@@ -144,3 +139,58 @@ func TestExecutionReceiptsStorage(t *testing.T) {
 		})
 	})
 }
+
+func testMyExecutionReceiptsStorage(t *testing.T, run RunWithBackend) {
+	withStore := func(t *testing.T, f func(store storage.MyExecutionReceipts)) {
+		run(t, func(backend Backend) {
+			f(backend.NewMyExecutionReceipts())
+		})
+	}
+
+	t.Run("store one get one", func(t *testing.T) {
+		withStore(t, func(store storage.MyExecutionReceipts) {
+			block := unittest.BlockFixture()
+			receipt1 := unittest.ReceiptForBlockFixture(&block)
+
+			err := store.StoreMyReceipt(receipt1)
+			require.NoError(t, err)
+
+			actual, err := store.MyReceipt(block.ID())
+			require.NoError(t, err)
+
+			require.Equal(t, receipt1, actual)
+		})
+	})
+
+	t.Run("store same for the same block", func(t *testing.T) {
+		withStore(t, func(store storage.MyExecutionReceipts) {
+			block := unittest.BlockFixture()
+
+			receipt1 := unittest.ReceiptForBlockFixture(&block)
+
+			err := store.StoreMyReceipt(receipt1)
+			require.NoError(t, err)
+
+			err = store.StoreMyReceipt(receipt1)
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("store different receipt for same block should fail", func(t *testing.T) {
+		withStore(t, func(store storage.MyExecutionReceipts) {
+			block := unittest.BlockFixture()
+
+			executor1 := unittest.IdentifierFixture()
+			executor2 := unittest.IdentifierFixture()
+
+			receipt1 := unittest.ReceiptForBlockExecutorFixture(&block, executor1)
+			receipt2 := unittest.ReceiptForBlockExecutorFixture(&block, executor2)
+
+			err := store.StoreMyReceipt(receipt1)
+			require.NoError(t, err)
+
+			err = store.StoreMyReceipt(receipt2)
+			require.Error(t, err)
+		})
+	})
+}
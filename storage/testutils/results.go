@@ -1,22 +1,18 @@
-package badger_test
+package testutils
 
 import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
-	bstorage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
-func TestResultStoreAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := bstorage.NewExecutionResults(metrics, db)
+func testResultStoreAndRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Results
 
 		result := unittest.ExecutionResultFixture()
 		blockID := unittest.IdentifierFixture()
@@ -33,10 +29,9 @@ func TestResultStoreAndRetrieve(t *testing.T) {
 	})
 }
 
-func TestResultStoreTwice(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := bstorage.NewExecutionResults(metrics, db)
+func testResultStoreTwice(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Results
 
 		result := unittest.ExecutionResultFixture()
 		blockID := unittest.IdentifierFixture()
@@ -54,15 +49,14 @@ func TestResultStoreTwice(t *testing.T) {
 	})
 }
 
-func TestResultBatchStoreTwice(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := bstorage.NewExecutionResults(metrics, db)
+func testResultBatchStoreTwice(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Results
 
 		result := unittest.ExecutionResultFixture()
 		blockID := unittest.IdentifierFixture()
 
-		batch := bstorage.NewBatch(db)
+		batch := backend.NewBatch()
 		err := store.BatchStore(result, batch)
 		require.NoError(t, err)
 
@@ -71,7 +65,7 @@ func TestResultBatchStoreTwice(t *testing.T) {
 
 		require.NoError(t, batch.Flush())
 
-		batch = bstorage.NewBatch(db)
+		batch = backend.NewBatch()
 		err = store.BatchStore(result, batch)
 		require.NoError(t, err)
 
@@ -82,10 +76,9 @@ func TestResultBatchStoreTwice(t *testing.T) {
 	})
 }
 
-func TestResultStoreTwoDifferentResultsShouldFail(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := bstorage.NewExecutionResults(metrics, db)
+func testResultStoreTwoDifferentResultsShouldFail(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Results
 
 		result1 := unittest.ExecutionResultFixture()
 		result2 := unittest.ExecutionResultFixture()
@@ -108,10 +101,9 @@ func TestResultStoreTwoDifferentResultsShouldFail(t *testing.T) {
 	})
 }
 
-func TestResultStoreForceIndexOverridesMapping(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := bstorage.NewExecutionResults(metrics, db)
+func testResultStoreForceIndexOverridesMapping(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Results
 
 		result1 := unittest.ExecutionResultFixture()
 		result2 := unittest.ExecutionResultFixture()
@@ -1,24 +1,18 @@
-package badger_test
+package testutils
 
 import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
-	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/utils/unittest"
-
-	badgerstorage "github.com/onflow/flow-go/storage/badger"
 )
 
-func TestRetrieveWithoutStore(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewSeals(metrics, db)
+func testSealRetrieveWithoutStore(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Seals
 
 		_, err := store.ByID(unittest.IdentifierFixture())
 		require.True(t, errors.Is(err, storage.ErrNotFound))
@@ -28,11 +22,10 @@ func TestRetrieveWithoutStore(t *testing.T) {
 	})
 }
 
-// TestSealStoreRetrieve verifies that a seal can be stored and retrieved by its ID
-func TestSealStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewSeals(metrics, db)
+// testSealStoreRetrieve verifies that a seal can be stored and retrieved by its ID
+func testSealStoreRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Seals
 
 		expected := unittest.Seal.Fixture()
 		// store seal
@@ -46,15 +39,14 @@ func TestSealStoreRetrieve(t *testing.T) {
 	})
 }
 
-// TestSealIndexAndRetrieve verifies that:
+// testSealIndexAndRetrieve verifies that:
 //   - for a block, we can store (aka index) the latest sealed block along this fork.
 //
 // Note: indexing the seal for a block is currently implemented only through a direct
-// Badger operation. The Seals mempool only supports retrieving the latest sealed block.
-func TestSealIndexAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewSeals(metrics, db)
+// database operation. The Seals mempool only supports retrieving the latest sealed block.
+func testSealIndexAndRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Seals
 
 		expectedSeal := unittest.Seal.Fixture()
 		blockID := unittest.IdentifierFixture()
@@ -64,7 +56,7 @@ func TestSealIndexAndRetrieve(t *testing.T) {
 		require.NoError(t, err)
 
 		// index the seal ID for the heighest sealed block in this fork
-		err = operation.RetryOnConflict(db.Update, operation.IndexLatestSealAtBlock(blockID, expectedSeal.ID()))
+		err = backend.IndexLatestSealAtBlock(blockID, expectedSeal.ID())
 		require.NoError(t, err)
 
 		// retrieve latest seal
@@ -74,12 +66,11 @@ func TestSealIndexAndRetrieve(t *testing.T) {
 	})
 }
 
-// TestSealedBlockIndexAndRetrieve checks after indexing a seal by a sealed block ID, it can be
+// testSealedBlockIndexAndRetrieve checks after indexing a seal by a sealed block ID, it can be
 // retrieved by the sealed block ID
-func TestSealedBlockIndexAndRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewSeals(metrics, db)
+func testSealedBlockIndexAndRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Seals
 
 		expectedSeal := unittest.Seal.Fixture()
 		blockID := unittest.IdentifierFixture()
@@ -90,7 +81,7 @@ func TestSealedBlockIndexAndRetrieve(t *testing.T) {
 		require.NoError(t, err)
 
 		// index the seal ID for the highest sealed block in this fork
-		err = operation.RetryOnConflict(db.Update, operation.IndexFinalizedSealByBlockID(expectedSeal.BlockID, expectedSeal.ID()))
+		err = backend.IndexFinalizedSealByBlockID(expectedSeal.BlockID, expectedSeal.ID())
 		require.NoError(t, err)
 
 		// retrieve latest seal
@@ -0,0 +1,81 @@
+package testutils
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// Backend provides the storage test suite with the storage abstractions of a database backend,
+// such as badger or pebble, backed by a database which is specific to the running test.
+type Backend interface {
+	// All returns new instances of the storage abstractions. As the instances don't share any
+	// cached state with instances returned by earlier calls, they can be used to check what was
+	// persisted in the database, as after a restart.
+	All() *storage.All
+
+	// NewBatch returns a new write batch of the database.
+	NewBatch() storage.BatchStorage
+
+	// NewMyExecutionReceipts returns a new instance of the storage of the node's own receipts.
+	NewMyExecutionReceipts() storage.MyExecutionReceipts
+
+	// IndexBlockHeight indexes the block with the given ID by the given height.
+	IndexBlockHeight(height uint64, blockID flow.Identifier) error
+
+	// IndexLatestSealAtBlock indexes the seal with the given ID as the latest seal in the fork
+	// of the block with the given ID.
+	IndexLatestSealAtBlock(blockID flow.Identifier, sealID flow.Identifier) error
+
+	// IndexFinalizedSealByBlockID indexes the seal with the given ID as the finalized seal of
+	// the block with the given ID.
+	IndexFinalizedSealByBlockID(blockID flow.Identifier, sealID flow.Identifier) error
+}
+
+// RunWithBackend runs the given function with a backend over a new, empty database.
+type RunWithBackend func(t *testing.T, f func(backend Backend))
+
+// RunStorageSuite runs the tests of the storage abstractions which are shared by all database
+// backends against the backend provided by the given function.
+func RunStorageSuite(t *testing.T, run RunWithBackend) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, run RunWithBackend)
+	}{
+		{"Blocks", testBlocks},
+		{"BlockStoreAndRetrieve", testBlockStoreAndRetrieve},
+		{"Collections", testCollections},
+		{"CollectionsIndexDuplicateTx", testCollectionsIndexDuplicateTx},
+		{"CommitsStoreAndRetrieve", testCommitsStoreAndRetrieve},
+		{"EventStoreRetrieve", testEventStoreRetrieve},
+		{"EventRetrieveWithoutStore", testEventRetrieveWithoutStore},
+		{"GuaranteeStoreRetrieve", testGuaranteeStoreRetrieve},
+		{"HeaderStoreRetrieve", testHeaderStoreRetrieve},
+		{"HeaderRetrieveWithoutStore", testHeaderRetrieveWithoutStore},
+		{"IndexStoreRetrieve", testIndexStoreRetrieve},
+		{"PayloadStoreRetrieve", testPayloadStoreRetrieve},
+		{"PayloadRetrieveWithoutStore", testPayloadRetrieveWithoutStore},
+		{"ExecutionReceiptsStorage", testExecutionReceiptsStorage},
+		{"MyExecutionReceiptsStorage", testMyExecutionReceiptsStorage},
+		{"ResultStoreAndRetrieve", testResultStoreAndRetrieve},
+		{"ResultStoreTwice", testResultStoreTwice},
+		{"ResultBatchStoreTwice", testResultBatchStoreTwice},
+		{"ResultStoreTwoDifferentResultsShouldFail", testResultStoreTwoDifferentResultsShouldFail},
+		{"ResultStoreForceIndexOverridesMapping", testResultStoreForceIndexOverridesMapping},
+		{"SealRetrieveWithoutStore", testSealRetrieveWithoutStore},
+		{"SealStoreRetrieve", testSealStoreRetrieve},
+		{"SealIndexAndRetrieve", testSealIndexAndRetrieve},
+		{"SealedBlockIndexAndRetrieve", testSealedBlockIndexAndRetrieve},
+		{"BatchStoringTransactionResults", testBatchStoringTransactionResults},
+		{"ReadingNotStoreTransaction", testReadingNotStoreTransaction},
+		{"TransactionStoreRetrieve", testTransactionStoreRetrieve},
+		{"TransactionRetrieveWithoutStore", testTransactionRetrieveWithoutStore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, run)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package testutils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/rand"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func testBatchStoringTransactionResults(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().TransactionResults
+
+		blockID := unittest.IdentifierFixture()
+		txResults := make([]flow.TransactionResult, 0)
+		for i := 0; i < 10; i++ {
+			txID := unittest.IdentifierFixture()
+			expected := flow.TransactionResult{
+				TransactionID: txID,
+				ErrorMessage:  fmt.Sprintf("a runtime error %d", i),
+			}
+			txResults = append(txResults, expected)
+		}
+		writeBatch := backend.NewBatch()
+		err := store.BatchStore(blockID, txResults, writeBatch)
+		require.NoError(t, err)
+
+		err = writeBatch.Flush()
+		require.NoError(t, err)
+
+		for _, txResult := range txResults {
+			actual, err := store.ByBlockIDTransactionID(blockID, txResult.TransactionID)
+			require.NoError(t, err)
+			assert.Equal(t, txResult, *actual)
+		}
+
+		// test loading from database
+		newStore := backend.All().TransactionResults
+		for _, txResult := range txResults {
+			actual, err := newStore.ByBlockIDTransactionID(blockID, txResult.TransactionID)
+			require.NoError(t, err)
+			assert.Equal(t, txResult, *actual)
+		}
+
+		// check retrieving by index from both cache and db
+		for i := len(txResults) - 1; i >= 0; i-- {
+			actual, err := store.ByBlockIDTransactionIndex(blockID, uint32(i))
+			require.NoError(t, err)
+			assert.Equal(t, txResults[i], *actual)
+
+			actual, err = newStore.ByBlockIDTransactionIndex(blockID, uint32(i))
+			require.NoError(t, err)
+			assert.Equal(t, txResults[i], *actual)
+		}
+	})
+}
+
+func testReadingNotStoreTransaction(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().TransactionResults
+
+		blockID := unittest.IdentifierFixture()
+		txID := unittest.IdentifierFixture()
+		txIndex := rand.Uint32()
+
+		_, err := store.ByBlockIDTransactionID(blockID, txID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		_, err = store.ByBlockIDTransactionIndex(blockID, txIndex)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -1,24 +1,19 @@
-package badger_test
+package testutils
 
 import (
 	"errors"
 	"testing"
 
-	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
-
-	badgerstorage "github.com/onflow/flow-go/storage/badger"
 )
 
-func TestTransactionStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewTransactions(metrics, db)
+func testTransactionStoreRetrieve(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Transactions
 
 		// store a transaction in db
 		expected := unittest.TransactionFixture()
@@ -36,10 +31,9 @@ func TestTransactionStoreRetrieve(t *testing.T) {
 	})
 }
 
-func TestTransactionRetrieveWithoutStore(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		metrics := metrics.NewNoopCollector()
-		store := badgerstorage.NewTransactions(metrics, db)
+func testTransactionRetrieveWithoutStore(t *testing.T, run RunWithBackend) {
+	run(t, func(backend Backend) {
+		store := backend.All().Transactions
 
 		// attempt to get a invalid transaction
 		_, err := store.ByID(unittest.IdentifierFixture())
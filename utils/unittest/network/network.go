@@ -7,6 +7,7 @@ import (
 
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/mocknetwork"
 )
@@ -59,6 +60,24 @@ func (n *Network) Send(channel channels.Channel, originID flow.Identifier, event
 	return nil
 }
 
+// ReplayCapture sends the inbound messages of the capture file read by the given reader which match the filter to the
+// engines registered on this mock network, in their captured order, as if they were received from their origins.
+// Outbound messages, i.e., messages sent by the capturing node, are never replayed, and messages on channels without
+// registered engine are skipped. Replay stops at the first error returned by an engine.
+func (n *Network) ReplayCapture(r *capture.Reader, filter capture.Filter) error {
+	if filter.Direction == capture.DirectionOutbound {
+		return fmt.Errorf("outbound messages cannot be replayed as received messages")
+	}
+	filter.Direction = capture.DirectionInbound
+	return capture.Replay(r, filter, func(channel channels.Channel, originID flow.Identifier, event interface{}) error {
+		eng, ok := n.engines[channel]
+		if !ok {
+			return nil
+		}
+		return eng.Process(channel, originID, event)
+	})
+}
+
 // OnPublish specifies the callback that should be executed when `Publish` is called on any conduits
 // created by this mock network.
 func (n *Network) OnPublish(publishFunc PublishFunc) *Network {
@@ -149,6 +149,29 @@ func EpochCommitFixtureByChainID(chain flow.ChainID) (flow.Event, *flow.EpochCom
 	return event, expected
 }
 
+// EpochRecoverFixtureByChainID returns an EpochRecover service event as a Cadence event
+// representation and as a protocol model representation.
+func EpochRecoverFixtureByChainID(chain flow.ChainID) (flow.Event, *flow.EpochRecover) {
+
+	events, err := systemcontracts.ServiceEventsForChain(chain)
+	if err != nil {
+		panic(err)
+	}
+
+	event := EventFixture(events.EpochRecover.EventType(), 1, 1, IdentifierFixture(), 0)
+	event.Payload = EpochRecoverFixtureCCF
+
+	// the recovery epoch is specified by the same data as the setup and commit fixtures
+	_, expectedSetup := EpochSetupFixtureByChainID(chain)
+	_, expectedCommit := EpochCommitFixtureByChainID(chain)
+	expected := &flow.EpochRecover{
+		EpochSetup:  *expectedSetup,
+		EpochCommit: *expectedCommit,
+	}
+
+	return event, expected
+}
+
 // VersionBeaconFixtureByChainID returns a VersionTable service event as a Cadence event
 // representation and as a protocol model representation.
 func VersionBeaconFixtureByChainID(chain flow.ChainID) (flow.Event, *flow.VersionBeacon) {
@@ -655,6 +678,16 @@ func createEpochCommittedEvent() cadence.Event {
 	}).WithType(newFlowEpochEpochCommittedEventType())
 }
 
+func createEpochRecoverEvent() cadence.Event {
+	setup := createEpochSetupEvent()
+	commit := createEpochCommittedEvent()
+
+	// the EpochRecover event contains all fields of both events, the counter is included only once
+	fields := append(setup.Fields, commit.Fields[1:]...)
+
+	return cadence.NewEvent(fields).WithType(newFlowEpochEpochRecoverEventType())
+}
+
 func createVersionBeaconEvent() cadence.Event {
 	versionBoundaryType := NewNodeVersionBeaconVersionBoundaryStructType()
 
@@ -912,6 +945,23 @@ func newFlowEpochEpochCommittedEventType() *cadence.EventType {
 	}
 }
 
+func newFlowEpochEpochRecoverEventType() *cadence.EventType {
+
+	// A.01cf0e2f2f715450.FlowEpoch.EpochRecover
+
+	address, _ := common.HexToAddress("01cf0e2f2f715450")
+	location := common.NewAddressLocation(nil, address, "FlowEpoch")
+
+	setupFields := newFlowEpochEpochSetupEventType().Fields
+	commitFields := newFlowEpochEpochCommittedEventType().Fields
+
+	return &cadence.EventType{
+		Location:            location,
+		QualifiedIdentifier: "FlowEpoch.EpochRecover",
+		Fields:              append(setupFields, commitFields[1:]...),
+	}
+}
+
 func newFlowClusterQCClusterQCStructType() *cadence.StructType {
 
 	// A.01cf0e2f2f715450.FlowClusterQC.ClusterQC"
@@ -1052,6 +1102,18 @@ var EpochCommitFixtureCCF = func() []byte {
 	return b
 }()
 
+var EpochRecoverFixtureCCF = func() []byte {
+	b, err := ccf.Encode(createEpochRecoverEvent())
+	if err != nil {
+		panic(err)
+	}
+	_, err = ccf.Decode(nil, b)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}()
+
 var VersionBeaconFixtureCCF = func() []byte {
 	b, err := ccf.Encode(createVersionBeaconEvent())
 	if err != nil {